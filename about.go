@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+)
+
+// Method to describe a dictionary for the about screen and the /about endpoint.
+func aboutDictionary(d *Dictionary) api.About {
+	metadata := d.Metadata()
+	return api.About{
+		Name:        metadata.Name,
+		Source:      metadata.Source,
+		License:     metadata.License,
+		Attribution: metadata.Attribution,
+		WordCount:   d.WordCount(),
+	}
+}
+
+// Driver method for the "about" subcommand, which shows the license and the
+// attribution of the dictionary.
+func StartAbout() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	about := aboutDictionary(currentDictionary())
+	fmt.Println("Dictionary:  ", orUnknown(about.Name))
+	fmt.Println("Words:       ", about.WordCount)
+	fmt.Println("Source:      ", orUnknown(about.Source))
+	fmt.Println("License:     ", orUnknown(about.License))
+	fmt.Println("Attribution: ", orUnknown(about.Attribution))
+	return nil
+}
+
+// Method to show "unknown" for the metadata which is not given.
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	screenReader = flag.Bool("screen_reader", false,
+		"Make the terminal game usable with a screen reader: the word is spelled "+
+			"out (\"blank, blank, E, blank\"), the guesses and the tries left are "+
+			"told in words, and there are no colors, keyboard drawing or "+
+			"countdown rewritten every second.")
+)
+
+// Time left in a turn at which the screen reader mode warns the player, on top
+// of the time left told at the start of the turn.
+const screenReaderWarning = 10 * time.Second
+
+// Method to set up the screen reader mode, which turns off the colors and the
+// keyboard. Must be called after they are set up from their flags.
+func setupScreenReader() {
+	if *screenReader {
+		colors = nil
+		keyboard = nil
+	}
+}
+
+// Method to spell out a word shown to the player, for a screen reader: the
+// characters yet to be guessed are blanks and the letters are in upper case,
+// so that they are read as letters rather than as a word.
+func spokenWord(pattern []rune) string {
+	chars := make([]string, len(pattern))
+	for i, char := range pattern {
+		switch {
+		case char == emptyChar:
+			chars[i] = tr("spoken_blank")
+		case char == ' ':
+			chars[i] = tr("spoken_space")
+		default:
+			chars[i] = string(unicode.ToUpper(char))
+		}
+	}
+	return tr("spoken_word", len(pattern), strings.Join(chars, ", "))
+}
+
+// Method to tell the outcome of the guesses in words, for a screen reader.
+// Unlike formatHistory, no symbol nor color tells if a guess was right.
+func spokenHistory(history []GuessRecord) string {
+	entries := make([]string, len(history))
+	for i, record := range history {
+		switch {
+		case record.Timeout:
+			entries[i] = tr("spoken_timeout")
+		case record.Correct:
+			entries[i] = tr("spoken_right", unicode.ToUpper(record.Char), record.Positions)
+		default:
+			entries[i] = tr("spoken_wrong", unicode.ToUpper(record.Char))
+		}
+	}
+	return tr("spoken_guesses", strings.Join(entries, "; "))
+}
+
+// Method to tell the tries left in words, for a screen reader.
+func spokenRetries(retries int) string {
+	if retries == 1 {
+		return tr("spoken_last_try")
+	}
+	return tr("spoken_tries", retries)
+}
+
+// Method to tell the time left in a turn, given the time left at the last
+// tick (zero at the start of the turn). The countdown is rewritten on the same
+// line every second, which a screen reader would read again and again, so in
+// screen reader mode the time left is only told at the start of the turn and
+// once when it is about to end.
+func printTimeLeft(remaining, previous time.Duration) {
+	if !*screenReader {
+		fmt.Printf("\rTime left: %v ", remaining)
+		return
+	}
+	if previous == 0 || (previous > screenReaderWarning && remaining <= screenReaderWarning) {
+		fmt.Printf("Time left: %v\n", remaining)
+	}
+}
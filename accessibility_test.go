@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type AccessibilityTestSuite struct {
+	suite.Suite
+}
+
+func (s *AccessibilityTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *AccessibilityTestSuite) TestSpokenWord() {
+	assert.Equal(s.T(), "The word has 4 characters: blank, blank, E, blank.",
+		spokenWord([]rune{emptyChar, emptyChar, 'e', emptyChar}))
+	assert.Equal(s.T(), "The word has 3 characters: A, space, blank.",
+		spokenWord([]rune{'a', ' ', emptyChar}))
+}
+
+func (s *AccessibilityTestSuite) TestSpokenHistory() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	for _, char := range "sz" {
+		_, err = game.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+	}
+	assert.Equal(s.T(), "Your guesses: S, right, 1 in the word; Z, wrong.",
+		spokenHistory(game.History()))
+	assert.Equal(s.T(), "Your guesses: time ran out.",
+		spokenHistory([]GuessRecord{{Timeout: true}}))
+}
+
+func (s *AccessibilityTestSuite) TestSpokenRetries() {
+	assert.Equal(s.T(), "You have 3 tries left.", spokenRetries(3))
+	assert.Equal(s.T(), "You have one try left.", spokenRetries(1))
+	assert.Equal(s.T(), "You have 0 tries left.", spokenRetries(0))
+}
+
+func (s *AccessibilityTestSuite) TestSetupScreenReader() {
+	oldColors, oldKeyboard := colors, keyboard
+	defer func() {
+		colors, keyboard = oldColors, oldKeyboard
+		*screenReader = false
+	}()
+	colors, keyboard = &DefaultColorTheme, keyboardLayouts["qwerty"]
+	setupScreenReader()
+	assert.NotNil(s.T(), colors)
+	*screenReader = true
+	setupScreenReader()
+	assert.Nil(s.T(), colors)
+	assert.Nil(s.T(), keyboard)
+}
+
+func TestAccessibilityTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessibilityTestSuite))
+}
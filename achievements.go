@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// Max number of retries of a hard game, see isHardEvilGame.
+	hardRetries = 4
+	// Min length of the word of the long word achievement.
+	longWordLength = 12
+	// Wins in a row of the streak achievement.
+	achievementStreak = 10
+)
+
+// Achievement unlocked by a player.
+type Achievement struct {
+	ID       string    `json:"id"`
+	Unlocked time.Time `json:"unlocked"`
+}
+
+// Rule unlocking an achievement.
+type AchievementRule struct {
+	// Id of the achievement, saved in the profiles.
+	ID string
+	// Description shown to the player.
+	Description string
+	// Method to check if a finished game earns the achievement.
+	// Params:
+	// stats: Stats of the player, including the game.
+	// game: Finished game.
+	Earned func(stats Stats, game *Game) bool
+}
+
+// Engine evaluating the achievements after every game, with a registry of
+// rules. The achievements are listed in the order their rules were registered.
+type Achievements struct {
+	mu    sync.Mutex
+	rules []AchievementRule
+}
+
+// Method to create an engine with the builtin rules.
+func NewAchievements() *Achievements {
+	a := &Achievements{}
+	for _, rule := range builtinAchievementRules {
+		a.Register(rule)
+	}
+	return a
+}
+
+// Engine used by the terminal game.
+var defaultAchievements = NewAchievements()
+
+// Builtin rules, see NewAchievements.
+var builtinAchievementRules = []AchievementRule{
+	{"first_win", "Win a game", func(stats Stats, game *Game) bool {
+		return game.State == Won
+	}},
+	{"flawless", "Win a game without a wrong guess", func(stats Stats, game *Game) bool {
+		return game.State == Won && wrongGuesses(game) == 0
+	}},
+	{"long_word", fmt.Sprintf("Guess a word of %d letters or more", longWordLength),
+		func(stats Stats, game *Game) bool {
+			return game.State == Won && game.ExpectedLength >= longWordLength
+		}},
+	{"streak", fmt.Sprintf("Win %d games in a row", achievementStreak),
+		func(stats Stats, game *Game) bool {
+			return stats.CurrentWinStreak >= achievementStreak
+		}},
+	{"evil_hard", fmt.Sprintf("Beat the vindictive or entropy opponent with %d retries "+
+		"or less", hardRetries), func(stats Stats, game *Game) bool {
+		return game.State == Won && isHardEvilGame(game)
+	}},
+	{"veteran", "Play 100 games", func(stats Stats, game *Game) bool {
+		return stats.GamesPlayed >= 100
+	}},
+}
+
+// Method to check if a game was played against the evil engine in hard mode:
+// the vindictive or the entropy opponent, not capped (see Capped), with at
+// most hardRetries retries.
+func isHardEvilGame(game *Game) bool {
+	switch game.strategy.(type) {
+	case nil, Vindictive, StrategyEntropy:
+		return game.AllowedRetries <= hardRetries
+	}
+	return false
+}
+
+// Method to add a rule.
+// Returns an error if a rule with the same id was already registered.
+func (a *Achievements) Register(rule AchievementRule) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, r := range a.rules {
+		if r.ID == rule.ID {
+			return fmt.Errorf("achievement %q is already registered", rule.ID)
+		}
+	}
+	a.rules = append(a.rules, rule)
+	return nil
+}
+
+// Method to get the registered rules, in the order they were registered.
+func (a *Achievements) Rules() []AchievementRule {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]AchievementRule(nil), a.rules...)
+}
+
+// Method to get the description of an achievement, its id if it has no rule
+// (e.g. a rule removed since it was unlocked).
+func (a *Achievements) Description(id string) string {
+	for _, rule := range a.Rules() {
+		if rule.ID == id {
+			return rule.Description
+		}
+	}
+	return id
+}
+
+// Method to evaluate the rules after a finished game.
+// Params:
+// unlocked: Achievements unlocked before the game, which are not unlocked
+// again.
+// stats: Stats of the player, including the game.
+// game: Finished game.
+// now: Time the game ended.
+//
+// Returns the achievements unlocked by the game.
+func (a *Achievements) Evaluate(unlocked []Achievement, stats Stats, game *Game,
+	now time.Time) []Achievement {
+	seen := make(map[string]bool, len(unlocked))
+	for _, achievement := range unlocked {
+		seen[achievement.ID] = true
+	}
+	var earned []Achievement
+	for _, rule := range a.Rules() {
+		if !seen[rule.ID] && rule.Earned(stats, game) {
+			earned = append(earned, Achievement{ID: rule.ID, Unlocked: now})
+		}
+	}
+	return earned
+}
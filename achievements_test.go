@@ -0,0 +1,87 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type AchievementsTestSuite struct {
+	suite.Suite
+}
+
+func (s *AchievementsTestSuite) SetupTest() {
+	InitGame([]string{"code", "chocolatebars"})
+}
+
+func (s *AchievementsTestSuite) TearDownTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to play a game with the given guesses, and get the ids of the
+// achievements it unlocks.
+func (s *AchievementsTestSuite) earned(stats Stats, length, retries int, guesses string,
+	opts ...GameOption) []string {
+	game, err := NewGame(length, retries, opts...)
+	assert.Nil(s.T(), err)
+	for _, char := range guesses {
+		game.CheckUserInput(char)
+	}
+	stats.Record(game.State)
+	var ids []string
+	for _, achievement := range NewAchievements().Evaluate(nil, stats, game, time.Now()) {
+		ids = append(ids, achievement.ID)
+	}
+	return ids
+}
+
+func (s *AchievementsTestSuite) TestBuiltinRules() {
+	assert.Equal(s.T(), []string{"first_win", "flawless"},
+		s.earned(Stats{}, 4, 6, "code"))
+	assert.Equal(s.T(), []string{"first_win", "flawless", "long_word"},
+		s.earned(Stats{}, 13, 6, "cholatebrs"))
+	assert.Equal(s.T(), []string{"first_win", "evil_hard"},
+		s.earned(Stats{}, 4, 4, "zcode"))
+	assert.Empty(s.T(), s.earned(Stats{}, 4, 2, "zy"))
+
+	streak := Stats{GamesPlayed: 99, GamesWon: 99, CurrentWinStreak: 9}
+	assert.Equal(s.T(), []string{"first_win", "streak", "veteran"},
+		s.earned(streak, 4, 6, "zcode"))
+}
+
+func (s *AchievementsTestSuite) TestHardEvilGame() {
+	assert.Contains(s.T(), s.earned(Stats{}, 4, 4, "code",
+		WithStrategy(StrategyEntropy{})), "evil_hard")
+	assert.NotContains(s.T(), s.earned(Stats{}, 4, 4, "code",
+		WithStrategy(Merciful{})), "evil_hard")
+	assert.NotContains(s.T(), s.earned(Stats{}, 4, 4, "code",
+		WithStrategy(Capped{Strategy: Vindictive{}, MaxDodges: 1})), "evil_hard")
+	assert.NotContains(s.T(), s.earned(Stats{}, 4, 5, "code"), "evil_hard")
+}
+
+func (s *AchievementsTestSuite) TestRegistry() {
+	achievements := NewAchievements()
+	rule := AchievementRule{ID: "z_first", Description: "Guess z first",
+		Earned: func(stats Stats, game *Game) bool {
+			return len(game.UsedChars) > 0 && game.UsedChars[0] == 'z'
+		}}
+	assert.Nil(s.T(), achievements.Register(rule))
+	assert.NotNil(s.T(), achievements.Register(rule))
+	rules := achievements.Rules()
+	assert.Equal(s.T(), "z_first", rules[len(rules)-1].ID)
+	assert.Equal(s.T(), "Guess z first", achievements.Description("z_first"))
+	assert.Equal(s.T(), "gone", achievements.Description("gone"))
+
+	game, err := NewGame(4, 2)
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('z')
+	earned := achievements.Evaluate([]Achievement{{ID: "first_win"}}, Stats{}, game,
+		time.Now())
+	assert.Len(s.T(), earned, 1)
+	assert.Equal(s.T(), "z_first", earned[0].ID)
+}
+
+func TestAchievementsTestSuite(t *testing.T) {
+	suite.Run(t, new(AchievementsTestSuite))
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	tlsCert = flag.String("tls_cert", "",
+		"Certificate file (PEM) to serve the game over HTTPS, with --tls_key. "+
+			"The game is served over HTTP if empty.")
+	tlsKey = flag.String("tls_key", "",
+		"Private key file (PEM) of the certificate of --tls_cert.")
+	adminClientCA = flag.String("admin_client_ca", "",
+		"CA certificates file (PEM) of the admins: over HTTPS, the clients "+
+			"presenting a certificate signed by one of them can use the admin API "+
+			"without the admin token (mTLS).")
+)
+
+// Storage of the sessions which can list the sessions it keeps, see
+// handleListGames.
+type sessionLister interface {
+	// Method to get the sessions kept, in no particular order.
+	list() []*session
+}
+
+// Method to get the sessions kept by the session manager.
+func (m *SessionManager) list() []*session {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sessions := make([]*session, 0, len(m.sessions))
+	for _, managed := range m.sessions {
+		sessions = append(sessions, managed.sess)
+	}
+	return sessions
+}
+
+// Method to get the sessions served by this server. The games served by the
+// other servers sharing the store are not listed.
+func (s *redisStore) list() []*session {
+	return s.local.list()
+}
+
+// Method to build the TLS configuration of the server from the flags.
+// Returns nil if the game is served over HTTP.
+func tlsConfigFromFlags() (*tls.Config, error) {
+	if *tlsCert == "" && *tlsKey == "" {
+		if *adminClientCA != "" {
+			return nil, errors.New("--admin_client_ca needs --tls_cert and --tls_key")
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(*tlsCert, *tlsKey)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if *adminClientCA != "" {
+		data, err := ioutil.ReadFile(*adminClientCA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in %s", *adminClientCA)
+		}
+		// The players do not have a certificate, only the admins.
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return config, nil
+}
+
+// Method to check if a request comes from an admin: it sends the admin token,
+// or a client certificate verified against --admin_client_ca.
+func (s *gameServer) isAdmin(r *http.Request) bool {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return s.adminToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
+
+// Method to wrap an admin handler so that it is only served to the admins,
+// see isAdmin.
+func (s *gameServer) admin(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAdmin(r) {
+			writeError(w, api.NewError(api.CodeForbidden,
+				"a valid admin token or client certificate is required"))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// Method to get the sessions kept by the store of the server, oldest first.
+func (s *gameServer) sessions() []*session {
+	lister, ok := s.store.(sessionLister)
+	if !ok {
+		return nil
+	}
+	sessions := lister.list()
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].created.Before(sessions[j].created)
+	})
+	return sessions
+}
+
+// Method to get the view of a session for the admins.
+func (sess *session) adminView() api.AdminGame {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return api.AdminGame{
+		Game:       sess.viewLocked(),
+		Created:    sess.created,
+		Candidates: sess.game.RemainingCandidates(),
+	}
+}
+
+// Handler of GET /admin/games, which lists the running games as an
+// api.AdminGameList, oldest first. Add ?all=1 to list the finished games too.
+func (s *gameServer) handleListGames(w http.ResponseWriter, r *http.Request) {
+	all := r.URL.Query().Get("all") == "1"
+	list := api.AdminGameList{Games: []api.AdminGame{}}
+	for _, sess := range s.sessions() {
+		view := sess.adminView()
+		if all || view.State == api.StateRunning {
+			list.Games = append(list.Games, view)
+		}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Handler of GET /admin/games/{id}/candidates, which shows the words the game
+// can still pick the word from as api.AdminCandidates.
+func (s *gameServer) handleGetCandidates(w http.ResponseWriter, r *http.Request) {
+	sess, apiErr := s.getSession(r.Context(), r.PathValue("id"))
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	words := append([]string{}, sess.game.Candidates()...)
+	sort.Strings(words)
+	writeJSON(w, http.StatusOK, api.AdminCandidates{ID: sess.id, Words: words})
+}
+
+// Handler of POST /admin/games/{id}/finish, which forfeits a running game and
+// returns its new state as an api.Game.
+func (s *gameServer) handleFinishGame(w http.ResponseWriter, r *http.Request) {
+	sess, apiErr := s.getSession(r.Context(), r.PathValue("id"))
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	view, apiErr := sess.forceFinish(r.Context())
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	defaultLogger.Infof("Game %s was finished by an admin", sess.id)
+	writeJSON(w, http.StatusOK, view)
+}
+
+// Method to forfeit a running game on behalf of an admin, and send the new
+// state to the watchers. The game is not recorded in the leaderboard nor in
+// the hall of shame, as the player did not lose it. Like a guess, the game is
+// saved even if the context is canceled once it is finished.
+func (sess *session) forceFinish(ctx context.Context) (api.Game, *api.Error) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	for attempt := 1; ; attempt++ {
+		if sess.game.State != Running {
+			return api.Game{}, api.NewError(api.CodeGameFinished, "the game is already finished").
+				WithDetail("id", sess.id)
+		}
+		sess.game.forfeit()
+		// Never recorded, see recordLocked.
+		sess.ended = false
+		sess.game.settleWord()
+		if sess.expiryTimer != nil {
+			sess.expiryTimer.Stop()
+		}
+		err := sess.saveLocked(context.WithoutCancel(ctx))
+		if err == nil {
+			break
+		}
+		// The game was changed by another server, and has been reloaded.
+		if err == errSessionConflict && attempt < maxSaveAttempts {
+			continue
+		}
+		defaultLogger.Errorf("Unable to save game %s, error %v", sess.id, err)
+		return api.Game{}, api.NewError(api.CodeInternal, "unable to save the game").
+			WithDetail("id", sess.id)
+	}
+	view := sess.viewLocked()
+	sess.broadcastLocked(api.Message{Type: api.MessageState, Game: &view})
+	return view, nil
+}
+
+// Method to compute the aggregate stats of the games kept by the server.
+func (s *gameServer) adminStats() api.AdminStats {
+	stats := api.AdminStats{
+		Games:           make(map[api.GameState]int),
+		RunningByLength: make(map[int]int),
+		DictionaryWords: currentDictionary().WordCount(),
+	}
+	players := make(map[string]bool)
+	for _, sess := range s.sessions() {
+		view := sess.adminView()
+		stats.Games[view.State]++
+		if view.State == api.StateRunning {
+			stats.RunningByLength[view.WordLength]++
+			stats.Candidates += view.Candidates
+		}
+		if view.Player != "" {
+			players[view.Player] = true
+		}
+	}
+	stats.Players = len(players)
+	return stats
+}
+
+// Handler of GET /admin/stats, which shows the aggregate stats of the games
+// kept by the server as an api.AdminStats.
+func (s *gameServer) handleAdminStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.adminStats())
+}
+
+// Method to serve the handler on the address, over HTTPS if the TLS
+// configuration is not nil. The clients sending their headers too slowly, or
+// too large ones, are cut off.
+func listenAndServe(addr string, handler http.Handler, config *tls.Config) error {
+	server := &http.Server{Addr: addr, Handler: handler, TLSConfig: config,
+		ReadHeaderTimeout: readHeaderTimeout, MaxHeaderBytes: maxRequestHeader}
+	if config == nil {
+		return server.ListenAndServe()
+	}
+	return server.ListenAndServeTLS("", "")
+}
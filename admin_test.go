@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+type AdminTestSuite struct {
+	suite.Suite
+	gameServer *gameServer
+	server     *httptest.Server
+}
+
+func (s *AdminTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+	s.gameServer = newGameServer()
+	s.gameServer.adminToken = "secret"
+	s.gameServer.leaderboard = newLeaderboardStore(
+		filepath.Join(s.T().TempDir(), "leaderboard.jsonl"))
+	s.server = httptest.NewServer(s.gameServer.Handler())
+}
+
+func (s *AdminTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// Method to send a request to the server, with the admin token if asked.
+func (s *AdminTestSuite) do(method, path, body string, admin bool, out interface{}) int {
+	req, err := http.NewRequest(method, s.server.URL+path, bytes.NewBufferString(body))
+	assert.Nil(s.T(), err)
+	if admin {
+		req.Header.Set("Authorization", "Bearer secret")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+// Method to create a game and return its id.
+func (s *AdminTestSuite) create(body string) string {
+	var game api.Game
+	assert.Equal(s.T(), http.StatusCreated, s.do("POST", "/games", body, false, &game))
+	return game.ID
+}
+
+func (s *AdminTestSuite) TestForbidden() {
+	for _, path := range []string{"/admin/games", "/admin/stats"} {
+		var errResp api.ErrorResponse
+		assert.Equal(s.T(), http.StatusForbidden, s.do("GET", path, "", false, &errResp))
+		assert.Equal(s.T(), api.CodeForbidden, errResp.Error.Code)
+	}
+	var errResp api.ErrorResponse
+	assert.Equal(s.T(), http.StatusForbidden,
+		s.do("POST", "/admin/games/x/finish", "", false, &errResp))
+}
+
+func (s *AdminTestSuite) TestClientCertificate() {
+	req := httptest.NewRequest("GET", "/admin/games", nil)
+	assert.False(s.T(), s.gameServer.isAdmin(req))
+	// A certificate which was not verified is not enough.
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}}
+	assert.False(s.T(), s.gameServer.isAdmin(req))
+	req.TLS.VerifiedChains = [][]*x509.Certificate{{{}}}
+	assert.True(s.T(), s.gameServer.isAdmin(req))
+
+	*adminClientCA = "admins.pem"
+	defer func() { *adminClientCA = "" }()
+	_, err := tlsConfigFromFlags()
+	assert.NotNil(s.T(), err)
+}
+
+func (s *AdminTestSuite) TestGames() {
+	first := s.create(`{"word_length": 4, "retries": 3, "player": "alice"}`)
+	second := s.create(`{"word_length": 4, "retries": 1}`)
+	var guess api.GuessResponse
+	s.do("POST", "/games/"+second+"/guesses", `{"char": "z"}`, false, &guess)
+	assert.Equal(s.T(), api.StateLost, guess.Game.State)
+
+	var list api.AdminGameList
+	assert.Equal(s.T(), http.StatusOK, s.do("GET", "/admin/games", "", true, &list))
+	assert.Len(s.T(), list.Games, 1)
+	assert.Equal(s.T(), first, list.Games[0].ID)
+	assert.Equal(s.T(), "alice", list.Games[0].Player)
+	assert.Equal(s.T(), 4, list.Games[0].Candidates)
+	s.do("GET", "/admin/games?all=1", "", true, &list)
+	assert.Len(s.T(), list.Games, 2)
+
+	var candidates api.AdminCandidates
+	assert.Equal(s.T(), http.StatusOK,
+		s.do("GET", "/admin/games/"+first+"/candidates", "", true, &candidates))
+	assert.Equal(s.T(), []string{"bets", "code", "fast", "last"}, candidates.Words)
+
+	var stats api.AdminStats
+	assert.Equal(s.T(), http.StatusOK, s.do("GET", "/admin/stats", "", true, &stats))
+	assert.Equal(s.T(), map[api.GameState]int{api.StateRunning: 1, api.StateLost: 1},
+		stats.Games)
+	assert.Equal(s.T(), map[int]int{4: 1}, stats.RunningByLength)
+	assert.Equal(s.T(), 4, stats.Candidates)
+	assert.Equal(s.T(), 1, stats.Players)
+	assert.Equal(s.T(), 4, stats.DictionaryWords)
+}
+
+func (s *AdminTestSuite) TestFinish() {
+	id := s.create(`{"word_length": 4, "retries": 3, "player": "alice"}`)
+	var game api.Game
+	assert.Equal(s.T(), http.StatusOK, s.do("POST", "/admin/games/"+id+"/finish", "", true, &game))
+	assert.Equal(s.T(), api.StateForfeited, game.State)
+
+	var candidates api.AdminCandidates
+	s.do("GET", "/admin/games/"+id+"/candidates", "", true, &candidates)
+	assert.Len(s.T(), candidates.Words, 1)
+	// The player did not lose the game.
+	entries, err := s.gameServer.leaderboard.entries()
+	assert.Nil(s.T(), err)
+	assert.Empty(s.T(), entries)
+
+	var errResp api.ErrorResponse
+	assert.Equal(s.T(), http.StatusConflict,
+		s.do("POST", "/admin/games/"+id+"/finish", "", true, &errResp))
+	assert.Equal(s.T(), api.CodeGameFinished, errResp.Error.Code)
+	assert.Equal(s.T(), http.StatusNotFound,
+		s.do("POST", "/admin/games/nope/finish", "", true, &errResp))
+}
+
+func TestAdminTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminTestSuite))
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+	alphabetLetters = flag.String("alphabet", "",
+		"Letters allowed in the dictionary words and in the guesses, e.g. "+
+			"\"abcdefghijklmnopqrstuvwxyzäöüß\". Any unicode letter is allowed if empty.")
+	phraseMode = flag.Bool("phrases", false,
+		"Accept dictionary entries of multiple words separated by single spaces, "+
+			"e.g. \"wheel of fortune\". The spaces are revealed from the start.")
+	punctuation = flag.String("punctuation", "",
+		"Punctuation allowed in the dictionary words besides the letters, e.g. "+
+			"\"-'\" for \"mother-in-law\" and \"don't\". Like the spaces of the "+
+			"phrases, it is revealed from the start and never guessed.")
+	caseFolding = flag.String("case_folding", string(FoldLower),
+		"How the case of the dictionary words and of the guesses is folded, so "+
+			"that 'A' and 'a' are the same guess: \"lower\" (the default), "+
+			"\"turkish\" (lower case with the Turkish dotted and dotless i) or "+
+			"\"none\" for a case sensitive dictionary.")
+)
+
+const (
+	// Character separating the words of a phrase. It is never guessed.
+	phraseSeparator = ' '
+)
+
+// How the case of the letters is folded before they are compared.
+type CaseFolding string
+
+const (
+	// Lower case, which is the default.
+	FoldLower CaseFolding = "lower"
+	// Lower case with the Turkish rules, e.g. 'I' is folded to 'ı'.
+	FoldTurkish CaseFolding = "turkish"
+	// The case is kept, for case sensitive dictionaries.
+	FoldNone CaseFolding = "none"
+)
+
+// Method to parse the name of a case folding.
+func ParseCaseFolding(name string) (CaseFolding, error) {
+	switch folding := CaseFolding(name); folding {
+	case FoldLower, FoldTurkish, FoldNone:
+		return folding, nil
+	}
+	return "", fmt.Errorf("case folding must be %q, %q or %q, got %q",
+		FoldLower, FoldTurkish, FoldNone, name)
+}
+
+// Set of letters which can be used in a dictionary. An empty alphabet accepts
+// every unicode letter, which is the default.
+type Alphabet struct {
+	letters map[rune]bool
+	// True if a dictionary entry can be a phrase of multiple words separated
+	// by single spaces.
+	Phrases bool
+	// Punctuation characters allowed in the words besides the letters, see
+	// --punctuation.
+	Punctuation string
+	// Case folding of the words and of the guesses, FoldLower if empty.
+	Folding CaseFolding
+}
+
+// Method to create an alphabet from the string of all its letters.
+// Both the upper and lower case forms of each letter are accepted since our
+// hangman is not case sensitive.
+func NewAlphabet(letters string) Alphabet {
+	if letters == "" {
+		return Alphabet{Folding: FoldLower}
+	}
+	a := Alphabet{letters: make(map[rune]bool), Folding: FoldLower}
+	for _, char := range letters {
+		// The engine marks the hidden letters with emptyChar, it can never be
+		// a letter.
+		if char == emptyChar {
+			continue
+		}
+		a.letters[char] = true
+		a.letters[unicode.ToLower(char)] = true
+		a.letters[unicode.ToUpper(char)] = true
+	}
+	return a
+}
+
+// Method to fold the case of a character, see CaseFolding.
+func (a Alphabet) Fold(char rune) rune {
+	switch a.Folding {
+	case FoldNone:
+		return char
+	case FoldTurkish:
+		return unicode.TurkishCase.ToLower(char)
+	}
+	return unicode.ToLower(char)
+}
+
+// Method to fold the case of a word, see CaseFolding.
+func (a Alphabet) FoldWord(word string) string {
+	switch a.Folding {
+	case FoldNone:
+		return word
+	case FoldTurkish:
+		return strings.ToLowerSpecial(unicode.TurkishCase, word)
+	}
+	return strings.ToLower(word)
+}
+
+// Method to check if the given character is part of the alphabet.
+func (a Alphabet) Contains(char rune) bool {
+	if len(a.letters) == 0 {
+		return unicode.IsLetter(char)
+	}
+	return a.letters[char]
+}
+
+// Method to check if all the characters of the word are part of the alphabet,
+// or punctuation of the alphabet with at least one letter in the word.
+// If phrases are allowed, the word can also be multiple words separated by
+// single spaces.
+func (a Alphabet) ValidWord(word string) bool {
+	if a.Phrases && strings.ContainsRune(word, phraseSeparator) {
+		for _, part := range strings.Split(word, string(phraseSeparator)) {
+			if !a.ValidWord(part) {
+				return false
+			}
+		}
+		return true
+	}
+	hasLetter := false
+	for _, char := range word {
+		switch {
+		case a.Contains(char):
+			hasLetter = true
+		case !strings.ContainsRune(a.Punctuation, char):
+			return false
+		}
+	}
+	return hasLetter
+}
+
+// Method to get the characters of the words which are revealed from the
+// start: the space if phrases are allowed, and the punctuation.
+func (a Alphabet) revealedChars() []rune {
+	var chars []rune
+	if a.Phrases {
+		chars = append(chars, phraseSeparator)
+	}
+	return append(chars, []rune(a.Punctuation)...)
+}
+
+// Method to check if a character of a word is revealed from the start and
+// never guessed, i.e. the space of a phrase or punctuation.
+func isRevealedChar(char rune) bool {
+	return char == phraseSeparator || unicode.IsPunct(char)
+}
+
+// Method to check the punctuation allowed in the words, see --punctuation.
+// Returns an error if one of the characters is not punctuation.
+func validatePunctuation(chars string) error {
+	for _, char := range chars {
+		if !unicode.IsPunct(char) {
+			return fmt.Errorf("%q is not punctuation", char)
+		}
+	}
+	return nil
+}
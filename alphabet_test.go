@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type AlphabetTestSuite struct {
+	suite.Suite
+}
+
+func (s *AlphabetTestSuite) SetupSuite() {
+	InitGame([]string{"über", "glüh", "straße"})
+}
+
+func (s *AlphabetTestSuite) TestDefaultAlphabet() {
+	a := NewAlphabet("")
+	assert.Equal(s.T(), true, a.ValidWord("straße"))
+	assert.Equal(s.T(), true, a.ValidWord("привет"))
+	assert.Equal(s.T(), false, a.ValidWord("hello1"))
+	assert.Equal(s.T(), false, a.ValidWord(""))
+}
+
+func (s *AlphabetTestSuite) TestCustomAlphabet() {
+	a := NewAlphabet("abcdefghijklmnopqrstuvwxyzäöüß")
+	assert.Equal(s.T(), true, a.Contains('Ü'))
+	assert.Equal(s.T(), true, a.ValidWord("Straße"))
+	assert.Equal(s.T(), false, a.ValidWord("café"))
+}
+
+func (s *AlphabetTestSuite) TestLengthInCharacters() {
+	// "straße" is 7 bytes long but only 6 characters.
+	assert.Equal(s.T(), []string{"straße"}, currentDictionary().Words(6))
+	assert.Equal(s.T(), 0, len(currentDictionary().Words(7)))
+}
+
+func (s *AlphabetTestSuite) TestMultiByteGuess() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	isValid, err := game.CheckUserInput('ü')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), true, isValid)
+	// Both the groups are of the same size and reveal one character, so the
+	// lexicographically smaller pattern is picked.
+	assert.Equal(s.T(), "__ü_", DefaultPatternFormat.Format(game.CurrentDisplayedWord))
+	assert.Equal(s.T(), []string{"glüh"}, game.CurrentSetOfWords)
+
+	// Digits are not part of any alphabet.
+	_, err = game.CheckUserInput('1')
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), 3, game.CurrentRetries)
+}
+
+func (s *AlphabetTestSuite) TestCaseFolding() {
+	a := NewAlphabet("")
+	assert.Equal(s.T(), 'a', a.Fold('A'))
+	assert.Equal(s.T(), "straße", a.FoldWord("STRAßE"))
+	a.Folding = FoldTurkish
+	assert.Equal(s.T(), 'ı', a.Fold('I'))
+	assert.Equal(s.T(), "istanbul", a.FoldWord("İSTANBUL"))
+	a.Folding = FoldNone
+	assert.Equal(s.T(), "Paris", a.FoldWord("Paris"))
+
+	folding, err := ParseCaseFolding("turkish")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), FoldTurkish, folding)
+	_, err = ParseCaseFolding("upper")
+	assert.NotNil(s.T(), err)
+}
+
+func (s *AlphabetTestSuite) TestUpperCaseInput() {
+	defer InitGame([]string{"über", "glüh", "straße"})
+	InitGame([]string{"CATS|Animal"})
+	assert.Equal(s.T(), []string{"cats"}, currentDictionary().Words(4))
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	clue, ok := game.Hint()
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "Animal", clue)
+	isValid, err := game.CheckUserInput('C')
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), isValid)
+	assert.Equal(s.T(), "c___", DefaultPatternFormat.Format(game.CurrentDisplayedWord))
+	// 'C' and 'c' are the same guess.
+	_, err = game.CheckUserInput('c')
+	assert.True(s.T(), errors.Is(err, ErrCharAlreadyUsed))
+	assert.Equal(s.T(), []rune("c"), game.UsedChars)
+}
+
+func (s *AlphabetTestSuite) TestCaseSensitive() {
+	*caseFolding = string(FoldNone)
+	defer func() {
+		*caseFolding = string(FoldLower)
+		InitGame([]string{"über", "glüh", "straße"})
+	}()
+	InitGame([]string{"Cats", "cats"})
+	assert.Equal(s.T(), []string{"Cats", "cats"}, currentDictionary().Words(4))
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('C')
+	_, err = game.CheckUserInput('c')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []rune("Cc"), game.UsedChars)
+}
+
+func TestAlphabetTestSuite(t *testing.T) {
+	suite.Run(t, new(AlphabetTestSuite))
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Words of the same length by their sorted letters, e.g. "aelst" for "least",
+// "slate" and "steal". The words of a key are sorted.
+type anagramIndex map[string][]string
+
+// Method to get the key of a word in the anagram index: its letters, sorted.
+func anagramKey(word string) string {
+	letters := []rune(word)
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i] < letters[j]
+	})
+	return string(letters)
+}
+
+// Method to build the anagram index of sorted words of the same length.
+func newAnagramIndex(words []string) anagramIndex {
+	idx := make(anagramIndex)
+	for _, word := range words {
+		key := anagramKey(word)
+		idx[key] = append(idx[key], word)
+	}
+	return idx
+}
+
+// Method to build the anagram index of every length of the dictionary. Must
+// only be called while the dictionary is built.
+func (d *Dictionary) indexAnagrams() {
+	d.anagrams = make(map[int]anagramIndex)
+	for _, length := range d.Lengths() {
+		d.anagrams[length] = newAnagramIndex(d.Words(length))
+	}
+}
+
+// Method to get the words of the dictionary made of the same letters as a
+// word, including the word itself if it is in the dictionary, in sorted order.
+// The word is folded like the guesses.
+func (d *Dictionary) Anagrams(word string) []string {
+	word = d.alphabet.FoldWord(word)
+	return d.anagrams[len([]rune(word))][anagramKey(word)]
+}
+
+// Anagram game: the letters of a word are shown shuffled, and the player must
+// find a word made of them within limited attempts, and time if the game has a
+// time limit. Every word of the dictionary made of the letters is accepted,
+// as the player can not tell them apart.
+type AnagramGame struct {
+	// Letters of the word, shuffled.
+	Letters string
+	// Total attempts allowed.
+	AllowedAttempts int
+	// Current attempts left.
+	CurrentAttempts int
+	// Words tried so far, in order.
+	Attempts []string
+	// Current state of the game.
+	State GameState
+	// Time allowed to find the word. Zero means there is no time limit.
+	TimeLimit time.Duration
+
+	// Word picked by the game.
+	word string
+	dict *Dictionary
+	// Clock used to enforce the time limit, and the time by which the word
+	// must be found.
+	clock    Clock
+	deadline time.Time
+}
+
+// Method to start an anagram game with a word of the given length picked in
+// the current dictionary, see pickWord.
+// It returns an error wrapping ErrInvalidLength if the dictionary has no word
+// of that length with at least two different letters, or ErrInvalidRetries if
+// the attempts are not positive.
+func NewAnagramGame(length, attempts int, timeLimit time.Duration, rng *rand.Rand) (
+	*AnagramGame, error) {
+	if attempts <= 0 {
+		return nil, newGameError(ErrInvalidRetries,
+			"Attempts must be positive, got %d", attempts)
+	}
+	d := currentDictionary()
+	// The words made of a single letter have nothing to shuffle, and the
+	// separators of the phrases are not letters.
+	var words []string
+	for _, word := range d.Words(length) {
+		if strings.Count(anagramKey(word), word[:1]) < len(word) &&
+			!strings.ContainsFunc(word, isRevealedChar) {
+			words = append(words, word)
+		}
+	}
+	if len(words) == 0 {
+		return nil, newGameError(ErrInvalidLength,
+			"No words of length %d in the dictionary to make an anagram of", length)
+	}
+	word := words[pickWord(rng, d, words)]
+	letters := []rune(word)
+	// Shuffled till the letters are not the word itself. Every word has two
+	// different letters, so it ends quickly.
+	for string(letters) == word {
+		rng.Shuffle(len(letters), func(i, j int) {
+			letters[i], letters[j] = letters[j], letters[i]
+		})
+	}
+	g := &AnagramGame{
+		Letters:         string(letters),
+		AllowedAttempts: attempts,
+		CurrentAttempts: attempts,
+		State:           Running,
+		TimeLimit:       timeLimit,
+		word:            word,
+		dict:            d,
+		clock:           realClock{},
+	}
+	if timeLimit > 0 {
+		g.deadline = g.clock.Now().Add(timeLimit)
+	}
+	return g, nil
+}
+
+// Method to get the time left to find the word.
+// Returns false if the game has no time limit or is not running.
+func (g *AnagramGame) TimeLeft() (time.Duration, bool) {
+	if g.TimeLimit <= 0 || g.State != Running {
+		return 0, false
+	}
+	left := g.deadline.Sub(g.clock.Now())
+	if left < 0 {
+		left = 0
+	}
+	return left, true
+}
+
+// Method to check a word typed by the player.
+// Returns true if the word is made of the letters and in the dictionary, which
+// wins the game, and false if it is not in the dictionary, which costs an
+// attempt. It returns an error wrapping ErrGameFinished if the game is over,
+// including when the time ran out before the word was typed, and
+// ErrInvalidCharacter if the word is not made of the letters, in which case
+// no attempt is used.
+func (g *AnagramGame) CheckWord(word string) (bool, error) {
+	if g.State != Running {
+		return false, newGameError(ErrGameFinished,
+			"Unexpected scenario: input given for a game which is not running")
+	}
+	if left, ok := g.TimeLeft(); ok && left == 0 {
+		g.State = Lost
+		return false, newGameError(ErrGameFinished,
+			"Time is up: the game was lost before the input was given")
+	}
+	word = g.dict.alphabet.FoldWord(strings.TrimSpace(word))
+	if anagramKey(word) != anagramKey(g.Letters) {
+		return false, newGameError(ErrInvalidCharacter,
+			"%s is not made of the letters %s, please try again.", word, g.Letters)
+	}
+	g.Attempts = append(g.Attempts, word)
+	for _, anagram := range g.dict.Anagrams(word) {
+		if anagram == word {
+			g.State = Won
+			return true, nil
+		}
+	}
+	g.CurrentAttempts--
+	if g.CurrentAttempts <= 0 {
+		g.State = Lost
+	}
+	return false, nil
+}
+
+// Method to get the word picked by the game, to show it once the game is lost.
+func (g *AnagramGame) Word() string {
+	return g.word
+}
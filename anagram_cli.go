@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+var (
+	anagramAttempts = flag.Int("anagram_attempts", 3,
+		"Number of words the player can try in the \"anagram\" subcommand.")
+	anagramTime = flag.Duration("anagram_time", time.Minute,
+		"Time allowed to find the word in the \"anagram\" subcommand, e.g. 30s. "+
+			"No limit if zero.")
+)
+
+// Method to show the letters of an anagram game, spaced so that they are not
+// read as a word, spelled out in screen reader mode.
+func formatAnagramLetters(g *AnagramGame) string {
+	if *screenReader {
+		return spokenWord([]rune(g.Letters))
+	}
+	return strings.ToUpper(strings.Join(strings.Split(g.Letters, ""), " "))
+}
+
+// Driver method for the "anagram" subcommand, where the player must find the
+// word whose letters are shown shuffled, with --anagram_attempts attempts and
+// --anagram_time to find it.
+func StartAnagram() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	var game *AnagramGame
+	for game == nil {
+		fmt.Println(tr("enter_length"))
+		length, err := readInt()
+		if isInputEnd(err) {
+			return err
+		}
+		if err != nil {
+			fmt.Println(tr("invalid_input", err))
+			continue
+		}
+		game, err = NewAnagramGame(length, *anagramAttempts, *anagramTime,
+			rand.New(rand.NewSource(randInt63())))
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+	ctx := context.Background()
+	if left, ok := game.TimeLeft(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, left)
+		defer cancel()
+	}
+	for game.State == Running {
+		fmt.Println("Letters:", formatAnagramLetters(game))
+		if left, ok := game.TimeLeft(); ok {
+			fmt.Printf("Enter the word (%d attempts and %v left): \n", game.CurrentAttempts,
+				left.Round(time.Second))
+		} else {
+			fmt.Printf("Enter the word (%d attempts left): \n", game.CurrentAttempts)
+		}
+		line, event, err := waitInput(false, nil, ctx.Done())
+		if err != nil {
+			return err
+		}
+		if event == inputDone {
+			// The game is lost once its time is up, whatever the input.
+			line = ""
+		}
+		found, err := game.CheckWord(line)
+		switch {
+		case errors.Is(err, ErrGameFinished):
+			fmt.Println(colors.wrong("Time is up!"))
+		case err != nil:
+			fmt.Println(err)
+		case found:
+			fmt.Println(colors.correct(tr("won")))
+		case game.State == Running:
+			fmt.Println(colors.wrong(fmt.Sprint("Sorry that is not a word. Remaining attempts: ",
+				game.CurrentAttempts)))
+		}
+	}
+	if game.State == Lost {
+		fmt.Println(colors.wrong("You lose!! The word was " + game.Word()))
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type AnagramTestSuite struct {
+	suite.Suite
+}
+
+func (s *AnagramTestSuite) SetupTest() {
+	InitGame([]string{"least", "slate", "steal", "stale", "table", "aaaaa", "notes",
+		"stone", "onset", "a b", "ab", "ba"})
+}
+
+// Method to start a game of 5 letters words.
+func (s *AnagramTestSuite) newGame(attempts int, timeLimit time.Duration) *AnagramGame {
+	game, err := NewAnagramGame(5, attempts, timeLimit, rand.New(rand.NewSource(1)))
+	s.Require().NoError(err)
+	return game
+}
+
+func (s *AnagramTestSuite) TestAnagrams() {
+	d := currentDictionary()
+	assert.Equal(s.T(), []string{"least", "slate", "stale", "steal"}, d.Anagrams("tales"))
+	assert.Equal(s.T(), []string{"notes", "onset", "stone"}, d.Anagrams("TONES"))
+	assert.Equal(s.T(), []string{"ab", "ba"}, d.Anagrams("ab"))
+	assert.Empty(s.T(), d.Anagrams("blast"))
+	assert.Empty(s.T(), d.Anagrams("xyz"))
+}
+
+func (s *AnagramTestSuite) TestAnagramsFiltered() {
+	*maxWordLength = 4
+	defer func() { *maxWordLength = 0 }()
+	InitGame([]string{"least", "slate", "ab", "ba"})
+	d := currentDictionary()
+	assert.Empty(s.T(), d.Anagrams("least"))
+	assert.Equal(s.T(), []string{"ab", "ba"}, d.Anagrams("ba"))
+}
+
+func (s *AnagramTestSuite) TestNewGame() {
+	for seed := int64(0); seed < 20; seed++ {
+		game, err := NewAnagramGame(5, 3, 0, rand.New(rand.NewSource(seed)))
+		s.Require().NoError(err)
+		// The word made of a single letter is never picked, and the letters
+		// are never shown in the order of the word.
+		assert.NotEqual(s.T(), "aaaaa", game.Word())
+		assert.NotEqual(s.T(), game.Word(), game.Letters)
+		assert.Equal(s.T(), anagramKey(game.Word()), anagramKey(game.Letters))
+		assert.Equal(s.T(), Running, game.State)
+	}
+	_, err := NewAnagramGame(7, 3, 0, rand.New(rand.NewSource(1)))
+	assert.True(s.T(), errors.Is(err, ErrInvalidLength))
+	// The phrases are left out.
+	_, err = NewAnagramGame(3, 3, 0, rand.New(rand.NewSource(1)))
+	assert.True(s.T(), errors.Is(err, ErrInvalidLength))
+	_, err = NewAnagramGame(5, 0, 0, rand.New(rand.NewSource(1)))
+	assert.True(s.T(), errors.Is(err, ErrInvalidRetries))
+}
+
+func (s *AnagramTestSuite) TestCheckWord() {
+	game := s.newGame(2, 0)
+	// Any word made of the letters wins, not only the word picked.
+	var other string
+	for _, word := range currentDictionary().Anagrams(game.Letters) {
+		if word != game.Word() {
+			other = word
+		}
+	}
+	s.Require().NotEmpty(other)
+
+	_, err := game.CheckWord("table")
+	assert.True(s.T(), errors.Is(err, ErrInvalidCharacter))
+	assert.Equal(s.T(), 2, game.CurrentAttempts)
+	assert.Empty(s.T(), game.Attempts)
+
+	found, err := game.CheckWord(" " + other + " ")
+	s.Require().NoError(err)
+	assert.True(s.T(), found)
+	assert.Equal(s.T(), Won, game.State)
+	assert.Equal(s.T(), []string{other}, game.Attempts)
+
+	_, err = game.CheckWord(other)
+	assert.True(s.T(), errors.Is(err, ErrGameFinished))
+}
+
+func (s *AnagramTestSuite) TestAttemptsFinished() {
+	InitGame([]string{"notes", "stone", "onset"})
+	game := s.newGame(2, 0)
+	for _, word := range []string{"tones", "snote"} {
+		found, err := game.CheckWord(word)
+		s.Require().NoError(err)
+		assert.False(s.T(), found)
+	}
+	assert.Equal(s.T(), Lost, game.State)
+	assert.Equal(s.T(), []string{"tones", "snote"}, game.Attempts)
+}
+
+func (s *AnagramTestSuite) TestTimeLimit() {
+	game := s.newGame(3, time.Minute)
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	game.clock = clock
+	game.deadline = clock.Now().Add(game.TimeLimit)
+
+	left, ok := game.TimeLeft()
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), time.Minute, left)
+	clock.Advance(45 * time.Second)
+	left, _ = game.TimeLeft()
+	assert.Equal(s.T(), 15*time.Second, left)
+
+	clock.Advance(time.Minute)
+	_, err := game.CheckWord(game.Word())
+	assert.True(s.T(), errors.Is(err, ErrGameFinished))
+	assert.Equal(s.T(), Lost, game.State)
+	_, ok = game.TimeLeft()
+	assert.False(s.T(), ok)
+
+	_, ok = s.newGame(3, 0).TimeLeft()
+	assert.False(s.T(), ok)
+}
+
+func TestAnagramTestSuite(t *testing.T) {
+	suite.Run(t, new(AnagramTestSuite))
+}
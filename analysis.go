@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Words a game was choosing from before a guess, kept for the analysis of the
+// game, see WithAnalysis.
+type turnSnapshot struct {
+	char  rune
+	words []string
+	// Characters used before the guess.
+	used []rune
+	// Number of words left after the guess.
+	after int
+}
+
+// Snapshots of the turns of a game analyzed once it ends.
+type gameAnalysis struct {
+	// Snapshot taken at the start of the current turn, completed when the
+	// turn is recorded.
+	pending *turnSnapshot
+	turns   []turnSnapshot
+}
+
+// Analysis of a guess of a finished game, like the analysis of a move by a
+// chess engine.
+type GuessAnalysis struct {
+	Char rune
+	// Letter which would have told the most about the word, as picked by the
+	// solver (see Solver.NextGuess).
+	Best rune
+	// Number of words the computer was choosing from before and after the
+	// guess.
+	Before int
+	After  int
+	// Fraction of the words removed by the guess.
+	Shrink float64
+	// Information expected from the guess and from the best letter, in bits,
+	// whatever the answer of the computer.
+	Bits     float64
+	BestBits float64
+	// Information of the guess relative to the best letter, from 0 to 100.
+	Accuracy float64
+}
+
+// Analysis of a finished game, see Game.Analysis.
+type Analysis struct {
+	Guesses []GuessAnalysis
+	// Average accuracy of the guesses, from 0 to 100. Zero without guesses.
+	Accuracy float64
+}
+
+// Option to keep the words the game is choosing from before every guess, so
+// that the game can be analyzed once it ends, see Game.Analysis. The words are
+// shared with the game, but it keeps more memory for its whole life.
+func WithAnalysis() GameOption {
+	return func(g *Game) {
+		g.analysis = &gameAnalysis{}
+	}
+}
+
+// Method to take the snapshot of the turn starting. The game lock must be
+// held.
+func (g *Game) snapshotTurnLocked() {
+	if g.analysis == nil {
+		return
+	}
+	g.analysis.pending = &turnSnapshot{
+		words: g.candidatesLocked(),
+		used:  append([]rune{}, g.UsedChars...),
+	}
+}
+
+// Method to complete the snapshot of the turn with its guess. Timeouts are
+// not analyzed. The game lock must be held.
+func (g *Game) recordAnalysisLocked(turn Turn) {
+	if g.analysis == nil || g.analysis.pending == nil || turn.Kind != TurnGuess {
+		return
+	}
+	snapshot := *g.analysis.pending
+	snapshot.char = turn.Char
+	snapshot.after = g.candidateCountLocked()
+	g.analysis.turns = append(g.analysis.turns, snapshot)
+}
+
+// Method to analyze the guesses of the game: for every guess, the letter
+// which would have told the most about the word, how many words the guess
+// removed, and how close it was to the best letter. Only the guesses of games
+// created with WithAnalysis are analyzed.
+func (g *Game) Analysis() Analysis {
+	g.mu.Lock()
+	var turns []turnSnapshot
+	if g.analysis != nil {
+		turns = append(turns, g.analysis.turns...)
+	}
+	length := g.ExpectedLength
+	g.mu.Unlock()
+	var analysis Analysis
+	for _, turn := range turns {
+		solver := Solver{WordLength: length, Candidates: turn.words, UsedChars: turn.used}
+		guess := GuessAnalysis{Char: turn.char, Before: len(turn.words), After: turn.after,
+			Accuracy: 100}
+		guess.Best, _ = solver.NextGuess()
+		if guess.Before > 0 {
+			guess.Shrink = 1 - float64(guess.After)/float64(guess.Before)
+			guess.Bits = solver.entropy(turn.char)
+			guess.BestBits = solver.entropy(guess.Best)
+		}
+		if guess.BestBits > 0 {
+			guess.Accuracy = 100 * guess.Bits / guess.BestBits
+		}
+		analysis.Guesses = append(analysis.Guesses, guess)
+		analysis.Accuracy += guess.Accuracy
+	}
+	if len(analysis.Guesses) > 0 {
+		analysis.Accuracy /= float64(len(analysis.Guesses))
+	}
+	return analysis
+}
+
+// Method to format the analysis of a game for the terminal.
+func formatAnalysis(analysis Analysis) string {
+	var b strings.Builder
+	b.WriteString("Analysis of the game:\n")
+	fmt.Fprintf(&b, "  %-5s %-5s %13s %7s %9s\n", "Guess", "Best", "Words", "Shrink",
+		"Accuracy")
+	for _, guess := range analysis.Guesses {
+		best := string(guess.Best)
+		if guess.Best == guess.Char || guess.Accuracy >= 100 {
+			best = "="
+		}
+		fmt.Fprintf(&b, "  %-5c %-5s %6d → %-4d %6.0f%% %8.0f%%\n", guess.Char, best,
+			guess.Before, guess.After, guess.Shrink*100, guess.Accuracy)
+	}
+	fmt.Fprintf(&b, "Accuracy: %.0f%%\n", analysis.Accuracy)
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type AnalysisTestSuite struct {
+	suite.Suite
+}
+
+func (s *AnalysisTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *AnalysisTestSuite) TestAnalysis() {
+	game, err := NewGame(4, 3, WithAnalysis())
+	assert.Nil(s.T(), err)
+	// Keeps last and fast.
+	_, err = game.CheckUserInput('s')
+	assert.Nil(s.T(), err)
+	// The invalid guesses are not analyzed.
+	_, err = game.CheckUserInput('s')
+	assert.NotNil(s.T(), err)
+	// In none of the words, so it tells nothing.
+	_, err = game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+
+	analysis := game.Analysis()
+	assert.Equal(s.T(), 2, len(analysis.Guesses))
+	first := analysis.Guesses[0]
+	assert.Equal(s.T(), 's', first.Char)
+	assert.Equal(s.T(), 4, first.Before)
+	assert.Equal(s.T(), 2, first.After)
+	assert.Equal(s.T(), 0.5, first.Shrink)
+	assert.Greater(s.T(), first.Bits, 0.0)
+	assert.LessOrEqual(s.T(), first.Bits, first.BestBits)
+	second := analysis.Guesses[1]
+	assert.Equal(s.T(), 'z', second.Char)
+	assert.Equal(s.T(), 2, second.Before)
+	assert.Equal(s.T(), 2, second.After)
+	assert.Equal(s.T(), 0.0, second.Shrink)
+	assert.Equal(s.T(), 0.0, second.Accuracy)
+	assert.Contains(s.T(), "lf", string(second.Best))
+	assert.Equal(s.T(), first.Accuracy/2, analysis.Accuracy)
+
+	text := formatAnalysis(analysis)
+	assert.True(s.T(), strings.HasPrefix(text, "Analysis of the game:"))
+	assert.Contains(s.T(), text, "Accuracy: ")
+}
+
+func (s *AnalysisTestSuite) TestWithoutAnalysis() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	_, err = game.CheckUserInput('s')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), Analysis{}, game.Analysis())
+}
+
+func TestAnalysisTestSuite(t *testing.T) {
+	suite.Run(t, new(AnalysisTestSuite))
+}
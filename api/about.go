@@ -0,0 +1,17 @@
+package api
+
+// Information about the dictionary the server plays with. Word lists are often
+// distributed under licenses which require the attribution to be shown to the
+// players, so it is served by the /about endpoint.
+type About struct {
+	// Name of the dictionary.
+	Name string `json:"name,omitempty"`
+	// Where the dictionary was taken from, usually a URL.
+	Source string `json:"source,omitempty"`
+	// License the dictionary is distributed under, e.g. "CC-BY-SA-4.0".
+	License string `json:"license,omitempty"`
+	// Attribution required by the license.
+	Attribution string `json:"attribution,omitempty"`
+	// Number of words loaded from the dictionary.
+	WordCount int `json:"word_count"`
+}
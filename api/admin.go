@@ -0,0 +1,42 @@
+package api
+
+import (
+	"time"
+)
+
+// Game of the server as seen by its admins.
+type AdminGame struct {
+	Game
+	// Time the game was created.
+	Created time.Time `json:"created"`
+	// Number of words the game can still pick the word from.
+	Candidates int `json:"candidates"`
+}
+
+// Games kept by the server, listed by the admin API.
+type AdminGameList struct {
+	Games []AdminGame `json:"games"`
+}
+
+// Words a game can still pick the word from, shown by the admin API.
+type AdminCandidates struct {
+	ID string `json:"id"`
+	// Words consistent with the guesses so far, sorted.
+	Words []string `json:"words"`
+}
+
+// Aggregate stats of the games kept by the server, shown by the admin API.
+// The counters of the process are served by GET /stats.
+type AdminStats struct {
+	// Number of games by state.
+	Games map[GameState]int `json:"games"`
+	// Number of running games by word length.
+	RunningByLength map[int]int `json:"running_by_length,omitempty"`
+	// Number of candidate words kept by the running games, which take most of
+	// their memory.
+	Candidates int `json:"candidates"`
+	// Number of named players with a game kept.
+	Players int `json:"players"`
+	// Number of words in the dictionary.
+	DictionaryWords int `json:"dictionary_words"`
+}
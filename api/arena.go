@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// Version of JSON-RPC used to talk to the guesser bots.
+const JSONRPCVersion = "2.0"
+
+// Method called on a guesser bot for every move, with a BotState as params
+// and a BotGuess as result.
+const BotMethodGuess = "guess"
+
+// Game as seen by a guesser bot of the arena when it is its turn to move.
+type BotState struct {
+	WordLength int `json:"word_length"`
+	// Word shown to the bot, with "_" for the characters not guessed yet.
+	MaskedWord string `json:"masked_word"`
+	// Characters guessed so far, in order.
+	UsedChars string `json:"used_chars"`
+	// Incorrect guesses which can still be made.
+	RetriesLeft int `json:"retries_left"`
+}
+
+// Move of a guesser bot.
+type BotGuess struct {
+	// Guessed character. It must be a single letter not guessed yet.
+	Char string `json:"char"`
+}
+
+// JSON-RPC 2.0 request, sent to the bots one per line.
+type RPCRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// Error object of a JSON-RPC 2.0 response of a bot.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC 2.0 response, read from the bots one per line. Exactly one of
+// Result and Error is set.
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
@@ -0,0 +1,52 @@
+package api
+
+// Type of an event written by the terminal game with --output=json, one JSON
+// object per line.
+type CLIEventType string
+
+const (
+	// The game waits for a line of input, see CLIPrompt.
+	CLIEventPrompt CLIEventType = "prompt"
+	// A new game started, with its state.
+	CLIEventStarted CLIEventType = "started"
+	// A guess was played, with the new state of the game.
+	CLIEventGuess CLIEventType = "guess"
+	// The game is won or lost, with the word.
+	CLIEventOver CLIEventType = "over"
+	// A line of input could not be used, and the game prompts for it again.
+	CLIEventError CLIEventType = "error"
+	// The player quit, or the input ended.
+	CLIEventQuit CLIEventType = "quit"
+)
+
+// Input a prompt event waits for.
+type CLIPrompt string
+
+const (
+	// "y" to play a new game, "n" to quit.
+	PromptNewGame CLIPrompt = "new_game"
+	// Length of the word of the new game, 0 for a random length.
+	PromptWordLength CLIPrompt = "word_length"
+	// Number of retries of the new game.
+	PromptRetries CLIPrompt = "retries"
+	// A single character to guess.
+	PromptGuess CLIPrompt = "guess"
+)
+
+// Event written by the terminal game with --output=json. Only the fields
+// relevant to the event type are set.
+type CLIEvent struct {
+	Type CLIEventType `json:"type"`
+	// Input waited for, for prompt events.
+	Prompt CLIPrompt `json:"prompt,omitempty"`
+	// Guessed character and whether it was accepted, for guess events.
+	Char     string `json:"char,omitempty"`
+	Accepted *bool  `json:"accepted,omitempty"`
+	// State of the game, with the masked word and the retries left, for the
+	// guess prompts and the started, guess and over events.
+	Game *Game `json:"game,omitempty"`
+	// Word of the game, for over events.
+	Word string `json:"word,omitempty"`
+	// Error, for error events.
+	Error *Error `json:"error,omitempty"`
+}
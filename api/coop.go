@@ -0,0 +1,51 @@
+package api
+
+import (
+	"time"
+)
+
+// Players of a cooperative game, who take turns guessing the same word and
+// share its retries.
+type Coop struct {
+	// Names of the players, the host first. Only the host till the partner
+	// joins.
+	Players []string `json:"players"`
+	// Name of the player whose turn it is to guess, empty till the partner
+	// joins.
+	Turn string `json:"turn,omitempty"`
+	// Code the partner joins the game with, only shown till the partner
+	// joins.
+	JoinCode string `json:"join_code,omitempty"`
+}
+
+// Seat of a player in a cooperative game, returned to the host when the game
+// is created and to the partner when joining it.
+type CoopSeat struct {
+	Game Game `json:"game"`
+	// Secret token of the player, sent with every guess of the player (see
+	// GuessRequest.PlayerToken) to prove whose guess it is.
+	PlayerToken string `json:"player_token"`
+}
+
+// Body of the request to join a cooperative game.
+type JoinRequest struct {
+	// Join code of the game, given by the host or listed in the lobby.
+	Code string `json:"code"`
+	// Name of the player joining, which must differ from the name of the
+	// host.
+	Player string `json:"player"`
+}
+
+// Cooperative game waiting for a partner, as listed in the lobby.
+type LobbyGame struct {
+	JoinCode   string    `json:"join_code"`
+	Host       string    `json:"host"`
+	WordLength int       `json:"word_length"`
+	Retries    int       `json:"retries"`
+	Created    time.Time `json:"created"`
+}
+
+// Cooperative games listed in the lobby of the server, oldest first.
+type Lobby struct {
+	Games []LobbyGame `json:"games"`
+}
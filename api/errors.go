@@ -0,0 +1,119 @@
+// Package api defines the types exchanged between the WordGuess server and its
+// clients. The same types are used by all the transports (REST and WebSocket)
+// so that clients see the same errors whichever transport they use.
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Stable code of an error returned by the server.
+// The codes are part of the API: an existing code must never be renamed or
+// reused for a different failure. New failures get new codes.
+type ErrorCode string
+
+const (
+	// The request could not be parsed or is missing required fields.
+	CodeInvalidRequest ErrorCode = "invalid_request"
+	// No word of the requested length exists in the dictionary.
+	CodeInvalidLength ErrorCode = "invalid_length"
+	// The requested number of retries is negative or above the max allowed.
+	CodeInvalidRetries ErrorCode = "invalid_retries"
+	// The guess is not a single letter of the dictionary alphabet.
+	CodeInvalidCharacter ErrorCode = "invalid_character"
+	// The guessed character has already been used in this game.
+	CodeCharacterUsed ErrorCode = "character_used"
+	// No game exists with the given id.
+	CodeGameNotFound ErrorCode = "game_not_found"
+	// The game has already been won or lost.
+	CodeGameFinished ErrorCode = "game_finished"
+	// The game ran out of time, i.e. the deadlines of the guesses were missed
+	// or the correspondence game expired, or it was forfeited.
+	CodeGameExpired ErrorCode = "game_expired"
+	// The guess was given after the deadline of its turn, which was recorded
+	// as a timeout instead. The game goes on if it has retries left.
+	CodeTurnTimeout ErrorCode = "turn_timeout"
+	// The player tried to guess when it was another player's turn.
+	CodeNotYourTurn ErrorCode = "not_your_turn"
+	// The client is not allowed to do this, e.g. a spectator guessing.
+	CodeForbidden ErrorCode = "forbidden"
+	// The client sent too many requests.
+	CodeRateLimited ErrorCode = "rate_limited"
+	// Unexpected failure in the server.
+	CodeInternal ErrorCode = "internal"
+	// A preset has an invalid rule or name.
+	CodeInvalidPreset ErrorCode = "invalid_preset"
+	// No preset exists with the given name.
+	CodePresetNotFound ErrorCode = "preset_not_found"
+	// A preset with the same name already exists.
+	CodePresetExists ErrorCode = "preset_exists"
+	// The words the server is still choosing from do not share a clue (yet).
+	CodeHintUnavailable ErrorCode = "hint_unavailable"
+	// No class of words of the hall of shame can be played, e.g. every word
+	// of the requested length was solved.
+	CodeChallengeUnavailable ErrorCode = "challenge_unavailable"
+	// No feature exists with the given name.
+	CodeFeatureNotFound ErrorCode = "feature_not_found"
+	// The dictionary could not be loaded again, the server keeps the one it
+	// had.
+	CodeReloadFailed ErrorCode = "reload_failed"
+	// A cooperative game already has its two players.
+	CodeGameFull ErrorCode = "game_full"
+	// The request was canceled, or did not complete before its deadline (see
+	// --request_timeout). It can be retried.
+	CodeTimeout ErrorCode = "timeout"
+)
+
+// Error returned by the server, serialized as
+// {"code": "...", "message": "...", "details": {...}}.
+type Error struct {
+	// Stable code which clients can use to handle the error.
+	Code ErrorCode `json:"code"`
+	// Human readable description, which may change between versions.
+	Message string `json:"message"`
+	// Optional structured details, e.g. the invalid value.
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Method to create a new error with a formatted message.
+func NewError(code ErrorCode, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Method to add a detail to the error. Returns the same error so that calls
+// can be chained.
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+	e.Details[key] = value
+	return e
+}
+
+// Method to get the HTTP status code used for the error code.
+func (c ErrorCode) HTTPStatus() int {
+	switch c {
+	case CodeInvalidRequest, CodeInvalidLength, CodeInvalidRetries,
+		CodeInvalidCharacter, CodeInvalidPreset:
+		return http.StatusBadRequest
+	case CodeGameNotFound, CodePresetNotFound, CodeFeatureNotFound:
+		return http.StatusNotFound
+	case CodeCharacterUsed, CodeGameFinished, CodeNotYourTurn, CodePresetExists,
+		CodeHintUnavailable, CodeChallengeUnavailable, CodeGameFull, CodeTurnTimeout:
+		return http.StatusConflict
+	case CodeGameExpired:
+		return http.StatusGone
+	case CodeForbidden:
+		return http.StatusForbidden
+	case CodeRateLimited:
+		return http.StatusTooManyRequests
+	case CodeTimeout:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
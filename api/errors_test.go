@@ -0,0 +1,29 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestErrorJSON(t *testing.T) {
+	err := NewError(CodeInvalidLength, "no words of length %d", 42).
+		WithDetail("length", 42)
+	data, jsonErr := json.Marshal(err)
+	assert.Nil(t, jsonErr)
+	assert.Equal(t, `{"code":"invalid_length","message":"no words of length 42",`+
+		`"details":{"length":42}}`, string(data))
+
+	var decoded Error
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, CodeInvalidLength, decoded.Code)
+	assert.Equal(t, "invalid_length: no words of length 42", decoded.Error())
+}
+
+func TestErrorMappings(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, CodeInvalidRetries.HTTPStatus())
+	assert.Equal(t, http.StatusTooManyRequests, CodeRateLimited.HTTPStatus())
+	assert.Equal(t, http.StatusInternalServerError, ErrorCode("unknown").HTTPStatus())
+	assert.Equal(t, http.StatusGone, CodeGameExpired.HTTPStatus())
+}
@@ -0,0 +1,52 @@
+package api
+
+// Rule of a feature flag, deciding where and for whom the feature is enabled.
+type FeatureRule struct {
+	// False to turn the feature off for everyone.
+	Enabled bool `json:"enabled"`
+	// Deployments the feature is enabled on (see --deployment), every
+	// deployment if empty.
+	Deployments []string `json:"deployments,omitempty"`
+	// Cohorts of players the feature is enabled for.
+	Cohorts []string `json:"cohorts,omitempty"`
+	// Percentage (0 to 100) of the other named players the feature is
+	// enabled for. A player is picked by a hash of their name, so they keep
+	// the feature while the percentage grows. Nil enables the feature for
+	// every player, including the anonymous ones, unless cohorts are given.
+	Percent *int `json:"percent,omitempty"`
+}
+
+// Feature flags of a deployment, as stored in --features_file.
+type FeatureConfig struct {
+	// Players of each cohort by cohort name.
+	Cohorts map[string][]string `json:"cohorts,omitempty"`
+	// Rules of the features by feature name. The features without a rule
+	// are in their default state.
+	Features map[string]FeatureRule `json:"features,omitempty"`
+}
+
+// Feature which can be turned on and off with a feature flag.
+type Feature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// State of the feature when it has no rule.
+	Default bool `json:"default"`
+	// Rule of the feature, nil if it is in its default state.
+	Rule *FeatureRule `json:"rule,omitempty"`
+	// True if the feature is enabled for the player the features were listed
+	// for, or for an anonymous player.
+	Enabled bool `json:"enabled"`
+}
+
+// Response listing the features.
+type FeatureList struct {
+	// Deployment of the server, see --deployment.
+	Deployment string              `json:"deployment,omitempty"`
+	Features   []Feature           `json:"features"`
+	Cohorts    map[string][]string `json:"cohorts"`
+}
+
+// Body of the request to set the players of a cohort.
+type Cohort struct {
+	Players []string `json:"players"`
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"time"
+)
+
+// State of a game as seen by the clients.
+type GameState string
+
+const (
+	StateRunning GameState = "running"
+	StateWon     GameState = "won"
+	StateLost    GameState = "lost"
+	// The game was lost because no guess was made before its deadline,
+	// instead of running out of retries.
+	StateForfeited GameState = "forfeited"
+)
+
+// Rule deciding when the wrong guesses of a game lose it.
+type RetryPolicy string
+
+const (
+	// The game is lost once all the retries are used, i.e. the retries are the
+	// number of wrong guesses the player can make. This is the default.
+	RetryStrict RetryPolicy = "strict"
+	// The game is lost at the first wrong guess made after all the retries
+	// are used.
+	RetryLenient RetryPolicy = "lenient"
+	// The game is never lost, the retries left stop at zero.
+	RetryUnlimited RetryPolicy = "unlimited"
+)
+
+// Public view of a game. It never contains the words the server is still
+// choosing from.
+type Game struct {
+	// Id of the game, used in the URLs of the game.
+	ID string `json:"id"`
+	// Length of the word to be guessed.
+	WordLength int `json:"word_length"`
+	// Word shown to the player, with "_" for the characters not guessed yet,
+	// whatever the glyph used by the terminal.
+	MaskedWord string `json:"masked_word"`
+	// Characters guessed so far, in order.
+	UsedChars string `json:"used_chars"`
+	// Incorrect guesses which can still be made.
+	RetriesLeft int `json:"retries_left"`
+	// Incorrect guesses allowed when the game started.
+	AllowedRetries int `json:"allowed_retries"`
+	// Current state of the game.
+	State GameState `json:"state"`
+	// When the wrong guesses lose the game.
+	RetryPolicy RetryPolicy `json:"retry_policy"`
+	// True for practice games, which allow previewing guesses.
+	Practice bool `json:"practice,omitempty"`
+	// Name of the preset the game was created with, if any.
+	Preset string `json:"preset,omitempty"`
+	// True once the clue of the word can be requested, see Hint.
+	HintAvailable bool `json:"hint_available,omitempty"`
+	// Name of the player, if given when the game was created.
+	Player string `json:"player,omitempty"`
+	// True for challenge games, played on the words no player of the server
+	// has solved yet.
+	Challenge bool `json:"challenge,omitempty"`
+	// Correspondence games, and the games of servers with deadlines: time by
+	// which the next guess must be made, after which the game is forfeited.
+	// Nil for the other games.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Correspondence games: number of warnings sent since the last guess
+	// that the game is about to expire.
+	ExpiryWarnings int `json:"expiry_warnings,omitempty"`
+	// True if the game was lost because it expired, instead of running out of
+	// retries. The state is then StateForfeited.
+	Forfeited bool `json:"forfeited,omitempty"`
+	// Cooperative games: players of the game and whose turn it is. Nil for
+	// the other games.
+	Coop *Coop `json:"coop,omitempty"`
+}
+
+// Clue of the word of a game, from the dictionary. It is only given once all
+// the words the server is still choosing from share the clue.
+type Hint struct {
+	GameID string `json:"game_id"`
+	Hint   string `json:"hint"`
+}
+
+// Body of the request to create a new game.
+type CreateGameRequest struct {
+	WordLength int `json:"word_length"`
+	Retries    int `json:"retries"`
+	// When the wrong guesses lose the game, strict if empty.
+	RetryPolicy RetryPolicy `json:"retry_policy,omitempty"`
+	// Create a practice game, which allows previewing guesses.
+	Practice bool `json:"practice,omitempty"`
+	// Name of a preset to create the game with. The rules of the preset take
+	// the place of the retries, and of the word length if the preset sets it.
+	Preset string `json:"preset,omitempty"`
+	// Name of the player, under which the finished game is recorded in the
+	// leaderboard of the server.
+	Player string `json:"player,omitempty"`
+	// Create a correspondence game, played over days: the game is forfeited
+	// if no guess is made for this many seconds. Every guess gives the
+	// player the full time again. Zero for no limit.
+	LifetimeSeconds int `json:"lifetime_seconds,omitempty"`
+	// Create a challenge game, on a class of words from the hall of shame of
+	// the server, i.e. which no player has solved yet. The word length is
+	// picked among these classes if zero. A won challenge game counts double
+	// in the leaderboard.
+	Challenge bool `json:"challenge,omitempty"`
+	// Create a cooperative game for two players, who take turns guessing and
+	// share the retries. Player is required and names the host, who guesses
+	// first once a partner joins with the join code of the game. The response
+	// is a CoopSeat instead of a Game.
+	Coop bool `json:"coop,omitempty"`
+	// Cooperative games: list the game in the lobby of the server, so that
+	// any player can join it. Otherwise only the players the host gives the
+	// join code to can.
+	Lobby bool `json:"lobby,omitempty"`
+}
+
+// Body of the request to guess a character.
+type GuessRequest struct {
+	// Guessed character. It must be a single letter.
+	Char string `json:"char"`
+	// Cooperative games: token of the player guessing, see CoopSeat.
+	PlayerToken string `json:"player_token,omitempty"`
+}
+
+// Response to a guess.
+type GuessResponse struct {
+	// True if the character is present in the word.
+	Accepted bool `json:"accepted"`
+	// State of the game after the guess.
+	Game Game `json:"game"`
+}
+
+// Preview of a single character, see Preview.
+type LetterPreview struct {
+	Char string `json:"char"`
+	// Fraction of the remaining words containing the character.
+	Probability float64 `json:"probability"`
+	// True if the character would be accepted if it was guessed now.
+	WouldAccept bool `json:"would_accept"`
+}
+
+// Preview of the characters not guessed yet in a practice game, e.g. to show
+// a heatmap over a keyboard. Characters which are not listed have a
+// probability of zero.
+type Preview struct {
+	GameID string `json:"game_id"`
+	// Number of guesses made when the preview was computed.
+	Guesses int             `json:"guesses"`
+	Letters []LetterPreview `json:"letters"`
+}
+
+// Body of all the error responses of the REST API.
+type ErrorResponse struct {
+	Error *Error `json:"error"`
+}
+
+// Type of a message sent over the WebSocket connection of a game.
+type MessageType string
+
+const (
+	// Sent by the server when a client connects and after every guess made
+	// by any player of the game.
+	MessageState MessageType = "state"
+	// Sent by a player to guess a character.
+	MessageGuess MessageType = "guess"
+	// Sent by the server when a message from the client could not be handled.
+	MessageError MessageType = "error"
+	// Sent by the server to the clients of a practice game after every state
+	// message.
+	MessagePreview MessageType = "preview"
+	// Sent by the server to the clients of a correspondence game when the
+	// game is about to expire, with the state of the game.
+	MessageExpiryWarning MessageType = "expiry_warning"
+)
+
+// Message sent over the WebSocket connection of a game, in either direction.
+// Only the fields relevant to the message type are set.
+type Message struct {
+	Type MessageType `json:"type"`
+	// Guessed character, for guess messages.
+	Char string `json:"char,omitempty"`
+	// Guessed character and whether it was accepted, for state messages sent
+	// after a guess.
+	LastGuess string `json:"last_guess,omitempty"`
+	Accepted  *bool  `json:"accepted,omitempty"`
+	// State of the game, for state and expiry warning messages.
+	Game *Game `json:"game,omitempty"`
+	// Error, for error messages.
+	Error *Error `json:"error,omitempty"`
+	// Preview of the next guess, for preview messages.
+	Preview *Preview `json:"preview,omitempty"`
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"time"
+)
+
+// Finished game recorded in the leaderboard.
+type LeaderboardEntry struct {
+	Player     string `json:"player"`
+	WordLength int    `json:"word_length"`
+	// Number of incorrect guesses made.
+	RetriesUsed int  `json:"retries_used"`
+	Won         bool `json:"won"`
+	// True if the game was lost because the player did not guess in time.
+	Forfeited bool `json:"forfeited,omitempty"`
+	// True for a challenge game, which counts double when won.
+	Challenge bool `json:"challenge,omitempty"`
+	// Time taken to finish the game, in milliseconds.
+	DurationMillis int64     `json:"duration_ms"`
+	Finished       time.Time `json:"finished"`
+}
+
+// Results of a player in the leaderboard.
+type PlayerStanding struct {
+	Player string `json:"player"`
+	// One point per game won, two per challenge game won.
+	Points int `json:"points"`
+	Games  int `json:"games"`
+	Wins   int `json:"wins"`
+	// Games lost because the player did not guess in time. They also count
+	// as games played and not won.
+	Forfeits int `json:"forfeits,omitempty"`
+	// Fraction of the games won, from 0 to 1.
+	WinRate float64 `json:"win_rate"`
+	// Average number of incorrect guesses per game.
+	AverageRetriesUsed float64 `json:"average_retries_used"`
+	// Fastest won game in milliseconds, zero if no game was won.
+	FastestWinMillis int64 `json:"fastest_win_ms,omitempty"`
+}
+
+// Top players of the leaderboard, best first.
+type Leaderboard struct {
+	Players []PlayerStanding `json:"players"`
+}
@@ -0,0 +1,27 @@
+package api
+
+// Named set of rules which games can be created with, e.g. a blitz preset with
+// a short time for every guess. Presets are stored by the server, and sharing
+// one is a matter of posting its JSON to another server.
+type Preset struct {
+	// Unique name of the preset, made of lower case letters, digits, "-" and
+	// "_".
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Length of the word. Zero lets the creator of the game pick the length.
+	WordLength int `json:"word_length,omitempty"`
+	// Number of incorrect guesses allowed.
+	Retries int `json:"retries"`
+	// Time allowed for every guess. A retry is consumed every time it runs
+	// out. Zero means no time limit.
+	GuessTimeoutSeconds int `json:"guess_timeout_seconds,omitempty"`
+	// Create practice games, which allow previewing guesses.
+	Practice bool `json:"practice,omitempty"`
+	// True for the presets which come with the server and can not be changed.
+	Builtin bool `json:"builtin,omitempty"`
+}
+
+// Response listing the presets.
+type PresetList struct {
+	Presets []Preset `json:"presets"`
+}
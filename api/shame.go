@@ -0,0 +1,33 @@
+package api
+
+import (
+	"time"
+)
+
+// Finished game recorded in the hall of shame of a server.
+type ShameEntry struct {
+	// Word shown when the game started, with "_" for the characters not
+	// guessed yet. The server keeps changing its word during a game, so the
+	// games are grouped by the class of words they started from rather than
+	// by word.
+	Pattern    string    `json:"pattern"`
+	WordLength int       `json:"word_length"`
+	Won        bool      `json:"won"`
+	Finished   time.Time `json:"finished"`
+}
+
+// Class of words which no player of the server has ever solved.
+type ShamedClass struct {
+	// Word shown when the games of the class started, see ShameEntry.
+	Pattern    string `json:"pattern"`
+	WordLength int    `json:"word_length"`
+	// Number of games played on the class, all lost.
+	Games int `json:"games"`
+	// Time the last game of the class was lost.
+	LastLost time.Time `json:"last_lost"`
+}
+
+// Classes of words never solved, most played first.
+type HallOfShame struct {
+	Classes []ShamedClass `json:"classes"`
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/hackeracc/WordGuess/api"
+)
+
+// Method to convert the error returned by NewGame to an API error. The message
+// of an invalid length is the one of the engine, which tells a length outside
+// --min_word_length and --max_word_length from one missing in the dictionary.
+func inputErrorToAPI(err error, expectedLen, retries int) *api.Error {
+	switch {
+	case errors.Is(err, ErrInvalidLength):
+		return api.NewError(api.CodeInvalidLength, "%s", err.Error()).
+			WithDetail("length", expectedLen).
+			WithDetail("available_lengths", availableLengths())
+	case errors.Is(err, ErrInvalidRetries):
+		return api.NewError(api.CodeInvalidRetries,
+			"retries must be between 0 and %d", *maxAllowedRetries).
+			WithDetail("retries", retries)
+	}
+	return api.NewError(api.CodeInternal, "unexpected error: %v", err)
+}
+
+// Method to convert the error of a request whose context is done to an API
+// error. Returns nil for the other errors.
+func contextErrorToAPI(err error) *api.Error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return api.NewError(api.CodeTimeout, "the request did not complete before its deadline")
+	case errors.Is(err, context.Canceled):
+		return api.NewError(api.CodeTimeout, "the request was canceled")
+	}
+	return nil
+}
+
+// Method to convert the error returned by Game.CheckUserInput to an API error.
+func guessErrorToAPI(char rune, err error) *api.Error {
+	switch {
+	case errors.Is(err, ErrGameExpired):
+		return api.NewError(api.CodeGameExpired, "%s", err.Error())
+	case errors.Is(err, ErrGameFinished):
+		return api.NewError(api.CodeGameFinished, "%s", err.Error())
+	case errors.Is(err, ErrCharAlreadyUsed):
+		return api.NewError(api.CodeCharacterUsed, "%s", err.Error()).
+			WithDetail("character", string(char))
+	case errors.Is(err, ErrInvalidCharacter):
+		return api.NewError(api.CodeInvalidCharacter, "%s", err.Error()).
+			WithDetail("character", string(char))
+	case errors.Is(err, ErrTurnTimeout):
+		return api.NewError(api.CodeTurnTimeout, "%s", err.Error()).
+			WithDetail("character", string(char))
+	}
+	return api.NewError(api.CodeInternal, "unexpected error: %v", err)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAPIErrorMapping(t *testing.T) {
+	InitGame([]string{"last", "fast"})
+	_, err := NewGame(7, 3)
+	assert.Equal(t, api.CodeInvalidLength, inputErrorToAPI(err, 7, 3).Code)
+	assert.Equal(t, "No words of length 7 in the dictionary", inputErrorToAPI(err, 7, 3).Message)
+	// A length the dictionary has, but outside the allowed lengths.
+	oldMin := *minWordLength
+	*minWordLength = 5
+	_, err = NewGame(4, 3)
+	*minWordLength = oldMin
+	assert.Equal(t, api.CodeInvalidLength, inputErrorToAPI(err, 4, 3).Code)
+	assert.Contains(t, inputErrorToAPI(err, 4, 3).Message, "Word length 4 is not allowed")
+	_, err = NewGame(4, 30)
+	assert.Equal(t, api.CodeInvalidRetries, inputErrorToAPI(err, 4, 30).Code)
+
+	game, _ := NewGame(4, 0)
+	_, err = game.CheckUserInput('1')
+	assert.Equal(t, api.CodeInvalidCharacter, guessErrorToAPI('1', err).Code)
+	game.CheckUserInput('s')
+	_, err = game.CheckUserInput('s')
+	assert.Equal(t, api.CodeCharacterUsed, guessErrorToAPI('s', err).Code)
+	game.CheckUserInput('z')
+	_, err = game.CheckUserInput('y')
+	assert.Equal(t, api.CodeGameFinished, guessErrorToAPI('y', err).Code)
+
+	// A guess given after the end of its turn.
+	game, _ = NewGame(4, 3)
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	_, err = game.CheckUserInputCtx(ctx, 's')
+	cancel()
+	assert.Equal(t, api.CodeTurnTimeout, guessErrorToAPI('s', err).Code)
+
+	// A forfeited game expired.
+	game, _ = NewGame(4, 3)
+	game.forfeit()
+	_, err = game.CheckUserInput('s')
+	assert.True(t, errors.Is(err, ErrGameFinished))
+	assert.Equal(t, api.CodeGameExpired, guessErrorToAPI('s', err).Code)
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	arenaBots = flag.String("arena_bots", "entropy,frequency",
+		"Comma separated bots playing in the arena: \"entropy\" and \"frequency\" "+
+			"are built in, \"plugin:<path>\" loads a Go plugin and \"rpc:<command>\" "+
+			"starts a JSON-RPC subprocess.")
+	arenaAdversaries = flag.String("arena_adversaries", "evil,honest",
+		"Comma separated strategies the bots play against: \"evil\" (the engine), "+
+			"\"honest\" (a random word fixed when the game starts), \"merciful\", "+
+			"\"chaotic\" or \"entropy\" (see --opponent).")
+	arenaGames = flag.Int("arena_games", 1000,
+		"Number of games every bot plays against every adversary.")
+	arenaWorkers = flag.Int("arena_workers", runtime.GOMAXPROCS(0),
+		"Number of games played at the same time.")
+	arenaMoveTimeout = flag.Duration("arena_move_timeout", time.Second,
+		"Time allowed to a bot for every move. A bot which does not move in time "+
+			"forfeits the game.")
+	arenaWordLength = flag.Int("arena_word_length", 0,
+		"Length of the words of the arena games. A random length of the dictionary "+
+			"is picked for every game if 0.")
+	arenaRetries = flag.Int("arena_retries", 6,
+		"Retries allowed in every arena game.")
+	arenaSeed = flag.Int64("arena_seed", 0,
+		"Seed of the random choices of the arena, to replay the same games. The "+
+			"current time is used if 0.")
+)
+
+// Max time a bot subprocess gets to exit once the arena is over.
+const botExitTimeout = 5 * time.Second
+
+// Strategies the bots can play against, by name. They return the options of a
+// new game given a seed for their random choices.
+var arenaAdversaryOptions = map[string]func(seed int64) []GameOption{
+	"evil": func(int64) []GameOption {
+		return nil
+	},
+	"honest": func(seed int64) []GameOption {
+		return []GameOption{withSecretWord(seed)}
+	},
+	"merciful": func(int64) []GameOption {
+		return []GameOption{WithStrategy(Merciful{})}
+	},
+	"entropy": func(int64) []GameOption {
+		return []GameOption{WithStrategy(StrategyEntropy{})}
+	},
+	"chaotic": func(seed int64) []GameOption {
+		return []GameOption{WithStrategy(Chaotic{Rand: rand.New(rand.NewSource(seed))})}
+	},
+}
+
+// Option to make the game honest: a single random word is kept, so the game can
+// no longer change its word to avoid the guesses. See --favor_common_words.
+func withSecretWord(seed int64) GameOption {
+	return func(g *Game) {
+		rng := rand.New(rand.NewSource(seed))
+		if g.packed != nil {
+			words := unpackWords(g.packed, g.ExpectedLength)
+			g.packed = g.packed[pickWord(rng, g.dict, words):][:1]
+			return
+		}
+		g.CurrentSetOfWords = g.CurrentSetOfWords[pickWord(rng, g.dict, g.CurrentSetOfWords):][:1]
+		g.bits = nil
+	}
+}
+
+// Configuration of an arena run.
+type arenaConfig struct {
+	// Games played by every bot against every adversary.
+	Games       int
+	Workers     int
+	MoveTimeout time.Duration
+	// Length of the words, zero for a random length per game.
+	WordLength int
+	Retries    int
+	Seed       int64
+}
+
+// Bot registered in the arena.
+type arenaBot struct {
+	Name    string
+	Guesser Guesser
+}
+
+// Single game of the arena.
+type arenaJob struct {
+	Bot        int
+	Adversary  string
+	WordLength int
+	Seed       int64
+}
+
+// Result of a single game of the arena.
+type arenaResult struct {
+	Bot          int
+	Adversary    string
+	Won          bool
+	WrongGuesses int
+	// Reason the bot forfeited the game, nil if it did not.
+	Fault error
+	Moves int
+	// Total time the bot took to move.
+	MoveTime time.Duration
+}
+
+// Results of a bot in the arena.
+type arenaStanding struct {
+	Bot   string
+	Games int
+	Wins  int
+	// Fraction of the games won, from 0 to 1.
+	WinRate float64
+	// Fraction of the games won against every adversary.
+	WinRates map[string]float64
+	// Average number of incorrect guesses per game.
+	AverageWrongGuesses float64
+	// Games forfeited because of an invalid move, an error or a timeout.
+	Faults      int
+	AverageMove time.Duration
+}
+
+// Method to get the state of a game as shown to the bots.
+func botState(game *Game) api.BotState {
+	snapshot := game.Snapshot(false)
+	state := api.BotState{
+		WordLength:  snapshot.WordLength,
+		MaskedWord:  snapshot.Word,
+		UsedChars:   snapshot.UsedChars,
+		RetriesLeft: snapshot.RetriesLeft,
+	}
+	if state.RetriesLeft < 0 {
+		state.RetriesLeft = 0
+	}
+	return state
+}
+
+// Method to ask a bot for its move. A bot which panics loses the move instead
+// of taking the arena down.
+func guessSafely(ctx context.Context, bot Guesser, state api.BotState) (char rune, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("bot panicked: %v", r)
+		}
+	}()
+	return bot.Guess(ctx, state)
+}
+
+// Method to play a game of the arena till the bot wins, loses or forfeits.
+func playArenaGame(bot Guesser, job arenaJob, config arenaConfig) arenaResult {
+	result := arenaResult{Bot: job.Bot, Adversary: job.Adversary}
+	// The move timeout is the guess timeout of the game, so a move is timed
+	// like a guess of the player.
+	opts := append(arenaAdversaryOptions[job.Adversary](job.Seed),
+		WithRetryPolicy(RetryStrict), WithGuessTimeout(config.MoveTimeout))
+	game, err := NewGame(job.WordLength, config.Retries, opts...)
+	if err != nil {
+		result.Fault = err
+		return result
+	}
+	for game.State == Running {
+		ctx, cancel := game.TurnContext(context.Background())
+		start := time.Now()
+		char, err := guessSafely(ctx, bot, botState(game))
+		result.MoveTime += time.Since(start)
+		result.Moves++
+		if err == nil {
+			var accepted bool
+			accepted, err = game.CheckUserInputCtx(ctx, char)
+			if err == nil && !accepted {
+				result.WrongGuesses++
+			}
+		}
+		cancel()
+		if err != nil {
+			result.Fault = err
+			game.forfeit()
+		}
+	}
+	result.Won = game.State == Won
+	return result
+}
+
+// Method to play all the games of the arena on a pool of workers.
+func runArena(bots []arenaBot, adversaries []string, config arenaConfig) []arenaStanding {
+	// The jobs are drawn up front, so the same seed plays the same games
+	// whatever the number of workers.
+	rng := rand.New(rand.NewSource(config.Seed))
+	lengths := currentDictionary().Lengths()
+	var jobs []arenaJob
+	for bot := range bots {
+		for _, adversary := range adversaries {
+			for i := 0; i < config.Games; i++ {
+				job := arenaJob{Bot: bot, Adversary: adversary,
+					WordLength: config.WordLength, Seed: rng.Int63()}
+				if job.WordLength == 0 {
+					job.WordLength = lengths[rng.Intn(len(lengths))]
+				}
+				jobs = append(jobs, job)
+			}
+		}
+	}
+	jobCh := make(chan arenaJob)
+	resultCh := make(chan arenaResult)
+	var wg sync.WaitGroup
+	for i := 0; i < config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobCh {
+				resultCh <- playArenaGame(bots[job.Bot].Guesser, job, config)
+			}
+		}()
+	}
+	go func() {
+		for _, job := range jobs {
+			jobCh <- job
+		}
+		close(jobCh)
+		wg.Wait()
+		close(resultCh)
+	}()
+	var results []arenaResult
+	for result := range resultCh {
+		if result.Fault != nil {
+			defaultLogger.Infof("Bot %s forfeited a game, error %v", bots[result.Bot].Name,
+				result.Fault)
+		}
+		results = append(results, result)
+	}
+	return rankArenaBots(bots, results)
+}
+
+// Method to rank the bots by win rate, then by average incorrect guesses
+// (fewer first), then by name.
+func rankArenaBots(bots []arenaBot, results []arenaResult) []arenaStanding {
+	standings := make([]arenaStanding, len(bots))
+	moves := make([]int, len(bots))
+	moveTimes := make([]time.Duration, len(bots))
+	wrongGuesses := make([]int, len(bots))
+	games := make([]map[string]int, len(bots))
+	wins := make([]map[string]int, len(bots))
+	for i, bot := range bots {
+		standings[i] = arenaStanding{Bot: bot.Name, WinRates: make(map[string]float64)}
+		games[i], wins[i] = make(map[string]int), make(map[string]int)
+	}
+	for _, r := range results {
+		s := &standings[r.Bot]
+		s.Games++
+		games[r.Bot][r.Adversary]++
+		if r.Won {
+			s.Wins++
+			wins[r.Bot][r.Adversary]++
+		}
+		if r.Fault != nil {
+			s.Faults++
+		}
+		wrongGuesses[r.Bot] += r.WrongGuesses
+		moves[r.Bot] += r.Moves
+		moveTimes[r.Bot] += r.MoveTime
+	}
+	for i := range standings {
+		s := &standings[i]
+		if s.Games > 0 {
+			s.WinRate = float64(s.Wins) / float64(s.Games)
+			s.AverageWrongGuesses = float64(wrongGuesses[i]) / float64(s.Games)
+		}
+		if moves[i] > 0 {
+			s.AverageMove = moveTimes[i] / time.Duration(moves[i])
+		}
+		for adversary, n := range games[i] {
+			s.WinRates[adversary] = float64(wins[i][adversary]) / float64(n)
+		}
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.WinRate != b.WinRate {
+			return a.WinRate > b.WinRate
+		}
+		if a.AverageWrongGuesses != b.AverageWrongGuesses {
+			return a.AverageWrongGuesses < b.AverageWrongGuesses
+		}
+		return a.Bot < b.Bot
+	})
+	return standings
+}
+
+// Method to format the ranking of the arena as a table.
+func formatArenaReport(standings []arenaStanding, adversaries []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-4s %-24s %6s %6s %8s", "Rank", "Bot", "Games", "Wins", "Win rate")
+	for _, adversary := range adversaries {
+		fmt.Fprintf(&b, " %10s", "vs "+adversary)
+	}
+	fmt.Fprintf(&b, " %11s %6s %10s\n", "Avg wrong", "Faults", "Avg move")
+	for i, s := range standings {
+		fmt.Fprintf(&b, "%-4d %-24s %6d %6d %7.1f%%", i+1, s.Bot, s.Games, s.Wins,
+			s.WinRate*100)
+		for _, adversary := range adversaries {
+			fmt.Fprintf(&b, " %9.1f%%", s.WinRates[adversary]*100)
+		}
+		fmt.Fprintf(&b, " %11.2f %6d %10v\n", s.AverageWrongGuesses, s.Faults,
+			s.AverageMove.Round(time.Microsecond))
+	}
+	return b.String()
+}
+
+// Method to split a comma separated flag, ignoring the empty values.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Driver method for the "arena" subcommand, which plays the bots against the
+// adversaries and prints their ranking.
+func StartArena() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	config := arenaConfig{
+		Games:       *arenaGames,
+		Workers:     *arenaWorkers,
+		MoveTimeout: *arenaMoveTimeout,
+		WordLength:  *arenaWordLength,
+		Retries:     *arenaRetries,
+		Seed:        *arenaSeed,
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	adversaries := splitList(*arenaAdversaries)
+	for _, adversary := range adversaries {
+		if arenaAdversaryOptions[adversary] == nil {
+			return configError("unknown adversary %s, expected evil or honest", adversary)
+		}
+	}
+	switch {
+	case config.Games <= 0 || config.Workers <= 0 || config.MoveTimeout <= 0:
+		return configError("--arena_games, --arena_workers and --arena_move_timeout " +
+			"must be positive")
+	case config.WordLength != 0 && !currentDictionary().HasLength(config.WordLength):
+		return configError("no words of length %d in the dictionary", config.WordLength)
+	case !validateNumRetries(config.Retries):
+		return configError("invalid value of --arena_retries %d", config.Retries)
+	case len(adversaries) == 0:
+		return configError("no adversary, please set --arena_adversaries")
+	}
+	var bots []arenaBot
+	defer func() {
+		for _, bot := range bots {
+			if err := bot.Guesser.Close(); err != nil {
+				defaultLogger.Errorf("Unable to stop bot %s, error %v", bot.Name, err)
+			}
+		}
+	}()
+	for _, spec := range splitList(*arenaBots) {
+		guesser, err := newGuesser(spec)
+		if err != nil {
+			return configError("unable to load bot %s: %w", spec, err)
+		}
+		bots = append(bots, arenaBot{Name: spec, Guesser: guesser})
+	}
+	if len(bots) == 0 {
+		return configError("no bot, please set --arena_bots")
+	}
+	fmt.Println("Playing", config.Games, "games per bot and adversary on", config.Workers,
+		"workers (seed", config.Seed, ")")
+	started := time.Now()
+	standings := runArena(bots, adversaries, config)
+	fmt.Print(formatArenaReport(standings, adversaries))
+	fmt.Println("Done in", time.Since(started).Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"io"
+	"os"
+	"os/exec"
+	"plugin"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Bot guessing the words of the arena games. Guess is called concurrently by
+// the workers of the arena, for different games.
+type Guesser interface {
+	// Method to pick the next character to guess. The bot must answer before
+	// the context is done.
+	Guess(ctx context.Context, state api.BotState) (rune, error)
+	// Method to release the resources of the bot once the arena is over.
+	Close() error
+}
+
+// Method to get the candidates consistent with the state of a game.
+func botCandidates(state api.BotState) *Solver {
+	usedChars := []rune(state.UsedChars)
+	pattern, excluded := parseMaskedWord(state.MaskedWord, usedChars)
+	return &Solver{
+		WordLength: state.WordLength,
+		Candidates: currentDictionary().matchPattern(pattern, excluded),
+		Pattern:    pattern,
+		UsedChars:  usedChars,
+	}
+}
+
+// Built in bot guessing the letter which splits the candidates most evenly,
+// like the solver mode.
+type entropyBot struct{}
+
+func (entropyBot) Guess(ctx context.Context, state api.BotState) (rune, error) {
+	char, ok := botCandidates(state).NextGuess()
+	if !ok {
+		return 0, errors.New("no word matches the game")
+	}
+	return char, nil
+}
+
+func (entropyBot) Close() error {
+	return nil
+}
+
+// Built in bot guessing the letter present in the most candidates. Ties go to
+// the smaller letter.
+type frequencyBot struct{}
+
+func (frequencyBot) Guess(ctx context.Context, state api.BotState) (rune, error) {
+	best := botCandidates(state).BestNextGuesses(1)
+	if len(best) == 0 {
+		return 0, errors.New("no word matches the game")
+	}
+	return best[0].Char, nil
+}
+
+func (frequencyBot) Close() error {
+	return nil
+}
+
+// Function a Go plugin bot must export as "Guess". It returns the guessed
+// character.
+type pluginGuessFunc = func(api.BotState) string
+
+// Bot loaded from a Go plugin. The plugin runs in the process of the arena, so
+// a move which does not finish in time is abandoned (and forfeits the game)
+// but keeps running in the background.
+type pluginBot struct {
+	guess pluginGuessFunc
+}
+
+// Method to load a bot from a Go plugin exporting a pluginGuessFunc.
+func loadPluginBot(path string) (*pluginBot, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := p.Lookup("Guess")
+	if err != nil {
+		return nil, err
+	}
+	guess, ok := symbol.(pluginGuessFunc)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s: Guess is a %T, expected a %T", path,
+			symbol, pluginGuessFunc(nil))
+	}
+	return &pluginBot{guess: guess}, nil
+}
+
+func (b *pluginBot) Guess(ctx context.Context, state api.BotState) (rune, error) {
+	type move struct {
+		char string
+		err  error
+	}
+	// Buffered so that an abandoned move does not block forever.
+	done := make(chan move, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- move{err: fmt.Errorf("bot panicked: %v", r)}
+			}
+		}()
+		done <- move{char: b.guess(state)}
+	}()
+	select {
+	case m := <-done:
+		if m.err != nil {
+			return 0, m.err
+		}
+		return parseBotChar(m.char)
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func (b *pluginBot) Close() error {
+	return nil
+}
+
+// Bot running as a subprocess, which gets JSON-RPC requests on its stdin and
+// writes the responses on its stdout, one per line. Requests of concurrent
+// games are pipelined, and a response coming after the move timed out is
+// dropped. The subprocess keeps the memory and the crashes of the bot away
+// from the arena.
+type rpcBot struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	// Guards the fields below and the writes to stdin.
+	mu     sync.Mutex
+	nextID int64
+	// Moves waiting for their response, by request id.
+	pending map[int64]chan api.RPCResponse
+	// Set once the subprocess stopped answering.
+	err error
+	// Closed once all the output of the subprocess was read.
+	readDone chan struct{}
+}
+
+// Method to start a bot subprocess. The command is split on spaces.
+func startRPCBot(command string) (*rpcBot, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("empty bot command")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	b := &rpcBot{
+		cmd:      cmd,
+		stdin:    stdin,
+		pending:  make(map[int64]chan api.RPCResponse),
+		readDone: make(chan struct{}),
+	}
+	go b.readResponses(stdout)
+	return b, nil
+}
+
+// Method to dispatch the responses of the subprocess to the waiting moves.
+func (b *rpcBot) readResponses(stdout io.Reader) {
+	defer close(b.readDone)
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		var resp api.RPCResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			defaultLogger.Errorf("Invalid response from bot %s, error %v", b.cmd.Path, err)
+			continue
+		}
+		b.mu.Lock()
+		if ch, ok := b.pending[resp.ID]; ok {
+			ch <- resp
+			delete(b.pending, resp.ID)
+		}
+		b.mu.Unlock()
+	}
+	err := scanner.Err()
+	if err == nil {
+		err = errors.New("bot exited")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err = err
+	for id, ch := range b.pending {
+		close(ch)
+		delete(b.pending, id)
+	}
+}
+
+func (b *rpcBot) Guess(ctx context.Context, state api.BotState) (rune, error) {
+	b.mu.Lock()
+	if b.err != nil {
+		b.mu.Unlock()
+		return 0, b.err
+	}
+	b.nextID++
+	id := b.nextID
+	ch := make(chan api.RPCResponse, 1)
+	b.pending[id] = ch
+	data, err := json.Marshal(api.RPCRequest{
+		JSONRPC: api.JSONRPCVersion,
+		ID:      id,
+		Method:  api.BotMethodGuess,
+		Params:  state,
+	})
+	if err == nil {
+		_, err = b.stdin.Write(append(data, '\n'))
+	}
+	if err != nil {
+		delete(b.pending, id)
+		b.mu.Unlock()
+		return 0, err
+	}
+	b.mu.Unlock()
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return 0, errors.New("bot exited")
+		}
+		if resp.Error != nil {
+			return 0, fmt.Errorf("bot error %d: %s", resp.Error.Code, resp.Error.Message)
+		}
+		var guess api.BotGuess
+		if err := json.Unmarshal(resp.Result, &guess); err != nil {
+			return 0, fmt.Errorf("invalid guess from bot: %v", err)
+		}
+		return parseBotChar(guess.Char)
+	case <-ctx.Done():
+		b.mu.Lock()
+		delete(b.pending, id)
+		b.mu.Unlock()
+		return 0, ctx.Err()
+	}
+}
+
+// Method to stop the subprocess: its stdin is closed, and it is killed if it
+// does not exit by itself.
+func (b *rpcBot) Close() error {
+	b.stdin.Close()
+	select {
+	case <-b.readDone:
+	case <-time.After(botExitTimeout):
+		b.cmd.Process.Kill()
+		<-b.readDone
+	}
+	return b.cmd.Wait()
+}
+
+// Method to parse the character guessed by a bot.
+func parseBotChar(s string) (rune, error) {
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, fmt.Errorf("invalid guess %q from bot, expected a single character", s)
+	}
+	char, _ := utf8.DecodeRuneInString(s)
+	return char, nil
+}
+
+// Method to create a bot from its spec: "entropy" and "frequency" are built in,
+// "plugin:<path>" loads a Go plugin and "rpc:<command>" starts a subprocess.
+func newGuesser(spec string) (Guesser, error) {
+	switch {
+	case spec == "entropy":
+		return entropyBot{}, nil
+	case spec == "frequency":
+		return frequencyBot{}, nil
+	case strings.HasPrefix(spec, "plugin:"):
+		return loadPluginBot(strings.TrimPrefix(spec, "plugin:"))
+	case strings.HasPrefix(spec, "rpc:"):
+		return startRPCBot(strings.TrimPrefix(spec, "rpc:"))
+	}
+	return nil, fmt.Errorf("unknown bot %q, expected entropy, frequency, plugin:<path> "+
+		"or rpc:<command>", spec)
+}
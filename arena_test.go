@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"testing"
+	"time"
+)
+
+// Bot guessing the letters in a fixed order.
+type scriptedBot struct {
+	order string
+}
+
+func (b scriptedBot) Guess(ctx context.Context, state api.BotState) (rune, error) {
+	for _, char := range b.order {
+		if !contains([]rune(state.UsedChars), char) {
+			return char, nil
+		}
+	}
+	return 0, errors.New("no letter left")
+}
+
+func (scriptedBot) Close() error {
+	return nil
+}
+
+// Bot which never answers in time.
+type slowBot struct{}
+
+func (slowBot) Guess(ctx context.Context, state api.BotState) (rune, error) {
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+func (slowBot) Close() error {
+	return nil
+}
+
+// Bot which panics on every move.
+type panickingBot struct{}
+
+func (panickingBot) Guess(ctx context.Context, state api.BotState) (rune, error) {
+	panic("out of ideas")
+}
+
+func (panickingBot) Close() error {
+	return nil
+}
+
+type ArenaTestSuite struct {
+	suite.Suite
+	config arenaConfig
+}
+
+func (s *ArenaTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats", "list", "lost", "cost"})
+	s.config = arenaConfig{Games: 20, Workers: 4, MoveTimeout: time.Second,
+		WordLength: 4, Retries: 6, Seed: 1}
+}
+
+func (s *ArenaTestSuite) TestRanking() {
+	bots := []arenaBot{
+		{Name: "alphabet", Guesser: scriptedBot{order: "abcdefghijklmnopqrstuvwxyz"}},
+		{Name: "entropy", Guesser: entropyBot{}},
+		{Name: "frequency", Guesser: frequencyBot{}},
+	}
+	standings := runArena(bots, []string{"evil", "honest"}, s.config)
+	assert.Equal(s.T(), 3, len(standings))
+	for i, standing := range standings {
+		assert.Equal(s.T(), 40, standing.Games)
+		assert.Equal(s.T(), 0, standing.Faults)
+		if i > 0 {
+			assert.True(s.T(), standings[i-1].WinRate >= standing.WinRate)
+		}
+	}
+	// The bots reading the dictionary always find the word among 8 with 6
+	// retries.
+	assert.Equal(s.T(), 1.0, standings[0].WinRate)
+	assert.Equal(s.T(), "alphabet", standings[2].Bot)
+	assert.Equal(s.T(), 1.0, standings[0].WinRates["honest"])
+}
+
+func (s *ArenaTestSuite) TestSameSeedSameGames() {
+	bots := []arenaBot{{Name: "alphabet", Guesser: scriptedBot{order: "etaoinshrdlcu"}}}
+	s.config.WordLength = 0
+	first := runArena(bots, []string{"honest"}, s.config)
+	s.config.Workers = 1
+	second := runArena(bots, []string{"honest"}, s.config)
+	// Only the time taken to move can change.
+	first[0].AverageMove, second[0].AverageMove = 0, 0
+	assert.Equal(s.T(), first, second)
+}
+
+func (s *ArenaTestSuite) TestFaults() {
+	s.config.Games = 3
+	s.config.MoveTimeout = 10 * time.Millisecond
+	bots := []arenaBot{
+		{Name: "slow", Guesser: slowBot{}},
+		{Name: "panicking", Guesser: panickingBot{}},
+		{Name: "invalid", Guesser: scriptedBot{order: "1"}},
+	}
+	for _, standing := range runArena(bots, []string{"evil"}, s.config) {
+		assert.Equal(s.T(), 3, standing.Faults, standing.Bot)
+		assert.Equal(s.T(), 0, standing.Wins, standing.Bot)
+	}
+}
+
+func (s *ArenaTestSuite) TestRPCBot() {
+	s.T().Setenv("ARENA_BOT_PROCESS", "1")
+	bot, err := newGuesser("rpc:" + os.Args[0] + " -test.run=^TestArenaBotProcess$")
+	assert.Nil(s.T(), err)
+	standings := runArena([]arenaBot{{Name: "rpc", Guesser: bot}}, []string{"evil"}, s.config)
+	assert.Nil(s.T(), bot.Close())
+	assert.Equal(s.T(), 20, standings[0].Games)
+	assert.Equal(s.T(), 0, standings[0].Faults)
+	assert.Equal(s.T(), 1.0, standings[0].WinRate)
+}
+
+func (s *ArenaTestSuite) TestUnknownBot() {
+	_, err := newGuesser("genius")
+	assert.NotNil(s.T(), err)
+}
+
+func TestArenaTestSuite(t *testing.T) {
+	suite.Run(t, new(ArenaTestSuite))
+}
+
+// Not a test: the bot subprocess started by TestRPCBot, which answers the
+// JSON-RPC requests on stdin with the entropy bot.
+func TestArenaBotProcess(t *testing.T) {
+	if os.Getenv("ARENA_BOT_PROCESS") != "1" {
+		return
+	}
+	InitGame([]string{"last", "fast", "bets", "code", "cats", "list", "lost", "cost"})
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req struct {
+			ID     int64        `json:"id"`
+			Params api.BotState `json:"params"`
+		}
+		json.Unmarshal(scanner.Bytes(), &req)
+		char, _ := entropyBot{}.Guess(context.Background(), req.Params)
+		result, _ := json.Marshal(api.BotGuess{Char: string(char)})
+		resp, _ := json.Marshal(api.RPCResponse{JSONRPC: api.JSONRPCVersion, ID: req.ID,
+			Result: result})
+		fmt.Println(string(resp))
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	showRemaining = flag.Bool("show_remaining", false,
+		"Show the number of words the computer is still choosing from before "+
+			"every guess.")
+	dumpCandidates = flag.Bool("dump_candidates", false,
+		"Debug flag to show all the words the computer is still choosing from "+
+			"before every guess. It gives the word away, e.g. to demonstrate how "+
+			"the computer dodges the guesses.")
+)
+
+// Method to count the words the computer is still choosing from, in both the
+// normal and the compact mode. Unlike Candidates, the compact words are not
+// unpacked.
+func (g *Game) RemainingCandidates() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.packed != nil {
+		return len(g.packed)
+	}
+	return len(g.CurrentSetOfWords)
+}
+
+// Method to format a number with a comma between every group of three digits,
+// e.g. 1,204.
+func groupThousands(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return sign + b.String()
+}
+
+// Method to show the words the computer is still choosing from, as asked by
+// the flags.
+func printCandidates(game *Game) {
+	if *showRemaining {
+		fmt.Println("Possible words remaining:", groupThousands(game.RemainingCandidates()))
+	}
+	if *dumpCandidates {
+		fmt.Println("Candidate words:", strings.Join(game.Candidates(), " "))
+	}
+}
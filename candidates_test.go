@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type CandidatesTestSuite struct {
+	suite.Suite
+}
+
+func (s *CandidatesTestSuite) TearDownTest() {
+	*compactWords = false
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *CandidatesTestSuite) TestRemainingCandidates() {
+	for _, compact := range []bool{false, true} {
+		*compactWords = compact
+		InitGame([]string{"last", "fast", "bets", "code"})
+		game, err := NewGame(4, 5)
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), 4, game.RemainingCandidates())
+		// The computer keeps the three words without a "c".
+		_, err = game.CheckUserInput('c')
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), 3, game.RemainingCandidates())
+		assert.Equal(s.T(), len(game.Candidates()), game.RemainingCandidates())
+	}
+}
+
+func (s *CandidatesTestSuite) TestGroupThousands() {
+	assert.Equal(s.T(), "0", groupThousands(0))
+	assert.Equal(s.T(), "999", groupThousands(999))
+	assert.Equal(s.T(), "1,204", groupThousands(1204))
+	assert.Equal(s.T(), "1,000,000", groupThousands(1000000))
+	assert.Equal(s.T(), "-12,345", groupThousands(-12345))
+}
+
+func TestCandidatesTestSuite(t *testing.T) {
+	suite.Run(t, new(CandidatesTestSuite))
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"math/rand"
+)
+
+var (
+	opponentMaxDodges = flag.Int("opponent_max_dodges", -1,
+		"Max number of guesses per game the opponent rejects while some of its "+
+			"words contain the guessed letter. Once they are used, the opponent "+
+			"sticks to a single word till the end of the game. No limit if negative.")
+)
+
+// Strategy limiting how many times per game another strategy may dodge a guess,
+// i.e. keep the partition rejecting the guessed character while some
+// candidates contain it. Once MaxDodges guesses were dodged, a random
+// candidate is picked and the partition containing it is kept from then on,
+// as if the game had been played honestly with that word. Every game gets its
+// own count of dodges, see WithStrategy, so the same Capped can be shared by
+// all the games of a player like the strategy it wraps.
+type Capped struct {
+	// Strategy choosing the partitions till the dodges are used.
+	Strategy Strategy
+	// Max number of dodges per game, zero to stick to a word from the first
+	// guess.
+	MaxDodges int
+	// Source of the word picked once the dodges are used, the source of the
+	// game if nil (see WithSeed).
+	Rand *rand.Rand
+
+	// State of the game played, nil till the strategy is given to a game.
+	game *cappedGame
+}
+
+// Dodges made in a game, and the word it sticks to once they are used.
+type cappedGame struct {
+	dodges int
+	word   string
+}
+
+// Strategy keeping a state for every game it plays. WithStrategy gives every
+// game its own copy.
+type gameStrategy interface {
+	// Method to get the copy of the strategy for a new game.
+	forGame() Strategy
+}
+
+func (c Capped) Name() string {
+	return c.Strategy.Name()
+}
+
+// Method to pick a partition knowing only the sizes of the partitions, which
+// the wrapped strategy does. The game calls chooseWords instead.
+func (c Capped) Choose(d Decision) int {
+	return c.Strategy.Choose(d)
+}
+
+func (c Capped) chooseWords(d Decision, partitions [][]string, usedChars []rune) int {
+	if c.game == nil {
+		c.game = &cappedGame{}
+	}
+	if c.game.word == "" && c.game.dodges >= c.MaxDodges {
+		var words []string
+		for _, p := range partitions {
+			words = append(words, p...)
+		}
+		c.game.word = words[randomIntn(c.Rand, len(words))]
+	}
+	if c.game.word != "" {
+		for i, words := range partitions {
+			for _, word := range words {
+				if word == c.game.word {
+					return i
+				}
+			}
+		}
+	}
+	var choice int
+	if s, ok := c.Strategy.(wordsStrategy); ok {
+		choice = s.chooseWords(d, partitions, usedChars)
+	} else {
+		choice = c.Strategy.Choose(d)
+	}
+	if d.Partitions[choice].Pattern == d.Before && d.Containing() > 0 {
+		c.game.dodges++
+	}
+	return choice
+}
+
+func (c Capped) forGame() Strategy {
+	c.game = &cappedGame{}
+	return c
+}
+
+func (c Capped) withRand(rng *rand.Rand) Strategy {
+	if c.Rand == nil {
+		c.Rand = rng
+	}
+	if s, ok := c.Strategy.(randomStrategy); ok {
+		c.Strategy = s.withRand(rng)
+	}
+	return c
+}
+
+func (c Capped) observeResult(won bool) {
+	if observer, ok := c.Strategy.(resultObserver); ok {
+		observer.observeResult(won)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type CappedStrategyTestSuite struct {
+	suite.Suite
+}
+
+func (s *CappedStrategyTestSuite) SetupTest() {
+	InitGame([]string{"bat", "cat", "hat", "mat", "dog"})
+}
+
+func (s *CappedStrategyTestSuite) TearDownTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to guess the first letters of the words ending in "at", and get the
+// ones accepted.
+func (s *CappedStrategyTestSuite) guessInitials(game *Game) string {
+	accepted := ""
+	for _, char := range "bchm" {
+		ok, err := game.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+		if ok {
+			accepted += string(char)
+		}
+	}
+	return accepted
+}
+
+func (s *CappedStrategyTestSuite) TestStopsDodging() {
+	// The vindictive opponent dodges every initial.
+	game, err := NewGame(3, 6)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "", s.guessInitials(game))
+
+	capped := Capped{Strategy: Vindictive{}, MaxDodges: 1}
+	for seed := int64(0); seed < 5; seed++ {
+		game, err = NewGame(3, 6, WithStrategy(capped), WithSeed(seed))
+		assert.Nil(s.T(), err)
+		// The only dodge allowed.
+		accepted, err := game.CheckUserInput('d')
+		assert.Nil(s.T(), err)
+		assert.False(s.T(), accepted)
+		assert.Equal(s.T(), "vindictive", game.LastDecision.Strategy)
+		// The opponent sticks to a single word from then on.
+		initials := s.guessInitials(game)
+		assert.Len(s.T(), initials, 1)
+		for _, char := range "at" {
+			game.CheckUserInput(char)
+		}
+		assert.Equal(s.T(), Won, game.State)
+	}
+}
+
+func (s *CappedStrategyTestSuite) TestSameSeedSameWord() {
+	capped := Capped{Strategy: Vindictive{}}
+	var words []string
+	for i := 0; i < 2; i++ {
+		game, err := NewGame(3, 6, WithStrategy(capped), WithSeed(7))
+		assert.Nil(s.T(), err)
+		s.guessInitials(game)
+		words = append(words, string(game.CurrentDisplayedWord))
+	}
+	assert.Equal(s.T(), words[0], words[1])
+}
+
+func (s *CappedStrategyTestSuite) TestGamesCountTheirDodges() {
+	capped := Capped{Strategy: Vindictive{}, MaxDodges: 1}
+	first, err := NewGame(3, 6, WithStrategy(capped))
+	assert.Nil(s.T(), err)
+	second, err := NewGame(3, 6, WithStrategy(capped))
+	assert.Nil(s.T(), err)
+	first.CheckUserInput('d')
+	// The dodge of the first game does not count for the second one.
+	accepted, err := second.CheckUserInput('d')
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), accepted)
+	assert.Equal(s.T(), 4, second.RemainingCandidates())
+}
+
+func (s *CappedStrategyTestSuite) TestWrapsBalanced() {
+	balanced := NewBalanced(0.5)
+	game, err := NewGame(3, 1, WithStrategy(Capped{Strategy: balanced, MaxDodges: 2}))
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "balanced", game.strategy.Name())
+	game.forfeit()
+	assert.Equal(s.T(), 1, balanced.games)
+}
+
+func TestCappedStrategyTestSuite(t *testing.T) {
+	suite.Run(t, new(CappedStrategyTestSuite))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	chaosLatency = flag.Duration("chaos_latency", 0,
+		"Testing only: delay added to every HTTP request and WebSocket message "+
+			"sent by the server.")
+	chaosJitter = flag.Duration("chaos_jitter", 0,
+		"Testing only: max random delay added on top of --chaos_latency.")
+	chaosDropRate = flag.Float64("chaos_drop_rate", 0,
+		"Testing only: fraction (0 to 1) of the WebSocket messages which are "+
+			"silently dropped instead of being sent.")
+	chaosStoreErrorRate = flag.Float64("chaos_store_error_rate", 0,
+		"Testing only: fraction (0 to 1) of the session store operations which "+
+			"fail.")
+	chaosSeed = flag.Int64("chaos_seed", 1,
+		"Testing only: seed of the random faults, to make a chaos run repeatable.")
+)
+
+// Error returned by the session store for the faults injected in chaos mode.
+var errChaos = errors.New("chaos: injected session store failure")
+
+// Faults injected by the server in chaos mode, to check how the clients cope
+// with a slow and unreliable server before a release. A nil config injects no
+// faults.
+type chaosConfig struct {
+	// Guards all the fields, which can be changed while the server is running.
+	mu sync.Mutex
+	// Delay added to every request and WebSocket message.
+	Latency time.Duration
+	// Max random delay added on top of the latency.
+	Jitter time.Duration
+	// Fraction of the WebSocket messages which are dropped.
+	DropRate float64
+	// Fraction of the session store operations which fail.
+	StoreErrorRate float64
+	// Source of the random faults.
+	rand *rand.Rand
+}
+
+func newChaosConfig(seed int64) *chaosConfig {
+	return &chaosConfig{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Method to build the chaos config from the command line flags.
+// Returns nil if no fault is enabled.
+func chaosFromFlags() *chaosConfig {
+	if *chaosLatency <= 0 && *chaosJitter <= 0 && *chaosDropRate <= 0 &&
+		*chaosStoreErrorRate <= 0 {
+		return nil
+	}
+	c := newChaosConfig(*chaosSeed)
+	c.Latency = *chaosLatency
+	c.Jitter = *chaosJitter
+	c.DropRate = *chaosDropRate
+	c.StoreErrorRate = *chaosStoreErrorRate
+	return c
+}
+
+// Method to wait for the configured latency.
+func (c *chaosConfig) delay() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	d := c.Latency
+	if c.Jitter > 0 {
+		d += time.Duration(c.rand.Int63n(int64(c.Jitter) + 1))
+	}
+	c.mu.Unlock()
+	if d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// Method to decide if a WebSocket message should be dropped.
+func (c *chaosConfig) dropMessage() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.DropRate > 0 && c.rand.Float64() < c.DropRate
+}
+
+// Method to decide if a session store operation should fail.
+func (c *chaosConfig) storeFails() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.StoreErrorRate > 0 && c.rand.Float64() < c.StoreErrorRate
+}
+
+// Method to wrap an HTTP handler so every request is delayed.
+func (c *chaosConfig) middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.delay()
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Session store which fails randomly in chaos mode.
+type chaosStore struct {
+	sessionStore
+	chaos *chaosConfig
+}
+
+func (s chaosStore) add(ctx context.Context, sess *session) error {
+	if s.chaos.storeFails() {
+		return errChaos
+	}
+	return s.sessionStore.add(ctx, sess)
+}
+
+func (s chaosStore) get(ctx context.Context, id string) (*session, error) {
+	if s.chaos.storeFails() {
+		return nil, errChaos
+	}
+	return s.sessionStore.get(ctx, id)
+}
+
+func (s chaosStore) save(ctx context.Context, sess *session) error {
+	if s.chaos.storeFails() {
+		return errChaos
+	}
+	return s.sessionStore.save(ctx, sess)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type ChaosTestSuite struct {
+	suite.Suite
+	chaos      *chaosConfig
+	gameServer *gameServer
+	server     *httptest.Server
+}
+
+func (s *ChaosTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *ChaosTestSuite) SetupTest() {
+	s.chaos = newChaosConfig(1)
+	s.gameServer = newGameServer().withChaos(s.chaos)
+	s.server = httptest.NewServer(s.gameServer.Handler())
+}
+
+func (s *ChaosTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// Method to change the faults while the server is running.
+func (s *ChaosTestSuite) setChaos(update func(c *chaosConfig)) {
+	s.chaos.mu.Lock()
+	defer s.chaos.mu.Unlock()
+	update(s.chaos)
+}
+
+func (s *ChaosTestSuite) do(method, path, body string, out interface{}) int {
+	req, _ := http.NewRequest(method, s.server.URL+path, bytes.NewBufferString(body))
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+func (s *ChaosTestSuite) TestLatency() {
+	s.setChaos(func(c *chaosConfig) { c.Latency = 30 * time.Millisecond })
+	start := time.Now()
+	var game api.Game
+	status := s.do("POST", "/games", `{"word_length": 4, "retries": 3}`, &game)
+	assert.Equal(s.T(), http.StatusCreated, status)
+	assert.True(s.T(), time.Since(start) >= 30*time.Millisecond)
+}
+
+func (s *ChaosTestSuite) TestStoreErrors() {
+	var game api.Game
+	status := s.do("POST", "/games", `{"word_length": 4, "retries": 3}`, &game)
+	assert.Equal(s.T(), http.StatusCreated, status)
+
+	s.setChaos(func(c *chaosConfig) { c.StoreErrorRate = 1 })
+	var errResp api.ErrorResponse
+	status = s.do("POST", "/games", `{"word_length": 4, "retries": 3}`, &errResp)
+	assert.Equal(s.T(), http.StatusInternalServerError, status)
+	assert.Equal(s.T(), api.CodeInternal, errResp.Error.Code)
+	status = s.do("POST", "/games/"+game.ID+"/guesses", `{"char": "a"}`, &errResp)
+	assert.Equal(s.T(), http.StatusInternalServerError, status)
+	assert.Equal(s.T(), api.CodeInternal, errResp.Error.Code)
+
+	// The game survives the failures, and the failed guess was not applied.
+	s.setChaos(func(c *chaosConfig) { c.StoreErrorRate = 0 })
+	var fetched api.Game
+	status = s.do("GET", "/games/"+game.ID, "", &fetched)
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.Equal(s.T(), game, fetched)
+}
+
+func (s *ChaosTestSuite) TestSaveErrors() {
+	var game api.Game
+	s.do("POST", "/games", `{"word_length": 4, "retries": 1}`, &game)
+	ctx := context.Background()
+	sess, apiErr := s.gameServer.getSession(ctx, game.ID)
+	s.Require().Nil(apiErr)
+
+	// The guess is undone when it can not be saved, even the one losing the
+	// game.
+	s.setChaos(func(c *chaosConfig) { c.StoreErrorRate = 1 })
+	_, _, apiErr = sess.guess(ctx, "z", "")
+	s.Require().NotNil(apiErr)
+	assert.Equal(s.T(), api.CodeInternal, apiErr.Code)
+	assert.Equal(s.T(), Running, sess.game.State)
+	assert.Empty(s.T(), sess.game.UsedChars)
+	assert.Empty(s.T(), sess.game.Turns)
+	assert.Equal(s.T(), 1, sess.game.CurrentRetries)
+	assert.False(s.T(), sess.ended)
+
+	// So the guess can be made again.
+	s.setChaos(func(c *chaosConfig) { c.StoreErrorRate = 0 })
+	var guess api.GuessResponse
+	status := s.do("POST", "/games/"+game.ID+"/guesses", `{"char": "z"}`, &guess)
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.Equal(s.T(), "z", guess.Game.UsedChars)
+	assert.Equal(s.T(), api.StateLost, guess.Game.State)
+}
+
+func (s *ChaosTestSuite) TestDroppedMessages() {
+	var game api.Game
+	s.do("POST", "/games", `{"word_length": 4, "retries": 3}`, &game)
+	s.setChaos(func(c *chaosConfig) { c.DropRate = 1 })
+	client := dialTestWS(s.T(), s.server.URL, "/games/"+game.ID+"/ws")
+	defer client.conn.Close()
+	s.do("POST", "/games/"+game.ID+"/guesses", `{"char": "a"}`, &api.GuessResponse{})
+
+	// Every state message has the full state of the game, so the client
+	// catches up with the first message it gets after the drops stop.
+	s.setChaos(func(c *chaosConfig) { c.DropRate = 0 })
+	s.do("POST", "/games/"+game.ID+"/guesses", `{"char": "s"}`, &api.GuessResponse{})
+	for {
+		msg := client.read(s.T())
+		assert.Equal(s.T(), api.MessageState, msg.Type)
+		if msg.Game.UsedChars == "as" {
+			break
+		}
+	}
+
+	// A client which reconnects gets the current state right away.
+	reconnected := dialTestWS(s.T(), s.server.URL, "/games/"+game.ID+"/ws")
+	defer reconnected.conn.Close()
+	msg := reconnected.read(s.T())
+	assert.Equal(s.T(), api.MessageState, msg.Type)
+	assert.Equal(s.T(), "as", msg.Game.UsedChars)
+	assert.Equal(s.T(), 1, msg.Game.RetriesLeft)
+}
+
+func TestChaosTestSuite(t *testing.T) {
+	suite.Run(t, new(ChaosTestSuite))
+}
@@ -0,0 +1,144 @@
+//go:build !js
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Exit codes of the command line program.
+const (
+	// The program failed while running, e.g. a file could not be written or a
+	// server could not be reached.
+	exitFailure = 1
+	// A subcommand was given the wrong arguments, see ErrUsage.
+	exitUsage = 2
+	// The flags or the files they name are not valid, see ErrInvalidConfig.
+	exitConfig = 3
+	// The player quit with Ctrl+C, or SIGINT or SIGTERM was received. These
+	// are the codes of the shells for the signals (128 + the signal number).
+	exitInterrupted = 130
+	exitTerminated  = 143
+)
+
+// Method to get the exit code of the program for an error.
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, ErrEndOfInput):
+		return 0
+	case errors.Is(err, ErrInterrupted):
+		return exitInterrupted
+	case errors.Is(err, ErrTerminated):
+		return exitTerminated
+	case errors.Is(err, ErrUsage):
+		return exitUsage
+	case errors.Is(err, ErrInvalidConfig):
+		return exitConfig
+	}
+	return exitFailure
+}
+
+// Entry point of the command line program. The WebAssembly build has its own,
+// see wasm_js.go. Only the command line program exits the process: the rest of
+// the code returns errors, so that the engine can be embedded.
+func main() {
+	flag.Parse()
+	err := run()
+	// The terminal may have been switched to key mode while reading input.
+	restoreTerminal()
+	// The player stopping is not an error, its message was shown already.
+	if err != nil && !isInputEnd(err) {
+		fmt.Println("Error:", err)
+	}
+	if code := exitCode(err); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// Method to set up the program from the flags and run the subcommand, or the
+// game mode given by the flags.
+func run() error {
+	for _, setup := range []func() error{setupKidsMode, setupLanguage, setupFeatures,
+		setupLogging, setupDisplayFormat, setupColors, setupKeyboard} {
+		if err := setup(); err != nil {
+			return err
+		}
+	}
+	setupScreenReader()
+	if err := setupSound(); err != nil {
+		return err
+	}
+	setupRandom()
+	startMetricsServer()
+	switch flag.Arg(0) {
+	case "":
+	case "solve":
+		return StartSolver()
+	case "helper":
+		return StartHelper()
+	case "about":
+		return StartAbout()
+	case "dict":
+		return StartDictTool(flag.Args()[1:])
+	case "watch":
+		return StartWatch(flag.Args()[1:])
+	case "leaderboard":
+		return StartLeaderboard()
+	case "shame":
+		return StartHallOfShame()
+	case "telemetry":
+		return StartTelemetry(flag.Args()[1:])
+	case "arena":
+		return StartArena()
+	case "replay":
+		return StartReplay(flag.Args()[1:])
+	case "graph":
+		return StartGraph(flag.Args()[1:])
+	case "tournament":
+		return StartTournament()
+	case "versus":
+		return StartVersus()
+	case "crossword":
+		return StartCrossword()
+	case "anagram":
+		return StartAnagram()
+	case "ladder":
+		return StartLadder(flag.Args()[1:])
+	case "history":
+		return StartHistory(flag.Args()[1:])
+	case "profiles":
+		return StartProfiles(flag.Args()[1:])
+	case "openapi":
+		return StartOpenAPI()
+	default:
+		return fmt.Errorf("%w: unknown command %s", ErrUsage, flag.Arg(0))
+	}
+	if *httpAddr != "" {
+		// Lets the word list be updated without restarting the server.
+		defer reloadDictionaryOnSignal()()
+		if err := StartServer(); err != nil {
+			return fmt.Errorf("server stopped: %w", err)
+		}
+		return nil
+	}
+	if *dailyMode {
+		return StartDaily()
+	}
+	if *relayPlayers != "" {
+		return StartRelay()
+	}
+	if *boardCount > 1 {
+		return StartMulti()
+	}
+	jsonOutput, err := jsonOutputFromFlags()
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return StartJSONGame()
+	}
+	return StartHangman()
+}
@@ -0,0 +1,63 @@
+//go:build !js
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"testing"
+)
+
+type CLITestSuite struct {
+	suite.Suite
+}
+
+func (s *CLITestSuite) TestExitCode() {
+	assert.Equal(s.T(), 0, exitCode(nil))
+	assert.Equal(s.T(), exitFailure, exitCode(errors.New("unable to write")))
+	assert.Equal(s.T(), exitUsage, exitCode(usageError("hangman replay <replay file>")))
+	assert.Equal(s.T(), exitConfig, exitCode(configError("invalid --retry_policy")))
+	// The errors keep their kind once wrapped by the callers.
+	err := fmt.Errorf("server stopped: %w", configError("invalid TLS settings: %w",
+		errors.New("no certificate")))
+	assert.Equal(s.T(), exitConfig, exitCode(err))
+	assert.EqualError(s.T(), err, "server stopped: invalid configuration: invalid TLS "+
+		"settings: no certificate")
+	// Stopping to play is not a failure, the signals get the codes of the shells.
+	assert.Equal(s.T(), 0, exitCode(ErrEndOfInput))
+	assert.Equal(s.T(), 130, exitCode(fmt.Errorf("daily puzzle: %w", ErrInterrupted)))
+	assert.Equal(s.T(), 143, exitCode(ErrTerminated))
+	assert.True(s.T(), isInputEnd(fmt.Errorf("daily puzzle: %w", ErrEndOfInput)))
+	assert.False(s.T(), isInputEnd(usageError("hangman replay <replay file>")))
+}
+
+func (s *CLITestSuite) TestInitGameError() {
+	s.Require().NoError(InitGame([]string{"last", "fast"}))
+	oldFile := *dictionaryFile
+	*dictionaryFile = filepath.Join(s.T().TempDir(), "missing.txt")
+	defer func() { *dictionaryFile = oldFile }()
+
+	err := InitGame(nil)
+	assert.True(s.T(), errors.Is(err, ErrInvalidConfig))
+	// The dictionary is kept.
+	assert.Equal(s.T(), 2, currentDictionary().WordCount())
+}
+
+func (s *CLITestSuite) TestHelpersReturnErrors() {
+	oldPolicy, oldOutput := *retryPolicyName, *outputFormat
+	defer func() { *retryPolicyName, *outputFormat = oldPolicy, oldOutput }()
+	*retryPolicyName = "forgiving"
+	_, err := retryPolicyFromFlags()
+	assert.True(s.T(), errors.Is(err, ErrInvalidConfig))
+	*outputFormat = "xml"
+	_, err = jsonOutputFromFlags()
+	assert.True(s.T(), errors.Is(err, ErrInvalidConfig))
+	assert.True(s.T(), errors.Is(StartReplay(nil), ErrUsage))
+}
+
+func TestCLITestSuite(t *testing.T) {
+	suite.Run(t, new(CLITestSuite))
+}
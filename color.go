@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	noColor = flag.Bool("no_color", false,
+		"Do not color the output. Colors are also off when the NO_COLOR "+
+			"environment variable is set or stdout is not a terminal.")
+	colorTheme = flag.String("color_theme", "",
+		"Colors of the output, as comma separated element=code pairs overriding "+
+			"the default ones, where the elements are correct, wrong, used and "+
+			"revealed and the codes are ANSI SGR parameters, e.g. "+
+			"\"correct=34,revealed=1;35\". An empty code leaves an element uncolored.")
+)
+
+// Colors of the terminal output, as ANSI SGR parameters (e.g. "32" for green,
+// "1;33" for bold yellow). An empty parameter leaves the element uncolored.
+// The methods can be called on a nil theme, which colors nothing.
+type ColorTheme struct {
+	// Messages of the correct guesses and of a won game.
+	Correct string
+	// Messages of the wrong guesses and of a lost game.
+	Wrong string
+	// Characters guessed so far.
+	Used string
+	// Positions of the word revealed by the last guess.
+	Revealed string
+}
+
+// Colors used when no theme is given.
+var DefaultColorTheme = ColorTheme{Correct: "32", Wrong: "31", Used: "2", Revealed: "1;33"}
+
+// Theme of the terminal, set from the flags when the program starts. Nil if
+// the output is not colored.
+var colors *ColorTheme
+
+// Method to color a text with the given SGR parameters.
+func (t *ColorTheme) paint(code, text string) string {
+	if t == nil || code == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+// Method to color a message of a correct guess.
+func (t *ColorTheme) correct(text string) string {
+	if t == nil {
+		return text
+	}
+	return t.paint(t.Correct, text)
+}
+
+// Method to color a message of a wrong guess.
+func (t *ColorTheme) wrong(text string) string {
+	if t == nil {
+		return text
+	}
+	return t.paint(t.Wrong, text)
+}
+
+// Method to color the characters guessed so far.
+func (t *ColorTheme) used(text string) string {
+	if t == nil {
+		return text
+	}
+	return t.paint(t.Used, text)
+}
+
+// Method to display a pattern of the engine with the format, highlighting the
+// positions which were not revealed yet in the previous pattern. Nothing is
+// highlighted if previous is nil.
+func (t *ColorTheme) formatWord(f PatternFormat, pattern, previous []rune) string {
+	if t == nil || t.Revealed == "" || previous == nil {
+		return f.Format(pattern)
+	}
+	var b strings.Builder
+	for i, r := range pattern {
+		if i > 0 && f.Spacing > 0 {
+			b.WriteString(strings.Repeat(" ", f.Spacing))
+		}
+		char := f.Format([]rune{r})
+		if r != emptyChar && i < len(previous) && previous[i] == emptyChar {
+			char = t.paint(t.Revealed, char)
+		}
+		b.WriteString(char)
+	}
+	return b.String()
+}
+
+// Method to parse a theme given as comma separated element=code pairs, which
+// override the elements of the default theme.
+func parseColorTheme(spec string) (ColorTheme, error) {
+	theme := DefaultColorTheme
+	if strings.TrimSpace(spec) == "" {
+		return theme, nil
+	}
+	elements := map[string]*string{
+		"correct":  &theme.Correct,
+		"wrong":    &theme.Wrong,
+		"used":     &theme.Used,
+		"revealed": &theme.Revealed,
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		name, code, found := strings.Cut(strings.TrimSpace(pair), "=")
+		element, ok := elements[name]
+		if !found || !ok {
+			return theme, fmt.Errorf("invalid color %q, expected correct, wrong, used "+
+				"or revealed followed by =<code>", pair)
+		}
+		if strings.Trim(code, "0123456789;") != "" {
+			return theme, fmt.Errorf("invalid code %q of color %s, expected ANSI SGR "+
+				"parameters, e.g. 32 or 1;33", code, name)
+		}
+		*element = code
+	}
+	return theme, nil
+}
+
+// Method to set the colors of the terminal from the flags. Returns an error if
+// the theme is invalid.
+func setupColors() error {
+	theme, err := parseColorTheme(*colorTheme)
+	if err != nil {
+		return configError("invalid --color_theme: %w", err)
+	}
+	if *noColor || os.Getenv("NO_COLOR") != "" || !isTerminal(os.Stdout) {
+		colors = nil
+		return nil
+	}
+	colors = &theme
+	return nil
+}
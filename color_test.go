@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ColorTestSuite struct {
+	suite.Suite
+}
+
+func (s *ColorTestSuite) TestNoColors() {
+	var theme *ColorTheme
+	assert.Equal(s.T(), "right", theme.correct("right"))
+	assert.Equal(s.T(), "wrong", theme.wrong("wrong"))
+	assert.Equal(s.T(), "ab", theme.used("ab"))
+	assert.Equal(s.T(), "c_t", theme.formatWord(DefaultPatternFormat, rawPattern("c_t"),
+		rawPattern("___")))
+}
+
+func (s *ColorTestSuite) TestPaint() {
+	theme := DefaultColorTheme
+	assert.Equal(s.T(), "\x1b[32mright\x1b[0m", theme.correct("right"))
+	assert.Equal(s.T(), "\x1b[31mwrong\x1b[0m", theme.wrong("wrong"))
+	assert.Equal(s.T(), "\x1b[2mab\x1b[0m", theme.used("ab"))
+	assert.Equal(s.T(), "", theme.used(""))
+	theme.Used = ""
+	assert.Equal(s.T(), "ab", theme.used("ab"))
+}
+
+func (s *ColorTestSuite) TestFormatWord() {
+	theme := DefaultColorTheme
+	format := PatternFormat{Blank: '-', Spacing: 1, Case: CaseUpper}
+	assert.Equal(s.T(), "C \x1b[1;33mA\x1b[0m - \x1b[1;33mA\x1b[0m",
+		theme.formatWord(format, rawPattern("ca_a"), rawPattern("c___")))
+	// Nothing is highlighted before the first guess.
+	assert.Equal(s.T(), "C - - -", theme.formatWord(format, rawPattern("c___"), nil))
+}
+
+func (s *ColorTestSuite) TestParseTheme() {
+	theme, err := parseColorTheme("")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), DefaultColorTheme, theme)
+
+	theme, err = parseColorTheme("correct=34, revealed=1;35,used=")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), ColorTheme{Correct: "34", Wrong: "31", Used: "", Revealed: "1;35"}, theme)
+
+	_, err = parseColorTheme("right=32")
+	assert.NotNil(s.T(), err)
+	_, err = parseColorTheme("correct")
+	assert.NotNil(s.T(), err)
+	_, err = parseColorTheme("correct=green")
+	assert.NotNil(s.T(), err)
+}
+
+func TestColorTestSuite(t *testing.T) {
+	suite.Run(t, new(ColorTestSuite))
+}
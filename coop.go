@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"github.com/hackeracc/WordGuess/api"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// Number of characters of a join code.
+	joinCodeLength = 6
+	// Characters of the join codes, without the ones easily mistaken for each
+	// other (0 and O, 1 and I), as the codes are often read out loud.
+	joinCodeChars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// Player of a cooperative game.
+type coopPlayer struct {
+	Name string `json:"name"`
+	// Secret token sent with the guesses of the player.
+	Token string `json:"token"`
+}
+
+// Players of a cooperative game, who take turns guessing. Saved with the
+// session, so that the turns are enforced by every server of a shared store.
+type coopSeats struct {
+	// Code the partner joins the game with.
+	Code string `json:"code"`
+	// True if the game is listed in the lobby.
+	Listed bool `json:"listed,omitempty"`
+	// Players of the game, the host first.
+	Players []coopPlayer `json:"players"`
+	// Index of the player whose turn it is.
+	Turn int `json:"turn"`
+}
+
+// Method to get a copy of the seats, which can be changed without changing
+// these ones.
+func (c *coopSeats) clone() *coopSeats {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.Players = append([]coopPlayer(nil), c.Players...)
+	return &clone
+}
+
+// Method to check if the partner of the host has joined.
+func (c *coopSeats) full() bool {
+	return len(c.Players) == 2
+}
+
+// Method to check that it is the turn of the player with the given token.
+func (c *coopSeats) checkTurn(token string) *api.Error {
+	player := -1
+	for i, p := range c.Players {
+		if subtle.ConstantTimeCompare([]byte(p.Token), []byte(token)) == 1 {
+			player = i
+		}
+	}
+	switch {
+	case player < 0:
+		return api.NewError(api.CodeForbidden,
+			"the guesses of a cooperative game need the token of one of its players")
+	case !c.full():
+		return api.NewError(api.CodeNotYourTurn, "waiting for a partner to join the game")
+	case player != c.Turn:
+		return api.NewError(api.CodeNotYourTurn, "it is the turn of %s",
+			c.Players[c.Turn].Name).WithDetail("turn", c.Players[c.Turn].Name)
+	}
+	return nil
+}
+
+// Method to give the turn to the other player.
+func (c *coopSeats) nextTurn() {
+	c.Turn = (c.Turn + 1) % len(c.Players)
+}
+
+// Method to build the public view of the seats, without the tokens.
+func (c *coopSeats) view() *api.Coop {
+	view := &api.Coop{}
+	for _, p := range c.Players {
+		view.Players = append(view.Players, p.Name)
+	}
+	if c.full() {
+		view.Turn = c.Players[c.Turn].Name
+	} else {
+		view.JoinCode = c.Code
+	}
+	return view
+}
+
+// Join codes of the cooperative games waiting for a partner, and the games
+// listed in the lobby. Like the Slack channels, the codes are only known to
+// the server which created their games.
+type coopLobby struct {
+	mu sync.Mutex
+	// Id of the session of the game of every code.
+	codes map[string]string
+}
+
+func newCoopLobby() *coopLobby {
+	return &coopLobby{codes: make(map[string]string)}
+}
+
+// Method to generate a random join code.
+func newJoinCode() string {
+	var b [joinCodeLength]byte
+	rand.Read(b[:])
+	for i := range b {
+		b[i] = joinCodeChars[int(b[i])%len(joinCodeChars)]
+	}
+	return string(b[:])
+}
+
+// Method to generate a random secret token for a player.
+func newPlayerToken() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Method to register a new cooperative game and get its join code.
+func (l *coopLobby) add(id string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for {
+		code := newJoinCode()
+		if _, ok := l.codes[code]; !ok {
+			l.codes[code] = id
+			return code
+		}
+	}
+}
+
+// Method to get the id of the game of a join code.
+func (l *coopLobby) get(code string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id, ok := l.codes[code]
+	return id, ok
+}
+
+// Method to forget a join code, once its game is full or gone.
+func (l *coopLobby) remove(code string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.codes, code)
+}
+
+// Method to get the ids of the games waiting for a partner.
+func (l *coopLobby) ids() map[string]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ids := make(map[string]string, len(l.codes))
+	for code, id := range l.codes {
+		ids[code] = id
+	}
+	return ids
+}
+
+// Method to create a cooperative game with its host.
+// Returns the seat of the host.
+func (s *gameServer) addCoopSession(ctx context.Context, game *Game,
+	req api.CreateGameRequest) (api.CoopSeat, *api.Error) {
+	host := coopPlayer{Name: req.Player, Token: newPlayerToken()}
+	sess, apiErr := s.addSession(ctx, game, req, 0, &coopSeats{Listed: req.Lobby,
+		Players: []coopPlayer{host}})
+	if apiErr != nil {
+		return api.CoopSeat{}, apiErr
+	}
+	return api.CoopSeat{Game: sess.view(), PlayerToken: host.Token}, nil
+}
+
+// Method to validate the request to create a cooperative game.
+func validateCoopRequest(req api.CreateGameRequest) *api.Error {
+	if req.Lobby && !req.Coop {
+		return api.NewError(api.CodeInvalidRequest, "only cooperative games are listed in the lobby")
+	}
+	if req.Coop && strings.TrimSpace(req.Player) == "" {
+		return api.NewError(api.CodeInvalidRequest, "a cooperative game needs the name of its host")
+	}
+	if req.Coop && (req.LifetimeSeconds != 0 || req.Challenge) {
+		return api.NewError(api.CodeInvalidRequest,
+			"a cooperative game can not be a correspondence or challenge game")
+	}
+	return nil
+}
+
+func (s *gameServer) handleJoin(w http.ResponseWriter, r *http.Request) {
+	var req api.JoinRequest
+	if apiErr := decodeRequest(w, r, &req); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	seat, apiErr := s.join(r.Context(), req)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, seat)
+}
+
+// Method to seat a partner in the cooperative game of a join code. The game
+// starts with the turn of the host.
+func (s *gameServer) join(ctx context.Context, req api.JoinRequest) (api.CoopSeat,
+	*api.Error) {
+	if strings.TrimSpace(req.Player) == "" {
+		return api.CoopSeat{}, api.NewError(api.CodeInvalidRequest,
+			"the name of the player joining is required")
+	}
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	id, ok := s.coop.get(code)
+	if !ok {
+		return api.CoopSeat{}, api.NewError(api.CodeGameNotFound,
+			"no cooperative game with join code %q", req.Code).WithDetail("code", req.Code)
+	}
+	sess, apiErr := s.getSession(ctx, id)
+	if apiErr != nil {
+		if apiErr.Code == api.CodeGameNotFound {
+			s.coop.remove(code)
+		}
+		return api.CoopSeat{}, apiErr
+	}
+	partner := coopPlayer{Name: req.Player, Token: newPlayerToken()}
+	view, apiErr := sess.join(ctx, partner)
+	if apiErr != nil {
+		return api.CoopSeat{}, apiErr
+	}
+	s.coop.remove(code)
+	return api.CoopSeat{Game: view, PlayerToken: partner.Token}, nil
+}
+
+// Method to seat the partner of the host, and send the new state to the
+// watchers. The partner is not seated if the context is done before the game
+// is saved.
+func (sess *session) join(ctx context.Context, partner coopPlayer) (api.Game, *api.Error) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	for attempt := 1; ; attempt++ {
+		if sess.coop == nil || sess.coop.full() {
+			return api.Game{}, api.NewError(api.CodeGameFull,
+				"the game already has its two players").WithDetail("id", sess.id)
+		}
+		if sess.game.State != Running {
+			return api.Game{}, api.NewError(api.CodeGameFinished,
+				"the game is finished").WithDetail("id", sess.id)
+		}
+		if partner.Name == sess.coop.Players[0].Name {
+			return api.Game{}, api.NewError(api.CodeInvalidRequest,
+				"the partner must not have the name of the host").
+				WithDetail("player", partner.Name)
+		}
+		sess.coop.Players = append(sess.coop.Players, partner)
+		err := sess.saveLocked(ctx)
+		if err == nil {
+			break
+		}
+		// Another partner joined on another server, or a guess was made.
+		if err == errSessionConflict && attempt < maxSaveAttempts {
+			continue
+		}
+		sess.coop.Players = sess.coop.Players[:1]
+		if apiErr := contextErrorToAPI(err); apiErr != nil {
+			return api.Game{}, apiErr.WithDetail("id", sess.id)
+		}
+		defaultLogger.Errorf("Unable to save game %s, error %v", sess.id, err)
+		return api.Game{}, api.NewError(api.CodeInternal, "unable to save the game").
+			WithDetail("id", sess.id)
+	}
+	view := sess.viewLocked()
+	sess.broadcastLocked(api.Message{Type: api.MessageState, Game: &view})
+	return view, nil
+}
+
+func (s *gameServer) handleLobby(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.lobby(r.Context()))
+}
+
+// Method to list the cooperative games of the lobby which are waiting for a
+// partner. The codes of the games which are gone are forgotten.
+func (s *gameServer) lobby(ctx context.Context) api.Lobby {
+	lobby := api.Lobby{Games: []api.LobbyGame{}}
+	for code, id := range s.coop.ids() {
+		sess, err := s.store.get(ctx, id)
+		if err == errSessionNotFound {
+			s.coop.remove(code)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		sess.mu.Lock()
+		if sess.coop != nil && sess.coop.Listed && !sess.coop.full() &&
+			sess.game.State == Running {
+			lobby.Games = append(lobby.Games, api.LobbyGame{
+				JoinCode:   code,
+				Host:       sess.coop.Players[0].Name,
+				WordLength: sess.game.ExpectedLength,
+				Retries:    sess.game.AllowedRetries,
+				Created:    sess.created,
+			})
+		}
+		sess.mu.Unlock()
+	}
+	sort.Slice(lobby.Games, func(i, j int) bool {
+		return lobby.Games[i].Created.Before(lobby.Games[j].Created)
+	})
+	return lobby
+}
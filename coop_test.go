@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type CoopTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+func (s *CoopTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *CoopTestSuite) SetupTest() {
+	s.server = httptest.NewServer(newGameServer().Handler())
+}
+
+func (s *CoopTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *CoopTestSuite) post(path string, body interface{}, out interface{}) int {
+	data, _ := json.Marshal(body)
+	resp, err := http.Post(s.server.URL+path, "application/json", bytes.NewReader(data))
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+// Method to create a cooperative game and get the seat of its host.
+func (s *CoopTestSuite) create(lobby bool) api.CoopSeat {
+	var seat api.CoopSeat
+	status := s.post("/games", api.CreateGameRequest{WordLength: 4, Retries: 3,
+		Player: "ann", Coop: true, Lobby: lobby}, &seat)
+	assert.Equal(s.T(), http.StatusCreated, status)
+	return seat
+}
+
+// Method to guess a character as the player of a token.
+func (s *CoopTestSuite) guess(id, char, token string) (api.GuessResponse, *api.Error) {
+	var resp struct {
+		api.GuessResponse
+		Error *api.Error `json:"error"`
+	}
+	s.post("/games/"+id+"/guesses", api.GuessRequest{Char: char, PlayerToken: token}, &resp)
+	return resp.GuessResponse, resp.Error
+}
+
+func (s *CoopTestSuite) TestTurns() {
+	host := s.create(false)
+	assert.NotEmpty(s.T(), host.PlayerToken)
+	assert.Equal(s.T(), []string{"ann"}, host.Game.Coop.Players)
+	assert.Len(s.T(), host.Game.Coop.JoinCode, joinCodeLength)
+	code := host.Game.Coop.JoinCode
+
+	// No guess till the partner joins.
+	_, apiErr := s.guess(host.Game.ID, "e", host.PlayerToken)
+	assert.Equal(s.T(), api.CodeNotYourTurn, apiErr.Code)
+
+	var partner api.CoopSeat
+	status := s.post("/coop/join", api.JoinRequest{Code: strings.ToLower(code),
+		Player: "bob"}, &partner)
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.NotEqual(s.T(), host.PlayerToken, partner.PlayerToken)
+	assert.Equal(s.T(), &api.Coop{Players: []string{"ann", "bob"}, Turn: "ann"},
+		partner.Game.Coop)
+
+	// The players alternate, and share the retries.
+	resp, apiErr := s.guess(host.Game.ID, "z", host.PlayerToken)
+	assert.Nil(s.T(), apiErr)
+	assert.Equal(s.T(), 2, resp.Game.RetriesLeft)
+	assert.Equal(s.T(), "bob", resp.Game.Coop.Turn)
+	_, apiErr = s.guess(host.Game.ID, "y", host.PlayerToken)
+	assert.Equal(s.T(), api.CodeNotYourTurn, apiErr.Code)
+	assert.Equal(s.T(), "bob", apiErr.Details["turn"])
+	resp, apiErr = s.guess(host.Game.ID, "y", partner.PlayerToken)
+	assert.Nil(s.T(), apiErr)
+	assert.Equal(s.T(), 1, resp.Game.RetriesLeft)
+	assert.Equal(s.T(), "ann", resp.Game.Coop.Turn)
+
+	// A used character does not pass the turn.
+	_, apiErr = s.guess(host.Game.ID, "y", host.PlayerToken)
+	assert.Equal(s.T(), api.CodeCharacterUsed, apiErr.Code)
+	_, apiErr = s.guess(host.Game.ID, "x", "")
+	assert.Equal(s.T(), api.CodeForbidden, apiErr.Code)
+	// The third wrong guess of the team uses the last retry.
+	resp, apiErr = s.guess(host.Game.ID, "x", host.PlayerToken)
+	assert.Nil(s.T(), apiErr)
+	assert.Equal(s.T(), api.StateLost, resp.Game.State)
+
+	// The code is gone once the game is full.
+	var errResp api.ErrorResponse
+	status = s.post("/coop/join", api.JoinRequest{Code: code, Player: "cid"}, &errResp)
+	assert.Equal(s.T(), http.StatusNotFound, status)
+}
+
+func (s *CoopTestSuite) TestLobby() {
+	listed := s.create(true)
+	s.create(false)
+	var lobby api.Lobby
+	resp, err := http.Get(s.server.URL + "/coop/lobby")
+	assert.Nil(s.T(), err)
+	json.NewDecoder(resp.Body).Decode(&lobby)
+	resp.Body.Close()
+	assert.Len(s.T(), lobby.Games, 1)
+	assert.Equal(s.T(), listed.Game.Coop.JoinCode, lobby.Games[0].JoinCode)
+	assert.Equal(s.T(), "ann", lobby.Games[0].Host)
+	assert.Equal(s.T(), 4, lobby.Games[0].WordLength)
+
+	var partner api.CoopSeat
+	s.post("/coop/join", api.JoinRequest{Code: lobby.Games[0].JoinCode, Player: "bob"},
+		&partner)
+	resp, err = http.Get(s.server.URL + "/coop/lobby")
+	assert.Nil(s.T(), err)
+	json.NewDecoder(resp.Body).Decode(&lobby)
+	resp.Body.Close()
+	assert.Empty(s.T(), lobby.Games)
+}
+
+func (s *CoopTestSuite) TestInvalidRequests() {
+	var errResp api.ErrorResponse
+	status := s.post("/games", api.CreateGameRequest{WordLength: 4, Retries: 3,
+		Coop: true}, &errResp)
+	assert.Equal(s.T(), http.StatusBadRequest, status)
+	status = s.post("/games", api.CreateGameRequest{WordLength: 4, Retries: 3,
+		Player: "ann", Lobby: true}, &errResp)
+	assert.Equal(s.T(), http.StatusBadRequest, status)
+
+	host := s.create(false)
+	status = s.post("/coop/join", api.JoinRequest{Code: host.Game.Coop.JoinCode,
+		Player: "ann"}, &errResp)
+	assert.Equal(s.T(), http.StatusBadRequest, status)
+	status = s.post("/coop/join", api.JoinRequest{Code: "AAAAAA", Player: "bob"}, &errResp)
+	assert.Equal(s.T(), http.StatusNotFound, status)
+	assert.Equal(s.T(), api.CodeGameNotFound, errResp.Error.Code)
+}
+
+func (s *CoopTestSuite) TestWebsocket() {
+	host := s.create(false)
+	var partner api.CoopSeat
+	s.post("/coop/join", api.JoinRequest{Code: host.Game.Coop.JoinCode, Player: "bob"},
+		&partner)
+	client := dialTestWS(s.T(), s.server.URL,
+		"/games/"+host.Game.ID+"/ws?player_token="+partner.PlayerToken)
+	defer client.conn.Close()
+	client.read(s.T())
+	client.send(api.Message{Type: api.MessageGuess, Char: "e"})
+	msg := client.read(s.T())
+	assert.Equal(s.T(), api.MessageError, msg.Type)
+	assert.Equal(s.T(), api.CodeNotYourTurn, msg.Error.Code)
+
+	s.guess(host.Game.ID, "e", host.PlayerToken)
+	assert.Equal(s.T(), "bob", client.read(s.T()).Game.Coop.Turn)
+	client.send(api.Message{Type: api.MessageGuess, Char: "a"})
+	msg = client.read(s.T())
+	assert.Equal(s.T(), api.MessageState, msg.Type)
+	assert.Equal(s.T(), "ann", msg.Game.Coop.Turn)
+}
+
+func (s *CoopTestSuite) TestSavedSeats() {
+	seats := &coopSeats{Code: "ABCDEF", Players: []coopPlayer{{Name: "ann", Token: "t"}}}
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	sess := &session{id: "id", game: game, coop: seats}
+	rec := redisRecordLocked(sess)
+	seats.Players = append(seats.Players, coopPlayer{Name: "bob", Token: "u"})
+	assert.Len(s.T(), rec.Coop.Players, 1)
+	data, err := json.Marshal(rec)
+	assert.Nil(s.T(), err)
+	var decoded redisSession
+	assert.Nil(s.T(), json.Unmarshal(data, &decoded))
+	assert.Equal(s.T(), rec.Coop, decoded.Coop)
+}
+
+func TestCoopTestSuite(t *testing.T) {
+	suite.Run(t, new(CoopTestSuite))
+}
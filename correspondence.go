@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	maxGameLifetime = flag.Duration("max_game_lifetime", 30*24*time.Hour,
+		"Max time a correspondence game created on the server can allow between "+
+			"two guesses.")
+	lifetimeWarnings = flag.String("lifetime_warnings", "24h,1h",
+		"Comma separated times before a correspondence game expires at which its "+
+			"clients are warned, e.g. \"24h,1h\".")
+	idleForfeit = flag.Duration("idle_forfeit", 0,
+		"Time after which a game created on the server without a lifetime is "+
+			"forfeited if no guess is made, e.g. \"10m\". Games never expire if zero.")
+	gameDeadline = flag.Duration("game_deadline", 0,
+		"Max time a game created on the server can last, after which it is "+
+			"forfeited if it is still running. Games can last forever if zero.")
+)
+
+// Method to parse a comma separated list of warning times, see
+// --lifetime_warnings.
+// Returns the times, longest first.
+func parseLifetimeWarnings(list string) ([]time.Duration, error) {
+	var warnings []time.Duration
+	for _, field := range splitList(list) {
+		d, err := time.ParseDuration(field)
+		if err != nil {
+			return nil, err
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("warning time %v must be positive", d)
+		}
+		warnings = append(warnings, d)
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i] > warnings[j]
+	})
+	return warnings, nil
+}
+
+// Method to get the warning times configured by the flags. Returns an error if
+// they are invalid.
+func lifetimeWarningsFromFlags() ([]time.Duration, error) {
+	warnings, err := parseLifetimeWarnings(*lifetimeWarnings)
+	if err != nil {
+		return nil, configError("invalid value of --lifetime_warnings %s: %w",
+			*lifetimeWarnings, err)
+	}
+	return warnings, nil
+}
+
+// Method to get the deadlines of the games configured by the flags: the time
+// allowed between two guesses of the games without a lifetime, and the max
+// time a game can last. Returns an error if they are invalid.
+func gameDeadlinesFromFlags() (time.Duration, time.Duration, error) {
+	if *idleForfeit < 0 || *gameDeadline < 0 {
+		return 0, 0, configError("invalid value of --idle_forfeit %v or --game_deadline %v, "+
+			"they can not be negative", *idleForfeit, *gameDeadline)
+	}
+	return *idleForfeit, *gameDeadline, nil
+}
+
+// Method to set the deadlines of a new session, from the lifetime requested
+// and the deadlines of the server. Games without a lifetime are given the
+// idle time of the server, or else its max game time, so that they are
+// forfeited like correspondence games.
+func (s *gameServer) setDeadlines(sess *session, lifetime time.Duration) {
+	if lifetime == 0 {
+		lifetime = s.idleForfeit
+	}
+	if s.gameDeadline > 0 {
+		sess.deadline = sess.created.Add(s.gameDeadline)
+		if lifetime == 0 {
+			lifetime = s.gameDeadline
+		}
+	}
+	sess.lifetime = lifetime
+}
+
+// Method to validate the lifetime requested for a new game.
+// Returns the lifetime, zero if the game is not a correspondence game.
+func (s *gameServer) gameLifetime(req api.CreateGameRequest) (time.Duration, *api.Error) {
+	lifetime := time.Duration(req.LifetimeSeconds) * time.Second
+	if lifetime < 0 || lifetime > s.maxLifetime {
+		return 0, api.NewError(api.CodeInvalidRequest,
+			"the lifetime must be between 0 and %v", s.maxLifetime).
+			WithDetail("lifetime_seconds", req.LifetimeSeconds)
+	}
+	return lifetime, nil
+}
+
+// Method to give the player of a correspondence game the full lifetime again,
+// after the game is created and after every guess. Must be called with the
+// session lock held.
+func (sess *session) resetExpiryLocked(now time.Time) {
+	if sess.lifetime == 0 {
+		return
+	}
+	if sess.expiryTimer != nil {
+		sess.expiryTimer.Stop()
+	}
+	sess.expiresAt = now.Add(sess.lifetime)
+	if !sess.deadline.IsZero() && sess.deadline.Before(sess.expiresAt) {
+		sess.expiresAt = sess.deadline
+	}
+	sess.warningsSent = 0
+	// Warnings earlier than the time left, e.g. than the whole lifetime,
+	// would be sent right away.
+	sess.nextWarning = 0
+	for sess.nextWarning < len(sess.warningTimes) &&
+		!now.Before(sess.nextExpiryEventLocked()) {
+		sess.nextWarning++
+	}
+	sess.armExpiryLocked(now)
+}
+
+// Method to start the timer of a correspondence game loaded from a session
+// store shared by several servers, which expires at the given time. The
+// warnings due before now are counted as sent. Must be called with the
+// session lock held.
+func (sess *session) restoreExpiryLocked(expiresAt, now time.Time) {
+	if sess.expiryTimer != nil {
+		sess.expiryTimer.Stop()
+		sess.expiryTimer = nil
+	}
+	if sess.lifetime == 0 || sess.game.State != Running {
+		return
+	}
+	sess.expiresAt = expiresAt
+	sess.nextWarning = 0
+	sess.warningsSent = 0
+	for sess.nextWarning < len(sess.warningTimes) &&
+		sess.warningTimes[sess.nextWarning] >= sess.lifetime {
+		sess.nextWarning++
+	}
+	for sess.nextWarning < len(sess.warningTimes) &&
+		!now.Before(sess.nextExpiryEventLocked()) {
+		sess.nextWarning++
+		sess.warningsSent++
+	}
+	sess.armExpiryLocked(now)
+}
+
+// Method to get the time of the next warning, or of the expiry once all the
+// warnings are sent. Must be called with the session lock held.
+func (sess *session) nextExpiryEventLocked() time.Time {
+	if sess.nextWarning < len(sess.warningTimes) {
+		return sess.expiresAt.Add(-sess.warningTimes[sess.nextWarning])
+	}
+	return sess.expiresAt
+}
+
+// Method to start the timer of the next warning or of the expiry. Must be
+// called with the session lock held.
+func (sess *session) armExpiryLocked(now time.Time) {
+	sess.expiryTimer = time.AfterFunc(sess.nextExpiryEventLocked().Sub(now), sess.onExpiryTimer)
+}
+
+// Method called by the expiry timer: it sends the next warning, or forfeits
+// the game once it expired.
+func (sess *session) onExpiryTimer() {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	now := time.Now()
+	// A guess made while the timer fired already reset the expiry.
+	if sess.game.State != Running || now.Before(sess.nextExpiryEventLocked()) {
+		return
+	}
+	if now.Before(sess.expiresAt) {
+		sess.nextWarning++
+		sess.warningsSent++
+		view := sess.viewLocked()
+		sess.broadcastLocked(api.Message{Type: api.MessageExpiryWarning, Game: &view})
+		sess.armExpiryLocked(now)
+		return
+	}
+	defaultLogger.Infof("Game %s expired, the player forfeits", sess.id)
+	sess.game.forfeit()
+	gameMetrics.sessionForfeited()
+	// The other candidates are no longer needed once the word is settled.
+	sess.game.settleWord()
+	if err := sess.saveLocked(context.Background()); err == errSessionConflict {
+		// Another server made a guess or forfeited the game first, the game
+		// was reloaded with its change.
+		return
+	} else if err != nil {
+		defaultLogger.Errorf("Unable to save game %s, error %v", sess.id, err)
+	}
+	view := sess.viewLocked()
+	sess.broadcastLocked(api.Message{Type: api.MessageState, Game: &view})
+	sess.recordLocked(now)
+}
+
+// Method to start the timer of the turn of a game with a guess timeout, which
+// fires at the deadline of the next guess. Without it, the missed deadlines
+// would only be applied when the next guess arrives, and a game lost by them
+// would show as running till then. The timer is armed again whenever it fires
+// while the game runs, since a guess moves the deadline. Must be called with
+// the session lock held.
+func (sess *session) armTurnTimerLocked() {
+	if sess.turnTimer != nil {
+		sess.turnTimer.Stop()
+		sess.turnTimer = nil
+	}
+	deadline, ok := sess.game.Deadline()
+	if !ok {
+		return
+	}
+	sess.turnTimer = time.AfterFunc(time.Until(deadline), sess.onTurnTimer)
+}
+
+// Method called by the turn timer: it consumes a retry for every deadline
+// missed, and sends the new state.
+func (sess *session) onTurnTimer() {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.game.Tick() {
+		sess.applyTimeoutsLocked(context.Background())
+	}
+	sess.armTurnTimerLocked()
+}
+
+// Method to save the game after the turns which timed out, and send it to the
+// watchers. Must be called with the session lock held.
+func (sess *session) applyTimeoutsLocked(ctx context.Context) {
+	now := time.Now()
+	if sess.game.State != Running && sess.expiryTimer != nil {
+		sess.expiryTimer.Stop()
+	}
+	if err := sess.saveLocked(ctx); err == errSessionConflict {
+		// Another server applied the timeouts or made a guess first, the game
+		// was reloaded with its change.
+		return
+	} else if err != nil {
+		defaultLogger.Errorf("Unable to save game %s, error %v", sess.id, err)
+	}
+	sess.preview = nil
+	view := sess.viewLocked()
+	sess.broadcastLocked(api.Message{Type: api.MessageState, Game: &view})
+	if sess.practice && len(sess.watchers) > 0 {
+		sess.broadcastLocked(api.Message{
+			Type:    api.MessagePreview,
+			Preview: sess.previewLocked(),
+		})
+	}
+	sess.recordLocked(now)
+}
+
+// Method to record the game in the hall of shame once it ended, as told by the
+// observer of the game, and in the leaderboard if the player is named and plays
+// alone. Must be called with the session lock held.
+func (sess *session) recordLocked(now time.Time) {
+	if !sess.ended {
+		return
+	}
+	sess.ended = false
+	if sess.shame != nil {
+		if err := sess.shame.add(shameEntry(sess.game, now)); err != nil {
+			defaultLogger.Errorf("Unable to record game %s in the hall of shame, error %v",
+				sess.id, err)
+		}
+	}
+	// A cooperative game is won by both players, so it is not recorded as a
+	// game of its host.
+	if sess.leaderboard == nil || sess.player == "" || sess.coop != nil {
+		return
+	}
+	entry := leaderboardEntry(sess.player, sess.game, now.Sub(sess.created), now)
+	entry.Challenge = sess.challenge
+	if err := sess.leaderboard.add(entry); err != nil {
+		defaultLogger.Errorf("Unable to record game %s in the leaderboard, error %v",
+			sess.id, err)
+	}
+}
+
+// Method to describe the time left before a correspondence game expires.
+func formatTimeLeft(expiresAt, now time.Time) string {
+	left := expiresAt.Sub(now).Round(time.Minute)
+	if left < time.Minute {
+		return "less than a minute"
+	}
+	return strings.TrimSuffix(left.String(), "0s")
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type CorrespondenceTestSuite struct {
+	suite.Suite
+	gameServer *gameServer
+	server     *httptest.Server
+}
+
+func (s *CorrespondenceTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+	s.gameServer = newGameServer()
+	s.gameServer.leaderboard = newLeaderboardStore(
+		filepath.Join(s.T().TempDir(), "leaderboard.jsonl"))
+	s.gameServer.lifetimeWarnings = []time.Duration{time.Hour, 500 * time.Millisecond}
+	s.server = httptest.NewServer(s.gameServer.Handler())
+}
+
+func (s *CorrespondenceTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *CorrespondenceTestSuite) create(req api.CreateGameRequest) (api.Game, int) {
+	body, _ := json.Marshal(req)
+	resp, err := http.Post(s.server.URL+"/games", "application/json", bytes.NewReader(body))
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	var game api.Game
+	json.NewDecoder(resp.Body).Decode(&game)
+	return game, resp.StatusCode
+}
+
+func (s *CorrespondenceTestSuite) TestParseWarnings() {
+	warnings, err := parseLifetimeWarnings("1h, 24h,30m")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []time.Duration{24 * time.Hour, time.Hour, 30 * time.Minute}, warnings)
+	_, err = parseLifetimeWarnings("1h,soon")
+	assert.NotNil(s.T(), err)
+	_, err = parseLifetimeWarnings("-1h")
+	assert.NotNil(s.T(), err)
+}
+
+func (s *CorrespondenceTestSuite) TestExpiry() {
+	game, status := s.create(api.CreateGameRequest{WordLength: 4, Retries: 3,
+		Player: "alice", LifetimeSeconds: 1})
+	assert.Equal(s.T(), http.StatusCreated, status)
+	assert.NotNil(s.T(), game.ExpiresAt)
+	assert.Equal(s.T(), "alice", game.Player)
+	client := dialTestWS(s.T(), s.server.URL, "/games/"+game.ID+"/ws")
+	defer client.conn.Close()
+	assert.Equal(s.T(), api.MessageState, client.read(s.T()).Type)
+
+	// The warning an hour before is longer than the lifetime, so it is skipped.
+	msg := client.read(s.T())
+	assert.Equal(s.T(), api.MessageExpiryWarning, msg.Type)
+	assert.Equal(s.T(), 1, msg.Game.ExpiryWarnings)
+	msg = client.read(s.T())
+	assert.Equal(s.T(), api.MessageState, msg.Type)
+	assert.Equal(s.T(), api.StateForfeited, msg.Game.State)
+	assert.True(s.T(), msg.Game.Forfeited)
+	assert.Nil(s.T(), msg.Game.ExpiresAt)
+
+	entries, err := s.gameServer.leaderboard.entries()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, len(entries))
+	assert.True(s.T(), entries[0].Forfeited)
+	board := rankPlayers(entries, 10)
+	assert.Equal(s.T(), 1, board.Players[0].Forfeits)
+	assert.Equal(s.T(), 0, board.Players[0].Wins)
+}
+
+func (s *CorrespondenceTestSuite) TestGuessResetsExpiry() {
+	game, _ := s.create(api.CreateGameRequest{WordLength: 4, Retries: 3, LifetimeSeconds: 60})
+	time.Sleep(10 * time.Millisecond)
+	var guess api.GuessResponse
+	resp, err := http.Post(s.server.URL+"/games/"+game.ID+"/guesses", "application/json",
+		bytes.NewBufferString(`{"char": "x"}`))
+	assert.Nil(s.T(), err)
+	json.NewDecoder(resp.Body).Decode(&guess)
+	resp.Body.Close()
+	assert.True(s.T(), guess.Game.ExpiresAt.After(*game.ExpiresAt))
+
+	// Games without a lifetime never expire.
+	game, _ = s.create(api.CreateGameRequest{WordLength: 4, Retries: 3})
+	assert.Nil(s.T(), game.ExpiresAt)
+}
+
+func (s *CorrespondenceTestSuite) TestIdleForfeit() {
+	s.gameServer.idleForfeit = 300 * time.Millisecond
+	forfeited := gameMetrics.Snapshot().ForfeitedSessions
+	game, _ := s.create(api.CreateGameRequest{WordLength: 4, Retries: 3})
+	assert.NotNil(s.T(), game.ExpiresAt)
+	sess, apiErr := s.gameServer.getSession(context.Background(), game.ID)
+	assert.Nil(s.T(), apiErr)
+
+	time.Sleep(500 * time.Millisecond)
+	view := sess.view()
+	assert.Equal(s.T(), api.StateForfeited, view.State)
+	assert.Nil(s.T(), view.ExpiresAt)
+	assert.Equal(s.T(), forfeited+1, gameMetrics.Snapshot().ForfeitedSessions)
+	// Only the word revealed is kept.
+	assert.Len(s.T(), sess.game.Candidates(), 1)
+	assert.Equal(s.T(), sess.game.Candidates()[0], sess.game.RevealWord())
+}
+
+func (s *CorrespondenceTestSuite) TestGameDeadline() {
+	s.gameServer.gameDeadline = time.Minute
+	game, _ := s.create(api.CreateGameRequest{WordLength: 4, Retries: 3})
+	deadline := *game.ExpiresAt
+	// A guess does not give more time than the deadline.
+	var guess api.GuessResponse
+	resp, err := http.Post(s.server.URL+"/games/"+game.ID+"/guesses", "application/json",
+		bytes.NewBufferString(`{"char": "x"}`))
+	assert.Nil(s.T(), err)
+	json.NewDecoder(resp.Body).Decode(&guess)
+	resp.Body.Close()
+	assert.Equal(s.T(), deadline, *guess.Game.ExpiresAt)
+
+	// Correspondence games expire by the deadline too.
+	game, _ = s.create(api.CreateGameRequest{WordLength: 4, Retries: 3, LifetimeSeconds: 3600})
+	assert.True(s.T(), game.ExpiresAt.Before(time.Now().Add(time.Minute+time.Second)))
+	game, _ = s.create(api.CreateGameRequest{WordLength: 4, Retries: 3, LifetimeSeconds: 10})
+	assert.True(s.T(), game.ExpiresAt.Before(time.Now().Add(11*time.Second)))
+}
+
+func (s *CorrespondenceTestSuite) TestInvalidLifetime() {
+	s.gameServer.maxLifetime = time.Minute
+	_, status := s.create(api.CreateGameRequest{WordLength: 4, Retries: 3,
+		LifetimeSeconds: 61})
+	assert.Equal(s.T(), http.StatusBadRequest, status)
+}
+
+func (s *CorrespondenceTestSuite) TestNotifications() {
+	expiresAt := time.Now().Add(time.Hour)
+	prev := api.Game{ID: "1", State: api.StateRunning, ExpiresAt: &expiresAt}
+	cur := prev
+	cur.ExpiryWarnings = 1
+	n, ok := gameNotification(prev, cur, false)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "Game 1 is about to expire", n.Title)
+	assert.Contains(s.T(), n.Message, "within 1h")
+	_, ok = gameNotification(prev, cur, true)
+	assert.False(s.T(), ok)
+
+	cur = api.Game{ID: "1", State: api.StateForfeited, Forfeited: true}
+	n, ok = gameNotification(prev, cur, true)
+	assert.True(s.T(), ok)
+	assert.Contains(s.T(), n.Message, "forfeited")
+}
+
+func TestCorrespondenceTestSuite(t *testing.T) {
+	suite.Run(t, new(CorrespondenceTestSuite))
+}
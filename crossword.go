@@ -0,0 +1,338 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	// Lengths of the words of the crosswords.
+	crosswordMinLength = 3
+	crosswordMaxLength = 8
+	// Max number of rows and columns of a crossword grid.
+	crosswordMaxSize = 15
+	// Words tried per word of a crossword before giving up.
+	crosswordAttempts = 400
+)
+
+// Word of a crossword, written across (left to right) or down (top to bottom)
+// from its first cell.
+type CrosswordEntry struct {
+	Word   string
+	Row    int
+	Col    int
+	Across bool
+	// Clue of the word, see Dictionary.Hint. Empty if it has none.
+	Clue string
+}
+
+// Method to get the cell of the i-th character of the word.
+func (e CrosswordEntry) cell(i int) (int, int) {
+	if e.Across {
+		return e.Row, e.Col + i
+	}
+	return e.Row + i, e.Col
+}
+
+// Crossword played like hangman: every guess reveals the letter in all the
+// words of the grid, so a letter found in a word also helps with the words
+// crossing it. Unlike Game, the words are picked when the crossword is
+// generated and never change.
+type Crossword struct {
+	// Words of the grid, in the order they were placed.
+	Entries []CrosswordEntry
+	// Size of the grid.
+	Rows int
+	Cols int
+	// Total retries allowed.
+	AllowedRetries int
+	// Current retries left.
+	CurrentRetries int
+	// Used characters.
+	UsedChars []rune
+	// Current state of the game.
+	State GameState
+
+	// Letters of the grid by row, zero for the cells without a letter.
+	cells [][]rune
+	// Alphabet of the dictionary the words come from, for the guesses.
+	alphabet Alphabet
+}
+
+// Grid being generated, with the cells of the words placed so far. The cells
+// are not bounded, so the grid can grow in every direction.
+type crosswordLayout struct {
+	entries []CrosswordEntry
+	letters map[[2]int]rune
+	// Cells of the words written across and down, so that two words of the
+	// same direction never share a cell.
+	across map[[2]int]bool
+	down   map[[2]int]bool
+	// Bounds of the grid, inclusive.
+	minRow, maxRow, minCol, maxCol int
+}
+
+// Method to generate a crossword of the given number of words picked at
+// random in the dictionary, with the given retries. Every word after the first
+// one crosses at least one of the words placed before it.
+// It returns an error wrapping ErrInvalidWordCount if the number of words is
+// less than 2 or the words do not fit in a grid, and ErrInvalidRetries if the
+// retries are not valid.
+func NewCrossword(d *Dictionary, words, retries int, rng *rand.Rand) (*Crossword, error) {
+	if words < 2 {
+		return nil, newGameError(ErrInvalidWordCount,
+			"A crossword needs at least 2 words, got %d", words)
+	}
+	if !validateNumRetries(retries) {
+		return nil, newGameError(ErrInvalidRetries,
+			"Retries must be between 0 and %d, got %d", *maxAllowedRetries, retries)
+	}
+	pool := crosswordWords(d)
+	if len(pool) == 0 {
+		return nil, newGameError(ErrInvalidWordCount,
+			"No words of %d to %d letters in the dictionary", crosswordMinLength,
+			crosswordMaxLength)
+	}
+	layout := &crosswordLayout{
+		letters: make(map[[2]int]rune),
+		across:  make(map[[2]int]bool),
+		down:    make(map[[2]int]bool),
+	}
+	layout.place(CrosswordEntry{Word: pool[rng.Intn(len(pool))], Across: true})
+	for attempt := 0; attempt < crosswordAttempts*words && len(layout.entries) < words; attempt++ {
+		word := pool[rng.Intn(len(pool))]
+		if layout.contains(word) {
+			continue
+		}
+		fits := layout.placements(word)
+		if len(fits) > 0 {
+			layout.place(fits[rng.Intn(len(fits))])
+		}
+	}
+	if len(layout.entries) < words {
+		return nil, newGameError(ErrInvalidWordCount,
+			"Unable to fit %d words of the dictionary in a crossword, try fewer words", words)
+	}
+	c := &Crossword{
+		Rows:           layout.maxRow - layout.minRow + 1,
+		Cols:           layout.maxCol - layout.minCol + 1,
+		AllowedRetries: retries,
+		CurrentRetries: retries,
+		State:          Running,
+		alphabet:       d.alphabet,
+	}
+	c.cells = make([][]rune, c.Rows)
+	for i := range c.cells {
+		c.cells[i] = make([]rune, c.Cols)
+	}
+	for cell, letter := range layout.letters {
+		c.cells[cell[0]-layout.minRow][cell[1]-layout.minCol] = letter
+	}
+	for _, entry := range layout.entries {
+		entry.Row -= layout.minRow
+		entry.Col -= layout.minCol
+		entry.Clue, _ = d.Hint(entry.Word)
+		c.Entries = append(c.Entries, entry)
+	}
+	return c, nil
+}
+
+// Method to get the words of the dictionary which can be used in a crossword,
+// sorted so that the crosswords of a seed are reproducible. Phrases and words
+// with punctuation are left out, as their separators are not letters to guess.
+func crosswordWords(d *Dictionary) []string {
+	var words []string
+	for _, length := range d.Lengths() {
+		if length < crosswordMinLength || length > crosswordMaxLength {
+			continue
+		}
+	word:
+		for _, word := range d.Words(length) {
+			for _, char := range word {
+				if isRevealedChar(char) {
+					continue word
+				}
+			}
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+	return words
+}
+
+// Method to check if a word is already in the grid.
+func (l *crosswordLayout) contains(word string) bool {
+	for _, entry := range l.entries {
+		if entry.Word == word {
+			return true
+		}
+	}
+	return false
+}
+
+// Method to add a word to the grid, which must fit.
+func (l *crosswordLayout) place(entry CrosswordEntry) {
+	first := len(l.entries) == 0
+	l.entries = append(l.entries, entry)
+	i := 0
+	for _, char := range entry.Word {
+		row, col := entry.cell(i)
+		cell := [2]int{row, col}
+		l.letters[cell] = char
+		if entry.Across {
+			l.across[cell] = true
+		} else {
+			l.down[cell] = true
+		}
+		if first && i == 0 {
+			l.minRow, l.maxRow, l.minCol, l.maxCol = row, row, col, col
+		}
+		l.minRow, l.maxRow = min(l.minRow, row), max(l.maxRow, row)
+		l.minCol, l.maxCol = min(l.minCol, col), max(l.maxCol, col)
+		i++
+	}
+}
+
+// Method to find the positions at which a word crosses the words of the grid
+// without touching the other words.
+func (l *crosswordLayout) placements(word string) []CrosswordEntry {
+	chars := []rune(word)
+	var fits []CrosswordEntry
+	for _, placed := range l.entries {
+		i := 0
+		for _, placedChar := range placed.Word {
+			row, col := placed.cell(i)
+			i++
+			for j, char := range chars {
+				if char != placedChar {
+					continue
+				}
+				entry := CrosswordEntry{Word: word, Row: row, Col: col, Across: !placed.Across}
+				if entry.Across {
+					entry.Col -= j
+				} else {
+					entry.Row -= j
+				}
+				if l.fits(entry) {
+					fits = append(fits, entry)
+				}
+			}
+		}
+	}
+	return fits
+}
+
+// Method to check if a word can be written in the grid: its letters match the
+// letters of the words it crosses, it crosses at least one word, it does not
+// touch the words it does not cross, and the grid stays small enough.
+func (l *crosswordLayout) fits(entry CrosswordEntry) bool {
+	length := utf8.RuneCountInString(entry.Word)
+	empty := func(row, col int) bool {
+		_, ok := l.letters[[2]int{row, col}]
+		return !ok
+	}
+	// The cells right before and after the word are free, or the word would
+	// run into another one.
+	beforeRow, beforeCol := entry.cell(-1)
+	afterRow, afterCol := entry.cell(length)
+	if !empty(beforeRow, beforeCol) || !empty(afterRow, afterCol) {
+		return false
+	}
+	same, crossing := l.across, l.down
+	if !entry.Across {
+		same, crossing = l.down, l.across
+	}
+	crosses := 0
+	minRow, maxRow, minCol, maxCol := l.minRow, l.maxRow, l.minCol, l.maxCol
+	i := 0
+	for _, char := range entry.Word {
+		row, col := entry.cell(i)
+		i++
+		cell := [2]int{row, col}
+		minRow, maxRow = min(minRow, row), max(maxRow, row)
+		minCol, maxCol = min(minCol, col), max(maxCol, col)
+		if letter, ok := l.letters[cell]; ok {
+			if letter != char || same[cell] || !crossing[cell] {
+				return false
+			}
+			crosses++
+			continue
+		}
+		// The sides of a new letter are free, or it would form a word with
+		// the letters next to it.
+		if entry.Across && (!empty(row-1, col) || !empty(row+1, col)) {
+			return false
+		}
+		if !entry.Across && (!empty(row, col-1) || !empty(row, col+1)) {
+			return false
+		}
+	}
+	return crosses > 0 && maxRow-minRow < crosswordMaxSize && maxCol-minCol < crosswordMaxSize
+}
+
+// Method to check a guess, which reveals the letter in all the words of the
+// grid.
+// Returns true if the letter is in the grid. It returns an error wrapping
+// ErrGameFinished, ErrInvalidCharacter or ErrCharAlreadyUsed if the guess is
+// not valid, in which case nothing changes.
+func (c *Crossword) CheckUserInput(char rune) (bool, error) {
+	if c.State != Running {
+		return false, newGameError(ErrGameFinished,
+			"Unexpected scenario: input given for a crossword which is not running")
+	}
+	char = c.alphabet.Fold(char)
+	if !c.alphabet.Contains(char) {
+		return false, newGameError(ErrInvalidCharacter,
+			"Character %s is not a valid letter for this dictionary. "+
+				"Please enter a new character.", string(char))
+	}
+	if contains(c.UsedChars, char) {
+		return false, newGameError(ErrCharAlreadyUsed, "Character %s has been used. "+
+			"Please enter a new character.", string(char))
+	}
+	c.UsedChars = append(c.UsedChars, char)
+	found, hidden := false, false
+	for _, row := range c.cells {
+		for _, letter := range row {
+			found = found || letter == char
+			hidden = hidden || (letter != 0 && !contains(c.UsedChars, letter))
+		}
+	}
+	if !found {
+		c.CurrentRetries--
+		if c.CurrentRetries <= 0 {
+			c.State = Lost
+		}
+		return false, nil
+	}
+	if !hidden {
+		c.State = Won
+	}
+	return true, nil
+}
+
+// Method to get the cell of the grid at a row and column: whether the cell has
+// a letter, and the letter if it was guessed (emptyChar otherwise, or once the
+// game is over, the letter).
+func (c *Crossword) Cell(row, col int) (rune, bool) {
+	letter := c.cells[row][col]
+	if letter == 0 {
+		return 0, false
+	}
+	if c.State == Running && !contains(c.UsedChars, letter) {
+		return emptyChar, true
+	}
+	return letter, true
+}
+
+// Method to get a word of the grid as shown to the player, a pattern with
+// emptyChar for the letters not guessed yet.
+func (c *Crossword) Pattern(entry CrosswordEntry) []rune {
+	pattern := make([]rune, 0, len(entry.Word))
+	for i := range []rune(entry.Word) {
+		letter, _ := c.Cell(entry.cell(i))
+		pattern = append(pattern, letter)
+	}
+	return pattern
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+var (
+	crosswordWordCount = flag.Int("crossword_words", 5,
+		"Number of words of the grid of the crossword subcommand.")
+)
+
+// Method to number the words of a crossword like in the newspapers: the cells
+// starting a word are numbered in reading order, and the words starting at the
+// same cell share its number.
+// Returns the number of every entry.
+func crosswordNumbers(c *Crossword) []int {
+	starts := make([][2]int, 0, len(c.Entries))
+	for _, entry := range c.Entries {
+		starts = append(starts, [2]int{entry.Row, entry.Col})
+	}
+	sorted := append([][2]int{}, starts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i][0] != sorted[j][0] {
+			return sorted[i][0] < sorted[j][0]
+		}
+		return sorted[i][1] < sorted[j][1]
+	})
+	numbers := make(map[[2]int]int)
+	for _, start := range sorted {
+		if _, ok := numbers[start]; !ok {
+			numbers[start] = len(numbers) + 1
+		}
+	}
+	result := make([]int, len(starts))
+	for i, start := range starts {
+		result[i] = numbers[start]
+	}
+	return result
+}
+
+// Method to show a crossword: the grid, with the letters not guessed yet
+// shown as blanks of the format, then the numbered words across and down with
+// their clues. In screen reader mode the grid is left out and the words are
+// spelled out.
+func formatCrossword(c *Crossword, f PatternFormat) string {
+	var b strings.Builder
+	if !*screenReader {
+		for row := 0; row < c.Rows; row++ {
+			cells := make([]string, c.Cols)
+			for col := range cells {
+				letter, ok := c.Cell(row, col)
+				if !ok {
+					cells[col] = " "
+				} else {
+					cells[col] = f.Format([]rune{letter})
+				}
+			}
+			b.WriteString(strings.TrimRight(strings.Join(cells, " "), " ") + "\n")
+		}
+	}
+	numbers := crosswordNumbers(c)
+	for _, across := range []bool{true, false} {
+		if across {
+			b.WriteString("Across:\n")
+		} else {
+			b.WriteString("Down:\n")
+		}
+		// The words are listed by number, like in the newspapers.
+		var entries []int
+		for i, entry := range c.Entries {
+			if entry.Across == across {
+				entries = append(entries, i)
+			}
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return numbers[entries[i]] < numbers[entries[j]]
+		})
+		for _, i := range entries {
+			entry := c.Entries[i]
+			word := f.Format(c.Pattern(entry))
+			if *screenReader {
+				word = spokenWord(c.Pattern(entry))
+			}
+			fmt.Fprintf(&b, "  %d. %s", numbers[i], word)
+			if entry.Clue != "" {
+				fmt.Fprintf(&b, " (%s)", entry.Clue)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// Driver method for the "crossword" subcommand, where the player fills a grid
+// of --crossword_words crossing words by guessing letters, which are revealed
+// in all the words.
+func StartCrossword() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	var crossword *Crossword
+	for crossword == nil {
+		fmt.Println(tr("enter_retries", *maxAllowedRetries))
+		retries, err := readInt()
+		if isInputEnd(err) {
+			return err
+		}
+		if err != nil {
+			fmt.Println(tr("invalid_input", err))
+			continue
+		}
+		crossword, err = NewCrossword(currentDictionary(), *crosswordWordCount, retries,
+			rand.New(rand.NewSource(randInt63())))
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+	for crossword.State == Running {
+		fmt.Print(formatCrossword(crossword, displayFormat))
+		fmt.Println(tr("enter_char", colors.used(string(crossword.UsedChars)),
+			crossword.CurrentRetries))
+		char, err := readChar()
+		if err != nil {
+			return err
+		}
+		accepted, err := crossword.CheckUserInput(char)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if accepted {
+			fmt.Println(colors.correct(tr("right_char")))
+		} else if crossword.State == Running {
+			fmt.Println(colors.wrong(fmt.Sprint("Sorry its a wrong input. Remaining tries: ",
+				crossword.CurrentRetries)))
+		}
+	}
+	fmt.Print(formatCrossword(crossword, displayFormat))
+	if crossword.State == Won {
+		fmt.Println(colors.correct(tr("won")))
+	} else {
+		fmt.Println(colors.wrong("All retries finished, you lose!!"))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+)
+
+type CrosswordTestSuite struct {
+	suite.Suite
+}
+
+func (s *CrosswordTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cast", "lost", "stone", "notes",
+		"table", "eats", "to", "a b", "don't"})
+}
+
+// Method to build a small crossword by hand: "cat" across and "cow" down from
+// the top left cell.
+func smallCrossword() *Crossword {
+	return &Crossword{
+		Entries: []CrosswordEntry{
+			{Word: "cat", Across: true, Clue: "pet"},
+			{Word: "cow", Across: false},
+		},
+		Rows: 3, Cols: 3, AllowedRetries: 1, CurrentRetries: 1, State: Running,
+		cells:    [][]rune{{'c', 'a', 't'}, {'o', 0, 0}, {'w', 0, 0}},
+		alphabet: currentDictionary().alphabet,
+	}
+}
+
+func (s *CrosswordTestSuite) TestGenerate() {
+	for seed := int64(0); seed < 50; seed++ {
+		c, err := NewCrossword(currentDictionary(), 4, 5, rand.New(rand.NewSource(seed)))
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), 4, len(c.Entries))
+		letters := 0
+		for i, entry := range c.Entries {
+			assert.NotContains(s.T(), []string{"to", "a b", "don't"}, entry.Word)
+			crosses := false
+			for j, char := range []rune(entry.Word) {
+				row, col := entry.cell(j)
+				assert.Equal(s.T(), char, c.cells[row][col])
+				for _, other := range c.Entries[:i] {
+					for k := range []rune(other.Word) {
+						otherRow, otherCol := other.cell(k)
+						crosses = crosses || (row == otherRow && col == otherCol)
+					}
+				}
+			}
+			assert.True(s.T(), i == 0 || crosses, "word %s crosses no word", entry.Word)
+			letters += utf8.RuneCountInString(entry.Word)
+		}
+		// Every word after the first shares at least a cell.
+		cells := 0
+		for _, row := range c.cells {
+			for _, letter := range row {
+				if letter != 0 {
+					cells++
+				}
+			}
+		}
+		assert.LessOrEqual(s.T(), cells, letters-len(c.Entries)+1)
+	}
+}
+
+func (s *CrosswordTestSuite) TestInvalid() {
+	rng := rand.New(rand.NewSource(1))
+	_, err := NewCrossword(currentDictionary(), 1, 5, rng)
+	assert.True(s.T(), errors.Is(err, ErrInvalidWordCount))
+	_, err = NewCrossword(currentDictionary(), 50, 5, rng)
+	assert.True(s.T(), errors.Is(err, ErrInvalidWordCount))
+	_, err = NewCrossword(currentDictionary(), 2, -1, rng)
+	assert.True(s.T(), errors.Is(err, ErrInvalidRetries))
+}
+
+func (s *CrosswordTestSuite) TestPlay() {
+	c := smallCrossword()
+	accepted, err := c.CheckUserInput('C')
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), accepted)
+	assert.Equal(s.T(), []rune{'c', emptyChar, emptyChar}, c.Pattern(c.Entries[0]))
+	assert.Equal(s.T(), []rune{'c', emptyChar, emptyChar}, c.Pattern(c.Entries[1]))
+	_, err = c.CheckUserInput('c')
+	assert.True(s.T(), errors.Is(err, ErrCharAlreadyUsed))
+	for _, char := range "atow" {
+		accepted, err = c.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+		assert.True(s.T(), accepted)
+	}
+	assert.Equal(s.T(), Won, c.State)
+	_, err = c.CheckUserInput('z')
+	assert.True(s.T(), errors.Is(err, ErrGameFinished))
+
+	c = smallCrossword()
+	accepted, err = c.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), accepted)
+	assert.Equal(s.T(), Lost, c.State)
+	// The words are shown once the game is over.
+	assert.Equal(s.T(), []rune("cow"), c.Pattern(c.Entries[1]))
+}
+
+func (s *CrosswordTestSuite) TestFormat() {
+	c := smallCrossword()
+	_, err := c.CheckUserInput('a')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "_ a _\n_\n_\nAcross:\n  1. _a_ (pet)\nDown:\n  1. ___\n",
+		formatCrossword(c, DefaultPatternFormat))
+	assert.Equal(s.T(), []int{1, 1}, crosswordNumbers(c))
+}
+
+func TestCrosswordTestSuite(t *testing.T) {
+	suite.Run(t, new(CrosswordTestSuite))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+var (
+	dailyMode = flag.Bool("daily", false,
+		"Play the puzzle of the day. Everyone playing on the same (UTC) day with "+
+			"the same dictionary gets the same puzzle.")
+)
+
+const (
+	// Retries allowed in the daily puzzle.
+	dailyRetries = 6
+	// Min number of words of a length for it to be picked for the daily puzzle.
+	dailyMinWords = 20
+	// Number of guesses in a row of the shareable result.
+	shareRowLength = 5
+)
+
+// Method to get the configuration of the puzzle of a day.
+// The word length is derived from the date, so everyone gets the same length
+// on the same day. The game itself needs no seed: the computer picks the
+// words the same way for the same guesses, so everyone making the same guesses
+// gets the same answers.
+func dailyPuzzle(dict *Dictionary, date time.Time) Difficulty {
+	var lengths []int
+	for _, length := range dict.Lengths() {
+		if dict.Count(length) >= dailyMinWords {
+			lengths = append(lengths, length)
+		}
+	}
+	// Small dictionaries may not have enough words of any length.
+	if len(lengths) == 0 {
+		lengths = dict.Lengths()
+	}
+	retries := dailyRetries
+	if retries > *maxAllowedRetries {
+		retries = *maxAllowedRetries
+	}
+	if len(lengths) == 0 {
+		return Difficulty{Retries: retries}
+	}
+	h := fnv.New64a()
+	h.Write([]byte(dailyKey(date)))
+	return Difficulty{
+		WordLength: lengths[h.Sum64()%uint64(len(lengths))],
+		Retries:    retries,
+	}
+}
+
+// Method to get the key of the day a time falls on, in UTC.
+func dailyKey(date time.Time) string {
+	return date.UTC().Format("2006-01-02")
+}
+
+// Method to build the result of a daily puzzle which can be shared without
+// giving away the word: one square per guess, green if it was right and red if
+// it was wrong (or the time ran out).
+func shareString(date time.Time, puzzle Difficulty, state GameState, results []bool) string {
+	wrong := 0
+	for _, accepted := range results {
+		if !accepted {
+			wrong++
+		}
+	}
+	score := fmt.Sprintf("%d/%d", wrong, puzzle.Retries)
+	if state != Won {
+		score = fmt.Sprintf("X/%d", puzzle.Retries)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "WordGuess daily %s (%d letters) %s", dailyKey(date),
+		puzzle.WordLength, score)
+	for i, accepted := range results {
+		if i%shareRowLength == 0 {
+			b.WriteString("\n")
+		}
+		if accepted {
+			b.WriteString("🟩")
+		} else {
+			b.WriteString("🟥")
+		}
+	}
+	return b.String()
+}
+
+// Driver method to play the puzzle of the day.
+func StartDaily() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	revealPolicy, err := revealPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	retryPolicy, err := retryPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	puzzle := dailyPuzzle(currentDictionary(), now)
+	game, err := NewGame(puzzle.WordLength, puzzle.Retries, WithGuessTimeout(*guessTimeout),
+		WithRevealPolicy(revealPolicy), WithRetryPolicy(retryPolicy))
+	if err != nil {
+		return fmt.Errorf("unable to start the daily puzzle: %w", err)
+	}
+	fmt.Println("Daily puzzle of", dailyKey(now), ": a word of", puzzle.WordLength,
+		"letters with", puzzle.Retries, "retries.")
+	results, err := playGame(game, *showFrequencies)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Share your result:")
+	fmt.Println(shareString(now, puzzle, game.State, results))
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type DailyTestSuite struct {
+	suite.Suite
+}
+
+func (s *DailyTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code", "hello"})
+}
+
+func (s *DailyTestSuite) TestSamePuzzleForTheDay() {
+	dict := currentDictionary()
+	morning := time.Date(2026, 3, 1, 1, 0, 0, 0, time.UTC)
+	// 23:00 UTC on the same day, in another time zone.
+	evening := time.Date(2026, 3, 1, 15, 0, 0, 0, time.FixedZone("PST", -8*3600))
+	puzzle := dailyPuzzle(dict, morning)
+	assert.Equal(s.T(), puzzle, dailyPuzzle(dict, evening))
+	assert.Equal(s.T(), dailyRetries, puzzle.Retries)
+	assert.Contains(s.T(), []int{4, 5}, puzzle.WordLength)
+
+	// Every length is picked on some day.
+	lengths := make(map[int]bool)
+	for day := 0; day < 30; day++ {
+		lengths[dailyPuzzle(dict, morning.AddDate(0, 0, day)).WordLength] = true
+	}
+	assert.Equal(s.T(), 2, len(lengths))
+}
+
+func (s *DailyTestSuite) TestShareString() {
+	date := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	puzzle := Difficulty{WordLength: 4, Retries: 6}
+	results := []bool{false, true, true, false, true, true}
+	assert.Equal(s.T(), "WordGuess daily 2026-03-01 (4 letters) 2/6\n"+
+		"🟥🟩🟩🟥🟩\n🟩", shareString(date, puzzle, Won, results))
+	assert.Equal(s.T(), "WordGuess daily 2026-03-01 (4 letters) X/6\n🟥",
+		shareString(date, puzzle, Lost, results[:1]))
+}
+
+func TestDailyTestSuite(t *testing.T) {
+	suite.Run(t, new(DailyTestSuite))
+}
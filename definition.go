@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	definitionsFile = flag.String("definitions_file", "",
+		"Absolute path of a WordNet data file (e.g. data.noun) or of a file of "+
+			"word<TAB>definition lines. The definition of the word is shown at "+
+			"the end of every game played in the terminal.")
+	definitionsAPI = flag.String("definitions_api", "",
+		"URL of a dictionary API returning the definitions of a word in the "+
+			"format of dictionaryapi.dev, where {word} is replaced by the word, e.g. "+
+			"https://api.dictionaryapi.dev/api/v2/entries/en/{word}. The definition "+
+			"of the word is shown at the end of every game played in the terminal.")
+	definitionsCache = flag.String("definitions_cache", "",
+		"Directory where the definitions fetched from --definitions_api are "+
+			"cached. Defaults to wordguess/definitions in the user cache directory.")
+)
+
+// Max number of definitions shown for a word.
+const maxDefinitions = 3
+
+// Definition of a word.
+type Definition struct {
+	// Part of speech, e.g. "noun", empty if the source does not tell it.
+	PartOfSpeech string `json:"part_of_speech,omitempty"`
+	Text         string `json:"text"`
+}
+
+// Source of the definitions of the words.
+type DefinitionSource interface {
+	// Method to get the definitions of a word, none if the source does not
+	// know the word.
+	Define(word string) ([]Definition, error)
+}
+
+// Source of the definitions shown at the end of the games in the terminal,
+// set from the flags when the game starts. Nil if no definition is shown.
+var definitions DefinitionSource
+
+// Definitions loaded from a file, by word.
+type fileDefinitions map[string][]Definition
+
+// Parts of speech of the synset types of WordNet.
+var wordNetPartsOfSpeech = map[string]string{
+	"n": "noun", "v": "verb", "a": "adjective", "s": "adjective", "r": "adverb",
+}
+
+// Method to load the definitions of a WordNet data file, whose lines are
+// "offset lex_filenum ss_type w_cnt word lex_id [word lex_id...] ... | gloss",
+// or of a file of word<TAB>definition lines. The lines of the license at the
+// start of the WordNet files, which start with spaces, are skipped.
+func loadDefinitions(path string) (fileDefinitions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	defs := make(fileDefinitions)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, " ") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		if word, text, ok := strings.Cut(line, "\t"); ok {
+			word = strings.ToLower(strings.TrimSpace(word))
+			defs[word] = append(defs[word], Definition{Text: strings.TrimSpace(text)})
+			continue
+		}
+		data, gloss, ok := strings.Cut(line, " | ")
+		fields := strings.Fields(data)
+		if !ok || len(fields) < 4 {
+			continue
+		}
+		// The definition is the gloss without its examples, which follow it
+		// in quotes after a semicolon.
+		text, _, _ := strings.Cut(gloss, "; \"")
+		def := Definition{PartOfSpeech: wordNetPartsOfSpeech[fields[2]],
+			Text: strings.TrimSpace(text)}
+		var count int
+		fmt.Sscanf(fields[3], "%x", &count)
+		for i := 0; i < count && 4+2*i < len(fields); i++ {
+			word := strings.ToLower(strings.ReplaceAll(fields[4+2*i], "_", " "))
+			defs[word] = append(defs[word], def)
+		}
+	}
+	return defs, scanner.Err()
+}
+
+func (d fileDefinitions) Define(word string) ([]Definition, error) {
+	return d[strings.ToLower(word)], nil
+}
+
+// Definitions fetched from a dictionary API and cached on disk, so a word is
+// only fetched once, including the words the API does not know.
+type apiDefinitions struct {
+	client *http.Client
+	// URL of the API, where {word} is replaced by the word.
+	url string
+	// Directory of the cache.
+	cacheDir string
+}
+
+func newAPIDefinitions(apiURL, cacheDir string) *apiDefinitions {
+	return &apiDefinitions{
+		client:   &http.Client{Timeout: 5 * time.Second},
+		url:      apiURL,
+		cacheDir: cacheDir,
+	}
+}
+
+// Response of the API for a word, in the format of dictionaryapi.dev.
+type apiEntry struct {
+	Meanings []struct {
+		PartOfSpeech string `json:"partOfSpeech"`
+		Definitions  []struct {
+			Definition string `json:"definition"`
+		} `json:"definitions"`
+	} `json:"meanings"`
+}
+
+// Method to get the path of the cached definitions of a word. The URL of the
+// API is part of the name, so changing the API does not use the definitions
+// of the previous one.
+func (a *apiDefinitions) cachePath(word string) string {
+	sum := sha256.Sum256([]byte(a.url + "\n" + word))
+	return filepath.Join(a.cacheDir, hex.EncodeToString(sum[:12])+".json")
+}
+
+func (a *apiDefinitions) Define(word string) ([]Definition, error) {
+	word = strings.ToLower(word)
+	path := a.cachePath(word)
+	if data, err := ioutil.ReadFile(path); err == nil {
+		var defs []Definition
+		if err := json.Unmarshal(data, &defs); err == nil {
+			return defs, nil
+		}
+	}
+	defs, err := a.fetch(word)
+	if err != nil {
+		return nil, err
+	}
+	// The definitions are still shown if they can not be cached.
+	if err := os.MkdirAll(a.cacheDir, 0755); err == nil {
+		data, _ := json.Marshal(defs)
+		ioutil.WriteFile(path, data, 0644)
+	}
+	return defs, nil
+}
+
+// Method to fetch the definitions of a word from the API. A word the API does
+// not know has no definition.
+func (a *apiDefinitions) fetch(word string) ([]Definition, error) {
+	resp, err := a.client.Get(strings.ReplaceAll(a.url, "{word}", url.PathEscape(word)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return []Definition{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var entries []apiEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	defs := []Definition{}
+	for _, entry := range entries {
+		for _, meaning := range entry.Meanings {
+			for _, d := range meaning.Definitions {
+				defs = append(defs, Definition{PartOfSpeech: meaning.PartOfSpeech,
+					Text: d.Definition})
+			}
+		}
+	}
+	return defs, nil
+}
+
+// Method to format the definitions of a word for the terminal, at most
+// maxDefinitions of them, e.g. "cat (noun): feline mammal".
+func formatDefinitions(word string, defs []Definition) string {
+	if len(defs) > maxDefinitions {
+		defs = defs[:maxDefinitions]
+	}
+	lines := make([]string, len(defs))
+	for i, def := range defs {
+		if def.PartOfSpeech != "" {
+			lines[i] = fmt.Sprintf("%s (%s): %s", word, def.PartOfSpeech, def.Text)
+		} else {
+			lines[i] = fmt.Sprintf("%s: %s", word, def.Text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Method to show the definition of the word of a game which ended, if the
+// definitions are on.
+func printDefinition(word string) {
+	if definitions == nil || word == "" {
+		return
+	}
+	defs, err := definitions.Define(word)
+	if err != nil {
+		fmt.Println("Unable to look up the definition of", word, ", error ", err)
+		return
+	}
+	if len(defs) == 0 {
+		fmt.Println("No definition found for", word)
+		return
+	}
+	fmt.Println(formatDefinitions(word, defs))
+}
+
+// Method to get the source of the definitions set by the flags, nil if the
+// definitions are off. Returns an error if the definitions file can not be
+// loaded.
+func definitionsFromFlags() (DefinitionSource, error) {
+	if *definitionsFile != "" && *definitionsAPI != "" {
+		return nil, configError("please set only one of --definitions_file and --definitions_api")
+	}
+	if *definitionsFile != "" {
+		defs, err := loadDefinitions(*definitionsFile)
+		if err != nil {
+			return nil, configError("unable to load the definitions %s: %w", *definitionsFile, err)
+		}
+		return defs, nil
+	}
+	if *definitionsAPI == "" {
+		return nil, nil
+	}
+	if !strings.Contains(*definitionsAPI, "{word}") {
+		return nil, configError("--definitions_api must contain {word}, e.g. " +
+			"https://api.dictionaryapi.dev/api/v2/entries/en/{word}")
+	}
+	cacheDir := *definitionsCache
+	if cacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, configError("unable to find the cache directory, please set "+
+				"--definitions_cache: %w", err)
+		}
+		cacheDir = filepath.Join(dir, "wordguess", "definitions")
+	}
+	return newAPIDefinitions(*definitionsAPI, cacheDir), nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+type DefinitionTestSuite struct {
+	suite.Suite
+}
+
+func (s *DefinitionTestSuite) TestWordNet() {
+	path := filepath.Join(s.T().TempDir(), "data.noun")
+	data := "  1 This software and database is being provided to you, the LICENSEE\n" +
+		"02121620 05 n 02 cat 0 true_cat 0 012 @ 02120997 n 0000 | feline mammal " +
+		"usually having thick soft fur; \"cats purr\"\n" +
+		"02985606 06 n 01 Cat 0 001 @ 03082979 n 0000 | a whip with nine knotted cords\n"
+	assert.Nil(s.T(), ioutil.WriteFile(path, []byte(data), 0644))
+	defs, err := loadDefinitions(path)
+	assert.Nil(s.T(), err)
+	cat, _ := defs.Define("CAT")
+	assert.Equal(s.T(), []Definition{
+		{PartOfSpeech: "noun", Text: "feline mammal usually having thick soft fur"},
+		{PartOfSpeech: "noun", Text: "a whip with nine knotted cords"},
+	}, cat)
+	trueCat, _ := defs.Define("true cat")
+	assert.Len(s.T(), trueCat, 1)
+	none, _ := defs.Define("dog")
+	assert.Empty(s.T(), none)
+}
+
+func (s *DefinitionTestSuite) TestTabSeparated() {
+	path := filepath.Join(s.T().TempDir(), "defs.txt")
+	assert.Nil(s.T(), ioutil.WriteFile(path, []byte("Fox\ta wild canine\n"), 0644))
+	defs, err := loadDefinitions(path)
+	assert.Nil(s.T(), err)
+	fox, _ := defs.Define("fox")
+	assert.Equal(s.T(), []Definition{{Text: "a wild canine"}}, fox)
+
+	_, err = loadDefinitions(filepath.Join(s.T().TempDir(), "missing"))
+	assert.NotNil(s.T(), err)
+}
+
+func (s *DefinitionTestSuite) TestAPI() {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/entries/cat" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `[{"word":"cat","meanings":[{"partOfSpeech":"noun",`+
+			`"definitions":[{"definition":"A small domesticated carnivorous mammal."}]}]}]`)
+	}))
+	defer server.Close()
+	api := newAPIDefinitions(server.URL+"/entries/{word}", s.T().TempDir())
+
+	expected := []Definition{{PartOfSpeech: "noun", Text: "A small domesticated carnivorous mammal."}}
+	defs, err := api.Define("Cat")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), expected, defs)
+	// The definitions are cached, also for the unknown words.
+	defs, _ = api.Define("cat")
+	assert.Equal(s.T(), expected, defs)
+	defs, err = api.Define("zzz")
+	assert.Nil(s.T(), err)
+	assert.Empty(s.T(), defs)
+	api.Define("zzz")
+	assert.Equal(s.T(), 2, requests)
+
+	// Another API does not use the cache of the first one.
+	other := newAPIDefinitions(server.URL+"/other/{word}", api.cacheDir)
+	defs, _ = other.Define("cat")
+	assert.Empty(s.T(), defs)
+	assert.Equal(s.T(), 3, requests)
+}
+
+func (s *DefinitionTestSuite) TestAPIError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	api := newAPIDefinitions(server.URL+"/{word}", s.T().TempDir())
+	_, err := api.Define("cat")
+	assert.NotNil(s.T(), err)
+}
+
+func (s *DefinitionTestSuite) TestFormat() {
+	defs := []Definition{
+		{PartOfSpeech: "noun", Text: "feline mammal"},
+		{Text: "a whip"},
+		{Text: "third"},
+		{Text: "fourth"},
+	}
+	assert.Equal(s.T(), "cat (noun): feline mammal\ncat: a whip\ncat: third",
+		formatDefinitions("cat", defs))
+}
+
+func TestDefinitionTestSuite(t *testing.T) {
+	suite.Run(t, new(DefinitionTestSuite))
+}
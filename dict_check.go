@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/gob"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+var (
+	dictionaryIndex = flag.String("dictionary_index", "",
+		"Absolute path of a binary index written by \"dict check\". It is loaded "+
+			"instead of the dictionary file, which is much faster for large "+
+			"dictionaries.")
+	writeDictionaryIndex = flag.String("write_dictionary_index", "",
+		"Absolute path to write the binary index of the dictionary to, used by "+
+			"\"dict check\".")
+)
+
+const (
+	// Version of the binary index format. Indexes written with another version
+	// are refused, they must be written again.
+	indexFormatVersion = 2
+	// Max width of a bar of the length histogram.
+	histogramWidth = 40
+)
+
+// Binary index of a dictionary, encoded with gob. It holds the words exactly
+// as the dictionary keeps them (validated, split by length and sorted) along
+// with their pattern index, so loading it skips all the preprocessing.
+type dictionaryIndexFile struct {
+	Version int
+	// Alphabet, phrase mode, punctuation and case folding the index was built
+	// with. The index can only be loaded with the same flags.
+	Alphabet    string
+	Phrases     bool
+	Punctuation string
+	Folding     CaseFolding
+	Metadata    DictionaryMetadata
+	Lengths     []indexedLength
+	// Clues of the words, by word. Indexes written before clues were
+	// supported have none.
+	Hints map[string]string
+	// Frequencies of the words, by word. Indexes written before frequencies
+	// were supported have none.
+	Frequencies map[string]float64
+}
+
+// Words of a single length and their pattern index, see wordIndex.
+type indexedLength struct {
+	Length     int
+	Words      []string
+	ByPosition [][]int
+	Letters    [][]rune
+	Offsets    [][]int
+}
+
+// Method to write the binary index of a dictionary.
+func saveDictionaryIndex(path string, d *Dictionary, alphabet string) error {
+	file := dictionaryIndexFile{
+		Version:     indexFormatVersion,
+		Alphabet:    alphabet,
+		Phrases:     d.alphabet.Phrases,
+		Punctuation: d.alphabet.Punctuation,
+		Folding:     d.alphabet.Folding,
+		Metadata:    d.metadata,
+		Hints:       d.hints,
+		Frequencies: d.frequencies,
+	}
+	for _, length := range d.Lengths() {
+		idx, ok := d.index[length]
+		if !ok {
+			return fmt.Errorf("words of length %d are not indexed", length)
+		}
+		file.Lengths = append(file.Lengths, indexedLength{
+			Length:     length,
+			Words:      idx.words,
+			ByPosition: idx.byPosition,
+			Letters:    idx.letters,
+			Offsets:    idx.offsets,
+		})
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(file); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Method to load a dictionary from its binary index. The alphabet, the phrase
+// mode, the punctuation and the case folding must be the ones the index was
+// built with. In compact mode the words are packed the same way as when
+// loading the dictionary file.
+func loadDictionaryIndex(path, alphabet string, phrases bool, punctuation string,
+	folding CaseFolding, compact, strict bool) (*Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var file dictionaryIndexFile
+	if err := gob.NewDecoder(f).Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid dictionary index %s: %v", path, err)
+	}
+	switch {
+	case file.Version != indexFormatVersion:
+		return nil, fmt.Errorf("dictionary index %s has version %d, expected %d: "+
+			"please write it again", path, file.Version, indexFormatVersion)
+	case file.Alphabet != alphabet || file.Phrases != phrases ||
+		file.Punctuation != punctuation || file.Folding != folding:
+		return nil, fmt.Errorf("dictionary index %s was built with --alphabet=%q "+
+			"--phrases=%v --punctuation=%q --case_folding=%s", path, file.Alphabet,
+			file.Phrases, file.Punctuation, file.Folding)
+	}
+	if missing := file.Metadata.MissingFields(); strict && len(missing) > 0 {
+		return nil, fmt.Errorf("dictionary index %s is missing the required metadata: %s",
+			path, strings.Join(missing, ", "))
+	}
+	a := NewAlphabet(alphabet)
+	a.Phrases = phrases
+	a.Punctuation = punctuation
+	a.Folding = folding
+	d := &Dictionary{
+		words:       make(map[int][]string),
+		index:       make(map[int]*wordIndex),
+		alphabet:    a,
+		metadata:    file.Metadata,
+		hints:       file.Hints,
+		frequencies: file.Frequencies,
+		report:      DictionaryReport{Lengths: make(map[int]int)},
+	}
+	for _, l := range file.Lengths {
+		if len(l.ByPosition) != l.Length || len(l.Letters) != l.Length ||
+			len(l.Offsets) != l.Length {
+			return nil, fmt.Errorf("invalid dictionary index %s: bad index of length %d",
+				path, l.Length)
+		}
+		idx := &wordIndex{
+			length:     l.Length,
+			words:      l.Words,
+			chars:      make([][]rune, len(l.Words)),
+			byPosition: l.ByPosition,
+			letters:    l.Letters,
+			offsets:    l.Offsets,
+		}
+		for i, word := range l.Words {
+			idx.chars[i] = []rune(word)
+		}
+		idx.positions = newLetterPositions(l.Length, idx.chars)
+		idx.cache = newPartitionCache()
+		d.words[l.Length] = l.Words
+		d.index[l.Length] = idx
+		d.report.Words += len(l.Words)
+		d.report.Lengths[l.Length] = len(l.Words)
+	}
+	if compact {
+		d.packed = make(map[int][]packedWord)
+		for length, words := range d.words {
+			// The words are already sorted.
+			if packed, ok := packWords(words); ok {
+				d.packed[length] = packed
+				delete(d.words, length)
+				delete(d.index, length)
+			}
+		}
+	}
+	d.indexAnagrams()
+	return d, nil
+}
+
+// Word of a word list reported when it is loaded or checked.
+type ReportedWord struct {
+	// Line of the word, starting at 1.
+	Line int
+	// Word as written in the list for an invalid word, once its case is
+	// folded for a duplicate.
+	Word string
+	// Line where the word was first seen, for duplicates.
+	FirstLine int
+}
+
+// Report of the words of a list skipped when it is loaded, see
+// Dictionary.Report, or checked by "dict check".
+type DictionaryReport struct {
+	// Number of non empty lines.
+	Words int
+	// Words with characters outside the alphabet, by line.
+	Invalid []ReportedWord
+	// Words seen more than once, once their case is folded, by line. Only the
+	// first one is kept.
+	Duplicates []ReportedWord
+	// Number of valid words by length, without the duplicates. The length
+	// limits of the game are not applied, see AvailableLengths.
+	Lengths map[int]int
+	// Number of valid words excluded by the word filter of the dictionary,
+	// see WordFilter. They are still counted in Lengths.
+	Filtered int
+	// Number of valid words excluded as uncommon, see --common_words. They
+	// are still counted in Lengths.
+	Uncommon int
+}
+
+// Method to get the report of the word list the dictionary was built from:
+// the words skipped because they are invalid or duplicated, and the number of
+// words kept by length. A dictionary loaded from an index only has the
+// numbers of words, the skipped ones were reported when it was written.
+func (d *Dictionary) Report() DictionaryReport {
+	return d.report
+}
+
+// Method to check the lines of a dictionary file. Empty lines are ignored, and
+// so are the clues and the frequencies of the words.
+func checkDictionary(lines []string, alphabet Alphabet) DictionaryReport {
+	words, _ := splitFrequencies(lines)
+	words, _ = splitHints(words)
+	_, report := buildLenBasedDictionary(words, alphabet)
+	return report
+}
+
+// Method to format the report of a dictionary check.
+func formatDictionaryReport(report DictionaryReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d words, %d invalid, %d duplicates\n", report.Words,
+		len(report.Invalid), len(report.Duplicates))
+	for _, w := range report.Invalid {
+		fmt.Fprintf(&b, "  line %d: invalid word %q\n", w.Line, w.Word)
+	}
+	for _, w := range report.Duplicates {
+		fmt.Fprintf(&b, "  line %d: duplicate word %q (first seen on line %d)\n",
+			w.Line, w.Word, w.FirstLine)
+	}
+	b.WriteString(formatHistogram(report.Lengths))
+	return b.String()
+}
+
+// Method to format the number of words by length as a histogram.
+func formatHistogram(lengths map[int]int) string {
+	var keys []int
+	maxCount := 0
+	for length, count := range lengths {
+		keys = append(keys, length)
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+	sort.Ints(keys)
+	var b strings.Builder
+	b.WriteString("Words per length:\n")
+	for _, length := range keys {
+		count := lengths[length]
+		bar := count * histogramWidth / maxCount
+		if bar == 0 {
+			bar = 1
+		}
+		fmt.Fprintf(&b, "  %3d | %-*s %d\n", length, histogramWidth,
+			strings.Repeat("#", bar), count)
+	}
+	return b.String()
+}
+
+// Driver method for the "dict" subcommand. "dict check" reports the invalid
+// and duplicate words of the dictionary with a histogram of the word lengths,
+// and writes the binary index if --write_dictionary_index is set.
+// Returns an error if the dictionary has invalid or duplicate words.
+func StartDictTool(args []string) error {
+	if len(args) != 1 || args[0] != "check" {
+		return usageError("hangman [flags] dict check")
+	}
+	data, err := readDictionary(*dictionaryFile)
+	if err != nil {
+		return configError("unable to read file %s: %w", dictionaryName(*dictionaryFile), err)
+	}
+	alphabet := NewAlphabet(dictionaryAlphabet())
+	alphabet.Phrases = *phraseMode
+	if err := validatePunctuation(*punctuation); err != nil {
+		return configError("invalid --punctuation: %w", err)
+	}
+	alphabet.Punctuation = *punctuation
+	folding, err := ParseCaseFolding(*caseFolding)
+	if err != nil {
+		return configError("invalid --case_folding: %w", err)
+	}
+	alphabet.Folding = folding
+	report := checkDictionary(strings.Split(string(data), "\n"), alphabet)
+	fmt.Println("Dictionary", dictionaryName(*dictionaryFile))
+	fmt.Print(formatDictionaryReport(report))
+	if *writeDictionaryIndex != "" {
+		metadata, err := loadDictionaryMetadata(*dictionaryMetadataFile,
+			*dictionaryFile, *strictDictionary)
+		if err != nil {
+			return configError("unable to load the dictionary metadata: %w", err)
+		}
+		d := newDictionary(strings.Split(string(data), "\n"), alphabet, metadata, false)
+		if err := saveDictionaryIndex(*writeDictionaryIndex, d, dictionaryAlphabet()); err != nil {
+			return fmt.Errorf("unable to write the dictionary index: %w", err)
+		}
+		fmt.Println("Index written to ", *writeDictionaryIndex)
+	}
+	if len(report.Invalid) > 0 || len(report.Duplicates) > 0 {
+		return fmt.Errorf("the dictionary has %d invalid and %d duplicate words",
+			len(report.Invalid), len(report.Duplicates))
+	}
+	return nil
+}
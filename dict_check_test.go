@@ -0,0 +1,87 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"testing"
+)
+
+type DictCheckTestSuite struct {
+	suite.Suite
+}
+
+func (s *DictCheckTestSuite) TestReport() {
+	lines := []string{"last", "l4st", "", "Fast", "fast", "bets\r", "cod"}
+	report := checkDictionary(lines, NewAlphabet(""))
+	assert.Equal(s.T(), 6, report.Words)
+	assert.Equal(s.T(), []ReportedWord{{Line: 2, Word: "l4st"}}, report.Invalid)
+	assert.Equal(s.T(), []ReportedWord{{Line: 5, Word: "fast", FirstLine: 4}},
+		report.Duplicates)
+	assert.Equal(s.T(), map[int]int{3: 1, 4: 3}, report.Lengths)
+	assert.Equal(s.T(), "Words per length:\n"+
+		"    3 | #############                            1\n"+
+		"    4 | ######################################## 3\n",
+		formatHistogram(report.Lengths))
+}
+
+func (s *DictCheckTestSuite) TestLoadReport() {
+	lines := []string{"last", "l4st", "Fast\r", "", "fast", "bets", "at"}
+	d := newDictionary(lines, NewAlphabet(""), DictionaryMetadata{}, false)
+	// The invalid and duplicate words are not played.
+	assert.Equal(s.T(), []string{"bets", "fast", "last"}, d.Words(4))
+	assert.Equal(s.T(), 4, d.WordCount())
+	assert.Equal(s.T(), DictionaryReport{
+		Words:      6,
+		Invalid:    []ReportedWord{{Line: 2, Word: "l4st"}},
+		Duplicates: []ReportedWord{{Line: 5, Word: "fast", FirstLine: 3}},
+		Lengths:    map[int]int{2: 1, 4: 3},
+	}, d.Report())
+
+	path := filepath.Join(s.T().TempDir(), "dict.idx")
+	assert.Nil(s.T(), saveDictionaryIndex(path, d, ""))
+	loaded, err := loadDictionaryIndex(path, "", false, "", FoldLower, false, false)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), DictionaryReport{Words: 4, Lengths: map[int]int{2: 1, 4: 3}},
+		loaded.Report())
+}
+
+func (s *DictCheckTestSuite) TestIndexRoundTrip() {
+	words := []string{"last", "fast", "bets", "code", "at"}
+	metadata := DictionaryMetadata{License: "MIT", Attribution: "Us"}
+	d := newDictionary(words, NewAlphabet(""), metadata, false)
+	path := filepath.Join(s.T().TempDir(), "dict.idx")
+	assert.Nil(s.T(), saveDictionaryIndex(path, d, ""))
+
+	loaded, err := loadDictionaryIndex(path, "", false, "", FoldLower, false, true)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), d.Lengths(), loaded.Lengths())
+	assert.Equal(s.T(), d.Words(4), loaded.Words(4))
+	assert.Equal(s.T(), metadata, loaded.Metadata())
+	assert.Equal(s.T(), d.index[4], loaded.index[4])
+
+	compact, err := loadDictionaryIndex(path, "", false, "", FoldLower, true, false)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), d.Words(4), compact.Words(4))
+	assert.Equal(s.T(), 0, len(compact.index))
+
+	// The index only loads with the flags it was built with.
+	_, err = loadDictionaryIndex(path, "abc", false, "", FoldLower, false, false)
+	assert.NotNil(s.T(), err)
+	_, err = loadDictionaryIndex(path, "", true, "", FoldLower, false, false)
+	assert.NotNil(s.T(), err)
+	_, err = loadDictionaryIndex(path, "", false, "", FoldNone, false, false)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *DictCheckTestSuite) TestStrictIndex() {
+	d := newDictionary([]string{"last"}, NewAlphabet(""), DictionaryMetadata{}, false)
+	path := filepath.Join(s.T().TempDir(), "dict.idx")
+	assert.Nil(s.T(), saveDictionaryIndex(path, d, ""))
+	_, err := loadDictionaryIndex(path, "", false, "", FoldLower, false, true)
+	assert.NotNil(s.T(), err)
+}
+
+func TestDictCheckTestSuite(t *testing.T) {
+	suite.Run(t, new(DictCheckTestSuite))
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	dictionaryMetadataFile = flag.String("dictionary_metadata", "",
+		"Absolute path of the JSON file with the license and attribution of the "+
+			"dictionary. Defaults to the dictionary path followed by \".meta.json\", "+
+			"which is optional.")
+	strictDictionary = flag.Bool("strict_dictionary", false,
+		"Refuse to load a dictionary whose metadata does not have a license and "+
+			"an attribution.")
+	minWordLength = flag.Int("min_word_length", 1,
+		"Shortest words kept from the dictionary. Games can not be played with "+
+			"shorter words.")
+	maxWordLength = flag.Int("max_word_length", 0,
+		"Longest words kept from the dictionary, no limit if zero. Games can not "+
+			"be played with longer words.")
+)
+
+// Metadata of a dictionary, read from a JSON file next to it. It records where
+// the words come from and under which terms they can be distributed.
+type DictionaryMetadata struct {
+	// Name of the dictionary.
+	Name string `json:"name"`
+	// Where the dictionary was taken from, usually a URL.
+	Source string `json:"source"`
+	// License the dictionary is distributed under, e.g. "CC-BY-SA-4.0".
+	License string `json:"license"`
+	// Attribution required by the license.
+	Attribution string `json:"attribution"`
+}
+
+// Method to get the names of the required fields which are empty.
+// The license and the attribution are required in strict mode.
+func (m DictionaryMetadata) MissingFields() []string {
+	var missing []string
+	if strings.TrimSpace(m.License) == "" {
+		missing = append(missing, "license")
+	}
+	if strings.TrimSpace(m.Attribution) == "" {
+		missing = append(missing, "attribution")
+	}
+	return missing
+}
+
+// Dictionary of words loaded in memory.
+// A dictionary is never modified once it is built, so it can be shared by any
+// number of goroutines without locking. InitGame replaces the current
+// dictionary as a whole, and every game keeps using the dictionary it was
+// created with.
+type Dictionary struct {
+	// Map of all the words. The key is the length of the word (in characters,
+	// not bytes) and value is the sorted list of words matching that length.
+	words map[int][]string
+	// Words packed in compact mode, by length. A length is either in words or
+	// in packed, never in both.
+	packed map[int][]packedWord
+	// Index of the words used for pattern based filtering, by length.
+	index map[int]*wordIndex
+	// Words by their sorted letters, by length, see Anagrams.
+	anagrams map[int]anagramIndex
+	// Alphabet of the words and the guesses.
+	alphabet Alphabet
+	// License and attribution of the words.
+	metadata DictionaryMetadata
+	// Clues of the words which have one, by word. Nil if no word has a clue.
+	hints map[string]string
+	// Frequencies of the words which have one, by word, see
+	// --common_words. Nil if no word has a frequency.
+	frequencies map[string]float64
+	// Files the dictionary was loaded from, nil if it was built from a list
+	// of words. See Reload.
+	source *dictionarySource
+	// Words skipped while building the dictionary, see Report.
+	report DictionaryReport
+}
+
+// Dictionary used for the new games.
+var currentDict atomic.Pointer[Dictionary]
+
+// Method to get the dictionary used for the new games. An empty dictionary is
+// returned if InitGame was not called yet.
+func currentDictionary() *Dictionary {
+	if d := currentDict.Load(); d != nil {
+		return d
+	}
+	return &Dictionary{}
+}
+
+// Method to build a dictionary from a list of words.
+// Words which are not valid for the alphabet are discarded. In compact mode,
+// the lengths whose words are all made of the letters a-z are kept packed.
+// The words can be followed by a clue, see splitHint, and by a frequency, see
+// splitFrequencies.
+func newDictionary(wordList []string, alphabet Alphabet, metadata DictionaryMetadata,
+	compact bool) *Dictionary {
+	d := &Dictionary{alphabet: alphabet, metadata: metadata}
+	wordList, d.frequencies = splitFrequencies(wordList)
+	wordList, d.hints = splitHints(wordList)
+	if d.frequencies != nil {
+		// Like the clues, the frequencies are found by the folded words.
+		frequencies := make(map[string]float64, len(d.frequencies))
+		for word, frequency := range d.frequencies {
+			frequencies[alphabet.FoldWord(word)] = frequency
+		}
+		d.frequencies = frequencies
+	}
+	if d.hints != nil {
+		// The clues are found by the words as the dictionary keeps them.
+		hints := make(map[string]string, len(d.hints))
+		for word, clue := range d.hints {
+			hints[alphabet.FoldWord(word)] = clue
+		}
+		d.hints = hints
+	}
+	// Sanitize the strings in the dictionary and also do preprocessing to build
+	// a map where key is the length of the word and value is the slice of all
+	// words of that length.
+	d.words, d.report = buildLenBasedDictionary(wordList, alphabet)
+	if compact {
+		d.packed = make(map[int][]packedWord)
+		for length, words := range d.words {
+			sorted := make([]string, len(words))
+			copy(sorted, words)
+			sort.Strings(sorted)
+			if packed, ok := packWords(sorted); ok {
+				d.packed[length] = packed
+				delete(d.words, length)
+			}
+		}
+	}
+	// Build the index used for pattern based filtering. The index keeps the
+	// words sorted, so the same sorted list is used for the dictionary map.
+	d.index = buildDictionaryIndex(d.words)
+	for length, idx := range d.index {
+		d.words[length] = idx.words
+	}
+	d.indexAnagrams()
+	return d
+}
+
+// Method to get all the words of a length, in sorted order.
+// The returned slice must not be modified. Packed words are unpacked into a new
+// slice on every call.
+func (d *Dictionary) Words(length int) []string {
+	if packed, ok := d.packed[length]; ok {
+		return unpackWords(packed, length)
+	}
+	return d.words[length]
+}
+
+// Method to find the words matching a mask like "_a__e", where "_" is a
+// character yet to be guessed, and containing none of the excluded characters.
+// The mask follows the rules of the game: a revealed character is revealed at
+// all its positions, so it is never behind a "_". The characters are folded
+// like the guesses of the games, see Alphabet.Fold.
+// Returns the matching words in sorted order, none if the mask has no word of
+// its length.
+func (d *Dictionary) Match(mask string, excluded []rune) []string {
+	pattern := []rune(mask)
+	for i, char := range pattern {
+		if char == DefaultPatternFormat.Blank {
+			pattern[i] = emptyChar
+		} else {
+			pattern[i] = d.alphabet.Fold(char)
+		}
+	}
+	folded := make([]rune, len(excluded))
+	for i, char := range excluded {
+		folded[i] = d.alphabet.Fold(char)
+	}
+	return d.matchPattern(pattern, folded)
+}
+
+// Method to find the words matching a pattern of the engine, see
+// wordIndex.match. Packed words are matched one by one.
+// Returns the matching words in sorted order.
+func (d *Dictionary) matchPattern(pattern []rune, excluded []rune) []string {
+	if idx, ok := d.index[len(pattern)]; ok {
+		return idx.match(pattern, excluded)
+	}
+	var result []string
+	for _, word := range d.Words(len(pattern)) {
+		if matchesPattern([]rune(word), pattern, excluded) {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+// Method to get the number of words of a length.
+func (d *Dictionary) Count(length int) int {
+	if packed, ok := d.packed[length]; ok {
+		return len(packed)
+	}
+	return len(d.words[length])
+}
+
+// Method to check if the dictionary has any word of the given length.
+func (d *Dictionary) HasLength(length int) bool {
+	_, inWords := d.words[length]
+	_, inPacked := d.packed[length]
+	return inWords || inPacked
+}
+
+// Method to get the sorted list of word lengths in the dictionary.
+func (d *Dictionary) Lengths() []int {
+	var lengths []int
+	for length, words := range d.words {
+		if len(words) > 0 {
+			lengths = append(lengths, length)
+		}
+	}
+	for length, packed := range d.packed {
+		if len(packed) > 0 {
+			lengths = append(lengths, length)
+		}
+	}
+	sort.Ints(lengths)
+	return lengths
+}
+
+// Method to get the sorted list of word lengths a game can be played with:
+// the lengths of the dictionary allowed by --min_word_length and
+// --max_word_length.
+func (d *Dictionary) AvailableLengths() []int {
+	var lengths []int
+	for _, length := range d.Lengths() {
+		if wordLengthAllowed(length) {
+			lengths = append(lengths, length)
+		}
+	}
+	return lengths
+}
+
+// Method to remove the words whose length is not between min and max (no
+// limit if max is zero). Must only be called while the dictionary is built.
+func (d *Dictionary) limitLengths(min, max int) {
+	for length := range d.words {
+		if length < min || (max > 0 && length > max) {
+			delete(d.words, length)
+			delete(d.index, length)
+			delete(d.anagrams, length)
+		}
+	}
+	for length := range d.packed {
+		if length < min || (max > 0 && length > max) {
+			delete(d.packed, length)
+			delete(d.anagrams, length)
+		}
+	}
+}
+
+// Method to format a sorted list of word lengths for the player, with the
+// consecutive lengths as ranges, e.g. "3-7, 9". Returns "none" for an empty
+// list.
+func formatLengths(lengths []int) string {
+	if len(lengths) == 0 {
+		return "none"
+	}
+	var parts []string
+	for i := 0; i < len(lengths); {
+		j := i
+		for j+1 < len(lengths) && lengths[j+1] == lengths[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, strconv.Itoa(lengths[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", lengths[i], lengths[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Method to check if a game can be played with a word length, see
+// --min_word_length and --max_word_length.
+func wordLengthAllowed(length int) bool {
+	return length >= *minWordLength && (*maxWordLength <= 0 || length <= *maxWordLength)
+}
+
+// Method to check that the word length limits are consistent.
+func validateWordLengthLimits(min, max int) error {
+	if min < 1 {
+		return fmt.Errorf("--min_word_length must be at least 1, got %d", min)
+	}
+	if max > 0 && max < min {
+		return fmt.Errorf("--max_word_length (%d) must not be less than "+
+			"--min_word_length (%d)", max, min)
+	}
+	return nil
+}
+
+// Method to get the clue of a word.
+// Returns false if the word has no clue.
+func (d *Dictionary) Hint(word string) (string, bool) {
+	clue, ok := d.hints[word]
+	return clue, ok
+}
+
+// Method to check if any word of the dictionary has a clue.
+func (d *Dictionary) HasHints() bool {
+	return len(d.hints) > 0
+}
+
+// Method to get the license and attribution of the dictionary.
+func (d *Dictionary) Metadata() DictionaryMetadata {
+	return d.metadata
+}
+
+// Method to get the number of words in the dictionary.
+func (d *Dictionary) WordCount() int {
+	count := 0
+	for _, words := range d.words {
+		count += len(words)
+	}
+	for _, packed := range d.packed {
+		count += len(packed)
+	}
+	return count
+}
+
+// Method to read the metadata of a dictionary file.
+// Params:
+// path: Path of the metadata file. If empty, the dictionary path followed by
+//   ".meta.json" is used, and a missing file is not an error.
+// dictionaryPath: Path of the dictionary file.
+// strict: If true, metadata without a license or an attribution is an error.
+//
+// Returns the metadata, or an error if it can not be read or is incomplete in
+// strict mode.
+func loadDictionaryMetadata(path, dictionaryPath string, strict bool) (DictionaryMetadata, error) {
+	var metadata DictionaryMetadata
+	optional := path == ""
+	if optional {
+		path = dictionaryPath + ".meta.json"
+	}
+	var data []byte
+	var err error
+	if optional && dictionaryPath == "" {
+		// The embedded dictionaries have no metadata file, their metadata is
+		// built in.
+		metadata = embeddedMetadata()
+		err = os.ErrNotExist
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil && !(optional && errors.Is(err, os.ErrNotExist)) {
+		return metadata, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &metadata); err != nil {
+			return metadata, fmt.Errorf("invalid metadata file %s: %v", path, err)
+		}
+	}
+	if missing := metadata.MissingFields(); strict && len(missing) > 0 {
+		return metadata, fmt.Errorf("dictionary %s is missing the required metadata: %s",
+			dictionaryName(dictionaryPath), strings.Join(missing, ", "))
+	}
+	return metadata, nil
+}
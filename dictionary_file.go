@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"io/ioutil"
+)
+
+// English word list used when no dictionary file is given, so the game runs
+// without any file next to the binary.
+//
+//go:embed dictionary.txt
+var embeddedDictionary []byte
+
+// Metadata of the embedded English word list, see DictionaryMetadata.
+var embeddedDictionaryMetadata = DictionaryMetadata{
+	Name:        "Basic English",
+	Source:      "Basic English word list of C. K. Ogden (1930)",
+	License:     "Public domain",
+	Attribution: "Basic English, C. K. Ogden",
+}
+
+// Metadata of the word lists written for the built in language packs.
+var builtinLanguageMetadata = DictionaryMetadata{
+	Source:      "https://github.com/hackeracc/WordGuess",
+	License:     "CC0-1.0",
+	Attribution: "Word list of the WordGuess contributors",
+}
+
+// First bytes of every gzip file.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Method to read the contents of a dictionary file, decompressing it if it is
+// gzip compressed (whatever its name). The word list of the language of the
+// game is returned if the path is empty, see --lang.
+func readDictionary(path string) ([]byte, error) {
+	if path == "" {
+		if words := currentLanguage().Dictionary; words != nil {
+			return words, nil
+		}
+		return embeddedDictionary, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return gunzipDictionary(data)
+}
+
+// Method to get the metadata of the word list returned by readDictionary for
+// an empty path: the one of the language of the game, or of the embedded
+// English word list.
+func embeddedMetadata() DictionaryMetadata {
+	if pack := currentLanguage(); pack.Dictionary != nil {
+		return pack.Metadata
+	}
+	return embeddedDictionaryMetadata
+}
+
+// Method to decompress the contents of a word list if it is gzip compressed.
+func gunzipDictionary(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// Method to get the name of a dictionary path for the messages to the player.
+func dictionaryName(path string) string {
+	if path == "" && currentLanguage().Dictionary != nil {
+		return "embedded " + currentLanguage().Name
+	}
+	if path == "" {
+		return "embedded"
+	}
+	return path
+}
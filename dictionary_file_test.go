@@ -0,0 +1,62 @@
+package main
+
+import (
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type DictionaryFileTestSuite struct {
+	suite.Suite
+}
+
+func (s *DictionaryFileTestSuite) TestEmbedded() {
+	data, err := readDictionary("")
+	assert.Nil(s.T(), err)
+	onDisk, err := ioutil.ReadFile("dictionary.txt")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), onDisk, data)
+	assert.Equal(s.T(), "embedded", dictionaryName(""))
+}
+
+func (s *DictionaryFileTestSuite) TestGzip() {
+	dir := s.T().TempDir()
+	plain := filepath.Join(dir, "words.txt")
+	assert.Nil(s.T(), ioutil.WriteFile(plain, []byte("last\nfast\n"), 0644))
+
+	// Gzip is detected from the contents, not from the name.
+	compressed := filepath.Join(dir, "words")
+	f, err := os.Create(compressed)
+	assert.Nil(s.T(), err)
+	w := gzip.NewWriter(f)
+	w.Write([]byte("last\nfast\n"))
+	assert.Nil(s.T(), w.Close())
+	assert.Nil(s.T(), f.Close())
+
+	for _, path := range []string{plain, compressed} {
+		data, err := readDictionary(path)
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), "last\nfast\n", string(data))
+	}
+
+	_, err = readDictionary(filepath.Join(dir, "missing.txt"))
+	assert.ErrorIs(s.T(), err, os.ErrNotExist)
+}
+
+func (s *DictionaryFileTestSuite) TestEmbeddedMetadata() {
+	// The metadata of the embedded dictionary is built in, so it can be used
+	// in strict mode.
+	metadata, err := loadDictionaryMetadata("", "", false)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), embeddedDictionaryMetadata, metadata)
+	_, err = loadDictionaryMetadata("", "", true)
+	assert.Nil(s.T(), err)
+}
+
+func TestDictionaryFileTestSuite(t *testing.T) {
+	suite.Run(t, new(DictionaryFileTestSuite))
+}
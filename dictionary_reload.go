@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"net/http"
+	"sync"
+)
+
+// Files and options a dictionary is loaded from, so that it can be loaded
+// again once the files are updated.
+type dictionarySource struct {
+	// Prebuilt index of the words, see --dictionary_index. The other files
+	// are not used if set.
+	index string
+	// Word list and its metadata, see --dictionary and --dictionary_metadata.
+	// The word list of the language of the game is used if file is empty.
+	file         string
+	metadataFile string
+	// Backend of the words, see WordSource. The words are read from file (a
+	// path or a URL) if nil.
+	words       WordSource
+	alphabet    string
+	phrases     bool
+	punctuation string
+	folding     CaseFolding
+	compact     bool
+	strict      bool
+	// Word length limits, see --min_word_length and --max_word_length.
+	minLength int
+	maxLength int
+	// Lists of the words filter, see WordFilter.
+	blocklist      string
+	allowlist      string
+	familyFriendly bool
+	// Percentage of the most frequent words kept, see --common_words.
+	commonWords int
+}
+
+// Method to get the name of the source for the messages to the player.
+func (source dictionarySource) name() string {
+	if _, ok := source.words.(FileSource); source.file == "" && source.words != nil && !ok {
+		return "the word source"
+	}
+	return dictionaryName(source.file)
+}
+
+// Serializes the reloads of the current dictionary, so that a slow reload
+// never replaces the dictionary of a more recent one.
+var reloadMu sync.Mutex
+
+// Method to get the source of the dictionary given by the flags.
+func dictionarySourceFromFlags() dictionarySource {
+	return dictionarySource{
+		index:          *dictionaryIndex,
+		file:           *dictionaryFile,
+		metadataFile:   *dictionaryMetadataFile,
+		alphabet:       dictionaryAlphabet(),
+		phrases:        *phraseMode,
+		punctuation:    *punctuation,
+		folding:        CaseFolding(*caseFolding),
+		compact:        *compactWords,
+		strict:         *strictDictionary,
+		minLength:      *minWordLength,
+		maxLength:      *maxWordLength,
+		blocklist:      *blocklistFile,
+		allowlist:      *allowlistFile,
+		familyFriendly: *familyFriendly,
+		commonWords:    *commonWords,
+	}
+}
+
+// Method to load a dictionary from its files.
+func loadDictionary(source dictionarySource) (*Dictionary, error) {
+	return loadDictionaryCtx(context.Background(), source)
+}
+
+// Method to load a dictionary from its files, or from its word source. The
+// context bounds the time taken by the word source.
+func loadDictionaryCtx(ctx context.Context, source dictionarySource) (*Dictionary, error) {
+	if err := validateWordLengthLimits(source.minLength, source.maxLength); err != nil {
+		return nil, err
+	}
+	if _, err := ParseCaseFolding(string(source.folding)); err != nil {
+		return nil, err
+	}
+	if err := validatePunctuation(source.punctuation); err != nil {
+		return nil, fmt.Errorf("invalid --punctuation: %v", err)
+	}
+	if err := validateCommonWords(source.commonWords); err != nil {
+		return nil, err
+	}
+	alphabet := NewAlphabet(source.alphabet)
+	alphabet.Phrases = source.phrases
+	alphabet.Punctuation = source.punctuation
+	alphabet.Folding = source.folding
+	// The lists of the filter are read first, so that a missing list fails
+	// before the words are loaded. They are read again on every reload.
+	filter, err := newWordFilter(source, alphabet)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the word filter: %v", err)
+	}
+	if source.index != "" {
+		// The prebuilt index has the words ready to use.
+		d, err := loadDictionaryIndex(source.index, source.alphabet, source.phrases,
+			source.punctuation, source.folding, source.compact, source.strict)
+		if err != nil {
+			return nil, err
+		}
+		d.limitLengths(source.minLength, source.maxLength)
+		d.filterWords(filter)
+		d.keepCommonWords(source.commonWords)
+		d.source = &source
+		return d, nil
+	}
+	words := source.words
+	if words == nil {
+		words = wordSourceFromPath(source.file)
+	}
+	lines, err := words.Words(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", source.name(), err)
+	}
+	// Load the license and attribution of the words.
+	metadata, err := loadDictionaryMetadata(source.metadataFile, source.file, source.strict)
+	if err != nil {
+		return nil, err
+	}
+	d := newDictionary(lines, alphabet, metadata, source.compact)
+	d.limitLengths(source.minLength, source.maxLength)
+	d.filterWords(filter)
+	d.keepCommonWords(source.commonWords)
+	d.source = &source
+	return d, nil
+}
+
+// Method to load the dictionary again from the files it was loaded from, e.g.
+// after the word list was updated. The dictionary itself is not changed, the
+// new one is returned. Returns an error if the files can not be loaded, or if
+// the dictionary was built from a list of words.
+func (d *Dictionary) Reload() (*Dictionary, error) {
+	return d.ReloadCtx(context.Background())
+}
+
+// Method to load the dictionary again like Reload. The context bounds the time
+// taken by a download or a word source.
+func (d *Dictionary) ReloadCtx(ctx context.Context) (*Dictionary, error) {
+	if d.source == nil {
+		return nil, errors.New("the dictionary was not loaded from a file")
+	}
+	return loadDictionaryCtx(ctx, *d.source)
+}
+
+// Method to reload the dictionary used for the new games, see
+// Dictionary.Reload. The games already created keep playing with their own
+// words. The current dictionary is kept if the new one can not be loaded, or
+// if the context is done first.
+func reloadDictionary(ctx context.Context) (*Dictionary, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	d, err := currentDictionary().ReloadCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	currentDict.Store(d)
+	defaultLogger.Infof("Dictionary reloaded, %d words", d.WordCount())
+	return d, nil
+}
+
+// Handler of POST /admin/dictionary/reload, which reloads the dictionary and
+// returns the new one as an api.About.
+func (s *gameServer) handleReloadDictionary(w http.ResponseWriter, r *http.Request) {
+	d, err := reloadDictionary(r.Context())
+	if apiErr := contextErrorToAPI(err); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if err != nil {
+		defaultLogger.Errorf("Unable to reload the dictionary, error %v", err)
+		writeError(w, api.NewError(api.CodeReloadFailed,
+			"unable to reload the dictionary: %v", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, aboutDictionary(d))
+}
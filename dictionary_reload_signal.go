@@ -0,0 +1,34 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Method to reload the dictionary every time the process gets a SIGHUP.
+// Returns a function to stop it.
+func reloadDictionaryOnSignal() func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-signals:
+				if _, err := reloadDictionary(context.Background()); err != nil {
+					defaultLogger.Errorf("Unable to reload the dictionary, error %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
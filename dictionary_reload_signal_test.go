@@ -0,0 +1,35 @@
+//go:build !js && !windows
+
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadDictionaryOnSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("last\nfast"), 0644))
+	oldFile := *dictionaryFile
+	*dictionaryFile = path
+	defer func() {
+		*dictionaryFile = oldFile
+		InitGame([]string{"last", "fast", "bets", "code"})
+	}()
+	InitGame(nil)
+	stop := reloadDictionaryOnSignal()
+	defer stop()
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("mist"), 0644))
+	assert.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	deadline := time.Now().Add(5 * time.Second)
+	for currentDictionary().WordCount() != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, currentDictionary().WordCount())
+}
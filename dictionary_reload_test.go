@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type DictionaryReloadTestSuite struct {
+	suite.Suite
+	path    string
+	oldFile string
+}
+
+func (s *DictionaryReloadTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "words.txt")
+	s.write("last\nfast\nbets\ncode")
+	s.oldFile = *dictionaryFile
+	*dictionaryFile = s.path
+	InitGame(nil)
+}
+
+func (s *DictionaryReloadTestSuite) TearDownTest() {
+	*dictionaryFile = s.oldFile
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *DictionaryReloadTestSuite) write(words string) {
+	assert.Nil(s.T(), ioutil.WriteFile(s.path, []byte(words), 0644))
+}
+
+func (s *DictionaryReloadTestSuite) TestReload() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	s.write("last\nfast\nbets\ncode\nmist\nhorse")
+	d, err := reloadDictionary(context.Background())
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 6, d.WordCount())
+	assert.Equal(s.T(), d, currentDictionary())
+	assert.True(s.T(), d.HasLength(5))
+	// The game created before keeps its words.
+	assert.ElementsMatch(s.T(), []string{"bets", "code", "fast", "last"}, game.Candidates())
+
+	// The current dictionary is kept if the file can not be loaded.
+	os.Remove(s.path)
+	_, err = reloadDictionary(context.Background())
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), d, currentDictionary())
+}
+
+func (s *DictionaryReloadTestSuite) TestReloadWordList() {
+	InitGame([]string{"last", "fast"})
+	_, err := currentDictionary().Reload()
+	assert.NotNil(s.T(), err)
+}
+
+func (s *DictionaryReloadTestSuite) TestAdminReload() {
+	gameServer := newGameServer()
+	gameServer.adminToken = "secret"
+	server := httptest.NewServer(gameServer.Handler())
+	defer server.Close()
+	reload := func(token string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/admin/dictionary/reload", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(s.T(), err)
+		return resp
+	}
+
+	resp := reload("wrong")
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusForbidden, resp.StatusCode)
+
+	s.write("mist\nhorse")
+	resp = reload("secret")
+	var about api.About
+	json.NewDecoder(resp.Body).Decode(&about)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), 2, about.WordCount)
+
+	os.Remove(s.path)
+	resp = reload("secret")
+	var errResp api.ErrorResponse
+	json.NewDecoder(resp.Body).Decode(&errResp)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(s.T(), api.CodeReloadFailed, errResp.Error.Code)
+	assert.Equal(s.T(), 2, currentDictionary().WordCount())
+}
+
+func TestDictionaryReloadTestSuite(t *testing.T) {
+	suite.Run(t, new(DictionaryReloadTestSuite))
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type DictionaryTestSuite struct {
+	suite.Suite
+}
+
+func (s *DictionaryTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *DictionaryTestSuite) TestGameKeepsItsDictionary() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+
+	// Loading a new dictionary does not change the games already created.
+	InitGame([]string{"hello"})
+	assert.Equal(s.T(), 4, len(game.CurrentSetOfWords))
+	accepted, err := game.CheckUserInput('s')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), true, accepted)
+
+	_, err = NewGame(4, 3)
+	assert.ErrorIs(s.T(), err, ErrInvalidLength)
+	_, err = NewGame(5, 3)
+	assert.Nil(s.T(), err)
+}
+
+func (s *DictionaryTestSuite) TestConcurrentGuesses() {
+	game, err := NewGame(4, *maxAllowedRetries)
+	assert.Nil(s.T(), err)
+
+	// Every character is played by two goroutines at once, at most one of
+	// them gets it through.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	played := 0
+	for _, char := range "abcdefghijklmnopqrstuvwxyz" {
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func(char rune) {
+				defer wg.Done()
+				game.PreviewAll()
+				if _, err := game.CheckUserInput(char); err == nil {
+					mu.Lock()
+					played++
+					mu.Unlock()
+				}
+			}(char)
+		}
+	}
+	wg.Wait()
+	assert.NotEqual(s.T(), Running, game.State)
+	assert.Equal(s.T(), len(game.UsedChars), played)
+}
+
+func (s *DictionaryTestSuite) TestConcurrentInit() {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			InitGame([]string{"last", "fast", "bets", "code"})
+		}()
+		go func() {
+			defer wg.Done()
+			game, err := NewGame(4, 3)
+			assert.Nil(s.T(), err)
+			game.CheckUserInput('s')
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *DictionaryTestSuite) TestMetadata() {
+	dir := s.T().TempDir()
+	dictPath := filepath.Join(dir, "words.txt")
+
+	// The default metadata file is optional, unless in strict mode.
+	metadata, err := loadDictionaryMetadata("", dictPath, false)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), DictionaryMetadata{}, metadata)
+	_, err = loadDictionaryMetadata("", dictPath, true)
+	assert.EqualError(s.T(), err, "dictionary "+dictPath+
+		" is missing the required metadata: license, attribution")
+
+	// An explicitly given metadata file must exist.
+	_, err = loadDictionaryMetadata(filepath.Join(dir, "missing.json"), dictPath, false)
+	assert.NotNil(s.T(), err)
+
+	ioutil.WriteFile(dictPath+".meta.json",
+		[]byte(`{"name": "Words", "license": "CC0-1.0"}`), 0644)
+	metadata, err = loadDictionaryMetadata("", dictPath, false)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), DictionaryMetadata{Name: "Words", License: "CC0-1.0"}, metadata)
+	_, err = loadDictionaryMetadata("", dictPath, true)
+	assert.EqualError(s.T(), err, "dictionary "+dictPath+
+		" is missing the required metadata: attribution")
+
+	ioutil.WriteFile(dictPath+".meta.json",
+		[]byte(`{"license": "CC0-1.0", "attribution": "By someone"}`), 0644)
+	_, err = loadDictionaryMetadata("", dictPath, true)
+	assert.Nil(s.T(), err)
+
+	ioutil.WriteFile(dictPath+".meta.json", []byte(`{`), 0644)
+	_, err = loadDictionaryMetadata("", dictPath, false)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *DictionaryTestSuite) TestWordLengthLimits() {
+	*minWordLength, *maxWordLength = 3, 4
+	defer func() { *minWordLength, *maxWordLength = 1, 0 }()
+	InitGame([]string{"a", "to", "the", "last", "fast", "horse", "animals"})
+	d := currentDictionary()
+	assert.Equal(s.T(), []int{3, 4}, d.Lengths())
+	assert.Equal(s.T(), []int{3, 4}, d.AvailableLengths())
+	assert.Equal(s.T(), 3, d.WordCount())
+
+	_, err := NewGame(5, 3)
+	assert.True(s.T(), errors.Is(err, ErrInvalidLength))
+	assert.EqualError(s.T(), err, "Word length 5 is not allowed, playable lengths: 3-4")
+	_, err = NewGame(4, 3)
+	assert.Nil(s.T(), err)
+
+	// A dictionary built before the limits changed only offers the allowed
+	// lengths.
+	*minWordLength = 4
+	assert.Equal(s.T(), []int{4}, d.AvailableLengths())
+
+	assert.NotNil(s.T(), validateWordLengthLimits(0, 0))
+	assert.NotNil(s.T(), validateWordLengthLimits(5, 4))
+	assert.Nil(s.T(), validateWordLengthLimits(4, 4))
+	assert.Nil(s.T(), validateWordLengthLimits(4, 0))
+}
+
+func (s *DictionaryTestSuite) TestFormatLengths() {
+	assert.Equal(s.T(), "none", formatLengths(nil))
+	assert.Equal(s.T(), "4", formatLengths([]int{4}))
+	assert.Equal(s.T(), "3-7, 9, 11-12", formatLengths([]int{3, 4, 5, 6, 7, 9, 11, 12}))
+}
+
+func (s *DictionaryTestSuite) TestMatch() {
+	d := newDictionary([]string{"last", "fast", "bets", "code", "cast", "lost", "lo"},
+		NewAlphabet(""), DictionaryMetadata{}, false)
+	assert.Equal(s.T(), []string{"cast", "fast", "last"}, d.Match("_as_", nil))
+	// The mask and the excluded characters are folded like the guesses.
+	assert.Equal(s.T(), []string{"fast"}, d.Match("_AS_", []rune{'C', 'l'}))
+	assert.Equal(s.T(), []string{"bets", "code", "lost"}, d.Match("____", []rune{'a'}))
+	// Revealed characters can not be present at a hidden position.
+	assert.Equal(s.T(), []string{"bets"}, d.Match("___s", nil))
+	assert.Equal(s.T(), []string{"lo"}, d.Match("__", nil))
+	assert.Nil(s.T(), d.Match("___", nil))
+}
+
+func TestDictionaryTestSuite(t *testing.T) {
+	suite.Run(t, new(DictionaryTestSuite))
+}
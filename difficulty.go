@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+)
+
+var (
+	mercyAfterLosses = flag.Int("mercy_after_losses", 3,
+		"Offer an easier game after these many losses in a row. Set to 0 to "+
+			"never offer an easier game.")
+)
+
+const (
+	// Extra retries given when the player accepts an easier game.
+	mercyExtraRetries = 2
+)
+
+// Configuration of a game which decides how hard it is to win.
+type Difficulty struct {
+	// Length of the word to be guessed. Shorter words leave the computer with
+	// fewer words to switch between, which makes them easier.
+	WordLength int
+	// Number of incorrect guesses allowed.
+	Retries int
+	// Strategy of the computer, the one chosen by the player if nil.
+	Strategy Strategy
+}
+
+// Engine which adapts the difficulty of the next game to how the player has
+// been doing so far.
+type AdaptiveDifficulty struct {
+	// Number of consecutive losses after which an easier game is offered.
+	// Zero disables the offer.
+	MercyAfterLosses int
+	// Max number of retries which can be offered.
+	MaxRetries int
+}
+
+// Method to check if the player should be offered an easier game.
+// Params:
+// stats: Statistics of the games played so far.
+// current: Configuration of the last game played.
+//
+// Returns the easier configuration and true if it should be offered. The easier
+// configuration uses the next shorter word length available in the dictionary,
+// a few extra retries (up to the max allowed retries) and the merciful
+// strategy. No offer is made if neither the length nor the retries can be made
+// easier.
+func (a AdaptiveDifficulty) MercyOffer(stats Stats, current Difficulty) (Difficulty, bool) {
+	if a.MercyAfterLosses <= 0 || stats.CurrentLossStreak < a.MercyAfterLosses {
+		return current, false
+	}
+	easier := current
+	lengths := availableLengths()
+	// Pick the longest available length which is shorter than the current one.
+	for i := len(lengths) - 1; i >= 0; i-- {
+		if lengths[i] < current.WordLength {
+			easier.WordLength = lengths[i]
+			break
+		}
+	}
+	easier.Retries += mercyExtraRetries
+	if easier.Retries > a.MaxRetries {
+		easier.Retries = a.MaxRetries
+	}
+	if easier.Retries < current.Retries {
+		easier.Retries = current.Retries
+	}
+	if easier == current {
+		return current, false
+	}
+	easier.Strategy = Merciful{}
+	return easier, true
+}
+
+// Method to get the sorted list of word lengths a game can be played with,
+// see Dictionary.AvailableLengths.
+func availableLengths() []int {
+	return currentDictionary().AvailableLengths()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type DifficultyTestSuite struct {
+	suite.Suite
+}
+
+func (s *DifficultyTestSuite) SetupSuite() {
+	InitGame([]string{"cat", "dog", "last", "fast", "bets", "code", "sunny"})
+}
+
+func (s *DifficultyTestSuite) TestStatsStreaks() {
+	stats := Stats{}
+	stats.Record(Won)
+	stats.Record(Won)
+	stats.Record(Lost)
+	stats.Record(Running)
+	assert.Equal(s.T(), 3, stats.GamesPlayed)
+	assert.Equal(s.T(), 2, stats.GamesWon)
+	assert.Equal(s.T(), 1, stats.CurrentLossStreak)
+	assert.Equal(s.T(), 0, stats.CurrentWinStreak)
+	assert.Equal(s.T(), 2, stats.LongestWinStreak)
+}
+
+func (s *DifficultyTestSuite) TestNoOfferBeforeStreak() {
+	a := AdaptiveDifficulty{MercyAfterLosses: 2, MaxRetries: 10}
+	_, ok := a.MercyOffer(Stats{CurrentLossStreak: 1}, Difficulty{WordLength: 5, Retries: 3})
+	assert.Equal(s.T(), false, ok)
+
+	// Zero disables the offer.
+	a.MercyAfterLosses = 0
+	_, ok = a.MercyOffer(Stats{CurrentLossStreak: 5}, Difficulty{WordLength: 5, Retries: 3})
+	assert.Equal(s.T(), false, ok)
+}
+
+func (s *DifficultyTestSuite) TestOffer() {
+	a := AdaptiveDifficulty{MercyAfterLosses: 2, MaxRetries: 10}
+	offer, ok := a.MercyOffer(Stats{CurrentLossStreak: 2}, Difficulty{WordLength: 5, Retries: 3})
+	assert.Equal(s.T(), true, ok)
+	assert.Equal(s.T(), Difficulty{WordLength: 4, Retries: 5, Strategy: Merciful{}}, offer)
+
+	// Retries are capped, but a shorter word is still offered.
+	offer, ok = a.MercyOffer(Stats{CurrentLossStreak: 2}, Difficulty{WordLength: 4, Retries: 10})
+	assert.Equal(s.T(), true, ok)
+	assert.Equal(s.T(), Difficulty{WordLength: 3, Retries: 10, Strategy: Merciful{}}, offer)
+
+	// Nothing can be made easier.
+	_, ok = a.MercyOffer(Stats{CurrentLossStreak: 2}, Difficulty{WordLength: 3, Retries: 10})
+	assert.Equal(s.T(), false, ok)
+}
+
+func TestDifficultyTestSuite(t *testing.T) {
+	suite.Run(t, new(DifficultyTestSuite))
+}
@@ -0,0 +1,317 @@
+// Package engine plays the games of WordGuess on a word list, so that the
+// programs importing it (e.g. a web app mounting hangmanhttp) play the same
+// game as the hangman program: the computer does not pick its word up front,
+// and after every guess it keeps the largest group of words showing the same
+// pattern, revealing as few letters as possible.
+//
+// The games are played on words made of letters only, folded to lower case.
+// The hangman program builds on the same rules with its own dictionary, which
+// also supports phrases, custom alphabets and the other personalities of the
+// computer.
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Character of the patterns for a letter which is yet to be guessed. It is
+// not printable so it never clashes with a letter of the words.
+const Hidden = '\x00'
+
+// Separator of a word and its clue in a line of a word list, e.g.
+// "paris|capital city". The clue is optional.
+const ClueSeparator = "|"
+
+// Errors returned by the games. The errors returned by the methods wrap one of
+// these along with a message for the player, so callers should check them
+// using errors.Is.
+var (
+	// No word of the dictionary has the expected length.
+	ErrInvalidLength = errors.New("invalid word length")
+	// The number of retries is negative, or the retry policy is unknown.
+	ErrInvalidRetries = errors.New("invalid number of retries")
+	// The guessed character is not a letter.
+	ErrInvalidCharacter = errors.New("invalid character")
+	// The guessed character was already guessed in this game.
+	ErrCharAlreadyUsed = errors.New("character already used")
+	// The game is won or lost, so it does not take any more guesses.
+	ErrGameFinished = errors.New("game finished")
+)
+
+// Words the games are played on, by length, and their clues.
+type Dictionary struct {
+	words map[int][]string
+	clues map[string]string
+}
+
+// Method to create a dictionary from the lines of a word list, each a word
+// optionally followed by its clue, see ClueSeparator. The words are folded to
+// lower case; empty lines, duplicates and words with other characters than
+// letters are skipped.
+func NewDictionary(lines []string) *Dictionary {
+	d := &Dictionary{words: make(map[int][]string), clues: make(map[string]string)}
+	seen := make(map[string]bool)
+	words, clues := SplitClues(lines)
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || seen[word] || strings.IndexFunc(word, isNotLetter) >= 0 {
+			continue
+		}
+		seen[word] = true
+		length := utf8.RuneCountInString(word)
+		d.words[length] = append(d.words[length], word)
+	}
+	for word, clue := range clues {
+		d.clues[strings.ToLower(word)] = clue
+	}
+	return d
+}
+
+func isNotLetter(r rune) bool {
+	return !unicode.IsLetter(r)
+}
+
+// Method to split the lines of a word list into the words and their clues.
+// The list is returned as is if no line has a clue. Spaces around the
+// separator are ignored.
+// Returns the words, in the order of the lines, and the clues by word.
+func SplitClues(lines []string) ([]string, map[string]string) {
+	words := lines
+	var clues map[string]string
+	for i, line := range lines {
+		word, clue, found := strings.Cut(line, ClueSeparator)
+		if !found {
+			continue
+		}
+		if clues == nil {
+			// Copied so that the list of the caller is not modified.
+			words = append([]string{}, lines...)
+			clues = make(map[string]string)
+		}
+		word, clue = strings.TrimSpace(word), strings.TrimSpace(clue)
+		words[i] = word
+		if clue != "" {
+			clues[word] = clue
+		}
+	}
+	return words, clues
+}
+
+// Method to get the word lengths of the dictionary, in increasing order.
+func (d *Dictionary) Lengths() []int {
+	lengths := make([]int, 0, len(d.words))
+	for length := range d.words {
+		lengths = append(lengths, length)
+	}
+	sort.Ints(lengths)
+	return lengths
+}
+
+// Method to get the words of a length, in the order of the word list.
+func (d *Dictionary) Words(length int) []string {
+	return append([]string{}, d.words[length]...)
+}
+
+// Game played on a dictionary. The methods of a game can be called from
+// multiple goroutines.
+type Game struct {
+	mu         sync.Mutex
+	dict       *Dictionary
+	candidates []string
+	pattern    []rune
+	used       []rune
+	allowed    int
+	left       int
+	policy     api.RetryPolicy
+	state      api.GameState
+}
+
+// Method to start a game on the words of a length of the dictionary.
+// Params:
+// dict: Words the computer chooses from.
+// length: Length of the word.
+// retries: Wrong guesses the player can make, see the retry policy.
+// policy: When the wrong guesses lose the game, strict if empty.
+//
+// Returns an error wrapping ErrInvalidLength or ErrInvalidRetries if the game
+// can not be played.
+func NewGame(dict *Dictionary, length, retries int, policy api.RetryPolicy) (*Game, error) {
+	if length <= 0 || len(dict.words[length]) == 0 {
+		return nil, fmt.Errorf("%w: no words of length %d in the dictionary",
+			ErrInvalidLength, length)
+	}
+	if retries < 0 {
+		return nil, fmt.Errorf("%w: retries can not be negative, got %d",
+			ErrInvalidRetries, retries)
+	}
+	switch policy {
+	case "":
+		policy = api.RetryStrict
+	case api.RetryStrict, api.RetryLenient, api.RetryUnlimited:
+	default:
+		return nil, fmt.Errorf("%w: unknown retry policy %q, expected %q, %q or %q",
+			ErrInvalidRetries, policy, api.RetryStrict, api.RetryLenient, api.RetryUnlimited)
+	}
+	g := &Game{
+		dict:       dict,
+		candidates: dict.Words(length),
+		pattern:    make([]rune, length),
+		allowed:    retries,
+		left:       retries,
+		policy:     policy,
+		state:      api.StateRunning,
+	}
+	for i := range g.pattern {
+		g.pattern[i] = Hidden
+	}
+	return g, nil
+}
+
+// Method to guess a character. The case of the character is ignored.
+// Returns true if the character is in the word, or an error wrapping
+// ErrGameFinished, ErrInvalidCharacter or ErrCharAlreadyUsed if the guess is
+// not played.
+func (g *Game) Guess(char rune) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.state != api.StateRunning {
+		return false, fmt.Errorf("%w: the game is %s", ErrGameFinished, g.state)
+	}
+	char = unicode.ToLower(char)
+	if !unicode.IsLetter(char) {
+		return false, fmt.Errorf("%w: character %s is not a letter", ErrInvalidCharacter,
+			string(char))
+	}
+	for _, used := range g.used {
+		if used == char {
+			return false, fmt.Errorf("%w: character %s has been used", ErrCharAlreadyUsed,
+				string(char))
+		}
+	}
+	g.used = append(g.used, char)
+	groups := make(map[string][]string)
+	for _, word := range g.candidates {
+		pattern := GuessPattern(word, g.pattern, char)
+		groups[pattern] = append(groups[pattern], word)
+	}
+	var kept string
+	for pattern, words := range groups {
+		if kept == "" || Prefer(pattern, len(words), kept, len(groups[kept])) {
+			kept = pattern
+		}
+	}
+	g.candidates = groups[kept]
+	if kept == string(g.pattern) {
+		g.left--
+		if RetriesLost(g.policy, g.left) {
+			g.state = api.StateLost
+		}
+		return false, nil
+	}
+	g.pattern = []rune(kept)
+	if !strings.ContainsRune(kept, Hidden) {
+		g.state = api.StateWon
+	}
+	return true, nil
+}
+
+// Method to get the clue shared by all the words the game is still choosing
+// from. A clue is never shown while the words have different clues, since it
+// would tell which words the game is not playing.
+// Returns false if there is no such clue.
+func (g *Game) Hint() (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.hintLocked()
+}
+
+func (g *Game) hintLocked() (string, bool) {
+	var clue string
+	for i, word := range g.candidates {
+		wordClue, ok := g.dict.clues[word]
+		if !ok || (i > 0 && wordClue != clue) {
+			return "", false
+		}
+		clue = wordClue
+	}
+	return clue, clue != ""
+}
+
+// Method to get the public view of the game, which has the given id.
+func (g *Game) View(id string) api.Game {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	masked := make([]rune, len(g.pattern))
+	for i, char := range g.pattern {
+		if char == Hidden {
+			char = '_'
+		}
+		masked[i] = char
+	}
+	retries := g.left
+	if retries < 0 {
+		retries = 0
+	}
+	_, hintAvailable := g.hintLocked()
+	return api.Game{
+		ID:             id,
+		WordLength:     len(g.pattern),
+		MaskedWord:     string(masked),
+		UsedChars:      string(g.used),
+		RetriesLeft:    retries,
+		AllowedRetries: g.allowed,
+		State:          g.state,
+		RetryPolicy:    g.policy,
+		HintAvailable:  hintAvailable,
+	}
+}
+
+// Method to get the pattern shown after a guess if a word is kept, i.e. the
+// current pattern with the guessed character revealed where the word has it.
+func GuessPattern(word string, current []rune, char rune) string {
+	pattern := make([]rune, len(current))
+	copy(pattern, current)
+	for i, wordChar := range []rune(word) {
+		if wordChar == char {
+			pattern[i] = wordChar
+		}
+	}
+	return string(pattern)
+}
+
+// Method to check if the group of words showing a pattern should be kept over
+// another one. The group with more words is kept first. Among the groups of
+// the same size, the one which reveals less letters is kept, then the
+// lexicographically smaller pattern, assuming that the first letters of a word
+// are the easiest to guess.
+func Prefer(pattern string, size int, other string, otherSize int) bool {
+	if size != otherSize {
+		return size > otherSize
+	}
+	hidden := strings.Count(pattern, string(Hidden))
+	otherHidden := strings.Count(other, string(Hidden))
+	if hidden != otherHidden {
+		return hidden > otherHidden
+	}
+	return pattern < other
+}
+
+// Method to check if the retries left lose a game under a retry policy: strict
+// once they are all used, lenient at the wrong guess after that, and unlimited
+// never.
+func RetriesLost(policy api.RetryPolicy, retriesLeft int) bool {
+	switch policy {
+	case api.RetryLenient:
+		return retriesLeft < 0
+	case api.RetryUnlimited:
+		return false
+	}
+	return retriesLeft <= 0
+}
@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"errors"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDictionary(t *testing.T) {
+	dict := NewDictionary([]string{"Last", "fast|quick", "last", "c0de", "", "it"})
+	assert.Equal(t, []int{2, 4}, dict.Lengths())
+	assert.Equal(t, []string{"last", "fast"}, dict.Words(4))
+	assert.Equal(t, "quick", dict.clues["fast"])
+}
+
+func TestGame(t *testing.T) {
+	dict := NewDictionary([]string{"last", "fast", "code"})
+	_, err := NewGame(dict, 5, 3, "")
+	assert.True(t, errors.Is(err, ErrInvalidLength))
+	_, err = NewGame(dict, -1, 3, "")
+	assert.True(t, errors.Is(err, ErrInvalidLength))
+	_, err = NewGame(dict, 4, -1, "")
+	assert.True(t, errors.Is(err, ErrInvalidRetries))
+	_, err = NewGame(dict, 4, 1, "forgiving")
+	assert.True(t, errors.Is(err, ErrInvalidRetries))
+
+	game, err := NewGame(dict, 4, 1, api.RetryLenient)
+	assert.Nil(t, err)
+	// The largest group is kept, the one revealing less letters among equals.
+	accepted, err := game.Guess('A')
+	assert.Nil(t, err)
+	assert.True(t, accepted)
+	_, err = game.Guess('a')
+	assert.True(t, errors.Is(err, ErrCharAlreadyUsed))
+	_, err = game.Guess('1')
+	assert.True(t, errors.Is(err, ErrInvalidCharacter))
+	accepted, _ = game.Guess('z')
+	assert.False(t, accepted)
+	// The lenient policy allows one more wrong guess.
+	assert.Equal(t, api.StateRunning, game.View("1").State)
+	game.Guess('y')
+	view := game.View("1")
+	assert.Equal(t, api.StateLost, view.State)
+	assert.Equal(t, "_a__", view.MaskedWord)
+	assert.Equal(t, 0, view.RetriesLeft)
+	_, err = game.Guess('s')
+	assert.True(t, errors.Is(err, ErrGameFinished))
+}
+
+func TestPrefer(t *testing.T) {
+	hidden := string(Hidden)
+	assert.True(t, Prefer("a"+hidden, 2, hidden+hidden, 1))
+	assert.True(t, Prefer(hidden+hidden, 1, "a"+hidden, 1))
+	assert.True(t, Prefer("a"+hidden, 1, "b"+hidden, 1))
+	assert.Equal(t, "a"+hidden+"a", GuessPattern("aba", []rune{Hidden, Hidden, Hidden}, 'a'))
+	assert.True(t, RetriesLost(api.RetryStrict, 0))
+	assert.False(t, RetriesLost(api.RetryUnlimited, -3))
+}
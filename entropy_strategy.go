@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/hackeracc/WordGuess/engine"
+	"math"
+)
+
+// Uncertainty (in bits) a rejected guess is worth on top of the words it keeps,
+// since it costs the player a retry while an accepted guess is free.
+const rejectionBonus = 1.0
+
+// Strategy keeping the partition which leaves the player the most uncertainty:
+// the entropy (in bits) of the candidates of the partition, all equally likely,
+// minus what the best next guess of the player would reveal of them, plus
+// rejectionBonus for the partition rejecting the guess. Unlike the vindictive
+// strategy, which only looks at the size of the partitions, it avoids the
+// partitions a single letter would split evenly, which makes it harder to beat
+// on large dictionaries (see BenchmarkStrategies). Ties go to the partition the
+// vindictive strategy prefers.
+type StrategyEntropy struct{}
+
+func (StrategyEntropy) Name() string {
+	return "entropy"
+}
+
+// Method to pick a partition knowing only the sizes of the partitions, where the
+// largest one leaves the most uncertainty. The game calls chooseWords instead.
+func (StrategyEntropy) Choose(d Decision) int {
+	return 0
+}
+
+func (StrategyEntropy) chooseWords(d Decision, partitions [][]string, usedChars []rune) int {
+	best, bestUncertainty := 0, -1.0
+	for i, words := range partitions {
+		uncertainty := remainingUncertainty(d.Partitions[i].Pattern, words, usedChars)
+		if d.Partitions[i].Pattern == d.Before {
+			uncertainty += rejectionBonus
+		}
+		if uncertainty > bestUncertainty {
+			best, bestUncertainty = i, uncertainty
+		}
+	}
+	return best
+}
+
+// Method to compute the uncertainty (in bits) left to the player after their
+// best next guess, if the computer keeps the given words.
+func remainingUncertainty(pattern string, words []string, usedChars []rune) float64 {
+	if len(words) == 0 {
+		return 0
+	}
+	solver := &Solver{
+		WordLength: len([]rune(pattern)),
+		Candidates: words,
+		Pattern:    []rune(pattern),
+		UsedChars:  usedChars,
+	}
+	uncertainty := math.Log2(float64(len(words)))
+	if char, ok := solver.NextGuess(); ok {
+		uncertainty -= solver.entropy(char)
+	}
+	return uncertainty
+}
+
+// Strategy which needs the words of every partition to choose, instead of
+// their sizes only.
+type wordsStrategy interface {
+	// Method to pick the partition to keep, like Strategy.Choose.
+	// Params:
+	// d: Decision with the partitions, in the order of Strategy.Choose.
+	// partitions: Words of every partition of the decision, in the same order.
+	// usedChars: Characters guessed so far, including the one of the decision.
+	//
+	// Returns the index of the partition to keep.
+	chooseWords(d Decision, partitions [][]string, usedChars []rune) int
+}
+
+// Method to split words into the partitions of a decision.
+// Returns the words of every partition, in the order of the decision.
+func partitionWords(words []string, d Decision) [][]string {
+	index := make(map[string]int, len(d.Partitions))
+	for i, p := range d.Partitions {
+		index[p.Pattern] = i
+	}
+	before := []rune(d.Before)
+	partitions := make([][]string, len(d.Partitions))
+	for _, word := range words {
+		i := index[engine.GuessPattern(word, before, d.Char)]
+		partitions[i] = append(partitions[i], word)
+	}
+	return partitions
+}
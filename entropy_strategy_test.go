@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type EntropyStrategyTestSuite struct {
+	suite.Suite
+}
+
+func (s *EntropyStrategyTestSuite) SetupTest() {
+	// Guessing 'z' splits the words into the 8 words without it, which any of
+	// 'a' or 'b' tells apart, and the 4 words starting with it, which no letter
+	// splits better than one against three.
+	InitGame([]string{"aaab", "aaba", "abaa", "baaa", "bbba", "bbab", "babb", "abbb",
+		"zcde", "zcdf", "zcdg", "zcdh"})
+}
+
+func (s *EntropyStrategyTestSuite) TestKeepsHardestPartition() {
+	game, err := NewGame(4, 6)
+	assert.Nil(s.T(), err)
+	accepted, err := game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), accepted)
+
+	game, err = NewGame(4, 6, WithStrategy(StrategyEntropy{}))
+	assert.Nil(s.T(), err)
+	accepted, err = game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), accepted)
+	assert.Equal(s.T(), []string{"zcde", "zcdf", "zcdg", "zcdh"}, game.candidatesLocked())
+	assert.Equal(s.T(), "entropy", game.LastDecision.Strategy)
+	assert.Equal(s.T(), 8, game.LastDecision.Partitions[1].Size)
+}
+
+func (s *EntropyStrategyTestSuite) TestRemainingUncertainty() {
+	used := []rune{'z'}
+	assert.Equal(s.T(), 0.0, remainingUncertainty(string(rawPattern("____")),
+		[]string{"aaab", "aaba", "abaa", "baaa"}, used))
+	// The best guess tells one word from the two others.
+	expected := math.Log2(3) - (-(1.0/3)*math.Log2(1.0/3) - (2.0/3)*math.Log2(2.0/3))
+	assert.InDelta(s.T(), expected, remainingUncertainty(string(rawPattern("z___")),
+		[]string{"zcde", "zcdf", "zcdg"}, used), 1e-9)
+	assert.Equal(s.T(), 0.0, remainingUncertainty(string(rawPattern("____")), nil, used))
+}
+
+func (s *EntropyStrategyTestSuite) TestPartitionWords() {
+	words := []string{"last", "fast", "bets", "code", "cats"}
+	_, d := getMaxSet(NopLogger{}, words, rawPattern("____"), 'e')
+	assert.Equal(s.T(), [][]string{{"last", "fast", "cats"}, {"code"}, {"bets"}},
+		partitionWords(words, d))
+}
+
+func (s *EntropyStrategyTestSuite) TestParse() {
+	strategy, err := ParseStrategy("entropy", 0)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), StrategyEntropy{}, strategy)
+}
+
+func TestEntropyStrategyTestSuite(t *testing.T) {
+	suite.Run(t, new(EntropyStrategyTestSuite))
+}
+
+// Plays games of the solver against the max-set heuristic and the entropy
+// strategy on a large dictionary, reporting the incorrect guesses the solver
+// needs to find the word. The words use few letters, so that most guesses
+// split them.
+func BenchmarkStrategies(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	words := make([]string, 20000)
+	for i := range words {
+		word := make([]byte, 4+i%4)
+		for j := range word {
+			word[j] = byte('a' + rng.Intn(12))
+		}
+		words[i] = string(word)
+	}
+	InitGame(words)
+	strategies := map[string]Strategy{"vindictive": nil, "entropy": StrategyEntropy{}}
+	for name, strategy := range strategies {
+		b.Run(name, func(b *testing.B) {
+			var guesses, misses int
+			for i := 0; i < b.N; i++ {
+				game, err := NewGame(4+i%4, 6, WithStrategy(strategy),
+					WithRetryPolicy(RetryUnlimited))
+				if err != nil {
+					b.Fatal(err)
+				}
+				for game.State == Running {
+					char, err := entropyBot{}.Guess(context.Background(), botState(game))
+					if err != nil {
+						b.Fatal(err)
+					}
+					guesses++
+					if accepted, _ := game.CheckUserInput(char); !accepted {
+						misses++
+					}
+				}
+			}
+			b.ReportMetric(float64(guesses)/float64(b.N), "guesses/game")
+			b.ReportMetric(float64(misses)/float64(b.N), "misses/game")
+		})
+	}
+}
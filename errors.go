@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the game. The errors returned by the methods of the game
+// wrap one of these along with a message for the player, so callers should
+// check them using errors.Is.
+var (
+	// No dictionary word has the expected length.
+	ErrInvalidLength = errors.New("invalid word length")
+	// The number of retries is negative or more than the max allowed retries.
+	ErrInvalidRetries = errors.New("invalid number of retries")
+	// A multi game is not played on 2 or 4 boards.
+	ErrInvalidBoards = errors.New("invalid number of boards")
+	// A crossword has too few words, or its words do not fit in a grid.
+	ErrInvalidWordCount = errors.New("invalid number of words")
+	// A word of a word ladder is not in the dictionary.
+	ErrUnknownWord = errors.New("unknown word")
+	// A step of a word ladder does not change exactly one letter, or goes back
+	// to a word already in the ladder.
+	ErrInvalidStep = errors.New("invalid ladder step")
+	// No word ladder joins the two words.
+	ErrNoLadder = errors.New("no word ladder")
+	// The guessed character is not a letter of the dictionary alphabet.
+	ErrInvalidCharacter = errors.New("invalid character")
+	// The guessed character was already guessed in this game.
+	ErrCharAlreadyUsed = errors.New("character already used")
+	// The game is won or lost (possibly because the time is up), so it does not
+	// take any more guesses.
+	ErrGameFinished = errors.New("game finished")
+	// The game was lost because its time is up, i.e. the deadlines of the
+	// guesses were missed, or because it was forfeited. It wraps
+	// ErrGameFinished.
+	ErrGameExpired = fmt.Errorf("game expired: %w", ErrGameFinished)
+	// The deadline of the turn passed before the guess was given, so the turn
+	// was recorded as a timeout instead of the guess.
+	ErrTurnTimeout = errors.New("turn timed out")
+)
+
+// Errors returned when the program can not start, see configError and
+// usageError. The command line program exits with its own code for each of
+// them, see exitCode.
+var (
+	// The flags, or the files they name (dictionary, word lists, themes...),
+	// are not valid.
+	ErrInvalidConfig = errors.New("invalid configuration")
+	// A subcommand was given the wrong arguments.
+	ErrUsage = errors.New("wrong arguments")
+)
+
+// Errors returned by the terminal games once the player stops playing, see
+// waitInput. They are returned up to the command line program, which exits
+// with their own code, see exitCode.
+var (
+	// The input ended, e.g. the end of a file piped to the program. This is
+	// a normal end of the program.
+	ErrEndOfInput = errors.New("no more input")
+	// The player pressed Ctrl+C and confirmed quitting, or SIGINT was
+	// received.
+	ErrInterrupted = errors.New("interrupted")
+	// SIGTERM was received, e.g. because the system is shutting down.
+	ErrTerminated = errors.New("terminated")
+)
+
+// Method to check if an error tells that the player stopped playing, see
+// ErrEndOfInput, ErrInterrupted and ErrTerminated.
+func isInputEnd(err error) bool {
+	return errors.Is(err, ErrEndOfInput) || errors.Is(err, ErrInterrupted) ||
+		errors.Is(err, ErrTerminated)
+}
+
+// Error returned by the game, with a message for the player.
+type gameError struct {
+	// One of the sentinel errors above.
+	err error
+	// Message shown to the player.
+	msg string
+}
+
+// Method to create an error wrapping one of the sentinel errors.
+func newGameError(err error, format string, args ...interface{}) error {
+	return &gameError{err: err, msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *gameError) Error() string {
+	return e.msg
+}
+
+func (e *gameError) Unwrap() error {
+	return e.err
+}
+
+// Method to create an error wrapping ErrInvalidConfig. The format can use %w
+// to wrap the cause too.
+func configError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidConfig}, args...)...)
+}
+
+// Method to create an error wrapping ErrUsage, with the usage of the
+// subcommand.
+func usageError(usage string) error {
+	return fmt.Errorf("%w, usage: %s", ErrUsage, usage)
+}
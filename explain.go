@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hackeracc/WordGuess/engine"
+	"sort"
+	"strings"
+)
+
+// Partition of the candidate words by the positions of the guessed character.
+type Partition struct {
+	// Word as it is displayed if the partition is kept. It is the displayed
+	// word before the guess for the words without the character.
+	Pattern string
+	// Number of words in the partition.
+	Size int
+}
+
+// Decision made by the engine for a guess.
+type Decision struct {
+	// Guessed character.
+	Char rune
+	// Word displayed before the guess.
+	Before string
+	// Number of candidate words before the guess.
+	Candidates int
+	// Pattern of the kept partition, i.e. the word displayed after the guess.
+	Pattern string
+	// All the partitions, the kept one first and the others in the order the
+	// vindictive engine prefers them.
+	Partitions []Partition
+	// Name of the strategy which picked the kept partition, empty if the
+	// vindictive engine decided, see Strategy.
+	Strategy string
+}
+
+// Method to build the decision from the sizes of the partitions by pattern.
+func newDecision(char rune, currWord []rune, candidates int, sizes map[string]int) Decision {
+	d := Decision{Char: char, Before: string(currWord), Candidates: candidates}
+	for pattern, size := range sizes {
+		d.Partitions = append(d.Partitions, Partition{Pattern: pattern, Size: size})
+	}
+	sort.Slice(d.Partitions, func(i, j int) bool {
+		a, b := d.Partitions[i], d.Partitions[j]
+		return engine.Prefer(a.Pattern, a.Size, b.Pattern, b.Size)
+	})
+	if len(d.Partitions) > 0 {
+		d.Pattern = d.Partitions[0].Pattern
+	} else {
+		d.Pattern = d.Before
+	}
+	return d
+}
+
+// Method to move a partition first, making it the kept one.
+func (d Decision) keep(i int) Decision {
+	if i <= 0 || i >= len(d.Partitions) {
+		return d
+	}
+	partitions := make([]Partition, 0, len(d.Partitions))
+	partitions = append(partitions, d.Partitions[i])
+	partitions = append(partitions, d.Partitions[:i]...)
+	d.Partitions = append(partitions, d.Partitions[i+1:]...)
+	d.Pattern = d.Partitions[0].Pattern
+	return d
+}
+
+// Method to get the index of the first partition accepting the guess.
+// Returns -1 if no candidate contains the guessed character.
+func (d Decision) acceptingPartition() int {
+	for i, p := range d.Partitions {
+		if p.Pattern != d.Before {
+			return i
+		}
+	}
+	return -1
+}
+
+// Method to check if the guess was accepted.
+func (d Decision) Accepted() bool {
+	return d.Pattern != d.Before
+}
+
+// Method to get the number of words which contain the guessed character.
+func (d Decision) Containing() int {
+	containing := 0
+	for _, p := range d.Partitions {
+		if p.Pattern != d.Before {
+			containing += p.Size
+		}
+	}
+	return containing
+}
+
+// Method to get the best partition which was not kept. Returns false if there
+// was a single partition.
+func (d Decision) RunnerUp() (Partition, bool) {
+	if len(d.Partitions) < 2 {
+		return Partition{}, false
+	}
+	return d.Partitions[1], true
+}
+
+// Method to explain a decision in a few lines for the player, with the
+// patterns displayed in the given format.
+func explainDecision(d Decision, format PatternFormat) string {
+	var b strings.Builder
+	kept := 0
+	if len(d.Partitions) > 0 {
+		kept = d.Partitions[0].Size
+	}
+	containing := d.Containing()
+	fmt.Fprintf(&b, "%d of the %d words left contained '%c', %d did not.\n",
+		containing, d.Candidates, d.Char, d.Candidates-containing)
+	runnerUp, ok := d.RunnerUp()
+	if !d.Accepted() {
+		fmt.Fprintf(&b, "Rejecting '%c' kept the %s without it.\n", d.Char, countWords(kept))
+		if ok {
+			fmt.Fprintf(&b, "The best way to accept it, %s, would have kept %d.\n",
+				format.FormatString(runnerUp.Pattern), runnerUp.Size)
+		}
+	} else {
+		fmt.Fprintf(&b, "Accepting '%c' as %s kept %s.\n", d.Char, format.FormatString(d.Pattern),
+			countWords(kept))
+		if ok {
+			what := "rejecting it"
+			if runnerUp.Pattern != d.Before {
+				what = "showing it as " + format.FormatString(runnerUp.Pattern)
+			}
+			fmt.Fprintf(&b, "The next best choice, %s, would have kept %d.\n", what,
+				runnerUp.Size)
+		}
+	}
+	if d.Strategy != "" && d.Strategy != (Vindictive{}).Name() {
+		fmt.Fprintf(&b, "The %s opponent picked this choice.\n", d.Strategy)
+	} else if ok && runnerUp.Size == kept {
+		b.WriteString("On a tie the engine reveals fewer letters.\n")
+	}
+	return b.String()
+}
+
+// Method to format a number of words, e.g. "1 word" or "3 words".
+func countWords(n int) string {
+	if n == 1 {
+		return "1 word"
+	}
+	return fmt.Sprintf("%d words", n)
+}
+
+// Method to run a command typed instead of a guess. "why" explains the
+// decision made for the last guess and "hint" shows the clue of the word, see
+// Game.Hint. "?" and "!" do the same, since in key mode the first letter of
+// a command would already be taken as a guess.
+// Returns false if the line is not a command.
+func runGameCommand(game *Game, line string) bool {
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "why", "?":
+		if game.LastDecision == nil {
+			fmt.Println("Nothing to explain yet, make a guess first.")
+		} else {
+			fmt.Print(explainDecision(*game.LastDecision, displayFormat))
+		}
+	case "hint", "!":
+		if clue, ok := game.UseHint(); ok {
+			fmt.Println("Hint: ", clue)
+		} else {
+			fmt.Println("No hint yet, the words the computer is still choosing from " +
+				"do not share a clue.")
+		}
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ExplainTestSuite struct {
+	suite.Suite
+}
+
+func (s *ExplainTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats"})
+}
+
+func (s *ExplainTestSuite) TestRejectedGuess() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	accepted, err := game.CheckUserInput('e')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), false, accepted)
+	d := *game.LastDecision
+	assert.Equal(s.T(), 5, d.Candidates)
+	assert.Equal(s.T(), 2, d.Containing())
+	assert.Equal(s.T(), []Partition{
+		{Pattern: string(rawPattern("____")), Size: 3},
+		{Pattern: string(rawPattern("___e")), Size: 1},
+		{Pattern: string(rawPattern("_e__")), Size: 1},
+	}, d.Partitions)
+	assert.Equal(s.T(), "2 of the 5 words left contained 'e', 3 did not.\n"+
+		"Rejecting 'e' kept the 3 words without it.\n"+
+		"The best way to accept it, ___e, would have kept 1.\n", explainDecision(d, DefaultPatternFormat))
+}
+
+func (s *ExplainTestSuite) TestAcceptedGuess() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	accepted, err := game.CheckUserInput('a')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), true, accepted)
+	assert.Equal(s.T(), "3 of the 5 words left contained 'a', 2 did not.\n"+
+		"Accepting 'a' as _a__ kept 3 words.\n"+
+		"The next best choice, rejecting it, would have kept 2.\n",
+		explainDecision(*game.LastDecision, DefaultPatternFormat))
+}
+
+func (s *ExplainTestSuite) TestTie() {
+	InitGame([]string{"ab", "cd"})
+	game, err := NewGame(2, 3)
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('a')
+	assert.Equal(s.T(), "1 of the 2 words left contained 'a', 1 did not.\n"+
+		"Rejecting 'a' kept the 1 word without it.\n"+
+		"The best way to accept it, a_, would have kept 1.\n"+
+		"On a tie the engine reveals fewer letters.\n", explainDecision(*game.LastDecision, DefaultPatternFormat))
+}
+
+func (s *ExplainTestSuite) TestPackedDecision() {
+	words := []string{"last", "fast", "bets", "code", "cats"}
+	packed, _ := packWords(words)
+	_, d := getMaxSet(NopLogger{}, words, rawPattern("____"), 'e')
+	_, packedDecision := getMaxSetPacked(NopLogger{}, packed, 4, rawPattern("____"), 'e')
+	assert.Equal(s.T(), d, packedDecision)
+}
+
+func TestExplainTestSuite(t *testing.T) {
+	suite.Run(t, new(ExplainTestSuite))
+}
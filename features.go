@@ -0,0 +1,340 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"hash/fnv"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+)
+
+var (
+	featuresFile = flag.String("features_file", "",
+		"JSON file of the feature flags (an api.FeatureConfig) turning the "+
+			"experimental features on and off. The changes made through the admin "+
+			"API of the server are saved to it. Every feature is in its default "+
+			"state if empty.")
+	deploymentName = flag.String("deployment", "",
+		"Name of this deployment, e.g. \"staging\", which the feature flags can be "+
+			"restricted to.")
+	adminToken = flag.String("admin_token", "",
+		"Token required by the admin API of the server, sent in an "+
+			"\"Authorization: Bearer <token>\" header. The admin API is disabled if "+
+			"empty.")
+)
+
+// Names of the features gated by a feature flag.
+const (
+	// The "entropy" opponent.
+	featureEntropyOpponent = "entropy_opponent"
+	// The challenge games of the hall of shame.
+	featureChallengeGames = "challenge_games"
+	// The double points of the won challenge games in the leaderboard.
+	featureChallengePoints = "challenge_points"
+)
+
+// Features which can be turned on and off, by name. A new experimental
+// feature is registered with a false default so that it ships dark, and is
+// then enabled gradually with a rule.
+var knownFeatures = map[string]api.Feature{
+	featureEntropyOpponent: {
+		Name:        featureEntropyOpponent,
+		Description: "Opponent keeping the words hardest to tell apart (--opponent=entropy).",
+		Default:     true,
+	},
+	featureChallengeGames: {
+		Name:        featureChallengeGames,
+		Description: "Challenge games played on the words of the hall of shame.",
+		Default:     true,
+	},
+	featureChallengePoints: {
+		Name:        featureChallengePoints,
+		Description: "Double points for the won challenge games in the leaderboard.",
+		Default:     true,
+	},
+}
+
+// Feature flags of the program, optionally saved in a file.
+type featureStore struct {
+	// Guards the fields below.
+	mu sync.Mutex
+	// File the flags are saved to, empty if they are only kept in memory.
+	path string
+	// Deployment the program runs in, see --deployment.
+	deployment string
+	config     api.FeatureConfig
+}
+
+// Feature flags used by the whole program, set up by setupFeatures.
+var features = &featureStore{}
+
+// Method to load the feature flags of a file for a deployment. A missing file
+// has no flags, it is created by the first change. The flags are only kept in
+// memory if the path is empty.
+func (f *featureStore) load(path, deployment string) error {
+	var config api.FeatureConfig
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &config); err != nil {
+				return fmt.Errorf("invalid feature flags %s: %v", path, err)
+			}
+		}
+	}
+	for name, rule := range config.Features {
+		if err := validateFeatureRule(name, rule); err != nil {
+			return fmt.Errorf("invalid feature flags %s: %v", path, err)
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.path = path
+	f.deployment = deployment
+	f.config = config
+	return nil
+}
+
+// Method to check that a rule is for a known feature and is valid.
+func validateFeatureRule(name string, rule api.FeatureRule) error {
+	if _, ok := knownFeatures[name]; !ok {
+		return fmt.Errorf("unknown feature %q", name)
+	}
+	if rule.Percent != nil && (*rule.Percent < 0 || *rule.Percent > 100) {
+		return fmt.Errorf("invalid percent %d for feature %q, expected a value from 0 "+
+			"to 100", *rule.Percent, name)
+	}
+	return nil
+}
+
+// Method to check if a feature is enabled for a player, empty for an
+// anonymous player.
+func (f *featureStore) Enabled(name, player string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.enabledLocked(name, player)
+}
+
+// Method to check if a feature is enabled, see Enabled. The lock must be held.
+func (f *featureStore) enabledLocked(name, player string) bool {
+	rule, ok := f.config.Features[name]
+	if !ok {
+		return knownFeatures[name].Default
+	}
+	if !rule.Enabled {
+		return false
+	}
+	if len(rule.Deployments) > 0 && !containsString(rule.Deployments, f.deployment) {
+		return false
+	}
+	if rule.Percent == nil && len(rule.Cohorts) == 0 {
+		return true
+	}
+	if player == "" {
+		return rule.Percent != nil && *rule.Percent >= 100
+	}
+	for _, cohort := range rule.Cohorts {
+		if containsString(f.config.Cohorts[cohort], player) {
+			return true
+		}
+	}
+	return rule.Percent != nil && featureBucket(name, player) < *rule.Percent
+}
+
+// Method to get the bucket, from 0 to 99, of a player in the rollout of a
+// feature. The buckets of a player differ between features so that the same
+// players do not get every experimental feature first.
+func featureBucket(name, player string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + "/" + player))
+	return int(h.Sum32() % 100)
+}
+
+// Method to check if a list of strings contains a string.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Method to list the features, with their state for a player.
+func (f *featureStore) list(player string) api.FeatureList {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list := api.FeatureList{
+		Deployment: f.deployment,
+		Features:   []api.Feature{},
+		Cohorts:    make(map[string][]string),
+	}
+	for name, feature := range knownFeatures {
+		if rule, ok := f.config.Features[name]; ok {
+			feature.Rule = &rule
+		}
+		feature.Enabled = f.enabledLocked(name, player)
+		list.Features = append(list.Features, feature)
+	}
+	sort.Slice(list.Features, func(i, j int) bool {
+		return list.Features[i].Name < list.Features[j].Name
+	})
+	for cohort, players := range f.config.Cohorts {
+		list.Cohorts[cohort] = players
+	}
+	return list
+}
+
+// Method to set the rule of a feature, or to put it back in its default state
+// if rule is nil. The flags are saved if they are kept in a file.
+func (f *featureStore) setRule(name string, rule *api.FeatureRule) error {
+	if rule != nil {
+		if err := validateFeatureRule(name, *rule); err != nil {
+			return err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.config.Features == nil {
+		f.config.Features = make(map[string]api.FeatureRule)
+	}
+	if rule == nil {
+		delete(f.config.Features, name)
+	} else {
+		f.config.Features[name] = *rule
+	}
+	return f.saveLocked()
+}
+
+// Method to set the players of a cohort, or to remove it if there is none.
+// The flags are saved if they are kept in a file.
+func (f *featureStore) setCohort(name string, players []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.config.Cohorts == nil {
+		f.config.Cohorts = make(map[string][]string)
+	}
+	if len(players) == 0 {
+		delete(f.config.Cohorts, name)
+	} else {
+		f.config.Cohorts[name] = players
+	}
+	return f.saveLocked()
+}
+
+// Method to save the flags to their file, if any. The file is replaced
+// atomically so that a crash never leaves it half written. The lock must be
+// held.
+func (f *featureStore) saveLocked() error {
+	if f.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(f.config, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// Method to check if a feature is enabled for a player, see
+// featureStore.Enabled.
+func featureEnabled(name, player string) bool {
+	return features.Enabled(name, player)
+}
+
+// Method to load the feature flags given by the flags. Returns an error if
+// they are invalid.
+func setupFeatures() error {
+	if *featuresFile == "" && *deploymentName == "" {
+		return nil
+	}
+	if err := features.load(*featuresFile, *deploymentName); err != nil {
+		return configError("unable to load the feature flags: %w", err)
+	}
+	return nil
+}
+
+// ***************************  Admin API ******************************
+
+// Handler of GET /admin/features, which lists the features as an
+// api.FeatureList. Add ?player= to get their state for a player.
+func (s *gameServer) handleListFeatures(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, features.list(r.URL.Query().Get("player")))
+}
+
+// Handler of PUT /admin/features/{name}, which sets the rule of a feature,
+// body api.FeatureRule.
+func (s *gameServer) handleSetFeature(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := knownFeatures[name]; !ok {
+		writeError(w, featureNotFound(name))
+		return
+	}
+	var rule api.FeatureRule
+	if apiErr := decodeRequest(w, r, &rule); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if err := validateFeatureRule(name, rule); err != nil {
+		writeError(w, api.NewError(api.CodeInvalidRequest, "%s", err.Error()))
+		return
+	}
+	s.updateFeatures(w, features.setRule(name, &rule))
+}
+
+// Handler of DELETE /admin/features/{name}, which puts a feature back in its
+// default state.
+func (s *gameServer) handleResetFeature(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := knownFeatures[name]; !ok {
+		writeError(w, featureNotFound(name))
+		return
+	}
+	s.updateFeatures(w, features.setRule(name, nil))
+}
+
+// Handler of PUT /admin/cohorts/{name}, which sets the players of a cohort,
+// body api.Cohort. A cohort without players is removed.
+func (s *gameServer) handleSetCohort(w http.ResponseWriter, r *http.Request) {
+	var cohort api.Cohort
+	if apiErr := decodeRequest(w, r, &cohort); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	s.updateFeatures(w, features.setCohort(r.PathValue("name"), cohort.Players))
+}
+
+// Method to reply to a change of the feature flags with the new list of
+// features.
+func (s *gameServer) updateFeatures(w http.ResponseWriter, err error) {
+	if err != nil {
+		defaultLogger.Errorf("Unable to update the feature flags, error %v", err)
+		writeError(w, api.NewError(api.CodeInternal, "unable to update the feature flags"))
+		return
+	}
+	writeJSON(w, http.StatusOK, features.list(""))
+}
+
+// Method to build the error returned for an unknown feature.
+func featureNotFound(name string) *api.Error {
+	return api.NewError(api.CodeFeatureNotFound, "no feature named %q", name).
+		WithDetail("name", name)
+}
+
+// Method to build the error returned when a feature is disabled for a player.
+func featureDisabled(name, what string) *api.Error {
+	return api.NewError(api.CodeForbidden, "%s are not enabled", what).
+		WithDetail("feature", name)
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+type FeaturesTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+func (s *FeaturesTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bird"})
+	features = &featureStore{}
+	server := newGameServer()
+	server.adminToken = "secret"
+	server.shame = newShameStore(filepath.Join(s.T().TempDir(), "shame.jsonl"))
+	s.server = httptest.NewServer(server.Handler())
+}
+
+func (s *FeaturesTestSuite) TearDownTest() {
+	s.server.Close()
+	features = &featureStore{}
+}
+
+// Method to send a request to the server, with the admin token if asked.
+func (s *FeaturesTestSuite) do(method, path, body string, admin bool,
+	out interface{}) int {
+	req, err := http.NewRequest(method, s.server.URL+path, bytes.NewBufferString(body))
+	assert.Nil(s.T(), err)
+	if admin {
+		req.Header.Set("Authorization", "Bearer secret")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+func percent(p int) *int {
+	return &p
+}
+
+func (s *FeaturesTestSuite) TestRules() {
+	assert.Equal(s.T(), true, featureEnabled(featureChallengeGames, "alice"))
+
+	assert.Nil(s.T(), features.setRule(featureChallengeGames, &api.FeatureRule{}))
+	assert.Equal(s.T(), false, featureEnabled(featureChallengeGames, "alice"))
+
+	// Only on the listed deployments.
+	assert.Nil(s.T(), features.setRule(featureChallengeGames,
+		&api.FeatureRule{Enabled: true, Deployments: []string{"staging"}}))
+	assert.Equal(s.T(), false, featureEnabled(featureChallengeGames, "alice"))
+	assert.Nil(s.T(), features.load("", "staging"))
+	assert.Nil(s.T(), features.setRule(featureChallengeGames,
+		&api.FeatureRule{Enabled: true, Deployments: []string{"staging"}}))
+	assert.Equal(s.T(), true, featureEnabled(featureChallengeGames, "alice"))
+	assert.Equal(s.T(), true, featureEnabled(featureChallengeGames, ""))
+
+	// Only for the cohorts.
+	assert.Nil(s.T(), features.setCohort("beta", []string{"alice"}))
+	assert.Nil(s.T(), features.setRule(featureChallengeGames,
+		&api.FeatureRule{Enabled: true, Cohorts: []string{"beta"}}))
+	assert.Equal(s.T(), true, featureEnabled(featureChallengeGames, "alice"))
+	assert.Equal(s.T(), false, featureEnabled(featureChallengeGames, "bob"))
+	assert.Equal(s.T(), false, featureEnabled(featureChallengeGames, ""))
+
+	assert.NotNil(s.T(), features.setRule("unknown", &api.FeatureRule{}))
+	assert.NotNil(s.T(), features.setRule(featureChallengeGames,
+		&api.FeatureRule{Enabled: true, Percent: percent(101)}))
+}
+
+func (s *FeaturesTestSuite) TestGradualRollout() {
+	players := make([]string, 1000)
+	for i := range players {
+		players[i] = "player" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+	}
+	enabled := func() map[string]bool {
+		result := make(map[string]bool)
+		for _, player := range players {
+			if featureEnabled(featureEntropyOpponent, player) {
+				result[player] = true
+			}
+		}
+		return result
+	}
+	assert.Nil(s.T(), features.setRule(featureEntropyOpponent,
+		&api.FeatureRule{Enabled: true, Percent: percent(10)}))
+	few := enabled()
+	assert.InDelta(s.T(), 100, len(few), 40)
+	assert.Equal(s.T(), false, featureEnabled(featureEntropyOpponent, ""))
+
+	// The players keep the feature as the rollout grows.
+	assert.Nil(s.T(), features.setRule(featureEntropyOpponent,
+		&api.FeatureRule{Enabled: true, Percent: percent(50)}))
+	many := enabled()
+	assert.InDelta(s.T(), 500, len(many), 60)
+	for player := range few {
+		assert.Equal(s.T(), true, many[player])
+	}
+
+	assert.Nil(s.T(), features.setRule(featureEntropyOpponent,
+		&api.FeatureRule{Enabled: true, Percent: percent(100)}))
+	assert.Equal(s.T(), len(players), len(enabled()))
+	assert.Equal(s.T(), true, featureEnabled(featureEntropyOpponent, ""))
+}
+
+func (s *FeaturesTestSuite) TestFile() {
+	path := filepath.Join(s.T().TempDir(), "features.json")
+	assert.Nil(s.T(), features.load(path, ""))
+	assert.Nil(s.T(), features.setCohort("beta", []string{"alice"}))
+	assert.Nil(s.T(), features.setRule(featureChallengePoints, &api.FeatureRule{}))
+
+	loaded := &featureStore{}
+	assert.Nil(s.T(), loaded.load(path, ""))
+	assert.Equal(s.T(), false, loaded.Enabled(featureChallengePoints, "alice"))
+	assert.Equal(s.T(), []string{"alice"}, loaded.list("").Cohorts["beta"])
+
+	assert.Nil(s.T(), ioutil.WriteFile(path,
+		[]byte(`{"features": {"teleport": {"enabled": true}}}`), 0644))
+	assert.NotNil(s.T(), loaded.load(path, ""))
+}
+
+func (s *FeaturesTestSuite) TestGatedFeatures() {
+	assert.Nil(s.T(), features.setRule(featureChallengeGames, &api.FeatureRule{}))
+	var errResp api.ErrorResponse
+	status := s.do("POST", "/games", `{"retries": 3, "challenge": true}`, false, &errResp)
+	assert.Equal(s.T(), http.StatusForbidden, status)
+	assert.Equal(s.T(), featureChallengeGames, errResp.Error.Details["feature"])
+
+	assert.Nil(s.T(), features.setRule(featureChallengePoints, &api.FeatureRule{}))
+	assert.Equal(s.T(), 1, gamePoints(api.LeaderboardEntry{Won: true, Challenge: true}))
+
+	assert.Nil(s.T(), features.setRule(featureEntropyOpponent, &api.FeatureRule{}))
+	_, apiErr := newOfflineEngine().createGame([]byte(
+		`{"word_length": 4, "retries": 3, "opponent": "entropy"}`))
+	assert.Equal(s.T(), api.CodeForbidden, apiErr.Code)
+}
+
+func (s *FeaturesTestSuite) TestAdminAPI() {
+	// Method to send an admin request and decode the features it returns.
+	admin := func(method, path, body string) (int, api.FeatureList) {
+		var list api.FeatureList
+		status := s.do(method, path, body, true, &list)
+		return status, list
+	}
+	var errResp api.ErrorResponse
+	assert.Equal(s.T(), http.StatusForbidden,
+		s.do("GET", "/admin/features", "", false, &errResp))
+	status, list := admin("GET", "/admin/features", "")
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.Equal(s.T(), 3, len(list.Features))
+	assert.Equal(s.T(), featureChallengeGames, list.Features[0].Name)
+	assert.Equal(s.T(), true, list.Features[0].Enabled)
+	assert.Nil(s.T(), list.Features[0].Rule)
+
+	status, _ = admin("PUT", "/admin/cohorts/beta", `{"players": ["alice"]}`)
+	assert.Equal(s.T(), http.StatusOK, status)
+	status, list = admin("PUT", "/admin/features/challenge_games",
+		`{"enabled": true, "cohorts": ["beta"]}`)
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.Equal(s.T(), false, list.Features[0].Enabled)
+	_, list = admin("GET", "/admin/features?player=alice", "")
+	assert.Equal(s.T(), true, list.Features[0].Enabled)
+	assert.Equal(s.T(), []string{"beta"}, list.Features[0].Rule.Cohorts)
+	assert.Equal(s.T(), []string{"alice"}, list.Cohorts["beta"])
+
+	assert.Equal(s.T(), http.StatusBadRequest, s.do("PUT",
+		"/admin/features/challenge_games", `{"enabled": true, "percent": -1}`, true,
+		&errResp))
+	assert.Equal(s.T(), http.StatusNotFound, s.do("PUT", "/admin/features/teleport",
+		`{"enabled": true}`, true, &errResp))
+	assert.Equal(s.T(), api.CodeFeatureNotFound, errResp.Error.Code)
+
+	status, list = admin("DELETE", "/admin/features/challenge_games", "")
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.Nil(s.T(), list.Features[0].Rule)
+	assert.Equal(s.T(), true, list.Features[0].Enabled)
+}
+
+func TestFeaturesTestSuite(t *testing.T) {
+	suite.Run(t, new(FeaturesTestSuite))
+}
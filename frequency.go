@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	commonWords = flag.Int("common_words", 0,
+		"Only play with the most frequent words of the dictionary: this "+
+			"percentage of the words of every length, by the frequencies of the "+
+			"dictionary (\"word<TAB>frequency\" lines). The words without a "+
+			"frequency are the least frequent. All the words are kept if zero.")
+	favorCommonWords = flag.Bool("favor_common_words", false,
+		"Pick the secret word of the honest games (the \"honest\" adversary of "+
+			"the arena, the versus mode) and the word revealed at the end of the "+
+			"games with a probability proportional to its frequency.")
+)
+
+// Separator of a word and its frequency in a dictionary line, e.g.
+// "house\t1234" or "paris|capital city\t567".
+const frequencySeparator = "\t"
+
+// Method to split the lines of a word list into the lines without their
+// frequency (the words, with their clue if any) and the frequencies of the
+// words, by word. The list is returned as is if no line has a frequency.
+func splitFrequencies(lines []string) ([]string, map[string]float64) {
+	words := lines
+	var frequencies map[string]float64
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		sep := strings.LastIndex(line, frequencySeparator)
+		if sep < 0 {
+			continue
+		}
+		frequency, err := strconv.ParseFloat(strings.TrimSpace(line[sep+1:]), 64)
+		if err != nil || frequency < 0 {
+			// Left as is, the word is then discarded as invalid.
+			continue
+		}
+		if frequencies == nil {
+			// Copied so that the list of the caller is not modified.
+			words = append([]string{}, lines...)
+			frequencies = make(map[string]float64)
+		}
+		words[i] = strings.TrimSpace(line[:sep])
+		word, _ := splitHint(words[i])
+		frequencies[word] = frequency
+	}
+	return words, frequencies
+}
+
+// Method to get the frequency of a word, as given by the dictionary.
+// Returns false if the word has no frequency.
+func (d *Dictionary) Frequency(word string) (float64, bool) {
+	frequency, ok := d.frequencies[word]
+	return frequency, ok
+}
+
+// Method to check if any word of the dictionary has a frequency.
+func (d *Dictionary) HasFrequencies() bool {
+	return len(d.frequencies) > 0
+}
+
+// Method to check the percentage of --common_words.
+func validateCommonWords(percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("--common_words must be between 0 and 100, got %d", percent)
+	}
+	return nil
+}
+
+// Method to keep only the given percentage of the words of every length, the
+// most frequent ones (at least one word per length). Ties are broken by the
+// order of the words. Nothing is removed if the percentage is zero or 100.
+// Must only be called while the dictionary is built.
+func (d *Dictionary) keepCommonWords(percent int) {
+	if percent <= 0 || percent >= 100 {
+		return
+	}
+	kept := make(map[string]bool)
+	for _, length := range d.Lengths() {
+		words := append([]string{}, d.Words(length)...)
+		sort.SliceStable(words, func(i, j int) bool {
+			return d.frequencies[words[i]] > d.frequencies[words[j]]
+		})
+		count := (len(words)*percent + 99) / 100
+		for _, word := range words[:count] {
+			kept[word] = true
+		}
+	}
+	d.report.Uncommon += d.retainWords(func(word string) bool {
+		return kept[word]
+	})
+}
+
+// Method to pick one of the words of a game, uniformly or, with
+// --favor_common_words, in proportion of their frequency in the dictionary.
+// The words are picked uniformly if none of them has a frequency.
+func pickWord(rng *rand.Rand, d *Dictionary, words []string) int {
+	if !*favorCommonWords || d == nil || !d.HasFrequencies() {
+		return rng.Intn(len(words))
+	}
+	total := 0.0
+	for _, word := range words {
+		total += d.frequencies[word]
+	}
+	if total <= 0 {
+		return rng.Intn(len(words))
+	}
+	target := rng.Float64() * total
+	for i, word := range words {
+		target -= d.frequencies[word]
+		if target < 0 {
+			return i
+		}
+	}
+	// Rounding errors may leave a bit of the total, which goes to the last
+	// word with a frequency.
+	for i := len(words) - 1; i > 0; i-- {
+		if d.frequencies[words[i]] > 0 {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+type FrequencyTestSuite struct {
+	suite.Suite
+}
+
+func (s *FrequencyTestSuite) TearDownTest() {
+	*favorCommonWords = false
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to load a dictionary of the given lines, keeping a percentage of the
+// most frequent words.
+func (s *FrequencyTestSuite) load(lines string, percent int) *Dictionary {
+	source := dictionarySourceFromFlags()
+	source.index = ""
+	source.file = filepath.Join(s.T().TempDir(), "words.txt")
+	source.commonWords = percent
+	assert.Nil(s.T(), ioutil.WriteFile(source.file, []byte(lines), 0644))
+	d, err := loadDictionary(source)
+	assert.Nil(s.T(), err)
+	return d
+}
+
+func (s *FrequencyTestSuite) TestSplitFrequencies() {
+	words, frequencies := splitFrequencies([]string{"house\t120", "Paris|city\t7.5\r",
+		"cat", "dog\tmany"})
+	assert.Equal(s.T(), []string{"house", "Paris|city", "cat", "dog\tmany"}, words)
+	assert.Equal(s.T(), map[string]float64{"house": 120, "Paris": 7.5}, frequencies)
+
+	lines := []string{"last", "fast"}
+	words, frequencies = splitFrequencies(lines)
+	assert.Equal(s.T(), lines, words)
+	assert.Nil(s.T(), frequencies)
+}
+
+func (s *FrequencyTestSuite) TestFrequencies() {
+	d := s.load("Last\t10\nfast|quick\t3\nbets\ncode\t0.5", 0)
+	assert.Equal(s.T(), []string{"bets", "code", "fast", "last"}, d.Words(4))
+	frequency, ok := d.Frequency("last")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 10.0, frequency)
+	_, ok = d.Frequency("bets")
+	assert.False(s.T(), ok)
+	hint, _ := d.Hint("fast")
+	assert.Equal(s.T(), "quick", hint)
+	assert.True(s.T(), d.HasFrequencies())
+	assert.False(s.T(), s.load("last\nfast", 0).HasFrequencies())
+}
+
+func (s *FrequencyTestSuite) TestCommonWords() {
+	d := s.load("last\t10\nfast\t3\nbets\ncode\t5\nhorse\t1\nmouse\t2", 50)
+	assert.Equal(s.T(), []string{"code", "last"}, d.Words(4))
+	// At least one word of every length is kept.
+	assert.Equal(s.T(), []string{"mouse"}, d.Words(5))
+	assert.Equal(s.T(), 3, d.Report().Uncommon)
+
+	source := dictionarySourceFromFlags()
+	source.commonWords = 101
+	_, err := loadDictionary(source)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *FrequencyTestSuite) TestPickWord() {
+	d := s.load("last\t1\nfast\t99\nbets\ncode", 0)
+	words := []string{"bets", "code", "fast", "last"}
+	rng := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[words[pickWord(rng, d, words)]]++
+	}
+	// The words are picked uniformly without --favor_common_words.
+	assert.InDelta(s.T(), 250, counts["bets"], 60)
+
+	*favorCommonWords = true
+	counts = make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[words[pickWord(rng, d, words)]]++
+	}
+	assert.Equal(s.T(), 0, counts["bets"]+counts["code"])
+	assert.Greater(s.T(), counts["fast"], 950)
+	// Without any frequency, the words are picked uniformly.
+	assert.Contains(s.T(), []int{0, 1}, pickWord(rng, d, []string{"bets", "code"}))
+}
+
+func (s *FrequencyTestSuite) TestFavorCommonSecretWord() {
+	*favorCommonWords = true
+	currentDict.Store(s.load("last\t0\nfast\t1\nbets\ncode", 0))
+	for seed := int64(0); seed < 10; seed++ {
+		game, err := NewGame(4, 3, withSecretWord(seed))
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), []string{"fast"}, game.Candidates())
+	}
+}
+
+func TestFrequencyTestSuite(t *testing.T) {
+	suite.Run(t, new(FrequencyTestSuite))
+}
@@ -1,32 +1,32 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
-	"github.com/golang/glog"
-	"io/ioutil"
-	"os"
-	"regexp"
+	"github.com/hackeracc/WordGuess/engine"
+	"math/rand"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
+	"unicode/utf8"
 )
 
 var (
-	dictionaryFile = flag.String("dictionary", "dictionary.txt",
-		"Absolute path of the file which contains the dictionary of words")
-
-	// Regex for a valid word (which contains only english alphabets).
-	isLetter = regexp.MustCompile(`^[a-zA-Z]+$`).MatchString
-
-	// Global map used to store all the dictionary words. The key is the length
-	// of the word and value is the list of words matching that length.
-	dictionaryMap map[int][]string
+	dictionaryFile = flag.String("dictionary", "",
+		"Absolute path of the file which contains the dictionary of words, or an "+
+			"http(s) URL to download it from. It can be gzip compressed. The English "+
+			"word list embedded in the binary is used if empty.")
 )
 
 const (
-	emptyChar = '_'
+	// Character of the patterns of the engine for a letter which is yet to be
+	// guessed. It is not printable so it never clashes with a letter of the
+	// dictionary, e.g. an "_" in a custom alphabet. Patterns are displayed
+	// using a PatternFormat.
+	emptyChar = engine.Hidden
 
 	// Enums for state of the game.
 	Running GameState = iota
@@ -34,84 +34,193 @@ const (
 	Lost
 	// User won while playing the game.
 	Won
-
-	// Error codes while creating a new game.
-	NoError InputError = iota
-	InvalidRetries
-	InvalidLength
+	// User forfeited the game, e.g. a correspondence player who did not guess
+	// before the game expired. The game is lost without running out of
+	// retries.
+	Forfeited
 )
 
 type GameState int
-type InputError int
 
 // Game struct, new instance is created for every new game to be played.
+// The methods of a game can be called from multiple goroutines. The exported
+// fields should only be read directly when no other goroutine is playing the
+// game.
 type Game struct {
 	// Expected length of the chosen word.
 	ExpectedLength int
-	// List of current set of words chosen by the computer.
+	// List of current set of words chosen by the computer. It is empty in
+	// compact mode, where the words are kept packed; Candidates returns the
+	// words in both modes.
 	CurrentSetOfWords []string
 	// Total retries allowed.
 	AllowedRetries int
-	// Current retries left.
+	// Current retries left. It can be negative once the game is lost, or with
+	// the unlimited retry policy.
 	CurrentRetries int
+	// When the wrong guesses lose the game. Defaults to RetryStrict.
+	RetryPolicy RetryPolicy
 	// Used characters.
 	UsedChars []rune
 	// Current regex shown to the user.
-	// Please note we use "_" to represent a character which is yet to be guessed.
+	// Please note we use emptyChar to represent a character which is yet to be
+	// guessed. Use a PatternFormat to display it.
 	CurrentDisplayedWord []rune
 	// Current state of the game.
 	State GameState
+	// Logger used while playing the game. Defaults to the logger set using
+	// SetLogger.
+	Logger Logger
+	// Max time allowed for a single guess. A retry is consumed every time
+	// the player fails to guess in time. Zero means there is no time limit.
+	GuessTimeout time.Duration
+	// Turns played so far, including the timeouts, in order.
+	Turns []Turn
+	// Decision made for the last guess, nil before the first guess.
+	LastDecision *Decision
+	// Number of hints shown to the player, see UseHint.
+	HintsUsed int
+
+	// Guards all the fields of the game.
+	mu sync.Mutex
+	// Dictionary the game was created with.
+	dict *Dictionary
+	// Current set of words in compact mode, nil otherwise.
+	packed []packedWord
+	// Current set of words as a set of the words of the index of the
+	// dictionary, see wordIndex.maxDecision. Nil if the words are partitioned
+	// one by one, e.g. once the set was changed by an option of the game. The
+	// set is never modified, it can be shared with other games.
+	bits bitset
+	// Clock used to enforce the guess timeout.
+	clock Clock
+	// Time by which the next guess is expected (only used with a timeout).
+	deadline time.Time
+	// Notified of the events of the game, see WithObserver.
+	observers []Observer
+	// Filter of the words revealed to the player, nil if not set.
+	revealPolicy RevealPolicy
+	// Word revealed once the game is over, empty till then, see RevealWord.
+	revealed string
+	// Personality of the computer, nil for the default vindictive one.
+	strategy Strategy
+	// Seed and source of the random choices of the game, see WithSeed.
+	seed int64
+	rng  *rand.Rand
+	// Word shown and number of candidates when the game started, and the
+	// turns so far, see Replay.
+	replayStart      string
+	replayCandidates int
+	replaySteps      []ReplayStep
+	// Snapshots of the turns for the analysis of the game, nil if not
+	// enabled, see WithAnalysis.
+	analysis *gameAnalysis
 }
 
+// Optional configuration of a new game, passed to NewGame.
+type GameOption func(*Game)
+
 // ******************* Methods to init the game ************************
 
 // Method to init the game once. It loads all the dictionary words in memory.
 // Custom dictionary words can also be passed. This is mainly used for testing.
 // This method should be called only once and multiple instances of the game can
-// be played.
-func InitGame(customWordList []string) {
-	var wordList []string
-	if customWordList == nil || len(customWordList) == 0 {
-		// Load all the words in memory.
-		data, err := ioutil.ReadFile(*dictionaryFile)
-		if err != nil {
-			fmt.Println("Unable to read file ", *dictionaryFile, ",error ", err)
-			os.Exit(1)
-		}
-		wordList = strings.Split(string(data), "\n")
-	} else {
-		wordList = customWordList
+// be played. Calling it again replaces the dictionary for the new games, while
+// the games already created keep using the old one.
+// It returns an error wrapping ErrInvalidConfig if the dictionary given by the
+// flags can not be loaded, in which case the dictionary does not change.
+func InitGame(customWordList []string) error {
+	return InitGameCtx(context.Background(), customWordList)
+}
+
+// Method to init the game like InitGame. The context bounds the time taken to
+// download the dictionary given by the flags as a URL: the dictionary does not
+// change if the context is done first, and the error does not wrap
+// ErrInvalidConfig.
+func InitGameCtx(ctx context.Context, customWordList []string) error {
+	if len(customWordList) > 0 {
+		// The dictionary is fully built before it is made visible, so games
+		// created concurrently see either the old or the new dictionary.
+		alphabet := NewAlphabet(dictionaryAlphabet())
+		alphabet.Phrases = *phraseMode
+		alphabet.Punctuation = *punctuation
+		alphabet.Folding = CaseFolding(*caseFolding)
+		d := newDictionary(customWordList, alphabet, DictionaryMetadata{}, *compactWords)
+		d.limitLengths(*minWordLength, *maxWordLength)
+		currentDict.Store(d)
+		return nil
+	}
+	d, err := loadDictionaryCtx(ctx, dictionarySourceFromFlags())
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("unable to load the dictionary: %w", err)
+	}
+	if err != nil {
+		return configError("unable to load the dictionary: %w", err)
 	}
-	// Sanitize the strings in the dictionary and also do preprocessing to build
-	// a map where key is the length of the word and value is the slice of all
-	// words of that length.
-	dictionaryMap = buildLenBasedDictionary(wordList)
+	currentDict.Store(d)
+	return nil
 }
 
 // Method to initialize one instance of a new game.
 // This method returns a new instance of the game if the input is valid.
-// It returns the input error code in case there was an error in the input.
-func NewGame(expectedLen, maxretries int) (*Game, InputError) {
+// It returns an error wrapping ErrInvalidLength or ErrInvalidRetries in case
+// there was an error in the input.
+// Optional configuration like a guess timeout can be passed as options.
+func NewGame(expectedLen, maxretries int, opts ...GameOption) (*Game, error) {
+	dict := currentDictionary()
+	// Checked before the word is allocated, e.g. for a negative length sent
+	// to the server.
+	if expectedLen <= 0 {
+		return nil, newGameError(ErrInvalidLength,
+			"Word length %d is not allowed, playable lengths: %s", expectedLen,
+			formatLengths(dict.AvailableLengths()))
+	}
 	g := &Game{
 		ExpectedLength: expectedLen,
-		CurrentSetOfWords: dictionaryMap[expectedLen],
 		AllowedRetries: maxretries,
 		CurrentRetries: maxretries,
 		CurrentDisplayedWord: make([]rune, expectedLen),
 		State: Running,
+		Logger: defaultLogger,
+		dict: dict,
+		clock: realClock{},
 	}
 	// Validate the expected length and allowed retries values.
-	if !validateLength(expectedLen) {
-		return nil, InvalidLength
+	if !wordLengthAllowed(expectedLen) {
+		return nil, newGameError(ErrInvalidLength,
+			"Word length %d is not allowed, playable lengths: %s", expectedLen,
+			formatLengths(dict.AvailableLengths()))
+	}
+	if !validateLength(dict, expectedLen) {
+		return nil, newGameError(ErrInvalidLength,
+			"No words of length %d in the dictionary", expectedLen)
 	}
 	if !validateNumRetries(maxretries) {
-		return nil, InvalidRetries
+		return nil, newGameError(ErrInvalidRetries,
+			"Retries must be between 0 and %d, got %d", *maxAllowedRetries, maxretries)
+	}
+	if packed, ok := dict.packed[expectedLen]; ok {
+		g.packed = packed
+	} else {
+		g.CurrentSetOfWords = dict.Words(expectedLen)
+		if idx, ok := dict.index[expectedLen]; ok {
+			g.bits = fullBitset(len(idx.words))
+		}
 	}
 	// Initialize the current display word as all empty characters.
 	for i, _ := range g.CurrentDisplayedWord {
 		g.CurrentDisplayedWord[i] = emptyChar
 	}
-	return g, NoError
+	// The seed is picked before the options, which can replace it.
+	g.randLocked()
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.revealSeparators()
+	g.startReplayLocked()
+	g.resetDeadline()
+	gameMetrics.sessionStarted()
+	return g, nil
 }
 
 // ******************* Methods to play the game ************************
@@ -137,58 +246,237 @@ func NewGame(expectedLen, maxretries int) (*Game, InputError) {
 // Returns:
 // Bool: true if its a correct guess.
 // error: Returns an error with the user input. Error is returned if the input is
-//   not a valid alphabet or the user input was already used.
+//   not a valid alphabet (ErrInvalidCharacter), the user input was already used
+//   (ErrCharAlreadyUsed) or the game is not running (ErrGameFinished).
 func (g *Game) CheckUserInput(char rune) (bool, error) {
-	// Check if game state is not running, return.
-	if g.State != Running {
-		err := errors.New("Unexpected scenario: input given for a game which is not running")
+	return g.CheckUserInputCtx(context.Background(), char)
+}
+
+// Method to check the user input like CheckUserInput, for a turn which ends when
+// the context is done. If the deadline of the context has passed, the turn is
+// recorded as a timeout (costing a retry, unless the guess timeout of the game
+// already took one for it) and ErrTurnTimeout is returned. If the context was
+// canceled, the context error is returned and the game is not changed.
+func (g *Game) CheckUserInputCtx(ctx context.Context, char rune) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.startTurnLocked(ctx); err != nil {
+		return false, err
+	}
+	// 'A' and 'a' are the same guess, unless the dictionary is case sensitive.
+	char = g.dict.alphabet.Fold(char)
+	g.Logger.Infof("Current word list %+v, input character %c", g.CurrentSetOfWords, char)
+	if !g.dict.alphabet.Contains(char) {
+		err := newGameError(ErrInvalidCharacter,
+			"Character %s is not a valid letter for this dictionary. " +
+			"Please enter a new character.", string(char))
 		return false, err
 	}
-	glog.Infof("Current word list %+v, input character %d", g.CurrentSetOfWords, char)
 	if contains(g.UsedChars, char) {
-		err := fmt.Errorf("Character %s has been used. " +
+		err := newGameError(ErrCharAlreadyUsed, "Character %s has been used. " +
 			"Please enter a new character.", string(char))
 		return false, err
 	}
 	g.UsedChars = append(g.UsedChars, char)
-	// Get the group with max possibilities.
-	newSet, newRegex := getMaxSet(g.CurrentSetOfWords,
-		g.CurrentDisplayedWord, char)
-	g.CurrentSetOfWords = newSet
-	glog.Infof("New word list after processing character %s: %v", string(char), g.CurrentSetOfWords)
+	g.resetDeadline()
+	start := time.Now()
+	defer func() {
+		gameMetrics.observeGuess(time.Since(start))
+		if g.State != Running {
+			g.endedLocked()
+		}
+	}()
+	// Get the group with max possibilities, unless the strategy of the game
+	// picks another one.
+	var decision Decision
+	if g.packed != nil {
+		newSet, maxDecision := getMaxSetPacked(g.Logger, g.packed, g.ExpectedLength,
+			g.CurrentDisplayedWord, char)
+		decision = g.chooseLocked(maxDecision)
+		if decision.Pattern != maxDecision.Pattern {
+			newSet = packedInPartition(g.packed, g.ExpectedLength, decision)
+		}
+		g.packed = newSet
+		g.Logger.Infof("%d words left after processing character %s", len(g.packed), string(char))
+	} else if g.bits != nil {
+		idx := g.dict.index[g.ExpectedLength]
+		maxDecision, kept := idx.cachedMaxDecision(g.Logger, g.bits, g.CurrentDisplayedWord, char)
+		decision = g.chooseLocked(maxDecision)
+		if decision.Pattern != maxDecision.Pattern {
+			kept = idx.partition(g.bits, g.CurrentDisplayedWord, char, decision.Pattern)
+		}
+		g.bits = kept
+		g.CurrentSetOfWords = idx.wordsOf(g.bits)
+		g.Logger.Infof("%d words left after processing character %s", len(g.CurrentSetOfWords),
+			string(char))
+	} else {
+		newSet, maxDecision := getMaxSet(g.Logger, g.CurrentSetOfWords,
+			g.CurrentDisplayedWord, char)
+		decision = g.chooseLocked(maxDecision)
+		if decision.Pattern != maxDecision.Pattern {
+			newSet = wordsInPartition(g.CurrentSetOfWords, decision)
+		}
+		g.CurrentSetOfWords = newSet
+		g.Logger.Infof("New word list after processing character %s: %v", string(char), g.CurrentSetOfWords)
+	}
+	g.LastDecision = &decision
+	newRegex := decision.Pattern
 	// Check if the new regex is same as the previous regex which means input was
 	// not accepted.
 	if newRegex == string(g.CurrentDisplayedWord) {
 		// Reduce the retries only if its an incorrect guess.
-		g.CurrentRetries --
-		if g.CurrentRetries < 0 {
-			g.State = Lost
-		}
+		g.consumeRetry()
+		g.recordTurnLocked(Turn{Kind: TurnGuess, Char: char})
 		return false, nil
 	}
-	g.CurrentDisplayedWord = []rune(newRegex)
+	g.revealLocked([]rune(newRegex))
 	if !contains(g.CurrentDisplayedWord, emptyChar) {
 		g.State = Won
-		return true, nil
 	}
+	g.recordTurnLocked(Turn{Kind: TurnGuess, Char: char, Accepted: true})
 	return true, nil
 }
 
+// Method to check that a turn can be played before its input is checked. The
+// game lock must be held.
+// Returns an error if the game is not running, also once the retries for the
+// deadlines missed before the turn are consumed, or if the turn ended with the
+// context.
+func (g *Game) startTurnLocked(ctx context.Context) error {
+	// Check if game state is not running, return.
+	if g.State == Forfeited {
+		return newGameError(ErrGameExpired,
+			"The game was forfeited, it does not take any more input")
+	}
+	if g.State != Running {
+		return newGameError(ErrGameFinished,
+			"Unexpected scenario: input given for a game which is not running")
+	}
+	// Consume the retries for the deadlines missed before this guess was given.
+	timedOut := g.tickLocked()
+	if g.State != Running {
+		return newGameError(ErrGameExpired,
+			"Time is up: the game was lost before the input was given")
+	}
+	if err := ctx.Err(); err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		if !timedOut {
+			g.timeoutLocked()
+			g.resetDeadline()
+		}
+		return newGameError(ErrTurnTimeout,
+			"Time is up: the input was given after the end of the turn")
+	}
+	g.snapshotTurnLocked()
+	return nil
+}
+
+// Method to reveal the spaces between the words of the phrases and the
+// punctuation of the words, which are never guessed. The words of the same
+// length can have them at different positions, so the positions are picked the
+// same way as for a guess: the words with the largest common layout are kept.
+func (g *Game) revealSeparators() {
+	for _, separator := range g.dict.alphabet.revealedChars() {
+		found := false
+		for _, word := range g.CurrentSetOfWords {
+			if strings.ContainsRune(word, separator) {
+				found = true
+				break
+			}
+		}
+		if found {
+			g.revealSeparator(separator)
+		}
+	}
+}
+
+// Method to reveal a single character which is never guessed, see
+// revealSeparators.
+func (g *Game) revealSeparator(separator rune) {
+	if g.bits != nil {
+		idx := g.dict.index[g.ExpectedLength]
+		decision, kept := idx.cachedMaxDecision(g.Logger, g.bits, g.CurrentDisplayedWord,
+			separator)
+		g.bits = kept
+		g.CurrentSetOfWords = idx.wordsOf(g.bits)
+		g.revealLocked([]rune(decision.Pattern))
+		return
+	}
+	newSet, decision := getMaxSet(g.Logger, g.CurrentSetOfWords,
+		g.CurrentDisplayedWord, separator)
+	g.CurrentSetOfWords = newSet
+	g.revealLocked([]rune(decision.Pattern))
+}
+
+// Method to get the current set of words chosen by the computer, in both the
+// normal and the compact mode.
+func (g *Game) Candidates() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.candidatesLocked()
+}
+
+// Method to get the current set of words, the game lock must be held.
+func (g *Game) candidatesLocked() []string {
+	if g.packed != nil {
+		return unpackWords(g.packed, g.ExpectedLength)
+	}
+	return g.CurrentSetOfWords
+}
+
+// Method to lose a game which is still running when the player forfeits it,
+// e.g. a bot of the arena or a correspondence player who did not guess in
+// time.
+func (g *Game) forfeit() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.State == Running {
+		g.State = Forfeited
+		g.endedLocked()
+	}
+}
+
+// Method to reduce the retries left, which loses the game as decided by the
+// retry policy.
+func (g *Game) consumeRetry() {
+	g.CurrentRetries --
+	if g.RetryPolicy.lost(g.CurrentRetries) {
+		g.State = Lost
+	}
+}
+
 // Method to get the max set.
 // Params:
+// logger: Logger for the verbose logs of the decision.
 // wordList: List of words from which the program can chose any word as the secret word.
 // currWord: This is the string representation of the current word shown to the
-//   user. Please note we use "_" to represent a character which is not yet guessed.
+//   user. Please note we use emptyChar to represent a character which is not yet guessed.
 // char: Current input character from the user.
 //
 // Returns:
 // 1. The new set of words which can be the potential candidates based on user input.
 //    This will be based on evaluating all possibilities whether the user input
 //    character is accepted or not.
-// 2. The string representation of the word to be shown to the user after the
-//    program has made a best decision whether the input character is to be accepted
-//    or not.
-func getMaxSet(wordList []string, currWord []rune, char rune) ([]string, string) {
+// 2. The decision made, whose Pattern is the string representation of the word
+//    to be shown to the user after the program has made a best decision whether
+//    the input character is to be accepted or not.
+// The words are partitioned with position masks, see getMaxSetMasks, unless
+// they are too long for the masks.
+func getMaxSet(logger Logger, wordList []string, currWord []rune, char rune) ([]string,
+	Decision) {
+	if len(currWord) <= maxMaskLength {
+		return getMaxSetMasks(logger, wordList, currWord, char)
+	}
+	return getMaxSetStrings(logger, wordList, currWord, char)
+}
+
+// Method to get the max set, see getMaxSet, by grouping the words by the
+// pattern they would show. A pattern string is built for every word, which is
+// slow on large lists but works for words of any length.
+func getMaxSetStrings(logger Logger, wordList []string, currWord []rune, char rune) ([]string,
+	Decision) {
 	// Map to store all the possibilities. Possibilities can be:
 	// 1. The input character is not accepted.
 	// 2. The input character is accepted at a particular location.
@@ -201,9 +489,9 @@ func getMaxSet(wordList []string, currWord []rune, char rune) ([]string, string)
 	// in the map possibilitiesMap.
 	var maxSet string
 	for _, word := range wordList {
-		glog.Infof("Checking string %s, current input character %v", word, string(char))
+		logger.Infof("Checking string %s, current input character %v", word, string(char))
 		if !strings.ContainsRune(word, char) {
-			glog.Infof("String does not contain rune")
+			logger.Infof("String does not contain rune")
 			if _, ok := possiblitiesMap[string(currWord)]; ok{
 				possiblitiesMap[string(currWord)] = append(possiblitiesMap[string(currWord)], word)
 			} else {
@@ -218,7 +506,9 @@ func getMaxSet(wordList []string, currWord []rune, char rune) ([]string, string)
 			// Check the regex if the character is present.
 			modifiedInput := make([]rune, len(currWord))
 			copy(modifiedInput, currWord)
-			for idx, wordChar := range word {
+			// Iterate over the runes (and not the bytes) so that the index
+			// matches the position in the displayed word.
+			for idx, wordChar := range []rune(word) {
 				if wordChar == char {
 					modifiedInput[idx] = wordChar
 				}
@@ -239,54 +529,94 @@ func getMaxSet(wordList []string, currWord []rune, char rune) ([]string, string)
 	// Now that we have the max set, we can find if there is another set of the
 	// same length which reveals less number of alphabets to the user.
 	for possibility, possibilityWords := range possiblitiesMap {
-		if len(possibilityWords) == maxSetLength {
-			// Calculate number of hidden characters in both possibilities.
-			n1 := strings.Count(possibility, string(emptyChar))
-			n2 := strings.Count(maxSet, string(emptyChar))
-			if (n1 > n2) {
-				maxSet = possibility
-			} else if n1 == n2 {
-				// If both the possibilities reveal the same amount of characters,
-				// we can pick the lexicographically smaller string. This is an
-				// assumption that if user finds the first (or any of the first
-				// few) character, it will be easier to guess the word.
-				if possibility < maxSet {
-					maxSet = possibility
-				}
-			}
+		if engine.Prefer(possibility, len(possibilityWords), maxSet, maxSetLength) {
+			maxSet = possibility
 		}
 	}
 	// The maxSet contains the regex for the largest length..
-	glog.Infof("Possibilities map %+v", possiblitiesMap)
-	glog.Infof("Max set %v", maxSet)
-	return possiblitiesMap[maxSet], maxSet
+	logger.Infof("Possibilities map %+v", possiblitiesMap)
+	logger.Infof("Max set %v", maxSet)
+	sizes := make(map[string]int, len(possiblitiesMap))
+	for possibility, possibilityWords := range possiblitiesMap {
+		sizes[possibility] = len(possibilityWords)
+	}
+	return possiblitiesMap[maxSet], newDecision(char, currWord, len(wordList), sizes)
 }
 
 // ********************  Preprocessing methods ************************
 
 // Method to build a map where key is the length and value is the list of words
 // for that length.
-// This method also validates each word before adding it in memory.
-// This method also converts all the words to lower case since our hangman is not
-// case sensitive.
-func buildLenBasedDictionary(wordList []string) map[int][]string {
-	wordMap := make(map[int][]string)
-	for _, word := range wordList {
-		isValid := validateWord(word)
-		if !isValid {
-			glog.Errorf("Discarding word %s since it has some invalid characters", word)
+// This method also validates each word before adding it in memory: the invalid
+// words and the duplicates are skipped, and listed in the returned report.
+// This method also folds the case of all the words (to lower case by default,
+// see CaseFolding) since our hangman is not case sensitive.
+// Large lists are split in chunks which are processed in parallel, and the
+// words of every length are kept in the order of the list.
+func buildLenBasedDictionary(wordList []string, alphabet Alphabet) (map[int][]string,
+	DictionaryReport) {
+	workers := preprocessWorkers(len(wordList))
+	if workers == 1 {
+		return mergeLenBasedChunks([]lenBasedChunk{buildLenBasedChunk(wordList, 1, alphabet)})
+	}
+	partials := make([]lenBasedChunk, workers)
+	chunkSize := (len(wordList) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for i := range partials {
+		start, end := i*chunkSize, (i+1)*chunkSize
+		if end > len(wordList) {
+			end = len(wordList)
 		}
-		wordMap[len(word)] = append(wordMap[len(word)], word)
+		wg.Add(1)
+		go func(i, firstLine int, chunk []string) {
+			defer wg.Done()
+			partials[i] = buildLenBasedChunk(chunk, firstLine, alphabet)
+		}(i, start+1, wordList[start:end])
 	}
-	return wordMap
+	wg.Wait()
+	return mergeLenBasedChunks(partials)
+}
+
+// Valid words of a chunk of the word list, see buildLenBasedChunk.
+type lenBasedChunk struct {
+	// Words by length, in the order of the list, and their lines.
+	words map[int][]string
+	lines map[int][]int
+	// Number of non empty lines of the chunk.
+	count int
+	// Words with characters outside the alphabet, in the order of the list.
+	invalid []ReportedWord
+}
+
+// Method to build the length based map of a chunk of the word list, whose
+// first line is firstLine. Empty lines are ignored.
+func buildLenBasedChunk(wordList []string, firstLine int, alphabet Alphabet) lenBasedChunk {
+	chunk := lenBasedChunk{words: make(map[int][]string), lines: make(map[int][]int)}
+	for i, entry := range wordList {
+		entry = strings.TrimSuffix(entry, "\r")
+		if entry == "" {
+			continue
+		}
+		chunk.count++
+		word := alphabet.FoldWord(entry)
+		if !validateWord(word, alphabet) {
+			defaultLogger.Errorf("Discarding word %s since it has some invalid characters", entry)
+			chunk.invalid = append(chunk.invalid, ReportedWord{Line: firstLine + i, Word: entry})
+			continue
+		}
+		length := utf8.RuneCountInString(word)
+		chunk.words[length] = append(chunk.words[length], word)
+		chunk.lines[length] = append(chunk.lines[length], firstLine+i)
+	}
+	return chunk
 }
 
 // **************************  Validators *****************************
 
-// Method to validate if there is any word in the wordList with the length
+// Method to validate if there is any word in the dictionary with the length
 // "expectedLen".
-func validateLength(expectedLen int) bool {
-	if _, ok := dictionaryMap[expectedLen]; ok {
+func validateLength(dict *Dictionary, expectedLen int) bool {
+	if dict.HasLength(expectedLen) {
 		return true
 	}
 	return false
@@ -300,40 +630,48 @@ func validateNumRetries(retries int) bool {
 	return true
 }
 
-// Method to validate a word.
-func validateWord(word string) bool {
-	if isLetter(word) {
-		return true
-	}
-	return false
+// Method to validate a word. A word is valid if all its characters are part
+// of the alphabet of the dictionary.
+func validateWord(word string, alphabet Alphabet) bool {
+	return alphabet.ValidWord(word)
 }
 
 // *************************  Helper methods ***************************
 
 // Read a single character from stdin. This method also validates if its a valid
 // character and does not return till a valid character is given as an input.
-func readChar() rune {
-	var char rune
+// In key mode the character is registered as soon as its key is pressed.
+// Returns an error if the player stopped playing, see waitInput.
+func readChar() (rune, error) {
 	for {
-		scanner := bufio.NewScanner(os.Stdin)
-		scanned := scanner.Scan()
-		for !scanned {
-			scanned = scanner.Scan()
-		}
-		str := scanner.Text()
-		if len(str) != 1 {
-			fmt.Println("Invalid character, please input the character again")
-			continue
+		line, err := readKey()
+		if err != nil {
+			return 0, err
 		}
-		char = rune(str[0])
-		// Check if its a character.
-		if !unicode.IsLetter(char) {
-			fmt.Println("Invalid character, please input the character again")
+		char, ok := parseChar(line)
+		if !ok {
+			fmt.Println(tr("invalid_char"))
 			continue
 		}
-		break
+		return char, nil
+	}
+}
+
+// Method to parse a line of input as a single character.
+// Returns false if the line is not a single letter.
+func parseChar(line string) (rune, bool) {
+	// Convert to runes so that multi-byte characters are read as a single
+	// character.
+	str := []rune(strings.TrimSpace(line))
+	if len(str) != 1 {
+		return 0, false
+	}
+	// Check if its a character. The vowel signs of some languages, like the
+	// matras of Hindi, are marks rather than letters.
+	if !unicode.IsLetter(str[0]) && !unicode.IsMark(str[0]) {
+		return 0, false
 	}
-	return char
+	return str[0], true
 }
 
 // Method to check if a slice of rune elements contains a particular character.
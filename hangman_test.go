@@ -18,8 +18,8 @@ func (s *HangmanTestSuite) SetupSuite() {
 }
 
 func (s *HangmanTestSuite) TestConflictingOptions() {
-	game, errCode := NewGame(4, 5)
-	assert.Equal(s.T(), NoError, errCode)
+	game, err := NewGame(4, 5)
+	assert.Nil(s.T(), err)
 	isValid, err := game.CheckUserInput('a')
 	// With the given dictionary, if 'a' is accepted, the groups will be of same
 	// size. Based on our logic, 'a' should not be accepted.
@@ -32,8 +32,8 @@ func (s *HangmanTestSuite) TestConflictingOptions() {
 
 
 func (s *HangmanTestSuite) TestWinningScenario() {
-	game, errCode := NewGame(4, 2)
-	assert.Equal(s.T(), NoError, errCode)
+	game, err := NewGame(4, 2, WithRetryPolicy(RetryLenient))
+	assert.Nil(s.T(), err)
 	isValid, err := game.CheckUserInput('a')
 	// User input not accepted.
 	assert.Nil(s.T(), err)
@@ -73,8 +73,8 @@ func (s *HangmanTestSuite) TestWinningScenario() {
 }
 
 func (s *HangmanTestSuite) TestDuplicateInputs() {
-	game, errCode := NewGame(4, 8)
-	assert.Equal(s.T(), NoError, errCode)
+	game, err := NewGame(4, 8)
+	assert.Nil(s.T(), err)
 	isValid, err := game.CheckUserInput('i')
 	// User input not accepted.
 	assert.Nil(s.T(), err)
@@ -84,14 +84,14 @@ func (s *HangmanTestSuite) TestDuplicateInputs() {
 
 	// Duplicate input key is rejected.
 	isValid, err = game.CheckUserInput('i')
-	assert.NotNil(s.T(), err)
+	assert.ErrorIs(s.T(), err, ErrCharAlreadyUsed)
 	assert.Equal(s.T(), Running, game.State)
 	assert.Equal(s.T(), 7, game.CurrentRetries)
 }
 
 func (s *HangmanTestSuite) TestLosingScenario() {
-	game, errCode := NewGame(4, 3)
-	assert.Equal(s.T(), NoError, errCode)
+	game, err := NewGame(4, 3, WithRetryPolicy(RetryLenient))
+	assert.Nil(s.T(), err)
 	isValid, err := game.CheckUserInput('i')
 	// User input not accepted.
 	assert.Nil(s.T(), err)
@@ -129,19 +129,19 @@ func (s *HangmanTestSuite) TestLosingScenario() {
 
 func (s *HangmanTestSuite) TestInvalidInputs() {
 	// Test case 1: Invalid length.
-	game, errCode := NewGame(5, 3)
+	game, err := NewGame(5, 3)
 	assert.Nil(s.T(), game)
-	assert.Equal(s.T(), InvalidLength, errCode)
+	assert.ErrorIs(s.T(), err, ErrInvalidLength)
 
 	// Test case 2: Very large number of retries.
-	game, errCode = NewGame(4, 15)
+	game, err = NewGame(4, 15)
 	assert.Nil(s.T(), game)
-	assert.Equal(s.T(), InvalidRetries, errCode)
+	assert.ErrorIs(s.T(), err, ErrInvalidRetries)
 
 	// Test case 3: Negative number of retries.
-	game, errCode = NewGame(4, -1)
+	game, err = NewGame(4, -1)
 	assert.Nil(s.T(), game)
-	assert.Equal(s.T(), InvalidRetries, errCode)
+	assert.ErrorIs(s.T(), err, ErrInvalidRetries)
 }
 
 // In order for 'go test' to run this suite, we need to create
@@ -0,0 +1,87 @@
+package hangmanhttp
+
+import (
+	"errors"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/hackeracc/WordGuess/engine"
+)
+
+// Engine playing the games of WordGuess on the words of a dictionary, see the
+// engine package.
+type wordEngine struct {
+	dict       *engine.Dictionary
+	maxRetries int
+}
+
+// Game of the word engine.
+type wordGame struct {
+	game *engine.Game
+}
+
+// Method to create the engine playing the games of WordGuess on the words of a
+// dictionary, with at most maxRetries retries, e.g.
+//
+//	dict := engine.NewDictionary(strings.Fields(wordList))
+//	mux.Handle("/games/hangman/", hangmanhttp.NewHandler(
+//	    hangmanhttp.NewEngine(dict, 10), hangmanhttp.WithPrefix("/games/hangman")))
+//
+// Presets, correspondence, challenge and cooperative games need the stores of
+// the server, so they are rejected.
+func NewEngine(dict *engine.Dictionary, maxRetries int) Engine {
+	return wordEngine{dict: dict, maxRetries: maxRetries}
+}
+
+func (e wordEngine) NewGame(req api.CreateGameRequest) (Game, *api.Error) {
+	if req.Preset != "" || req.LifetimeSeconds != 0 || req.Challenge || req.Coop {
+		return nil, api.NewError(api.CodeInvalidRequest,
+			"presets, correspondence, challenge and cooperative games need a server")
+	}
+	if req.Retries > e.maxRetries {
+		return nil, api.NewError(api.CodeInvalidRetries,
+			"retries must be between 0 and %d", e.maxRetries).
+			WithDetail("retries", req.Retries)
+	}
+	game, err := engine.NewGame(e.dict, req.WordLength, req.Retries, req.RetryPolicy)
+	switch {
+	case errors.Is(err, engine.ErrInvalidLength):
+		return nil, api.NewError(api.CodeInvalidLength, "%s", err.Error()).
+			WithDetail("length", req.WordLength).
+			WithDetail("available_lengths", e.dict.Lengths())
+	case errors.Is(err, engine.ErrInvalidRetries):
+		return nil, api.NewError(api.CodeInvalidRetries, "%s", err.Error()).
+			WithDetail("retries", req.Retries)
+	case err != nil:
+		return nil, api.NewError(api.CodeInternal, "unexpected error: %v", err)
+	}
+	return wordGame{game: game}, nil
+}
+
+func (g wordGame) Guess(char rune) (bool, *api.Error) {
+	accepted, err := g.game.Guess(char)
+	switch {
+	case errors.Is(err, engine.ErrGameFinished):
+		return false, api.NewError(api.CodeGameFinished, "%s", err.Error())
+	case errors.Is(err, engine.ErrCharAlreadyUsed):
+		return false, api.NewError(api.CodeCharacterUsed, "%s", err.Error()).
+			WithDetail("character", string(char))
+	case errors.Is(err, engine.ErrInvalidCharacter):
+		return false, api.NewError(api.CodeInvalidCharacter, "%s", err.Error()).
+			WithDetail("character", string(char))
+	case err != nil:
+		return false, api.NewError(api.CodeInternal, "unexpected error: %v", err)
+	}
+	return accepted, nil
+}
+
+func (g wordGame) Hint() (string, *api.Error) {
+	clue, ok := g.game.Hint()
+	if !ok {
+		return "", api.NewError(api.CodeHintUnavailable,
+			"the words of the game do not share a clue yet")
+	}
+	return clue, nil
+}
+
+func (g wordGame) View(id string) api.Game {
+	return g.game.View(id)
+}
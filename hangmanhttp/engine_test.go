@@ -0,0 +1,66 @@
+package hangmanhttp
+
+import (
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/hackeracc/WordGuess/engine"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEngine(t *testing.T) {
+	dict := engine.NewDictionary([]string{"last|rhymes", "fast|rhymes", "Code", "c0de"})
+	mux := http.NewServeMux()
+	mux.Handle("/games/hangman/", NewHandler(NewEngine(dict, 10),
+		WithPrefix("/games/hangman")))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	base := server.URL + "/games/hangman/"
+
+	var game api.Game
+	assert.Equal(t, http.StatusCreated,
+		request(t, "POST", base, `{"word_length": 4, "retries": 3}`, &game))
+	assert.Equal(t, "____", game.MaskedWord)
+	assert.Equal(t, api.RetryStrict, game.RetryPolicy)
+
+	// Two words out of three have an "a", which is accepted.
+	var guess api.GuessResponse
+	assert.Equal(t, http.StatusOK,
+		request(t, "POST", base+game.ID+"/guesses", `{"char": "A"}`, &guess))
+	assert.Equal(t, true, guess.Accepted)
+	assert.Equal(t, "_a__", guess.Game.MaskedWord)
+	assert.Equal(t, true, guess.Game.HintAvailable)
+	var hint api.Hint
+	assert.Equal(t, http.StatusOK, request(t, "GET", base+game.ID+"/hint", "", &hint))
+	assert.Equal(t, "rhymes", hint.Hint)
+
+	// The computer keeps both words as long as it can.
+	for _, char := range []string{"s", "t", "l"} {
+		assert.Equal(t, http.StatusOK,
+			request(t, "POST", base+game.ID+"/guesses", `{"char": "`+char+`"}`, &guess))
+	}
+	assert.Equal(t, false, guess.Accepted)
+	assert.Equal(t, "_ast", guess.Game.MaskedWord)
+	assert.Equal(t, 2, guess.Game.RetriesLeft)
+	assert.Equal(t, http.StatusOK,
+		request(t, "POST", base+game.ID+"/guesses", `{"char": "f"}`, &guess))
+	assert.Equal(t, api.StateWon, guess.Game.State)
+
+	var errResp api.ErrorResponse
+	assert.Equal(t, http.StatusConflict,
+		request(t, "POST", base+game.ID+"/guesses", `{"char": "b"}`, &errResp))
+	assert.Equal(t, api.CodeGameFinished, errResp.Error.Code)
+	assert.Equal(t, http.StatusBadRequest,
+		request(t, "POST", base, `{"word_length": 9, "retries": 3}`, &errResp))
+	assert.Equal(t, api.CodeInvalidLength, errResp.Error.Code)
+	assert.Equal(t, http.StatusBadRequest,
+		request(t, "POST", base, `{"word_length": -1, "retries": 3}`, &errResp))
+	assert.Equal(t, api.CodeInvalidLength, errResp.Error.Code)
+	assert.Equal(t, http.StatusBadRequest,
+		request(t, "POST", base, `{"word_length": 4, "retries": 11}`, &errResp))
+	assert.Equal(t, api.CodeInvalidRetries, errResp.Error.Code)
+	assert.Equal(t, http.StatusBadRequest,
+		request(t, "POST", base, `{"word_length": 4, "retries": 3, "challenge": true}`,
+			&errResp))
+}
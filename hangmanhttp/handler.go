@@ -0,0 +1,270 @@
+// Package hangmanhttp serves games of WordGuess over a REST API from a plain
+// http.Handler, so that an existing web app can mount the game under one of its
+// paths (e.g. /games/hangman) without running the whole server. The requests
+// and responses are the types of the api package, like the server.
+//
+// Routes, relative to the path the handler is mounted on:
+//
+//	POST   /               Create a game, body api.CreateGameRequest.
+//	GET    /{id}           Get the state of a game, as api.Game.
+//	POST   /{id}/guesses   Guess a character, body api.GuessRequest.
+//	GET    /{id}/hint      Clue of the word, as api.Hint.
+//	DELETE /{id}           Forget a game.
+//
+// The handler works with any router accepting an http.Handler: mount it with
+// http.StripPrefix (or pass WithPrefix), e.g. with net/http
+//
+//	mux.Handle("/games/hangman/", hangmanhttp.NewHandler(engine,
+//	    hangmanhttp.WithPrefix("/games/hangman")))
+//
+// with chi
+//
+//	r.Mount("/games/hangman", hangmanhttp.NewHandler(engine,
+//	    hangmanhttp.WithPrefix("/games/hangman")))
+//
+// or with gorilla/mux
+//
+//	r.PathPrefix("/games/hangman/").Handler(hangmanhttp.NewHandler(engine,
+//	    hangmanhttp.WithPrefix("/games/hangman")))
+package hangmanhttp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/hackeracc/WordGuess/api"
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	// Max size of a request body.
+	maxRequestBody = 4 * 1024
+)
+
+// Engine creating the games served by the handler.
+type Engine interface {
+	// Method to create a game. The error is sent to the client as is.
+	NewGame(req api.CreateGameRequest) (Game, *api.Error)
+}
+
+// Game served by the handler. Its methods may be called concurrently.
+type Game interface {
+	// Method to guess a character.
+	// Returns true if the character is in the word.
+	Guess(char rune) (bool, *api.Error)
+	// Method to get the clue of the word, or a CodeHintUnavailable error.
+	Hint() (string, *api.Error)
+	// Method to get the public view of the game, which has the given id.
+	View(id string) api.Game
+}
+
+// Error returned by a SessionStore for an unknown game.
+var ErrNotFound = errors.New("hangmanhttp: game not found")
+
+// Storage of the games being played, keyed by their id. It must be safe for
+// concurrent use.
+type SessionStore interface {
+	Add(id string, game Game) error
+	// Returns ErrNotFound if there is no game with the id.
+	Get(id string) (Game, error)
+	Delete(id string) error
+}
+
+// Middleware wrapping a handler, with the signature used by net/http, chi and
+// gorilla/mux.
+type Middleware func(http.Handler) http.Handler
+
+// Option of NewHandler.
+type Option func(*handler)
+
+// Option to keep the games in the given store instead of in memory.
+func WithStore(store SessionStore) Option {
+	return func(h *handler) {
+		h.store = store
+	}
+}
+
+// Option to strip a prefix from the paths of the requests, i.e. the path the
+// handler is mounted on. The paths without the prefix are served as is, so the
+// handler also works behind http.StripPrefix.
+func WithPrefix(prefix string) Option {
+	return func(h *handler) {
+		h.prefix = prefix
+	}
+}
+
+// Option to wrap the handler in middlewares, e.g. to authenticate the players
+// or log the requests. The first middleware is the outermost one.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(h *handler) {
+		h.middlewares = append(h.middlewares, middlewares...)
+	}
+}
+
+// Handler serving the games of an engine.
+type handler struct {
+	engine      Engine
+	store       SessionStore
+	prefix      string
+	middlewares []Middleware
+}
+
+// Method to create the handler serving the games of an engine.
+func NewHandler(engine Engine, opts ...Option) http.Handler {
+	h := &handler{engine: engine, store: NewMemoryStore()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /{$}", h.handleCreate)
+	mux.HandleFunc("GET /{id}", h.handleGet)
+	mux.HandleFunc("DELETE /{id}", h.handleDelete)
+	mux.HandleFunc("POST /{id}/guesses", h.handleGuess)
+	mux.HandleFunc("GET /{id}/hint", h.handleHint)
+	var root http.Handler = mux
+	if h.prefix != "" {
+		root = stripPrefix(h.prefix, mux)
+	}
+	for i := len(h.middlewares) - 1; i >= 0; i-- {
+		root = h.middlewares[i](root)
+	}
+	return root
+}
+
+// Method to strip a prefix from the paths of the requests having it.
+func stripPrefix(prefix string, next http.Handler) http.Handler {
+	strip := http.StripPrefix(prefix, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.Path) >= len(prefix) && r.URL.Path[:len(prefix)] == prefix {
+			strip.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *handler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req api.CreateGameRequest
+	if apiErr := decodeRequest(w, r, &req); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	game, apiErr := h.engine.NewGame(req)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	id := newID()
+	if err := h.store.Add(id, game); err != nil {
+		writeError(w, api.NewError(api.CodeInternal, "unable to store the game"))
+		return
+	}
+	writeJSON(w, http.StatusCreated, game.View(id))
+}
+
+func (h *handler) handleGet(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	game, apiErr := h.game(id)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, game.View(id))
+}
+
+func (h *handler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, apiErr := h.game(id); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if err := h.store.Delete(id); err != nil {
+		writeError(w, api.NewError(api.CodeInternal, "unable to delete the game"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) handleGuess(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	game, apiErr := h.game(id)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	var req api.GuessRequest
+	if apiErr := decodeRequest(w, r, &req); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if utf8.RuneCountInString(req.Char) != 1 {
+		writeError(w, api.NewError(api.CodeInvalidCharacter,
+			"a guess must be a single character").WithDetail("character", req.Char))
+		return
+	}
+	char, _ := utf8.DecodeRuneInString(req.Char)
+	accepted, apiErr := game.Guess(char)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, api.GuessResponse{Accepted: accepted, Game: game.View(id)})
+}
+
+func (h *handler) handleHint(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	game, apiErr := h.game(id)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	hint, apiErr := game.Hint()
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, api.Hint{GameID: id, Hint: hint})
+}
+
+// Method to get a game from the store.
+func (h *handler) game(id string) (Game, *api.Error) {
+	game, err := h.store.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, api.NewError(api.CodeGameNotFound, "no game with id %q", id).
+			WithDetail("id", id)
+	}
+	if err != nil {
+		return nil, api.NewError(api.CodeInternal, "unable to load the game").
+			WithDetail("id", id)
+	}
+	return game, nil
+}
+
+// Method to decode the JSON body of a request.
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) *api.Error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return api.NewError(api.CodeInvalidRequest, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, apiErr *api.Error) {
+	writeJSON(w, apiErr.Code.HTTPStatus(), api.ErrorResponse{Error: apiErr})
+}
+
+// Method to generate a random id for a game.
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,126 @@
+package hangmanhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Engine of the tests, whose games accept the letters of a fixed word.
+type fakeEngine struct{}
+
+type fakeGame struct {
+	mu      sync.Mutex
+	word    string
+	guessed string
+}
+
+func (fakeEngine) NewGame(req api.CreateGameRequest) (Game, *api.Error) {
+	if req.WordLength != 4 {
+		return nil, api.NewError(api.CodeInvalidLength, "only words of 4 letters")
+	}
+	return &fakeGame{word: "fast"}, nil
+}
+
+func (g *fakeGame) Guess(char rune) (bool, *api.Error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.guessed += string(char)
+	return strings.ContainsRune(g.word, char), nil
+}
+
+func (g *fakeGame) Hint() (string, *api.Error) {
+	return "", api.NewError(api.CodeHintUnavailable, "no clue")
+}
+
+func (g *fakeGame) View(id string) api.Game {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return api.Game{ID: id, WordLength: len(g.word), UsedChars: g.guessed}
+}
+
+func request(t *testing.T, method, url, body string, out interface{}) int {
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	assert.Nil(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+func TestMountedHandler(t *testing.T) {
+	var calls []string
+	logging := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			next.ServeHTTP(w, r)
+		})
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/games/hangman/", NewHandler(fakeEngine{},
+		WithPrefix("/games/hangman"), WithMiddleware(logging)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	base := server.URL + "/games/hangman/"
+
+	var game api.Game
+	assert.Equal(t, http.StatusCreated, request(t, "POST", base, `{"word_length": 4}`, &game))
+	assert.Equal(t, 4, game.WordLength)
+	var guess api.GuessResponse
+	assert.Equal(t, http.StatusOK,
+		request(t, "POST", base+game.ID+"/guesses", `{"char": "a"}`, &guess))
+	assert.Equal(t, true, guess.Accepted)
+	assert.Equal(t, "a", guess.Game.UsedChars)
+	assert.Equal(t, http.StatusOK, request(t, "GET", base+game.ID, "", &game))
+	assert.Equal(t, "a", game.UsedChars)
+
+	var errResp api.ErrorResponse
+	assert.Equal(t, http.StatusBadRequest, request(t, "POST", base, `{"word_length": 5}`,
+		&errResp))
+	assert.Equal(t, api.CodeInvalidLength, errResp.Error.Code)
+	assert.Equal(t, http.StatusBadRequest,
+		request(t, "POST", base+game.ID+"/guesses", `{"char": "ab"}`, &errResp))
+	assert.Equal(t, http.StatusConflict, request(t, "GET", base+game.ID+"/hint", "",
+		&errResp))
+
+	assert.Equal(t, http.StatusNoContent, request(t, "DELETE", base+game.ID, "", nil))
+	assert.Equal(t, http.StatusNotFound, request(t, "GET", base+game.ID, "", &errResp))
+	assert.Equal(t, api.CodeGameNotFound, errResp.Error.Code)
+	assert.Equal(t, "POST /games/hangman/", calls[0])
+}
+
+// Session store counting the games added to it.
+type countingStore struct {
+	*MemoryStore
+	added int
+}
+
+func (c *countingStore) Add(id string, game Game) error {
+	c.added++
+	return c.MemoryStore.Add(id, game)
+}
+
+func TestStripPrefixAndStore(t *testing.T) {
+	store := &countingStore{MemoryStore: NewMemoryStore()}
+	mux := http.NewServeMux()
+	mux.Handle("/hangman/", http.StripPrefix("/hangman",
+		NewHandler(fakeEngine{}, WithStore(store))))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var game api.Game
+	assert.Equal(t, http.StatusCreated,
+		request(t, "POST", server.URL+"/hangman/", `{"word_length": 4}`, &game))
+	assert.Equal(t, 1, store.added)
+	_, err := store.Get(game.ID)
+	assert.Nil(t, err)
+	_, err = store.Get("unknown")
+	assert.Equal(t, ErrNotFound, err)
+}
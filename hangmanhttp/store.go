@@ -0,0 +1,41 @@
+package hangmanhttp
+
+import (
+	"sync"
+)
+
+// Session store keeping the games in memory, used by default. The games are
+// lost when the process stops.
+type MemoryStore struct {
+	mu    sync.Mutex
+	games map[string]Game
+}
+
+// Method to create an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{games: make(map[string]Game)}
+}
+
+func (m *MemoryStore) Add(id string, game Game) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.games[id] = game
+	return nil
+}
+
+func (m *MemoryStore) Get(id string) (Game, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	game, ok := m.games[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return game, nil
+}
+
+func (m *MemoryStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.games, id)
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	helperMaxWords = flag.Int("helper_max_words", 20,
+		"Max number of candidate words listed by the \"helper\" subcommand. All "+
+			"of them are listed if zero.")
+)
+
+// Suggestion of the helper for a game played elsewhere.
+type helperSuggestion struct {
+	// Words of the dictionary matching the game, sorted.
+	Words []string
+	// Letter which tells the most about the word, as picked by the solver
+	// (see Solver.NextGuess). Zero if there is no word left or every letter of
+	// the words is known.
+	Best rune
+	// Letters not guessed yet in the most words, the most common first.
+	Letters []ScoredLetter
+}
+
+// Method to find the words of the dictionary matching a game of hangman
+// played elsewhere, given the word shown in the game (with "_" for the letters
+// not guessed yet) and the wrong letters, and the best letter to guess next.
+// Returns an error if the wrong letters are revealed in the word.
+func suggestGuess(d *Dictionary, mask string, wrong []rune) (helperSuggestion, error) {
+	var suggestion helperSuggestion
+	if mask == "" {
+		return suggestion, fmt.Errorf("the word is empty")
+	}
+	folded := []rune(d.alphabet.FoldWord(mask))
+	var used []rune
+	for _, char := range folded {
+		if char != DefaultPatternFormat.Blank && !contains(used, char) {
+			used = append(used, char)
+		}
+	}
+	for _, char := range wrong {
+		char = d.alphabet.Fold(char)
+		if contains(used, char) {
+			return suggestion, fmt.Errorf("the wrong letter %c is in the word", char)
+		}
+		used = append(used, char)
+	}
+	suggestion.Words = d.Match(mask, wrong)
+	solver := Solver{WordLength: len(folded), Candidates: suggestion.Words, UsedChars: used}
+	suggestion.Letters = solver.BestNextGuesses(0)
+	if len(suggestion.Letters) > 0 {
+		suggestion.Best, _ = solver.NextGuess()
+	}
+	return suggestion, nil
+}
+
+// Method to show a suggestion of the helper.
+func formatSuggestion(suggestion helperSuggestion, maxWords int) string {
+	var b strings.Builder
+	switch len(suggestion.Words) {
+	case 0:
+		b.WriteString("No word of the dictionary matches, check the word and the wrong letters.\n")
+		return b.String()
+	case 1:
+		fmt.Fprintf(&b, "The word is %s!\n", suggestion.Words[0])
+		return b.String()
+	}
+	words := suggestion.Words
+	if maxWords > 0 && len(words) > maxWords {
+		words = words[:maxWords]
+	}
+	fmt.Fprintf(&b, "%d words match: %s", len(suggestion.Words), strings.Join(words, ", "))
+	if len(words) < len(suggestion.Words) {
+		fmt.Fprintf(&b, " and %d more", len(suggestion.Words)-len(words))
+	}
+	b.WriteString("\n")
+	if suggestion.Best == 0 {
+		return b.String()
+	}
+	probability := 0.0
+	for _, letter := range suggestion.Letters {
+		if letter.Char == suggestion.Best {
+			probability = letter.Probability
+		}
+	}
+	fmt.Fprintf(&b, "Best next letter: %c (in %.0f%% of the words)\n", suggestion.Best,
+		probability*100)
+	return b.String()
+}
+
+// Driver method for the "helper" subcommand, an assistant for the games of
+// hangman played elsewhere: the user enters the word shown in the game and the
+// wrong letters, and gets the matching words and the best letter to guess.
+func StartHelper() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	d := currentDictionary()
+	fmt.Println("Helper for the hangman games you play elsewhere.")
+	for {
+		fmt.Println("Enter the word as shown in your game, with _ for the letters not " +
+			"guessed yet (or just press enter to quit): ")
+		line, err := readLine()
+		if err != nil {
+			return err
+		}
+		mask := strings.TrimSpace(line)
+		if mask == "" {
+			return nil
+		}
+		if !d.HasLength(utf8.RuneCountInString(mask)) {
+			fmt.Println("Sorry we do not have any words of length ",
+				utf8.RuneCountInString(mask), " in the dictionary. Please try again!")
+			continue
+		}
+		fmt.Println("Enter the wrong letters guessed so far, or just press enter if none: ")
+		line, err = readLine()
+		if err != nil {
+			return err
+		}
+		var wrong []rune
+		for _, char := range line {
+			if char != ' ' && char != ',' {
+				wrong = append(wrong, char)
+			}
+		}
+		suggestion, err := suggestGuess(d, mask, wrong)
+		if err != nil {
+			fmt.Println("Invalid input given, error: ", err)
+			continue
+		}
+		fmt.Print(formatSuggestion(suggestion, *helperMaxWords))
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type HelperTestSuite struct {
+	suite.Suite
+}
+
+func (s *HelperTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cast", "lost"})
+}
+
+func (s *HelperTestSuite) TestSuggestGuess() {
+	suggestion, err := suggestGuess(currentDictionary(), "_AS_", []rune{'c'})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"fast", "last"}, suggestion.Words)
+	// t is in both words, only f and l tell them apart.
+	assert.Equal(s.T(), 'f', suggestion.Best)
+	assert.Equal(s.T(), ScoredLetter{Char: 't', Probability: 1, Count: 2}, suggestion.Letters[0])
+	assert.Equal(s.T(), "2 words match: fast, last\nBest next letter: f (in 50% of the words)\n",
+		formatSuggestion(suggestion, 20))
+	assert.Equal(s.T(), "2 words match: fast and 1 more\nBest next letter: f (in 50% of the "+
+		"words)\n", formatSuggestion(suggestion, 1))
+
+	suggestion, err = suggestGuess(currentDictionary(), "lo__", nil)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "The word is lost!\n", formatSuggestion(suggestion, 20))
+	suggestion, err = suggestGuess(currentDictionary(), "____", []rune("aeo"))
+	assert.Nil(s.T(), err)
+	assert.Empty(s.T(), suggestion.Words)
+	assert.Equal(s.T(), rune(0), suggestion.Best)
+	assert.Contains(s.T(), formatSuggestion(suggestion, 20), "No word of the dictionary matches")
+}
+
+func (s *HelperTestSuite) TestInvalidInput() {
+	_, err := suggestGuess(currentDictionary(), "_as_", []rune{'A'})
+	assert.NotNil(s.T(), err)
+	_, err = suggestGuess(currentDictionary(), "", nil)
+	assert.NotNil(s.T(), err)
+}
+
+func TestHelperTestSuite(t *testing.T) {
+	suite.Run(t, new(HelperTestSuite))
+}
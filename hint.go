@@ -0,0 +1,62 @@
+package main
+
+import (
+	"github.com/hackeracc/WordGuess/engine"
+	"strings"
+)
+
+// Separator of a word and its clue in a dictionary line, e.g.
+// "paris|capital city". The clue is optional.
+const hintSeparator = engine.ClueSeparator
+
+// Method to split a dictionary line into the word and its clue. The clue is
+// empty if the line has none. Spaces around the separator are ignored.
+func splitHint(line string) (string, string) {
+	word, clue, found := strings.Cut(line, hintSeparator)
+	if !found {
+		return line, ""
+	}
+	return strings.TrimSpace(word), strings.TrimSpace(clue)
+}
+
+// Method to split the lines of a word list into the words and their clues.
+// The list is returned as is if no line has a clue, see engine.SplitClues.
+// Returns the words, in the order of the lines, and the clues by word.
+func splitHints(lines []string) ([]string, map[string]string) {
+	return engine.SplitClues(lines)
+}
+
+// Method to get the clue shared by all the words the game is still choosing
+// from, to show on request. A clue is never shown while the words have
+// different clues, since it would tell which word the game is not playing: in
+// a game with a single secret word it is available right away, and otherwise
+// once the computer has committed to words sharing a clue (e.g. a category).
+// Returns false if there is no such clue.
+func (g *Game) Hint() (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.dict == nil || !g.dict.HasHints() {
+		return "", false
+	}
+	var clue string
+	for i, word := range g.candidatesLocked() {
+		wordClue, ok := g.dict.hints[word]
+		if !ok || (i > 0 && wordClue != clue) {
+			return "", false
+		}
+		clue = wordClue
+	}
+	return clue, clue != ""
+}
+
+// Method to show the clue of the word to the player, see Hint. Unlike Hint,
+// every clue shown is counted in HintsUsed, which costs points in the score.
+func (g *Game) UseHint() (string, bool) {
+	clue, ok := g.Hint()
+	if ok {
+		g.mu.Lock()
+		g.HintsUsed++
+		g.mu.Unlock()
+	}
+	return clue, ok
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"testing"
+)
+
+type HintTestSuite struct {
+	suite.Suite
+}
+
+func (s *HintTestSuite) SetupTest() {
+	InitGame([]string{"cats|animal", "rats | animal", "blue|color", "grey|color", "pink"})
+}
+
+func (s *HintTestSuite) TestSplitHints() {
+	lines := []string{"cats|animal", "blue", "grey|", ""}
+	words, hints := splitHints(lines)
+	assert.Equal(s.T(), []string{"cats", "blue", "grey", ""}, words)
+	assert.Equal(s.T(), map[string]string{"cats": "animal"}, hints)
+	assert.Equal(s.T(), "cats|animal", lines[0])
+
+	words, hints = splitHints([]string{"cats", "blue"})
+	assert.Equal(s.T(), []string{"cats", "blue"}, words)
+	assert.Nil(s.T(), hints)
+}
+
+func (s *HintTestSuite) TestDictionary() {
+	dict := currentDictionary()
+	assert.True(s.T(), dict.HasHints())
+	assert.Equal(s.T(), []string{"blue", "cats", "grey", "pink", "rats"}, dict.Words(4))
+	clue, ok := dict.Hint("rats")
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "animal", clue)
+	_, ok = dict.Hint("pink")
+	assert.False(s.T(), ok)
+}
+
+func (s *HintTestSuite) TestHintOnceCommitted() {
+	InitGame([]string{"cats|animal", "rats|animal", "blue|color", "grey|color"})
+	game, err := NewGame(4, 6)
+	assert.Nil(s.T(), err)
+	_, ok := game.Hint()
+	assert.False(s.T(), ok)
+	// Rejecting "s" keeps the words without it, which are both colors.
+	accepted, err := game.CheckUserInput('s')
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), accepted)
+	clue, ok := game.Hint()
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "color", clue)
+}
+
+func (s *HintTestSuite) TestSecretWord() {
+	for seed := int64(0); seed < 10; seed++ {
+		game, err := NewGame(4, 6, withSecretWord(seed))
+		assert.Nil(s.T(), err)
+		word := game.candidatesLocked()[0]
+		clue, ok := game.Hint()
+		expected, hasClue := currentDictionary().Hint(word)
+		assert.Equal(s.T(), hasClue, ok, word)
+		assert.Equal(s.T(), expected, clue, word)
+	}
+}
+
+func (s *HintTestSuite) TestNoHints() {
+	InitGame([]string{"cats", "rats"})
+	game, err := NewGame(4, 6, withSecretWord(1))
+	assert.Nil(s.T(), err)
+	_, ok := game.Hint()
+	assert.False(s.T(), ok)
+}
+
+func (s *HintTestSuite) TestIndexAndCheck() {
+	d := currentDictionary()
+	path := filepath.Join(s.T().TempDir(), "dict.idx")
+	assert.Nil(s.T(), saveDictionaryIndex(path, d, ""))
+	loaded, err := loadDictionaryIndex(path, "", false, "", FoldLower, false, false)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), d.hints, loaded.hints)
+
+	report := checkDictionary([]string{"cats|animal", "c4ts|animal"}, NewAlphabet(""))
+	assert.Equal(s.T(), []ReportedWord{{Line: 2, Word: "c4ts"}}, report.Invalid)
+	assert.Equal(s.T(), map[int]int{4: 1}, report.Lengths)
+}
+
+func TestHintTestSuite(t *testing.T) {
+	suite.Run(t, new(HintTestSuite))
+}
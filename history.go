@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Outcome of a turn of a game, see Game.History.
+type GuessRecord struct {
+	// Guessed character, zero for a timeout.
+	Char rune
+	// True if the character is in the word. Always false for a timeout.
+	Correct bool
+	// Number of positions of the word revealed by the guess, zero if it was
+	// not correct.
+	Positions int
+	// True if the player did not guess before the deadline of the turn.
+	Timeout bool
+	// Teammate who guessed the character in a team turn, empty otherwise.
+	Player string
+}
+
+// Method to get the outcome of every turn played so far, in order. Unlike
+// UsedChars, it tells which guesses were correct and how many positions they
+// revealed, and includes the timeouts.
+func (g *Game) History() []GuessRecord {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	history := make([]GuessRecord, 0, len(g.Turns))
+	for _, turn := range g.Turns {
+		record := GuessRecord{
+			Char:    turn.Char,
+			Correct: turn.Accepted,
+			Timeout: turn.Kind == TurnTimeout,
+			Player:  turn.Player,
+		}
+		if turn.Accepted {
+			// The positions of a character are all revealed by its guess,
+			// so they are the positions it has in the word shown now.
+			for _, char := range g.CurrentDisplayedWord {
+				if char == turn.Char {
+					record.Positions++
+				}
+			}
+		}
+		history = append(history, record)
+	}
+	return history
+}
+
+// Method to format the history of a game for the terminal, e.g.
+// "a ✗, e ✓ (2 positions), t ✗", coloring the outcomes with the theme.
+func formatHistory(history []GuessRecord, theme *ColorTheme) string {
+	entries := make([]string, len(history))
+	for i, record := range history {
+		switch {
+		case record.Timeout:
+			entries[i] = "timeout " + theme.wrong("✗")
+		case record.Correct:
+			positions := "positions"
+			if record.Positions == 1 {
+				positions = "position"
+			}
+			entries[i] = fmt.Sprintf("%c %s (%d %s)", record.Char, theme.correct("✓"),
+				record.Positions, positions)
+		default:
+			entries[i] = fmt.Sprintf("%c %s", record.Char, theme.wrong("✗"))
+		}
+	}
+	return strings.Join(entries, ", ")
+}
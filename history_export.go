@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Columns of the games exported as CSV.
+var historyCSVHeader = []string{"ended", "length", "retries_used", "outcome", "word",
+	"duration_seconds"}
+
+// Method to write the recorded games as CSV, one game per row after a header,
+// e.g. to analyze them in a spreadsheet. The times are in RFC 3339.
+func writeHistoryCSV(w io.Writer, games []GameRecord) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(historyCSVHeader); err != nil {
+		return err
+	}
+	for _, game := range games {
+		outcome := "lost"
+		if game.Won {
+			outcome = "won"
+		}
+		word := game.Revealed
+		if word == "" {
+			word = game.Word
+		}
+		duration := time.Duration(game.DurationMillis) * time.Millisecond
+		err := out.Write([]string{
+			game.Ended.Format(time.RFC3339),
+			strconv.Itoa(game.Length),
+			strconv.Itoa(game.RetriesUsed),
+			outcome,
+			word,
+			strconv.FormatFloat(duration.Seconds(), 'f', 3, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// Method to load the stats the games are exported from: the profile of
+// --player (or the current profile) with --profiles, or --stats_file.
+func loadExportedHistory() (StatsHistory, error) {
+	if *useProfiles {
+		store, err := profileStoreFromFlags()
+		if err != nil {
+			return StatsHistory{}, err
+		}
+		name := *playerName
+		if !flagPassed("player") {
+			current, err := store.current()
+			if err != nil {
+				return StatsHistory{}, err
+			}
+			if current != "" {
+				name = current
+			}
+		}
+		profile, err := store.load(name)
+		if err != nil {
+			return StatsHistory{}, err
+		}
+		return profile.History, nil
+	}
+	if *statsFile == "" {
+		return StatsHistory{}, configError("pass --stats_file or --profiles to " +
+			"choose the stats to export")
+	}
+	return loadStatsHistory(*statsFile)
+}
+
+// Driver method for the "history" subcommand. "history export --format=csv"
+// writes every game recorded in the stats to stdout.
+func StartHistory(args []string) error {
+	const usage = "hangman history export [--format=csv]"
+	if len(args) == 0 || args[0] != "export" {
+		return usageError(usage)
+	}
+	exportFlags := flag.NewFlagSet("history export", flag.ExitOnError)
+	format := exportFlags.String("format", "csv", "Format of the export, only csv "+
+		"is supported.")
+	exportFlags.Parse(args[1:])
+	if *format != "csv" || exportFlags.NArg() != 0 {
+		return usageError(usage)
+	}
+	history, err := loadExportedHistory()
+	if err != nil {
+		return fmt.Errorf("unable to load the stats: %w", err)
+	}
+	if err := writeHistoryCSV(os.Stdout, history.Games); err != nil {
+		return fmt.Errorf("unable to export the games: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type HistoryExportTestSuite struct {
+	suite.Suite
+}
+
+func (s *HistoryExportTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "hello"})
+}
+
+// Method to play a game with the given guesses.
+func (s *HistoryExportTestSuite) play(length, retries int, guesses string) *Game {
+	game, err := NewGame(length, retries, WithRetryPolicy(RetryLenient))
+	s.Require().NoError(err)
+	for _, char := range guesses {
+		game.CheckUserInput(char)
+	}
+	return game
+}
+
+func (s *HistoryExportTestSuite) TestRecord() {
+	ended := time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC)
+	tracker := NewSessionTracker(initialRating)
+	tracker.Record(s.play(5, 3, "helo"), 12500*time.Millisecond, ended)
+	lost := s.play(4, 1, "zy")
+	tracker.Record(lost, 3*time.Second, ended.Add(time.Minute))
+	tracker.Record(s.play(4, 3, ""), time.Second, ended)
+	s.Require().Len(tracker.Records, 2)
+
+	assert.Equal(s.T(), GameRecord{Word: "hello", Won: true, Revealed: "hello", Length: 5,
+		Ended: ended, DurationMillis: 12500}, tracker.Records[0])
+	record := tracker.Records[1]
+	assert.False(s.T(), record.Won)
+	assert.Equal(s.T(), 4, record.Length)
+	// The word recorded is the one shown to the player.
+	assert.Equal(s.T(), lost.RevealWord(), record.Revealed)
+	assert.Contains(s.T(), []string{"last", "fast", "bets", "code"}, record.Revealed)
+}
+
+func (s *HistoryExportTestSuite) TestSaveGames() {
+	path := filepath.Join(s.T().TempDir(), "stats.json")
+	*statsFile = path
+	defer func() { *statsFile = "" }()
+	tracker := NewSessionTracker(initialRating)
+	tracker.Record(s.play(5, 3, "helo"), time.Second, time.Now())
+	history := endSession(tracker, StatsHistory{Rating: initialRating,
+		Games: []GameRecord{{Word: "code", Won: true}}})
+	assert.Len(s.T(), history.Games, 2)
+	loaded, err := loadExportedHistory()
+	s.Require().NoError(err)
+	assert.Len(s.T(), loaded.Games, 2)
+	assert.Equal(s.T(), "hello", loaded.Games[1].Revealed)
+}
+
+func (s *HistoryExportTestSuite) TestWriteCSV() {
+	ended := time.Date(2020, 1, 1, 10, 30, 0, 0, time.UTC)
+	games := []GameRecord{
+		{Word: "hello", Won: true, Revealed: "hello", Length: 5, Ended: ended,
+			DurationMillis: 12500},
+		{Word: "_a_t", RetriesUsed: 6, Revealed: "last, first", Length: 4,
+			Ended: ended.Add(time.Hour), DurationMillis: 800},
+		// Saved before the word was revealed.
+		{Word: "code", RetriesUsed: 1, Won: true},
+	}
+	var b bytes.Buffer
+	s.Require().NoError(writeHistoryCSV(&b, games))
+	assert.Equal(s.T(), "ended,length,retries_used,outcome,word,duration_seconds\n"+
+		"2020-01-01T10:30:00Z,5,0,won,hello,12.500\n"+
+		"2020-01-01T11:30:00Z,4,6,lost,\"last, first\",0.800\n"+
+		"0001-01-01T00:00:00Z,0,1,won,code,0.000\n", b.String())
+}
+
+func TestHistoryExportTestSuite(t *testing.T) {
+	suite.Run(t, new(HistoryExportTestSuite))
+}
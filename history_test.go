@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type HistoryTestSuite struct {
+	suite.Suite
+}
+
+func (s *HistoryTestSuite) SetupTest() {
+	InitGame([]string{"geese"})
+}
+
+func (s *HistoryTestSuite) TearDownTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *HistoryTestSuite) TestHistory() {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	game, err := NewGame(5, 3, WithClock(clock), WithGuessTimeout(10*time.Second))
+	assert.Nil(s.T(), err)
+	assert.Empty(s.T(), game.History())
+	game.CheckUserInput('z')
+	game.CheckUserInput('e')
+	clock.Advance(11 * time.Second)
+	game.Tick()
+	game.CheckUserInput('g')
+	assert.Equal(s.T(), []GuessRecord{
+		{Char: 'z'},
+		{Char: 'e', Correct: true, Positions: 3},
+		{Timeout: true},
+		{Char: 'g', Correct: true, Positions: 1},
+	}, game.History())
+}
+
+func (s *HistoryTestSuite) TestFormat() {
+	history := []GuessRecord{
+		{Char: 'a'},
+		{Char: 'e', Correct: true, Positions: 2},
+		{Char: 't', Correct: true, Positions: 1},
+		{Timeout: true},
+	}
+	assert.Equal(s.T(), "a ✗, e ✓ (2 positions), t ✓ (1 position), timeout ✗",
+		formatHistory(history, nil))
+	assert.Equal(s.T(), "a \x1b[31m✗\x1b[0m", formatHistory(history[:1], &DefaultColorTheme))
+	assert.Equal(s.T(), "", formatHistory(nil, nil))
+}
+
+func TestHistoryTestSuite(t *testing.T) {
+	suite.Run(t, new(HistoryTestSuite))
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"sort"
+	"sync"
+)
+
+// Index over all the dictionary words of a single length.
+// The words are kept in sorted order. Along with that, for every position in
+// the word we keep the words ordered by the character at that position, so all
+// the words having a particular character at a particular position form one
+// contiguous range. The start of every such range is precomputed, which lets
+// pattern based filtering find the words for a revealed character using a
+// binary search instead of scanning the whole list.
+type wordIndex struct {
+	// Length of all the words in this index.
+	length int
+	// Sorted list of words.
+	words []string
+	// Characters of each word, in the same order as words.
+	chars [][]rune
+	// Per position, the indices of the words sorted by the character at that
+	// position. Words having the same character are in sorted order.
+	byPosition [][]int
+	// Per position, the sorted distinct characters seen at that position.
+	letters [][]rune
+	// Per position, offsets[pos][i] is the start of the range in
+	// byPosition[pos] for the character letters[pos][i]. An extra entry at the
+	// end marks the end of the last range.
+	offsets [][]int
+	// Per letter, the words having the letter and the words having it at
+	// every position, used to partition the candidates of the games with bit
+	// operations, see maxDecision.
+	positions map[rune]*letterPositions
+	// Partitions of the candidates of the games of this length, see
+	// cachedMaxDecision. A new cache comes with every index, so the cached
+	// partitions are dropped when the dictionary is reloaded.
+	cache *partitionCache
+}
+
+// Method to build the index for a list of words of the same length.
+// The input list is not modified.
+func newWordIndex(length int, wordList []string) *wordIndex {
+	idx := &wordIndex{
+		length:     length,
+		words:      make([]string, len(wordList)),
+		chars:      make([][]rune, len(wordList)),
+		byPosition: make([][]int, length),
+		letters:    make([][]rune, length),
+		offsets:    make([][]int, length),
+	}
+	copy(idx.words, wordList)
+	sort.Strings(idx.words)
+	for i, word := range idx.words {
+		idx.chars[i] = []rune(word)
+	}
+	idx.positions = newLetterPositions(length, idx.chars)
+	idx.cache = newPartitionCache()
+	for pos := 0; pos < length; pos++ {
+		order := make([]int, len(idx.words))
+		for i := range order {
+			order[i] = i
+		}
+		// Stable sort keeps the words with the same character in sorted order.
+		sort.SliceStable(order, func(a, b int) bool {
+			return idx.chars[order[a]][pos] < idx.chars[order[b]][pos]
+		})
+		idx.byPosition[pos] = order
+		for i, wordIdx := range order {
+			char := idx.chars[wordIdx][pos]
+			n := len(idx.letters[pos])
+			if n == 0 || idx.letters[pos][n-1] != char {
+				idx.letters[pos] = append(idx.letters[pos], char)
+				idx.offsets[pos] = append(idx.offsets[pos], i)
+			}
+		}
+		idx.offsets[pos] = append(idx.offsets[pos], len(order))
+	}
+	return idx
+}
+
+// Method to get the range in byPosition[pos] of all the words which have the
+// given character at the given position. The range is [start, end) and is
+// empty if no such word exists.
+func (idx *wordIndex) letterRange(pos int, char rune) (int, int) {
+	letters := idx.letters[pos]
+	i := sort.Search(len(letters), func(i int) bool { return letters[i] >= char })
+	if i == len(letters) || letters[i] != char {
+		return 0, 0
+	}
+	return idx.offsets[pos][i], idx.offsets[pos][i+1]
+}
+
+// Method to find all the words matching a pattern.
+// Params:
+// pattern: Word as displayed to the user, where emptyChar represents a character which
+//   is yet to be guessed. Like in the game, a revealed character is revealed at
+//   all its positions, so a hidden position can never hold a revealed character.
+// excluded: Characters which must not be present anywhere in the word.
+//
+// Returns the matching words in sorted order.
+func (idx *wordIndex) match(pattern []rune, excluded []rune) []string {
+	if len(pattern) != idx.length {
+		return nil
+	}
+	// Find the revealed position which narrows down the words the most.
+	bestPos, bestStart, bestEnd := -1, 0, len(idx.words)
+	for pos, char := range pattern {
+		if char == emptyChar {
+			continue
+		}
+		start, end := idx.letterRange(pos, char)
+		if end-start < bestEnd-bestStart || bestPos == -1 {
+			bestPos, bestStart, bestEnd = pos, start, end
+		}
+	}
+	var result []string
+	for i := bestStart; i < bestEnd; i++ {
+		wordIdx := i
+		if bestPos != -1 {
+			wordIdx = idx.byPosition[bestPos][i]
+		}
+		if matchesPattern(idx.chars[wordIdx], pattern, excluded) {
+			result = append(result, idx.words[wordIdx])
+		}
+	}
+	return result
+}
+
+// Method to check if the characters of a word match the pattern without using
+// any of the excluded characters.
+func matchesPattern(word []rune, pattern []rune, excluded []rune) bool {
+	for pos, char := range word {
+		if pattern[pos] == emptyChar {
+			if contains(pattern, char) || contains(excluded, char) {
+				return false
+			}
+		} else if pattern[pos] != char {
+			return false
+		}
+	}
+	return true
+}
+
+// Method to build the index for every length in the dictionary map. The
+// lengths are indexed in parallel for large dictionaries.
+func buildDictionaryIndex(wordMap map[int][]string) map[int]*wordIndex {
+	lengths := make([]int, 0, len(wordMap))
+	total := 0
+	for length, words := range wordMap {
+		lengths = append(lengths, length)
+		total += len(words)
+	}
+	indexes := make([]*wordIndex, len(lengths))
+	workers := preprocessWorkers(total)
+	if workers > len(lengths) {
+		workers = len(lengths)
+	}
+	jobs := make(chan int, len(lengths))
+	for i := range lengths {
+		jobs <- i
+	}
+	close(jobs)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				indexes[i] = newWordIndex(lengths[i], wordMap[lengths[i]])
+			}
+		}()
+	}
+	wg.Wait()
+	index := make(map[int]*wordIndex)
+	for i, length := range lengths {
+		index[length] = indexes[i]
+	}
+	return index
+}
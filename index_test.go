@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type IndexTestSuite struct {
+	suite.Suite
+	idx *wordIndex
+}
+
+func (s *IndexTestSuite) SetupTest() {
+	s.idx = newWordIndex(4, []string{"last", "fast", "bets", "code", "cast", "lost"})
+}
+
+func (s *IndexTestSuite) TestSortedWords() {
+	assert.Equal(s.T(), []string{"bets", "cast", "code", "fast", "last", "lost"}, s.idx.words)
+}
+
+func (s *IndexTestSuite) TestLetterRange() {
+	start, end := s.idx.letterRange(1, 'a')
+	assert.Equal(s.T(), 3, end-start)
+	start, end = s.idx.letterRange(0, 'z')
+	assert.Equal(s.T(), 0, end-start)
+}
+
+func (s *IndexTestSuite) TestMatch() {
+	assert.Equal(s.T(), []string{"cast", "fast", "last"},
+		s.idx.match(rawPattern("_as_"), nil))
+	assert.Equal(s.T(), []string{"fast"},
+		s.idx.match(rawPattern("_as_"), []rune{'c', 'l'}))
+	assert.Equal(s.T(), []string{"bets", "code", "lost"},
+		s.idx.match(rawPattern("____"), []rune{'a'}))
+	// Revealed characters can not be present at a hidden position.
+	assert.Equal(s.T(), []string{"bets"},
+		s.idx.match(rawPattern("___s"), nil))
+	assert.Equal(s.T(), []string{"lost"},
+		s.idx.match(rawPattern("l___"), []rune{'a'}))
+	assert.Nil(s.T(), s.idx.match(rawPattern("___"), nil))
+}
+
+func TestIndexTestSuite(t *testing.T) {
+	suite.Run(t, new(IndexTestSuite))
+}
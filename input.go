@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	// Lines read from stdin. A single goroutine reads stdin so that the CLI can
+	// wait for input with a timeout without losing a line typed later.
+	stdinLines chan string
+	// Keys pressed, read instead of stdinLines when stdin is a terminal in key
+	// mode. They are put together by stdinEditor.
+	stdinKeys   chan keyPress
+	stdinEditor lineEditor
+	// Signaled when Ctrl+C is pressed or SIGINT is received, see confirmQuit.
+	stdinInterrupt = make(chan struct{}, 1)
+	// Closed when SIGTERM is received.
+	stdinTerminate = make(chan struct{})
+	terminateOnce  sync.Once
+	stdinOnce      sync.Once
+	// True while the player is asked to confirm quitting.
+	confirmingQuit bool
+)
+
+// Outcome of waiting for input.
+type inputEvent int
+
+const (
+	inputTyped inputEvent = iota
+	inputTick
+	inputDone
+)
+
+// Method to start reading stdin in the background (only once). Stdin is read
+// key by key if it is a terminal which can be switched to key mode, and line
+// by line otherwise.
+func startStdinReader() {
+	stdinOnce.Do(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			for sig := range signals {
+				if sig == syscall.SIGTERM {
+					terminateInput()
+				} else {
+					interruptInput()
+				}
+			}
+		}()
+		if !*lineInput && isTerminal(os.Stdin) {
+			err := enableKeyMode()
+			if err == nil {
+				stdinKeys = make(chan keyPress)
+				stdinEditor = lineEditor{out: os.Stdout}
+				go func() {
+					readKeys(os.Stdin, func(key keyPress) {
+						if key.Kind == keyInterrupt {
+							interruptInput()
+							return
+						}
+						stdinKeys <- key
+					})
+					close(stdinKeys)
+				}()
+				return
+			}
+			defaultLogger.Infof("Unable to read single keys, reading lines, error %v", err)
+		}
+		stdinLines = make(chan string)
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				stdinLines <- scanner.Text()
+			}
+			close(stdinLines)
+		}()
+	})
+}
+
+// Method to signal that Ctrl+C was pressed. An interrupt already waiting to be
+// handled is not signaled again.
+func interruptInput() {
+	select {
+	case stdinInterrupt <- struct{}{}:
+	default:
+	}
+}
+
+// Method to signal that the program must exit, e.g. because the system is
+// shutting down.
+func terminateInput() {
+	terminateOnce.Do(func() {
+		close(stdinTerminate)
+	})
+}
+
+// Method to ask the player to confirm quitting after Ctrl+C. The line being
+// typed is put aside while asking, and shown again if the player goes on.
+// Returns true without asking if stdin is not a terminal, as nobody can
+// answer, and an error if the input ends while asking.
+func confirmQuit() (bool, error) {
+	if !isTerminal(os.Stdin) {
+		return true, nil
+	}
+	confirmingQuit = true
+	defer func() {
+		confirmingQuit = false
+	}()
+	typed := stdinEditor.line
+	stdinEditor.line = nil
+	fmt.Println()
+	fmt.Println(tr("confirm_quit"))
+	answer, _, err := waitInput(true, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	if char, ok := parseChar(answer); ok && isYes(char) {
+		return true, nil
+	}
+	stdinEditor.line = typed
+	fmt.Print(string(typed))
+	return false, nil
+}
+
+// Method to tell the player that the program exits because the input ended
+// or was interrupted. The terminal is restored first. Returns the error, see
+// isInputEnd.
+func quitInput(message string, err error) error {
+	restoreTerminal()
+	fmt.Println()
+	fmt.Println(message)
+	return err
+}
+
+// Method to wait for input.
+// Params:
+// single: whether a single key completes the input in key mode, see
+// lineEditor.press. A whole line is always read in line mode.
+// tick: channel interrupting the wait every time it fires. Can be nil.
+// done: channel ending the wait once closed. Can be nil.
+// Returns:
+// the input and inputTyped, or inputTick or inputDone if the wait was
+// interrupted. Returns ErrEndOfInput if there is no more input,
+// ErrInterrupted if Ctrl+C is pressed and the player confirms quitting, and
+// ErrTerminated on SIGTERM: the callers return it up to the command line
+// program, which exits.
+func waitInput(single bool, tick <-chan time.Time, done <-chan struct{}) (string, inputEvent,
+	error) {
+	startStdinReader()
+	for {
+		// Only one of stdinLines and stdinKeys is set, a nil channel is never
+		// ready.
+		select {
+		case line, ok := <-stdinLines:
+			if !ok {
+				return "", inputDone, quitInput("No more input, exiting.", ErrEndOfInput)
+			}
+			return line, inputTyped, nil
+		case key, ok := <-stdinKeys:
+			if !ok {
+				return "", inputDone, quitInput("No more input, exiting.", ErrEndOfInput)
+			}
+			if line, complete := stdinEditor.press(key, single); complete {
+				return line, inputTyped, nil
+			}
+		case <-stdinInterrupt:
+			// Pressing Ctrl+C again while asked to confirm quits right away.
+			if confirmingQuit {
+				return "", inputDone, ErrInterrupted
+			}
+			quit, err := confirmQuit()
+			if err != nil {
+				return "", inputDone, err
+			}
+			if quit {
+				return "", inputDone, quitInput("Interrupted, exiting.", ErrInterrupted)
+			}
+		case <-stdinTerminate:
+			return "", inputDone, quitInput("Terminated, exiting.", ErrTerminated)
+		case <-tick:
+			return "", inputTick, nil
+		case <-done:
+			return "", inputDone, nil
+		}
+	}
+}
+
+// Read a single line from stdin. Returns an error if the player stopped
+// playing, see waitInput.
+func readLine() (string, error) {
+	line, _, err := waitInput(false, nil, nil)
+	return line, err
+}
+
+// Read the input for a single character: in key mode the first key pressed is
+// returned without waiting for Enter. Returns an error if the player stopped
+// playing, see waitInput.
+func readKey() (string, error) {
+	line, _, err := waitInput(true, nil, nil)
+	return line, err
+}
+
+// Read an integer from stdin. Returns an error if the line is not an integer,
+// or if the player stopped playing (see isInputEnd).
+func readInt() (int, error) {
+	line, err := readLine()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(line))
+}
+
+// Read a single character for a turn of a game, which ends when the context is
+// done. A countdown is shown till the deadline of the turn, if it has one.
+// Returns false if the turn ended (and the game recorded the timeout) before a
+// valid character was given, and an error if the player stopped playing.
+// Commands like "why" (or "?" in key mode) can be typed instead of the
+// character, see runGameCommand.
+func readTimedChar(ctx context.Context, game *Game) (rune, bool, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		for {
+			line, err := readKey()
+			if err != nil {
+				return 0, false, err
+			}
+			if runGameCommand(game, line) {
+				continue
+			}
+			if char, valid := parseChar(line); valid {
+				return char, true, nil
+			}
+			fmt.Println(tr("invalid_char"))
+		}
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	var previous time.Duration
+	for {
+		remaining := deadline.Sub(time.Now()).Round(time.Second)
+		if remaining < 0 {
+			remaining = 0
+		}
+		printTimeLeft(remaining, previous)
+		previous = remaining
+		line, event, err := waitInput(true, ticker.C, ctx.Done())
+		if err != nil {
+			return 0, false, err
+		}
+		switch event {
+		case inputTick:
+			continue
+		case inputDone:
+			game.Tick()
+			fmt.Println()
+			return 0, false, nil
+		}
+		if runGameCommand(game, line) {
+			continue
+		}
+		char, valid := parseChar(line)
+		if !valid {
+			fmt.Println(tr("invalid_char"))
+			continue
+		}
+		return char, true, nil
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"github.com/hackeracc/WordGuess/api"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	outputFormat = flag.String("output", "text",
+		"Output of the terminal game: \"text\" for the players, or \"json\" to "+
+			"write every prompt and state change as a line of JSON and read the "+
+			"input one line at a time, so that other programs can play.")
+)
+
+// Output format of the terminal game for other programs, see api.CLIEvent.
+const outputJSON = "json"
+
+// Method to check if the terminal game writes JSON lines. Returns an error if
+// --output is not valid.
+func jsonOutputFromFlags() (bool, error) {
+	switch *outputFormat {
+	case "text":
+		return false, nil
+	case outputJSON:
+		return true, nil
+	}
+	return false, configError("unknown --output %s, expected text or json", *outputFormat)
+}
+
+// Terminal game played by another program, which reads the events written as
+// JSON lines and answers the prompts with lines of input.
+type jsonCLI struct {
+	in  *bufio.Scanner
+	out *json.Encoder
+	// Options of the games.
+	opts []GameOption
+	// Number of games started, which is the id of the last one.
+	games int
+}
+
+func newJSONCLI(in io.Reader, out io.Writer, opts ...GameOption) *jsonCLI {
+	return &jsonCLI{in: bufio.NewScanner(in), out: json.NewEncoder(out), opts: opts}
+}
+
+// Method to write an event.
+func (c *jsonCLI) emit(event api.CLIEvent) {
+	c.out.Encode(event)
+}
+
+// Method to write a prompt and read the line answering it.
+// Returns false if the input ended.
+func (c *jsonCLI) read(prompt api.CLIPrompt, view *api.Game) (string, bool) {
+	c.emit(api.CLIEvent{Type: api.CLIEventPrompt, Prompt: prompt, Game: view})
+	if !c.in.Scan() {
+		return "", false
+	}
+	return strings.TrimSpace(c.in.Text()), true
+}
+
+// Method to read a number, prompting again till the input is a number.
+// Returns false if the input ended.
+func (c *jsonCLI) readInt(prompt api.CLIPrompt) (int, bool) {
+	for {
+		line, ok := c.read(prompt, nil)
+		if !ok {
+			return 0, false
+		}
+		n, err := strconv.Atoi(line)
+		if err == nil {
+			return n, true
+		}
+		c.emit(api.CLIEvent{Type: api.CLIEventError, Error: api.NewError(
+			api.CodeInvalidRequest, "%q is not a number", line).WithDetail("prompt", prompt)})
+	}
+}
+
+// Method to play games till the player quits or the input ends.
+func (c *jsonCLI) run() {
+	defer c.emit(api.CLIEvent{Type: api.CLIEventQuit})
+	for {
+		line, ok := c.read(api.PromptNewGame, nil)
+		if !ok || strings.EqualFold(line, "n") {
+			return
+		}
+		if !strings.EqualFold(line, "y") {
+			c.emit(api.CLIEvent{Type: api.CLIEventError, Error: api.NewError(
+				api.CodeInvalidRequest, "expected y or n, got %q", line).
+				WithDetail("prompt", api.PromptNewGame)})
+			continue
+		}
+		game, ok := c.newGame()
+		if !ok {
+			return
+		}
+		if !c.play(game) {
+			return
+		}
+	}
+}
+
+// Method to read the configuration of a game and start it, asking again till
+// the configuration is valid.
+// Returns false if the input ended.
+func (c *jsonCLI) newGame() (*Game, bool) {
+	for {
+		expectedLen, ok := c.readInt(api.PromptWordLength)
+		if !ok {
+			return nil, false
+		}
+		retries, ok := c.readInt(api.PromptRetries)
+		if !ok {
+			return nil, false
+		}
+		var game *Game
+		var err error
+		if expectedLen == 0 {
+			game, err = NewGameRandomLength(retries, c.opts...)
+		} else {
+			game, err = NewGame(expectedLen, retries, c.opts...)
+		}
+		if err != nil {
+			c.emit(api.CLIEvent{Type: api.CLIEventError,
+				Error: inputErrorToAPI(err, expectedLen, retries)})
+			continue
+		}
+		c.games++
+		view := c.view(game)
+		c.emit(api.CLIEvent{Type: api.CLIEventStarted, Game: &view})
+		return game, true
+	}
+}
+
+// Method to get the public view of a game.
+func (c *jsonCLI) view(game *Game) api.Game {
+	return apiGame(strconv.Itoa(c.games), game)
+}
+
+// Method to play a game till it is won or lost.
+// Returns false if the input ended first.
+func (c *jsonCLI) play(game *Game) bool {
+	for game.State == Running {
+		view := c.view(game)
+		line, ok := c.read(api.PromptGuess, &view)
+		if !ok {
+			return false
+		}
+		char, size := utf8.DecodeRuneInString(line)
+		if size == 0 || size != len(line) || unicode.IsSpace(char) {
+			c.emit(api.CLIEvent{Type: api.CLIEventError, Error: api.NewError(
+				api.CodeInvalidCharacter, "expected a single character, got %q", line).
+				WithDetail("prompt", api.PromptGuess)})
+			continue
+		}
+		accepted, err := game.CheckUserInput(char)
+		if err != nil {
+			c.emit(api.CLIEvent{Type: api.CLIEventError, Error: guessErrorToAPI(char, err)})
+			continue
+		}
+		view = c.view(game)
+		c.emit(api.CLIEvent{Type: api.CLIEventGuess, Char: string(char), Accepted: &accepted,
+			Game: &view})
+	}
+	view := c.view(game)
+	word := string(game.CurrentDisplayedWord)
+	if game.State == Lost {
+		word = game.RevealWord()
+	}
+	c.emit(api.CLIEvent{Type: api.CLIEventOver, Game: &view, Word: word})
+	return true
+}
+
+// Driver method for the terminal game with --output=json. The guess timeout
+// is not used, as the programs playing do not need one.
+func StartJSONGame() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	revealPolicy, err := revealPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	retryPolicy, err := retryPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	strategy, err := strategyFromFlags()
+	if err != nil {
+		return err
+	}
+	newJSONCLI(os.Stdin, os.Stdout, WithRevealPolicy(revealPolicy),
+		WithRetryPolicy(retryPolicy), WithStrategy(strategy)).run()
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type JSONCLITestSuite struct {
+	suite.Suite
+}
+
+func (s *JSONCLITestSuite) SetupTest() {
+	InitGame([]string{"cat"})
+}
+
+func (s *JSONCLITestSuite) TearDownTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to play with the given lines of input and decode the events.
+func (s *JSONCLITestSuite) run(input string) []api.CLIEvent {
+	var out bytes.Buffer
+	newJSONCLI(strings.NewReader(input), &out).run()
+	var events []api.CLIEvent
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var event api.CLIEvent
+		assert.Nil(s.T(), decoder.Decode(&event))
+		events = append(events, event)
+	}
+	return events
+}
+
+// Method to get the types of the events, with the prompts.
+func eventTypes(events []api.CLIEvent) []string {
+	var types []string
+	for _, event := range events {
+		if event.Type == api.CLIEventPrompt {
+			types = append(types, string(event.Prompt))
+		} else {
+			types = append(types, string(event.Type))
+		}
+	}
+	return types
+}
+
+func (s *JSONCLITestSuite) TestWin() {
+	events := s.run("y\n3\n2\nz\nc\na\nt\nn\n")
+	assert.Equal(s.T(), []string{"new_game", "word_length", "retries", "started",
+		"guess", "guess", "guess", "guess", "guess", "guess", "guess", "guess", "over",
+		"new_game", "quit"}, eventTypes(events))
+
+	started := events[3]
+	assert.Equal(s.T(), "1", started.Game.ID)
+	assert.Equal(s.T(), "___", started.Game.MaskedWord)
+	assert.Equal(s.T(), 2, started.Game.RetriesLeft)
+
+	wrong := events[5]
+	assert.Equal(s.T(), "z", wrong.Char)
+	assert.False(s.T(), *wrong.Accepted)
+	assert.Equal(s.T(), 1, wrong.Game.RetriesLeft)
+
+	over := events[12]
+	assert.Equal(s.T(), "cat", over.Word)
+	assert.Equal(s.T(), api.StateWon, over.Game.State)
+}
+
+func (s *JSONCLITestSuite) TestLossRevealsWord() {
+	events := s.run("y\n3\n1\nz\n")
+	over := events[len(events)-3]
+	assert.Equal(s.T(), api.CLIEventOver, over.Type)
+	assert.Equal(s.T(), api.StateLost, over.Game.State)
+	assert.Equal(s.T(), "cat", over.Word)
+	// The input ended at the new game prompt.
+	assert.Equal(s.T(), api.PromptNewGame, events[len(events)-2].Prompt)
+	assert.Equal(s.T(), api.CLIEventQuit, events[len(events)-1].Type)
+}
+
+func (s *JSONCLITestSuite) TestInvalidInput() {
+	events := s.run("maybe\ny\nthree\n7\n3\n3\n3\nab\n!\nc\nc\n")
+	assert.Equal(s.T(), []string{"new_game", "error", "new_game", "word_length", "error",
+		"word_length", "retries", "error", "word_length", "retries", "started",
+		"guess", "error", "guess", "error", "guess", "guess", "guess", "error", "guess",
+		"quit"}, eventTypes(events))
+	assert.Equal(s.T(), api.CodeInvalidRequest, events[1].Error.Code)
+	assert.Equal(s.T(), api.CodeInvalidLength, events[7].Error.Code)
+	assert.Equal(s.T(), api.CodeInvalidCharacter, events[12].Error.Code)
+	assert.Equal(s.T(), api.CodeInvalidCharacter, events[14].Error.Code)
+	assert.Equal(s.T(), api.CodeCharacterUsed, events[18].Error.Code)
+}
+
+func TestJSONCLITestSuite(t *testing.T) {
+	suite.Run(t, new(JSONCLITestSuite))
+}
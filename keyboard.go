@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	keyboardName = flag.String("keyboard", "",
+		"Layout of the keyboard shown before every guess, with the letters "+
+			"guessed right in upper case and the wrong ones as dots: \"qwerty\", "+
+			"\"azerty\" or \"dvorak\". No keyboard is shown if empty.")
+)
+
+// Rows of the letters of the keyboard layouts, from the top row.
+var keyboardLayouts = map[string][]string{
+	"qwerty": {"qwertyuiop", "asdfghjkl", "zxcvbnm"},
+	"azerty": {"azertyuiop", "qsdfghjklm", "wxcvbn"},
+	"dvorak": {"pyfgcrl", "aoeuidhtns", "qjkxbmwvz"},
+}
+
+// Layout of the keyboard shown in the terminal game, set from the flags when
+// the program starts. Nil if no keyboard is shown.
+var keyboard []string
+
+// Method to get the names of the layouts, sorted.
+func keyboardLayoutNames() []string {
+	var names []string
+	for name := range keyboardLayouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Method to set up the keyboard shown in the terminal game based on the flags.
+// Returns an error if the keyboard is unknown.
+func setupKeyboard() error {
+	if *keyboardName == "" {
+		return nil
+	}
+	layout, ok := keyboardLayouts[strings.ToLower(*keyboardName)]
+	if !ok {
+		return configError("unknown keyboard %s, expected one of %s", *keyboardName,
+			strings.Join(keyboardLayoutNames(), ", "))
+	}
+	keyboard = layout
+	return nil
+}
+
+// Method to draw a keyboard with the outcome of the guesses: the letters
+// guessed right are in upper case and colored as correct, the wrong ones are
+// replaced by a dot colored as wrong, and the others are left as they are.
+// Every row is shifted by one more space than the one above, like on a real
+// keyboard.
+func formatKeyboard(layout []string, history []GuessRecord, theme *ColorTheme) string {
+	correct := make(map[rune]bool)
+	for _, record := range history {
+		if !record.Timeout {
+			correct[unicode.ToLower(record.Char)] = record.Correct
+		}
+	}
+	lines := make([]string, len(layout))
+	for i, row := range layout {
+		keys := make([]string, 0, len(row))
+		for _, char := range row {
+			right, guessed := correct[char]
+			switch {
+			case !guessed:
+				keys = append(keys, string(char))
+			case right:
+				keys = append(keys, theme.correct(string(unicode.ToUpper(char))))
+			default:
+				keys = append(keys, theme.wrong("·"))
+			}
+		}
+		lines[i] = strings.Repeat(" ", i) + strings.Join(keys, " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type KeyboardTestSuite struct {
+	suite.Suite
+}
+
+func (s *KeyboardTestSuite) TestFormatKeyboard() {
+	history := []GuessRecord{{Char: 'e', Correct: true, Positions: 1}, {Char: 'z'},
+		{Timeout: true}}
+	assert.Equal(s.T(), "q w E r t y u i o p\n"+
+		" a s d f g h j k l\n"+
+		"  · x c v b n m", formatKeyboard(keyboardLayouts["qwerty"], history, nil))
+	lines := strings.Split(formatKeyboard(keyboardLayouts["azerty"], history, nil), "\n")
+	assert.Equal(s.T(), "  w x c v b n", lines[2])
+}
+
+func (s *KeyboardTestSuite) TestColors() {
+	theme := &DefaultColorTheme
+	text := formatKeyboard([]string{"ez"}, []GuessRecord{{Char: 'e', Correct: true},
+		{Char: 'z'}}, theme)
+	assert.Equal(s.T(), theme.correct("E")+" "+theme.wrong("·"), text)
+}
+
+func (s *KeyboardTestSuite) TestLayoutsHaveAllLetters() {
+	for _, name := range keyboardLayoutNames() {
+		letters := strings.Join(keyboardLayouts[name], "")
+		assert.Len(s.T(), letters, 26, name)
+		for char := 'a'; char <= 'z'; char++ {
+			assert.Contains(s.T(), letters, string(char), name)
+		}
+	}
+}
+
+func TestKeyboardTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyboardTestSuite))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	kidsMode = flag.Bool("kids", false,
+		"Play in kids mode: short common words (--lang=en-kids), a merciful "+
+			"opponent, more retries, a free vowel at the start of every game and "+
+			"encouraging messages. The flags given explicitly win over the mode.")
+	freeVowel = flag.Bool("free_vowel", false,
+		"Give a vowel of the word for free at the start of every terminal game.")
+)
+
+// Letters given by --free_vowel.
+const vowels = "aeiou"
+
+// Flags set by --kids, unless they are given explicitly. The mode is only a
+// layer over the existing options, so every part of it can be changed.
+var kidsFlags = map[string]string{
+	"lang":                "en-kids",
+	"opponent":            "merciful",
+	"max_allowed_retries": "15",
+	"family_friendly":     "true",
+	"free_vowel":          "true",
+}
+
+// Encouraging messages of the kids mode, see --lang=en-kids. The other
+// messages are the English ones.
+var kidsMessages = map[string]string{
+	"lengths":       "You can pick a word of %s letters (0 for a surprise)",
+	"random_length": "Surprise! Your word has %d letters.",
+	"enter_length":  "How many letters should the word have? ",
+	"enter_retries": "How many tries do you want? (8 is a good start, up to %d):",
+	"no_words":      "Oops, there are no words with %d letters. Let's try another number!",
+	"enter_char":    "Pick a letter! (letters so far: %s, tries left %d): ",
+	"invalid_char":  "That's not a letter we can use, try another one!",
+	"right_char":    "Yes! Great guess!",
+	"wrong_char":    "Not this time, but you're doing great! Tries left: %d",
+	"won":           "Hooray, you found the word! Amazing job!",
+	"lost":          "Good try! The word was: %s. Let's play again!",
+	"time_up":       "Time's up! No worries, tries left: %d",
+	"free_vowel":    "Here is a free letter to get you started: %c",
+}
+
+func init() {
+	RegisterLanguage(LanguagePack{Code: "en-kids", Name: "English (kids)",
+		Dictionary: builtinDictionary("en-kids"),
+		Metadata:   builtinMetadata("en-kids"),
+		Messages:   kidsMessages})
+}
+
+// Method to apply the kids mode to the flags which were not given explicitly.
+// Must be called after the flags are parsed and before they are used.
+// Returns an error if the mode can not be applied.
+func setupKidsMode() error {
+	if !*kidsMode {
+		return nil
+	}
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	if err := applyFlagLayer(kidsFlags, explicit); err != nil {
+		return configError("unable to set up the kids mode: %w", err)
+	}
+	return nil
+}
+
+// Method to set the flags of a layer, except the explicit ones. The flags
+// are set in the order of their names, so that errors are reproducible.
+func applyFlagLayer(layer map[string]string, explicit map[string]bool) error {
+	names := make([]string, 0, len(layer))
+	for name := range layer {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if explicit[name] {
+			continue
+		}
+		if err := flag.Set(name, layer[name]); err != nil {
+			return fmt.Errorf("--%s=%s: %v", name, layer[name], err)
+		}
+	}
+	return nil
+}
+
+// Method to guess a vowel of the word for the player at the start of a game:
+// the vowel in the most remaining words. The game first drops the words
+// without it, so that the guess is accepted whatever the opponent. Returns
+// false if no remaining word has a vowel, in which case nothing is guessed.
+func giveFreeVowel(game *Game) (rune, bool) {
+	for _, f := range game.LetterFrequencies() {
+		if !strings.ContainsRune(vowels, f.Char) {
+			continue
+		}
+		game.keepWordsContaining(f.Char)
+		accepted, err := game.CheckUserInput(f.Char)
+		return f.Char, err == nil && accepted
+	}
+	return 0, false
+}
+
+// Method to drop the words the game is choosing from which do not contain a
+// character, before the first guess.
+func (g *Game) keepWordsContaining(char rune) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var kept []string
+	for _, word := range g.candidatesLocked() {
+		if strings.ContainsRune(word, char) {
+			kept = append(kept, word)
+		}
+	}
+	g.CurrentSetOfWords, g.packed, g.bits = kept, nil, nil
+}
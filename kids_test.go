@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type KidsTestSuite struct {
+	suite.Suite
+}
+
+func (s *KidsTestSuite) SetupTest() {
+	InitGame([]string{"cat", "dog", "sun"})
+}
+
+func (s *KidsTestSuite) TestFlagLayer() {
+	oldOpponent, oldRetries := *opponentName, *maxAllowedRetries
+	defer func() {
+		*opponentName, *maxAllowedRetries = oldOpponent, oldRetries
+	}()
+	*opponentName = "chaotic"
+	err := applyFlagLayer(map[string]string{"opponent": "merciful",
+		"max_allowed_retries": "15"}, map[string]bool{"opponent": true})
+	assert.Nil(s.T(), err)
+	// The explicit flags are kept.
+	assert.Equal(s.T(), "chaotic", *opponentName)
+	assert.Equal(s.T(), 15, *maxAllowedRetries)
+
+	err = applyFlagLayer(map[string]string{"max_allowed_retries": "many"}, nil)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *KidsTestSuite) TestKidsFlags() {
+	for name := range kidsFlags {
+		assert.NotNil(s.T(), flag.Lookup(name), name)
+	}
+}
+
+func (s *KidsTestSuite) TestKidsDictionary() {
+	pack, ok := lookupLanguage("en-kids")
+	assert.True(s.T(), ok)
+	lines := strings.Split(strings.TrimSpace(string(pack.Dictionary)), "\n")
+	report := checkDictionary(lines, NewAlphabet(""))
+	assert.Empty(s.T(), report.Invalid)
+	assert.Empty(s.T(), report.Duplicates)
+	for length := range report.Lengths {
+		assert.LessOrEqual(s.T(), length, 5)
+	}
+}
+
+func (s *KidsTestSuite) TestFreeVowel() {
+	game, err := NewGame(3, 5, WithStrategy(Merciful{}))
+	assert.Nil(s.T(), err)
+	vowel, ok := giveFreeVowel(game)
+	assert.True(s.T(), ok)
+	assert.Contains(s.T(), vowels, string(vowel))
+	assert.Equal(s.T(), 5, game.CurrentRetries)
+	assert.Contains(s.T(), string(game.CurrentDisplayedWord), string(vowel))
+
+	// The vowel is accepted whatever the opponent.
+	game, err = NewGame(3, 5)
+	assert.Nil(s.T(), err)
+	_, ok = giveFreeVowel(game)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 1, len(game.Candidates()))
+
+	// No vowel is given if no word has one.
+	InitGame([]string{"dry", "fly"})
+	game, err = NewGame(3, 5)
+	assert.Nil(s.T(), err)
+	_, ok = giveFreeVowel(game)
+	assert.False(s.T(), ok)
+	assert.Equal(s.T(), 5, game.CurrentRetries)
+}
+
+func TestKidsTestSuite(t *testing.T) {
+	suite.Run(t, new(KidsTestSuite))
+}
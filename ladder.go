@@ -0,0 +1,352 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// Steps of the shortest ladder between the words of a random word ladder.
+	ladderMinSteps = 2
+	ladderMaxSteps = 6
+	// Start words tried before giving up on a random word ladder.
+	ladderAttempts = 50
+)
+
+// Graph of the words of the same length, where two words are neighbours if
+// they differ by exactly one letter, e.g. "cat" and "cot".
+type wordGraph struct {
+	// Words of the graph, sorted.
+	words []string
+	// Index of every word in words.
+	ids map[string]int
+	// Neighbours of every word, by index, sorted.
+	neighbours [][]int
+}
+
+// Method to build the graph of words of the same length. Rather than comparing
+// every pair of words, the words are grouped by their letters with one of them
+// left out: the words of a group are all neighbours, and two neighbours share
+// exactly one group.
+func newWordGraph(words []string) *wordGraph {
+	g := &wordGraph{
+		words:      words,
+		ids:        make(map[string]int, len(words)),
+		neighbours: make([][]int, len(words)),
+	}
+	groups := make(map[string][]int)
+	for i, word := range words {
+		g.ids[word] = i
+		chars := []rune(word)
+		for j, char := range chars {
+			chars[j] = 0
+			key := string(chars)
+			groups[key] = append(groups[key], i)
+			chars[j] = char
+		}
+	}
+	for _, group := range groups {
+		for _, i := range group {
+			for _, j := range group {
+				if i != j {
+					g.neighbours[i] = append(g.neighbours[i], j)
+				}
+			}
+		}
+	}
+	for _, neighbours := range g.neighbours {
+		sort.Ints(neighbours)
+	}
+	return g
+}
+
+// Method to build the graph of the words of a length of the dictionary which
+// can be used in a word ladder. Phrases and words with punctuation are left
+// out, as their separators are not letters to change.
+func newLadderGraph(d *Dictionary, length int) *wordGraph {
+	var words []string
+	for _, word := range d.Words(length) {
+		if !strings.ContainsFunc(word, isRevealedChar) {
+			words = append(words, word)
+		}
+	}
+	return newWordGraph(words)
+}
+
+// Method to find the distance of every word of the graph to a word, with a
+// breadth first search, and the word before it on a shortest ladder from the
+// word. The distance of the words which can not be reached is -1.
+func (g *wordGraph) search(from int) (distances []int, previous []int) {
+	distances = make([]int, len(g.words))
+	previous = make([]int, len(g.words))
+	for i := range distances {
+		distances[i] = -1
+		previous[i] = -1
+	}
+	distances[from] = 0
+	queue := []int{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range g.neighbours[current] {
+			if distances[next] < 0 {
+				distances[next] = distances[current] + 1
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return distances, previous
+}
+
+// Method to find a shortest ladder between two words of the graph, both
+// included. Returns nil if there is none.
+func (g *wordGraph) shortestLadder(from, to string) []string {
+	start, ok := g.ids[from]
+	if !ok {
+		return nil
+	}
+	end, ok := g.ids[to]
+	if !ok {
+		return nil
+	}
+	// Searched from the end, so that following the previous words leads
+	// from the start to the end.
+	distances, previous := g.search(end)
+	if distances[start] < 0 {
+		return nil
+	}
+	ladder := []string{from}
+	for current := start; current != end; {
+		current = previous[current]
+		ladder = append(ladder, g.words[current])
+	}
+	return ladder
+}
+
+// Method to check if two words differ by exactly one letter.
+func oneLetterApart(a, b string) bool {
+	charsA, charsB := []rune(a), []rune(b)
+	if len(charsA) != len(charsB) {
+		return false
+	}
+	diff := 0
+	for i := range charsA {
+		if charsA[i] != charsB[i] {
+			diff++
+		}
+	}
+	return diff == 1
+}
+
+// Method to find a shortest word ladder between two words of the dictionary,
+// both included. The words are folded like the guesses.
+// It returns an error wrapping ErrUnknownWord if a word is not in the
+// dictionary, or ErrNoLadder if no ladder joins them.
+func ShortestLadder(d *Dictionary, from, to string) ([]string, error) {
+	_, ladder, err := findLadder(d, from, to)
+	return ladder, err
+}
+
+// Method to find a shortest word ladder between two words of the dictionary,
+// along with the graph of the words of their length. See ShortestLadder.
+func findLadder(d *Dictionary, from, to string) (*wordGraph, []string, error) {
+	from, to = d.alphabet.FoldWord(from), d.alphabet.FoldWord(to)
+	length := utf8.RuneCountInString(from)
+	if utf8.RuneCountInString(to) != length {
+		return nil, nil, newGameError(ErrNoLadder,
+			"%s and %s do not have the same length, no ladder joins them", from, to)
+	}
+	g := newLadderGraph(d, length)
+	for _, word := range []string{from, to} {
+		if _, ok := g.ids[word]; !ok {
+			return nil, nil, newGameError(ErrUnknownWord,
+				"%s is not a word of the dictionary", word)
+		}
+	}
+	ladder := g.shortestLadder(from, to)
+	if ladder == nil {
+		return nil, nil, newGameError(ErrNoLadder, "No ladder joins %s and %s", from, to)
+	}
+	return g, ladder, nil
+}
+
+// Method to check a word ladder: it has at least 2 words, all of them are in
+// the dictionary and every word changes one letter of the word before it,
+// without going back to a word of the ladder. The words are folded like the
+// guesses.
+// It returns an error wrapping ErrUnknownWord or ErrInvalidStep for the first
+// word which is not valid, or ErrNoLadder if there are less than 2 words.
+func CheckLadder(d *Dictionary, ladder []string) error {
+	if len(ladder) < 2 {
+		return newGameError(ErrNoLadder, "A ladder needs at least 2 words, got %d",
+			len(ladder))
+	}
+	g := newLadderGraph(d, utf8.RuneCountInString(d.alphabet.FoldWord(ladder[0])))
+	seen := make(map[string]bool)
+	previous := ""
+	for i, word := range ladder {
+		word = d.alphabet.FoldWord(word)
+		if i > 0 && !oneLetterApart(previous, word) {
+			return newGameError(ErrInvalidStep,
+				"Word %d, %s, does not change exactly one letter of %s", i+1, word, previous)
+		}
+		if seen[word] {
+			return newGameError(ErrInvalidStep,
+				"Word %d, %s, is already in the ladder", i+1, word)
+		}
+		if _, ok := g.ids[word]; !ok {
+			return newGameError(ErrUnknownWord,
+				"Word %d, %s, is not a word of the dictionary", i+1, word)
+		}
+		seen[word] = true
+		previous = word
+	}
+	return nil
+}
+
+// Word ladder puzzle: the player goes from the start word to the target word
+// one word at a time, every word changing one letter of the word before it,
+// e.g. cat, cot, dot, dog. Every word must be in the dictionary, and the
+// player has a limited number of steps.
+type WordLadder struct {
+	// Word the ladder starts from.
+	Start string
+	// Word the ladder must reach.
+	Target string
+	// Words of the ladder so far, starting with Start.
+	Steps []string
+	// Steps of the shortest ladder from Start to Target.
+	Par int
+	// Total steps allowed, Par plus the extra steps.
+	AllowedSteps int
+	// Current state of the game.
+	State GameState
+
+	graph    *wordGraph
+	alphabet Alphabet
+}
+
+// Method to start a word ladder with words of the given length picked in the
+// dictionary, see pickWord, joined by a shortest ladder of 2 to 6 steps. The
+// player is allowed extraSteps steps more than the shortest ladder.
+// It returns an error wrapping ErrInvalidLength if the dictionary has no word
+// of that length, ErrNoLadder if no ladder was found between its words, or
+// ErrInvalidRetries if the extra steps are negative.
+func NewWordLadder(d *Dictionary, length, extraSteps int, rng *rand.Rand) (*WordLadder, error) {
+	if extraSteps < 0 {
+		return nil, newGameError(ErrInvalidRetries,
+			"Extra steps must not be negative, got %d", extraSteps)
+	}
+	g := newLadderGraph(d, length)
+	if len(g.words) == 0 {
+		return nil, newGameError(ErrInvalidLength,
+			"No words of length %d in the dictionary to make a ladder of", length)
+	}
+	for attempt := 0; attempt < ladderAttempts; attempt++ {
+		start := g.ids[g.words[pickWord(rng, d, g.words)]]
+		distances, _ := g.search(start)
+		var targets []int
+		for i, distance := range distances {
+			if distance >= ladderMinSteps && distance <= ladderMaxSteps {
+				targets = append(targets, i)
+			}
+		}
+		if len(targets) > 0 {
+			target := targets[rng.Intn(len(targets))]
+			return newWordLadder(g, d, g.words[start], g.words[target], extraSteps), nil
+		}
+	}
+	return nil, newGameError(ErrNoLadder,
+		"Unable to find a ladder between words of length %d, try another length", length)
+}
+
+// Method to start a word ladder between two words of the dictionary, e.g. to
+// play a ladder given by someone else. The player is allowed extraSteps steps
+// more than the shortest ladder.
+// It returns an error wrapping ErrUnknownWord if a word is not in the
+// dictionary, ErrNoLadder if no ladder joins them or they are the same, or
+// ErrInvalidRetries if the extra steps are negative.
+func NewWordLadderBetween(d *Dictionary, start, target string, extraSteps int) (
+	*WordLadder, error) {
+	if extraSteps < 0 {
+		return nil, newGameError(ErrInvalidRetries,
+			"Extra steps must not be negative, got %d", extraSteps)
+	}
+	g, ladder, err := findLadder(d, start, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(ladder) < 2 {
+		return nil, newGameError(ErrNoLadder,
+			"The ladder must go to another word than %s", ladder[0])
+	}
+	return newWordLadder(g, d, ladder[0], ladder[len(ladder)-1], extraSteps), nil
+}
+
+// Method to create a word ladder between two words of the graph joined by a
+// ladder.
+func newWordLadder(g *wordGraph, d *Dictionary, start, target string, extraSteps int) *WordLadder {
+	par := len(g.shortestLadder(start, target)) - 1
+	return &WordLadder{
+		Start:        start,
+		Target:       target,
+		Steps:        []string{start},
+		Par:          par,
+		AllowedSteps: par + extraSteps,
+		State:        Running,
+		graph:        g,
+		alphabet:     d.alphabet,
+	}
+}
+
+// Method to get the last word of the ladder so far.
+func (l *WordLadder) Current() string {
+	return l.Steps[len(l.Steps)-1]
+}
+
+// Method to get the steps left.
+func (l *WordLadder) StepsLeft() int {
+	return l.AllowedSteps - (len(l.Steps) - 1)
+}
+
+// Method to add a word to the ladder. The game is won once the target is
+// reached, and lost once the steps are finished.
+// It returns an error wrapping ErrGameFinished if the game is over, and
+// ErrUnknownWord or ErrInvalidStep if the word is not in the dictionary, does
+// not change exactly one letter of the last word or is already in the ladder,
+// in which case no step is used.
+func (l *WordLadder) Play(word string) error {
+	if l.State != Running {
+		return newGameError(ErrGameFinished,
+			"Unexpected scenario: input given for a ladder which is not running")
+	}
+	word = l.alphabet.FoldWord(strings.TrimSpace(word))
+	if _, ok := l.graph.ids[word]; !ok {
+		return newGameError(ErrUnknownWord,
+			"%s is not a word of the dictionary, please try again.", word)
+	}
+	if !oneLetterApart(l.Current(), word) {
+		return newGameError(ErrInvalidStep,
+			"%s does not change exactly one letter of %s, please try again.", word, l.Current())
+	}
+	if containsString(l.Steps, word) {
+		return newGameError(ErrInvalidStep,
+			"%s is already in the ladder, please try again.", word)
+	}
+	l.Steps = append(l.Steps, word)
+	if word == l.Target {
+		l.State = Won
+	} else if l.StepsLeft() <= 0 {
+		l.State = Lost
+	}
+	return nil
+}
+
+// Method to get a shortest ladder from the last word of the ladder so far to
+// the target, both included, e.g. to show it once the game is lost.
+func (l *WordLadder) Solution() []string {
+	return l.graph.shortestLadder(l.Current(), l.Target)
+}
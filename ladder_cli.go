@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var (
+	ladderExtraSteps = flag.Int("ladder_extra_steps", 3,
+		"Number of steps allowed in the \"ladder\" subcommand on top of the steps "+
+			"of the shortest ladder.")
+)
+
+// Method to show a word ladder, e.g. "cat -> cot -> dot".
+func formatLadder(words []string) string {
+	return strings.Join(words, " -> ")
+}
+
+// Driver method for the "ladder" subcommand.
+// "ladder" plays a word ladder between words of a length picked by the player,
+// "ladder <start> <target>" plays one between the given words, and
+// "ladder check <word>..." checks a ladder and shows a shortest one.
+func StartLadder(args []string) error {
+	if len(args) > 0 && args[0] == "check" {
+		return checkLadderCommand(args[1:])
+	}
+	if len(args) != 0 && len(args) != 2 {
+		return usageError("hangman ladder [<start> <target> | check <word>...]")
+	}
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	d := currentDictionary()
+	var ladder *WordLadder
+	if len(args) == 2 {
+		var err error
+		ladder, err = NewWordLadderBetween(d, args[0], args[1], *ladderExtraSteps)
+		if err != nil {
+			return err
+		}
+	}
+	for ladder == nil {
+		fmt.Println(tr("enter_length"))
+		length, err := readInt()
+		if isInputEnd(err) {
+			return err
+		}
+		if err != nil {
+			fmt.Println(tr("invalid_input", err))
+			continue
+		}
+		ladder, err = NewWordLadder(d, length, *ladderExtraSteps,
+			rand.New(rand.NewSource(randInt63())))
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+	fmt.Printf("Go from %s to %s changing one letter at a time (the shortest ladder "+
+		"has %d steps).\n", ladder.Start, ladder.Target, ladder.Par)
+	for ladder.State == Running {
+		fmt.Println(formatLadder(ladder.Steps))
+		fmt.Printf("Enter the next word (%d steps left to reach %s): \n", ladder.StepsLeft(),
+			ladder.Target)
+		line, err := readLine()
+		if err != nil {
+			return err
+		}
+		if err := ladder.Play(line); err != nil {
+			fmt.Println(err)
+		}
+	}
+	fmt.Println(formatLadder(ladder.Steps))
+	if ladder.State == Won {
+		fmt.Println(colors.correct(tr("won")))
+	} else {
+		fmt.Println(colors.wrong("All steps finished, you lose!! A ladder from " +
+			ladder.Current() + ": " + formatLadder(ladder.Solution())))
+	}
+	return nil
+}
+
+// Method to check a ladder given on the command line, and show a shortest
+// ladder between its first and last words. Returns an error if the ladder is
+// not valid.
+func checkLadderCommand(words []string) error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	d := currentDictionary()
+	if err := CheckLadder(d, words); err != nil {
+		return fmt.Errorf("invalid ladder: %w", err)
+	}
+	shortest, err := ShortestLadder(d, words[0], words[len(words)-1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Valid ladder of %d steps.\n", len(words)-1)
+	if len(shortest) < len(words) {
+		fmt.Printf("A shortest ladder has %d steps: %s\n", len(shortest)-1,
+			formatLadder(shortest))
+	}
+	return nil
+}
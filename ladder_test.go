@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math/rand"
+	"testing"
+)
+
+type LadderTestSuite struct {
+	suite.Suite
+}
+
+func (s *LadderTestSuite) SetupTest() {
+	InitGame([]string{"cat", "cot", "dot", "dog", "cog", "hat", "hot", "pig", "a b",
+		"a c", "fish"})
+}
+
+func (s *LadderTestSuite) TestWordGraph() {
+	g := newLadderGraph(currentDictionary(), 3)
+	neighbours := func(word string) []string {
+		var words []string
+		for _, i := range g.neighbours[g.ids[word]] {
+			words = append(words, g.words[i])
+		}
+		return words
+	}
+	assert.Equal(s.T(), []string{"cot", "hat"}, neighbours("cat"))
+	assert.Equal(s.T(), []string{"cat", "cog", "dot", "hot"}, neighbours("cot"))
+	assert.Empty(s.T(), neighbours("pig"))
+	// The phrases are left out.
+	_, ok := g.ids["a b"]
+	assert.False(s.T(), ok)
+}
+
+func (s *LadderTestSuite) TestShortestLadder() {
+	d := currentDictionary()
+	ladder, err := ShortestLadder(d, "CAT", "dog")
+	s.Require().NoError(err)
+	assert.Len(s.T(), ladder, 4)
+	assert.Equal(s.T(), "cat", ladder[0])
+	assert.Equal(s.T(), "dog", ladder[3])
+	assert.NoError(s.T(), CheckLadder(d, ladder))
+	ladder, err = ShortestLadder(d, "hat", "dot")
+	s.Require().NoError(err)
+	assert.Equal(s.T(), []string{"hat", "hot", "dot"}, ladder)
+
+	_, err = ShortestLadder(d, "cat", "pig")
+	assert.True(s.T(), errors.Is(err, ErrNoLadder))
+	_, err = ShortestLadder(d, "cat", "fish")
+	assert.True(s.T(), errors.Is(err, ErrNoLadder))
+	_, err = ShortestLadder(d, "cat", "bat")
+	assert.True(s.T(), errors.Is(err, ErrUnknownWord))
+}
+
+func (s *LadderTestSuite) TestCheckLadder() {
+	d := currentDictionary()
+	assert.NoError(s.T(), CheckLadder(d, []string{"cat", "cot", "dot", "dog"}))
+	for _, test := range []struct {
+		ladder []string
+		err    error
+	}{
+		{[]string{"cat"}, ErrNoLadder},
+		{[]string{"cat", "dog"}, ErrInvalidStep},
+		{[]string{"cat", "cot", "cat"}, ErrInvalidStep},
+		{[]string{"cat", "cats"}, ErrInvalidStep},
+		{[]string{"cat", "bat"}, ErrUnknownWord},
+		{[]string{"bat", "cat"}, ErrUnknownWord},
+	} {
+		err := CheckLadder(d, test.ladder)
+		assert.True(s.T(), errors.Is(err, test.err), "%v: %v", test.ladder, err)
+	}
+}
+
+func (s *LadderTestSuite) TestNewWordLadder() {
+	d := currentDictionary()
+	for seed := int64(0); seed < 20; seed++ {
+		ladder, err := NewWordLadder(d, 3, 2, rand.New(rand.NewSource(seed)))
+		s.Require().NoError(err)
+		assert.GreaterOrEqual(s.T(), ladder.Par, ladderMinSteps)
+		assert.Equal(s.T(), ladder.Par+2, ladder.AllowedSteps)
+		assert.Len(s.T(), ladder.Solution(), ladder.Par+1)
+		assert.Equal(s.T(), []string{ladder.Start}, ladder.Steps)
+	}
+	_, err := NewWordLadder(d, 5, 2, rand.New(rand.NewSource(1)))
+	assert.True(s.T(), errors.Is(err, ErrInvalidLength))
+	_, err = NewWordLadder(d, 4, 2, rand.New(rand.NewSource(1)))
+	assert.True(s.T(), errors.Is(err, ErrNoLadder))
+	_, err = NewWordLadder(d, 3, -1, rand.New(rand.NewSource(1)))
+	assert.True(s.T(), errors.Is(err, ErrInvalidRetries))
+	_, err = NewWordLadderBetween(d, "cat", "cat", 2)
+	assert.True(s.T(), errors.Is(err, ErrNoLadder))
+}
+
+func (s *LadderTestSuite) TestPlay() {
+	ladder, err := NewWordLadderBetween(currentDictionary(), "cat", "dog", 1)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), 3, ladder.Par)
+	assert.Equal(s.T(), 4, ladder.StepsLeft())
+
+	assert.True(s.T(), errors.Is(ladder.Play("bat"), ErrUnknownWord))
+	assert.True(s.T(), errors.Is(ladder.Play("dot"), ErrInvalidStep))
+	assert.Equal(s.T(), 4, ladder.StepsLeft())
+
+	s.Require().NoError(ladder.Play("cot"))
+	assert.True(s.T(), errors.Is(ladder.Play("cat"), ErrInvalidStep))
+	s.Require().NoError(ladder.Play(" DOT "))
+	assert.Equal(s.T(), []string{"dot", "dog"}, ladder.Solution())
+	s.Require().NoError(ladder.Play("dog"))
+	assert.Equal(s.T(), Won, ladder.State)
+	assert.Equal(s.T(), []string{"cat", "cot", "dot", "dog"}, ladder.Steps)
+	assert.True(s.T(), errors.Is(ladder.Play("cog"), ErrGameFinished))
+}
+
+func (s *LadderTestSuite) TestStepsFinished() {
+	ladder, err := NewWordLadderBetween(currentDictionary(), "cat", "dog", 0)
+	s.Require().NoError(err)
+	s.Require().NoError(ladder.Play("cot"))
+	s.Require().NoError(ladder.Play("hot"))
+	assert.Equal(s.T(), Running, ladder.State)
+	s.Require().NoError(ladder.Play("hat"))
+	assert.Equal(s.T(), Lost, ladder.State)
+	assert.Equal(s.T(), 0, ladder.StepsLeft())
+	assert.Equal(s.T(), []string{"hat", "hot", "dot", "dog"}, ladder.Solution())
+}
+
+func TestLadderTestSuite(t *testing.T) {
+	suite.Run(t, new(LadderTestSuite))
+}
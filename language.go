@@ -0,0 +1,353 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	languageCode = flag.String("lang", "en",
+		"Language of the game: its word list (unless --dictionary is set), its "+
+			"letters (unless --alphabet is set) and the messages of the terminal "+
+			"game. Built in: en, es, fr, de, hi.")
+	languagePacks = flag.String("language_packs", "",
+		"Directory of additional language packs, one sub directory per language "+
+			"named after its code, with a dictionary.txt word list and an optional "+
+			"pack.json ({\"name\": ..., \"alphabet\": ..., \"messages\": {...}, "+
+			"\"metadata\": {...}}).")
+)
+
+// Word lists of the built in languages other than English, which uses the
+// embedded dictionary.
+//
+//go:embed lang/*.txt
+var languageDictionaries embed.FS
+
+// Language of the game: the words to guess, the letters they are made of, and
+// the messages shown to the player.
+type LanguagePack struct {
+	// Code selecting the language with --lang, e.g. "es".
+	Code string `json:"-"`
+	// Name of the language, in the language itself.
+	Name string `json:"name"`
+	// Letters of the language, see NewAlphabet. Any unicode letter is allowed
+	// if empty.
+	Alphabet string `json:"alphabet"`
+	// Contents of the word list, in the format of --dictionary.
+	Dictionary []byte `json:"-"`
+	// License and attribution of the word list, used instead of a metadata
+	// file, see DictionaryMetadata. Packs without a word list use the
+	// metadata of the embedded English word list.
+	Metadata DictionaryMetadata `json:"metadata"`
+	// Messages shown to the player by key, see tr. The messages missing from
+	// the pack are shown in English.
+	Messages map[string]string `json:"messages"`
+}
+
+var (
+	// Guards languages.
+	languagesMu sync.Mutex
+	// Registered language packs by code.
+	languages = make(map[string]LanguagePack)
+)
+
+// Messages of the terminal game in English, which are also used for the
+// messages missing from a language pack. Some take arguments, formatted
+// with fmt.Sprintf.
+var englishMessages = map[string]string{
+	"new_game":        "Do you want to play a new game? (Y/N): ",
+	"yes":             "y",
+	"invalid_yes_no":  "Invalid input character, please enter a valid input (y/n)",
+	"lengths":         "Word lengths you can play: %s (0 for a surprise)",
+	"random_length":   "Surprise! The word has %d letters.",
+	"enter_length":    "Enter the expected length of the word: ",
+	"enter_retries":   "Enter the expected number of retries(max allowed retries: %d):",
+	"invalid_input":   "Invalid input given, error: %v",
+	"no_words":        "Sorry we do not have any words of length %d in the dictionary. Please try again!",
+	"invalid_retries": "Invalid value of expected retries, please try again",
+	"enter_char":      "Enter a character (previous characters: %s, remaining tries %d): ",
+	"invalid_char":    "Invalid character, please input the character again",
+	"right_char":      "You guessed a right character!!",
+	"wrong_char":      "Sorry its a wrong input. Remaining tries: %d (type why to see why it was rejected)",
+	"won":             "You won! Congratulations!!!",
+	"score":           "Score: %d points (x%.1f streak), session total: %d",
+	"lost":            "All retries finished, you lose!! Chosen word was: %s",
+	"time_up":         "Time is up! Remaining tries: %d",
+	"free_vowel":      "Free vowel: %c",
+	"spoken_word":     "The word has %d characters: %s.",
+	"spoken_blank":    "blank",
+	"spoken_space":    "space",
+	"spoken_guesses":  "Your guesses: %s.",
+	"spoken_right":    "%c, right, %d in the word",
+	"spoken_wrong":    "%c, wrong",
+	"spoken_timeout":  "time ran out",
+	"spoken_tries":    "You have %d tries left.",
+	"spoken_last_try": "You have one try left.",
+	"common_letters":  "Most common letters: %s",
+	"hint_help":       "Type hint (or ! in key mode) to see the clue of the word, once the computer has narrowed its choice to words sharing one.",
+	"achievement":     "Achievement unlocked: %s",
+	"mercy_offer":     "You lost %d games in a row. Want an easier game (word length %d, %d retries)? Press Y to accept, any other key to skip: ",
+	"confirm_quit":    "Do you really want to quit? Press Y to quit (or Ctrl+C again), any other key to go on: ",
+}
+
+func init() {
+	RegisterLanguage(LanguagePack{Code: "en", Name: "English",
+		Metadata: embeddedDictionaryMetadata, Messages: englishMessages})
+	RegisterLanguage(LanguagePack{Code: "es", Name: "Español",
+		Alphabet:   "abcdefghijklmnñopqrstuvwxyzáéíóúü",
+		Dictionary: builtinDictionary("es"),
+		Metadata:   builtinMetadata("es"),
+		Messages: map[string]string{
+			"new_game":        "¿Quieres jugar una partida nueva? (S/N): ",
+			"yes":             "s",
+			"invalid_yes_no":  "Respuesta no válida, escribe s o n",
+			"lengths":         "Longitudes de palabra disponibles: %s (0 para una sorpresa)",
+			"random_length":   "¡Sorpresa! La palabra tiene %d letras.",
+			"enter_length":    "Escribe la longitud de la palabra: ",
+			"enter_retries":   "Escribe el número de intentos (máximo %d):",
+			"invalid_input":   "Respuesta no válida, error: %v",
+			"no_words":        "No hay palabras de %d letras en el diccionario. ¡Inténtalo de nuevo!",
+			"invalid_retries": "Número de intentos no válido, inténtalo de nuevo",
+			"enter_char":      "Escribe una letra (letras anteriores: %s, intentos restantes %d): ",
+			"invalid_char":    "Letra no válida, escríbela de nuevo",
+			"right_char":      "¡Has acertado una letra!",
+			"wrong_char":      "Lo siento, la letra no está. Intentos restantes: %d (escribe why para ver por qué)",
+			"won":             "¡Has ganado! ¡Enhorabuena!",
+			"score":           "Puntuación: %d puntos (racha x%.1f), total de la sesión: %d",
+			"lost":            "¡Se acabaron los intentos, has perdido! La palabra era: %s",
+			"time_up":         "¡Se acabó el tiempo! Intentos restantes: %d",
+			"common_letters":  "Letras más frecuentes: %s",
+			"hint_help":       "Escribe hint (o ! en modo tecla) para ver la pista de la palabra, cuando el ordenador haya reducido su elección a palabras que la comparten.",
+			"achievement":     "Logro desbloqueado: %s",
+			"mercy_offer":     "Has perdido %d partidas seguidas. ¿Quieres una partida más fácil (longitud de palabra %d, %d intentos)? Pulsa S para aceptar, cualquier otra tecla para seguir: ",
+			"confirm_quit":    "¿Seguro que quieres salir? Pulsa S para salir (o Ctrl+C otra vez), cualquier otra tecla para seguir: ",
+		}})
+	RegisterLanguage(LanguagePack{Code: "fr", Name: "Français",
+		Alphabet:   "abcdefghijklmnopqrstuvwxyzàâæçéèêëîïôœùûüÿ",
+		Dictionary: builtinDictionary("fr"),
+		Metadata:   builtinMetadata("fr"),
+		Messages: map[string]string{
+			"new_game":        "Voulez-vous jouer une nouvelle partie ? (O/N) : ",
+			"yes":             "o",
+			"invalid_yes_no":  "Réponse invalide, tapez o ou n",
+			"lengths":         "Longueurs de mot disponibles : %s (0 pour une surprise)",
+			"random_length":   "Surprise ! Le mot a %d lettres.",
+			"enter_length":    "Entrez la longueur du mot : ",
+			"enter_retries":   "Entrez le nombre d'essais (au plus %d) :",
+			"invalid_input":   "Réponse invalide, erreur : %v",
+			"no_words":        "Il n'y a pas de mot de %d lettres dans le dictionnaire. Réessayez !",
+			"invalid_retries": "Nombre d'essais invalide, réessayez",
+			"enter_char":      "Entrez une lettre (lettres précédentes : %s, essais restants %d) : ",
+			"invalid_char":    "Lettre invalide, entrez-la à nouveau",
+			"right_char":      "Bonne lettre !",
+			"wrong_char":      "Désolé, mauvaise lettre. Essais restants : %d (tapez why pour savoir pourquoi)",
+			"won":             "Vous avez gagné ! Félicitations !",
+			"score":           "Score : %d points (série x%.1f), total de la session : %d",
+			"lost":            "Plus d'essais, vous avez perdu ! Le mot était : %s",
+			"time_up":         "Temps écoulé ! Essais restants : %d",
+			"common_letters":  "Lettres les plus fréquentes : %s",
+			"hint_help":       "Tapez hint (ou ! en mode touche) pour voir l'indice du mot, une fois que l'ordinateur a réduit son choix aux mots qui le partagent.",
+			"achievement":     "Succès débloqué : %s",
+			"mercy_offer":     "Vous avez perdu %d parties d'affilée. Voulez-vous une partie plus facile (longueur du mot %d, %d essais) ? Appuyez sur O pour accepter, sur une autre touche pour passer : ",
+			"confirm_quit":    "Voulez-vous vraiment quitter ? Appuyez sur O pour quitter (ou de nouveau sur Ctrl+C), sur une autre touche pour continuer : ",
+		}})
+	RegisterLanguage(LanguagePack{Code: "de", Name: "Deutsch",
+		Alphabet:   "abcdefghijklmnopqrstuvwxyzäöüß",
+		Dictionary: builtinDictionary("de"),
+		Metadata:   builtinMetadata("de"),
+		Messages: map[string]string{
+			"new_game":        "Möchtest du ein neues Spiel spielen? (J/N): ",
+			"yes":             "j",
+			"invalid_yes_no":  "Ungültige Eingabe, bitte j oder n eingeben",
+			"lengths":         "Spielbare Wortlängen: %s (0 für eine Überraschung)",
+			"random_length":   "Überraschung! Das Wort hat %d Buchstaben.",
+			"enter_length":    "Gib die Länge des Wortes ein: ",
+			"enter_retries":   "Gib die Anzahl der Versuche ein (höchstens %d):",
+			"invalid_input":   "Ungültige Eingabe, Fehler: %v",
+			"no_words":        "Es gibt keine Wörter mit %d Buchstaben im Wörterbuch. Versuch es noch einmal!",
+			"invalid_retries": "Ungültige Anzahl von Versuchen, bitte noch einmal",
+			"enter_char":      "Gib einen Buchstaben ein (bisherige Buchstaben: %s, verbleibende Versuche %d): ",
+			"invalid_char":    "Ungültiger Buchstabe, bitte noch einmal eingeben",
+			"right_char":      "Richtiger Buchstabe!",
+			"wrong_char":      "Leider falsch. Verbleibende Versuche: %d (gib why ein, um zu sehen warum)",
+			"won":             "Du hast gewonnen! Glückwunsch!",
+			"score":           "Punkte: %d (Serie x%.1f), Summe der Sitzung: %d",
+			"lost":            "Keine Versuche mehr, du hast verloren! Das Wort war: %s",
+			"time_up":         "Die Zeit ist um! Verbleibende Versuche: %d",
+			"common_letters":  "Häufigste Buchstaben: %s",
+			"hint_help":       "Gib hint ein (oder ! im Tastenmodus), um den Hinweis zum Wort zu sehen, sobald der Computer seine Wahl auf Wörter mit demselben Hinweis eingegrenzt hat.",
+			"achievement":     "Erfolg freigeschaltet: %s",
+			"mercy_offer":     "Du hast %d Spiele in Folge verloren. Möchtest du ein leichteres Spiel (Wortlänge %d, %d Versuche)? Drücke J zum Annehmen, eine andere Taste zum Überspringen: ",
+			"confirm_quit":    "Möchtest du wirklich aufhören? Drücke J zum Beenden (oder noch einmal Strg+C), eine andere Taste zum Weiterspielen: ",
+		}})
+	RegisterLanguage(LanguagePack{Code: "hi", Name: "हिन्दी",
+		Alphabet: "अआइईउऊऋएऐओऔकखगघङचछजझञटठडढणतथदधनपफबभमयरलवशषसह" +
+			"ािीुूृेैोौॉंःँ़्ड़ढ़",
+		Dictionary: builtinDictionary("hi"),
+		Metadata:   builtinMetadata("hi"),
+		Messages: map[string]string{
+			"new_game":        "क्या आप नया खेल खेलना चाहते हैं? (Y/N): ",
+			"invalid_yes_no":  "गलत जवाब, कृपया y या n लिखें",
+			"lengths":         "खेलने योग्य शब्द लंबाइयाँ: %s (सरप्राइज़ के लिए 0)",
+			"random_length":   "सरप्राइज़! शब्द में %d अक्षर हैं।",
+			"enter_length":    "शब्द की लंबाई लिखें: ",
+			"enter_retries":   "कोशिशों की संख्या लिखें (अधिकतम %d):",
+			"invalid_input":   "गलत जवाब, त्रुटि: %v",
+			"no_words":        "शब्दकोश में %d अक्षरों का कोई शब्द नहीं है। फिर से कोशिश करें!",
+			"invalid_retries": "कोशिशों की संख्या गलत है, फिर से कोशिश करें",
+			"enter_char":      "एक अक्षर लिखें (पिछले अक्षर: %s, बची कोशिशें %d): ",
+			"invalid_char":    "गलत अक्षर, कृपया फिर से लिखें",
+			"right_char":      "आपने सही अक्षर चुना!",
+			"wrong_char":      "माफ़ कीजिए, गलत अक्षर। बची कोशिशें: %d (कारण देखने के लिए why लिखें)",
+			"won":             "आप जीत गए! बधाई हो!",
+			"score":           "स्कोर: %d अंक (लगातार जीत x%.1f), सत्र का कुल: %d",
+			"lost":            "कोशिशें खत्म, आप हार गए! शब्द था: %s",
+			"time_up":         "समय खत्म! बची कोशिशें: %d",
+			"common_letters":  "सबसे आम अक्षर: %s",
+			"hint_help":       "शब्द का संकेत देखने के लिए hint लिखें (या की मोड में !), जब कंप्यूटर अपनी पसंद उन शब्दों तक सीमित कर ले जिनका संकेत एक जैसा है।",
+			"achievement":     "उपलब्धि मिली: %s",
+			"mercy_offer":     "आप लगातार %d खेल हार गए। क्या आप आसान खेल चाहते हैं (शब्द लंबाई %d, %d कोशिशें)? मानने के लिए Y दबाएँ, छोड़ने के लिए कोई और कुंजी: ",
+			"confirm_quit":    "क्या आप सच में खेल छोड़ना चाहते हैं? छोड़ने के लिए Y दबाएँ (या फिर से Ctrl+C), जारी रखने के लिए कोई और कुंजी: ",
+		}})
+}
+
+// Method to get the metadata of the word list of a built in language.
+func builtinMetadata(code string) DictionaryMetadata {
+	metadata := builtinLanguageMetadata
+	metadata.Name = "WordGuess " + code
+	return metadata
+}
+
+// Method to read the word list of a built in language.
+func builtinDictionary(code string) []byte {
+	data, err := languageDictionaries.ReadFile("lang/" + code + ".txt")
+	if err != nil {
+		// The word lists are embedded at build time, this can not happen.
+		panic(err)
+	}
+	return data
+}
+
+// Method to register a language pack, which can then be selected with --lang.
+// A pack registered with the code of another one replaces it, e.g. to use
+// another English word list. A pack without a dictionary uses the embedded
+// English word list.
+func RegisterLanguage(pack LanguagePack) {
+	languagesMu.Lock()
+	defer languagesMu.Unlock()
+	languages[pack.Code] = pack
+}
+
+// Method to get a registered language pack by its code.
+func lookupLanguage(code string) (LanguagePack, bool) {
+	languagesMu.Lock()
+	defer languagesMu.Unlock()
+	pack, ok := languages[code]
+	return pack, ok
+}
+
+// Method to get the codes of the registered languages, in sorted order.
+func languageCodes() []string {
+	languagesMu.Lock()
+	defer languagesMu.Unlock()
+	var codes []string
+	for code := range languages {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Method to get the language selected with --lang. English is used if it is
+// not registered, which setupLanguage reports at startup.
+func currentLanguage() LanguagePack {
+	if pack, ok := lookupLanguage(*languageCode); ok {
+		return pack
+	}
+	pack, _ := lookupLanguage("en")
+	return pack
+}
+
+// Method to get the letters of the dictionary: --alphabet if set, otherwise
+// the letters of the language.
+func dictionaryAlphabet() string {
+	if *alphabetLetters != "" {
+		return *alphabetLetters
+	}
+	return currentLanguage().Alphabet
+}
+
+// Method to get a message shown to the player in the language of the game,
+// formatted with the arguments if any.
+func tr(key string, args ...interface{}) string {
+	message, ok := currentLanguage().Messages[key]
+	if !ok {
+		message = englishMessages[key]
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Method to check if an answer is yes, in English or in the language of the
+// game.
+func isYes(char rune) bool {
+	answer := strings.ToLower(string(char))
+	return answer == englishMessages["yes"] || answer == tr("yes")
+}
+
+// Method to load the language packs of a directory, one sub directory per
+// language, see --language_packs.
+func loadLanguagePacks(dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pack := LanguagePack{Code: entry.Name()}
+		path := filepath.Join(dir, entry.Name())
+		data, err := ioutil.ReadFile(filepath.Join(path, "pack.json"))
+		if err == nil {
+			if err := json.Unmarshal(data, &pack); err != nil {
+				return fmt.Errorf("invalid language pack %s: %v", path, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		if pack.Name == "" {
+			pack.Name = pack.Code
+		}
+		if pack.Dictionary, err = readDictionary(filepath.Join(path, "dictionary.txt")); err != nil {
+			return fmt.Errorf("invalid language pack %s: %v", path, err)
+		}
+		RegisterLanguage(pack)
+	}
+	return nil
+}
+
+// Method to load the language packs and check the language given by the
+// flags. Returns an error if they are invalid.
+func setupLanguage() error {
+	if *languagePacks != "" {
+		if err := loadLanguagePacks(*languagePacks); err != nil {
+			return configError("unable to load the language packs: %w", err)
+		}
+	}
+	if _, ok := lookupLanguage(*languageCode); !ok {
+		return configError("unknown language %s, expected one of %s", *languageCode,
+			strings.Join(languageCodes(), ", "))
+	}
+	return nil
+}
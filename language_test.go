@@ -0,0 +1,154 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type LanguageTestSuite struct {
+	suite.Suite
+}
+
+func (s *LanguageTestSuite) TearDownTest() {
+	*languageCode = "en"
+	*alphabetLetters = ""
+}
+
+func (s *LanguageTestSuite) TestMessages() {
+	assert.Equal(s.T(), "You won! Congratulations!!!", tr("won"))
+	assert.Equal(s.T(), "Time is up! Remaining tries: 2", tr("time_up", 2))
+
+	*languageCode = "es"
+	assert.Equal(s.T(), "¡Has ganado! ¡Enhorabuena!", tr("won"))
+	assert.Equal(s.T(), "¡Se acabó el tiempo! Intentos restantes: 2", tr("time_up", 2))
+	assert.Equal(s.T(), "Logro desbloqueado: Gana 10 partidas", tr("achievement", "Gana 10 partidas"))
+
+	// The messages missing from a pack are shown in English.
+	RegisterLanguage(LanguagePack{Code: "xx", Name: "Test",
+		Messages: map[string]string{"won": "Won"}})
+	*languageCode = "xx"
+	assert.Equal(s.T(), "Won", tr("won"))
+	assert.Equal(s.T(), "Time is up! Remaining tries: 2", tr("time_up", 2))
+}
+
+func (s *LanguageTestSuite) TestIsYes() {
+	assert.Equal(s.T(), true, isYes('Y'))
+	assert.Equal(s.T(), false, isYes('s'))
+
+	*languageCode = "es"
+	assert.Equal(s.T(), true, isYes('S'))
+	assert.Equal(s.T(), true, isYes('y'))
+	assert.Equal(s.T(), false, isYes('n'))
+}
+
+func (s *LanguageTestSuite) TestAlphabet() {
+	assert.Equal(s.T(), "", dictionaryAlphabet())
+
+	*languageCode = "de"
+	assert.Equal(s.T(), "abcdefghijklmnopqrstuvwxyzäöüß", dictionaryAlphabet())
+
+	// --alphabet takes precedence over the language.
+	*alphabetLetters = "abc"
+	assert.Equal(s.T(), "abc", dictionaryAlphabet())
+}
+
+func (s *LanguageTestSuite) TestBuiltinDictionaries() {
+	for _, code := range []string{"es", "fr", "de", "hi"} {
+		*languageCode = code
+		data, err := readDictionary("")
+		assert.Nil(s.T(), err)
+		alphabet := NewAlphabet(dictionaryAlphabet())
+		for _, word := range strings.Fields(string(data)) {
+			assert.True(s.T(), alphabet.ValidWord(word), code+": "+word)
+		}
+		assert.Equal(s.T(), "embedded "+currentLanguage().Name, dictionaryName(""))
+	}
+
+	*languageCode = "en"
+	data, err := readDictionary("")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), embeddedDictionary, data)
+	assert.Equal(s.T(), "embedded", dictionaryName(""))
+}
+
+func (s *LanguageTestSuite) TestBuiltinMetadata() {
+	*strictDictionary = true
+	defer func() {
+		*strictDictionary = false
+		InitGame(nil)
+	}()
+	// Every built in word list can be distributed in strict mode.
+	for _, code := range []string{"en", "es", "fr", "de", "hi", "en-kids"} {
+		*languageCode = code
+		s.Require().NoError(InitGame(nil), code)
+		metadata := currentDictionary().Metadata()
+		assert.Empty(s.T(), metadata.MissingFields(), code)
+		assert.NotEmpty(s.T(), metadata.Name, code)
+	}
+	*languageCode = "en"
+	s.Require().NoError(InitGame(nil))
+	assert.Equal(s.T(), embeddedDictionaryMetadata, currentDictionary().Metadata())
+}
+
+func (s *LanguageTestSuite) TestHindiVowelSigns() {
+	*languageCode = "hi"
+	InitGame([]string{"किताब"})
+	defer InitGame(nil)
+	game, err := NewGame(5, 3)
+	assert.Nil(s.T(), err)
+
+	// The vowel signs are typed like any letter.
+	char, ok := parseChar("ि")
+	assert.Equal(s.T(), true, ok)
+	isValid, err := game.CheckUserInput(char)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), true, isValid)
+	assert.Equal(s.T(), "_ि___", DefaultPatternFormat.Format(game.CurrentDisplayedWord))
+
+	// Latin letters are not part of the Hindi alphabet.
+	_, err = game.CheckUserInput('a')
+	assert.NotNil(s.T(), err)
+}
+
+func (s *LanguageTestSuite) TestLoadLanguagePacks() {
+	dir, err := ioutil.TempDir("", "language_packs")
+	assert.Nil(s.T(), err)
+	defer os.RemoveAll(dir)
+	assert.Nil(s.T(), os.MkdirAll(filepath.Join(dir, "it"), 0755))
+	assert.Nil(s.T(), ioutil.WriteFile(filepath.Join(dir, "it", "pack.json"),
+		[]byte(`{"name": "Italiano", "alphabet": "abcdefghilmnopqrstuvzàèéìòù",
+		"messages": {"won": "Hai vinto!"}, "metadata": {"license": "CC0-1.0"}}`), 0644))
+	assert.Nil(s.T(), ioutil.WriteFile(filepath.Join(dir, "it", "dictionary.txt"),
+		[]byte("casa\ncane\n"), 0644))
+	// A pack only needs a word list.
+	assert.Nil(s.T(), os.MkdirAll(filepath.Join(dir, "la"), 0755))
+	assert.Nil(s.T(), ioutil.WriteFile(filepath.Join(dir, "la", "dictionary.txt"),
+		[]byte("rosa\n"), 0644))
+
+	assert.Nil(s.T(), loadLanguagePacks(dir))
+	*languageCode = "it"
+	assert.Equal(s.T(), "Hai vinto!", tr("won"))
+	assert.Equal(s.T(), "abcdefghilmnopqrstuvzàèéìòù", dictionaryAlphabet())
+	data, err := readDictionary("")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "casa\ncane\n", string(data))
+	assert.Equal(s.T(), "embedded Italiano", dictionaryName(""))
+	assert.Equal(s.T(), "CC0-1.0", embeddedMetadata().License)
+
+	*languageCode = "la"
+	assert.Equal(s.T(), "embedded la", dictionaryName(""))
+	assert.Equal(s.T(), "You won! Congratulations!!!", tr("won"))
+
+	// A language without a word list can not be played.
+	assert.Nil(s.T(), os.MkdirAll(filepath.Join(dir, "xx"), 0755))
+	assert.NotNil(s.T(), loadLanguagePacks(dir))
+}
+
+func TestLanguageTestSuite(t *testing.T) {
+	suite.Run(t, new(LanguageTestSuite))
+}
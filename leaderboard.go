@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	leaderboardFile = flag.String("leaderboard_file", "",
+		"Absolute path of the file where the finished games are recorded for the "+
+			"leaderboard. No leaderboard is kept if empty.")
+	playerName = flag.String("player", os.Getenv("USER"),
+		"Name of the player in the leaderboard, and of their profile with --profiles.")
+	leaderboardSize = flag.Int("leaderboard_size", 10,
+		"Number of players shown by the \"leaderboard\" subcommand.")
+)
+
+const (
+	// Max number of players returned by the /leaderboard endpoint.
+	maxLeaderboardSize = 100
+	// Points of a won game, and of a won challenge game.
+	winPoints          = 1
+	challengeWinPoints = 2
+)
+
+// Leaderboard kept in a local file, with one JSON entry per line. Entries are
+// only ever appended, so a crash can at most lose the last entry.
+type leaderboardStore struct {
+	// Serializes the writes of this process.
+	mu   sync.Mutex
+	path string
+}
+
+func newLeaderboardStore(path string) *leaderboardStore {
+	return &leaderboardStore{path: path}
+}
+
+// Method to record a finished game.
+func (l *leaderboardStore) add(entry api.LeaderboardEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Method to read all the recorded games. A missing file is an empty
+// leaderboard. Lines which can not be parsed (e.g. the last line cut by a
+// crash) are skipped.
+func (l *leaderboardStore) entries() ([]api.LeaderboardEntry, error) {
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []api.LeaderboardEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry api.LeaderboardEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			defaultLogger.Errorf("Skipping invalid leaderboard entry %q, error %v",
+				scanner.Text(), err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Method to get the top players. Players are ranked by points, then by wins,
+// then by win rate, then by average retries used (fewer is better), then by
+// name.
+func (l *leaderboardStore) top(limit int) (api.Leaderboard, error) {
+	entries, err := l.entries()
+	if err != nil {
+		return api.Leaderboard{}, err
+	}
+	return rankPlayers(entries, limit), nil
+}
+
+// Method to rank the players of the given games, keeping the best limit ones.
+func rankPlayers(entries []api.LeaderboardEntry, limit int) api.Leaderboard {
+	byPlayer := make(map[string]*api.PlayerStanding)
+	retries := make(map[string]int)
+	for _, entry := range entries {
+		standing, ok := byPlayer[entry.Player]
+		if !ok {
+			standing = &api.PlayerStanding{Player: entry.Player}
+			byPlayer[entry.Player] = standing
+		}
+		standing.Games++
+		retries[entry.Player] += entry.RetriesUsed
+		if entry.Forfeited {
+			standing.Forfeits++
+		}
+		if entry.Won {
+			standing.Points += gamePoints(entry)
+			standing.Wins++
+			if standing.FastestWinMillis == 0 || entry.DurationMillis < standing.FastestWinMillis {
+				standing.FastestWinMillis = entry.DurationMillis
+			}
+		}
+	}
+	board := api.Leaderboard{Players: []api.PlayerStanding{}}
+	for player, standing := range byPlayer {
+		standing.WinRate = float64(standing.Wins) / float64(standing.Games)
+		standing.AverageRetriesUsed = float64(retries[player]) / float64(standing.Games)
+		board.Players = append(board.Players, *standing)
+	}
+	sort.Slice(board.Players, func(i, j int) bool {
+		a, b := board.Players[i], board.Players[j]
+		switch {
+		case a.Points != b.Points:
+			return a.Points > b.Points
+		case a.Wins != b.Wins:
+			return a.Wins > b.Wins
+		case a.WinRate != b.WinRate:
+			return a.WinRate > b.WinRate
+		case a.AverageRetriesUsed != b.AverageRetriesUsed:
+			return a.AverageRetriesUsed < b.AverageRetriesUsed
+		}
+		return a.Player < b.Player
+	})
+	if limit >= 0 && len(board.Players) > limit {
+		board.Players = board.Players[:limit]
+	}
+	return board
+}
+
+// Method to get the points of a won game, which are doubled for a challenge
+// game while the feature is enabled for the player.
+func gamePoints(entry api.LeaderboardEntry) int {
+	if entry.Challenge && featureEnabled(featureChallengePoints, entry.Player) {
+		return challengeWinPoints
+	}
+	return winPoints
+}
+
+// Method to build the leaderboard entry of a finished game.
+func leaderboardEntry(player string, game *Game, duration time.Duration,
+	now time.Time) api.LeaderboardEntry {
+	return api.LeaderboardEntry{
+		Player:         player,
+		WordLength:     game.ExpectedLength,
+		RetriesUsed:    game.AllowedRetries - game.CurrentRetries,
+		Won:            game.State == Won,
+		Forfeited:      game.State == Forfeited,
+		DurationMillis: duration.Milliseconds(),
+		Finished:       now,
+	}
+}
+
+// Method to format the leaderboard for the terminal.
+func formatLeaderboard(board api.Leaderboard) string {
+	if len(board.Players) == 0 {
+		return "No games recorded yet.\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-4s %-20s %6s %6s %6s %9s %8s %8s %12s\n", "#", "Player", "Points",
+		"Games", "Wins", "Win rate", "Retries", "Forfeits", "Fastest win")
+	for i, p := range board.Players {
+		fastest := "-"
+		if p.FastestWinMillis > 0 {
+			fastest = (time.Duration(p.FastestWinMillis) * time.Millisecond).
+				Round(100 * time.Millisecond).String()
+		}
+		fmt.Fprintf(&b, "%-4d %-20s %6d %6d %6d %8.0f%% %8.1f %8d %12s\n", i+1, p.Player,
+			p.Points, p.Games, p.Wins, p.WinRate*100, p.AverageRetriesUsed, p.Forfeits, fastest)
+	}
+	return b.String()
+}
+
+// Driver method for the "leaderboard" subcommand, which shows the top players.
+func StartLeaderboard() error {
+	if *leaderboardFile == "" {
+		return configError("please pass the leaderboard using --leaderboard_file")
+	}
+	board, err := newLeaderboardStore(*leaderboardFile).top(*leaderboardSize)
+	if err != nil {
+		return fmt.Errorf("unable to read the leaderboard: %w", err)
+	}
+	fmt.Print(formatLeaderboard(board))
+	return nil
+}
+
+// Handler of GET /leaderboard, which returns the top players as an
+// api.Leaderboard. The number of players is set with ?limit=, 10 by default.
+func (s *gameServer) handleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	limit := 10
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLeaderboardSize {
+			writeError(w, api.NewError(api.CodeInvalidRequest,
+				"limit must be between 1 and %d", maxLeaderboardSize).WithDetail("limit", value))
+			return
+		}
+	}
+	if s.leaderboard == nil {
+		writeJSON(w, http.StatusOK, api.Leaderboard{Players: []api.PlayerStanding{}})
+		return
+	}
+	board, err := s.leaderboard.top(limit)
+	if err != nil {
+		defaultLogger.Errorf("Unable to read the leaderboard, error %v", err)
+		writeError(w, api.NewError(api.CodeInternal, "unable to read the leaderboard"))
+		return
+	}
+	writeJSON(w, http.StatusOK, board)
+}
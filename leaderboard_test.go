@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type LeaderboardTestSuite struct {
+	suite.Suite
+	store *leaderboardStore
+}
+
+func (s *LeaderboardTestSuite) SetupTest() {
+	s.store = newLeaderboardStore(filepath.Join(s.T().TempDir(), "leaderboard.jsonl"))
+}
+
+func (s *LeaderboardTestSuite) add(player string, retriesUsed int, won bool, millis int64) {
+	assert.Nil(s.T(), s.store.add(api.LeaderboardEntry{
+		Player:         player,
+		WordLength:     4,
+		RetriesUsed:    retriesUsed,
+		Won:            won,
+		DurationMillis: millis,
+	}))
+}
+
+func (s *LeaderboardTestSuite) TestRanking() {
+	board, err := s.store.top(10)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, len(board.Players))
+
+	s.add("alice", 1, true, 9000)
+	s.add("alice", 4, false, 3000)
+	s.add("bob", 2, true, 5000)
+	s.add("carol", 0, true, 4000)
+	s.add("alice", 1, true, 7000)
+	board, err = s.store.top(10)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []api.PlayerStanding{
+		{Player: "alice", Points: 2, Games: 3, Wins: 2, WinRate: 2.0 / 3, AverageRetriesUsed: 2,
+			FastestWinMillis: 7000},
+		// Same wins and win rate, fewer retries first.
+		{Player: "carol", Points: 1, Games: 1, Wins: 1, WinRate: 1, AverageRetriesUsed: 0,
+			FastestWinMillis: 4000},
+		{Player: "bob", Points: 1, Games: 1, Wins: 1, WinRate: 1, AverageRetriesUsed: 2,
+			FastestWinMillis: 5000},
+	}, board.Players)
+
+	board, err = s.store.top(1)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, len(board.Players))
+	assert.Contains(s.T(), formatLeaderboard(board), "alice")
+}
+
+func (s *LeaderboardTestSuite) TestSkipsInvalidLines() {
+	s.add("alice", 1, true, 9000)
+	f, err := os.OpenFile(s.store.path, os.O_WRONLY|os.O_APPEND, 0644)
+	assert.Nil(s.T(), err)
+	f.WriteString(`{"player": "bo`)
+	f.Close()
+	entries, err := s.store.entries()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, len(entries))
+}
+
+func (s *LeaderboardTestSuite) TestGameEntry() {
+	InitGame([]string{"last", "fast"})
+	game, err := NewGame(4, 0)
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('z')
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(s.T(), api.LeaderboardEntry{
+		Player:         "alice",
+		WordLength:     4,
+		RetriesUsed:    1,
+		DurationMillis: 1500,
+		Finished:       now,
+	}, leaderboardEntry("alice", game, 1500*time.Millisecond, now))
+}
+
+func (s *LeaderboardTestSuite) TestEndpoint() {
+	s.add("alice", 1, true, 9000)
+	server := newGameServer()
+	server.leaderboard = s.store
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	resp, err := http.Get(httpServer.URL + "/leaderboard?limit=5")
+	assert.Nil(s.T(), err)
+	var board api.Leaderboard
+	json.NewDecoder(resp.Body).Decode(&board)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "alice", board.Players[0].Player)
+
+	resp, err = http.Get(httpServer.URL + "/leaderboard?limit=0")
+	assert.Nil(s.T(), err)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestLeaderboardTestSuite(t *testing.T) {
+	suite.Run(t, new(LeaderboardTestSuite))
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"github.com/hackeracc/WordGuess/engine"
+	"math/bits"
+)
+
+// Set of words of a wordIndex, where bit i is set for the i-th word of the
+// index.
+type bitset []uint64
+
+// Method to create an empty set for n words.
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+// Method to create a set of all the n words.
+func fullBitset(n int) bitset {
+	b := newBitset(n)
+	for i := range b {
+		b[i] = ^uint64(0)
+	}
+	if n%64 != 0 {
+		b[len(b)-1] = 1<<uint(n%64) - 1
+	}
+	return b
+}
+
+// Method to add a word to the set.
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << uint(i%64)
+}
+
+// Method to check if a word is in the set.
+func (b bitset) has(i int) bool {
+	return b[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Method to count the words in the set.
+func (b bitset) count() int {
+	n := 0
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Words of a wordIndex having a letter, see wordIndex.positions.
+type letterPositions struct {
+	// Words having the letter at any position.
+	words bitset
+	// Per position, the words having the letter at that position.
+	at []bitset
+}
+
+// Method to build the sets of the words having every letter, for the
+// characters of the words of an index.
+func newLetterPositions(length int, chars [][]rune) map[rune]*letterPositions {
+	positions := make(map[rune]*letterPositions)
+	for i, word := range chars {
+		for pos, char := range word {
+			lp, ok := positions[char]
+			if !ok {
+				lp = &letterPositions{words: newBitset(len(chars)), at: make([]bitset, length)}
+				for p := range lp.at {
+					lp.at[p] = newBitset(len(chars))
+				}
+				positions[char] = lp
+			}
+			lp.words.set(i)
+			lp.at[pos].set(i)
+		}
+	}
+	return positions
+}
+
+// Method to get the words of the index in a set, in sorted order.
+func (idx *wordIndex) wordsOf(set bitset) []string {
+	words := make([]string, 0, set.count())
+	for i, w := range set {
+		for w != 0 {
+			words = append(words, idx.words[i*64+bits.TrailingZeros64(w)])
+			w &= w - 1
+		}
+	}
+	return words
+}
+
+// Method to get the decision of the vindictive engine, like getMaxSet, for
+// candidates given as a set of the words of the index. The words without the
+// character are counted with bit operations, and the positions of the
+// character in the other words are read from the sets of the positions, so no
+// word is scanned. The set is split in shards counted in parallel when it is
+// large, see partitionShards. Use partition to get the words of the kept
+// partition.
+func (idx *wordIndex) maxDecision(logger Logger, candidates bitset, currWord []rune,
+	char rune) Decision {
+	total := candidates.count()
+	var groups map[uint64]int
+	lp := idx.positions[char]
+	if lp == nil {
+		groups = map[uint64]int{0: total}
+	} else {
+		// The positions already showing the character are left out, so
+		// every mask maps to a different pattern, see getMaxSetMasks.
+		var hidden []int
+		for pos, c := range currWord {
+			if c != char {
+				hidden = append(hidden, pos)
+			}
+		}
+		// The blocks of 64 words of the set are split in shards.
+		shards := partitionShards(total)
+		partials := make([]map[uint64]int, shards)
+		runShards(shards, func(shard int) {
+			start, end := shardRange(len(candidates), shards, shard)
+			groups := make(map[uint64]int)
+			without := 0
+			for i := start; i < end; i++ {
+				without += bits.OnesCount64(candidates[i] &^ lp.words[i])
+				with := candidates[i] & lp.words[i]
+				for with != 0 {
+					word := i*64 + bits.TrailingZeros64(with)
+					var mask uint64
+					for _, pos := range hidden {
+						if lp.at[pos].has(word) {
+							mask |= 1 << uint(pos)
+						}
+					}
+					groups[mask]++
+					with &= with - 1
+				}
+			}
+			if without > 0 {
+				groups[0] += without
+			}
+			partials[shard] = groups
+		})
+		groups = mergeGroups(partials)
+	}
+	var maxSet string
+	maxSetLength := 0
+	sizes := make(map[string]int, len(groups))
+	for mask, size := range groups {
+		if size == 0 {
+			continue
+		}
+		possibility := maskPattern(currWord, mask, char)
+		sizes[possibility] = size
+		if engine.Prefer(possibility, size, maxSet, maxSetLength) {
+			maxSet, maxSetLength = possibility, size
+		}
+	}
+	logger.Infof("Max set %v with %d words", maxSet, maxSetLength)
+	return newDecision(char, currWord, total, sizes)
+}
+
+// Method to get the candidates showing the pattern once the character is
+// guessed, using only bit operations on the sets of the positions.
+func (idx *wordIndex) partition(candidates bitset, currWord []rune, char rune,
+	pattern string) bitset {
+	kept := make(bitset, len(candidates))
+	copy(kept, candidates)
+	lp := idx.positions[char]
+	if lp == nil {
+		if pattern != string(currWord) {
+			return newBitset(len(idx.words))
+		}
+		return kept
+	}
+	for pos, c := range []rune(pattern) {
+		if pos >= len(currWord) || currWord[pos] == char {
+			continue
+		}
+		if c == char {
+			for i := range kept {
+				kept[i] &= lp.at[pos][i]
+			}
+		} else {
+			for i := range kept {
+				kept[i] &^= lp.at[pos][i]
+			}
+		}
+	}
+	return kept
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type LetterIndexTestSuite struct {
+	suite.Suite
+}
+
+func (s *LetterIndexTestSuite) TearDownTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *LetterIndexTestSuite) TestBitset() {
+	b := fullBitset(70)
+	assert.Equal(s.T(), 70, b.count())
+	assert.True(s.T(), b.has(69))
+	b = newBitset(70)
+	b.set(3)
+	b.set(65)
+	assert.Equal(s.T(), 2, b.count())
+	assert.True(s.T(), b.has(65))
+	assert.False(s.T(), b.has(64))
+}
+
+func (s *LetterIndexTestSuite) TestPositions() {
+	idx := newWordIndex(4, []string{"last", "fast", "bets", "code"})
+	// The words are sorted: bets, code, fast, last.
+	assert.Equal(s.T(), 2, idx.positions['a'].words.count())
+	assert.True(s.T(), idx.positions['e'].at[1].has(0))
+	assert.True(s.T(), idx.positions['e'].at[3].has(1))
+	assert.Nil(s.T(), idx.positions['z'])
+}
+
+func (s *LetterIndexTestSuite) TestSameAsStrings() {
+	words := generateWordsOfLength(2000, 5, 1)
+	idx := newWordIndex(5, words)
+	all := fullBitset(len(idx.words))
+	patterns := [][]rune{rawPattern("_____"), rawPattern("__a__"), rawPattern("e___e")}
+	for _, pattern := range patterns {
+		for char := 'a'; char <= 'z'; char++ {
+			d := idx.maxDecision(NopLogger{}, all, pattern, char)
+			expectedSet, expected := getMaxSetStrings(NopLogger{}, idx.words, pattern, char)
+			assert.Equal(s.T(), expected, d)
+			assert.Equal(s.T(), expectedSet, idx.wordsOf(idx.partition(all, pattern, char, d.Pattern)))
+			// Any other partition can be kept.
+			last := d.Partitions[len(d.Partitions)-1]
+			assert.Equal(s.T(), last.Size,
+				idx.partition(all, pattern, char, last.Pattern).count())
+		}
+	}
+}
+
+func (s *LetterIndexTestSuite) TestGame() {
+	words := generateWordsOfLength(5000, 6, 2)
+	InitGame(words)
+	indexed, err := NewGame(6, 10)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), indexed.bits)
+	scanned, _ := NewGame(6, 10)
+	scanned.bits = nil
+	for _, char := range "etaoinsh" {
+		accepted, _ := indexed.CheckUserInput(char)
+		expected, _ := scanned.CheckUserInput(char)
+		assert.Equal(s.T(), expected, accepted)
+		assert.Equal(s.T(), scanned.CurrentSetOfWords, indexed.CurrentSetOfWords)
+		assert.Equal(s.T(), scanned.CurrentDisplayedWord, indexed.CurrentDisplayedWord)
+	}
+}
+
+func (s *LetterIndexTestSuite) TestPhrases() {
+	defer func() { *phraseMode = false }()
+	*phraseMode = true
+	InitGame([]string{"ab cd", "abc d", "ef gh"})
+	game, err := NewGame(5, 3)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"ab cd", "ef gh"}, game.CurrentSetOfWords)
+	assert.Equal(s.T(), 2, game.bits.count())
+}
+
+func TestLetterIndexTestSuite(t *testing.T) {
+	suite.Run(t, new(LetterIndexTestSuite))
+}
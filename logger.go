@@ -0,0 +1,50 @@
+package main
+
+import (
+	"log"
+)
+
+// Interface used by the engine and the dictionary loader for logging.
+// Embedders can plug in their own implementation to control the destination
+// and the verbosity of the logs. By default nothing is logged.
+type Logger interface {
+	// Verbose logs, mainly useful for debugging the engine decisions.
+	Infof(format string, args ...interface{})
+	// Logs for unexpected scenarios like invalid dictionary words.
+	Errorf(format string, args ...interface{})
+}
+
+// Logger used by the dictionary loader and assigned to every new game.
+var defaultLogger Logger = NopLogger{}
+
+// Logger which discards all the logs.
+type NopLogger struct{}
+
+func (NopLogger) Infof(format string, args ...interface{})  {}
+func (NopLogger) Errorf(format string, args ...interface{}) {}
+
+// Logger which writes to a standard library logger.
+// Info logs are written only if Verbose is set.
+type StdLogger struct {
+	Logger  *log.Logger
+	Verbose bool
+}
+
+func (l StdLogger) Infof(format string, args ...interface{}) {
+	if l.Verbose {
+		l.Logger.Printf("INFO: "+format, args...)
+	}
+}
+
+func (l StdLogger) Errorf(format string, args ...interface{}) {
+	l.Logger.Printf("ERROR: "+format, args...)
+}
+
+// Method to set the logger used by the dictionary loader and by all the games
+// created after this call. Passing nil disables logging.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = NopLogger{}
+	}
+	defaultLogger = l
+}
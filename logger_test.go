@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+// Logger which keeps all the logs in memory.
+type recordingLogger struct {
+	infos  []string
+	errors []string
+}
+
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.infos = append(l.infos, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Errorf(format string, args ...interface{}) {
+	l.errors = append(l.errors, fmt.Sprintf(format, args...))
+}
+
+type LoggerTestSuite struct {
+	suite.Suite
+	logger *recordingLogger
+}
+
+func (s *LoggerTestSuite) SetupTest() {
+	s.logger = &recordingLogger{}
+	SetLogger(s.logger)
+}
+
+func (s *LoggerTestSuite) TearDownTest() {
+	SetLogger(nil)
+}
+
+func (s *LoggerTestSuite) TestDictionaryLoaderLogs() {
+	InitGame([]string{"last", "l4st"})
+	assert.Equal(s.T(), 1, len(s.logger.errors))
+}
+
+func (s *LoggerTestSuite) TestGameLogs() {
+	InitGame([]string{"last", "fast"})
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.logger, game.Logger)
+
+	// Logs of a game can be redirected independently.
+	gameLogger := &recordingLogger{}
+	game.Logger = gameLogger
+	_, err = game.CheckUserInput('a')
+	assert.Nil(s.T(), err)
+	assert.NotEqual(s.T(), 0, len(gameLogger.infos))
+	assert.Equal(s.T(), 0, len(s.logger.infos))
+}
+
+func TestLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggerTestSuite))
+}
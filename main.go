@@ -1,101 +1,415 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"math/rand"
+	"log"
+	"os"
+	"strings"
+	"time"
 	"unicode"
 )
 
 var (
 	maxAllowedRetries = flag.Int("max_allowed_retries", 10,
 		"Max number of allowed retries.")
+	logFile = flag.String("log_file", "",
+		"Path of the file to write the logs to. Nothing is logged if empty.")
+	verbose = flag.Bool("verbose", false,
+		"Also log the verbose engine logs (only used with --log_file).")
+	guessTimeout = flag.Duration("guess_timeout", 0,
+		"Time allowed for every guess, e.g. 10s. A retry is consumed every time "+
+			"the time runs out. There is no time limit if zero.")
+	showFrequencies = flag.Bool("show_frequencies", false,
+		"Show the most common letters among the remaining words before every "+
+			"guess. Always shown in the easier games offered after a losing streak.")
 )
 
-// Driver method to start the hangman game.
-func StartHangman() {
-	// Initialize the game.
-	InitGame(nil)
+const (
+	// Number of letters shown in the letter frequency hint.
+	frequencyHintLetters = 5
+)
+
+// Method to set up the engine logger based on the flags. Returns an error if
+// the log file can not be opened.
+func setupLogging() error {
+	if *logFile == "" {
+		return nil
+	}
+	f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return configError("unable to open log file %s: %w", *logFile, err)
+	}
+	SetLogger(StdLogger{
+		Logger:  log.New(f, "", log.LstdFlags),
+		Verbose: *verbose,
+	})
+	return nil
+}
+
+// Method to read the configuration of a new game from the user.
+// A length of 0 asks for a random length, see NewGameRandomLength.
+// Returns false if the input was not valid, and an error if the player stopped
+// playing, see waitInput.
+func readGameConfig() (int, int, bool, error) {
+	fmt.Println(tr("lengths", formatLengths(availableLengths())))
+	fmt.Println(tr("enter_length"))
+	expectedLen, err := readInt()
+	if isInputEnd(err) {
+		return 0, 0, false, err
+	}
+	if err != nil {
+		fmt.Println(tr("invalid_input", err))
+		return 0, 0, false, nil
+	}
+	// Get number of retries.
+	fmt.Println(tr("enter_retries", *maxAllowedRetries))
+	expectedRetries, err := readInt()
+	if isInputEnd(err) {
+		return 0, 0, false, err
+	}
+	if err != nil {
+		fmt.Println(tr("invalid_input", err))
+		return 0, 0, false, nil
+	}
+	return expectedLen, expectedRetries, true, nil
+}
+
+// Method to tell the user that the game is lost.
+func printLoss(game *Game) {
+	// Pick any random word and show it to the user.
+	word := game.RevealWord()
+	fmt.Println(colors.wrong(tr("lost", word)))
+	printDefinition(word)
+}
+
+// Method to show the most common letters among the remaining words.
+func printFrequencyHint(game *Game) {
+	var hints []string
+	for _, letter := range game.BestNextGuesses(frequencyHintLetters) {
+		hints = append(hints, fmt.Sprintf("%c %.0f%%", letter.Char, letter.Probability*100))
+	}
+	fmt.Println(tr("common_letters", strings.Join(hints, ", ")))
+}
+
+// Method to play a game in the terminal till it is won or lost.
+// Returns the result of every guess in order: true if the guess was accepted,
+// false if it was wrong or the time ran out. Returns an error if the player
+// stopped playing before the end of the game, see waitInput.
+func playGame(game *Game, showHint bool) ([]bool, error) {
+	var results []bool
+	// Recorded as the turns are played, including every timeout of the turns
+	// missed while waiting for the input.
+	game.AddObserver(ObserverFuncs{Guess: func(turn Turn) {
+		results = append(results, turn.Accepted)
+	}})
+	if sounds != nil {
+		game.AddObserver(sounds)
+	}
+	if game.dict.HasHints() {
+		fmt.Println(tr("hint_help"))
+	}
+	// Word shown before the last guess, to highlight what the guess revealed.
+	var previous []rune
+	// Start checking the user input character.
 	for {
-		fmt.Println("Do you want to play a new game? (Y/N): ")
-		inputChar := readChar()
-		if unicode.ToLower(inputChar) == 'n' {
-			break
+		history := game.History()
+		if *screenReader {
+			fmt.Println(spokenWord(game.CurrentDisplayedWord))
+			if len(history) > 0 {
+				fmt.Println(spokenHistory(history))
+			}
+			fmt.Println(spokenRetries(game.CurrentRetries))
+		} else {
+			fmt.Println(colors.formatWord(displayFormat, game.CurrentDisplayedWord, previous))
+			if len(history) > 0 {
+				fmt.Println("Guesses: ", formatHistory(history, colors))
+			}
 		}
-		if unicode.ToLower(inputChar) != 'y' {
-			fmt.Println("Invalid input character, please enter a valid input (y/n)")
-			continue
+		previous = append([]rune{}, game.CurrentDisplayedWord...)
+		if keyboard != nil {
+			fmt.Println(formatKeyboard(keyboard, history, colors))
 		}
-		fmt.Println("Enter the expected length of the word: ")
-		var expectedLen int
-		_, err := fmt.Scan(&expectedLen)
+		if showHint {
+			printFrequencyHint(game)
+		}
+		printCandidates(game)
+		fmt.Println(tr("enter_char", colors.used(string(game.UsedChars)), game.CurrentRetries))
+		ctx, cancel := game.TurnContext(context.Background())
+		char, inTime, err := readTimedChar(ctx, game)
 		if err != nil {
-			fmt.Println("Invalid input given, error: ", err)
+			cancel()
+			return results, err
+		}
+		var acceptedChar bool
+		if inTime {
+			// Ranked before the guess, to comment on it.
+			var ranked []ScoredLetter
+			if *coachMode {
+				ranked = game.BestNextGuesses(0)
+			}
+			acceptedChar, err = game.CheckUserInputCtx(ctx, char)
+			if err == nil && *coachMode {
+				fmt.Println(coachComment(ranked, game.dict.alphabet.Fold(char)))
+			}
+		}
+		cancel()
+		if !inTime || errors.Is(err, ErrTurnTimeout) {
+			if game.State == Lost {
+				printLoss(game)
+				return results, nil
+			}
+			fmt.Println(tr("time_up", game.CurrentRetries))
 			continue
 		}
-		// Get number of retries.
-		fmt.Println("Enter the expected number of retries(max allowed " +
-			"retries: ", *maxAllowedRetries, "):")
-		var expectedRetries int
-		_, err = fmt.Scan(&expectedRetries)
 		if err != nil {
-			fmt.Println("Invalid input given for number of retries, error ", err)
+			fmt.Println(err)
 			continue
 		}
-		game, errCode := NewGame(expectedLen, expectedRetries)
-		if errCode != NoError {
-			if errCode == InvalidLength {
-				fmt.Println("Sorry we do not have any words of length ",
-					expectedLen, " in the dictionary. Please try again!")
-			} else if errCode == InvalidRetries {
-				fmt.Println("Invalid value of expected retries, please try again")
+		if acceptedChar {
+			if game.State == Running {
+				fmt.Println(colors.correct(tr("right_char")))
+			} else if game.State == Won {
+				fmt.Println(colors.correct(tr("won")))
+				printDefinition(string(game.CurrentDisplayedWord))
+				return results, nil
 			} else {
-				// Adding a generic case. This if else should be extended with
-				// more error codes in future if needed.
-				fmt.Println("Oops, input validation failed! Please try again.")
+				printLoss(game)
+				return results, nil
 			}
-			continue
+		} else {
+			if game.State == Running {
+				fmt.Println(colors.wrong(tr("wrong_char", game.CurrentRetries)))
+			} else if game.State == Lost {
+				printLoss(game)
+				return results, nil
+			}
+		}
+	}
+}
+
+// Driver method to start the hangman game. Returns an error if the game can
+// not be set up from the flags.
+func StartHangman() error {
+	// Initialize the game.
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	stats := Stats{}
+	history := StatsHistory{Rating: initialRating}
+	profiles, profile, err := profileFromFlags()
+	if err != nil {
+		return err
+	}
+	if profile != nil {
+		stats, history = profile.Stats, profile.History
+	} else if *statsFile != "" {
+		if history, err = loadStatsHistory(*statsFile); err != nil {
+			return configError("unable to load the stats: %w", err)
+		}
+	}
+	tracker := NewSessionTracker(history.Rating)
+	var leaderboard *leaderboardStore
+	if *leaderboardFile != "" {
+		leaderboard = newLeaderboardStore(*leaderboardFile)
+	}
+	// Game being played, saved if the program is interrupted.
+	var current *Game
+	finish := func() {
+		saveInterruptedGame(current)
+		history = endSession(tracker, history)
+		if profile != nil {
+			profile.History = history
+			if err := profiles.save(profile); err != nil {
+				fmt.Println("Unable to save the profile, error ", err)
+			}
+		}
+	}
+	// Ending the session when the player stops playing too, e.g. with Ctrl+C,
+	// so that the stats and the game in progress are saved.
+	quit := func(err error) error {
+		finish()
+		return err
+	}
+	telemetry, err := telemetryFromFlags()
+	if err != nil {
+		return err
+	}
+	revealPolicy, err := revealPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	if definitions, err = definitionsFromFlags(); err != nil {
+		return err
+	}
+	retryPolicy, err := retryPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	scoring, err := scoreConfigFromFlags()
+	if err != nil {
+		return err
+	}
+	// Shared by all the games, so that the balanced opponent adapts to the player.
+	strategy, err := strategyFromFlags()
+	if err != nil {
+		return err
+	}
+	difficulty := AdaptiveDifficulty{
+		MercyAfterLosses: *mercyAfterLosses,
+		MaxRetries: *maxAllowedRetries,
+	}
+	opts := []GameOption{WithGuessTimeout(*guessTimeout), WithRevealPolicy(revealPolicy),
+		WithRetryPolicy(retryPolicy), WithStrategy(strategy)}
+	if *coachMode {
+		opts = append(opts, WithAnalysis())
+	}
+	// Easier configuration accepted by the user after a losing streak. The next
+	// game is started with it without asking for the configuration again.
+	var mercy *Difficulty
+	// Game interrupted the last time, played first if the player resumes it.
+	saved, err := resumeSavedGame(opts...)
+	if err != nil {
+		return quit(err)
+	}
+	for {
+		var expectedLen, expectedRetries int
+		showHint := *showFrequencies
+		gameOpts := opts
+		// Settings saved in the profile, the easier games do not change them.
+		var settings ProfileSettings
+		if profile != nil {
+			settings = profile.Settings
 		}
-		// Start checking the user input character.
-		for {
-			fmt.Println(string(game.CurrentDisplayedWord))
-			fmt.Println("Enter a character (previous characters: ",
-				string(game.UsedChars), ", remaining tries", game.CurrentRetries, "): ")
-			char := readChar()
-			acceptedChar, err := game.CheckUserInput(char)
+		if saved != nil {
+			expectedLen, expectedRetries = saved.ExpectedLength, saved.AllowedRetries
+		} else if mercy != nil {
+			expectedLen, expectedRetries = mercy.WordLength, mercy.Retries
+			if mercy.Strategy != nil {
+				// Copied so that the next games keep the strategy of the player.
+				gameOpts = append(append([]GameOption{}, opts...),
+					WithStrategy(mercy.Strategy))
+			}
+			mercy = nil
+			showHint = true
+		} else {
+			fmt.Println(tr("new_game"))
+			inputChar, err := readChar()
 			if err != nil {
-				fmt.Println(err)
+				return quit(err)
+			}
+			if unicode.ToLower(inputChar) == 'n' {
+				break
+			}
+			if !isYes(inputChar) {
+				fmt.Println(tr("invalid_yes_no"))
 				continue
 			}
-			if acceptedChar {
-				if game.State == Running {
-					fmt.Println("You guessed a right character!!")
-				} else if game.State == Won {
-					fmt.Println("You won! Congratulations!!!")
-					break
-				} else {
-					// Pick any random word and show it to the user.
-					randomIndex := rand.Intn(len(game.CurrentSetOfWords))
-					fmt.Println("All retries finished, you lose!! Chosen word was: ",
-						game.CurrentSetOfWords[randomIndex])
-					break
-				}
+			usual, ok, err := offerUsualGame(profile)
+			if err != nil {
+				return quit(err)
+			}
+			if ok {
+				expectedLen, expectedRetries = usual.WordLength, usual.Retries
 			} else {
-				if game.State == Running {
-					fmt.Println("Sorry its a wrong input. Remaining tries: ", game.CurrentRetries)
-				} else if game.State == Lost {
-					// Pick any random word and show it to the user.
-					randomIndex := rand.Intn(len(game.CurrentSetOfWords))
-					fmt.Println("All retries finished, you lose!! Chosen word was: ",
-						game.CurrentSetOfWords[randomIndex])
-					break
+				expectedLen, expectedRetries, ok, err = readGameConfig()
+				if err != nil {
+					return quit(err)
 				}
+				if !ok {
+					continue
+				}
+			}
+			settings = ProfileSettings{WordLength: expectedLen, Retries: expectedRetries,
+				Opponent: *opponentName}
+		}
+		var game *Game
+		var err error
+		if saved != nil {
+			game, saved = saved, nil
+		} else if expectedLen == 0 {
+			game, err = NewGameRandomLength(expectedRetries, gameOpts...)
+		} else {
+			game, err = NewGame(expectedLen, expectedRetries, gameOpts...)
+		}
+		if err == nil && expectedLen == 0 {
+			expectedLen = game.ExpectedLength
+			fmt.Println(tr("random_length", expectedLen))
+		}
+		if err != nil {
+			if errors.Is(err, ErrInvalidLength) && !wordLengthAllowed(expectedLen) {
+				// Outside --min_word_length and --max_word_length, the error
+				// lists the lengths which can be played.
+				fmt.Println(err)
+			} else if errors.Is(err, ErrInvalidLength) {
+				fmt.Println(tr("no_words", expectedLen))
+			} else if errors.Is(err, ErrInvalidRetries) {
+				fmt.Println(tr("invalid_retries"))
+			} else {
+				// Adding a generic case. This if else should be extended with
+				// more errors in future if needed.
+				fmt.Println("Oops, input validation failed! Please try again.")
+			}
+			continue
+		}
+		if *freeVowel {
+			if vowel, ok := giveFreeVowel(game); ok {
+				fmt.Println(colors.correct(tr("free_vowel", vowel)))
+			}
+		}
+		started := time.Now()
+		current = game
+		if _, err := playGame(game, showHint); err != nil {
+			return quit(err)
+		}
+		current = nil
+		if *coachMode {
+			fmt.Print(formatAnalysis(game.Analysis()))
+		}
+		if *replayFile != "" {
+			if err := saveReplay(*replayFile, game.Replay()); err != nil {
+				fmt.Println("Unable to save the replay, error ", err)
+			}
+		}
+		stats.Record(game.State)
+		tracker.Record(game, time.Since(started), time.Now())
+		score := scoring.Score(game, stats.CurrentWinStreak)
+		tracker.AddScore(score)
+		fmt.Println(tr("score", score.Total, score.Multiplier, tracker.Score))
+		telemetry.record(game, time.Now())
+		if profile != nil {
+			earned := profile.recordGame(defaultAchievements, game, stats, settings, time.Now())
+			for _, achievement := range earned {
+				fmt.Println(colors.correct(tr("achievement",
+					defaultAchievements.Description(achievement.ID))))
+			}
+			if err := profiles.save(profile); err != nil {
+				fmt.Println("Unable to save the profile, error ", err)
+			}
+		}
+		if leaderboard != nil {
+			entry := leaderboardEntry(*playerName, game, time.Since(started), time.Now())
+			if err := leaderboard.add(entry); err != nil {
+				fmt.Println("Unable to record the game in the leaderboard, error ", err)
+			}
+		}
+		// Offer an easier game if the player has been losing a lot.
+		offer, ok := difficulty.MercyOffer(stats,
+			Difficulty{WordLength: expectedLen, Retries: expectedRetries})
+		if ok {
+			fmt.Println(tr("mercy_offer", stats.CurrentLossStreak, offer.WordLength,
+				offer.Retries))
+			answer, err := readChar()
+			if err != nil {
+				return quit(err)
+			}
+			if isYes(answer) {
+				mercy = &offer
 			}
 		}
 	}
-}
-
-func main() {
-	flag.Parse()
-	StartHangman()
+	finish()
+	return nil
 }
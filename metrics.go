@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	metricsAddr = flag.String("metrics_addr", "",
+		"Address (host:port) to serve the /healthz and /stats endpoints on. "+
+			"The endpoints are disabled if this is empty.")
+
+	// Global collector shared by all the games running in this process.
+	gameMetrics = newMetricsCollector()
+)
+
+// Point in time view of the load of this process. This is intentionally kept
+// small so that an autoscaler can poll it cheaply.
+type StatsSnapshot struct {
+	// Number of games which have been started but are not yet won or lost.
+	ActiveSessions int64 `json:"active_sessions"`
+	// Number of games started since the process came up.
+	TotalSessions int64 `json:"total_sessions"`
+	// Number of games kept in memory by the server, finished or not.
+	StoredSessions int64 `json:"stored_sessions"`
+	// Number of games the server forgot after they were idle for too long.
+	EvictedSessions int64 `json:"evicted_sessions"`
+	// Number of games forfeited because no guess was made before their
+	// deadline.
+	ForfeitedSessions int64 `json:"forfeited_sessions"`
+	// Number of guesses processed since the process came up.
+	TotalGuesses int64 `json:"total_guesses"`
+	// Average time taken by the engine to process a single guess.
+	AvgGuessLatencyMicros float64 `json:"avg_guess_latency_us"`
+	// Number of guesses whose partition of the candidates was found in the
+	// partition cache, and number of guesses which had to compute it.
+	PartitionCacheHits   int64 `json:"partition_cache_hits"`
+	PartitionCacheMisses int64 `json:"partition_cache_misses"`
+	// Share of the lookups in the partition cache which found the partition,
+	// zero before the first lookup.
+	PartitionCacheHitRate float64 `json:"partition_cache_hit_rate"`
+	// Time since the process came up.
+	UptimeSeconds float64 `json:"uptime_seconds"`
+	// Time at which the snapshot was taken.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Collector for the session and latency counters.
+// All the counters are guarded by a single mutex so that a snapshot never
+// mixes values from before and after a concurrent update.
+type metricsCollector struct {
+	mu                sync.Mutex
+	startTime         time.Time
+	activeSessions    int64
+	totalSessions     int64
+	storedSessions    int64
+	evictedSessions   int64
+	forfeitedSessions int64
+	totalGuesses      int64
+	totalGuessLatency time.Duration
+	cacheHits         int64
+	cacheMisses       int64
+}
+
+func newMetricsCollector() *metricsCollector {
+	return &metricsCollector{startTime: time.Now()}
+}
+
+// Method to record that a new game has been started.
+func (m *metricsCollector) sessionStarted() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.activeSessions++
+	m.totalSessions++
+}
+
+// Method to record that a game has finished (either won or lost).
+func (m *metricsCollector) sessionEnded() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.activeSessions > 0 {
+		m.activeSessions--
+	}
+}
+
+// Method to record the number of games kept in memory by the server.
+func (m *metricsCollector) setStoredSessions(count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.storedSessions = int64(count)
+}
+
+// Method to record that the server forgot an idle game.
+func (m *metricsCollector) sessionEvicted() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evictedSessions++
+}
+
+// Method to record that a game was forfeited after its deadline.
+func (m *metricsCollector) sessionForfeited() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forfeitedSessions++
+}
+
+// Method to record the time taken to process a single guess.
+func (m *metricsCollector) observeGuess(latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.totalGuesses++
+	m.totalGuessLatency += latency
+}
+
+// Method to record a lookup in the partition cache, see partitionCache.
+func (m *metricsCollector) partitionCacheLookup(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.cacheHits++
+	} else {
+		m.cacheMisses++
+	}
+}
+
+// Method to take a consistent snapshot of all the counters.
+func (m *metricsCollector) Snapshot() StatsSnapshot {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := StatsSnapshot{
+		ActiveSessions:    m.activeSessions,
+		TotalSessions:     m.totalSessions,
+		StoredSessions:    m.storedSessions,
+		EvictedSessions:   m.evictedSessions,
+		ForfeitedSessions: m.forfeitedSessions,
+		TotalGuesses:      m.totalGuesses,
+		UptimeSeconds:     now.Sub(m.startTime).Seconds(),
+		Timestamp:         now,
+	}
+	if lookups := m.cacheHits + m.cacheMisses; lookups > 0 {
+		snapshot.PartitionCacheHits = m.cacheHits
+		snapshot.PartitionCacheMisses = m.cacheMisses
+		snapshot.PartitionCacheHitRate = float64(m.cacheHits) / float64(lookups)
+	}
+	if m.totalGuesses > 0 {
+		avg := m.totalGuessLatency / time.Duration(m.totalGuesses)
+		snapshot.AvgGuessLatencyMicros = float64(avg) / float64(time.Microsecond)
+	}
+	return snapshot
+}
+
+// ************************  HTTP endpoints ***************************
+
+// Method to build the handler serving the /healthz and /stats endpoints for
+// the given collector.
+func newMetricsHandler(m *metricsCollector) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/stats", func(w http.ResponseWriter, r *http.Request) {
+		// Take the snapshot first so that the lock is not held while writing
+		// to a (possibly slow) client.
+		snapshot := m.Snapshot()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(snapshot)
+	})
+	return mux
+}
+
+// Method to start serving the metrics endpoints in the background.
+// This is a no-op if the metrics address is not set.
+func startMetricsServer() {
+	if *metricsAddr == "" {
+		return
+	}
+	go func() {
+		err := http.ListenAndServe(*metricsAddr, newMetricsHandler(gameMetrics))
+		if err != nil {
+			defaultLogger.Errorf("Metrics server on %s stopped, error %v", *metricsAddr, err)
+		}
+	}()
+}
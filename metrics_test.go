@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+}
+
+func (s *MetricsTestSuite) TestSnapshot() {
+	m := newMetricsCollector()
+	m.sessionStarted()
+	m.sessionStarted()
+	m.sessionEnded()
+	m.observeGuess(10 * time.Microsecond)
+	m.observeGuess(30 * time.Microsecond)
+
+	snapshot := m.Snapshot()
+	assert.Equal(s.T(), int64(1), snapshot.ActiveSessions)
+	assert.Equal(s.T(), int64(2), snapshot.TotalSessions)
+	assert.Equal(s.T(), int64(2), snapshot.TotalGuesses)
+	assert.Equal(s.T(), float64(20), snapshot.AvgGuessLatencyMicros)
+	assert.Equal(s.T(), float64(0), snapshot.PartitionCacheHitRate)
+}
+
+func (s *MetricsTestSuite) TestPartitionCache() {
+	m := newMetricsCollector()
+	m.partitionCacheLookup(false)
+	m.partitionCacheLookup(true)
+	m.partitionCacheLookup(true)
+	m.partitionCacheLookup(true)
+	snapshot := m.Snapshot()
+	assert.Equal(s.T(), int64(3), snapshot.PartitionCacheHits)
+	assert.Equal(s.T(), int64(1), snapshot.PartitionCacheMisses)
+	assert.Equal(s.T(), 0.75, snapshot.PartitionCacheHitRate)
+}
+
+func (s *MetricsTestSuite) TestActiveSessionsNeverNegative() {
+	m := newMetricsCollector()
+	m.sessionEnded()
+	assert.Equal(s.T(), int64(0), m.Snapshot().ActiveSessions)
+}
+
+func (s *MetricsTestSuite) TestEndpoints() {
+	m := newMetricsCollector()
+	m.sessionStarted()
+	handler := newMetricsHandler(m)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	assert.Equal(s.T(), http.StatusOK, rec.Code)
+	var snapshot StatsSnapshot
+	assert.Nil(s.T(), json.Unmarshal(rec.Body.Bytes(), &snapshot))
+	assert.Equal(s.T(), int64(1), snapshot.ActiveSessions)
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
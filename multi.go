@@ -0,0 +1,165 @@
+package main
+
+import (
+	"sync"
+)
+
+// Numbers of boards a multi game can be played with.
+var validBoardCounts = map[int]bool{2: true, 4: true}
+
+// Game played on several boards at once, like Dordle or Quordle for hangman.
+// Every guess is applied to all the boards which are still running, each with
+// its own word and its own set of candidates, and the boards share one pool of
+// retries: a guess costs a retry when no board accepts it. The game is won once
+// every board is won, and lost once the shared retries run out.
+type MultiGame struct {
+	// Boards of the game. A board is never lost on its own: it stops taking
+	// guesses once it is won, and the boards still running are lost with the
+	// game.
+	Boards []*Game
+	// Total retries allowed, shared by all the boards.
+	AllowedRetries int
+	// Current retries left.
+	CurrentRetries int
+	// When the wrong guesses lose the game.
+	RetryPolicy RetryPolicy
+	// Used characters.
+	UsedChars []rune
+	// Current state of the game.
+	State GameState
+
+	// Guards all the fields of the game.
+	mu sync.Mutex
+}
+
+// Option to keep only a share of the words, so that every board of a multi
+// game chooses from different words. Otherwise all the boards would make the
+// same decisions for the same guesses.
+func withWordShare(share, shares int) GameOption {
+	return func(g *Game) {
+		if g.packed != nil {
+			var packed []packedWord
+			for i := share; i < len(g.packed); i += shares {
+				packed = append(packed, g.packed[i])
+			}
+			g.packed = packed
+			return
+		}
+		var words []string
+		for i := share; i < len(g.CurrentSetOfWords); i += shares {
+			words = append(words, g.CurrentSetOfWords[i])
+		}
+		g.CurrentSetOfWords = words
+		g.bits = nil
+	}
+}
+
+// Method to create a new multi game.
+// Params:
+// boards: Number of boards, 2 or 4.
+// expectedLen: Length of the word of every board.
+// retries: Retries shared by all the boards.
+// policy: When the wrong guesses lose the game.
+// opts: Options used for the game of every board. The boards must not have a
+//   guess timeout.
+//
+// Returns an error wrapping ErrInvalidBoards, ErrInvalidLength (also if there
+// are fewer words of the length than boards) or ErrInvalidRetries.
+func NewMultiGame(boards, expectedLen, retries int, policy RetryPolicy,
+	opts ...GameOption) (*MultiGame, error) {
+	if !validBoardCounts[boards] {
+		return nil, newGameError(ErrInvalidBoards,
+			"A multi game is played on 2 or 4 boards, got %d", boards)
+	}
+	if !validateNumRetries(retries) {
+		return nil, newGameError(ErrInvalidRetries,
+			"Retries must be between 0 and %d, got %d", *maxAllowedRetries, retries)
+	}
+	if currentDictionary().Count(expectedLen) < boards {
+		return nil, newGameError(ErrInvalidLength,
+			"Not enough words of length %d in the dictionary for %d boards",
+			expectedLen, boards)
+	}
+	m := &MultiGame{
+		AllowedRetries: retries,
+		CurrentRetries: retries,
+		RetryPolicy:    policy,
+		State:          Running,
+	}
+	for i := 0; i < boards; i++ {
+		// The retries are counted by the multi game, so the boards can not
+		// be lost.
+		boardOpts := append(append([]GameOption{}, opts...),
+			withWordShare(i, boards), WithRetryPolicy(RetryUnlimited))
+		game, err := NewGame(expectedLen, 0, boardOpts...)
+		if err != nil {
+			return nil, err
+		}
+		m.Boards = append(m.Boards, game)
+	}
+	return m, nil
+}
+
+// Method to play a character on every board which is still running.
+// Returns, for every board, true if the character was accepted on it. Boards
+// which were already won do not take the character and get false.
+// It returns an error wrapping ErrGameFinished, ErrCharAlreadyUsed or
+// ErrInvalidCharacter if the character was not played.
+func (m *MultiGame) CheckUserInput(char rune) ([]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.State != Running {
+		return nil, newGameError(ErrGameFinished,
+			"Unexpected scenario: input given for a game which is not running")
+	}
+	accepted := make([]bool, len(m.Boards))
+	anyAccepted := false
+	played := false
+	for i, board := range m.Boards {
+		if board.State != Running {
+			continue
+		}
+		ok, err := board.CheckUserInput(char)
+		if err != nil {
+			// All the running boards have the same used characters, so only
+			// the first one can refuse the character.
+			return nil, err
+		}
+		played = true
+		accepted[i] = ok
+		anyAccepted = anyAccepted || ok
+	}
+	if !played {
+		return nil, newGameError(ErrGameFinished,
+			"Unexpected scenario: input given for a game which is not running")
+	}
+	m.UsedChars = append(m.UsedChars, char)
+	if !anyAccepted {
+		m.CurrentRetries--
+		if m.RetryPolicy.lost(m.CurrentRetries) {
+			m.State = Lost
+			m.loseBoards()
+			return accepted, nil
+		}
+	}
+	won := true
+	for _, board := range m.Boards {
+		won = won && board.State == Won
+	}
+	if won {
+		m.State = Won
+	}
+	return accepted, nil
+}
+
+// Method to end the boards still running once the game is lost.
+func (m *MultiGame) loseBoards() {
+	for _, board := range m.Boards {
+		board.mu.Lock()
+		if board.State == Running {
+			board.State = Lost
+			gameMetrics.sessionEnded()
+		}
+		board.mu.Unlock()
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+var boardCount = flag.Int("boards", 1,
+	"Number of words guessed at once, 2 or 4. Every guess is played on all the "+
+		"words, which share the retries. Normal games are played if 1.")
+
+// Space between two boards shown side by side.
+const boardSeparator = "   "
+
+// Method to show the boards of a multi game side by side, with the solved ones
+// marked.
+func formatBoards(m *MultiGame) string {
+	var boards []string
+	for _, board := range m.Boards {
+		word := displayFormat.Format(board.CurrentDisplayedWord)
+		if board.State == Won {
+			word += " ✓"
+		}
+		boards = append(boards, word)
+	}
+	return strings.Join(boards, boardSeparator)
+}
+
+// Driver method to play a multi game in the terminal, see MultiGame.
+func StartMulti() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	policy, err := retryPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	revealPolicy, err := revealPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	var game *MultiGame
+	for game == nil {
+		fmt.Println("Guessing", *boardCount, "words at once")
+		expectedLen, retries, ok, err := readGameConfig()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		game, err = NewMultiGame(*boardCount, expectedLen, retries, policy,
+			WithRevealPolicy(revealPolicy))
+		if err != nil {
+			fmt.Println(err, ". Please try again!")
+		}
+	}
+	for game.State == Running {
+		fmt.Println(formatBoards(game))
+		fmt.Println("Enter a character (previous characters: ",
+			string(game.UsedChars), ", remaining tries", game.CurrentRetries, "): ")
+		char, err := readChar()
+		if err != nil {
+			return err
+		}
+		accepted, err := game.CheckUserInput(char)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		hits := 0
+		for i, ok := range accepted {
+			if ok {
+				hits++
+			}
+			if ok && game.Boards[i].State == Won {
+				fmt.Println("Word", i+1, "solved!")
+			}
+		}
+		if hits == 0 && game.State == Running {
+			fmt.Println("Sorry its a wrong input on every word. Remaining tries: ",
+				game.CurrentRetries)
+		}
+	}
+	fmt.Println(formatBoards(game))
+	if game.State == Won {
+		fmt.Println("You solved all the words! Congratulations!!!")
+		return nil
+	}
+	fmt.Println("All retries finished, you lose!!")
+	for i, board := range game.Boards {
+		if board.State != Won {
+			fmt.Println("Word", i+1, "was: ", board.RevealWord())
+		}
+	}
+	return nil
+}
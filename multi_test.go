@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type MultiTestSuite struct {
+	suite.Suite
+}
+
+func (s *MultiTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats", "list"})
+}
+
+func (s *MultiTestSuite) TestBoardsHaveTheirOwnWords() {
+	game, err := NewMultiGame(2, 4, 3, RetryStrict)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, len(game.Boards))
+	assert.Equal(s.T(), []string{"bets", "code", "last"}, game.Boards[0].Candidates())
+	assert.Equal(s.T(), []string{"cats", "fast", "list"}, game.Boards[1].Candidates())
+}
+
+func (s *MultiTestSuite) TestSharedRetries() {
+	game, err := NewMultiGame(2, 4, 2, RetryStrict)
+	assert.Nil(s.T(), err)
+	accepted, err := game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []bool{false, false}, accepted)
+	assert.Equal(s.T(), 1, game.CurrentRetries)
+	assert.Equal(s.T(), "z", string(game.Boards[0].UsedChars))
+	assert.Equal(s.T(), "z", string(game.Boards[1].UsedChars))
+
+	_, err = game.CheckUserInput('z')
+	assert.True(s.T(), errors.Is(err, ErrCharAlreadyUsed))
+	assert.Equal(s.T(), 1, game.CurrentRetries)
+
+	_, err = game.CheckUserInput('q')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), Lost, game.State)
+	assert.Equal(s.T(), Lost, game.Boards[0].State)
+	assert.Equal(s.T(), Lost, game.Boards[1].State)
+	_, err = game.CheckUserInput('a')
+	assert.True(s.T(), errors.Is(err, ErrGameFinished))
+}
+
+func (s *MultiTestSuite) TestWin() {
+	InitGame([]string{"ab", "ba"})
+	game, err := NewMultiGame(2, 2, 0, RetryStrict)
+	assert.Nil(s.T(), err)
+	accepted, err := game.CheckUserInput('a')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []bool{true, true}, accepted)
+	assert.Equal(s.T(), Running, game.State)
+	accepted, err = game.CheckUserInput('b')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []bool{true, true}, accepted)
+	assert.Equal(s.T(), Won, game.State)
+	assert.Equal(s.T(), 0, game.CurrentRetries)
+}
+
+func (s *MultiTestSuite) TestInvalidConfig() {
+	_, err := NewMultiGame(3, 4, 3, RetryStrict)
+	assert.True(s.T(), errors.Is(err, ErrInvalidBoards))
+	_, err = NewMultiGame(4, 5, 3, RetryStrict)
+	assert.True(s.T(), errors.Is(err, ErrInvalidLength))
+	_, err = NewMultiGame(2, 4, -1, RetryStrict)
+	assert.True(s.T(), errors.Is(err, ErrInvalidRetries))
+	// Every board needs at least one word.
+	game, err := NewMultiGame(4, 4, 3, RetryStrict)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4, len(game.Boards))
+}
+
+func TestMultiTestSuite(t *testing.T) {
+	suite.Run(t, new(MultiTestSuite))
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+var (
+	serverURL = flag.String("server_url", "http://localhost:8080",
+		"URL of the server whose games are followed by the \"watch\" subcommand.")
+	watchInterval = flag.Duration("watch_interval", 5*time.Second,
+		"Time between two checks of the watched games.")
+	watchSpectate = flag.Bool("watch_spectate", false,
+		"Only notify when the watched games end, instead of also notifying after "+
+			"every guess made in them.")
+)
+
+// Notification shown on the desktop.
+type notification struct {
+	Title   string
+	Message string
+}
+
+// Function showing a desktop notification. It is a variable so that tests can
+// record the notifications instead of showing them.
+var notify = desktopNotify
+
+// Method to get the command showing a native notification on an OS, named like
+// runtime.GOOS. Returns an empty name if the OS is not supported.
+func notifyCommand(goos string, n notification) (string, []string) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=WordGuess", n.Title, n.Message}
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s",
+			appleScriptString(n.Message), appleScriptString(n.Title))
+		return "osascript", []string{"-e", script}
+	case "windows":
+		script := "[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, " +
+			"ContentType = WindowsRuntime] | Out-Null\n" +
+			"$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent(" +
+			"[Windows.UI.Notifications.ToastTemplateType]::ToastText02)\n" +
+			"$texts = $xml.GetElementsByTagName('text')\n" +
+			"$texts.Item(0).AppendChild($xml.CreateTextNode(" + powerShellString(n.Title) + ")) | Out-Null\n" +
+			"$texts.Item(1).AppendChild($xml.CreateTextNode(" + powerShellString(n.Message) + ")) | Out-Null\n" +
+			"$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)\n" +
+			"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('WordGuess').Show($toast)"
+		return "powershell", []string{"-NoProfile", "-NonInteractive", "-Command", script}
+	}
+	return "", nil
+}
+
+// Method to quote a string for AppleScript.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// Method to quote a string for PowerShell. Nothing is expanded in single
+// quoted strings, where a quote is escaped by doubling it.
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Method to show a native desktop notification. The notification is also
+// printed, so it is not lost if the desktop can not show it.
+func desktopNotify(n notification) {
+	fmt.Println(n.Title+":", n.Message)
+	name, args := notifyCommand(runtime.GOOS, n)
+	if name == "" {
+		return
+	}
+	if err := exec.Command(name, args...).Run(); err != nil {
+		defaultLogger.Errorf("Unable to show a desktop notification with %s, error %v", name, err)
+	}
+}
+
+// Method to get the notification for the change of a watched game between two
+// checks, including the warnings that a correspondence game is about to
+// expire. Returns false if nothing worth a notification happened.
+func gameNotification(prev, cur api.Game, spectate bool) (notification, bool) {
+	if prev.State == api.StateRunning && cur.State != api.StateRunning {
+		result := "won"
+		if cur.Forfeited {
+			result = "forfeited, nobody guessed in time"
+		} else if cur.State == api.StateLost {
+			result = "lost"
+		}
+		return notification{
+			Title:   "Game " + cur.ID + " ended",
+			Message: fmt.Sprintf("The game was %s: %s", result, cur.MaskedWord),
+		}, true
+	}
+	if spectate || cur.State != api.StateRunning {
+		return notification{}, false
+	}
+	if cur.ExpiryWarnings > prev.ExpiryWarnings && cur.ExpiresAt != nil {
+		return notification{
+			Title: "Game " + cur.ID + " is about to expire",
+			Message: fmt.Sprintf("Guess within %s or the game is forfeited: %s",
+				formatTimeLeft(*cur.ExpiresAt, time.Now()), cur.MaskedWord),
+		}, true
+	}
+	if cur.UsedChars == prev.UsedChars {
+		return notification{}, false
+	}
+	last := []rune(cur.UsedChars)
+	return notification{
+		Title: "Your turn in game " + cur.ID,
+		Message: fmt.Sprintf("'%c' was guessed: %s, %d retries left",
+			last[len(last)-1], cur.MaskedWord, cur.RetriesLeft),
+	}, true
+}
+
+// Client following games on a server by checking their state periodically.
+type gameFollower struct {
+	client  *http.Client
+	baseURL string
+	// Only notify when the games end.
+	spectate bool
+	// Last state seen of every game still followed, by id.
+	games map[string]api.Game
+}
+
+func newGameFollower(baseURL string, spectate bool) *gameFollower {
+	return &gameFollower{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		spectate: spectate,
+		games:    make(map[string]api.Game),
+	}
+}
+
+// Method to get the state of a game from the server.
+func (f *gameFollower) fetch(id string) (api.Game, error) {
+	var game api.Game
+	resp, err := f.client.Get(f.baseURL + "/games/" + id)
+	if err != nil {
+		return game, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errResp api.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil ||
+			errResp.Error == nil {
+			return game, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return game, errResp.Error
+	}
+	return game, json.NewDecoder(resp.Body).Decode(&game)
+}
+
+// Method to start following a game. Finished games are not followed.
+func (f *gameFollower) follow(id string) error {
+	game, err := f.fetch(id)
+	if err != nil {
+		return err
+	}
+	if game.State == api.StateRunning {
+		f.games[id] = game
+	}
+	return nil
+}
+
+// Method to check all the followed games once, notifying their changes. Games
+// which ended or no longer exist stop being followed, and games which can not
+// be fetched are checked again next time.
+func (f *gameFollower) poll() {
+	for id, prev := range f.games {
+		cur, err := f.fetch(id)
+		if apiErr, ok := err.(*api.Error); ok && apiErr.Code == api.CodeGameNotFound {
+			fmt.Println("Game", id, "no longer exists, stopped watching it.")
+			delete(f.games, id)
+			continue
+		}
+		if err != nil {
+			defaultLogger.Errorf("Unable to check game %s, error %v", id, err)
+			continue
+		}
+		if n, ok := gameNotification(prev, cur, f.spectate); ok {
+			notify(n)
+		}
+		f.games[id] = cur
+		if cur.State != api.StateRunning {
+			delete(f.games, id)
+		}
+	}
+}
+
+// Driver method for the "watch" subcommand, which runs in the background and
+// shows a desktop notification after every guess made in the given games of
+// the server, and when they end. It returns once all the games ended.
+func StartWatch(ids []string) error {
+	if len(ids) == 0 {
+		return usageError("hangman [flags] watch <game id>...")
+	}
+	follower := newGameFollower(*serverURL, *watchSpectate)
+	for _, id := range ids {
+		if err := follower.follow(id); err != nil {
+			return fmt.Errorf("unable to watch game %s: %w", id, err)
+		}
+	}
+	fmt.Println("Watching", len(follower.games), "games on", *serverURL)
+	for len(follower.games) > 0 {
+		time.Sleep(*watchInterval)
+		follower.poll()
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type NotifyTestSuite struct {
+	suite.Suite
+	server        *httptest.Server
+	notifications []notification
+}
+
+func (s *NotifyTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+	s.server = httptest.NewServer(newGameServer().Handler())
+	s.notifications = nil
+	notify = func(n notification) {
+		s.notifications = append(s.notifications, n)
+	}
+}
+
+func (s *NotifyTestSuite) TearDownTest() {
+	s.server.Close()
+	notify = desktopNotify
+}
+
+func (s *NotifyTestSuite) createGame(retries int) string {
+	body, _ := json.Marshal(api.CreateGameRequest{WordLength: 4, Retries: retries})
+	resp, err := http.Post(s.server.URL+"/games", "application/json", bytes.NewReader(body))
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	var game api.Game
+	json.NewDecoder(resp.Body).Decode(&game)
+	return game.ID
+}
+
+func (s *NotifyTestSuite) guess(id, char string) {
+	resp, err := http.Post(s.server.URL+"/games/"+id+"/guesses", "application/json",
+		bytes.NewBufferString(`{"char": "`+char+`"}`))
+	assert.Nil(s.T(), err)
+	resp.Body.Close()
+}
+
+func (s *NotifyTestSuite) TestCommands() {
+	n := notification{Title: `Game "1"`, Message: "It's your turn"}
+	name, args := notifyCommand("linux", n)
+	assert.Equal(s.T(), "notify-send", name)
+	assert.Equal(s.T(), []string{"--app-name=WordGuess", `Game "1"`, "It's your turn"}, args)
+
+	name, args = notifyCommand("darwin", n)
+	assert.Equal(s.T(), "osascript", name)
+	assert.Equal(s.T(), []string{"-e",
+		`display notification "It's your turn" with title "Game \"1\""`}, args)
+
+	name, args = notifyCommand("windows", n)
+	assert.Equal(s.T(), "powershell", name)
+	assert.Contains(s.T(), args[len(args)-1], `CreateTextNode('It''s your turn')`)
+
+	name, _ = notifyCommand("plan9", n)
+	assert.Equal(s.T(), "", name)
+}
+
+func (s *NotifyTestSuite) TestFollowGames() {
+	first, second := s.createGame(3), s.createGame(0)
+	follower := newGameFollower(s.server.URL, false)
+	assert.Nil(s.T(), follower.follow(first))
+	assert.Nil(s.T(), follower.follow(second))
+	assert.NotNil(s.T(), follower.follow("unknown"))
+
+	// Nothing changed yet.
+	follower.poll()
+	assert.Equal(s.T(), 0, len(s.notifications))
+
+	s.guess(first, "s")
+	s.guess(second, "z")
+	follower.poll()
+	assert.Equal(s.T(), 2, len(s.notifications))
+	assert.Contains(s.T(), s.notifications, notification{
+		Title:   "Your turn in game " + first,
+		Message: "'s' was guessed: __s_, 3 retries left",
+	})
+	assert.Contains(s.T(), s.notifications, notification{
+		Title:   "Game " + second + " ended",
+		Message: "The game was lost: ____",
+	})
+	// Finished games are no longer followed.
+	assert.Equal(s.T(), 1, len(follower.games))
+}
+
+func (s *NotifyTestSuite) TestSpectate() {
+	id := s.createGame(0)
+	follower := newGameFollower(s.server.URL, true)
+	assert.Nil(s.T(), follower.follow(id))
+	s.guess(id, "s")
+	follower.poll()
+	assert.Equal(s.T(), 0, len(s.notifications))
+	s.guess(id, "z")
+	follower.poll()
+	assert.Equal(s.T(), 1, len(s.notifications))
+	assert.Equal(s.T(), "Game "+id+" ended", s.notifications[0].Title)
+}
+
+func TestNotifyTestSuite(t *testing.T) {
+	suite.Run(t, new(NotifyTestSuite))
+}
@@ -0,0 +1,83 @@
+package main
+
+// Interface to react to the events of a game as they happen, e.g. to update a
+// UI, drive a bot, log or count the events, instead of polling the fields of
+// the game. The methods are called with the game locked, in the order of the
+// events, so they must not call the methods of the game.
+type Observer interface {
+	// Called after every turn, including the timeouts.
+	OnGuess(turn Turn)
+	// Called once the game is won or lost, with its new state.
+	OnStateChange(state GameState)
+	// Called when characters of the word are revealed, with the word shown
+	// to the player (emptyChar for the characters not revealed yet).
+	OnReveal(word string)
+}
+
+// Observer made of functions, any of which can be nil to ignore its events.
+type ObserverFuncs struct {
+	Guess       func(Turn)
+	StateChange func(GameState)
+	Reveal      func(string)
+}
+
+func (o ObserverFuncs) OnGuess(turn Turn) {
+	if o.Guess != nil {
+		o.Guess(turn)
+	}
+}
+
+func (o ObserverFuncs) OnStateChange(state GameState) {
+	if o.StateChange != nil {
+		o.StateChange(state)
+	}
+}
+
+func (o ObserverFuncs) OnReveal(word string) {
+	if o.Reveal != nil {
+		o.Reveal(word)
+	}
+}
+
+// Option to register an observer of the events of the game. The observers
+// registered first are called first.
+func WithObserver(observer Observer) GameOption {
+	return func(g *Game) {
+		g.observers = append(g.observers, observer)
+	}
+}
+
+// Method to register an observer of the events of a game already created,
+// see WithObserver. It gets the events which happen from now on.
+func (g *Game) AddObserver(observer Observer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.observers = append(g.observers, observer)
+}
+
+// Method to notify the observers of a turn. The game lock must be held.
+func (g *Game) notifyGuessLocked(turn Turn) {
+	for _, observer := range g.observers {
+		observer.OnGuess(turn)
+	}
+}
+
+// Method to notify the observers that the game ended. The game lock must be
+// held.
+func (g *Game) notifyStateLocked() {
+	for _, observer := range g.observers {
+		observer.OnStateChange(g.State)
+	}
+}
+
+// Method to show a new word to the player, notifying the observers if
+// characters were revealed. The game lock must be held.
+func (g *Game) revealLocked(word []rune) {
+	if string(word) == string(g.CurrentDisplayedWord) {
+		return
+	}
+	g.CurrentDisplayedWord = word
+	for _, observer := range g.observers {
+		observer.OnReveal(string(word))
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ObserverTestSuite struct {
+	suite.Suite
+}
+
+func (s *ObserverTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to get an observer recording the events it gets.
+func recordEvents(events *[]string) Observer {
+	return ObserverFuncs{
+		Guess: func(turn Turn) {
+			*events = append(*events, fmt.Sprintf("guess %c %v", turn.Char, turn.Accepted))
+		},
+		StateChange: func(state GameState) {
+			*events = append(*events, fmt.Sprintf("state %v", state == Won))
+		},
+		Reveal: func(word string) {
+			*events = append(*events, "reveal "+formatPattern(word))
+		},
+	}
+}
+
+// Method to show the characters not revealed yet as underscores.
+func formatPattern(word string) string {
+	runes := []rune(word)
+	for i, char := range runes {
+		if char == emptyChar {
+			runes[i] = '_'
+		}
+	}
+	return string(runes)
+}
+
+func (s *ObserverTestSuite) TestEvents() {
+	var events []string
+	game, err := NewGame(4, 3, WithObserver(recordEvents(&events)))
+	assert.Nil(s.T(), err)
+	for _, char := range "sazltf" {
+		_, err = game.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+	}
+	assert.Equal(s.T(), []string{
+		"reveal __s_", "guess s true",
+		"reveal _as_", "guess a true",
+		"guess z false",
+		"guess l false",
+		"reveal _ast", "guess t true",
+		"reveal fast", "guess f true",
+		"state true",
+	}, events)
+}
+
+func (s *ObserverTestSuite) TestAddObserver() {
+	game, err := NewGame(4, 1)
+	assert.Nil(s.T(), err)
+	_, err = game.CheckUserInput('s')
+	assert.Nil(s.T(), err)
+	var events []string
+	game.AddObserver(recordEvents(&events))
+	game.AddObserver(ObserverFuncs{})
+	// Invalid guesses are not turns.
+	_, err = game.CheckUserInput('s')
+	assert.NotNil(s.T(), err)
+	_, err = game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"guess z false", "state false"}, events)
+}
+
+func (s *ObserverTestSuite) TestForfeit() {
+	var states []GameState
+	game, err := NewGame(4, 3, WithObserver(ObserverFuncs{StateChange: func(state GameState) {
+		states = append(states, state)
+	}}))
+	assert.Nil(s.T(), err)
+	game.forfeit()
+	game.forfeit()
+	assert.Equal(s.T(), []GameState{Forfeited}, states)
+	assert.Equal(s.T(), Forfeited, game.State)
+}
+
+func TestObserverTestSuite(t *testing.T) {
+	suite.Run(t, new(ObserverTestSuite))
+}
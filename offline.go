@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Body of the request to create a game played offline. It takes the fields of
+// the server API, and the opponent which only offline games can choose.
+type offlineCreateRequest struct {
+	api.CreateGameRequest
+	// Personality of the computer, see --opponent. Vindictive if empty.
+	Opponent string `json:"opponent,omitempty"`
+	// Win rate targeted by the balanced opponent, see --opponent_win_rate.
+	OpponentWinRate *float64 `json:"opponent_win_rate,omitempty"`
+}
+
+// Engine playing the games without a server, e.g. in the browser once compiled
+// to WebAssembly (see wasm_js.go). The requests and responses are the JSON
+// bodies of the server API, so that a client can use either. The games are only
+// kept in memory.
+type offlineEngine struct {
+	// Guards the fields below.
+	mu     sync.Mutex
+	games  map[string]*Game
+	nextID int
+	// Shared by the balanced games, so that the opponent adapts to the player.
+	balanced map[float64]*Balanced
+}
+
+// Method to create an offline engine without any game.
+func newOfflineEngine() *offlineEngine {
+	return &offlineEngine{
+		games:    make(map[string]*Game),
+		balanced: make(map[float64]*Balanced),
+	}
+}
+
+// Method to replace the dictionary of the new games with a word list, one word
+// per line, optionally followed by its clue.
+// Returns the number of words of the dictionary.
+func (e *offlineEngine) loadDictionary(list string) int {
+	// Only the dictionary files of the flags can fail to load, not a word list.
+	InitGame(strings.Split(list, "\n"))
+	return currentDictionary().WordCount()
+}
+
+// Method to create a game, like "POST /games".
+// Returns the api.Game view of the new game.
+func (e *offlineEngine) createGame(body []byte) ([]byte, *api.Error) {
+	var req offlineCreateRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, api.NewError(api.CodeInvalidRequest, "invalid request body: %v", err)
+	}
+	if req.Preset != "" || req.LifetimeSeconds != 0 || req.Coop {
+		return nil, api.NewError(api.CodeInvalidRequest,
+			"presets, correspondence and cooperative games need a server")
+	}
+	policy, err := ParseRetryPolicy(string(req.RetryPolicy))
+	if err != nil {
+		return nil, api.NewError(api.CodeInvalidRetries, "%s", err.Error()).
+			WithDetail("retry_policy", req.RetryPolicy)
+	}
+	strategy, apiErr := e.strategy(req)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	game, err := NewGame(req.WordLength, req.Retries, WithRetryPolicy(policy),
+		WithStrategy(strategy))
+	if err != nil {
+		return nil, inputErrorToAPI(err, req.WordLength, req.Retries)
+	}
+	e.mu.Lock()
+	e.nextID++
+	id := strconv.Itoa(e.nextID)
+	e.games[id] = game
+	e.mu.Unlock()
+	return offlineResponse(apiGame(id, game))
+}
+
+// Method to get the strategy of a new game. The balanced strategies are kept,
+// one per target win rate.
+func (e *offlineEngine) strategy(req offlineCreateRequest) (Strategy, *api.Error) {
+	winRate := *opponentWinRate
+	if req.OpponentWinRate != nil {
+		winRate = *req.OpponentWinRate
+	}
+	strategy, err := ParseStrategy(req.Opponent, winRate)
+	if err != nil {
+		return nil, api.NewError(api.CodeInvalidRequest, "%s", err.Error()).
+			WithDetail("opponent", req.Opponent)
+	}
+	if _, ok := strategy.(StrategyEntropy); ok &&
+		!featureEnabled(featureEntropyOpponent, req.Player) {
+		return nil, featureDisabled(featureEntropyOpponent, "entropy opponents")
+	}
+	if _, ok := strategy.(*Balanced); !ok {
+		return strategy, nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if balanced, ok := e.balanced[winRate]; ok {
+		return balanced, nil
+	}
+	e.balanced[winRate] = strategy.(*Balanced)
+	return strategy, nil
+}
+
+// Method to get a game by its id.
+func (e *offlineEngine) game(id string) (*Game, *api.Error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	game, ok := e.games[id]
+	if !ok {
+		return nil, api.NewError(api.CodeGameNotFound, "no game with id %q", id).
+			WithDetail("id", id)
+	}
+	return game, nil
+}
+
+// Method to get the view of a game, like "GET /games/<id>".
+func (e *offlineEngine) getGame(id string) ([]byte, *api.Error) {
+	game, apiErr := e.game(id)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return offlineResponse(apiGame(id, game))
+}
+
+// Method to guess a character, like "POST /games/<id>/guesses".
+// Returns the api.GuessResponse.
+func (e *offlineEngine) guess(id string, body []byte) ([]byte, *api.Error) {
+	game, apiErr := e.game(id)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	var req api.GuessRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, api.NewError(api.CodeInvalidRequest, "invalid request body: %v", err)
+	}
+	if utf8.RuneCountInString(req.Char) != 1 {
+		return nil, api.NewError(api.CodeInvalidCharacter,
+			"a guess must be a single character").WithDetail("character", req.Char)
+	}
+	r, _ := utf8.DecodeRuneInString(req.Char)
+	accepted, err := game.CheckUserInput(r)
+	if err != nil {
+		return nil, guessErrorToAPI(r, err)
+	}
+	return offlineResponse(api.GuessResponse{Accepted: accepted, Game: apiGame(id, game)})
+}
+
+// Method to get the clue of a game, like "GET /games/<id>/hint".
+func (e *offlineEngine) hint(id string) ([]byte, *api.Error) {
+	game, apiErr := e.game(id)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	clue, ok := game.Hint()
+	if !ok {
+		return nil, api.NewError(api.CodeHintUnavailable,
+			"the words of the game do not share a clue yet")
+	}
+	return offlineResponse(api.Hint{GameID: id, Hint: clue})
+}
+
+// Method to explain the last decision of the computer, like the "why" command.
+// Returns the explanation as a JSON string, empty before the first guess.
+func (e *offlineEngine) explain(id string) ([]byte, *api.Error) {
+	game, apiErr := e.game(id)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	var explanation string
+	if game.LastDecision != nil {
+		explanation = explainDecision(*game.LastDecision, DefaultPatternFormat)
+	}
+	return offlineResponse(explanation)
+}
+
+// Method to encode the body of a response of the engine.
+func offlineResponse(v interface{}) ([]byte, *api.Error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, api.NewError(api.CodeInternal, "unable to encode the response: %v", err)
+	}
+	return body, nil
+}
+
+// Method to forget a game, e.g. once the player left it.
+func (e *offlineEngine) deleteGame(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.games, id)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type OfflineTestSuite struct {
+	suite.Suite
+	engine *offlineEngine
+}
+
+func (s *OfflineTestSuite) SetupTest() {
+	s.engine = newOfflineEngine()
+	assert.Equal(s.T(), 5, s.engine.loadDictionary("last\nfast\npast\nbets\ncode"))
+}
+
+func (s *OfflineTestSuite) create(body string) api.Game {
+	data, apiErr := s.engine.createGame([]byte(body))
+	assert.Nil(s.T(), apiErr)
+	var game api.Game
+	assert.Nil(s.T(), json.Unmarshal(data, &game))
+	return game
+}
+
+func (s *OfflineTestSuite) TestPlay() {
+	game := s.create(`{"word_length": 4, "retries": 1}`)
+	assert.Equal(s.T(), "1", game.ID)
+	assert.Equal(s.T(), "____", game.MaskedWord)
+	assert.Equal(s.T(), api.StateRunning, game.State)
+
+	data, apiErr := s.engine.guess(game.ID, []byte(`{"char": "a"}`))
+	assert.Nil(s.T(), apiErr)
+	var guess api.GuessResponse
+	assert.Nil(s.T(), json.Unmarshal(data, &guess))
+	assert.True(s.T(), guess.Accepted)
+	assert.Equal(s.T(), "_a__", guess.Game.MaskedWord)
+
+	_, apiErr = s.engine.guess(game.ID, []byte(`{"char": "a"}`))
+	assert.Equal(s.T(), api.CodeCharacterUsed, apiErr.Code)
+	_, apiErr = s.engine.guess(game.ID, []byte(`{"char": "ab"}`))
+	assert.Equal(s.T(), api.CodeInvalidCharacter, apiErr.Code)
+
+	data, apiErr = s.engine.explain(game.ID)
+	assert.Nil(s.T(), apiErr)
+	var explanation string
+	assert.Nil(s.T(), json.Unmarshal(data, &explanation))
+	assert.Contains(s.T(), explanation, "Accepting 'a' as _a__ kept 3 words.")
+
+	s.engine.deleteGame(game.ID)
+	_, apiErr = s.engine.getGame(game.ID)
+	assert.Equal(s.T(), api.CodeGameNotFound, apiErr.Code)
+}
+
+func (s *OfflineTestSuite) TestOpponent() {
+	game := s.create(`{"word_length": 4, "retries": 3, "opponent": "merciful"}`)
+	data, apiErr := s.engine.guess(game.ID, []byte(`{"char": "c"}`))
+	assert.Nil(s.T(), apiErr)
+	var guess api.GuessResponse
+	json.Unmarshal(data, &guess)
+	assert.True(s.T(), guess.Accepted)
+
+	_, apiErr = s.engine.createGame([]byte(`{"word_length": 4, "retries": 3, "opponent": "x"}`))
+	assert.Equal(s.T(), api.CodeInvalidRequest, apiErr.Code)
+
+	// The balanced games of the same win rate share the opponent.
+	first, _ := s.engine.strategy(offlineCreateRequest{Opponent: "balanced"})
+	second, _ := s.engine.strategy(offlineCreateRequest{Opponent: "balanced"})
+	assert.True(s.T(), first == second)
+}
+
+func (s *OfflineTestSuite) TestInvalidRequests() {
+	_, apiErr := s.engine.createGame([]byte(`{"word_length": 5, "retries": 3}`))
+	assert.Equal(s.T(), api.CodeInvalidLength, apiErr.Code)
+	_, apiErr = s.engine.createGame([]byte(`{"word_length": 4, "retries": 3, "preset": "easy"}`))
+	assert.Equal(s.T(), api.CodeInvalidRequest, apiErr.Code)
+	_, apiErr = s.engine.createGame([]byte(`not json`))
+	assert.Equal(s.T(), api.CodeInvalidRequest, apiErr.Code)
+}
+
+func (s *OfflineTestSuite) TestHint() {
+	game := s.create(`{"word_length": 4, "retries": 3}`)
+	_, apiErr := s.engine.hint(game.ID)
+	assert.Equal(s.T(), api.CodeHintUnavailable, apiErr.Code)
+
+	s.engine.loadDictionary("cats|animal\nrats|animal")
+	game = s.create(`{"word_length": 4, "retries": 3}`)
+	data, apiErr := s.engine.hint(game.ID)
+	assert.Nil(s.T(), apiErr)
+	assert.JSONEq(s.T(), `{"game_id": "2", "hint": "animal"}`, string(data))
+}
+
+func TestOfflineTestSuite(t *testing.T) {
+	suite.Run(t, new(OfflineTestSuite))
+}
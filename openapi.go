@@ -0,0 +1,485 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version of the OpenAPI document, changed with the REST API.
+const openAPIVersion = "1.0.0"
+
+// Query parameter of an operation of the REST API.
+type apiParam struct {
+	name        string
+	description string
+	// Type of the value, "string" or "integer".
+	kind string
+}
+
+// Operation of the REST API, as described in its OpenAPI document.
+type apiOperation struct {
+	method  string
+	path    string
+	summary string
+	query   []apiParam
+	// Type of the JSON body of the request, nil if it has none.
+	request reflect.Type
+	// Status of the response when the request succeeds, and the types its
+	// JSON body can have. The body is not JSON if there is no type.
+	status    int
+	responses []reflect.Type
+	// True for the operations of the admin API.
+	admin bool
+}
+
+// Method to get the type of a value, for the operations.
+func typeOf(v interface{}) reflect.Type {
+	return reflect.TypeOf(v)
+}
+
+// Operations of the REST API, see gameServer.Handler.
+var apiOperations = []apiOperation{
+	{method: "POST", path: "/games", summary: "Create a game, or a cooperative game if \"coop\" is set.",
+		request: typeOf(api.CreateGameRequest{}), status: http.StatusCreated,
+		responses: []reflect.Type{typeOf(api.Game{}), typeOf(api.CoopSeat{})}},
+	{method: "GET", path: "/games/{id}", summary: "Get the state of a game.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.Game{})}},
+	{method: "POST", path: "/games/{id}/guesses", summary: "Guess a character.",
+		request: typeOf(api.GuessRequest{}), status: http.StatusOK,
+		responses: []reflect.Type{typeOf(api.GuessResponse{})}},
+	{method: "GET", path: "/games/{id}/preview", summary: "Preview the next guess, for practice games only.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.Preview{})}},
+	{method: "GET", path: "/games/{id}/hint", summary: "Get the clue of the word, once the remaining words share one.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.Hint{})}},
+	{method: "GET", path: "/games/{id}/ws", summary: "Follow the game over WebSocket: every frame is a " +
+		"Message, and the players send guess messages. Add spectate=1 to only watch.",
+		query: []apiParam{{"spectate", "1 to watch without guessing.", "string"},
+			{"player_token", "Token of the player of a cooperative game.", "string"}},
+		status: http.StatusSwitchingProtocols, responses: []reflect.Type{typeOf(api.Message{})}},
+	{method: "POST", path: "/coop/join", summary: "Join a cooperative game as the partner of its host.",
+		request: typeOf(api.JoinRequest{}), status: http.StatusOK,
+		responses: []reflect.Type{typeOf(api.CoopSeat{})}},
+	{method: "GET", path: "/coop/lobby", summary: "List the cooperative games waiting for a partner.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.Lobby{})}},
+	{method: "GET", path: "/presets", summary: "List the presets.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.PresetList{})}},
+	{method: "POST", path: "/presets", summary: "Save a new preset.",
+		request: typeOf(api.Preset{}), status: http.StatusCreated,
+		responses: []reflect.Type{typeOf(api.Preset{})}},
+	{method: "GET", path: "/presets/{name}", summary: "Get a preset.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.Preset{})}},
+	{method: "GET", path: "/leaderboard", summary: "Get the top players of the leaderboard.",
+		query:  []apiParam{{"limit", "Number of players, 10 by default.", "integer"}},
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.Leaderboard{})}},
+	{method: "GET", path: "/hall_of_shame", summary: "Get the classes of words no player has solved.",
+		query:  []apiParam{{"limit", "Number of classes, 10 by default.", "integer"}},
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.HallOfShame{})}},
+	{method: "GET", path: "/about", summary: "Describe the dictionary of the server.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.About{})}},
+	{method: "GET", path: "/stats", summary: "Get the counters of the process.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(StatsSnapshot{})}},
+	{method: "GET", path: "/healthz", summary: "Check that the server is up, it answers \"ok\".",
+		status: http.StatusOK},
+	{method: "GET", path: "/openapi.json", summary: "Get this document.", status: http.StatusOK},
+	{method: "GET", path: "/admin/features", summary: "List the feature flags.",
+		query:  []apiParam{{"player", "Player to get the state of the features for.", "string"}},
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.FeatureList{})}, admin: true},
+	{method: "PUT", path: "/admin/features/{name}", summary: "Set the rule of a feature.",
+		request: typeOf(api.FeatureRule{}), status: http.StatusOK,
+		responses: []reflect.Type{typeOf(api.FeatureList{})}, admin: true},
+	{method: "DELETE", path: "/admin/features/{name}", summary: "Put a feature back in its default state.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.FeatureList{})}, admin: true},
+	{method: "PUT", path: "/admin/cohorts/{name}", summary: "Set the players of a cohort.",
+		request: typeOf(api.Cohort{}), status: http.StatusOK,
+		responses: []reflect.Type{typeOf(api.FeatureList{})}, admin: true},
+	{method: "POST", path: "/admin/dictionary/reload", summary: "Reload the dictionary.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.About{})}, admin: true},
+	{method: "GET", path: "/admin/games", summary: "List the running games.",
+		query:  []apiParam{{"all", "1 to list the finished games too.", "string"}},
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.AdminGameList{})}, admin: true},
+	{method: "GET", path: "/admin/games/{id}/candidates", summary: "List the words a game can still pick from.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.AdminCandidates{})}, admin: true},
+	{method: "POST", path: "/admin/games/{id}/finish", summary: "Forfeit a running game.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.Game{})}, admin: true},
+	{method: "GET", path: "/admin/stats", summary: "Get the aggregate stats of the games.",
+		status: http.StatusOK, responses: []reflect.Type{typeOf(api.AdminStats{})}, admin: true},
+}
+
+// Values of the string types of the API which only take a few values.
+var schemaEnums = map[reflect.Type][]string{
+	typeOf(api.GameState("")): {string(api.StateRunning), string(api.StateWon),
+		string(api.StateLost), string(api.StateForfeited)},
+	typeOf(api.RetryPolicy("")): {string(api.RetryStrict), string(api.RetryLenient),
+		string(api.RetryUnlimited)},
+	typeOf(api.ErrorCode("")): {
+		string(api.CodeInvalidRequest), string(api.CodeInvalidLength),
+		string(api.CodeInvalidRetries), string(api.CodeInvalidCharacter),
+		string(api.CodeCharacterUsed), string(api.CodeGameNotFound),
+		string(api.CodeGameFinished), string(api.CodeGameExpired), string(api.CodeTurnTimeout),
+		string(api.CodeNotYourTurn),
+		string(api.CodeForbidden), string(api.CodeRateLimited), string(api.CodeInternal),
+		string(api.CodeInvalidPreset), string(api.CodePresetNotFound),
+		string(api.CodePresetExists), string(api.CodeHintUnavailable),
+		string(api.CodeChallengeUnavailable), string(api.CodeFeatureNotFound),
+		string(api.CodeReloadFailed), string(api.CodeGameFull), string(api.CodeTimeout)},
+	typeOf(api.MessageType("")): {string(api.MessageState), string(api.MessageGuess),
+		string(api.MessageError), string(api.MessagePreview), string(api.MessageExpiryWarning)},
+}
+
+// JSON schema, as written in the OpenAPI document.
+type schema map[string]interface{}
+
+// Generator of the schemas of Go types, which keeps the schemas of the named
+// structs as components referenced by the others.
+type schemaGenerator struct {
+	components map[string]schema
+}
+
+// Method to get the schema of a type, from its JSON encoding.
+func (g *schemaGenerator) schema(t reflect.Type) schema {
+	if t == typeOf(time.Time{}) {
+		return schema{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if _, ok := g.components[t.Name()]; !ok {
+			// Reserved first, for the types referencing themselves.
+			g.components[t.Name()] = nil
+			g.components[t.Name()] = g.object(t)
+		}
+		return schema{"$ref": "#/components/schemas/" + t.Name()}
+	case reflect.String:
+		s := schema{"type": "string"}
+		if values, ok := schemaEnums[t]; ok {
+			s["enum"] = values
+		}
+		return s
+	case reflect.Bool:
+		return schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return schema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return schema{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	}
+	// Any JSON value.
+	return schema{}
+}
+
+// Smallest values of the integer fields of the requests, by struct and JSON
+// name, which are rejected before reaching the handlers.
+var schemaMinimums = map[reflect.Type]map[string]int{
+	typeOf(api.CreateGameRequest{}): {"word_length": 1},
+}
+
+// Method to get the schema of a struct. The fields of the embedded structs
+// are fields of the struct, as in its JSON encoding. Unknown fields are
+// rejected, like decodeRequest does.
+func (g *schemaGenerator) object(t reflect.Type) schema {
+	properties := schema{}
+	g.addFields(properties, t)
+	return schema{"type": "object", "properties": properties, "additionalProperties": false}
+}
+
+// Method to add the schemas of the fields of a struct to properties.
+func (g *schemaGenerator) addFields(properties schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(properties, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = g.schema(field.Type)
+		if minimum, ok := schemaMinimums[t][name]; ok {
+			properties[name].(schema)["minimum"] = minimum
+		}
+	}
+}
+
+// OpenAPI document of the REST API, with the schemas the requests are
+// validated against.
+type openAPISpec struct {
+	document   schema
+	components map[string]schema
+	// Schemas of the request bodies, by operation.
+	requests map[*apiOperation]schema
+}
+
+// Method to build the OpenAPI document of the operations.
+func newOpenAPISpec(operations []apiOperation) *openAPISpec {
+	g := &schemaGenerator{components: make(map[string]schema)}
+	spec := &openAPISpec{requests: make(map[*apiOperation]schema)}
+	errorResponse := schema{"description": "Error, see the code.", "content": schema{
+		"application/json": schema{"schema": g.schema(typeOf(api.ErrorResponse{}))}}}
+	paths := schema{}
+	for i := range operations {
+		op := &operations[i]
+		operation := schema{"summary": op.summary, "operationId": operationID(op)}
+		var params []schema
+		for _, segment := range strings.Split(op.path, "/") {
+			if strings.HasPrefix(segment, "{") {
+				params = append(params, schema{"name": strings.Trim(segment, "{}"), "in": "path",
+					"required": true, "schema": schema{"type": "string"}})
+			}
+		}
+		for _, param := range op.query {
+			params = append(params, schema{"name": param.name, "in": "query",
+				"description": param.description, "schema": schema{"type": param.kind}})
+		}
+		if params != nil {
+			operation["parameters"] = params
+		}
+		if op.request != nil {
+			body := g.schema(op.request)
+			spec.requests[op] = body
+			operation["requestBody"] = schema{"required": true, "content": schema{
+				"application/json": schema{"schema": body}}}
+		}
+		success := schema{"description": http.StatusText(op.status)}
+		switch len(op.responses) {
+		case 0:
+		case 1:
+			success["content"] = schema{"application/json": schema{"schema": g.schema(op.responses[0])}}
+		default:
+			var oneOf []schema
+			for _, t := range op.responses {
+				oneOf = append(oneOf, g.schema(t))
+			}
+			success["content"] = schema{"application/json": schema{"schema": schema{"oneOf": oneOf}}}
+		}
+		operation["responses"] = schema{fmt.Sprint(op.status): success, "default": errorResponse}
+		if op.admin {
+			operation["tags"] = []string{"admin"}
+			operation["security"] = []schema{{"adminToken": []string{}}}
+		} else {
+			operation["tags"] = []string{"games"}
+		}
+		if paths[op.path] == nil {
+			paths[op.path] = schema{}
+		}
+		paths[op.path].(schema)[strings.ToLower(op.method)] = operation
+	}
+	spec.components = g.components
+	spec.document = schema{
+		"openapi": "3.0.3",
+		"info": schema{
+			"title":   "WordGuess",
+			"version": openAPIVersion,
+			"description": "REST API of the WordGuess server. The admin API also accepts " +
+				"client certificates of the admins, see --admin_client_ca.",
+		},
+		"paths": paths,
+		"components": schema{
+			"schemas": g.components,
+			"securitySchemes": schema{"adminToken": schema{"type": "http", "scheme": "bearer",
+				"description": "Admin token of the server, see --admin_token."}},
+		},
+	}
+	return spec
+}
+
+// Method to get the id of an operation, e.g. "postGamesGuesses" for
+// POST /games/{id}/guesses.
+func operationID(op *apiOperation) string {
+	id := strings.ToLower(op.method)
+	for _, segment := range strings.FieldsFunc(op.path, func(r rune) bool {
+		return r == '/' || r == '_' || r == '.'
+	}) {
+		if !strings.HasPrefix(segment, "{") {
+			id += strings.ToUpper(segment[:1]) + segment[1:]
+		}
+	}
+	return id
+}
+
+// OpenAPI document of the server, built when the program starts.
+var openAPI = newOpenAPISpec(apiOperations)
+
+// Method to find the operation of a request.
+// Returns nil if the request is not an operation of the REST API.
+func (spec *openAPISpec) operation(r *http.Request) *apiOperation {
+	for op := range spec.requests {
+		if op.method == r.Method && pathMatches(op.path, r.URL.Path) {
+			return op
+		}
+	}
+	return nil
+}
+
+// Method to check if a path matches the path of an operation, where the
+// segments like {id} match any segment.
+func pathMatches(pattern, path string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	segments := strings.Split(path, "/")
+	if len(patternSegments) != len(segments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") {
+			if segments[i] == "" {
+				return false
+			}
+		} else if segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Method to validate a JSON value against a schema: the types of the values,
+// and the unknown fields of the objects.
+// Returns an error naming the first field which does not match.
+func (spec *openAPISpec) validate(s schema, value interface{}, field string) *api.Error {
+	if ref, ok := s["$ref"].(string); ok {
+		return spec.validate(spec.components[strings.TrimPrefix(ref, "#/components/schemas/")],
+			value, field)
+	}
+	// Null is the zero value of every field.
+	if value == nil {
+		return nil
+	}
+	invalid := func(expected string) *api.Error {
+		name := field
+		if name == "" {
+			name = "the body"
+		}
+		return api.NewError(api.CodeInvalidRequest, "invalid request body: %s must be %s",
+			name, expected).WithDetail("field", field)
+	}
+	switch s["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return invalid("an object")
+		}
+		properties, _ := s["properties"].(schema)
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			name := key
+			if field != "" {
+				name = field + "." + key
+			}
+			property, ok := properties[key].(schema)
+			if !ok {
+				additional, ok := s["additionalProperties"].(schema)
+				if !ok {
+					return api.NewError(api.CodeInvalidRequest,
+						"invalid request body: unknown field %s", name).WithDetail("field", name)
+				}
+				property = additional
+			}
+			if apiErr := spec.validate(property, object[key], name); apiErr != nil {
+				return apiErr
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return invalid("an array")
+		}
+		for i, item := range array {
+			if apiErr := spec.validate(s["items"].(schema), item,
+				fmt.Sprintf("%s[%d]", field, i)); apiErr != nil {
+				return apiErr
+			}
+		}
+	case "string":
+		// The values of the enums are checked by the handlers, which know the
+		// error codes of the invalid values, e.g. invalid_retries.
+		if _, ok := value.(string); !ok {
+			return invalid("a string")
+		}
+	case "integer":
+		if number, ok := value.(float64); !ok || number != float64(int64(number)) {
+			return invalid("an integer")
+		}
+		if minimum, ok := s["minimum"].(int); ok && value.(float64) < float64(minimum) {
+			return invalid(fmt.Sprint("at least ", minimum))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return invalid("a number")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return invalid("a boolean")
+		}
+	}
+	return nil
+}
+
+// Method to wrap the handler of the server so that the JSON bodies of the
+// requests are validated against the OpenAPI document before they are
+// handled.
+func (spec *openAPISpec) validateRequests(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op := spec.operation(r)
+		if op == nil {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+		if err != nil {
+			writeError(w, requestBodyError(err))
+			return
+		}
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			writeError(w, requestBodyError(err))
+			return
+		}
+		if apiErr := spec.validate(spec.requests[op], value, ""); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Handler of GET /openapi.json, which serves the OpenAPI document of the REST
+// API.
+func (s *gameServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, openAPI.document)
+}
+
+// Driver method for the openapi command, which writes the OpenAPI document of
+// the REST API, e.g. to generate a client.
+func StartOpenAPI() error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(openAPI.document); err != nil {
+		return fmt.Errorf("unable to write the OpenAPI document: %w", err)
+	}
+	return nil
+}
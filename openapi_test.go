@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type OpenAPITestSuite struct {
+	suite.Suite
+	gameServer *gameServer
+	server     *httptest.Server
+}
+
+func (s *OpenAPITestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+	s.gameServer = newGameServer()
+	s.gameServer.adminToken = "secret"
+	s.server = httptest.NewServer(s.gameServer.Handler())
+}
+
+func (s *OpenAPITestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// Method to send a request to the server as an admin.
+func (s *OpenAPITestSuite) do(method, path, body string) *http.Response {
+	req, err := http.NewRequest(method, s.server.URL+path, bytes.NewBufferString(body))
+	assert.Nil(s.T(), err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(s.T(), err)
+	return resp
+}
+
+func (s *OpenAPITestSuite) TestDocument() {
+	resp := s.do("GET", "/openapi.json", "")
+	defer resp.Body.Close()
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	var document struct {
+		OpenAPI    string                                `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Type string   `json:"type"`
+					Enum []string `json:"enum"`
+					Ref  string   `json:"$ref"`
+				} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	assert.Nil(s.T(), json.NewDecoder(resp.Body).Decode(&document))
+	assert.Equal(s.T(), "3.0.3", document.OpenAPI)
+	assert.Len(s.T(), document.Paths["/games/{id}/guesses"], 1)
+	assert.Contains(s.T(), document.Paths["/admin/features/{name}"], "put")
+	assert.Contains(s.T(), document.Paths["/admin/features/{name}"], "delete")
+
+	game := document.Components.Schemas["Game"]
+	assert.Contains(s.T(), game.Properties["state"].Enum, "forfeited")
+	assert.Equal(s.T(), "integer", game.Properties["word_length"].Type)
+	// The fields of the embedded game are fields of the admin view.
+	admin := document.Components.Schemas["AdminGame"]
+	assert.Contains(s.T(), admin.Properties, "id")
+	assert.Contains(s.T(), admin.Properties, "created")
+	assert.Equal(s.T(), "#/components/schemas/Game",
+		document.Components.Schemas["GuessResponse"].Properties["game"].Ref)
+}
+
+func (s *OpenAPITestSuite) TestOperationsAreRouted() {
+	for _, op := range apiOperations {
+		path := strings.NewReplacer("{id}", "x", "{name}", "x").Replace(op.path)
+		body := ""
+		if op.request != nil {
+			body = "{}"
+		}
+		resp := s.do(op.method, path, body)
+		resp.Body.Close()
+		assert.NotEqual(s.T(), http.StatusMethodNotAllowed, resp.StatusCode, op.path)
+		// The errors of the handlers are JSON, unlike the ones of the router.
+		if resp.StatusCode == http.StatusNotFound {
+			assert.Equal(s.T(), "application/json", resp.Header.Get("Content-Type"),
+				op.method+" "+op.path)
+		}
+	}
+}
+
+func (s *OpenAPITestSuite) TestValidation() {
+	for body, field := range map[string]string{
+		`{"word_length": "4", "retries": 3}`:           "word_length",
+		`{"word_length": 4.5, "retries": 3}`:           "word_length",
+		`{"word_length": 4, "bogus": true}`:            "bogus",
+		`{"word_length": 4, "player": ["a"]}`:          "player",
+		`[{"word_length": 4}]`:                         "",
+		`{"word_length": 4, "lifetime_seconds": true}`: "lifetime_seconds",
+	} {
+		resp := s.do("POST", "/games", body)
+		var errResp api.ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		resp.Body.Close()
+		assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode, body)
+		assert.Equal(s.T(), api.CodeInvalidRequest, errResp.Error.Code, body)
+		assert.Equal(s.T(), field, errResp.Error.Details["field"], body)
+	}
+	assert.Equal(s.T(), 0, s.gameServer.store.(*SessionManager).Len())
+
+	// The body is still handled once validated, null values included.
+	resp := s.do("POST", "/games", `{"word_length": 4, "retries": 3, "player": null}`)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+}
+
+func (s *OpenAPITestSuite) TestPathMatches() {
+	assert.True(s.T(), pathMatches("/games/{id}/guesses", "/games/abc/guesses"))
+	assert.False(s.T(), pathMatches("/games/{id}/guesses", "/games//guesses"))
+	assert.False(s.T(), pathMatches("/games/{id}/guesses", "/games/abc/hint"))
+	assert.False(s.T(), pathMatches("/games", "/games/abc"))
+	assert.Equal(s.T(), "postGamesGuesses", operationID(&apiOperations[2]))
+}
+
+func TestOpenAPITestSuite(t *testing.T) {
+	suite.Run(t, new(OpenAPITestSuite))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+)
+
+var (
+	coachMode = flag.Bool("coach", false,
+		"Comment on every guess of the terminal game, with the fraction of the "+
+			"remaining words which contained the letter and the best letter to "+
+			"guess instead.")
+)
+
+// Letter not guessed yet, scored by the chance that it is in the word.
+type ScoredLetter struct {
+	Char rune
+	// Fraction of the remaining words containing the letter.
+	Probability float64
+	// Number of remaining words containing the letter.
+	Count int
+}
+
+// Method to rank the letters not used yet by the number of words containing
+// them, the most common first. Ties are sorted by letter. The spaces of
+// phrases and the punctuation are not letters to guess.
+func rankLetters(words []string, used []rune) []ScoredLetter {
+	counts := make(map[rune]int)
+	for _, word := range words {
+		seen := make(map[rune]bool)
+		for _, char := range word {
+			if !seen[char] && !contains(used, char) && !isRevealedChar(char) {
+				seen[char] = true
+				counts[char]++
+			}
+		}
+	}
+	letters := make([]ScoredLetter, 0, len(counts))
+	for char, count := range counts {
+		letters = append(letters, ScoredLetter{
+			Char:        char,
+			Count:       count,
+			Probability: float64(count) / float64(len(words)),
+		})
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		if letters[i].Count != letters[j].Count {
+			return letters[i].Count > letters[j].Count
+		}
+		return letters[i].Char < letters[j].Char
+	})
+	return letters
+}
+
+// Method to get the n letters most likely to be in the word, i.e. in the most
+// words the computer is still choosing from, the best first. All the letters
+// in at least one word are returned if n is zero or less. The letters in none
+// of the words are never returned.
+func (g *Game) BestNextGuesses(n int) []ScoredLetter {
+	g.mu.Lock()
+	letters := rankLetters(g.candidatesLocked(), g.UsedChars)
+	g.mu.Unlock()
+	if n > 0 && len(letters) > n {
+		letters = letters[:n]
+	}
+	return letters
+}
+
+// Method to comment on a guess for --coach, given the letters ranked before
+// the guess.
+func coachComment(ranked []ScoredLetter, char rune) string {
+	if len(ranked) == 0 {
+		return ""
+	}
+	best := ranked[0]
+	probability := 0.0
+	for _, letter := range ranked {
+		if letter.Char == char {
+			probability = letter.Probability
+		}
+	}
+	if probability == best.Probability {
+		return fmt.Sprintf("Coach: great pick, %c was in %.0f%% of the remaining words.",
+			char, probability*100)
+	}
+	return fmt.Sprintf("Coach: that letter only appeared in %.0f%% of the remaining "+
+		"words, %c was in %.0f%%.", probability*100, best.Char, best.Probability*100)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type OracleTestSuite struct {
+	suite.Suite
+}
+
+func (s *OracleTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *OracleTestSuite) TestBestNextGuesses() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	best := game.BestNextGuesses(3)
+	assert.Equal(s.T(), []ScoredLetter{
+		{Char: 's', Probability: 0.75, Count: 3},
+		{Char: 't', Probability: 0.75, Count: 3},
+		{Char: 'a', Probability: 0.5, Count: 2},
+	}, best)
+	assert.Equal(s.T(), 10, len(game.BestNextGuesses(0)))
+
+	// The guessed letters and the letters of the dropped words are not ranked.
+	_, err = game.CheckUserInput('s')
+	assert.Nil(s.T(), err)
+	for _, letter := range game.BestNextGuesses(0) {
+		assert.NotEqual(s.T(), 's', letter.Char)
+		assert.Greater(s.T(), letter.Count, 0)
+	}
+}
+
+func (s *OracleTestSuite) TestSolver() {
+	solver, err := NewSolver(4)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []ScoredLetter{{Char: 's', Probability: 0.75, Count: 3}},
+		solver.BestNextGuesses(1))
+	assert.Nil(s.T(), solver.Feedback('s', nil))
+	assert.Equal(s.T(), []ScoredLetter{{Char: 'c', Probability: 1, Count: 1}},
+		solver.BestNextGuesses(1))
+}
+
+func (s *OracleTestSuite) TestCoachComment() {
+	ranked := []ScoredLetter{{Char: 'e', Probability: 0.62, Count: 31},
+		{Char: 'a', Probability: 0.62, Count: 31}, {Char: 'q', Probability: 0.03, Count: 1}}
+	assert.Equal(s.T(), "Coach: great pick, a was in 62% of the remaining words.",
+		coachComment(ranked, 'a'))
+	assert.Equal(s.T(), "Coach: that letter only appeared in 3% of the remaining words, "+
+		"e was in 62%.", coachComment(ranked, 'q'))
+	assert.Equal(s.T(), "Coach: that letter only appeared in 0% of the remaining words, "+
+		"e was in 62%.", coachComment(ranked, 'z'))
+	assert.Equal(s.T(), "", coachComment(nil, 'z'))
+}
+
+func TestOracleTestSuite(t *testing.T) {
+	suite.Run(t, new(OracleTestSuite))
+}
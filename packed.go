@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"github.com/hackeracc/WordGuess/engine"
+)
+
+var (
+	compactWords = flag.Bool("compact_words", false,
+		"Keep the words made of the letters a-z (up to 12 letters long) packed "+
+			"in 8 bytes each, which uses less than half the memory for large "+
+			"dictionaries.")
+)
+
+const (
+	// Bits used to store a single letter of a packed word.
+	packedLetterBits = 5
+	// Mask of the bits of a single letter.
+	packedLetterMask = 1<<packedLetterBits - 1
+	// Max length of a word which can be packed.
+	maxPackedLength = 64 / packedLetterBits
+)
+
+// Word made of the letters a-z, packed 5 bits per letter. The first letter is
+// in the most significant bits and every letter is stored as 1 to 26, so packed
+// words of the same length compare in the same order as the words themselves.
+// The length of the word is not stored, it is the length of the bucket the word
+// belongs to.
+type packedWord uint64
+
+// Method to pack a word.
+// Returns false if the word is too long or has a letter outside a-z.
+func packWord(word string) (packedWord, bool) {
+	if len(word) > maxPackedLength {
+		return 0, false
+	}
+	var p packedWord
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		if c < 'a' || c > 'z' {
+			return 0, false
+		}
+		p = p<<packedLetterBits | packedWord(c-'a'+1)
+	}
+	return p, true
+}
+
+// Method to pack all the words of a bucket.
+// Returns false if any of the words can not be packed.
+func packWords(words []string) ([]packedWord, bool) {
+	packed := make([]packedWord, len(words))
+	for i, word := range words {
+		p, ok := packWord(word)
+		if !ok {
+			return nil, false
+		}
+		packed[i] = p
+	}
+	return packed, true
+}
+
+// Method to get the letter at a position of a packed word of the given length.
+func (p packedWord) letter(pos, length int) rune {
+	shift := uint(packedLetterBits * (length - 1 - pos))
+	return rune('a' - 1 + (p>>shift)&packedLetterMask)
+}
+
+// Method to get the positions of a letter in a packed word of the given
+// length, as a bit mask where bit i is set for position i.
+func (p packedWord) positions(char rune, length int) uint16 {
+	var mask uint16
+	for pos := 0; pos < length; pos++ {
+		if p.letter(pos, length) == char {
+			mask |= 1 << uint(pos)
+		}
+	}
+	return mask
+}
+
+// Method to unpack a word of the given length.
+func (p packedWord) unpack(length int) string {
+	b := make([]byte, length)
+	for pos := range b {
+		b[pos] = byte(p.letter(pos, length))
+	}
+	return string(b)
+}
+
+// Method to unpack all the words of a bucket.
+func unpackWords(packed []packedWord, length int) []string {
+	if packed == nil {
+		return nil
+	}
+	words := make([]string, len(packed))
+	for i, p := range packed {
+		words[i] = p.unpack(length)
+	}
+	return words
+}
+
+// Method to get the max set, like getMaxSet, for packed words of the given
+// length. The words are grouped by the positions of the input character, which
+// avoids building a string for every word.
+func getMaxSetPacked(logger Logger, wordList []packedWord, length int, currWord []rune,
+	char rune) ([]packedWord, Decision) {
+	groups := make(map[uint16][]packedWord)
+	for _, word := range wordList {
+		mask := word.positions(char, length)
+		groups[mask] = append(groups[mask], word)
+	}
+	var maxSet string
+	var maxMask uint16
+	maxSetLength := -1
+	sizes := make(map[string]int, len(groups))
+	for mask, words := range groups {
+		possibility := make([]rune, len(currWord))
+		copy(possibility, currWord)
+		for pos := range possibility {
+			if mask&(1<<uint(pos)) != 0 {
+				possibility[pos] = char
+			}
+		}
+		sizes[string(possibility)] = len(words)
+		if engine.Prefer(string(possibility), len(words), maxSet, maxSetLength) {
+			maxSet, maxMask, maxSetLength = string(possibility), mask, len(words)
+		}
+	}
+	logger.Infof("Max set %v with %d words", maxSet, maxSetLength)
+	return groups[maxMask], newDecision(char, currWord, len(wordList), sizes)
+}
+
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+type PackedTestSuite struct {
+	suite.Suite
+}
+
+func (s *PackedTestSuite) TearDownTest() {
+	*compactWords = false
+}
+
+func (s *PackedTestSuite) TestPackWord() {
+	p, ok := packWord("hello")
+	assert.Equal(s.T(), true, ok)
+	assert.Equal(s.T(), "hello", p.unpack(5))
+	assert.Equal(s.T(), 'e', p.letter(1, 5))
+	assert.Equal(s.T(), uint16(0xC), p.positions('l', 5))
+
+	// Packed words keep the order of the words.
+	q, _ := packWord("help")
+	r, _ := packWord("hemp")
+	assert.True(s.T(), q < r)
+
+	p, ok = packWord("abcdefghijkl")
+	assert.Equal(s.T(), true, ok)
+	assert.Equal(s.T(), "abcdefghijkl", p.unpack(12))
+	_, ok = packWord("abcdefghijklm")
+	assert.Equal(s.T(), false, ok)
+	_, ok = packWord("Hello")
+	assert.Equal(s.T(), false, ok)
+	_, ok = packWord("straße")
+	assert.Equal(s.T(), false, ok)
+}
+
+func (s *PackedTestSuite) TestCompactDictionary() {
+	*compactWords = true
+	InitGame([]string{"last", "fast", "bets", "code", "straße", "garden"})
+	dict := currentDictionary()
+	assert.Equal(s.T(), 1, len(dict.packed))
+	assert.Equal(s.T(), []string{"bets", "code", "fast", "last"}, dict.Words(4))
+	assert.Equal(s.T(), []string{"garden", "straße"}, dict.Words(6))
+	assert.Equal(s.T(), []int{4, 6}, dict.Lengths())
+	assert.Equal(s.T(), 6, dict.WordCount())
+
+	// Same as TestConflictingOptions, in compact mode.
+	game, err := NewGame(4, 5)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, len(game.CurrentSetOfWords))
+	isValid, err := game.CheckUserInput('a')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), false, isValid)
+	assert.Equal(s.T(), 4, game.CurrentRetries)
+	assert.Equal(s.T(), []string{"bets", "code"}, game.Candidates())
+}
+
+// The compact mode must make the same decisions as the normal mode.
+func (s *PackedTestSuite) TestSameDecisions() {
+	data, err := ioutil.ReadFile("dictionary.txt")
+	assert.Nil(s.T(), err)
+	words := strings.Split(string(data), "\n")
+	guesses := "etaoinshrdlucmfwypvbgkjqxz"
+
+	InitGame(words)
+	normal := currentDictionary()
+	*compactWords = true
+	InitGame(words)
+	compact := currentDictionary()
+	assert.NotEqual(s.T(), 0, len(compact.packed))
+
+	for _, length := range normal.Lengths() {
+		currentDict.Store(normal)
+		normalGame, err := NewGame(length, *maxAllowedRetries)
+		assert.Nil(s.T(), err)
+		currentDict.Store(compact)
+		compactGame, err := NewGame(length, *maxAllowedRetries)
+		assert.Nil(s.T(), err)
+		for _, char := range guesses {
+			if normalGame.State != Running {
+				break
+			}
+			normalAccepted, _ := normalGame.CheckUserInput(char)
+			compactAccepted, _ := compactGame.CheckUserInput(char)
+			assert.Equal(s.T(), normalAccepted, compactAccepted)
+			assert.Equal(s.T(), string(normalGame.CurrentDisplayedWord),
+				string(compactGame.CurrentDisplayedWord))
+			assert.Equal(s.T(), normalGame.Candidates(), compactGame.Candidates())
+		}
+		assert.Equal(s.T(), normalGame.State, compactGame.State)
+	}
+}
+
+func TestPackedTestSuite(t *testing.T) {
+	suite.Run(t, new(PackedTestSuite))
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"
+	"github.com/hackeracc/WordGuess/engine"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	parallelPartitionThreshold = flag.Int("parallel_partition_threshold", 50000,
+		"Min number of candidate words from which the words are partitioned "+
+			"after a guess by several goroutines. Zero never partitions them in "+
+			"parallel.")
+	partitionWorkers = flag.Int("partition_workers", 0,
+		"Max number of goroutines partitioning the words after a guess. "+
+			"Defaults to GOMAXPROCS.")
+)
+
+// Max length of a word which can be partitioned with position masks, see
+// getMaxSetMasks.
+const maxMaskLength = 64
+
+// Goroutines shared by the partitioning of all the games, see runShards. There
+// are at most GOMAXPROCS of them, so the large guesses of concurrent games do
+// not start more goroutines than the CPUs can run.
+var partitionSlots = make(chan struct{}, runtime.GOMAXPROCS(0))
+
+// Method to get the number of shards to partition the given number of words
+// with, which is at least 1.
+func partitionShards(words int) int {
+	if *parallelPartitionThreshold <= 0 || words < *parallelPartitionThreshold {
+		return 1
+	}
+	shards := *partitionWorkers
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	if shards > words {
+		shards = words
+	}
+	if shards < 1 {
+		shards = 1
+	}
+	return shards
+}
+
+// Method to get the range [start, end) of the items of a shard, when n items
+// are split in the given number of shards.
+func shardRange(n, shards, shard int) (int, int) {
+	return n * shard / shards, n * (shard + 1) / shards
+}
+
+// Method to run f for every shard, in parallel when goroutines of the pool are
+// available. The shards which find no free goroutine are run by the caller, so
+// the guesses never wait for the other games.
+func runShards(shards int, f func(shard int)) {
+	var wg sync.WaitGroup
+	for shard := 1; shard < shards; shard++ {
+		select {
+		case partitionSlots <- struct{}{}:
+			wg.Add(1)
+			go func(shard int) {
+				defer wg.Done()
+				defer func() { <-partitionSlots }()
+				f(shard)
+			}(shard)
+		default:
+			f(shard)
+		}
+	}
+	f(0)
+	wg.Wait()
+}
+
+// Method to merge the sizes of the groups of words by mask counted by the
+// shards. The sizes are summed, so the merged groups do not depend on how the
+// words were split.
+func mergeGroups(partials []map[uint64]int) map[uint64]int {
+	if len(partials) == 1 {
+		return partials[0]
+	}
+	groups := make(map[uint64]int)
+	for _, partial := range partials {
+		for mask, size := range partial {
+			groups[mask] += size
+		}
+	}
+	return groups
+}
+
+// Method to get the positions of a character in a word as a bit mask, where
+// bit i is set for the i-th character (not byte) of the word. The words
+// without the character, most of them for a rare letter, are skipped without
+// decoding their runes.
+func wordPositions(word string, char rune) uint64 {
+	if char < utf8.RuneSelf {
+		if strings.IndexByte(word, byte(char)) < 0 {
+			return 0
+		}
+	} else if !strings.ContainsRune(word, char) {
+		return 0
+	}
+	var mask uint64
+	pos := 0
+	for _, c := range word {
+		if c == char {
+			mask |= 1 << uint(pos)
+		}
+		pos++
+	}
+	return mask
+}
+
+// Method to get the pattern shown to the user when the character is revealed
+// at the positions of the mask.
+func maskPattern(currWord []rune, mask uint64, char rune) string {
+	possibility := make([]rune, len(currWord))
+	copy(possibility, currWord)
+	for pos := range possibility {
+		if mask&(1<<uint(pos)) != 0 {
+			possibility[pos] = char
+		}
+	}
+	return string(possibility)
+}
+
+// Method to get the max set, like getMaxSetStrings, for words of up to
+// maxMaskLength characters. The words are grouped by the bit mask of the
+// positions of the input character instead of by the pattern they would show,
+// so only the sizes of the groups are kept while scanning the words and a
+// pattern string is built per group, not per word. The words of the kept group
+// are collected in a second scan, which makes the partitioning of large lists
+// allocation-light. Both scans are split in shards run in parallel for large
+// lists, see partitionShards.
+func getMaxSetMasks(logger Logger, wordList []string, currWord []rune, char rune) ([]string,
+	Decision) {
+	// The positions already showing the character are left out of the masks,
+	// so every mask maps to a different pattern.
+	var shown uint64
+	for pos, c := range currWord {
+		if c == char {
+			shown |= 1 << uint(pos)
+		}
+	}
+	shards := partitionShards(len(wordList))
+	partials := make([]map[uint64]int, shards)
+	runShards(shards, func(shard int) {
+		start, end := shardRange(len(wordList), shards, shard)
+		groups := make(map[uint64]int)
+		for _, word := range wordList[start:end] {
+			groups[wordPositions(word, char)&^shown]++
+		}
+		partials[shard] = groups
+	})
+	groups := mergeGroups(partials)
+	var maxSet string
+	var maxMask uint64
+	maxSetLength := 0
+	sizes := make(map[string]int, len(groups))
+	for mask, size := range groups {
+		possibility := maskPattern(currWord, mask, char)
+		sizes[possibility] = size
+		if engine.Prefer(possibility, size, maxSet, maxSetLength) {
+			maxSet, maxMask, maxSetLength = possibility, mask, size
+		}
+	}
+	// The shards keep their words in order, so the kept words are in the
+	// order of the list.
+	keptShards := make([][]string, shards)
+	if maxSetLength > 0 {
+		if shards == 1 {
+			keptShards[0] = make([]string, 0, maxSetLength)
+		}
+		runShards(shards, func(shard int) {
+			start, end := shardRange(len(wordList), shards, shard)
+			for _, word := range wordList[start:end] {
+				if wordPositions(word, char)&^shown == maxMask {
+					keptShards[shard] = append(keptShards[shard], word)
+				}
+			}
+		})
+	}
+	var kept []string
+	if shards == 1 {
+		kept = keptShards[0]
+	} else if maxSetLength > 0 {
+		kept = make([]string, 0, maxSetLength)
+		for _, words := range keptShards {
+			kept = append(kept, words...)
+		}
+	}
+	logger.Infof("Max set %v with %d words", maxSet, maxSetLength)
+	return kept, newDecision(char, currWord, len(wordList), sizes)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"container/list"
+	"encoding/binary"
+	"flag"
+	"hash/maphash"
+	"sync"
+)
+
+var (
+	partitionCacheSize = flag.Int("partition_cache_size", 256,
+		"Max number of partitions of the candidate words kept per word length, "+
+			"reused when the same candidates get the same guess again, e.g. by "+
+			"the solver or in the arena. Zero disables the cache.")
+)
+
+// Key of a partition in a partitionCache. The candidates are identified by a
+// hash of their set and their number.
+type partitionKey struct {
+	hash       uint64
+	candidates int
+	currWord   string
+	char       rune
+}
+
+// Partition kept by the vindictive engine for a guess, see
+// wordIndex.cachedMaxDecision.
+type partitionResult struct {
+	decision Decision
+	kept     bitset
+}
+
+// Seed of the hashes of the sets of candidates, see newPartitionKey.
+var partitionSeed = maphash.MakeSeed()
+
+// Entry of the recency list of a partitionCache.
+type partitionEntry struct {
+	key    partitionKey
+	result partitionResult
+}
+
+// Cache of the partitions of the candidates of a wordIndex, shared by all the
+// games of its length. The least recently used partition is dropped once the
+// cache holds --partition_cache_size of them. The cache is safe for concurrent
+// use.
+type partitionCache struct {
+	mu      sync.Mutex
+	entries map[partitionKey]*list.Element
+	// Entries from the most to the least recently used.
+	order *list.List
+}
+
+func newPartitionCache() *partitionCache {
+	return &partitionCache{
+		entries: make(map[partitionKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Method to get the key of a guess for a set of candidates.
+func newPartitionKey(candidates bitset, currWord []rune, char rune) partitionKey {
+	var h maphash.Hash
+	h.SetSeed(partitionSeed)
+	var buf [512]byte
+	for i := 0; i < len(candidates); i += len(buf) / 8 {
+		n := 0
+		for _, w := range candidates[i:min(i+len(buf)/8, len(candidates))] {
+			binary.LittleEndian.PutUint64(buf[n:], w)
+			n += 8
+		}
+		h.Write(buf[:n])
+	}
+	return partitionKey{hash: h.Sum64(), candidates: candidates.count(),
+		currWord: string(currWord), char: char}
+}
+
+// Method to get a cached partition, which becomes the most recently used.
+func (c *partitionCache) get(key partitionKey) (partitionResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return partitionResult{}, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*partitionEntry).result, true
+}
+
+// Method to cache a partition, dropping the least recently used ones above
+// the size of the cache.
+func (c *partitionCache) add(key partitionKey, result partitionResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&partitionEntry{key: key, result: result})
+	for c.order.Len() > *partitionCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*partitionEntry).key)
+	}
+}
+
+// Method to get the decision of the vindictive engine for a guess, see
+// maxDecision, along with the candidates it keeps. The result is taken from
+// the cache of the index when the same candidates got the same guess before.
+// The returned set must not be modified, as it can be shared with other games.
+func (idx *wordIndex) cachedMaxDecision(logger Logger, candidates bitset, currWord []rune,
+	char rune) (Decision, bitset) {
+	if *partitionCacheSize <= 0 || idx.cache == nil {
+		d := idx.maxDecision(logger, candidates, currWord, char)
+		return d, idx.partition(candidates, currWord, char, d.Pattern)
+	}
+	key := newPartitionKey(candidates, currWord, char)
+	if result, ok := idx.cache.get(key); ok {
+		gameMetrics.partitionCacheLookup(true)
+		d := result.decision
+		// The partitions are copied, so the games never share them.
+		d.Partitions = append([]Partition(nil), d.Partitions...)
+		return d, result.kept
+	}
+	gameMetrics.partitionCacheLookup(false)
+	d := idx.maxDecision(logger, candidates, currWord, char)
+	kept := idx.partition(candidates, currWord, char, d.Pattern)
+	idx.cache.add(key, partitionResult{decision: d, kept: kept})
+	d.Partitions = append([]Partition(nil), d.Partitions...)
+	return d, kept
+}
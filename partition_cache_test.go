@@ -0,0 +1,88 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PartitionCacheTestSuite struct {
+	suite.Suite
+}
+
+func (s *PartitionCacheTestSuite) TearDownTest() {
+	*partitionCacheSize = 256
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *PartitionCacheTestSuite) TestCachedDecision() {
+	idx := newWordIndex(4, []string{"last", "fast", "bets", "code", "cats"})
+	all := fullBitset(len(idx.words))
+	hits := gameMetrics.Snapshot().PartitionCacheHits
+	d, kept := idx.cachedMaxDecision(NopLogger{}, all, rawPattern("____"), 'e')
+	assert.Equal(s.T(), hits, gameMetrics.Snapshot().PartitionCacheHits)
+	cached, cachedKept := idx.cachedMaxDecision(NopLogger{}, all, rawPattern("____"), 'e')
+	assert.Equal(s.T(), hits+1, gameMetrics.Snapshot().PartitionCacheHits)
+	assert.Equal(s.T(), d, cached)
+	assert.Equal(s.T(), kept, cachedKept)
+	assert.Equal(s.T(), []string{"cats", "fast", "last"}, idx.wordsOf(kept))
+
+	// The partitions of the games are not shared.
+	cached.Partitions[0].Size = 0
+	again, _ := idx.cachedMaxDecision(NopLogger{}, all, rawPattern("____"), 'e')
+	assert.Equal(s.T(), d, again)
+
+	// Another set of candidates or another guess is a miss.
+	idx.cachedMaxDecision(NopLogger{}, kept, rawPattern("____"), 'e')
+	idx.cachedMaxDecision(NopLogger{}, all, rawPattern("____"), 'a')
+	assert.Equal(s.T(), hits+2, gameMetrics.Snapshot().PartitionCacheHits)
+}
+
+func (s *PartitionCacheTestSuite) TestEviction() {
+	*partitionCacheSize = 2
+	cache := newPartitionCache()
+	all := fullBitset(10)
+	keys := []partitionKey{
+		newPartitionKey(all, rawPattern("___"), 'a'),
+		newPartitionKey(all, rawPattern("___"), 'b'),
+		newPartitionKey(all, rawPattern("___"), 'c'),
+	}
+	cache.add(keys[0], partitionResult{})
+	cache.add(keys[1], partitionResult{})
+	// The first key becomes the most recently used, so the second one is
+	// dropped for the third one.
+	_, ok := cache.get(keys[0])
+	assert.True(s.T(), ok)
+	cache.add(keys[2], partitionResult{})
+	_, ok = cache.get(keys[1])
+	assert.False(s.T(), ok)
+	_, ok = cache.get(keys[0])
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 2, cache.order.Len())
+}
+
+func (s *PartitionCacheTestSuite) TestDisabled() {
+	*partitionCacheSize = 0
+	idx := newWordIndex(4, []string{"last", "fast"})
+	all := fullBitset(len(idx.words))
+	hits := gameMetrics.Snapshot().PartitionCacheHits
+	idx.cachedMaxDecision(NopLogger{}, all, rawPattern("____"), 'e')
+	idx.cachedMaxDecision(NopLogger{}, all, rawPattern("____"), 'e')
+	assert.Equal(s.T(), hits, gameMetrics.Snapshot().PartitionCacheHits)
+	assert.Equal(s.T(), 0, idx.cache.order.Len())
+}
+
+func (s *PartitionCacheTestSuite) TestSharedByGames() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats"})
+	first, _ := NewGame(4, 3)
+	first.CheckUserInput('e')
+	hits := gameMetrics.Snapshot().PartitionCacheHits
+	second, _ := NewGame(4, 3)
+	second.CheckUserInput('e')
+	assert.Equal(s.T(), hits+1, gameMetrics.Snapshot().PartitionCacheHits)
+	assert.Equal(s.T(), first.CurrentSetOfWords, second.CurrentSetOfWords)
+}
+
+func TestPartitionCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(PartitionCacheTestSuite))
+}
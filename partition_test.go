@@ -0,0 +1,113 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// Method to generate random words of the letters a-z of a single length.
+func generateWordsOfLength(n, length int, seed int64) []string {
+	rng := rand.New(rand.NewSource(seed))
+	words := make([]string, n)
+	for i := range words {
+		b := make([]byte, length)
+		for j := range b {
+			b[j] = byte('a' + rng.Intn(26))
+		}
+		words[i] = string(b)
+	}
+	return words
+}
+
+type PartitionTestSuite struct {
+	suite.Suite
+}
+
+func (s *PartitionTestSuite) TestWordPositions() {
+	assert.Equal(s.T(), uint64(0b10110), wordPositions("geese", 'e'))
+	assert.Equal(s.T(), uint64(0), wordPositions("geese", 'z'))
+	assert.Equal(s.T(), uint64(0b1001), wordPositions("éclé", 'é'))
+	assert.Equal(s.T(), uint64(0b100), wordPositions("éclé", 'l'))
+}
+
+func (s *PartitionTestSuite) TestSameAsStrings() {
+	words := generateWordsOfLength(2000, 5, 1)
+	patterns := [][]rune{rawPattern("_____"), rawPattern("__a__"), rawPattern("e___e")}
+	for _, pattern := range patterns {
+		for char := 'a'; char <= 'z'; char++ {
+			set, d := getMaxSetMasks(NopLogger{}, words, pattern, char)
+			expectedSet, expected := getMaxSetStrings(NopLogger{}, words, pattern, char)
+			assert.Equal(s.T(), expected, d)
+			assert.Equal(s.T(), expectedSet, set)
+		}
+	}
+}
+
+func (s *PartitionTestSuite) TestEmpty() {
+	set, d := getMaxSetMasks(NopLogger{}, nil, rawPattern("___"), 'a')
+	assert.Empty(s.T(), set)
+	assert.Equal(s.T(), string(rawPattern("___")), d.Pattern)
+	assert.Equal(s.T(), 0, d.Candidates)
+}
+
+func (s *PartitionTestSuite) TestParallel() {
+	defer func() { *parallelPartitionThreshold, *partitionWorkers = 50000, 0 }()
+	words := generateWordsOfLength(3000, 6, 2)
+	idx := newWordIndex(6, words)
+	all := fullBitset(len(idx.words))
+	pattern := rawPattern("__e___")
+	for char := 'a'; char <= 'z'; char++ {
+		*parallelPartitionThreshold = 0
+		expectedSet, expected := getMaxSetMasks(NopLogger{}, words, pattern, char)
+		expectedBits := idx.maxDecision(NopLogger{}, all, pattern, char)
+		*parallelPartitionThreshold, *partitionWorkers = 1, 7
+		set, d := getMaxSetMasks(NopLogger{}, words, pattern, char)
+		assert.Equal(s.T(), expected, d)
+		assert.Equal(s.T(), expectedSet, set)
+		assert.Equal(s.T(), expectedBits, idx.maxDecision(NopLogger{}, all, pattern, char))
+	}
+	// Small lists are not split.
+	*parallelPartitionThreshold, *partitionWorkers = 1000, 4
+	assert.Equal(s.T(), 1, partitionShards(999))
+	assert.Equal(s.T(), 4, partitionShards(1000))
+	*partitionWorkers = 0
+	assert.Equal(s.T(), runtime.GOMAXPROCS(0), partitionShards(1000))
+}
+
+func TestPartitionTestSuite(t *testing.T) {
+	suite.Run(t, new(PartitionTestSuite))
+}
+
+func BenchmarkGetMaxSet(b *testing.B) {
+	words := generateWordsOfLength(400000, 8, 1)
+	idx := newWordIndex(8, words)
+	all := fullBitset(len(idx.words))
+	pattern := rawPattern("________")
+	defer func() { *partitionWorkers = 0 }()
+	b.Run("strings", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			getMaxSetStrings(NopLogger{}, words, pattern, 'e')
+		}
+	})
+	for _, workers := range []int{1, runtime.GOMAXPROCS(0)} {
+		*partitionWorkers = workers
+		b.Run("masks/workers="+strconv.Itoa(workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				getMaxSetMasks(NopLogger{}, words, pattern, 'e')
+			}
+		})
+		b.Run("bits/workers="+strconv.Itoa(workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				d := idx.maxDecision(NopLogger{}, all, pattern, 'e')
+				idx.partition(all, pattern, 'e', d.Pattern)
+			}
+		})
+	}
+}
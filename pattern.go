@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	blankGlyph = flag.String("blank", "_",
+		"Character displayed for a letter which is yet to be guessed.")
+	letterSpacing = flag.Int("letter_spacing", 0,
+		"Number of spaces displayed between the letters of the word.")
+	revealedCase = flag.String("revealed_case", string(CaseAsIs),
+		"Case of the revealed letters: \"as_is\" (as in the dictionary), "+
+			"\"upper\" or \"lower\".")
+)
+
+// Max spaces between two letters of a displayed word.
+const maxLetterSpacing = 8
+
+// Case of the revealed letters of a displayed word.
+type LetterCase string
+
+const (
+	CaseAsIs  LetterCase = "as_is"
+	CaseUpper LetterCase = "upper"
+	CaseLower LetterCase = "lower"
+)
+
+// Format used to display the patterns of the engine, i.e. the word as known
+// so far. The engine marks the letters yet to be guessed with emptyChar, which
+// is only turned into a visible glyph here.
+type PatternFormat struct {
+	// Character displayed for a letter yet to be guessed.
+	Blank rune
+	// Number of spaces between two letters.
+	Spacing int
+	Case    LetterCase
+}
+
+// Format of the patterns sent by the server and saved in the stats, which
+// must not depend on the flags of the terminal.
+var DefaultPatternFormat = PatternFormat{Blank: '_', Case: CaseAsIs}
+
+// Format used by the terminal, set from the flags when the program starts.
+var displayFormat = DefaultPatternFormat
+
+// Method to display a pattern of the engine.
+func (f PatternFormat) Format(pattern []rune) string {
+	var b strings.Builder
+	for i, r := range pattern {
+		if i > 0 && f.Spacing > 0 {
+			b.WriteString(strings.Repeat(" ", f.Spacing))
+		}
+		switch {
+		case r == emptyChar:
+			r = f.Blank
+		case f.Case == CaseUpper:
+			r = unicode.ToUpper(r)
+		case f.Case == CaseLower:
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Method to display a pattern kept as a string, see Format.
+func (f PatternFormat) FormatString(pattern string) string {
+	return f.Format([]rune(pattern))
+}
+
+// Method to get the pattern format set by the flags.
+func patternFormatFromFlags() (PatternFormat, error) {
+	f := PatternFormat{Spacing: *letterSpacing, Case: LetterCase(*revealedCase)}
+	if utf8.RuneCountInString(*blankGlyph) != 1 {
+		return f, fmt.Errorf("--blank must be a single character, got %q", *blankGlyph)
+	}
+	f.Blank, _ = utf8.DecodeRuneInString(*blankGlyph)
+	if !unicode.IsPrint(f.Blank) || unicode.IsSpace(f.Blank) {
+		return f, fmt.Errorf("--blank must be a visible character, got %q", *blankGlyph)
+	}
+	if f.Spacing < 0 || f.Spacing > maxLetterSpacing {
+		return f, fmt.Errorf("--letter_spacing must be between 0 and %d, got %d",
+			maxLetterSpacing, f.Spacing)
+	}
+	switch f.Case {
+	case CaseAsIs, CaseUpper, CaseLower:
+	default:
+		return f, fmt.Errorf("--revealed_case must be %q, %q or %q, got %q",
+			CaseAsIs, CaseUpper, CaseLower, *revealedCase)
+	}
+	return f, nil
+}
+
+// Method to set the format of the terminal from the flags. Returns an error if
+// the flags are invalid.
+func setupDisplayFormat() error {
+	f, err := patternFormatFromFlags()
+	if err != nil {
+		return configError("invalid display flags: %w", err)
+	}
+	displayFormat = f
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+// Method to get the pattern of the engine for a pattern written with "_" for
+// the hidden letters, as shown by the default format.
+func rawPattern(s string) []rune {
+	pattern := []rune(s)
+	for i, r := range pattern {
+		if r == '_' {
+			pattern[i] = emptyChar
+		}
+	}
+	return pattern
+}
+
+type PatternTestSuite struct {
+	suite.Suite
+	alphabet string
+}
+
+func (s *PatternTestSuite) SetupTest() {
+	s.alphabet = *alphabetLetters
+}
+
+func (s *PatternTestSuite) TearDownTest() {
+	*alphabetLetters = s.alphabet
+	*blankGlyph, *letterSpacing, *revealedCase = "_", 0, string(CaseAsIs)
+}
+
+func (s *PatternTestSuite) TestDefaultFormat() {
+	assert.Equal(s.T(), "_a_e", DefaultPatternFormat.Format(rawPattern("_a_e")))
+	assert.Equal(s.T(), "", DefaultPatternFormat.Format(nil))
+}
+
+func (s *PatternTestSuite) TestCustomFormat() {
+	f := PatternFormat{Blank: '•', Spacing: 1, Case: CaseUpper}
+	assert.Equal(s.T(), "• Ä • E", f.Format(rawPattern("_ä_e")))
+	f = PatternFormat{Blank: '?', Spacing: 2, Case: CaseLower}
+	assert.Equal(s.T(), "?  a  ?", f.FormatString(string(rawPattern("_A_"))))
+}
+
+func (s *PatternTestSuite) TestFormatFromFlags() {
+	*blankGlyph, *letterSpacing, *revealedCase = "·", 1, "upper"
+	f, err := patternFormatFromFlags()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), PatternFormat{Blank: '·', Spacing: 1, Case: CaseUpper}, f)
+
+	*blankGlyph = "__"
+	_, err = patternFormatFromFlags()
+	assert.NotNil(s.T(), err)
+	*blankGlyph = " "
+	_, err = patternFormatFromFlags()
+	assert.NotNil(s.T(), err)
+	*blankGlyph, *letterSpacing = "_", -1
+	_, err = patternFormatFromFlags()
+	assert.NotNil(s.T(), err)
+	*letterSpacing, *revealedCase = 0, "title"
+	_, err = patternFormatFromFlags()
+	assert.NotNil(s.T(), err)
+}
+
+// A dictionary whose alphabet has "_" as a letter is played like any other.
+func (s *PatternTestSuite) TestUnderscoreLetter() {
+	*alphabetLetters = "ab_"
+	InitGame([]string{"a_b", "a_a"})
+	game, err := NewGame(3, 3)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "___", DefaultPatternFormat.Format(game.CurrentDisplayedWord))
+	for _, char := range "_ab" {
+		_, err := game.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+	}
+	assert.Equal(s.T(), Won, game.State)
+	assert.Contains(s.T(), []string{"a_a", "a_b"}, string(game.CurrentDisplayedWord))
+	assert.Equal(s.T(), "A _ B", PatternFormat{Blank: '?', Spacing: 1, Case: CaseUpper}.
+		Format([]rune("a_b")))
+}
+
+func TestPatternTestSuite(t *testing.T) {
+	suite.Run(t, new(PatternTestSuite))
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PhraseTestSuite struct {
+	suite.Suite
+}
+
+func (s *PhraseTestSuite) SetupTest() {
+	*phraseMode = true
+	InitGame([]string{"big cat", "red fox", "sad dog", "lettuce", "a b c d"})
+}
+
+func (s *PhraseTestSuite) TearDownTest() {
+	*phraseMode = false
+}
+
+func (s *PhraseTestSuite) TestValidPhrase() {
+	a := NewAlphabet("")
+	assert.Equal(s.T(), false, a.ValidWord("big cat"))
+	a.Phrases = true
+	assert.Equal(s.T(), true, a.ValidWord("big cat"))
+	assert.Equal(s.T(), true, a.ValidWord("wheel of fortune"))
+	assert.Equal(s.T(), false, a.ValidWord("big  cat"))
+	assert.Equal(s.T(), false, a.ValidWord(" big cat"))
+	assert.Equal(s.T(), false, a.ValidWord("big cat "))
+	assert.Equal(s.T(), false, a.ValidWord("big c4t"))
+}
+
+func (s *PhraseTestSuite) TestSpacesRevealed() {
+	game, err := NewGame(7, 3)
+	assert.Nil(s.T(), err)
+	// The layout shared by the most phrases is picked.
+	assert.Equal(s.T(), "___ ___", DefaultPatternFormat.Format(game.CurrentDisplayedWord))
+	assert.Equal(s.T(), []string{"big cat", "red fox", "sad dog"}, game.CurrentSetOfWords)
+	assert.Equal(s.T(), 0, len(game.UsedChars))
+
+	// Spaces can not be guessed.
+	_, err = game.CheckUserInput(' ')
+	assert.ErrorIs(s.T(), err, ErrInvalidCharacter)
+	assert.Equal(s.T(), 3, game.CurrentRetries)
+
+	for _, p := range game.PreviewAll() {
+		assert.NotEqual(s.T(), phraseSeparator, p.Char)
+	}
+}
+
+func (s *PhraseTestSuite) TestWinPhrase() {
+	game, _ := NewGame(7, 10)
+	for _, char := range "abcdefgiorstx" {
+		if game.State != Running {
+			break
+		}
+		game.CheckUserInput(char)
+	}
+	assert.Equal(s.T(), Won, game.State)
+	assert.Equal(s.T(), 1, len(game.CurrentSetOfWords))
+	assert.Equal(s.T(), game.CurrentSetOfWords[0], string(game.CurrentDisplayedWord))
+}
+
+func (s *PhraseTestSuite) TestSolverSkipsSpaces() {
+	solver, err := NewSolver(7)
+	assert.Nil(s.T(), err)
+	// Spaces are never guessed, they are not letters.
+	char, ok := solver.NextGuess()
+	assert.Equal(s.T(), true, ok)
+	assert.NotEqual(s.T(), phraseSeparator, char)
+}
+
+func TestPhraseTestSuite(t *testing.T) {
+	suite.Run(t, new(PhraseTestSuite))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"runtime"
+	"sort"
+)
+
+var (
+	dictionaryWorkers = flag.Int("dictionary_workers", 0,
+		"Max number of goroutines used to preprocess the dictionary when the "+
+			"game starts. Defaults to the number of CPUs.")
+)
+
+const (
+	// Min number of words given to a goroutine while preprocessing the
+	// dictionary. Smaller dictionaries are not worth splitting.
+	minWordsPerWorker = 10000
+)
+
+// Method to get the number of goroutines to preprocess the given number of
+// words with, which is at least 1.
+func preprocessWorkers(words int) int {
+	workers := *dictionaryWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if max := words / minWordsPerWorker; workers > max {
+		workers = max
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// Method to merge the length based maps built for the chunks of a word list,
+// keeping the first of the duplicate words. The chunks must be in the order of
+// the list, so the words of every length stay in that order.
+// Returns the words by length and the report of the list.
+func mergeLenBasedChunks(partials []lenBasedChunk) (map[int][]string, DictionaryReport) {
+	report := DictionaryReport{Lengths: make(map[int]int)}
+	sizes := make(map[int]int)
+	for _, partial := range partials {
+		report.Words += partial.count
+		report.Invalid = append(report.Invalid, partial.invalid...)
+		for length, words := range partial.words {
+			sizes[length] += len(words)
+		}
+	}
+	wordMap := make(map[int][]string, len(sizes))
+	for length, size := range sizes {
+		words := make([]string, 0, size)
+		// Line of every word kept, to report the duplicates.
+		seen := make(map[string]int, size)
+		for _, partial := range partials {
+			for i, word := range partial.words[length] {
+				line := partial.lines[length][i]
+				if first, ok := seen[word]; ok {
+					report.Duplicates = append(report.Duplicates,
+						ReportedWord{Line: line, Word: word, FirstLine: first})
+					continue
+				}
+				seen[word] = line
+				words = append(words, word)
+			}
+		}
+		wordMap[length] = words
+		report.Lengths[length] = len(words)
+	}
+	sort.Slice(report.Duplicates, func(i, j int) bool {
+		return report.Duplicates[i].Line < report.Duplicates[j].Line
+	})
+	return wordMap, report
+}
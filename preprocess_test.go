@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// Method to generate random words of 3 to 10 letters, with an invalid word
+// every 1000 words.
+func generateWords(n int, seed int64) []string {
+	rng := rand.New(rand.NewSource(seed))
+	words := make([]string, n)
+	for i := range words {
+		b := make([]byte, 3+rng.Intn(8))
+		for j := range b {
+			b[j] = byte('a' + rng.Intn(26))
+		}
+		if i%1000 == 0 {
+			b[0] = '4'
+		}
+		words[i] = string(b)
+	}
+	return words
+}
+
+type PreprocessTestSuite struct {
+	suite.Suite
+}
+
+func (s *PreprocessTestSuite) TearDownTest() {
+	*dictionaryWorkers = 0
+}
+
+func (s *PreprocessTestSuite) TestWorkers() {
+	*dictionaryWorkers = 4
+	assert.Equal(s.T(), 1, preprocessWorkers(100))
+	assert.Equal(s.T(), 2, preprocessWorkers(2*minWordsPerWorker))
+	assert.Equal(s.T(), 4, preprocessWorkers(100*minWordsPerWorker))
+	*dictionaryWorkers = 1
+	assert.Equal(s.T(), 1, preprocessWorkers(100*minWordsPerWorker))
+}
+
+func (s *PreprocessTestSuite) TestSameAsSequential() {
+	words := generateWords(5*minWordsPerWorker+123, 1)
+	*dictionaryWorkers = 1
+	sequentialWords, sequentialReport := buildLenBasedDictionary(words, NewAlphabet(""))
+	sequential := newDictionary(words, NewAlphabet(""), DictionaryMetadata{}, false)
+	*dictionaryWorkers = 4
+	// The order of the words of every length is the order of the list, and
+	// the first of the duplicates is kept.
+	parallelWords, parallelReport := buildLenBasedDictionary(words, NewAlphabet(""))
+	assert.Equal(s.T(), sequentialWords, parallelWords)
+	assert.Equal(s.T(), sequentialReport, parallelReport)
+	assert.Equal(s.T(), 51, len(parallelReport.Invalid))
+	assert.NotEmpty(s.T(), parallelReport.Duplicates)
+	parallel := newDictionary(words, NewAlphabet(""), DictionaryMetadata{}, false)
+	assert.Equal(s.T(), sequential.words, parallel.words)
+	assert.Equal(s.T(), sequential.index, parallel.index)
+}
+
+func TestPreprocessTestSuite(t *testing.T) {
+	suite.Run(t, new(PreprocessTestSuite))
+}
+
+func BenchmarkNewDictionary(b *testing.B) {
+	words := generateWords(500000, 1)
+	defer func() { *dictionaryWorkers = 0 }()
+	for _, workers := range []int{1, runtime.NumCPU()} {
+		b.Run("workers="+strconv.Itoa(workers), func(b *testing.B) {
+			*dictionaryWorkers = workers
+			for i := 0; i < b.N; i++ {
+				newDictionary(words, NewAlphabet(""), DictionaryMetadata{}, false)
+			}
+		})
+	}
+}
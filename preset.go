@@ -0,0 +1,175 @@
+package main
+
+import (
+	"github.com/hackeracc/WordGuess/api"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Max time allowed for every guess by a preset.
+	maxPresetGuessTimeout = time.Hour
+	// Max number of presets a server keeps, including the builtin ones.
+	maxPresets = 100
+)
+
+// Valid names of presets.
+var presetNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// Presets which come with the server.
+var builtinPresets = []api.Preset{
+	{
+		Name:        "classic",
+		Description: "Six wrong guesses and no time limit.",
+		Retries:     6,
+	},
+	{
+		Name:                "blitz",
+		Description:         "Ten seconds for every guess, every timeout costs a retry.",
+		Retries:             6,
+		GuessTimeoutSeconds: 10,
+	},
+	{
+		Name:        "practice",
+		Description: "Plenty of retries, with previews of every guess.",
+		Retries:     10,
+		Practice:    true,
+	},
+}
+
+// Storage of the presets of the server. Presets can be added but never changed,
+// so a game always gets the rules the preset had when it was shared.
+type presetStore struct {
+	mu      sync.Mutex
+	presets map[string]api.Preset
+}
+
+func newPresetStore() *presetStore {
+	store := &presetStore{presets: make(map[string]api.Preset)}
+	for _, p := range builtinPresets {
+		p.Builtin = true
+		store.presets[p.Name] = p
+	}
+	return store
+}
+
+// Method to add a preset after validating it.
+func (s *presetStore) add(p api.Preset) *api.Error {
+	// Only the server can create builtin presets.
+	p.Builtin = false
+	if apiErr := validatePreset(p); apiErr != nil {
+		return apiErr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.presets[p.Name]; ok {
+		return api.NewError(api.CodePresetExists, "preset %q already exists", p.Name).
+			WithDetail("name", p.Name)
+	}
+	if len(s.presets) >= maxPresets {
+		return api.NewError(api.CodeInvalidPreset, "no more than %d presets can be saved",
+			maxPresets)
+	}
+	s.presets[p.Name] = p
+	return nil
+}
+
+func (s *presetStore) get(name string) (api.Preset, *api.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.presets[name]
+	if !ok {
+		return api.Preset{}, api.NewError(api.CodePresetNotFound, "no preset named %q", name).
+			WithDetail("name", name)
+	}
+	return p, nil
+}
+
+// Method to get all the presets, sorted by name.
+func (s *presetStore) list() []api.Preset {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	presets := make([]api.Preset, 0, len(s.presets))
+	for _, p := range s.presets {
+		presets = append(presets, p)
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+	return presets
+}
+
+// Method to check that the rules of a preset can be used to create games.
+func validatePreset(p api.Preset) *api.Error {
+	switch {
+	case !presetNamePattern.MatchString(p.Name):
+		return api.NewError(api.CodeInvalidPreset,
+			"the name must be 1 to 32 lower case letters, digits, '-' or '_'").
+			WithDetail("name", p.Name)
+	case p.Retries < 0 || p.Retries > *maxAllowedRetries:
+		return api.NewError(api.CodeInvalidPreset,
+			"retries must be between 0 and %d", *maxAllowedRetries).
+			WithDetail("retries", p.Retries)
+	case p.GuessTimeoutSeconds < 0 ||
+		time.Duration(p.GuessTimeoutSeconds)*time.Second > maxPresetGuessTimeout:
+		return api.NewError(api.CodeInvalidPreset,
+			"the guess timeout must be between 0 and %d seconds",
+			int(maxPresetGuessTimeout/time.Second)).
+			WithDetail("guess_timeout_seconds", p.GuessTimeoutSeconds)
+	case p.WordLength < 0 ||
+		(p.WordLength > 0 && !currentDictionary().HasLength(p.WordLength)):
+		return api.NewError(api.CodeInvalidPreset,
+			"no words of length %d in the dictionary", p.WordLength).
+			WithDetail("word_length", p.WordLength)
+	}
+	return nil
+}
+
+// Method to apply the rules of a preset to a request to create a game. The
+// rules of the preset win, the request only picks the word length if the
+// preset does not set it.
+func applyPreset(req api.CreateGameRequest, p api.Preset) (api.CreateGameRequest, []GameOption) {
+	if p.WordLength > 0 {
+		req.WordLength = p.WordLength
+	}
+	req.Retries = p.Retries
+	req.Practice = req.Practice || p.Practice
+	var opts []GameOption
+	if p.GuessTimeoutSeconds > 0 {
+		opts = append(opts,
+			WithGuessTimeout(time.Duration(p.GuessTimeoutSeconds)*time.Second))
+	}
+	return req, opts
+}
+
+// ***************************  Handlers *******************************
+
+func (s *gameServer) handleListPresets(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, api.PresetList{Presets: s.presets.list()})
+}
+
+func (s *gameServer) handleGetPreset(w http.ResponseWriter, r *http.Request) {
+	p, apiErr := s.presets.get(r.PathValue("name"))
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *gameServer) handleCreatePreset(w http.ResponseWriter, r *http.Request) {
+	var p api.Preset
+	if apiErr := decodeRequest(w, r, &p); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if apiErr := s.presets.add(p); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	p, _ = s.presets.get(p.Name)
+	writeJSON(w, http.StatusCreated, p)
+}
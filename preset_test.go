@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type PresetTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+func (s *PresetTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *PresetTestSuite) SetupTest() {
+	s.server = httptest.NewServer(newGameServer().Handler())
+}
+
+func (s *PresetTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *PresetTestSuite) post(path string, body string, out interface{}) int {
+	resp, err := http.Post(s.server.URL+path, "application/json",
+		bytes.NewBufferString(body))
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+func (s *PresetTestSuite) get(path string, out interface{}) int {
+	resp, err := http.Get(s.server.URL + path)
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+func (s *PresetTestSuite) TestBuiltinPresets() {
+	var list api.PresetList
+	assert.Equal(s.T(), http.StatusOK, s.get("/presets", &list))
+	var names []string
+	for _, p := range list.Presets {
+		names = append(names, p.Name)
+		assert.Equal(s.T(), true, p.Builtin)
+	}
+	assert.Equal(s.T(), []string{"blitz", "classic", "practice"}, names)
+
+	// The preset sets the retries, the request picks the length.
+	var game api.Game
+	status := s.post("/games", `{"word_length": 4, "retries": 1, "preset": "practice"}`, &game)
+	assert.Equal(s.T(), http.StatusCreated, status)
+	assert.Equal(s.T(), 10, game.AllowedRetries)
+	assert.Equal(s.T(), true, game.Practice)
+	assert.Equal(s.T(), "practice", game.Preset)
+}
+
+func (s *PresetTestSuite) TestSharePreset() {
+	body := `{"name": "short-fuse", "word_length": 4, "retries": 2, ` +
+		`"guess_timeout_seconds": 30, "builtin": true}`
+	var created api.Preset
+	assert.Equal(s.T(), http.StatusCreated, s.post("/presets", body, &created))
+	assert.Equal(s.T(), false, created.Builtin)
+
+	var shared api.Preset
+	assert.Equal(s.T(), http.StatusOK, s.get("/presets/short-fuse", &shared))
+	assert.Equal(s.T(), created, shared)
+
+	var errResp api.ErrorResponse
+	assert.Equal(s.T(), http.StatusConflict, s.post("/presets", body, &errResp))
+	assert.Equal(s.T(), api.CodePresetExists, errResp.Error.Code)
+
+	var game api.Game
+	status := s.post("/games", `{"word_length": 9, "preset": "short-fuse"}`, &game)
+	assert.Equal(s.T(), http.StatusCreated, status)
+	assert.Equal(s.T(), 4, game.WordLength)
+	assert.Equal(s.T(), 2, game.AllowedRetries)
+}
+
+func (s *PresetTestSuite) TestInvalidPresets() {
+	for _, body := range []string{
+		`{"name": "Blitz", "retries": 3}`,
+		`{"name": "many", "retries": 99}`,
+		`{"name": "slow", "retries": 3, "guess_timeout_seconds": 7200}`,
+		`{"name": "long", "retries": 3, "word_length": 9}`,
+	} {
+		var errResp api.ErrorResponse
+		assert.Equal(s.T(), http.StatusBadRequest, s.post("/presets", body, &errResp), body)
+		assert.Equal(s.T(), api.CodeInvalidPreset, errResp.Error.Code, body)
+	}
+
+	var errResp api.ErrorResponse
+	assert.Equal(s.T(), http.StatusNotFound, s.get("/presets/unknown", &errResp))
+	assert.Equal(s.T(), api.CodePresetNotFound, errResp.Error.Code)
+	status := s.post("/games", `{"word_length": 4, "preset": "unknown"}`, &errResp)
+	assert.Equal(s.T(), http.StatusNotFound, status)
+}
+
+func TestPresetTestSuite(t *testing.T) {
+	suite.Run(t, new(PresetTestSuite))
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Preview of what would happen if a character was guessed. Previews do not
+// change the game, and are meant for practice games since they give away what
+// the computer knows.
+type GuessPreview struct {
+	// Character which would be guessed.
+	Char rune
+	// Fraction of the words the computer is still choosing from which contain
+	// the character.
+	Probability float64
+	// True if the computer would accept the character if it was guessed now.
+	WouldAccept bool
+}
+
+// Number of the remaining words containing a character.
+type LetterFrequency struct {
+	// Character counted.
+	Char rune
+	// Number of remaining words containing the character.
+	Count int
+	// Fraction of the remaining words containing the character.
+	Fraction float64
+}
+
+// Method to count, for every character not guessed yet, the remaining words
+// containing it. Unlike previews, this does not tell where the characters are
+// or whether a guess would be accepted, so it can be shown as a hint in any
+// game. The most common characters come first, ties are sorted by character.
+func (g *Game) LetterFrequencies() []LetterFrequency {
+	var frequencies []LetterFrequency
+	for _, letter := range g.BestNextGuesses(0) {
+		frequencies = append(frequencies, LetterFrequency{
+			Char:     letter.Char,
+			Count:    letter.Count,
+			Fraction: letter.Probability,
+		})
+	}
+	return frequencies
+}
+
+// Method to preview what would happen if the given character was guessed now.
+func (g *Game) PreviewGuess(char rune) GuessPreview {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.previewGuessLocked(g.candidatesLocked(), g.dict.alphabet.Fold(char))
+}
+
+// Method to preview a guess with the current set of words, the game lock must
+// be held.
+func (g *Game) previewGuessLocked(words []string, char rune) GuessPreview {
+	preview := GuessPreview{Char: char}
+	if len(words) == 0 || contains(g.UsedChars, char) {
+		return preview
+	}
+	matching := 0
+	for _, word := range words {
+		if strings.ContainsRune(word, char) {
+			matching++
+		}
+	}
+	preview.Probability = float64(matching) / float64(len(words))
+	if matching > 0 {
+		// Run the same decision the engine would run, without logging it.
+		_, decision := getMaxSet(NopLogger{}, words, g.CurrentDisplayedWord, char)
+		preview.WouldAccept = decision.Accepted()
+	}
+	return preview
+}
+
+// Method to preview all the characters which are not guessed yet and are
+// present in at least one of the remaining words, sorted by character. The
+// spaces of phrases are not previewed since they are revealed from the start.
+// Characters which are not returned have a probability of zero.
+func (g *Game) PreviewAll() []GuessPreview {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	words := g.candidatesLocked()
+	chars := make(map[rune]bool)
+	for _, word := range words {
+		for _, char := range word {
+			if !contains(g.UsedChars, char) && !isRevealedChar(char) {
+				chars[char] = true
+			}
+		}
+	}
+	var previews []GuessPreview
+	for char := range chars {
+		previews = append(previews, g.previewGuessLocked(words, char))
+	}
+	sort.Slice(previews, func(i, j int) bool {
+		return previews[i].Char < previews[j].Char
+	})
+	return previews
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PreviewTestSuite struct {
+	suite.Suite
+}
+
+func (s *PreviewTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *PreviewTestSuite) TestPreviewGuess() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+
+	// Half the words contain 'a', but it would be rejected (see
+	// TestConflictingOptions).
+	preview := game.PreviewGuess('a')
+	assert.Equal(s.T(), 0.5, preview.Probability)
+	assert.Equal(s.T(), false, preview.WouldAccept)
+
+	preview = game.PreviewGuess('s')
+	assert.Equal(s.T(), 0.75, preview.Probability)
+	assert.Equal(s.T(), true, preview.WouldAccept)
+
+	preview = game.PreviewGuess('z')
+	assert.Equal(s.T(), 0.0, preview.Probability)
+	assert.Equal(s.T(), false, preview.WouldAccept)
+
+	// The preview does not change the game.
+	assert.Equal(s.T(), 3, game.CurrentRetries)
+	assert.Equal(s.T(), 0, len(game.UsedChars))
+	assert.Equal(s.T(), 4, len(game.CurrentSetOfWords))
+}
+
+func (s *PreviewTestSuite) TestPreviewAll() {
+	game, _ := NewGame(4, 3)
+	game.CheckUserInput('a')
+	previews := game.PreviewAll()
+	var chars []rune
+	for _, p := range previews {
+		chars = append(chars, p.Char)
+	}
+	// Remaining words are "bets" and "code".
+	assert.Equal(s.T(), []rune("bcdeost"), chars)
+	assert.Equal(s.T(), 1.0, previews[3].Probability)
+}
+
+func (s *PreviewTestSuite) TestLetterFrequencies() {
+	game, _ := NewGame(4, 3)
+	frequencies := game.LetterFrequencies()
+	// "s" and "t" are in 3 of the 4 words, then "a" and "e" in 2.
+	assert.Equal(s.T(), LetterFrequency{Char: 's', Count: 3, Fraction: 0.75}, frequencies[0])
+	assert.Equal(s.T(), 't', frequencies[1].Char)
+	assert.Equal(s.T(), 'a', frequencies[2].Char)
+	assert.Equal(s.T(), 'e', frequencies[3].Char)
+
+	// Guessed characters are not counted.
+	game.CheckUserInput('s')
+	for _, f := range game.LetterFrequencies() {
+		assert.NotEqual(s.T(), 's', f.Char)
+	}
+}
+
+func TestPreviewTestSuite(t *testing.T) {
+	suite.Run(t, new(PreviewTestSuite))
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	useProfiles = flag.Bool("profiles", false,
+		"Keep the stats, streaks, achievements and preferred settings of the "+
+			"player in a profile, see --profiles_dir. The name of the player is "+
+			"asked when the game starts, unless --player is passed.")
+	profilesDir = flag.String("profiles_dir", "",
+		"Directory of the player profiles, ~/.wordguess/profiles if empty.")
+)
+
+const (
+	// File of the profiles directory with the name of the current profile.
+	currentProfileFile = "current"
+	// Max number of characters of a profile name.
+	maxProfileNameLength = 32
+)
+
+// Profile of a named player, saved across the sessions.
+type Profile struct {
+	Name string `json:"name"`
+	// Results of all the games played, with the current streaks.
+	Stats Stats `json:"stats"`
+	// Rating, scores and summaries of the past sessions.
+	History StatsHistory `json:"history"`
+	// Achievements unlocked, in the order they were unlocked.
+	Achievements []Achievement `json:"achievements,omitempty"`
+	// Settings of the last game played, offered for the next one.
+	Settings ProfileSettings `json:"settings"`
+}
+
+// Preferred settings of a player.
+type ProfileSettings struct {
+	// Length of the word, zero for a random length.
+	WordLength int `json:"word_length"`
+	Retries    int `json:"retries"`
+	// Personality of the computer, see --opponent.
+	Opponent string `json:"opponent,omitempty"`
+}
+
+// Method to check that a profile name can be used as a file name: letters,
+// digits, "-" and "_" only.
+func validateProfileName(name string) error {
+	if name == "" {
+		return errors.New("the profile name can not be empty")
+	}
+	if len([]rune(name)) > maxProfileNameLength {
+		return fmt.Errorf("the profile name can not be longer than %d characters",
+			maxProfileNameLength)
+	}
+	for _, char := range name {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) && char != '-' && char != '_' {
+			return fmt.Errorf("invalid profile name %q, only letters, digits, - and _ "+
+				"are allowed", name)
+		}
+	}
+	return nil
+}
+
+// Store of the profiles, one JSON file per profile in a directory.
+type profileStore struct {
+	dir string
+}
+
+// Method to get the path of the file of a profile.
+func (s profileStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// Method to load a profile. A missing profile is a new player.
+func (s profileStore) load(name string) (*Profile, error) {
+	if err := validateProfileName(name); err != nil {
+		return nil, err
+	}
+	profile := &Profile{Name: name, History: StatsHistory{Rating: initialRating}}
+	data, err := ioutil.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return profile, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %v", s.path(name), err)
+	}
+	return profile, nil
+}
+
+// Method to save a profile, creating the directory if needed.
+func (s profileStore) save(profile *Profile) error {
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path(profile.Name), data, 0644)
+}
+
+// Method to get the names of the saved profiles, sorted.
+func (s profileStore) names() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".json")
+		if !file.IsDir() && name != file.Name() && validateProfileName(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Method to get the name of the current profile, empty if none was picked yet.
+func (s profileStore) current() (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, currentProfileFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return strings.TrimSpace(string(data)), err
+}
+
+// Method to make a profile the current one, the profile played by default.
+func (s profileStore) setCurrent(name string) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(s.dir, currentProfileFile), []byte(name+"\n"), 0644)
+}
+
+// Method to record a finished game in the profile, and unlock the achievements
+// it earned.
+// Params:
+// achievements: Engine evaluating the achievements.
+// game: Finished game.
+// stats: Stats of the player including the game, see Stats.Record.
+// settings: Settings the game was played with.
+// now: Time the game ended.
+//
+// Returns the achievements unlocked by the game.
+func (p *Profile) recordGame(achievements *Achievements, game *Game, stats Stats,
+	settings ProfileSettings, now time.Time) []Achievement {
+	p.Stats = stats
+	p.Settings = settings
+	earned := achievements.Evaluate(p.Achievements, stats, game, now)
+	p.Achievements = append(p.Achievements, earned...)
+	return earned
+}
+
+// Method to get the store of the profiles configured by the flags. Returns an
+// error if the home directory is needed and can not be found.
+func profileStoreFromFlags() (profileStore, error) {
+	dir := *profilesDir
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return profileStore{}, configError("unable to find the home directory, "+
+				"please set --profiles_dir: %w", err)
+		}
+		dir = filepath.Join(home, ".wordguess", "profiles")
+	}
+	return profileStore{dir: dir}, nil
+}
+
+// Method to check if a flag was passed on the command line.
+func flagPassed(name string) bool {
+	passed := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+	return passed
+}
+
+// Method to load the profile of the player with --profiles, nil without it.
+// The name comes from --player if it is passed, and is asked otherwise, the
+// current profile being the default. The profile becomes the current one, and
+// its name is used as --player. Returns an error if the profile can not be
+// loaded.
+func profileFromFlags() (profileStore, *Profile, error) {
+	if !*useProfiles {
+		return profileStore{}, nil, nil
+	}
+	store, err := profileStoreFromFlags()
+	if err != nil {
+		return profileStore{}, nil, err
+	}
+	name := *playerName
+	if !flagPassed("player") {
+		current, err := store.current()
+		if err != nil {
+			return profileStore{}, nil, fmt.Errorf("unable to read the current profile: %w", err)
+		}
+		if current != "" {
+			name = current
+		}
+		for {
+			fmt.Println("Enter your name (press Enter to play as", name+"): ")
+			line, err := readLine()
+			if err != nil {
+				return profileStore{}, nil, err
+			}
+			if input := strings.TrimSpace(line); input != "" {
+				name = input
+			}
+			err = validateProfileName(name)
+			if err == nil {
+				break
+			}
+			fmt.Println(err)
+			name = current
+		}
+	}
+	profile, err := store.load(name)
+	if err != nil {
+		return profileStore{}, nil, fmt.Errorf("unable to load the profile: %w", err)
+	}
+	if err := store.setCurrent(name); err != nil {
+		fmt.Println("Unable to save the current profile, error ", err)
+	}
+	*playerName = profile.Name
+	if profile.Settings.Opponent != "" && !flagPassed("opponent") {
+		*opponentName = profile.Settings.Opponent
+	}
+	fmt.Println("Welcome", profile.Name+"!", "Games played:", profile.Stats.GamesPlayed,
+		", current win streak:", profile.Stats.CurrentWinStreak)
+	return store, profile, nil
+}
+
+// Method to offer the player the settings of their last game, instead of asking
+// for the configuration of the new one.
+// Returns false if there is no profile or no saved settings, or if the player
+// declined, and an error if the player stopped playing, see waitInput.
+func offerUsualGame(profile *Profile) (ProfileSettings, bool, error) {
+	if profile == nil || profile.Settings.Retries == 0 {
+		return ProfileSettings{}, false, nil
+	}
+	settings := profile.Settings
+	length := fmt.Sprint("word length ", settings.WordLength)
+	if settings.WordLength == 0 {
+		length = "random word length"
+	}
+	fmt.Println("Play your usual game (" + length + ", " + fmt.Sprint(settings.Retries) +
+		" retries)? Press Y to accept, any other key to choose: ")
+	answer, err := readChar()
+	if err != nil {
+		return ProfileSettings{}, false, err
+	}
+	return settings, isYes(answer), nil
+}
+
+// Method to format the profiles for the "profiles" subcommand, the current one
+// marked with a "*".
+func formatProfiles(profiles []*Profile, current string) string {
+	if len(profiles) == 0 {
+		return "No profiles yet, play with --profiles to create one.\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %-20s %6s %6s %7s %13s\n", "Player", "Games", "Won", "Streak",
+		"Achievements")
+	for _, profile := range profiles {
+		mark := " "
+		if profile.Name == current {
+			mark = "*"
+		}
+		fmt.Fprintf(&b, "%s %-20s %6d %6d %7d %13d\n", mark, profile.Name,
+			profile.Stats.GamesPlayed, profile.Stats.GamesWon, profile.Stats.CurrentWinStreak,
+			len(profile.Achievements))
+	}
+	return b.String()
+}
+
+// Method to format a profile for "profiles show".
+func formatProfile(profile *Profile) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Player: %s\n", profile.Name)
+	stats := profile.Stats
+	fmt.Fprintf(&b, "Games: %d (%d won, %d lost)\n", stats.GamesPlayed, stats.GamesWon,
+		stats.GamesLost)
+	fmt.Fprintf(&b, "Current win streak: %d, longest: %d\n", stats.CurrentWinStreak,
+		stats.LongestWinStreak)
+	fmt.Fprintf(&b, "Rating: %.0f\n", profile.History.Rating)
+	if settings := profile.Settings; settings.Retries > 0 {
+		fmt.Fprintf(&b, "Usual game: word length %d, %d retries", settings.WordLength,
+			settings.Retries)
+		if settings.Opponent != "" {
+			fmt.Fprintf(&b, ", %s opponent", settings.Opponent)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Achievements:\n")
+	for _, rule := range defaultAchievements.Rules() {
+		mark := " "
+		for _, achievement := range profile.Achievements {
+			if achievement.ID == rule.ID {
+				mark = "x"
+			}
+		}
+		fmt.Fprintf(&b, "  [%s] %s\n", mark, rule.Description)
+	}
+	return b.String()
+}
+
+// Driver method for the "profiles" subcommand, which lists the profiles,
+// shows one of them or switches the current one.
+func StartProfiles(args []string) error {
+	store, err := profileStoreFromFlags()
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(args) == 0 || (len(args) == 1 && args[0] == "list"):
+		names, err := store.names()
+		if err != nil {
+			return fmt.Errorf("unable to list the profiles: %w", err)
+		}
+		var profiles []*Profile
+		for _, name := range names {
+			profile, err := store.load(name)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			profiles = append(profiles, profile)
+		}
+		current, _ := store.current()
+		fmt.Print(formatProfiles(profiles, current))
+	case len(args) == 2 && args[0] == "show":
+		profile, err := store.load(args[1])
+		if err != nil {
+			return fmt.Errorf("unable to load the profile: %w", err)
+		}
+		fmt.Print(formatProfile(profile))
+	case len(args) == 2 && args[0] == "switch":
+		if err := store.setCurrent(args[1]); err != nil {
+			return fmt.Errorf("unable to switch the profile: %w", err)
+		}
+		fmt.Println("Now playing as", args[1])
+	default:
+		return usageError("hangman profiles [list|show <name>|switch <name>]")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type ProfileTestSuite struct {
+	suite.Suite
+	store profileStore
+}
+
+func (s *ProfileTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+	s.store = profileStore{dir: filepath.Join(s.T().TempDir(), "profiles")}
+}
+
+// Method to play a game with the given guesses.
+func (s *ProfileTestSuite) play(guesses string) *Game {
+	InitGame([]string{"code"})
+	defer InitGame([]string{"last", "fast", "bets", "code"})
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	for _, char := range guesses {
+		game.CheckUserInput(char)
+	}
+	return game
+}
+
+func (s *ProfileTestSuite) TestSaveAndLoad() {
+	profile, err := s.store.load("ann")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), &Profile{Name: "ann", History: StatsHistory{Rating: initialRating}},
+		profile)
+	profile.Stats.Record(Won)
+	profile.Settings = ProfileSettings{WordLength: 5, Retries: 6, Opponent: "merciful"}
+	assert.Nil(s.T(), s.store.save(profile))
+	loaded, err := s.store.load("ann")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), profile, loaded)
+	data, err := ioutil.ReadFile(s.store.path("ann"))
+	assert.Nil(s.T(), err)
+	assert.Contains(s.T(), string(data), `"current_win_streak": 1`)
+}
+
+func (s *ProfileTestSuite) TestListAndSwitch() {
+	names, err := s.store.names()
+	assert.Nil(s.T(), err)
+	assert.Empty(s.T(), names)
+	current, err := s.store.current()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "", current)
+
+	for _, name := range []string{"bob", "ann"} {
+		assert.Nil(s.T(), s.store.save(&Profile{Name: name}))
+	}
+	assert.Nil(s.T(), s.store.setCurrent("bob"))
+	names, err = s.store.names()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"ann", "bob"}, names)
+	current, err = s.store.current()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "bob", current)
+
+	text := formatProfiles([]*Profile{{Name: "ann"}, {Name: "bob"}}, "bob")
+	assert.Contains(s.T(), text, "  ann")
+	assert.Contains(s.T(), text, "* bob")
+}
+
+func (s *ProfileTestSuite) TestInvalidNames() {
+	for _, name := range []string{"", "../ann", "ann smith", "a/b"} {
+		_, err := s.store.load(name)
+		assert.NotNil(s.T(), err, name)
+		assert.NotNil(s.T(), s.store.setCurrent(name), name)
+	}
+	assert.Nil(s.T(), validateProfileName("Zoë_2"))
+}
+
+func (s *ProfileTestSuite) TestRecordGame() {
+	profile := &Profile{Name: "ann"}
+	settings := ProfileSettings{WordLength: 4, Retries: 3}
+	now := time.Now()
+
+	game := s.play("zcode")
+	stats := profile.Stats
+	stats.Record(game.State)
+	earned := profile.recordGame(defaultAchievements, game, stats, settings, now)
+	assert.Equal(s.T(), []Achievement{{ID: "first_win", Unlocked: now},
+		{ID: "evil_hard", Unlocked: now}}, earned)
+	assert.Equal(s.T(), settings, profile.Settings)
+	assert.Equal(s.T(), 1, profile.Stats.GamesWon)
+
+	// An achievement is only unlocked once.
+	game = s.play("code")
+	stats.Record(game.State)
+	earned = profile.recordGame(defaultAchievements, game, stats, settings, now)
+	assert.Equal(s.T(), []Achievement{{ID: "flawless", Unlocked: now}}, earned)
+	assert.Len(s.T(), profile.Achievements, 3)
+	assert.Contains(s.T(), formatProfile(profile), "[x] Win a game without a wrong guess")
+	assert.Contains(s.T(), formatProfile(profile), "[ ] Win 10 games in a row")
+
+	game = s.play("xyz")
+	stats.Record(game.State)
+	assert.Empty(s.T(), profile.recordGame(defaultAchievements, game, stats, settings, now))
+	assert.Equal(s.T(), 0, profile.Stats.CurrentWinStreak)
+}
+
+func TestProfileTestSuite(t *testing.T) {
+	suite.Run(t, new(ProfileTestSuite))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"testing"
+)
+
+type PunctuationTestSuite struct {
+	suite.Suite
+}
+
+func (s *PunctuationTestSuite) SetupTest() {
+	*punctuation = "-'"
+	InitGame([]string{"don't", "can't", "won't", "catch", "mother-in-law", "father-in-law",
+		"ill-at-ease"})
+}
+
+func (s *PunctuationTestSuite) TearDownTest() {
+	*punctuation = ""
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *PunctuationTestSuite) TestValidWord() {
+	a := NewAlphabet("")
+	assert.False(s.T(), a.ValidWord("don't"))
+	a.Punctuation = "-'"
+	assert.True(s.T(), a.ValidWord("don't"))
+	assert.True(s.T(), a.ValidWord("mother-in-law"))
+	assert.False(s.T(), a.ValidWord("don’t"))
+	assert.False(s.T(), a.ValidWord("-'-"))
+	assert.False(s.T(), a.ValidWord(""))
+	a.Phrases = true
+	assert.True(s.T(), a.ValidWord("rock 'n' roll"))
+
+	assert.Nil(s.T(), validatePunctuation("-'’."))
+	assert.NotNil(s.T(), validatePunctuation("-a"))
+	assert.NotNil(s.T(), validatePunctuation(" "))
+}
+
+func (s *PunctuationTestSuite) TestPunctuationRevealed() {
+	game, err := NewGame(5, 3)
+	assert.Nil(s.T(), err)
+	// The layout shared by the most words is picked.
+	assert.Equal(s.T(), "___'_", DefaultPatternFormat.Format(game.CurrentDisplayedWord))
+	assert.Equal(s.T(), []string{"can't", "don't", "won't"}, game.CurrentSetOfWords)
+	assert.Empty(s.T(), game.UsedChars)
+
+	// The punctuation can not be guessed.
+	_, err = game.CheckUserInput('\'')
+	assert.ErrorIs(s.T(), err, ErrInvalidCharacter)
+	assert.Equal(s.T(), 3, game.CurrentRetries)
+	for _, p := range game.PreviewAll() {
+		assert.NotEqual(s.T(), '\'', p.Char)
+	}
+	for _, f := range game.LetterFrequencies() {
+		assert.NotEqual(s.T(), '\'', f.Char)
+	}
+}
+
+func (s *PunctuationTestSuite) TestSeveralHyphens() {
+	game, err := NewGame(13, 10)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "______-__-___", DefaultPatternFormat.Format(game.CurrentDisplayedWord))
+	for _, char := range "motherinlawf" {
+		if game.State == Running {
+			game.CheckUserInput(char)
+		}
+	}
+	assert.Equal(s.T(), Won, game.State)
+
+	solver, err := NewSolver(11)
+	assert.Nil(s.T(), err)
+	char, ok := solver.NextGuess()
+	assert.True(s.T(), ok)
+	assert.NotEqual(s.T(), '-', char)
+}
+
+func (s *PunctuationTestSuite) TestIndex() {
+	a := NewAlphabet("")
+	a.Punctuation = "-'"
+	d := newDictionary([]string{"don't", "catch"}, a, DictionaryMetadata{}, false)
+	path := filepath.Join(s.T().TempDir(), "dict.idx")
+	assert.Nil(s.T(), saveDictionaryIndex(path, d, ""))
+	loaded, err := loadDictionaryIndex(path, "", false, "-'", FoldLower, false, false)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"catch", "don't"}, loaded.Words(5))
+	_, err = loadDictionaryIndex(path, "", false, "", FoldLower, false, false)
+	assert.NotNil(s.T(), err)
+}
+
+func TestPunctuationTestSuite(t *testing.T) {
+	suite.Run(t, new(PunctuationTestSuite))
+}
@@ -0,0 +1,37 @@
+package main
+
+// Method to create a game of a random word length, for players who do not
+// want to choose one. Every playable word is equally likely to be behind the
+// game, so the lengths with more words are picked more often. The length is
+// picked with the engine source, see SetRandSource.
+// Returns an error wrapping ErrInvalidLength if no length can be played, or
+// any error of NewGame.
+func NewGameRandomLength(maxretries int, opts ...GameOption) (*Game, error) {
+	length, ok := pickRandomLength(currentDictionary(), randIntn)
+	if !ok {
+		return nil, newGameError(ErrInvalidLength, "No word length can be played")
+	}
+	return NewGame(length, maxretries, opts...)
+}
+
+// Method to pick a playable word length of the dictionary, weighted by its
+// number of words. intn returns a random number in [0, n).
+// Returns false if the dictionary has no playable length.
+func pickRandomLength(dict *Dictionary, intn func(n int) int) (int, bool) {
+	lengths := dict.AvailableLengths()
+	total := 0
+	for _, length := range lengths {
+		total += dict.Count(length)
+	}
+	if total == 0 {
+		return 0, false
+	}
+	pick := intn(total)
+	for _, length := range lengths {
+		if pick < dict.Count(length) {
+			return length, true
+		}
+		pick -= dict.Count(length)
+	}
+	return 0, false
+}
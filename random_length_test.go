@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type RandomLengthTestSuite struct {
+	suite.Suite
+}
+
+func (s *RandomLengthTestSuite) SetupTest() {
+	InitGame([]string{"cat", "last", "fast", "bets", "code", "horse"})
+}
+
+func (s *RandomLengthTestSuite) TestPickWeighted() {
+	dict := currentDictionary()
+	// 1 word of 3 letters, 4 of 4 letters and 1 of 5 letters.
+	expected := []int{3, 4, 4, 4, 4, 5}
+	for pick, length := range expected {
+		got, ok := pickRandomLength(dict, func(n int) int {
+			assert.Equal(s.T(), 6, n)
+			return pick
+		})
+		assert.True(s.T(), ok)
+		assert.Equal(s.T(), length, got)
+	}
+}
+
+func (s *RandomLengthTestSuite) TestPickAvailableOnly() {
+	*maxWordLength = 4
+	defer func() { *maxWordLength = 0 }()
+	got, ok := pickRandomLength(currentDictionary(), func(n int) int {
+		assert.Equal(s.T(), 5, n)
+		return n - 1
+	})
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), 4, got)
+
+	*maxWordLength = 2
+	*minWordLength = 2
+	defer func() { *minWordLength = 1 }()
+	_, ok = pickRandomLength(currentDictionary(), func(n int) int { return 0 })
+	assert.False(s.T(), ok)
+	_, err := NewGameRandomLength(3)
+	assert.True(s.T(), errors.Is(err, ErrInvalidLength))
+}
+
+func (s *RandomLengthTestSuite) TestNewGame() {
+	game, err := NewGameRandomLength(3, WithRetryPolicy(RetryLenient))
+	assert.Nil(s.T(), err)
+	assert.Contains(s.T(), []int{3, 4, 5}, game.ExpectedLength)
+	assert.Equal(s.T(), 3, game.AllowedRetries)
+	assert.Equal(s.T(), RetryLenient, game.RetryPolicy)
+
+	_, err = NewGameRandomLength(-1)
+	assert.True(s.T(), errors.Is(err, ErrInvalidRetries))
+}
+
+func TestRandomLengthTestSuite(t *testing.T) {
+	suite.Run(t, new(RandomLengthTestSuite))
+}
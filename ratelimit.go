@@ -0,0 +1,220 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	createLimit = flag.String("create_limit", "30/m",
+		"Max number of games a client (by IP address) can create on the server, "+
+			"e.g. \"30/m\" for 30 a minute, or \"100/h\". The games are not limited "+
+			"if empty.")
+	guessLimit = flag.String("guess_limit", "300/m",
+		"Max number of guesses a client (by IP address) can make on the server, "+
+			"over HTTP and WebSocket. The guesses are not limited if empty.")
+	gameGuessLimit = flag.String("game_guess_limit", "5/s",
+		"Max number of guesses made on a single game of the server, by all its "+
+			"players together. The guesses are not limited if empty.")
+	trustForwardedFor = flag.Bool("trust_forwarded_for", false,
+		"Take the IP address of the clients from the X-Forwarded-For header, when "+
+			"the server runs behind a reverse proxy setting it. The header is "+
+			"ignored otherwise, as the clients could fake it.")
+)
+
+// Number of events allowed per period, e.g. 30 a minute. The events can be
+// made in a burst, and are allowed again little by little as time passes.
+type rateLimit struct {
+	count  int
+	period time.Duration
+}
+
+// Method to parse a rate limit like "30/m": a count, and a period which is
+// "s", "m", "h" or a duration like "10s".
+func parseRateLimit(value string) (rateLimit, error) {
+	fields := strings.Split(value, "/")
+	if len(fields) != 2 {
+		return rateLimit{}, fmt.Errorf("invalid rate limit %q, expected e.g. \"30/m\"", value)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+	if err != nil || count <= 0 {
+		return rateLimit{}, fmt.Errorf("invalid count %q of the rate limit, expected a "+
+			"positive number", fields[0])
+	}
+	var period time.Duration
+	switch unit := strings.TrimSpace(fields[1]); unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		if period, err = time.ParseDuration(unit); err != nil || period <= 0 {
+			return rateLimit{}, fmt.Errorf("invalid period %q of the rate limit, expected "+
+				"s, m, h or a positive duration", unit)
+		}
+	}
+	return rateLimit{count: count, period: period}, nil
+}
+
+// Tokens left to a client of a rate limiter.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Rate limiter giving every key, e.g. an IP address, its own bucket of tokens:
+// every event takes a token, and the bucket is filled again at the rate of the
+// limit. A nil rate limiter allows everything.
+type rateLimiter struct {
+	limit rateLimit
+	// Guards the fields below.
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	// Time the full buckets were last forgotten.
+	swept time.Time
+}
+
+func newRateLimiter(limit rateLimit) *rateLimiter {
+	return &rateLimiter{limit: limit, buckets: make(map[string]*tokenBucket)}
+}
+
+// Method to create a rate limiter from the value of a flag.
+// Returns nil if the value is empty, i.e. nothing is limited.
+func rateLimiterFromFlag(name, value string) (*rateLimiter, error) {
+	if value == "" {
+		return nil, nil
+	}
+	limit, err := parseRateLimit(value)
+	if err != nil {
+		return nil, fmt.Errorf("--%s: %v", name, err)
+	}
+	return newRateLimiter(limit), nil
+}
+
+// Method to take a token of a key at the given time.
+// Returns zero if the event is allowed, or else the time to wait before the
+// next token.
+func (l *rateLimiter) take(key string, now time.Time) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweepLocked(now)
+	capacity := float64(l.limit.count)
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updated: now}
+		l.buckets[key] = bucket
+	}
+	// Tokens added per nanosecond.
+	rate := capacity / float64(l.limit.period)
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		bucket.tokens = math.Min(capacity, bucket.tokens+float64(elapsed)*rate)
+		bucket.updated = now
+	}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - bucket.tokens) / rate))
+}
+
+// Method to forget the buckets which are full again, at most once per period,
+// so that the memory does not grow with the number of clients. Must be
+// called with the lock held.
+func (l *rateLimiter) sweepLocked(now time.Time) {
+	if now.Sub(l.swept) < l.limit.period {
+		return
+	}
+	l.swept = now
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= l.limit.period {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Rate limits of the server. The nil limiters do not limit anything.
+type serverLimits struct {
+	// Games created by every IP address.
+	create *rateLimiter
+	// Guesses made by every IP address, and on every game.
+	guess     *rateLimiter
+	gameGuess *rateLimiter
+	// True to take the IP address of the clients from X-Forwarded-For.
+	trustForwardedFor bool
+}
+
+// Method to get the rate limits of the server configured by the flags.
+func serverLimitsFromFlags() (serverLimits, error) {
+	limits := serverLimits{trustForwardedFor: *trustForwardedFor}
+	var err error
+	if limits.create, err = rateLimiterFromFlag("create_limit", *createLimit); err != nil {
+		return serverLimits{}, err
+	}
+	if limits.guess, err = rateLimiterFromFlag("guess_limit", *guessLimit); err != nil {
+		return serverLimits{}, err
+	}
+	if limits.gameGuess, err = rateLimiterFromFlag("game_guess_limit", *gameGuessLimit); err != nil {
+		return serverLimits{}, err
+	}
+	return limits, nil
+}
+
+// Method to get the IP address of the client of a request.
+func (l serverLimits) clientIP(r *http.Request) string {
+	if l.trustForwardedFor {
+		// The proxy appends the address it got the request from, so the first
+		// address is the client, as seen by the first proxy.
+		forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")[0]
+		if ip := strings.TrimSpace(forwarded); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Method to build the error of a request over a rate limit, with the time to
+// wait before trying again.
+func rateLimitedError(what string, wait time.Duration) *api.Error {
+	seconds := int(math.Ceil(wait.Seconds()))
+	return api.NewError(api.CodeRateLimited, "too many %s, try again in %d seconds",
+		what, seconds).WithDetail("retry_after_seconds", seconds)
+}
+
+// Method to check if a client can create a game.
+// Returns an error if the client created too many games recently.
+func (l serverLimits) allowCreate(ip string, now time.Time) *api.Error {
+	if wait := l.create.take(ip, now); wait > 0 {
+		return rateLimitedError("games created", wait)
+	}
+	return nil
+}
+
+// Method to check if a client can guess on a game.
+// Returns an error if the client, or the players of the game, guessed too
+// many times recently.
+func (l serverLimits) allowGuess(ip, id string, now time.Time) *api.Error {
+	if wait := l.guess.take(ip, now); wait > 0 {
+		return rateLimitedError("guesses", wait)
+	}
+	if wait := l.gameGuess.take(id, now); wait > 0 {
+		return rateLimitedError("guesses on this game", wait)
+	}
+	return nil
+}
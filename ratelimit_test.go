@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type RateLimitTestSuite struct {
+	suite.Suite
+	gameServer *gameServer
+	server     *httptest.Server
+}
+
+func (s *RateLimitTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+	s.gameServer = newGameServer()
+	s.server = httptest.NewServer(s.gameServer.Handler())
+}
+
+func (s *RateLimitTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// Method to post a request to the server and decode the response.
+func (s *RateLimitTestSuite) post(path, body string, out interface{}) *http.Response {
+	resp, err := http.Post(s.server.URL+path, "application/json", bytes.NewBufferString(body))
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp
+}
+
+func (s *RateLimitTestSuite) TestParse() {
+	limit, err := parseRateLimit("30/m")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), rateLimit{count: 30, period: time.Minute}, limit)
+	limit, err = parseRateLimit("5 / 10s")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), rateLimit{count: 5, period: 10 * time.Second}, limit)
+	for _, value := range []string{"30", "0/m", "-1/s", "x/m", "3/week", "3/-1s", "1/2/s"} {
+		_, err = parseRateLimit(value)
+		assert.NotNil(s.T(), err, value)
+	}
+	limiter, err := rateLimiterFromFlag("guess_limit", "")
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), limiter)
+	assert.Equal(s.T(), time.Duration(0), limiter.take("anyone", time.Now()))
+}
+
+func (s *RateLimitTestSuite) TestTokenBucket() {
+	limiter := newRateLimiter(rateLimit{count: 2, period: time.Minute})
+	now := time.Now()
+	assert.Equal(s.T(), time.Duration(0), limiter.take("a", now))
+	assert.Equal(s.T(), time.Duration(0), limiter.take("a", now))
+	assert.Equal(s.T(), 30*time.Second, limiter.take("a", now))
+	// Every key has its own bucket.
+	assert.Equal(s.T(), time.Duration(0), limiter.take("b", now))
+
+	// A token is back every 30 seconds.
+	assert.Equal(s.T(), 10*time.Second, limiter.take("a", now.Add(20*time.Second)))
+	assert.Equal(s.T(), time.Duration(0), limiter.take("a", now.Add(30*time.Second)))
+
+	// The buckets full again are forgotten.
+	limiter.take("c", now.Add(2*time.Minute))
+	assert.Len(s.T(), limiter.buckets, 1)
+}
+
+func (s *RateLimitTestSuite) TestClientIP() {
+	r := httptest.NewRequest("GET", "/games", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.2")
+	assert.Equal(s.T(), "10.0.0.1", serverLimits{}.clientIP(r))
+	assert.Equal(s.T(), "203.0.113.7", serverLimits{trustForwardedFor: true}.clientIP(r))
+	r.Header.Del("X-Forwarded-For")
+	assert.Equal(s.T(), "10.0.0.1", serverLimits{trustForwardedFor: true}.clientIP(r))
+}
+
+func (s *RateLimitTestSuite) TestCreateLimit() {
+	s.gameServer.limits.create = newRateLimiter(rateLimit{count: 2, period: time.Hour})
+	var game api.Game
+	for i := 0; i < 2; i++ {
+		resp := s.post("/games", `{"word_length": 4, "retries": 3}`, &game)
+		assert.Equal(s.T(), http.StatusCreated, resp.StatusCode)
+	}
+	var errResp api.ErrorResponse
+	resp := s.post("/games", `{"word_length": 4, "retries": 3}`, &errResp)
+	assert.Equal(s.T(), http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(s.T(), api.CodeRateLimited, errResp.Error.Code)
+	assert.Equal(s.T(), "1800", resp.Header.Get("Retry-After"))
+	assert.Equal(s.T(), 2, s.gameServer.store.(*SessionManager).Len())
+}
+
+func (s *RateLimitTestSuite) TestGuessLimit() {
+	s.gameServer.limits.gameGuess = newRateLimiter(rateLimit{count: 1, period: time.Hour})
+	var game api.Game
+	s.post("/games", `{"word_length": 4, "retries": 3}`, &game)
+	var other api.Game
+	s.post("/games", `{"word_length": 4, "retries": 3}`, &other)
+
+	var guess api.GuessResponse
+	resp := s.post("/games/"+game.ID+"/guesses", `{"char": "x"}`, &guess)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	var errResp api.ErrorResponse
+	resp = s.post("/games/"+game.ID+"/guesses", `{"char": "y"}`, &errResp)
+	assert.Equal(s.T(), http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(s.T(), api.CodeRateLimited, errResp.Error.Code)
+	// The other games are not limited.
+	resp = s.post("/games/"+other.ID+"/guesses", `{"char": "y"}`, &guess)
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+
+	// The guess over the limit was not made.
+	s.gameServer.limits.gameGuess = nil
+	s.post("/games/"+game.ID+"/guesses", `{"char": "z"}`, &guess)
+	assert.Equal(s.T(), "xz", guess.Game.UsedChars)
+}
+
+func (s *RateLimitTestSuite) TestLargeBody() {
+	var errResp api.ErrorResponse
+	body := `{"word_length": 4, "retries": 3, "player": "` + strings.Repeat("a", maxRequestBody) + `"}`
+	resp := s.post("/games", body, &errResp)
+	assert.Equal(s.T(), http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(s.T(), api.CodeInvalidRequest, errResp.Error.Code)
+	assert.Equal(s.T(), float64(maxRequestBody), errResp.Error.Details["max_bytes"])
+}
+
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitTestSuite))
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Minimal client of the Redis protocol (RESP2), covering what the session
+// store needs: commands, transactions and pub/sub.
+
+const (
+	// Max time allowed to connect to Redis.
+	redisDialTimeout = 5 * time.Second
+	// Max time allowed for a command, reply included.
+	redisCommandTimeout = 5 * time.Second
+	// Max number of idle connections kept open.
+	redisIdleConns = 8
+	// Max size of a reply. Sessions are a few kB, up to a few MB with all the
+	// words of a long game.
+	maxRedisReply = 64 * 1024 * 1024
+)
+
+// Error replied by Redis to a command.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+var errRedisProtocol = errors.New("redis: invalid reply")
+
+// Client of a Redis server, keeping a few connections open.
+type redisClient struct {
+	addr string
+	// Sent with AUTH on every new connection if not empty.
+	password string
+	// Idle connections.
+	idle chan *redisConn
+}
+
+// Connection with a Redis server.
+type redisConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func newRedisClient(addr, password string) *redisClient {
+	return &redisClient{addr: addr, password: password,
+		idle: make(chan *redisConn, redisIdleConns)}
+}
+
+// Method to open a new connection, authenticated if the client has a password.
+func (c *redisClient) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: redisDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	rc := &redisConn{conn: conn, br: bufio.NewReader(conn)}
+	if c.password != "" {
+		if _, err := rc.do("AUTH", c.password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return rc, nil
+}
+
+// Method to run a function with a connection of its own, e.g. for a
+// transaction. The connection is kept open for the next calls unless an error
+// other than a Redis error reply was returned, since the connection may then
+// be left in an unknown state. Once the context is done, the connection is
+// closed so that the command waiting for its reply fails, and the context
+// error is returned.
+func (c *redisClient) withConn(ctx context.Context, f func(rc *redisConn) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var rc *redisConn
+	select {
+	case rc = <-c.idle:
+	default:
+		var err error
+		if rc, err = c.dial(ctx); err != nil {
+			return err
+		}
+	}
+	stop := context.AfterFunc(ctx, func() {
+		rc.conn.Close()
+	})
+	err := f(rc)
+	if !stop() {
+		// The connection was closed, whatever f returned.
+		return ctx.Err()
+	}
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		rc.conn.Close()
+		return err
+	}
+	select {
+	case c.idle <- rc:
+	default:
+		rc.conn.Close()
+	}
+	return err
+}
+
+// Method to run a command. See redisConn.do for the types of the reply.
+func (c *redisClient) do(ctx context.Context, args ...string) (interface{}, error) {
+	var reply interface{}
+	err := c.withConn(ctx, func(rc *redisConn) error {
+		var err error
+		reply, err = rc.do(args...)
+		return err
+	})
+	return reply, err
+}
+
+// Method to close the idle connections.
+func (c *redisClient) Close() {
+	for {
+		select {
+		case rc := <-c.idle:
+			rc.conn.Close()
+		default:
+			return
+		}
+	}
+}
+
+// Method to run a command and read its reply, which is a string for a simple
+// or bulk string, an int64 for an integer, a []interface{} for an array, and
+// nil for a null bulk string or array. An error reply is returned as a
+// redisError.
+func (rc *redisConn) do(args ...string) (interface{}, error) {
+	if err := rc.send(args...); err != nil {
+		return nil, err
+	}
+	rc.conn.SetReadDeadline(time.Now().Add(redisCommandTimeout))
+	return rc.read()
+}
+
+// Method to send a command without waiting for its reply.
+func (rc *redisConn) send(args ...string) error {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	rc.conn.SetWriteDeadline(time.Now().Add(redisCommandTimeout))
+	_, err := rc.conn.Write(buf)
+	return err
+}
+
+// Method to read a reply, see do.
+func (rc *redisConn) read() (interface{}, error) {
+	line, err := rc.br.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errRedisProtocol
+	}
+	kind, value := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return value, nil
+	case '-':
+		return nil, redisError(value)
+	case ':':
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errRedisProtocol
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(value)
+		if err != nil || n > maxRedisReply {
+			return nil, errRedisProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rc.br, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errRedisProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			// A transaction replies an item per command, an error reply of
+			// one of them does not end the array.
+			items[i], err = rc.read()
+			var replyErr redisError
+			if errors.As(err, &replyErr) {
+				items[i] = replyErr
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, errRedisProtocol
+}
+
+// Subscription to a Redis channel, which calls a function with every message
+// published to it. The connection is opened again if it is lost.
+type redisSubscription struct {
+	client  *redisClient
+	channel string
+	handle  func(payload string)
+
+	// Guards the fields below.
+	mu      sync.Mutex
+	conn    *redisConn
+	stopped bool
+	// Closed once the subscription stopped.
+	done chan struct{}
+}
+
+// Method to subscribe to a channel. Messages are handled one at a time, in
+// order, till Stop is called.
+func (c *redisClient) subscribe(channel string, handle func(payload string)) *redisSubscription {
+	sub := &redisSubscription{client: c, channel: channel, handle: handle,
+		done: make(chan struct{})}
+	go sub.run()
+	return sub
+}
+
+func (sub *redisSubscription) run() {
+	defer close(sub.done)
+	for {
+		err := sub.listen()
+		sub.mu.Lock()
+		stopped := sub.stopped
+		sub.mu.Unlock()
+		if stopped {
+			return
+		}
+		defaultLogger.Errorf("Lost the subscription to Redis channel %s, error %v",
+			sub.channel, err)
+		time.Sleep(time.Second)
+	}
+}
+
+// Method to read the messages of the channel till the connection fails.
+func (sub *redisSubscription) listen() error {
+	rc, err := sub.client.dial(context.Background())
+	if err != nil {
+		return err
+	}
+	defer rc.conn.Close()
+	sub.mu.Lock()
+	if sub.stopped {
+		sub.mu.Unlock()
+		return nil
+	}
+	sub.conn = rc
+	sub.mu.Unlock()
+	// Messages can be published at any time, the reads never time out.
+	rc.conn.SetReadDeadline(time.Time{})
+	if err := rc.send("SUBSCRIBE", sub.channel); err != nil {
+		return err
+	}
+	for {
+		reply, err := rc.read()
+		if err != nil {
+			return err
+		}
+		items, ok := reply.([]interface{})
+		if !ok || len(items) != 3 {
+			return fmt.Errorf("unexpected pub/sub reply %v", reply)
+		}
+		if kind, _ := items[0].(string); kind != "message" {
+			continue
+		}
+		if payload, ok := items[2].(string); ok {
+			sub.handle(payload)
+		}
+	}
+}
+
+// Method to stop the subscription, waiting for the message being handled.
+func (sub *redisSubscription) Stop() {
+	sub.mu.Lock()
+	sub.stopped = true
+	if sub.conn != nil {
+		sub.conn.conn.Close()
+	}
+	sub.mu.Unlock()
+	<-sub.done
+}
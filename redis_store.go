@@ -0,0 +1,419 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	redisAddr = flag.String("redis_addr", "",
+		"Address (host:port) of a Redis server keeping the games of the server, so "+
+			"that several servers behind a load balancer can serve the same games. "+
+			"The games are kept in the memory of the server if empty.")
+	redisPassword = flag.String("redis_password", "",
+		"Password of the Redis server, if it requires one.")
+	redisPrefix = flag.String("redis_prefix", "hangman:",
+		"Prefix of the Redis keys, so that several deployments can share a Redis "+
+			"server.")
+)
+
+// Session as saved in Redis, encoded as JSON.
+type redisSession struct {
+	ID string `json:"id"`
+	// Incremented by every save, to detect the concurrent changes.
+	Version int64 `json:"version"`
+	// State of the game, with its candidates so that the game goes on with
+	// the same words even if the dictionary changes.
+	Game Snapshot `json:"game"`
+	// Word shown when the game started, the class of the game in the hall of
+	// shame.
+	Start        string        `json:"start"`
+	GuessTimeout time.Duration `json:"guess_timeout,omitempty"`
+	Practice     bool          `json:"practice,omitempty"`
+	Preset       string        `json:"preset,omitempty"`
+	Player       string        `json:"player,omitempty"`
+	Created      time.Time     `json:"created"`
+	Challenge    bool          `json:"challenge,omitempty"`
+	Lifetime     time.Duration `json:"lifetime,omitempty"`
+	ExpiresAt    time.Time     `json:"expires_at,omitempty"`
+	Deadline     time.Time     `json:"deadline,omitempty"`
+	// Players of a cooperative game, with the turn.
+	Coop *coopSeats `json:"coop,omitempty"`
+}
+
+// Session store keeping the games in Redis, so that several servers can serve
+// them. Every server keeps the sessions it serves in memory, for their
+// watchers and expiry timers, and brings them up to date when they are looked
+// up. The saves use optimistic locking: a guess made on a game changed by
+// another server since it was loaded fails with errSessionConflict, and is made
+// again on the new state. Every save is published to the other servers, which
+// send the new state to the watchers of the game.
+type redisStore struct {
+	client *redisClient
+	prefix string
+	// Idle time after which a game is deleted from Redis, zero to keep the
+	// games forever. Correspondence games are kept for at least their lifetime.
+	ttl time.Duration
+	// Called to set up the sessions loaded from Redis, see
+	// gameServer.initSession.
+	init func(sess *session)
+
+	// Guards the lookups of the local sessions, so that a session is only
+	// loaded once.
+	mu sync.Mutex
+	// Sessions served by this server.
+	local *SessionManager
+}
+
+// Method to create a store keeping the games in Redis, and the sessions served
+// by this server in a session manager. See start.
+func newRedisStore(client *redisClient, prefix string, ttl time.Duration,
+	local *SessionManager) *redisStore {
+	return &redisStore{client: client, prefix: prefix, ttl: ttl, local: local}
+}
+
+// Method to start following the changes made by the other servers and
+// evicting the idle local sessions. Returns a function stopping it, which also
+// closes the connections to Redis.
+func (s *redisStore) start() func() {
+	sub := s.client.subscribe(s.channel(), s.handleUpdate)
+	stopEviction := s.local.StartEviction(maxEvictionInterval)
+	return func() {
+		stopEviction()
+		sub.Stop()
+		s.client.Close()
+	}
+}
+
+// Method to get the key of a session.
+func (s *redisStore) key(id string) string {
+	return s.prefix + "game:" + id
+}
+
+// Method to get the channel the saves are published to.
+func (s *redisStore) channel() string {
+	return s.prefix + "updates"
+}
+
+// Method to get the time, in milliseconds, a session is kept in Redis after
+// its last use. Zero if it is kept forever.
+func (s *redisStore) expiryMillis(rec redisSession) string {
+	if s.ttl <= 0 {
+		return ""
+	}
+	ttl := s.ttl
+	if rec.Lifetime > ttl {
+		ttl = rec.Lifetime
+	}
+	return strconv.FormatInt(ttl.Milliseconds(), 10)
+}
+
+// Method to get the arguments of the SET command of a session.
+func (s *redisStore) setArgs(rec redisSession, data []byte, extra ...string) []string {
+	args := append([]string{"SET", s.key(rec.ID), string(data)}, extra...)
+	if ms := s.expiryMillis(rec); ms != "" {
+		args = append(args, "PX", ms)
+	}
+	return args
+}
+
+func (s *redisStore) add(ctx context.Context, sess *session) error {
+	sess.mu.Lock()
+	rec := redisRecordLocked(sess)
+	sess.mu.Unlock()
+	rec.Version = 1
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	reply, err := s.client.do(ctx, s.setArgs(rec, data, "NX")...)
+	if err != nil {
+		return err
+	}
+	if reply == nil {
+		return fmt.Errorf("game %s already exists", sess.id)
+	}
+	sess.mu.Lock()
+	sess.version = rec.Version
+	sess.mu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.local.add(ctx, sess)
+}
+
+// Method to find a session by its id. A session already served by this server
+// is brought up to date, and its watchers get the new state.
+func (s *redisStore) get(ctx context.Context, id string) (*session, error) {
+	reply, err := s.client.do(ctx, "GET", s.key(id))
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, errSessionNotFound
+	}
+	rec, err := decodeRedisSession(reply)
+	if err != nil {
+		return nil, err
+	}
+	if ms := s.expiryMillis(rec); ms != "" {
+		if _, err := s.client.do(ctx, "PEXPIRE", s.key(id), ms); err != nil {
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	sess, err := s.local.get(ctx, id)
+	if err == nil {
+		s.mu.Unlock()
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		if err := s.applyLocked(sess, rec); err != nil {
+			return nil, err
+		}
+		return sess, nil
+	}
+	defer s.mu.Unlock()
+	if sess, err = s.newSession(rec); err != nil {
+		return nil, err
+	}
+	if err := s.local.add(ctx, sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// Method to save a session, checking that its game was not changed by another
+// server since it was loaded. The save is then published to the other
+// servers. Must be called with the session lock held.
+func (s *redisStore) save(ctx context.Context, sess *session) error {
+	rec := redisRecordLocked(sess)
+	rec.Version = sess.version + 1
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	key := s.key(sess.id)
+	// Set if the save failed because of the game or of another server.
+	var failure error
+	// Game saved by another server, if any.
+	var current interface{}
+	err = s.client.withConn(ctx, func(rc *redisConn) error {
+		if _, err := rc.do("WATCH", key); err != nil {
+			return err
+		}
+		reply, err := rc.do("GET", key)
+		if err != nil {
+			return err
+		}
+		if reply == nil {
+			failure = errSessionNotFound
+			_, err := rc.do("UNWATCH")
+			return err
+		}
+		saved, err := decodeRedisSession(reply)
+		if err != nil {
+			rc.do("UNWATCH")
+			return err
+		}
+		if saved.Version != sess.version {
+			failure, current = errSessionConflict, reply
+			_, err := rc.do("UNWATCH")
+			return err
+		}
+		if _, err := rc.do("MULTI"); err != nil {
+			return err
+		}
+		if _, err := rc.do(s.setArgs(rec, data)...); err != nil {
+			rc.do("DISCARD")
+			return err
+		}
+		reply, err = rc.do("EXEC")
+		if err != nil {
+			return err
+		}
+		if reply == nil {
+			// The game was saved between the GET and the EXEC.
+			failure = errSessionConflict
+			current, err = rc.do("GET", key)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if failure == errSessionConflict && current != nil {
+		saved, err := decodeRedisSession(current)
+		if err != nil {
+			return err
+		}
+		if err := s.applyLocked(sess, saved); err != nil {
+			return err
+		}
+	}
+	if failure != nil {
+		return failure
+	}
+	sess.version = rec.Version
+	// The game is saved, so it is published even if the request is canceled.
+	publishCtx := context.WithoutCancel(ctx)
+	if _, err := s.client.do(publishCtx, "PUBLISH", s.channel(), string(data)); err != nil {
+		// The other servers get the new state at the next request for the
+		// game, only their watchers miss it.
+		defaultLogger.Errorf("Unable to publish the update of game %s, error %v",
+			sess.id, err)
+	}
+	return nil
+}
+
+// Method to handle a save published by a server: the watchers of the game on
+// this server get the new state.
+func (s *redisStore) handleUpdate(payload string) {
+	rec, err := decodeRedisSession(payload)
+	if err != nil {
+		defaultLogger.Errorf("Skipping invalid game update, error %v", err)
+		return
+	}
+	sess, ok := s.local.peek(rec.ID)
+	if !ok {
+		return
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if err := s.applyLocked(sess, rec); err != nil {
+		defaultLogger.Errorf("Unable to update game %s, error %v", rec.ID, err)
+	}
+}
+
+// Method to build a session served by this server from the saved one.
+func (s *redisStore) newSession(rec redisSession) (*session, error) {
+	// The guess timeout is set first, so that the current turn gets its full
+	// time on this server.
+	game := &Game{GuessTimeout: rec.GuessTimeout}
+	if err := game.restore(rec.Game); err != nil {
+		return nil, fmt.Errorf("invalid game %s: %v", rec.ID, err)
+	}
+	game.replayStart = rec.Start
+	sess := &session{
+		id:        rec.ID,
+		game:      game,
+		practice:  rec.Practice,
+		preset:    rec.Preset,
+		player:    rec.Player,
+		created:   rec.Created,
+		challenge: rec.Challenge,
+		lifetime:  rec.Lifetime,
+		deadline:  rec.Deadline,
+		coop:      rec.Coop.clone(),
+		version:   rec.Version,
+		watchers:  make(map[*watcher]bool),
+		store:     s,
+	}
+	if s.init != nil {
+		s.init(sess)
+	}
+	if game.State == Running {
+		gameMetrics.sessionStarted()
+	}
+	sess.mu.Lock()
+	sess.restoreExpiryLocked(rec.ExpiresAt, time.Now())
+	sess.mu.Unlock()
+	return sess, nil
+}
+
+// Method to bring a session up to date with a saved one, if it is more recent.
+// The watchers get the new state. Must be called with the session lock held.
+func (s *redisStore) applyLocked(sess *session, rec redisSession) error {
+	if rec.Version <= sess.version {
+		return nil
+	}
+	game := sess.game
+	wasRunning := game.State == Running
+	guesses := len(game.UsedChars)
+	if err := game.restore(rec.Game); err != nil {
+		return fmt.Errorf("invalid game %s: %v", rec.ID, err)
+	}
+	game.replayStart = rec.Start
+	game.GuessTimeout = rec.GuessTimeout
+	sess.version = rec.Version
+	sess.coop = rec.Coop.clone()
+	// The game ended by this server, if any, was replaced by the saved one.
+	sess.ended = false
+	sess.preview = nil
+	// Games finished or restarted by a guess made again after a conflict.
+	if running := game.State == Running; running && !wasRunning {
+		gameMetrics.sessionStarted()
+	} else if !running && wasRunning {
+		gameMetrics.sessionEnded()
+	}
+	sess.restoreExpiryLocked(rec.ExpiresAt, time.Now())
+	view := sess.viewLocked()
+	msg := api.Message{Type: api.MessageState, Game: &view}
+	if len(game.UsedChars) > guesses {
+		char := game.UsedChars[len(game.UsedChars)-1]
+		accepted := contains(game.CurrentDisplayedWord, char)
+		msg.LastGuess, msg.Accepted = string(char), &accepted
+	}
+	sess.broadcastLocked(msg)
+	if sess.practice && len(sess.watchers) > 0 {
+		sess.broadcastLocked(api.Message{
+			Type:    api.MessagePreview,
+			Preview: sess.previewLocked(),
+		})
+	}
+	return nil
+}
+
+// Method to build the saved session of a session, without its version. Must be
+// called with the session lock held.
+func redisRecordLocked(sess *session) redisSession {
+	rec := redisSession{
+		ID:           sess.id,
+		Game:         sess.game.Snapshot(true),
+		Start:        sess.game.replayStart,
+		GuessTimeout: sess.game.GuessTimeout,
+		Practice:     sess.practice,
+		Preset:       sess.preset,
+		Player:       sess.player,
+		Created:      sess.created,
+		Challenge:    sess.challenge,
+		Lifetime:     sess.lifetime,
+		Deadline:     sess.deadline,
+		Coop:         sess.coop.clone(),
+	}
+	if sess.lifetime > 0 {
+		rec.ExpiresAt = sess.expiresAt
+	}
+	return rec
+}
+
+// Method to decode a saved session from the reply of a GET command.
+func decodeRedisSession(reply interface{}) (redisSession, error) {
+	var rec redisSession
+	data, ok := reply.(string)
+	if !ok {
+		return rec, fmt.Errorf("unexpected reply %v", reply)
+	}
+	if err := json.Unmarshal([]byte(data), &rec); err != nil {
+		return rec, fmt.Errorf("invalid saved game: %v", err)
+	}
+	return rec, nil
+}
+
+// Method to create the Redis session store given by the flags, checking that
+// Redis can be reached. Returns nil if the games are kept in memory.
+func redisStoreFromFlags() (*redisStore, error) {
+	if *redisAddr == "" {
+		return nil, nil
+	}
+	client := newRedisClient(*redisAddr, *redisPassword)
+	if _, err := client.do(context.Background(), "PING"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("unable to reach Redis at %s: %v", *redisAddr, err)
+	}
+	return newRedisStore(client, *redisPrefix, *sessionTTL, newServerSessionManager()), nil
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// In-memory Redis server covering the commands used by the session store.
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	// Incremented by every write of a key, to check the watched keys.
+	writes      map[string]int
+	subscribers map[string][]*fakeRedisConn
+	// Called before running a transaction, nil if not set.
+	beforeExec func()
+}
+
+// Connection of a client with the fake server.
+type fakeRedisConn struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+	watched map[string]int
+	// Commands of the transaction in progress, nil if there is none.
+	queued [][]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &fakeRedis{listener: listener, data: make(map[string]string),
+		writes: make(map[string]int), subscribers: make(map[string][]*fakeRedisConn)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go r.serve(&fakeRedisConn{conn: conn, watched: make(map[string]int)})
+		}
+	}()
+	return r
+}
+
+func (r *fakeRedis) addr() string {
+	return r.listener.Addr().String()
+}
+
+func (r *fakeRedis) serve(c *fakeRedisConn) {
+	defer c.conn.Close()
+	br := bufio.NewReader(c.conn)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		for i := range args {
+			br.ReadString('\n')
+			arg, _ := br.ReadString('\n')
+			args[i] = strings.TrimSuffix(arg, "\r\n")
+		}
+		c.write(r.handle(c, args))
+	}
+}
+
+func (c *fakeRedisConn) write(reply string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.Write([]byte(reply))
+}
+
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+func (r *fakeRedis) handle(c *fakeRedisConn, args []string) string {
+	command := strings.ToUpper(args[0])
+	if c.queued != nil && command != "EXEC" && command != "DISCARD" {
+		c.queued = append(c.queued, args)
+		return "+QUEUED\r\n"
+	}
+	switch command {
+	case "MULTI":
+		c.queued = [][]string{}
+		return "+OK\r\n"
+	case "DISCARD":
+		c.queued = nil
+		c.watched = make(map[string]int)
+		return "+OK\r\n"
+	case "EXEC":
+		if r.beforeExec != nil {
+			r.beforeExec()
+		}
+		queued := c.queued
+		c.queued = nil
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for key, writes := range c.watched {
+			if r.writes[key] != writes {
+				c.watched = make(map[string]int)
+				return "*-1\r\n"
+			}
+		}
+		c.watched = make(map[string]int)
+		reply := "*" + strconv.Itoa(len(queued)) + "\r\n"
+		for _, args := range queued {
+			reply += r.run(args)
+		}
+		return reply
+	case "WATCH":
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, key := range args[1:] {
+			c.watched[key] = r.writes[key]
+		}
+		return "+OK\r\n"
+	case "UNWATCH":
+		c.watched = make(map[string]int)
+		return "+OK\r\n"
+	case "SUBSCRIBE":
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.subscribers[args[1]] = append(r.subscribers[args[1]], c)
+		return "*3\r\n" + bulk("subscribe") + bulk(args[1]) + ":1\r\n"
+	case "PUBLISH":
+		r.mu.Lock()
+		subscribers := r.subscribers[args[1]]
+		r.mu.Unlock()
+		for _, sub := range subscribers {
+			sub.write("*3\r\n" + bulk("message") + bulk(args[1]) + bulk(args[2]))
+		}
+		return ":" + strconv.Itoa(len(subscribers)) + "\r\n"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.run(args)
+}
+
+// Method to run a command on the data. The lock must be held.
+func (r *fakeRedis) run(args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		value, ok := r.data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(value)
+	case "SET":
+		if _, ok := r.data[args[1]]; ok && len(args) > 3 && args[3] == "NX" {
+			return "$-1\r\n"
+		}
+		r.data[args[1]] = args[2]
+		r.writes[args[1]]++
+		return "+OK\r\n"
+	case "PEXPIRE":
+		return ":1\r\n"
+	}
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+// Method to count the subscribers of a channel.
+func (r *fakeRedis) subscriberCount(channel string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.subscribers[channel])
+}
+
+// Method to write a key as another server would.
+func (r *fakeRedis) set(key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data[key] = value
+	r.writes[key]++
+}
+
+type RedisStoreTestSuite struct {
+	suite.Suite
+	redis *fakeRedis
+	// Two servers sharing the Redis server.
+	stores  []*redisStore
+	servers []*httptest.Server
+	stops   []func()
+}
+
+func (s *RedisStoreTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+	s.redis = newFakeRedis(s.T())
+	s.stores, s.servers, s.stops = nil, nil, nil
+	for i := 0; i < 2; i++ {
+		gameServer := newGameServer()
+		store := newRedisStore(newRedisClient(s.redis.addr(), ""), "test:", time.Hour,
+			NewSessionManager(time.Hour, SessionHooks{}))
+		store.init = gameServer.initSession
+		gameServer.store = store
+		s.stores = append(s.stores, store)
+		s.servers = append(s.servers, httptest.NewServer(gameServer.Handler()))
+		s.stops = append(s.stops, store.start())
+	}
+	// Waits for the subscriptions, the updates published before are lost.
+	deadline := time.Now().Add(5 * time.Second)
+	for s.redis.subscriberCount("test:updates") < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (s *RedisStoreTestSuite) TearDownTest() {
+	for i := range s.servers {
+		s.servers[i].Close()
+		s.stops[i]()
+	}
+	s.redis.listener.Close()
+}
+
+func (s *RedisStoreTestSuite) post(server int, path, body string, out interface{}) int {
+	resp, err := http.Post(s.servers[server].URL+path, "application/json",
+		bytes.NewBufferString(body))
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+func (s *RedisStoreTestSuite) getGame(server int, id string) (api.Game, int) {
+	resp, err := http.Get(s.servers[server].URL + "/games/" + id)
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	var game api.Game
+	json.NewDecoder(resp.Body).Decode(&game)
+	return game, resp.StatusCode
+}
+
+func (s *RedisStoreTestSuite) createGame() api.Game {
+	var game api.Game
+	status := s.post(0, "/games", `{"word_length": 4, "retries": 3}`, &game)
+	assert.Equal(s.T(), http.StatusCreated, status)
+	return game
+}
+
+func (s *RedisStoreTestSuite) TestClient() {
+	client := newRedisClient(s.redis.addr(), "")
+	defer client.Close()
+	ctx := context.Background()
+	reply, err := client.do(ctx, "SET", "key", "value")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "OK", reply)
+	reply, err = client.do(ctx, "GET", "key")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "value", reply)
+	reply, err = client.do(ctx, "GET", "missing")
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), reply)
+	reply, err = client.do(ctx, "PEXPIRE", "key", "10")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), int64(1), reply)
+	_, err = client.do(ctx, "NOPE")
+	assert.Equal(s.T(), redisError("ERR unknown command 'NOPE'"), err)
+	// The connection is still usable after an error reply.
+	reply, err = client.do(ctx, "PING")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "PONG", reply)
+}
+
+func (s *RedisStoreTestSuite) TestSharedGame() {
+	game := s.createGame()
+	var guess api.GuessResponse
+	assert.Equal(s.T(), http.StatusOK, s.post(1, "/games/"+game.ID+"/guesses",
+		`{"char": "s"}`, &guess))
+	assert.Equal(s.T(), "s", guess.Game.UsedChars)
+	view, status := s.getGame(0, game.ID)
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.Equal(s.T(), "s", view.UsedChars)
+	assert.Equal(s.T(), guess.Game.MaskedWord, view.MaskedWord)
+
+	// A character guessed on another server can not be guessed again.
+	var errResp api.ErrorResponse
+	assert.Equal(s.T(), http.StatusConflict, s.post(0, "/games/"+game.ID+"/guesses",
+		`{"char": "s"}`, &errResp))
+
+	_, status = s.getGame(1, "missing")
+	assert.Equal(s.T(), http.StatusNotFound, status)
+}
+
+func (s *RedisStoreTestSuite) TestConcurrentGuesses() {
+	game := s.createGame()
+	sessions := make([]*session, 2)
+	ctx := context.Background()
+	for i, store := range s.stores {
+		var err error
+		sessions[i], err = store.get(ctx, game.ID)
+		assert.Nil(s.T(), err)
+	}
+	_, _, apiErr := sessions[0].guess(ctx, "s", "")
+	assert.Nil(s.T(), apiErr)
+	// The second server still has the game without the first guess: its save
+	// conflicts, and the guess is made again on the saved game.
+	_, view, apiErr := sessions[1].guess(ctx, "z", "")
+	assert.Nil(s.T(), apiErr)
+	assert.Equal(s.T(), "sz", view.UsedChars)
+	assert.Equal(s.T(), int64(3), sessions[1].version)
+
+	// The same when the game is saved between the check of the version and
+	// the transaction.
+	saved := true
+	s.redis.beforeExec = func() {
+		if saved {
+			saved = false
+			sessions[0].mu.Lock()
+			rec := redisRecordLocked(sessions[0])
+			sessions[0].mu.Unlock()
+			rec.Version = 4
+			rec.Game.UsedChars = "szt"
+			data, _ := json.Marshal(rec)
+			s.redis.set("test:game:"+game.ID, string(data))
+		}
+	}
+	_, view, apiErr = sessions[1].guess(ctx, "o", "")
+	assert.Nil(s.T(), apiErr)
+	assert.Equal(s.T(), "szto", view.UsedChars)
+	assert.Equal(s.T(), int64(5), sessions[1].version)
+}
+
+func (s *RedisStoreTestSuite) TestWatchersOfOtherServers() {
+	game := s.createGame()
+	watcher := dialTestWS(s.T(), s.servers[0].URL, "/games/"+game.ID+"/ws?spectate=1")
+	defer watcher.conn.Close()
+	msg := watcher.read(s.T())
+	assert.Equal(s.T(), api.MessageState, msg.Type)
+
+	s.post(1, "/games/"+game.ID+"/guesses", `{"char": "s"}`, &api.GuessResponse{})
+	msg = watcher.read(s.T())
+	assert.Equal(s.T(), api.MessageState, msg.Type)
+	assert.Equal(s.T(), "s", msg.LastGuess)
+	assert.Equal(s.T(), true, *msg.Accepted)
+	assert.Equal(s.T(), "s", msg.Game.UsedChars)
+}
+
+func (s *RedisStoreTestSuite) TestRecord() {
+	game := s.createGame()
+	sess, err := s.stores[1].get(context.Background(), game.ID)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), rawPattern("____"), sess.game.CurrentDisplayedWord)
+	assert.Equal(s.T(), "____", sess.game.replayStart)
+	assert.ElementsMatch(s.T(), []string{"last", "fast", "bets", "code"},
+		sess.game.Candidates())
+	assert.Equal(s.T(), 1, s.stores[1].local.Len())
+}
+
+func TestRedisStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(RedisStoreTestSuite))
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// One leg of a relay, played by a single teammate.
+type RelayLeg struct {
+	// Name of the teammate playing this leg.
+	Player string
+	// Length of the word to be guessed in this leg.
+	WordLength int
+	// Game of this leg. It is nil till the previous leg is won.
+	Game *Game
+}
+
+// Manager of a relay where a team solves a chain of words of increasing
+// length. Every teammate plays one word. The team shares one budget of retries
+// (and optionally of time): the retries left after a leg is won are handed to
+// the next teammate, and the relay is lost as soon as any leg is lost.
+type RelayManager struct {
+	// Legs of the relay in the order they are played.
+	Legs []*RelayLeg
+	// Total time allowed for the whole relay. Zero means no time limit.
+	TimeBudget time.Duration
+	// Current state of the relay.
+	State GameState
+
+	// Index of the leg being played.
+	current int
+	// Time at which the relay started.
+	started time.Time
+	// Options used for the game of every leg.
+	opts []GameOption
+}
+
+// Method to create a new relay.
+// Params:
+// players: Names of the teammates, in the order they play.
+// startLength: Min length of the first word. Every next word is longer than
+//   the previous one.
+// retries: Shared budget of retries for the whole team.
+// timeBudget: Shared budget of time for the whole team, zero for no limit.
+// opts: Options used for the game of every leg.
+//
+// Returns an error if there are not enough word lengths in the dictionary for
+// all the players or the number of retries is not valid.
+func NewRelay(players []string, startLength, retries int,
+	timeBudget time.Duration, opts ...GameOption) (*RelayManager, error) {
+	if len(players) == 0 {
+		return nil, errors.New("A relay needs at least one player")
+	}
+	if !validateNumRetries(retries) {
+		return nil, newGameError(ErrInvalidRetries,
+			"Invalid number of retries %d for the relay", retries)
+	}
+	r := &RelayManager{
+		TimeBudget: timeBudget,
+		State:      Running,
+		opts:       opts,
+	}
+	// Pick increasing lengths, one per player.
+	lengths := increasingLengths(startLength, len(players))
+	if len(lengths) != len(players) {
+		return nil, newGameError(ErrInvalidLength, "Not enough word lengths of at "+
+			"least %d in the dictionary for %d players", startLength, len(players))
+	}
+	for i, length := range lengths {
+		r.Legs = append(r.Legs, &RelayLeg{
+			Player:     players[i],
+			WordLength: length,
+		})
+	}
+	if err := r.startLeg(retries); err != nil {
+		return nil, err
+	}
+	r.started = r.now()
+	return r, nil
+}
+
+// Method to get the leg which is being played.
+func (r *RelayManager) CurrentLeg() *RelayLeg {
+	return r.Legs[r.current]
+}
+
+// Method to get the time left in the time budget.
+// Returns false if the relay has no time limit.
+func (r *RelayManager) TimeLeft() (time.Duration, bool) {
+	if r.TimeBudget <= 0 {
+		return 0, false
+	}
+	left := r.TimeBudget - r.now().Sub(r.started)
+	if left < 0 {
+		left = 0
+	}
+	return left, true
+}
+
+// Method to play a character in the current leg.
+// When the leg is won, the retries left are handed over to the next leg. The
+// relay is won when the last leg is won and lost as soon as a leg is lost or
+// the time budget is used up.
+// Returns true if it is a correct guess, like Game.CheckUserInput.
+func (r *RelayManager) CheckUserInput(char rune) (bool, error) {
+	if r.State != Running {
+		return false, newGameError(ErrGameFinished,
+			"Unexpected scenario: input given for a relay which is not running")
+	}
+	game := r.CurrentLeg().Game
+	if left, ok := r.TimeLeft(); ok && left == 0 {
+		r.State = Lost
+		game.forfeit()
+		return false, newGameError(ErrGameFinished,
+			"Time is up: the relay was lost before the input was given")
+	}
+	accepted, err := game.CheckUserInput(char)
+	if err != nil {
+		if game.State == Lost {
+			r.State = Lost
+		}
+		return accepted, err
+	}
+	switch game.State {
+	case Lost:
+		r.State = Lost
+	case Won:
+		if r.current == len(r.Legs)-1 {
+			r.State = Won
+			break
+		}
+		r.current++
+		if err := r.startLeg(game.CurrentRetries); err != nil {
+			return accepted, err
+		}
+	}
+	return accepted, nil
+}
+
+// Method to get the current time. The clock of the games is used so that a
+// custom clock passed as an option applies to the time budget too.
+func (r *RelayManager) now() time.Time {
+	return r.CurrentLeg().Game.clock.Now()
+}
+
+// Method to start the game of the current leg with the retries left.
+func (r *RelayManager) startLeg(retries int) error {
+	leg := r.CurrentLeg()
+	game, err := NewGame(leg.WordLength, retries, r.opts...)
+	if err != nil {
+		r.State = Lost
+		return fmt.Errorf("Unable to start the leg of %s: %w", leg.Player, err)
+	}
+	leg.Game = game
+	return nil
+}
+
+// Method to pick up to count word lengths of the dictionary in increasing
+// order, starting from the shortest one of at least startLength.
+func increasingLengths(startLength, count int) []int {
+	var lengths []int
+	for _, length := range availableLengths() {
+		if len(lengths) == count {
+			break
+		}
+		if length >= startLength {
+			lengths = append(lengths, length)
+		}
+	}
+	return lengths
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	relayPlayers = flag.String("relay_players", "",
+		"Comma separated names of the teammates to play a relay. Every teammate "+
+			"guesses one word, each longer than the previous one, and the team "+
+			"shares the retries. Normal games are played if empty.")
+	relayTimeBudget = flag.Duration("relay_time_budget", 0,
+		"Total time allowed for the whole relay, e.g. 5m. No limit if zero.")
+)
+
+// Driver method to play a relay with the teammates sharing this terminal.
+func StartRelay() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	var players []string
+	for _, player := range strings.Split(*relayPlayers, ",") {
+		if player = strings.TrimSpace(player); player != "" {
+			players = append(players, player)
+		}
+	}
+	var relay *RelayManager
+	for relay == nil {
+		fmt.Println("Relay for", strings.Join(players, ", "))
+		startLength, retries, ok, err := readGameConfig()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		relay, err = NewRelay(players, startLength, retries, *relayTimeBudget)
+		if err != nil {
+			fmt.Println(err, ". Please try again!")
+		}
+	}
+	leg := relay.CurrentLeg()
+	fmt.Println(leg.Player, "starts with a word of length", leg.WordLength)
+	for relay.State == Running {
+		game := relay.CurrentLeg().Game
+		fmt.Println(displayFormat.Format(game.CurrentDisplayedWord))
+		prompt := fmt.Sprint(relay.CurrentLeg().Player, ", enter a character ",
+			"(previous characters: ", string(game.UsedChars),
+			", remaining tries ", game.CurrentRetries)
+		if left, ok := relay.TimeLeft(); ok {
+			prompt += fmt.Sprint(", time left ", left.Round(time.Second))
+		}
+		fmt.Println(prompt, "): ")
+		char, err := readChar()
+		if err != nil {
+			return err
+		}
+		accepted, err := relay.CheckUserInput(char)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if !accepted {
+			if relay.State != Running {
+				break
+			}
+			fmt.Println("Sorry its a wrong input. Remaining tries: ", game.CurrentRetries)
+			continue
+		}
+		if game.State == Won && relay.State == Running {
+			next := relay.CurrentLeg()
+			fmt.Println(displayFormat.Format(game.CurrentDisplayedWord), "solved! Handing over",
+				next.Game.CurrentRetries, "tries to", next.Player,
+				"for a word of length", next.WordLength)
+		}
+	}
+	if relay.State == Won {
+		fmt.Println("The team finished the relay! Congratulations!!!")
+	} else {
+		fmt.Println("The relay is lost at", relay.CurrentLeg().Player+"'s leg.")
+	}
+	return nil
+}
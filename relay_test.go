@@ -0,0 +1,71 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type RelayTestSuite struct {
+	suite.Suite
+}
+
+func (s *RelayTestSuite) SetupSuite() {
+	InitGame([]string{"cat", "last", "fast", "bets", "code", "sunny"})
+}
+
+func (s *RelayTestSuite) TestIncreasingLengths() {
+	relay, err := NewRelay([]string{"ann", "bob"}, 3, 5, 0)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 3, relay.Legs[0].WordLength)
+	assert.Equal(s.T(), 4, relay.Legs[1].WordLength)
+	assert.NotNil(s.T(), relay.Legs[0].Game)
+	assert.Nil(s.T(), relay.Legs[1].Game)
+
+	// No lengths left for a third player.
+	_, err = NewRelay([]string{"ann", "bob", "cid", "dan"}, 3, 5, 0)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *RelayTestSuite) TestLeftoverRetriesHandedOver() {
+	relay, err := NewRelay([]string{"ann", "bob"}, 3, 5, 0)
+	assert.Nil(s.T(), err)
+	// "cat" is the only word of length 3, one wrong guess first.
+	for _, char := range "zcat" {
+		_, err = relay.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+	}
+	assert.Equal(s.T(), Won, relay.Legs[0].Game.State)
+	assert.Equal(s.T(), "bob", relay.CurrentLeg().Player)
+	assert.Equal(s.T(), 4, relay.CurrentLeg().Game.CurrentRetries)
+	assert.Equal(s.T(), Running, relay.State)
+}
+
+func (s *RelayTestSuite) TestLostLegLosesRelay() {
+	relay, err := NewRelay([]string{"ann", "bob"}, 4, 0, 0)
+	assert.Nil(s.T(), err)
+	_, err = relay.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), Lost, relay.State)
+	_, err = relay.CheckUserInput('a')
+	assert.NotNil(s.T(), err)
+}
+
+func (s *RelayTestSuite) TestTimeBudget() {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	relay, err := NewRelay([]string{"ann"}, 3, 5, time.Minute, WithClock(clock))
+	assert.Nil(s.T(), err)
+	left, ok := relay.TimeLeft()
+	assert.Equal(s.T(), true, ok)
+	assert.Equal(s.T(), time.Minute, left)
+
+	clock.Advance(2 * time.Minute)
+	_, err = relay.CheckUserInput('c')
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), Lost, relay.State)
+}
+
+func TestRelayTestSuite(t *testing.T) {
+	suite.Run(t, new(RelayTestSuite))
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	replayFile = flag.String("replay_file", "",
+		"Path of the file to save the replay of every game played in the terminal "+
+			"to, replaced after every game. Nothing is saved if empty.")
+	replayInterval = flag.Duration("replay_interval", 0,
+		"Time between the steps shown by the replay subcommand. A key must be "+
+			"pressed for every step if zero.")
+)
+
+// Number of final words listed by the replay subcommand.
+const replayShownWords = 20
+
+// Log of a game, step by step, which can be encoded as JSON. Unlike a
+// Snapshot it keeps the whole history, including how many words the computer
+// was still choosing from after every turn, so anyone can check that every
+// answer of the computer was consistent with the word revealed at the end.
+type Replay struct {
+	WordLength     int             `json:"word_length"`
+	AllowedRetries int             `json:"allowed_retries"`
+	RetryPolicy    api.RetryPolicy `json:"retry_policy"`
+	// Seed of the random choices of the game, see WithSeed. Replaying the
+	// guesses in a game with the same seed plays the same game.
+	Seed int64 `json:"seed"`
+	// Word shown to the player when the game started, with "_" for the
+	// characters not guessed yet. The spaces of the phrases are shown.
+	Start string `json:"start"`
+	// Number of words the computer could choose from when the game started.
+	Candidates int           `json:"candidates"`
+	Steps      []ReplayStep  `json:"steps"`
+	State      api.GameState `json:"state"`
+	Forfeited  bool          `json:"forfeited,omitempty"`
+	// Words the computer was still choosing from when the game ended, in
+	// sorted order. Nil while the game is running.
+	Words []string `json:"words,omitempty"`
+}
+
+// Turn of a replay, see Turn.
+type ReplayStep struct {
+	// "guess" or "timeout".
+	Kind string `json:"kind"`
+	// Guessed character, empty for a timeout.
+	Char string `json:"char,omitempty"`
+	// Teammate who guessed the character in a team turn.
+	Player   string `json:"player,omitempty"`
+	Accepted bool   `json:"accepted"`
+	// Word shown to the player after the turn.
+	Word string `json:"word"`
+	// Characters guessed after the turn, in order.
+	UsedChars   string `json:"used_chars"`
+	RetriesLeft int    `json:"retries_left"`
+	// Number of words the computer could still choose from after the turn.
+	Candidates int `json:"candidates"`
+}
+
+// Method to start the replay of a new game, once its first word is shown.
+// The game lock must be held.
+func (g *Game) startReplayLocked() {
+	g.replayStart = DefaultPatternFormat.Format(g.CurrentDisplayedWord)
+	g.replayCandidates = g.candidateCountLocked()
+}
+
+// Method to add a turn to the replay. The game lock must be held.
+func (g *Game) recordReplayLocked(turn Turn) {
+	step := ReplayStep{
+		Kind:        turn.Kind.String(),
+		Player:      turn.Player,
+		Accepted:    turn.Accepted,
+		Word:        DefaultPatternFormat.Format(g.CurrentDisplayedWord),
+		UsedChars:   string(g.UsedChars),
+		RetriesLeft: turn.RetriesLeft,
+		Candidates:  g.candidateCountLocked(),
+	}
+	if turn.Kind == TurnGuess {
+		step.Char = string(turn.Char)
+	}
+	g.replaySteps = append(g.replaySteps, step)
+}
+
+// Method to count the words the game is still choosing from. The game lock
+// must be held.
+func (g *Game) candidateCountLocked() int {
+	if g.packed != nil {
+		return len(g.packed)
+	}
+	return len(g.CurrentSetOfWords)
+}
+
+// Method to get the replay of the game so far. The words the computer was
+// choosing from are only included once the game is over.
+func (g *Game) Replay() Replay {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.randLocked()
+	r := Replay{
+		WordLength:     g.ExpectedLength,
+		AllowedRetries: g.AllowedRetries,
+		RetryPolicy:    g.RetryPolicy.apiPolicy(),
+		Seed:           g.seed,
+		Start:          g.replayStart,
+		Candidates:     g.replayCandidates,
+		Steps:          append([]ReplayStep{}, g.replaySteps...),
+		State:          apiGameState(g.State),
+		Forfeited:      g.State == Forfeited,
+	}
+	if g.State != Running {
+		r.Words = append([]string{}, g.candidatesLocked()...)
+		sort.Strings(r.Words)
+	}
+	return r
+}
+
+// Method to check that every answer of the replay is consistent with every
+// word the computer was choosing from at the end, i.e. that the computer
+// never changed its mind in a way the final words contradict.
+// Returns an error describing the first inconsistent step.
+func (r Replay) Verify() error {
+	start, _ := parseMaskedWord(r.Start, nil)
+	for _, word := range r.Words {
+		if len([]rune(word)) != len(start) {
+			return fmt.Errorf("word %q does not have %d characters", word, len(start))
+		}
+		for i, step := range r.Steps {
+			usedChars := []rune(step.UsedChars)
+			shown, _ := parseMaskedWord(step.Word, usedChars)
+			if jointPattern(word, start, usedChars) != string(shown) {
+				return fmt.Errorf("step %d: %q does not match the word shown, %s", i+1,
+					word, step.Word)
+			}
+			if step.Kind == TurnGuess.String() &&
+				strings.Contains(word, step.Char) != step.Accepted {
+				return fmt.Errorf("step %d: %q contradicts the answer for %q", i+1, word,
+					step.Char)
+			}
+		}
+	}
+	return nil
+}
+
+// Method to save the replay of a game as JSON.
+func saveReplay(path string, r Replay) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Method to load a replay saved by saveReplay.
+func loadReplay(path string) (Replay, error) {
+	var r Replay
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("invalid replay %s: %v", path, err)
+	}
+	return r, nil
+}
+
+// Method to describe a step of a replay.
+func formatReplayStep(i int, step ReplayStep) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Step %d: ", i+1)
+	switch {
+	case step.Kind == TurnTimeout.String():
+		b.WriteString("time ran out")
+	case step.Accepted:
+		fmt.Fprintf(&b, "%q accepted", step.Char)
+	default:
+		fmt.Fprintf(&b, "%q rejected", step.Char)
+	}
+	if step.Player != "" {
+		fmt.Fprintf(&b, " (guessed by %s)", step.Player)
+	}
+	fmt.Fprintf(&b, ", word %s, %d retries left, %d words possible", step.Word,
+		step.RetriesLeft, step.Candidates)
+	return b.String()
+}
+
+// Driver method for the "replay" subcommand, which shows a saved game step by
+// step and checks that the computer played fair.
+func StartReplay(args []string) error {
+	if len(args) != 1 {
+		return usageError("hangman replay <replay file>")
+	}
+	r, err := loadReplay(args[0])
+	if err != nil {
+		return fmt.Errorf("unable to load the replay: %w", err)
+	}
+	fmt.Println("Word of", r.WordLength, "characters,", r.AllowedRetries, "retries,",
+		r.Candidates, "words possible:", r.Start)
+	fmt.Println("Seed of the game:", r.Seed)
+	for i, step := range r.Steps {
+		if *replayInterval > 0 {
+			time.Sleep(*replayInterval)
+		} else {
+			fmt.Println("Press any key for the next step")
+			if _, err := readKey(); err != nil {
+				return err
+			}
+		}
+		fmt.Println(formatReplayStep(i, step))
+	}
+	switch {
+	case r.State == api.StateRunning:
+		fmt.Println("The game was still running.")
+		return nil
+	case r.Forfeited:
+		fmt.Println("The game was forfeited.")
+	default:
+		fmt.Println("The game was", r.State)
+	}
+	words := strings.Join(r.Words, ", ")
+	if len(r.Words) > replayShownWords {
+		words = fmt.Sprintf("%s and %d more", strings.Join(r.Words[:replayShownWords], ", "),
+			len(r.Words)-replayShownWords)
+	}
+	fmt.Println("Words the computer was still choosing from:", words)
+	if err := r.Verify(); err != nil {
+		return fmt.Errorf("the replay is NOT consistent: %w", err)
+	}
+	fmt.Println("Every answer of the computer is consistent with these words.")
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/engine"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	graphBranches = flag.Int("graph_branches", 6,
+		"Max number of partitions not kept by the computer shown per turn by the "+
+			"graph subcommand. The others are grouped in a single node.")
+)
+
+// Turn of a replay with the partitions the computer chose from, rebuilt from
+// the dictionary.
+type graphTurn struct {
+	Step ReplayStep
+	// Decision of the turn, with the partitions in the order the vindictive
+	// engine prefers them. Nil for a timeout.
+	Decision *Decision
+	// Pattern of the partition kept by the computer.
+	Chosen string
+}
+
+// Method to rebuild the partitions of every turn of a replay, by playing it
+// again on the words of the dictionary. The dictionary must be the one the
+// game was played with, which is checked against the number of words recorded
+// after every turn.
+func replayTurns(r Replay, dict *Dictionary) ([]graphTurn, error) {
+	start, _ := parseMaskedWord(r.Start, nil)
+	var candidates []string
+	for _, word := range dict.Words(r.WordLength) {
+		if jointPattern(word, start, nil) == string(start) {
+			candidates = append(candidates, word)
+		}
+	}
+	if len(candidates) != r.Candidates {
+		return nil, fmt.Errorf("the dictionary has %d words for the game, the replay %d: "+
+			"it was recorded with another dictionary", len(candidates), r.Candidates)
+	}
+	before := start
+	var turns []graphTurn
+	for i, step := range r.Steps {
+		usedChars := []rune(step.UsedChars)
+		shown, _ := parseMaskedWord(step.Word, usedChars)
+		turn := graphTurn{Step: step, Chosen: string(shown)}
+		if step.Kind == TurnGuess.String() {
+			char := []rune(step.Char)[0]
+			sizes := make(map[string]int)
+			for _, word := range candidates {
+				pattern := engine.GuessPattern(word, before, char)
+				sizes[pattern]++
+				// In a team turn the word shown after the turn also has the
+				// other letters, only the one of this step is kept.
+				if jointPattern(word, start, usedChars) == string(shown) {
+					turn.Chosen = pattern
+				}
+			}
+			decision := newDecision(char, before, len(candidates), sizes)
+			turn.Decision = &decision
+		}
+		var kept []string
+		for _, word := range candidates {
+			if jointPattern(word, start, usedChars) == string(shown) {
+				kept = append(kept, word)
+			}
+		}
+		if len(kept) != step.Candidates {
+			return nil, fmt.Errorf("step %d: the dictionary has %d words left, the replay %d: "+
+				"it was recorded with another dictionary", i+1, len(kept), step.Candidates)
+		}
+		candidates = kept
+		before = shown
+		turns = append(turns, turn)
+	}
+	return turns, nil
+}
+
+// Method to write the tree of the partitions of a replay in the DOT language
+// of Graphviz: a node per word shown to the player, linked by the guesses, with
+// the partitions the computer did not keep as dashed leaves.
+func writeReplayGraph(w io.Writer, r Replay, turns []graphTurn, branches int) error {
+	var b strings.Builder
+	b.WriteString("digraph replay {\n")
+	b.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+	fmt.Fprintf(&b, "\tt0 [label=%s, style=bold];\n",
+		dotLabel(r.Start, fmt.Sprintf("%d words", r.Candidates)))
+	for i, turn := range turns {
+		node := fmt.Sprintf("t%d", i+1)
+		parent := fmt.Sprintf("t%d", i)
+		if turn.Decision == nil {
+			fmt.Fprintf(&b, "\t%s [label=%s, style=bold];\n", node,
+				dotLabel(turn.Step.Word, fmt.Sprintf("%d words", turn.Step.Candidates)))
+			fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", parent, node,
+				strconv.Quote("time ran out"))
+			continue
+		}
+		answer := "rejected"
+		if turn.Step.Accepted {
+			answer = "accepted"
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s, style=bold];\n", node,
+			dotLabel(turn.Step.Word, fmt.Sprintf("%d words, %s", turn.Step.Candidates, answer)))
+		guess := fmt.Sprintf("%q", turn.Step.Char)
+		if turn.Step.Player != "" {
+			guess += " by " + turn.Step.Player
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s, style=bold];\n", parent, node,
+			strconv.Quote(guess))
+		others := 0
+		for _, p := range turn.Decision.Partitions {
+			if p.Pattern == turn.Chosen {
+				continue
+			}
+			others++
+			if others > branches {
+				continue
+			}
+			leaf := fmt.Sprintf("%s_%d", node, others)
+			fmt.Fprintf(&b, "\t%s [label=%s, style=dashed, color=gray];\n", leaf,
+				dotLabel(DefaultPatternFormat.Format([]rune(p.Pattern)),
+					fmt.Sprintf("%d words", p.Size)))
+			fmt.Fprintf(&b, "\t%s -> %s [style=dashed, color=gray];\n", parent, leaf)
+		}
+		if others > branches {
+			leaf := node + "_more"
+			fmt.Fprintf(&b, "\t%s [label=%s, style=dashed, color=gray];\n", leaf,
+				strconv.Quote(fmt.Sprintf("%d more", others-branches)))
+			fmt.Fprintf(&b, "\t%s -> %s [style=dashed, color=gray];\n", parent, leaf)
+		}
+	}
+	if len(r.Words) > 0 {
+		words := append([]string{}, r.Words...)
+		sort.Strings(words)
+		if len(words) > replayShownWords {
+			words = append(words[:replayShownWords],
+				fmt.Sprintf("and %d more", len(r.Words)-replayShownWords))
+		}
+		fmt.Fprintf(&b, "\twords [label=%s, shape=note];\n", strconv.Quote(
+			strings.Join(words, "\n")))
+		fmt.Fprintf(&b, "\tt%d -> words [label=%s];\n", len(turns), strconv.Quote(string(r.State)))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Method to build the label of a node from its lines, quoted for DOT.
+func dotLabel(lines ...string) string {
+	return strconv.Quote(strings.Join(lines, "\n"))
+}
+
+// Driver method for the "graph" subcommand, which writes the tree of the
+// partitions of a saved game in the DOT language, e.g. to draw it with
+// "dot -Tsvg". The game must have been played with the same dictionary flags.
+func StartGraph(args []string) error {
+	if len(args) != 1 {
+		return usageError("hangman graph <replay file>")
+	}
+	r, err := loadReplay(args[0])
+	if err != nil {
+		return fmt.Errorf("unable to load the replay: %w", err)
+	}
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	turns, err := replayTurns(r, currentDictionary())
+	if err != nil {
+		return fmt.Errorf("unable to replay the game: %w", err)
+	}
+	if err := writeReplayGraph(os.Stdout, r, turns, *graphBranches); err != nil {
+		return fmt.Errorf("unable to write the graph: %w", err)
+	}
+	return nil
+}
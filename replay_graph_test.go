@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type ReplayGraphTestSuite struct {
+	suite.Suite
+}
+
+func (s *ReplayGraphTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats"})
+}
+
+func (s *ReplayGraphTestSuite) TestTurns() {
+	game, err := NewGame(4, 1)
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('a')
+	game.CheckUserInput('s')
+	turns, err := replayTurns(game.Replay(), currentDictionary())
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, len(turns))
+	assert.Equal(s.T(), []Partition{{Pattern: string(rawPattern("_a__")), Size: 3}, {Pattern: string(rawPattern("____")), Size: 2}},
+		turns[0].Decision.Partitions)
+	assert.Equal(s.T(), string(rawPattern("_a__")), turns[0].Chosen)
+	assert.Equal(s.T(), []Partition{{Pattern: string(rawPattern("_as_")), Size: 2}, {Pattern: string(rawPattern("_a_s")), Size: 1}},
+		turns[1].Decision.Partitions)
+	assert.Equal(s.T(), string(rawPattern("_as_")), turns[1].Chosen)
+
+	var out strings.Builder
+	assert.Nil(s.T(), writeReplayGraph(&out, game.Replay(), turns, 6))
+	graph := out.String()
+	assert.True(s.T(), strings.HasPrefix(graph, "digraph replay {\n"))
+	assert.Contains(s.T(), graph, `t0 [label="____\n5 words", style=bold];`)
+	assert.Contains(s.T(), graph, `t1 [label="_a__\n3 words, accepted", style=bold];`)
+	assert.Contains(s.T(), graph, `t0 -> t1 [label="\"a\"", style=bold];`)
+	assert.Contains(s.T(), graph, `t1_1 [label="____\n2 words", style=dashed, color=gray];`)
+	assert.Contains(s.T(), graph, `t1 -> t2_1 [style=dashed, color=gray];`)
+
+	// The partitions past the limit are grouped.
+	out.Reset()
+	assert.Nil(s.T(), writeReplayGraph(&out, game.Replay(), turns, 0))
+	assert.Contains(s.T(), out.String(), `t1_more [label="1 more", style=dashed, color=gray];`)
+	assert.NotContains(s.T(), out.String(), "t1_1")
+}
+
+func (s *ReplayGraphTestSuite) TestTeamTurnAndEnd() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	game.CheckTeamInput([]TeamGuess{{Player: "alice", Char: 'a'}, {Player: "bob", Char: 's'}})
+	game.forfeit()
+	replay := game.Replay()
+	turns, err := replayTurns(replay, currentDictionary())
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, len(turns))
+	// The word shown after the turn has both letters, each step keeps its own.
+	assert.Equal(s.T(), string(rawPattern("_a__")), turns[0].Chosen)
+
+	var out strings.Builder
+	assert.Nil(s.T(), writeReplayGraph(&out, replay, turns, 6))
+	assert.Contains(s.T(), out.String(), `t1 -> t2 [label="\"s\" by bob", style=bold];`)
+	assert.Contains(s.T(), out.String(), `t2 -> words [label="forfeited"];`)
+}
+
+func (s *ReplayGraphTestSuite) TestOtherDictionary() {
+	game, err := NewGame(4, 1)
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('a')
+	replay := game.Replay()
+	InitGame([]string{"last", "fast"})
+	_, err = replayTurns(replay, currentDictionary())
+	assert.NotNil(s.T(), err)
+}
+
+func TestReplayGraphTestSuite(t *testing.T) {
+	suite.Run(t, new(ReplayGraphTestSuite))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"testing"
+)
+
+type ReplayTestSuite struct {
+	suite.Suite
+}
+
+func (s *ReplayTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats"})
+}
+
+func (s *ReplayTestSuite) TestRecord() {
+	game, err := NewGame(4, 1, WithSeed(3))
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('a')
+	assert.Equal(s.T(), Replay{
+		WordLength:     4,
+		AllowedRetries: 1,
+		RetryPolicy:    api.RetryStrict,
+		Seed:           3,
+		Start:          "____",
+		Candidates:     5,
+		Steps: []ReplayStep{{Kind: "guess", Char: "a", Accepted: true, Word: "_a__",
+			UsedChars: "a", RetriesLeft: 1, Candidates: 3}},
+		State: api.StateRunning,
+	}, game.Replay())
+
+	game.CheckUserInput('z')
+	replay := game.Replay()
+	assert.Equal(s.T(), api.StateLost, replay.State)
+	assert.Equal(s.T(), []string{"cats", "fast", "last"}, replay.Words)
+	assert.Equal(s.T(), ReplayStep{Kind: "guess", Char: "z", Word: "_a__", UsedChars: "az",
+		Candidates: 3}, replay.Steps[1])
+	assert.Nil(s.T(), replay.Verify())
+}
+
+func (s *ReplayTestSuite) TestTeamTurn() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	game.CheckTeamInput([]TeamGuess{{Player: "alice", Char: 'a'}, {Player: "bob", Char: 's'}})
+	game.forfeit()
+	replay := game.Replay()
+	assert.Equal(s.T(), 2, len(replay.Steps))
+	assert.Equal(s.T(), "bob", replay.Steps[1].Player)
+	assert.True(s.T(), replay.Forfeited)
+	assert.Nil(s.T(), replay.Verify())
+}
+
+func (s *ReplayTestSuite) TestVerifyCatchesCheating() {
+	game, err := NewGame(4, 1)
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('e')
+	game.CheckUserInput('z')
+	replay := game.Replay()
+	assert.Nil(s.T(), replay.Verify())
+	// "code" has the 'e' the computer rejected.
+	replay.Words = append(replay.Words, "code")
+	assert.NotNil(s.T(), replay.Verify())
+	replay.Words = []string{"zero"}
+	assert.EqualError(s.T(), replay.Verify(),
+		`step 1: "zero" does not match the word shown, ____`)
+}
+
+func (s *ReplayTestSuite) TestSaveAndLoad() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('s')
+	path := filepath.Join(s.T().TempDir(), "game.json")
+	assert.Nil(s.T(), saveReplay(path, game.Replay()))
+	loaded, err := loadReplay(path)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), game.Replay(), loaded)
+	_, err = loadReplay(filepath.Join(s.T().TempDir(), "missing.json"))
+	assert.NotNil(s.T(), err)
+}
+
+func (s *ReplayTestSuite) TestFormatStep() {
+	assert.Equal(s.T(), `Step 2: "e" rejected (guessed by bob), word _a__, 2 retries left, `+
+		`3 words possible`, formatReplayStep(1, ReplayStep{Kind: "guess", Char: "e",
+		Player: "bob", Word: "_a__", RetriesLeft: 2, Candidates: 3}))
+	assert.Equal(s.T(), "Step 1: time ran out, word ____, 0 retries left, 5 words possible",
+		formatReplayStep(0, ReplayStep{Kind: "timeout", Word: "____", Candidates: 5}))
+}
+
+func TestReplayTestSuite(t *testing.T) {
+	suite.Run(t, new(ReplayTestSuite))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/hackeracc/WordGuess/engine"
+)
+
+var retryPolicyName = flag.String("retry_policy", string(api.RetryStrict),
+	"When a game is lost: \"strict\" (once the retries are all used), "+
+		"\"lenient\" (at the wrong guess after that) or \"unlimited\" (never).")
+
+// Rule deciding when the wrong guesses of a game lose it.
+type RetryPolicy int
+
+const (
+	// The game is lost once all the retries are used, i.e. the retries are the
+	// number of wrong guesses the player can make. This is the default.
+	RetryStrict RetryPolicy = iota
+	// The game is lost at the first wrong guess made after all the retries
+	// are used, which gives the player one extra wrong guess.
+	RetryLenient
+	// The game is never lost. The retries still count the wrong guesses.
+	RetryUnlimited
+)
+
+func (p RetryPolicy) String() string {
+	return string(p.apiPolicy())
+}
+
+// Method to convert a retry policy to its API representation.
+func (p RetryPolicy) apiPolicy() api.RetryPolicy {
+	switch p {
+	case RetryLenient:
+		return api.RetryLenient
+	case RetryUnlimited:
+		return api.RetryUnlimited
+	}
+	return api.RetryStrict
+}
+
+// Method to parse a retry policy from its API representation. An empty name
+// is the default policy.
+func ParseRetryPolicy(name string) (RetryPolicy, error) {
+	switch api.RetryPolicy(name) {
+	case "", api.RetryStrict:
+		return RetryStrict, nil
+	case api.RetryLenient:
+		return RetryLenient, nil
+	case api.RetryUnlimited:
+		return RetryUnlimited, nil
+	}
+	return RetryStrict, fmt.Errorf("unknown retry policy %q, expected %q, %q or %q",
+		name, api.RetryStrict, api.RetryLenient, api.RetryUnlimited)
+}
+
+// Option to choose when the game is lost, see RetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) GameOption {
+	return func(g *Game) {
+		g.RetryPolicy = policy
+	}
+}
+
+// Method to check if the retries left lose the game under the policy.
+func (p RetryPolicy) lost(retriesLeft int) bool {
+	return engine.RetriesLost(p.apiPolicy(), retriesLeft)
+}
+
+// Method to get the retry policy set by the flags. Returns an error if the
+// policy is unknown.
+func retryPolicyFromFlags() (RetryPolicy, error) {
+	policy, err := ParseRetryPolicy(*retryPolicyName)
+	if err != nil {
+		return policy, configError("invalid --retry_policy: %w", err)
+	}
+	return policy, nil
+}
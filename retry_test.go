@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type RetryTestSuite struct {
+	suite.Suite
+}
+
+func (s *RetryTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to guess characters which are never in the words, returning the
+// number of guesses made before the game ended.
+func (s *RetryTestSuite) missUntilLost(game *Game, chars string) int {
+	for i, char := range chars {
+		if game.State != Running {
+			return i
+		}
+		accepted, err := game.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), false, accepted)
+	}
+	return len(chars)
+}
+
+func (s *RetryTestSuite) TestStrictByDefault() {
+	game, err := NewGame(4, 2)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), RetryStrict, game.RetryPolicy)
+	assert.Equal(s.T(), 2, s.missUntilLost(game, "ijk"))
+	assert.Equal(s.T(), Lost, game.State)
+	assert.Equal(s.T(), 0, game.CurrentRetries)
+}
+
+func (s *RetryTestSuite) TestLenient() {
+	game, err := NewGame(4, 2, WithRetryPolicy(RetryLenient))
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 3, s.missUntilLost(game, "ijkm"))
+	assert.Equal(s.T(), Lost, game.State)
+	assert.Equal(s.T(), -1, game.CurrentRetries)
+}
+
+func (s *RetryTestSuite) TestUnlimited() {
+	game, err := NewGame(4, 1, WithRetryPolicy(RetryUnlimited))
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4, s.missUntilLost(game, "ijkm"))
+	assert.Equal(s.T(), Running, game.State)
+	assert.Equal(s.T(), -3, game.CurrentRetries)
+}
+
+func (s *RetryTestSuite) TestParse() {
+	for name, expected := range map[string]RetryPolicy{
+		"": RetryStrict, "strict": RetryStrict, "lenient": RetryLenient,
+		"unlimited": RetryUnlimited,
+	} {
+		policy, err := ParseRetryPolicy(name)
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), expected, policy)
+	}
+	_, err := ParseRetryPolicy("Strict")
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), "unlimited", RetryUnlimited.String())
+}
+
+func TestRetryTestSuite(t *testing.T) {
+	suite.Run(t, new(RetryTestSuite))
+}
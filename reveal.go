@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"os"
+	"strings"
+	"unicode"
+)
+
+var (
+	familySafe = flag.Bool("family_safe", false,
+		"Never show the words containing a flagged term (see --flagged_words) "+
+			"when revealing the word at the end of a game. Another word fitting "+
+			"the game is shown instead, or the term is masked if there is none.")
+	flaggedWordsFile = flag.String("flagged_words", "",
+		"Absolute path of the file with the terms flagged by --family_safe, one "+
+			"per line.")
+)
+
+// Character shown instead of the letters of a flagged term.
+const maskChar = '*'
+
+// Policy deciding which words can be shown to the player once they are no
+// longer hidden, i.e. the word revealed at the end of a game and the list of
+// words consistent with the guesses.
+type RevealPolicy interface {
+	// Method to get the word to reveal. pick is the index of the candidate
+	// picked by the game, and candidates is never empty.
+	RevealWord(candidates []string, pick int) string
+	// Method to get the words which can be shown in place of the candidates.
+	ConsistentWords(candidates []string) []string
+}
+
+// Option to filter the words revealed by the game through a policy.
+func WithRevealPolicy(policy RevealPolicy) GameOption {
+	return func(g *Game) {
+		g.revealPolicy = policy
+	}
+}
+
+// Method to get the word shown to the player at the end of the game: a random
+// word among the candidates, picked with the source of the game (see
+// WithSeed and --favor_common_words) and filtered by the reveal policy of the
+// game. Once the game is over the word is only picked once, so that the word
+// shown to the player is also the one recorded in the stats.
+func (g *Game) RevealWord() string {
+	g.mu.Lock()
+	if g.revealed != "" {
+		defer g.mu.Unlock()
+		return g.revealed
+	}
+	candidates := g.candidatesLocked()
+	pick := 0
+	if len(candidates) > 0 {
+		pick = pickWord(g.randLocked(), g.dict, candidates)
+	}
+	g.mu.Unlock()
+	if len(candidates) == 0 {
+		return ""
+	}
+	word := candidates[pick]
+	if g.revealPolicy != nil {
+		word = g.revealPolicy.RevealWord(candidates, pick)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.State == Running {
+		return word
+	}
+	// Another call may have picked the word in the meantime.
+	if g.revealed == "" {
+		g.revealed = word
+	}
+	return g.revealed
+}
+
+// Method to settle the word of a finished game, as revealed by RevealWord, and
+// forget the other candidates to free their memory. RevealWord returns the
+// same word afterwards.
+func (g *Game) settleWord() string {
+	word := g.RevealWord()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.State != Running && word != "" {
+		g.CurrentSetOfWords, g.packed, g.bits = []string{word}, nil, nil
+	}
+	return word
+}
+
+// Method to get the words consistent with the guesses so far, filtered by the
+// reveal policy of the game.
+func (g *Game) ConsistentWords() []string {
+	candidates := g.Candidates()
+	if g.revealPolicy == nil {
+		return candidates
+	}
+	return g.revealPolicy.ConsistentWords(candidates)
+}
+
+// Reveal policy which keeps the words containing a flagged term from being
+// shown. Terms are matched case insensitively anywhere in the word, so a term
+// is also caught inside a longer word or a phrase.
+type familySafePolicy struct {
+	terms []string
+}
+
+func newFamilySafePolicy(terms []string) *familySafePolicy {
+	p := &familySafePolicy{}
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term != "" {
+			p.terms = append(p.terms, term)
+		}
+	}
+	return p
+}
+
+// Method to check if a word contains a flagged term.
+func (p *familySafePolicy) flagged(word string) bool {
+	word = strings.ToLower(word)
+	for _, term := range p.terms {
+		if strings.Contains(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// Method to replace the letters of the flagged terms of a word by maskChar.
+func (p *familySafePolicy) mask(word string) string {
+	runes := []rune(word)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	for _, term := range p.terms {
+		termRunes := []rune(term)
+		for i := 0; i+len(termRunes) <= len(lower); i++ {
+			if string(lower[i:i+len(termRunes)]) == term {
+				for j := i; j < i+len(termRunes); j++ {
+					runes[j] = maskChar
+				}
+			}
+		}
+	}
+	return string(runes)
+}
+
+// Method to get the picked word if it is not flagged. Otherwise the next
+// candidate which is not flagged is revealed instead, as it fits the game
+// just as well, or the picked word is masked if all of them are flagged.
+func (p *familySafePolicy) RevealWord(candidates []string, pick int) string {
+	for i := range candidates {
+		word := candidates[(pick+i)%len(candidates)]
+		if !p.flagged(word) {
+			return word
+		}
+	}
+	return p.mask(candidates[pick])
+}
+
+// Method to get the candidates with the flagged terms masked.
+func (p *familySafePolicy) ConsistentWords(candidates []string) []string {
+	words := make([]string, len(candidates))
+	for i, word := range candidates {
+		if p.flagged(word) {
+			word = p.mask(word)
+		}
+		words[i] = word
+	}
+	return words
+}
+
+// Method to load the terms flagged by the family safe policy, one per line.
+func loadFlaggedTerms(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var terms []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		terms = append(terms, scanner.Text())
+	}
+	return terms, scanner.Err()
+}
+
+// Method to get the reveal policy set by the flags, nil if none. Returns an
+// error if the flagged terms can not be loaded.
+func revealPolicyFromFlags() (RevealPolicy, error) {
+	if !*familySafe {
+		return nil, nil
+	}
+	if *flaggedWordsFile == "" {
+		return nil, configError("--family_safe needs the flagged terms, please set --flagged_words")
+	}
+	terms, err := loadFlaggedTerms(*flaggedWordsFile)
+	if err != nil {
+		return nil, configError("unable to load the flagged words %s: %w", *flaggedWordsFile, err)
+	}
+	return newFamilySafePolicy(terms), nil
+}
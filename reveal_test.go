@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type RevealTestSuite struct {
+	suite.Suite
+	policy *familySafePolicy
+}
+
+func (s *RevealTestSuite) SetupTest() {
+	s.policy = newFamilySafePolicy([]string{"Darn", " heck ", ""})
+}
+
+func (s *RevealTestSuite) TestSubstitutesFlaggedWord() {
+	candidates := []string{"darned", "basket", "hecked"}
+	assert.Equal(s.T(), "basket", s.policy.RevealWord(candidates, 0))
+	assert.Equal(s.T(), "basket", s.policy.RevealWord(candidates, 1))
+	// The next candidate which is not flagged is used, wrapping around.
+	assert.Equal(s.T(), "basket", s.policy.RevealWord(candidates, 2))
+}
+
+func (s *RevealTestSuite) TestMasksWhenAllFlagged() {
+	candidates := []string{"darned", "heckle"}
+	assert.Equal(s.T(), "****ed", s.policy.RevealWord(candidates, 0))
+	assert.Equal(s.T(), []string{"****ed", "****le"}, s.policy.ConsistentWords(candidates))
+	assert.Equal(s.T(), "Oh **** ****", s.policy.mask("Oh DARN heck"))
+}
+
+func (s *RevealTestSuite) TestGameReveal() {
+	InitGame([]string{"darn", "dart"})
+	game, err := NewGame(4, 0, WithRevealPolicy(s.policy))
+	assert.Nil(s.T(), err)
+	_, err = game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), Lost, game.State)
+	for i := 0; i < 10; i++ {
+		assert.Equal(s.T(), "dart", game.RevealWord())
+	}
+	assert.Equal(s.T(), []string{"****", "dart"}, game.ConsistentWords())
+
+	// Games without a policy reveal any candidate.
+	game, err = NewGame(4, 1)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"darn", "dart"}, game.ConsistentWords())
+}
+
+func TestRevealTestSuite(t *testing.T) {
+	suite.Run(t, new(RevealTestSuite))
+}
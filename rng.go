@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	randomSeed = flag.Int64("seed", 0,
+		"Seed of the random choices (the seeds of the games, the random word "+
+			"lengths and the opponents without a source of their own), which "+
+			"makes a session reproducible. A random seed is used if zero.")
+)
+
+// Source of the random choices which are not made by a game, e.g. the seeds of
+// the games and the random word lengths. It can be replaced with
+// SetRandSource, and is safe for concurrent use.
+var engineRand = struct {
+	sync.Mutex
+	rng *rand.Rand
+}{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// Method to replace the source of the random choices which are not made by a
+// game, e.g. to reproduce a session or in the tests. The source does not need
+// to be safe for concurrent use.
+func SetRandSource(src rand.Source) {
+	engineRand.Lock()
+	defer engineRand.Unlock()
+	engineRand.rng = rand.New(src)
+}
+
+// Method to get a random int in [0, n) from the engine source.
+func randIntn(n int) int {
+	engineRand.Lock()
+	defer engineRand.Unlock()
+	return engineRand.rng.Intn(n)
+}
+
+// Method to get a random float in [0, 1) from the engine source.
+func randFloat64() float64 {
+	engineRand.Lock()
+	defer engineRand.Unlock()
+	return engineRand.rng.Float64()
+}
+
+// Method to get a random non-negative int64 from the engine source, used as
+// the seed of a game.
+func randInt63() int64 {
+	engineRand.Lock()
+	defer engineRand.Unlock()
+	return engineRand.rng.Int63()
+}
+
+// Option to seed the random choices of a game: the word revealed at the end,
+// and the choices of the opponents without a source of their own, see
+// Chaotic. Two games with the same seed and the same guesses play the same.
+// By default every game gets a seed from the engine source, see SetRandSource.
+func WithSeed(seed int64) GameOption {
+	return func(g *Game) {
+		g.seed = seed
+		g.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// Method to get the seed of the random choices of the game, see WithSeed.
+func (g *Game) Seed() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.randLocked()
+	return g.seed
+}
+
+// Method to get the source of the random choices of the game. A game restored
+// without one gets a new seed. The game lock must be held.
+func (g *Game) randLocked() *rand.Rand {
+	if g.rng == nil {
+		g.seed = randInt63()
+		g.rng = rand.New(rand.NewSource(g.seed))
+	}
+	return g.rng
+}
+
+// Method to seed the engine source from the flags, when a seed is given.
+func setupRandom() {
+	if *randomSeed != 0 {
+		SetRandSource(rand.NewSource(*randomSeed))
+	}
+}
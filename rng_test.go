@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type RandTestSuite struct {
+	suite.Suite
+}
+
+func (s *RandTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats", "dogs", "hogs", "kite"})
+}
+
+func (s *RandTestSuite) TearDownTest() {
+	SetRandSource(rand.NewSource(time.Now().UnixNano()))
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to play a game with a chaotic opponent and get its replay and the
+// word revealed at the end.
+func (s *RandTestSuite) play(opts ...GameOption) (Replay, string) {
+	opts = append(opts, WithStrategy(Chaotic{}))
+	game, err := NewGame(4, 2, opts...)
+	assert.Nil(s.T(), err)
+	for _, char := range "etaos" {
+		game.CheckUserInput(char)
+	}
+	return game.Replay(), game.RevealWord()
+}
+
+func (s *RandTestSuite) TestSeed() {
+	replay, word := s.play(WithSeed(42))
+	assert.Equal(s.T(), int64(42), replay.Seed)
+	for i := 0; i < 5; i++ {
+		again, againWord := s.play(WithSeed(42))
+		assert.Equal(s.T(), replay, again)
+		assert.Equal(s.T(), word, againWord)
+	}
+}
+
+func (s *RandTestSuite) TestEngineSource() {
+	SetRandSource(rand.NewSource(7))
+	first, _ := s.play()
+	length, _ := pickRandomLength(currentDictionary(), randIntn)
+	SetRandSource(rand.NewSource(7))
+	second, _ := s.play()
+	assert.Equal(s.T(), first, second)
+	assert.NotEqual(s.T(), int64(0), second.Seed)
+	again, _ := pickRandomLength(currentDictionary(), randIntn)
+	assert.Equal(s.T(), length, again)
+}
+
+func (s *RandTestSuite) TestChaoticOwnSource() {
+	rng := rand.New(rand.NewSource(1))
+	strategy := Chaotic{Rand: rng}.withRand(rand.New(rand.NewSource(2)))
+	assert.Equal(s.T(), rng, strategy.(Chaotic).Rand)
+}
+
+func TestRandTestSuite(t *testing.T) {
+	suite.Run(t, new(RandTestSuite))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+var (
+	savedGameFile = flag.String("saved_game_file", "",
+		"Absolute path of the JSON file where the game in progress is saved when "+
+			"the terminal game is interrupted (Ctrl+C or SIGTERM), so that it can "+
+			"be resumed at the next start.")
+)
+
+// Method to save a game in progress, to resume it later, see resumeSavedGame.
+// The candidates are kept so that the game goes on with the same words.
+func saveGame(path string, game *Game) error {
+	data, err := json.MarshalIndent(game.Snapshot(true), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Method to load a saved game and apply the options of the new games to it.
+// The retry policy of the saved game is kept. Returns nil if there is no saved
+// game.
+func loadSavedGame(path string, opts ...GameOption) (*Game, error) {
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	game := &Game{}
+	for _, opt := range opts {
+		opt(game)
+	}
+	if err := json.Unmarshal(data, game); err != nil {
+		return nil, fmt.Errorf("invalid saved game %s: %v", path, err)
+	}
+	if game.State != Running {
+		return nil, nil
+	}
+	game.startReplayLocked()
+	return game, nil
+}
+
+// Method to offer the player to resume the game saved by --saved_game_file.
+// The saved game is removed whether it is resumed or not.
+// Returns nil if there is no saved game or the player declined, and an error if
+// the player stopped playing, see waitInput.
+func resumeSavedGame(opts ...GameOption) (*Game, error) {
+	if *savedGameFile == "" {
+		return nil, nil
+	}
+	game, err := loadSavedGame(*savedGameFile, opts...)
+	if err != nil {
+		fmt.Println("Unable to load the saved game, error ", err)
+		return nil, nil
+	}
+	if game == nil {
+		return nil, nil
+	}
+	if err := os.Remove(*savedGameFile); err != nil {
+		fmt.Println("Unable to remove the saved game, error ", err)
+	}
+	fmt.Println("A game was interrupted:", DefaultPatternFormat.Format(game.CurrentDisplayedWord),
+		"with", game.CurrentRetries, "retries left. Press Y to resume it, any other "+
+			"key to start a new one: ")
+	answer, err := readChar()
+	if err != nil || !isYes(answer) {
+		return nil, err
+	}
+	return game, nil
+}
+
+// Method to save the game in progress when the terminal game is interrupted,
+// if --saved_game_file is set. Finished games are not saved.
+func saveInterruptedGame(game *Game) {
+	if *savedGameFile == "" || game == nil || game.State != Running {
+		return
+	}
+	if err := saveGame(*savedGameFile, game); err != nil {
+		fmt.Println("Unable to save the game, error ", err)
+		return
+	}
+	fmt.Println("Game saved, it will be offered again at the next start.")
+}
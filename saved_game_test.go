@@ -0,0 +1,84 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type SavedGameTestSuite struct {
+	suite.Suite
+	path string
+}
+
+func (s *SavedGameTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "hello"})
+	s.path = filepath.Join(s.T().TempDir(), "game.json")
+}
+
+func (s *SavedGameTestSuite) TestSaveAndLoad() {
+	game, err := NewGame(4, 5, WithRetryPolicy(RetryLenient))
+	s.Require().NoError(err)
+	game.CheckUserInput('t')
+	game.CheckUserInput('z')
+	s.Require().NoError(saveGame(s.path, game))
+
+	loaded, err := loadSavedGame(s.path, WithGuessTimeout(time.Minute),
+		WithRetryPolicy(RetryStrict))
+	s.Require().NoError(err)
+	s.Require().NotNil(loaded)
+	assert.Equal(s.T(), game.CurrentDisplayedWord, loaded.CurrentDisplayedWord)
+	assert.Equal(s.T(), game.UsedChars, loaded.UsedChars)
+	assert.Equal(s.T(), game.CurrentRetries, loaded.CurrentRetries)
+	assert.Equal(s.T(), game.Candidates(), loaded.Candidates())
+	// The options of the new games are applied, but the saved retry policy is
+	// kept.
+	assert.Equal(s.T(), time.Minute, loaded.GuessTimeout)
+	assert.Equal(s.T(), RetryLenient, loaded.RetryPolicy)
+	assert.Equal(s.T(), Running, loaded.State)
+}
+
+func (s *SavedGameTestSuite) TestNoSavedGame() {
+	loaded, err := loadSavedGame(s.path)
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), loaded)
+
+	// Finished games are not resumed.
+	game, err := NewGame(4, 1)
+	s.Require().NoError(err)
+	game.CheckUserInput('z')
+	s.Require().Equal(Lost, game.State)
+	s.Require().NoError(saveGame(s.path, game))
+	loaded, err = loadSavedGame(s.path)
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), loaded)
+}
+
+func (s *SavedGameTestSuite) TestSaveInterruptedGame() {
+	*savedGameFile = s.path
+	defer func() { *savedGameFile = "" }()
+	game, err := NewGame(4, 3)
+	s.Require().NoError(err)
+	saveInterruptedGame(nil)
+	loaded, _ := loadSavedGame(s.path)
+	assert.Nil(s.T(), loaded)
+
+	saveInterruptedGame(game)
+	loaded, err = loadSavedGame(s.path)
+	s.Require().NoError(err)
+	assert.NotNil(s.T(), loaded)
+}
+
+func (s *SavedGameTestSuite) TestInterrupt() {
+	// Interrupts are not queued up while one is waiting to be handled.
+	interruptInput()
+	interruptInput()
+	assert.Len(s.T(), stdinInterrupt, 1)
+	<-stdinInterrupt
+}
+
+func TestSavedGameTestSuite(t *testing.T) {
+	suite.Run(t, new(SavedGameTestSuite))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"flag"
+)
+
+var (
+	pointsPerLetter = flag.Int("points_per_letter", DefaultScoreConfig.PointsPerLetter,
+		"Points scored for every correct guess.")
+	pointsPerRetry = flag.Int("points_per_retry", DefaultScoreConfig.PointsPerRetryLeft,
+		"Bonus points for every retry left when the game is won.")
+	hintPenalty = flag.Int("hint_penalty", DefaultScoreConfig.HintPenalty,
+		"Points taken off for every hint shown during a game.")
+	streakStep = flag.Float64("streak_step", DefaultScoreConfig.StreakStep,
+		"Increase of the score multiplier for every win in a row before the "+
+			"current one, e.g. the third win in a row scores 1 + 2 * step times "+
+			"the points.")
+	maxStreakMultiplier = flag.Float64("max_streak_multiplier",
+		DefaultScoreConfig.MaxMultiplier, "Max score multiplier of a winning streak.")
+)
+
+// Rules to score a finished game.
+type ScoreConfig struct {
+	// Points for every correct guess.
+	PointsPerLetter int
+	// Bonus for every retry left, only given when the game is won.
+	PointsPerRetryLeft int
+	// Points taken off for every hint shown.
+	HintPenalty int
+	// Increase of the multiplier for every win in a row before the game, and
+	// the max multiplier. Only won games are multiplied.
+	StreakStep    float64
+	MaxMultiplier float64
+}
+
+// Scoring rules used when no other rules are given.
+var DefaultScoreConfig = ScoreConfig{
+	PointsPerLetter:    10,
+	PointsPerRetryLeft: 20,
+	HintPenalty:        25,
+	StreakStep:         0.5,
+	MaxMultiplier:      3,
+}
+
+// Score of a finished game, with the points it is made of.
+type GameScore struct {
+	// Points for the correct guesses.
+	Letters int
+	// Bonus for the retries left.
+	RetryBonus int
+	// Points taken off for the hints.
+	HintPenalty int
+	// Multiplier of the winning streak, 1 for a lost game.
+	Multiplier float64
+	// Score of the game, never negative.
+	Total int
+}
+
+// Method to validate the scoring rules.
+func (c ScoreConfig) Validate() error {
+	if c.PointsPerLetter < 0 || c.PointsPerRetryLeft < 0 || c.HintPenalty < 0 {
+		return errors.New("points can not be negative")
+	}
+	if c.StreakStep < 0 {
+		return errors.New("the streak step can not be negative")
+	}
+	if c.MaxMultiplier < 1 {
+		return errors.New("the max multiplier can not be less than 1")
+	}
+	return nil
+}
+
+// Method to get the multiplier of the streak-th win in a row.
+func (c ScoreConfig) multiplier(streak int) float64 {
+	if streak < 1 {
+		return 1
+	}
+	multiplier := 1 + c.StreakStep*float64(streak-1)
+	if multiplier > c.MaxMultiplier {
+		return c.MaxMultiplier
+	}
+	return multiplier
+}
+
+// Method to score a finished game. streak is the number of wins in a row
+// including this game, see Stats.CurrentWinStreak, and is ignored for a lost
+// game. A running game scores nothing.
+func (c ScoreConfig) Score(game *Game, streak int) GameScore {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	score := GameScore{Multiplier: 1}
+	if game.State == Running {
+		return score
+	}
+	for _, turn := range game.Turns {
+		if turn.Accepted {
+			score.Letters += c.PointsPerLetter
+		}
+	}
+	score.HintPenalty = c.HintPenalty * game.HintsUsed
+	if game.State == Won {
+		if game.CurrentRetries > 0 {
+			score.RetryBonus = c.PointsPerRetryLeft * game.CurrentRetries
+		}
+		score.Multiplier = c.multiplier(streak)
+	}
+	points := score.Letters + score.RetryBonus - score.HintPenalty
+	if points > 0 {
+		score.Total = int(float64(points) * score.Multiplier)
+	}
+	return score
+}
+
+// Method to get the scoring rules given by the flags. Returns an error if they
+// are invalid.
+func scoreConfigFromFlags() (ScoreConfig, error) {
+	config := ScoreConfig{
+		PointsPerLetter:    *pointsPerLetter,
+		PointsPerRetryLeft: *pointsPerRetry,
+		HintPenalty:        *hintPenalty,
+		StreakStep:         *streakStep,
+		MaxMultiplier:      *maxStreakMultiplier,
+	}
+	if err := config.Validate(); err != nil {
+		return ScoreConfig{}, configError("invalid scoring rules: %w", err)
+	}
+	return config, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ScoreTestSuite struct {
+	suite.Suite
+}
+
+func (s *ScoreTestSuite) SetupTest() {
+	InitGame([]string{"cats|animal"})
+}
+
+func (s *ScoreTestSuite) TearDownTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to play a game of 3 retries with the given guesses.
+func (s *ScoreTestSuite) play(guesses string) *Game {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	for _, char := range guesses {
+		game.CheckUserInput(char)
+	}
+	return game
+}
+
+func (s *ScoreTestSuite) TestWon() {
+	game := s.play("zcats")
+	assert.Equal(s.T(), Won, game.State)
+	score := DefaultScoreConfig.Score(game, 1)
+	assert.Equal(s.T(), GameScore{Letters: 40, RetryBonus: 40, Multiplier: 1, Total: 80}, score)
+
+	// The third win in a row is multiplied by 2, and the multiplier is capped.
+	assert.Equal(s.T(), 160, DefaultScoreConfig.Score(game, 3).Total)
+	assert.Equal(s.T(), 240, DefaultScoreConfig.Score(game, 10).Total)
+}
+
+func (s *ScoreTestSuite) TestLost() {
+	game := s.play("czxy")
+	assert.Equal(s.T(), Lost, game.State)
+	score := DefaultScoreConfig.Score(game, 5)
+	assert.Equal(s.T(), GameScore{Letters: 10, Multiplier: 1, Total: 10}, score)
+
+	assert.Equal(s.T(), GameScore{Multiplier: 1}, DefaultScoreConfig.Score(s.play("c"), 1))
+}
+
+func (s *ScoreTestSuite) TestHints() {
+	game := s.play("")
+	// Checking if a hint is available is free.
+	_, ok := game.Hint()
+	assert.True(s.T(), ok)
+	clue, ok := game.UseHint()
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), "animal", clue)
+	game.UseHint()
+	assert.Equal(s.T(), 2, game.HintsUsed)
+	for _, char := range "cats" {
+		game.CheckUserInput(char)
+	}
+	score := DefaultScoreConfig.Score(game, 1)
+	assert.Equal(s.T(), 50, score.HintPenalty)
+	assert.Equal(s.T(), 40+60-50, score.Total)
+
+	// The score is never negative.
+	config := DefaultScoreConfig
+	config.HintPenalty = 1000
+	assert.Equal(s.T(), 0, config.Score(game, 1).Total)
+}
+
+func (s *ScoreTestSuite) TestValidate() {
+	assert.Nil(s.T(), DefaultScoreConfig.Validate())
+	config := DefaultScoreConfig
+	config.HintPenalty = -1
+	assert.NotNil(s.T(), config.Validate())
+	config = DefaultScoreConfig
+	config.StreakStep = -0.5
+	assert.NotNil(s.T(), config.Validate())
+	config = DefaultScoreConfig
+	config.MaxMultiplier = 0.5
+	assert.NotNil(s.T(), config.Validate())
+}
+
+func TestScoreTestSuite(t *testing.T) {
+	suite.Run(t, new(ScoreTestSuite))
+}
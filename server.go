@@ -0,0 +1,952 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	httpAddr = flag.String("http_addr", "",
+		"Address (host:port) to serve the game over HTTP and WebSocket on, "+
+			"instead of playing in the terminal.")
+	requestTimeout = flag.Duration("request_timeout", 30*time.Second,
+		"Max time the server spends on a request, e.g. a guess or a dictionary "+
+			"reload, before answering with a timeout error. WebSocket "+
+			"connections are not limited, but each of their guesses is. 0 for "+
+			"no limit.")
+)
+
+const (
+	// Max size of a request body accepted by the REST API.
+	maxRequestBody = 4 * 1024
+	// Max size of the headers of a request, and time allowed to send them.
+	maxRequestHeader  = 16 * 1024
+	readHeaderTimeout = 10 * time.Second
+	// Number of messages buffered for a WebSocket client. A client which
+	// falls behind by more than this is disconnected.
+	watcherBuffer = 16
+)
+
+// Server which lets clients play games over a REST API, and follow them live
+// (as players or spectators) over WebSocket.
+//
+// REST API:
+//   POST /games                 Create a game, body api.CreateGameRequest.
+//                               Games with a lifetime are correspondence
+//                               games, forfeited if no guess is made in time.
+//                               See also --idle_forfeit and --game_deadline.
+//   GET  /games/{id}            Get the state of a game.
+//   POST /games/{id}/guesses    Guess a character, body api.GuessRequest.
+//                               The players of cooperative games send their
+//                               token, and take turns.
+//   GET  /games/{id}/preview    Preview the next guess (practice games only).
+//   GET  /games/{id}/hint       Clue of the word, as api.Hint, once the
+//                               remaining words share one.
+//   POST /coop/join             Join a cooperative game as the partner of
+//                               its host, body api.JoinRequest.
+//   GET  /coop/lobby            Cooperative games waiting for a partner, as
+//                               api.Lobby.
+//   GET  /presets               List the presets, as api.PresetList.
+//   POST /presets               Save a new preset, body api.Preset.
+//   GET  /presets/{name}        Get a preset, e.g. to share it with another
+//                               server.
+//   GET  /leaderboard           Top players of the leaderboard, as
+//                               api.Leaderboard.
+//   GET  /hall_of_shame         Classes of words no player has solved, as
+//                               api.HallOfShame. Challenge games are played
+//                               on them.
+//   GET  /openapi.json          OpenAPI document of the REST API, see
+//                               apiOperations. The request bodies are
+//                               validated against it.
+// Admin API, only served with the admin token (see --admin_token) or a client
+// certificate of an admin (see --admin_client_ca):
+//   GET    /admin/features        List the feature flags, as api.FeatureList.
+//   PUT    /admin/features/{name} Set the rule of a feature, body
+//                                 api.FeatureRule.
+//   DELETE /admin/features/{name} Put a feature back in its default state.
+//   PUT    /admin/cohorts/{name}  Set the players of a cohort, body
+//                                 api.Cohort.
+//   POST   /admin/dictionary/reload
+//                                 Reload the dictionary, see api.About.
+//   GET    /admin/games           Running games, as api.AdminGameList. Add
+//                                 ?all=1 for the finished games too.
+//   GET    /admin/games/{id}/candidates
+//                                 Words the game can still pick from, as
+//                                 api.AdminCandidates.
+//   POST   /admin/games/{id}/finish
+//                                 Forfeit a running game.
+//   GET    /admin/stats           Aggregate stats of the games, as
+//                                 api.AdminStats.
+// Web frontend, unless disabled with --web_ui=false:
+//   GET  /                      Page to play the games of the server in a
+//                               browser, see web/ui.
+//   GET  /ui/{file}             Assets of the page.
+// Slack, only served with the signing secret (see --slack_signing_secret):
+//   POST /slack/commands        The /hangman slash command, see
+//                               handleSlackCommand.
+// WebSocket:
+//   GET  /games/{id}/ws         Stream of api.Message. Players send guess
+//                               messages, and everyone connected gets a state
+//                               message after every guess. Add ?spectate=1 to
+//                               watch without being able to guess, and
+//                               ?player_token=<> to guess as a player of a
+//                               cooperative game. Practice games also get a
+//                               preview message after every state message,
+//                               and correspondence games an expiry_warning
+//                               message before they expire.
+type gameServer struct {
+	store sessionStore
+	// Named rules which games can be created with.
+	presets *presetStore
+	// Leaderboard exposed by the server, nil if none is kept.
+	leaderboard *leaderboardStore
+	// Hall of shame of the server, nil if none is kept.
+	shame *shameStore
+	// Faults injected for testing, nil in production.
+	chaos *chaosConfig
+	// Token of the admin API, which is disabled if empty.
+	adminToken string
+	// Max lifetime of the correspondence games, and the times before they
+	// expire at which their clients are warned, longest first.
+	maxLifetime      time.Duration
+	lifetimeWarnings []time.Duration
+	// Time allowed between two guesses of the games without a lifetime, and
+	// max time a game can last. Zero if the games never expire.
+	idleForfeit  time.Duration
+	gameDeadline time.Duration
+	// Rate limits of the game creations and of the guesses.
+	limits serverLimits
+	// Signing secret of the Slack app, which disables the slash command if
+	// empty, and the games of the Slack channels.
+	slackSecret string
+	slackGames  *slackGames
+	// Join codes of the cooperative games waiting for a partner.
+	coop *coopLobby
+	// True if the web frontend is served, see --web_ui.
+	webUI bool
+	// Max time spent on a request, zero if there is no limit.
+	requestTimeout time.Duration
+}
+
+// Game played through the server, along with the clients watching it.
+type session struct {
+	id string
+	// Guards the game and the watchers. Guesses are applied one at a time and
+	// every watcher gets the states in the same order.
+	mu       sync.Mutex
+	game     *Game
+	watchers map[*watcher]bool
+	// True if previews are allowed for the game.
+	practice bool
+	// Name of the preset the game was created with, empty if none.
+	preset string
+	// Preview computed for the current state, nil till it is first needed.
+	// Computing a preview runs the engine for every character, so it is shared
+	// by all the clients and only recomputed after a guess.
+	preview *api.Preview
+	// Name of the player, empty if not given.
+	player  string
+	created time.Time
+	// Leaderboard the game is recorded in once finished, nil if none is kept.
+	leaderboard *leaderboardStore
+	// Hall of shame the game is recorded in once finished, nil if none is
+	// kept.
+	shame *shameStore
+	// True for challenge games, which count double in the leaderboard.
+	challenge bool
+	// Store the session is saved to after every change, and the version of
+	// the game saved last. Stores shared by several servers check the version
+	// to detect the changes made concurrently.
+	store   sessionStore
+	version int64
+
+	// Correspondence games, and the games of servers with deadlines: time
+	// allowed between two guesses, zero for the other games.
+	lifetime time.Duration
+	// Time by which the next guess must be made.
+	expiresAt time.Time
+	// Time by which the game must be finished, see --game_deadline. Zero if
+	// the game can last forever.
+	deadline time.Time
+	// Times before the expiry at which warnings are sent, longest first, and
+	// the index of the next one to send.
+	warningTimes []time.Duration
+	nextWarning  int
+	// Number of warnings sent since the last guess.
+	warningsSent int
+	// Fires for the next warning or for the expiry.
+	expiryTimer *time.Timer
+	// Games with a guess timeout only: fires at the deadline of the current
+	// turn, see armTurnTimerLocked.
+	turnTimer *time.Timer
+
+	// Cooperative games only: players taking turns, nil for the other games.
+	coop *coopSeats
+	// True once the game ended, till it is recorded, see recordLocked. Set by
+	// the observer of the game.
+	ended bool
+}
+
+// Observer of the game of a session. The games of the sessions are only
+// played with the session lock held, so it is held by the observer too.
+type sessionObserver struct {
+	sess *session
+}
+
+func (o sessionObserver) OnGuess(Turn) {}
+
+func (o sessionObserver) OnStateChange(GameState) {
+	o.sess.ended = true
+}
+
+func (o sessionObserver) OnReveal(string) {}
+
+// WebSocket client following a game.
+type watcher struct {
+	conn *wsConn
+	// Faults injected while writing to the client, nil in production.
+	chaos *chaosConfig
+	// True if the client can only watch the game.
+	spectator bool
+	// Cooperative games: token of the player guessing from this client.
+	playerToken string
+	// Guards the send channel, which is closed when the watcher is dropped.
+	mu     sync.Mutex
+	closed bool
+	// Messages waiting to be written to the client.
+	send chan api.Message
+}
+
+func newGameServer() *gameServer {
+	return &gameServer{store: newServerSessionManager(), presets: newPresetStore(),
+		maxLifetime: *maxGameLifetime, slackGames: newSlackGames(), coop: newCoopLobby(),
+		webUI: *webUI, requestTimeout: *requestTimeout}
+}
+
+// Method to make the server inject the faults of the chaos config. Meant for
+// testing only.
+func (s *gameServer) withChaos(chaos *chaosConfig) *gameServer {
+	if chaos != nil {
+		s.chaos = chaos
+		s.store = chaosStore{sessionStore: s.store, chaos: chaos}
+	}
+	return s
+}
+
+// Method to build the HTTP handler of the server.
+func (s *gameServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /games", s.handleCreate)
+	mux.HandleFunc("GET /games/{id}", s.handleGet)
+	mux.HandleFunc("POST /games/{id}/guesses", s.handleGuess)
+	mux.HandleFunc("GET /games/{id}/preview", s.handlePreview)
+	mux.HandleFunc("GET /games/{id}/hint", s.handleHint)
+	mux.HandleFunc("GET /games/{id}/ws", s.handleWebsocket)
+	mux.HandleFunc("POST /coop/join", s.handleJoin)
+	mux.HandleFunc("GET /coop/lobby", s.handleLobby)
+	mux.HandleFunc("GET /presets", s.handleListPresets)
+	mux.HandleFunc("POST /presets", s.handleCreatePreset)
+	mux.HandleFunc("GET /presets/{name}", s.handleGetPreset)
+	mux.HandleFunc("GET /leaderboard", s.handleLeaderboard)
+	mux.HandleFunc("GET /hall_of_shame", s.handleHallOfShame)
+	mux.HandleFunc("GET /about", s.handleAbout)
+	mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	mux.HandleFunc("GET /admin/features", s.admin(s.handleListFeatures))
+	mux.HandleFunc("PUT /admin/features/{name}", s.admin(s.handleSetFeature))
+	mux.HandleFunc("DELETE /admin/features/{name}", s.admin(s.handleResetFeature))
+	mux.HandleFunc("PUT /admin/cohorts/{name}", s.admin(s.handleSetCohort))
+	mux.HandleFunc("POST /admin/dictionary/reload", s.admin(s.handleReloadDictionary))
+	mux.HandleFunc("GET /admin/games", s.admin(s.handleListGames))
+	mux.HandleFunc("GET /admin/games/{id}/candidates", s.admin(s.handleGetCandidates))
+	mux.HandleFunc("POST /admin/games/{id}/finish", s.admin(s.handleFinishGame))
+	mux.HandleFunc("GET /admin/stats", s.admin(s.handleAdminStats))
+	if s.slackSecret != "" {
+		mux.HandleFunc("POST /slack/commands", s.handleSlackCommand)
+	}
+	if s.webUI {
+		index, assets := webUIHandler()
+		mux.Handle("GET /{$}", index)
+		mux.Handle("GET /ui/", assets)
+	}
+	metrics := newMetricsHandler(gameMetrics)
+	mux.Handle("GET /healthz", metrics)
+	mux.Handle("GET /stats", metrics)
+	handler := s.withRequestTimeout(openAPI.validateRequests(mux))
+	if s.chaos != nil {
+		return s.chaos.middleware(handler)
+	}
+	return handler
+}
+
+// Method to derive the context of a request (or of a WebSocket message) from
+// its parent, with the deadline given by --request_timeout.
+func (s *gameServer) requestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if s.requestTimeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, s.requestTimeout)
+}
+
+// Method to enforce the deadline of the requests. The handlers pass the context
+// of the request down to the store and the dictionary, which give up once it
+// is done. WebSocket connections last as long as the game, so their guesses
+// get a deadline each instead.
+func (s *gameServer) withRequestTimeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if headerContains(r.Header, "Upgrade", "websocket") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, cancel := s.requestContext(r.Context())
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Method to serve the game on the configured address. This blocks till the
+// server stops.
+func StartServer() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	server := newGameServer()
+	if *leaderboardFile != "" {
+		server.leaderboard = newLeaderboardStore(*leaderboardFile)
+	}
+	if *hallOfShameFile != "" {
+		server.shame = newShameStore(*hallOfShameFile)
+	}
+	var err error
+	if server.lifetimeWarnings, err = lifetimeWarningsFromFlags(); err != nil {
+		return err
+	}
+	if server.idleForfeit, server.gameDeadline, err = gameDeadlinesFromFlags(); err != nil {
+		return err
+	}
+	if server.requestTimeout < 0 {
+		return configError("invalid --request_timeout %v, it can not be negative",
+			server.requestTimeout)
+	}
+	server.adminToken = *adminToken
+	tlsConfig, err := tlsConfigFromFlags()
+	if err != nil {
+		return configError("invalid TLS settings: %w", err)
+	}
+	if server.limits, err = serverLimitsFromFlags(); err != nil {
+		return configError("invalid rate limits: %w", err)
+	}
+	server.slackSecret = *slackSigningSecret
+	store, err := redisStoreFromFlags()
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		store.init = server.initSession
+		server.store = store
+		defer store.start()()
+	}
+	if chaos := chaosFromFlags(); chaos != nil {
+		defaultLogger.Infof("Chaos mode enabled, faults are injected on purpose")
+		server.withChaos(chaos)
+	}
+	if manager, ok := server.store.(*SessionManager); ok {
+		defer manager.StartEviction(maxEvictionInterval)()
+	}
+	defaultLogger.Infof("Serving the game on %s", *httpAddr)
+	return listenAndServe(*httpAddr, server.Handler(), tlsConfig)
+}
+
+// **************************  REST handlers ***************************
+
+func (s *gameServer) handleCreate(w http.ResponseWriter, r *http.Request) {
+	if apiErr := s.limits.allowCreate(s.limits.clientIP(r), time.Now()); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	var req api.CreateGameRequest
+	if apiErr := decodeRequest(w, r, &req); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	policy, err := ParseRetryPolicy(string(req.RetryPolicy))
+	if err != nil {
+		writeError(w, api.NewError(api.CodeInvalidRetries, "%s", err.Error()).
+			WithDetail("retry_policy", req.RetryPolicy))
+		return
+	}
+	opts := []GameOption{WithRetryPolicy(policy)}
+	if req.Preset != "" {
+		preset, apiErr := s.presets.get(req.Preset)
+		if apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+		var presetOpts []GameOption
+		req, presetOpts = applyPreset(req, preset)
+		opts = append(opts, presetOpts...)
+	}
+	lifetime, apiErr := s.gameLifetime(req)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if apiErr := validateCoopRequest(req); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if req.Challenge && !featureEnabled(featureChallengeGames, req.Player) {
+		writeError(w, featureDisabled(featureChallengeGames, "challenge games"))
+		return
+	}
+	if req.Challenge {
+		if req.WordLength, apiErr = s.challengeLength(req.WordLength); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+	}
+	game, err := NewGame(req.WordLength, req.Retries, opts...)
+	if err != nil {
+		writeError(w, inputErrorToAPI(err, req.WordLength, req.Retries))
+		return
+	}
+	if req.Coop {
+		seat, apiErr := s.addCoopSession(r.Context(), game, req)
+		if apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+		writeJSON(w, http.StatusCreated, seat)
+		return
+	}
+	sess, apiErr := s.addSession(r.Context(), game, req, lifetime, nil)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusCreated, sess.view())
+}
+
+func (s *gameServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	sess, apiErr := s.getSession(r.Context(), r.PathValue("id"))
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, sess.view())
+}
+
+func (s *gameServer) handleGuess(w http.ResponseWriter, r *http.Request) {
+	sess, apiErr := s.getSession(r.Context(), r.PathValue("id"))
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	var req api.GuessRequest
+	if apiErr := decodeRequest(w, r, &req); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	if apiErr := s.limits.allowGuess(s.limits.clientIP(r), sess.id, time.Now()); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	accepted, view, apiErr := sess.guess(r.Context(), req.Char, req.PlayerToken)
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, api.GuessResponse{Accepted: accepted, Game: view})
+}
+
+func (s *gameServer) handlePreview(w http.ResponseWriter, r *http.Request) {
+	sess, apiErr := s.getSession(r.Context(), r.PathValue("id"))
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	preview, apiErr := sess.getPreview()
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, preview)
+}
+
+func (s *gameServer) handleHint(w http.ResponseWriter, r *http.Request) {
+	sess, apiErr := s.getSession(r.Context(), r.PathValue("id"))
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	hint, apiErr := sess.hint()
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, hint)
+}
+
+// Method to decode the JSON body of a request.
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) *api.Error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return requestBodyError(err)
+	}
+	return nil
+}
+
+// Method to build the error of a request body which could not be read or
+// decoded.
+func requestBodyError(err error) *api.Error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return api.NewError(api.CodeInvalidRequest,
+			"the request body can not be larger than %d bytes", maxRequestBody).
+			WithDetail("max_bytes", maxRequestBody)
+	}
+	return api.NewError(api.CodeInvalidRequest, "invalid request body: %v", err)
+}
+
+func (s *gameServer) handleAbout(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, aboutDictionary(currentDictionary()))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, apiErr *api.Error) {
+	if seconds, ok := apiErr.Details["retry_after_seconds"].(int); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+	writeJSON(w, apiErr.Code.HTTPStatus(), api.ErrorResponse{Error: apiErr})
+}
+
+// *************************  WebSocket handler **************************
+
+func (s *gameServer) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	sess, apiErr := s.getSession(r.Context(), r.PathValue("id"))
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+	conn, err := upgradeWebsocket(w, r)
+	if err != nil {
+		defaultLogger.Errorf("WebSocket upgrade failed for game %s, error %v", sess.id, err)
+		return
+	}
+	ip := s.limits.clientIP(r)
+	spectate := r.URL.Query().Get("spectate")
+	wt := &watcher{
+		conn:        conn,
+		chaos:       s.chaos,
+		send:        make(chan api.Message, watcherBuffer),
+		spectator:   spectate == "1" || spectate == "true",
+		playerToken: r.URL.Query().Get("player_token"),
+	}
+	go wt.writeLoop()
+	sess.addWatcher(wt)
+	defer sess.removeWatcher(wt)
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg api.Message
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type != api.MessageGuess {
+			wt.sendError(api.NewError(api.CodeInvalidRequest,
+				"expected a message of type %q", api.MessageGuess))
+			continue
+		}
+		if wt.spectator {
+			wt.sendError(api.NewError(api.CodeForbidden, "spectators can not guess"))
+			continue
+		}
+		if apiErr := s.limits.allowGuess(ip, sess.id, time.Now()); apiErr != nil {
+			wt.sendError(apiErr)
+			continue
+		}
+		// The new state is sent to every watcher, including this one.
+		ctx, cancel := s.requestContext(r.Context())
+		if _, _, apiErr := sess.guess(ctx, msg.Char, wt.playerToken); apiErr != nil {
+			wt.sendError(apiErr)
+		}
+		cancel()
+	}
+}
+
+// Method to queue a message for the client.
+// Returns false if the queue is full (or the watcher is already dropped).
+func (wt *watcher) trySend(msg api.Message) bool {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+	if wt.closed {
+		return false
+	}
+	select {
+	case wt.send <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+// Method to stop sending messages to the client. The connection is closed once
+// the queued messages are written.
+func (wt *watcher) close() {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+	if !wt.closed {
+		wt.closed = true
+		close(wt.send)
+	}
+}
+
+func (wt *watcher) sendError(apiErr *api.Error) {
+	wt.trySend(api.Message{Type: api.MessageError, Error: apiErr})
+}
+
+// Method to write the queued messages to the client till the send channel is
+// closed.
+func (wt *watcher) writeLoop() {
+	defer wt.conn.Close()
+	for msg := range wt.send {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			continue
+		}
+		wt.chaos.delay()
+		if wt.chaos.dropMessage() {
+			continue
+		}
+		if err := wt.conn.WriteText(data); err != nil {
+			return
+		}
+	}
+}
+
+// ***************************  Sessions *******************************
+
+// Method to store a new game and assign it an id. coop holds the host of a
+// cooperative game, which is given a join code, and is nil for the other
+// games. The game is not stored if the context is done first.
+func (s *gameServer) addSession(ctx context.Context, game *Game, req api.CreateGameRequest,
+	lifetime time.Duration, coop *coopSeats) (*session, *api.Error) {
+	sess := &session{
+		id:        newSessionID(),
+		game:      game,
+		practice:  req.Practice,
+		preset:    req.Preset,
+		player:    req.Player,
+		created:   time.Now(),
+		challenge: req.Challenge,
+		coop:      coop,
+	}
+	s.setDeadlines(sess, lifetime)
+	if coop != nil {
+		coop.Code = s.coop.add(sess.id)
+	}
+	s.initSession(sess)
+	sess.mu.Lock()
+	// The expiry is part of the state saved by the store.
+	sess.resetExpiryLocked(sess.created)
+	sess.mu.Unlock()
+	if err := s.store.add(ctx, sess); err != nil {
+		// The game is never played, so it does not count as an active session.
+		sess.close()
+		if coop != nil {
+			s.coop.remove(coop.Code)
+		}
+		if apiErr := contextErrorToAPI(err); apiErr != nil {
+			return nil, apiErr
+		}
+		defaultLogger.Errorf("Unable to store game %s, error %v", sess.id, err)
+		return nil, api.NewError(api.CodeInternal, "unable to store the game")
+	}
+	return sess, nil
+}
+
+// Method to set up the parts of a session which come from the server rather
+// than from its game, for a new session or one loaded from the store.
+func (s *gameServer) initSession(sess *session) {
+	sess.watchers = make(map[*watcher]bool)
+	sess.leaderboard = s.leaderboard
+	sess.shame = s.shame
+	sess.warningTimes = s.lifetimeWarnings
+	sess.store = s.store
+	sess.game.AddObserver(sessionObserver{sess})
+	sess.mu.Lock()
+	sess.armTurnTimerLocked()
+	sess.mu.Unlock()
+}
+
+func (s *gameServer) getSession(ctx context.Context, id string) (*session, *api.Error) {
+	sess, err := s.store.get(ctx, id)
+	if err == errSessionNotFound {
+		return nil, api.NewError(api.CodeGameNotFound, "no game with id %q", id).
+			WithDetail("id", id)
+	}
+	if apiErr := contextErrorToAPI(err); apiErr != nil {
+		return nil, apiErr.WithDetail("id", id)
+	}
+	if err != nil {
+		defaultLogger.Errorf("Unable to load game %s, error %v", id, err)
+		return nil, api.NewError(api.CodeInternal, "unable to load the game").
+			WithDetail("id", id)
+	}
+	return sess, nil
+}
+
+// Method to generate a random id for a session, a version 4 UUID.
+func newSessionID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// Method to apply a guess to the game and send the new state to the watchers.
+// token is the token of the player guessing in a cooperative game, which must
+// be the player whose turn it is, and is ignored for the other games.
+// The guess is not made if the context is done before it is applied, but once
+// applied it is saved even if the context is canceled, and undone if it can
+// not be saved, so that the game in memory never gets ahead of the store.
+func (sess *session) guess(ctx context.Context, char, token string) (bool, api.Game,
+	*api.Error) {
+	if utf8.RuneCountInString(char) != 1 {
+		return false, api.Game{}, api.NewError(api.CodeInvalidCharacter,
+			"a guess must be a single character").WithDetail("character", char)
+	}
+	r, _ := utf8.DecodeRuneInString(char)
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	var accepted bool
+	var now time.Time
+	for attempt := 1; ; attempt++ {
+		if sess.coop != nil {
+			if apiErr := sess.coop.checkTurn(token); apiErr != nil {
+				return false, api.Game{}, apiErr
+			}
+		}
+		if apiErr := contextErrorToAPI(ctx.Err()); apiErr != nil {
+			return false, api.Game{}, apiErr.WithDetail("id", sess.id)
+		}
+		checkpoint := sess.checkpointLocked()
+		turns := len(sess.game.Turns)
+		// A guess given after the deadline of its turn is a timeout, like
+		// in the terminal game.
+		turnCtx, cancel := sess.game.TurnContext(ctx)
+		var err error
+		accepted, err = sess.game.CheckUserInputCtx(turnCtx, r)
+		cancel()
+		if err != nil {
+			// The deadlines missed before the guess may have lost the game.
+			if len(sess.game.Turns) > turns {
+				sess.applyTimeoutsLocked(context.WithoutCancel(ctx))
+			}
+			if apiErr := contextErrorToAPI(err); apiErr != nil {
+				return false, api.Game{}, apiErr.WithDetail("id", sess.id)
+			}
+			return false, api.Game{}, guessErrorToAPI(r, err)
+		}
+		if sess.coop != nil {
+			sess.coop.nextTurn()
+		}
+		now = time.Now()
+		if sess.game.State == Running {
+			sess.resetExpiryLocked(now)
+		} else if sess.expiryTimer != nil {
+			sess.expiryTimer.Stop()
+		}
+		err = sess.saveLocked(context.WithoutCancel(ctx))
+		if err == nil {
+			break
+		}
+		// The game was changed by another server, and has been reloaded: the
+		// guess is made again on the new state.
+		if err == errSessionConflict && attempt < maxSaveAttempts {
+			continue
+		}
+		// After a conflict the game was already reloaded with the saved
+		// state. Otherwise the guess is undone, so that a retry of the guess
+		// is made again.
+		if err != errSessionConflict {
+			sess.rollbackLocked(checkpoint)
+		}
+		defaultLogger.Errorf("Unable to save game %s, error %v", sess.id, err)
+		return false, api.Game{}, api.NewError(api.CodeInternal, "unable to save the game").
+			WithDetail("id", sess.id)
+	}
+	sess.recordLocked(now)
+	sess.preview = nil
+	view := sess.viewLocked()
+	sess.broadcastLocked(api.Message{
+		Type:      api.MessageState,
+		LastGuess: char,
+		Accepted:  &accepted,
+		Game:      &view,
+	})
+	if sess.practice && len(sess.watchers) > 0 {
+		sess.broadcastLocked(api.Message{
+			Type:    api.MessagePreview,
+			Preview: sess.previewLocked(),
+		})
+	}
+	return accepted, view, nil
+}
+
+// Method to get the preview of the next guess.
+// Returns an error if the game is not a practice game.
+func (sess *session) getPreview() (*api.Preview, *api.Error) {
+	if !sess.practice {
+		return nil, api.NewError(api.CodeForbidden,
+			"previews are only available for practice games")
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.previewLocked(), nil
+}
+
+// Method to get the clue of the word, see Game.Hint.
+// Returns an error if the remaining words do not share a clue.
+func (sess *session) hint() (*api.Hint, *api.Error) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	clue, ok := sess.game.Hint()
+	if !ok {
+		return nil, api.NewError(api.CodeHintUnavailable,
+			"the words of the game do not share a clue yet")
+	}
+	return &api.Hint{GameID: sess.id, Hint: clue}, nil
+}
+
+// Method to get the cached preview, computing it if needed. Must be called
+// with the session lock held.
+func (sess *session) previewLocked() *api.Preview {
+	if sess.preview != nil {
+		return sess.preview
+	}
+	preview := &api.Preview{
+		GameID:  sess.id,
+		Guesses: len(sess.game.UsedChars),
+		Letters: []api.LetterPreview{},
+	}
+	if sess.game.State == Running {
+		for _, p := range sess.game.PreviewAll() {
+			preview.Letters = append(preview.Letters, api.LetterPreview{
+				Char:        string(p.Char),
+				Probability: p.Probability,
+				WouldAccept: p.WouldAccept,
+			})
+		}
+	}
+	sess.preview = preview
+	return preview
+}
+
+// Method to start sending the updates of the game to a watcher. The current
+// state is sent right away.
+func (sess *session) addWatcher(wt *watcher) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	view := sess.viewLocked()
+	wt.trySend(api.Message{Type: api.MessageState, Game: &view})
+	if sess.practice {
+		wt.trySend(api.Message{Type: api.MessagePreview, Preview: sess.previewLocked()})
+	}
+	sess.watchers[wt] = true
+}
+
+func (sess *session) removeWatcher(wt *watcher) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	delete(sess.watchers, wt)
+	wt.close()
+}
+
+// Method to send a message to all the watchers. Watchers which can not keep up
+// are dropped. Must be called with the session lock held.
+func (sess *session) broadcastLocked(msg api.Message) {
+	for wt := range sess.watchers {
+		if !wt.trySend(msg) {
+			delete(sess.watchers, wt)
+			wt.close()
+		}
+	}
+}
+
+func (sess *session) view() api.Game {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.viewLocked()
+}
+
+// Method to build the public view of the game. Must be called with the session
+// lock held.
+func (sess *session) viewLocked() api.Game {
+	g := sess.game
+	view := apiGame(sess.id, g)
+	view.Practice = sess.practice
+	view.Preset = sess.preset
+	view.Player = sess.player
+	view.Challenge = sess.challenge
+	if sess.coop != nil {
+		view.Coop = sess.coop.view()
+	}
+	if sess.lifetime > 0 && g.State == Running {
+		expiresAt := sess.expiresAt
+		view.ExpiresAt = &expiresAt
+		view.ExpiryWarnings = sess.warningsSent
+	}
+	return view
+}
+
+// Method to get the public view of a game, without the settings of the
+// session it is played in. The game must not be played concurrently.
+func apiGame(id string, g *Game) api.Game {
+	retries := g.CurrentRetries
+	if retries < 0 {
+		retries = 0
+	}
+	_, hintAvailable := g.Hint()
+	view := api.Game{
+		ID:             id,
+		WordLength:     g.ExpectedLength,
+		MaskedWord:     DefaultPatternFormat.Format(g.CurrentDisplayedWord),
+		UsedChars:      string(g.UsedChars),
+		RetriesLeft:    retries,
+		AllowedRetries: g.AllowedRetries,
+		State:          apiGameState(g.State),
+		RetryPolicy:    g.RetryPolicy.apiPolicy(),
+		HintAvailable:  hintAvailable,
+		Forfeited:      g.State == Forfeited,
+	}
+	return view
+}
+
+// Method to convert the state of a game to its API representation.
+func apiGameState(state GameState) api.GameState {
+	switch state {
+	case Won:
+		return api.StateWon
+	case Lost:
+		return api.StateLost
+	case Forfeited:
+		return api.StateForfeited
+	}
+	return api.StateRunning
+}
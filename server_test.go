@@ -0,0 +1,416 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Minimal WebSocket client used to test the server.
+type testWSClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func dialTestWS(t *testing.T, serverURL, path string) *testWSClient {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+	req := "GET " + path + " HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\n" +
+		"Connection: Upgrade\r\nSec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n\r\n"
+	conn.Write([]byte(req))
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		resp.Header.Get("Sec-Websocket-Accept") != websocketAcceptKey(key) {
+		t.Fatalf("Unexpected handshake response %v", resp.Status)
+	}
+	return &testWSClient{conn: conn, br: br}
+}
+
+// Method to send a masked text frame.
+func (c *testWSClient) send(msg api.Message) {
+	payload, _ := json.Marshal(msg)
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{0x81, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	c.conn.Write(frame)
+}
+
+// Method to read an unmasked text frame.
+func (c *testWSClient) read(t *testing.T) api.Message {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.br, header); err != nil {
+		t.Fatal(err)
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		ext := make([]byte, 2)
+		io.ReadFull(c.br, ext)
+		length = int(binary.BigEndian.Uint16(ext))
+	}
+	payload := make([]byte, length)
+	io.ReadFull(c.br, payload)
+	var msg api.Message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+type ServerTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+func (s *ServerTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	s.server = httptest.NewServer(newGameServer().Handler())
+}
+
+func (s *ServerTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ServerTestSuite) post(path string, body string, out interface{}) int {
+	resp, err := http.Post(s.server.URL+path, "application/json",
+		bytes.NewBufferString(body))
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+func (s *ServerTestSuite) createGame() api.Game {
+	var game api.Game
+	status := s.post("/games", `{"word_length": 4, "retries": 3}`, &game)
+	assert.Equal(s.T(), http.StatusCreated, status)
+	return game
+}
+
+func (s *ServerTestSuite) TestRestGame() {
+	game := s.createGame()
+	assert.Equal(s.T(), "____", game.MaskedWord)
+	assert.Equal(s.T(), api.StateRunning, game.State)
+
+	var guess api.GuessResponse
+	status := s.post("/games/"+game.ID+"/guesses", `{"char": "a"}`, &guess)
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.Equal(s.T(), false, guess.Accepted)
+	assert.Equal(s.T(), 2, guess.Game.RetriesLeft)
+	assert.Equal(s.T(), "a", guess.Game.UsedChars)
+
+	resp, err := http.Get(s.server.URL + "/games/" + game.ID)
+	assert.Nil(s.T(), err)
+	var fetched api.Game
+	json.NewDecoder(resp.Body).Decode(&fetched)
+	resp.Body.Close()
+	assert.Equal(s.T(), guess.Game, fetched)
+}
+
+func (s *ServerTestSuite) TestRestErrors() {
+	var errResp api.ErrorResponse
+	status := s.post("/games", `{"word_length": 9, "retries": 3}`, &errResp)
+	assert.Equal(s.T(), http.StatusBadRequest, status)
+	assert.Equal(s.T(), api.CodeInvalidLength, errResp.Error.Code)
+
+	// Rejected by the OpenAPI document before reaching the game.
+	status = s.post("/games", `{"word_length": -1, "retries": 3}`, &errResp)
+	assert.Equal(s.T(), http.StatusBadRequest, status)
+	assert.Equal(s.T(), api.CodeInvalidRequest, errResp.Error.Code)
+	assert.Equal(s.T(), "word_length", errResp.Error.Details["field"])
+	_, err := NewGame(-1, 3)
+	assert.True(s.T(), errors.Is(err, ErrInvalidLength))
+
+	status = s.post("/games/unknown/guesses", `{"char": "a"}`, &errResp)
+	assert.Equal(s.T(), http.StatusNotFound, status)
+	assert.Equal(s.T(), api.CodeGameNotFound, errResp.Error.Code)
+
+	game := s.createGame()
+	status = s.post("/games/"+game.ID+"/guesses", `{"char": "ab"}`, &errResp)
+	assert.Equal(s.T(), http.StatusBadRequest, status)
+	assert.Equal(s.T(), api.CodeInvalidCharacter, errResp.Error.Code)
+
+	s.post("/games/"+game.ID+"/guesses", `{"char": "a"}`, &api.GuessResponse{})
+	status = s.post("/games/"+game.ID+"/guesses", `{"char": "a"}`, &errResp)
+	assert.Equal(s.T(), http.StatusConflict, status)
+	assert.Equal(s.T(), api.CodeCharacterUsed, errResp.Error.Code)
+}
+
+func (s *ServerTestSuite) TestRequestTimeout() {
+	server := newGameServer()
+	handler := server.Handler()
+	serve := func(ctx context.Context, method, path, body string, out interface{}) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body)).WithContext(ctx)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		json.NewDecoder(rec.Body).Decode(out)
+		return rec.Code
+	}
+	var game api.Game
+	s.Require().Equal(http.StatusCreated, serve(context.Background(), "POST", "/games",
+		`{"word_length": 4, "retries": 3}`, &game))
+
+	// The guess of a client which went away is not made.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var errResp api.ErrorResponse
+	status := serve(ctx, "POST", "/games/"+game.ID+"/guesses", `{"char": "s"}`, &errResp)
+	assert.Equal(s.T(), http.StatusServiceUnavailable, status)
+	assert.Equal(s.T(), api.CodeTimeout, errResp.Error.Code)
+
+	// Neither is a guess past the deadline of --request_timeout.
+	server.requestTimeout = time.Nanosecond
+	errResp = api.ErrorResponse{}
+	status = serve(context.Background(), "POST", "/games/"+game.ID+"/guesses",
+		`{"char": "s"}`, &errResp)
+	assert.Equal(s.T(), http.StatusServiceUnavailable, status)
+	assert.Equal(s.T(), api.CodeTimeout, errResp.Error.Code)
+
+	server.requestTimeout = time.Minute
+	var guess api.GuessResponse
+	status = serve(context.Background(), "POST", "/games/"+game.ID+"/guesses",
+		`{"char": "s"}`, &guess)
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.Equal(s.T(), "s", guess.Game.UsedChars)
+}
+
+func (s *ServerTestSuite) TestRetryPolicy() {
+	assert.Equal(s.T(), api.RetryStrict, s.createGame().RetryPolicy)
+
+	var game api.Game
+	status := s.post("/games", `{"word_length": 4, "retries": 0, "retry_policy": "unlimited"}`,
+		&game)
+	assert.Equal(s.T(), http.StatusCreated, status)
+	assert.Equal(s.T(), api.RetryUnlimited, game.RetryPolicy)
+	var guess api.GuessResponse
+	s.post("/games/"+game.ID+"/guesses", `{"char": "i"}`, &guess)
+	assert.Equal(s.T(), api.StateRunning, guess.Game.State)
+	assert.Equal(s.T(), 0, guess.Game.RetriesLeft)
+
+	var errResp api.ErrorResponse
+	status = s.post("/games", `{"word_length": 4, "retries": 3, "retry_policy": "forgiving"}`,
+		&errResp)
+	assert.Equal(s.T(), http.StatusBadRequest, status)
+	assert.Equal(s.T(), api.CodeInvalidRetries, errResp.Error.Code)
+}
+
+func (s *ServerTestSuite) TestWebsocketUpdates() {
+	game := s.createGame()
+	player := dialTestWS(s.T(), s.server.URL, "/games/"+game.ID+"/ws")
+	defer player.conn.Close()
+	spectator := dialTestWS(s.T(), s.server.URL, "/games/"+game.ID+"/ws?spectate=1")
+	defer spectator.conn.Close()
+
+	// Everyone gets the current state on connecting.
+	msg := player.read(s.T())
+	assert.Equal(s.T(), api.MessageState, msg.Type)
+	assert.Equal(s.T(), "____", msg.Game.MaskedWord)
+	spectator.read(s.T())
+
+	player.send(api.Message{Type: api.MessageGuess, Char: "s"})
+	for _, client := range []*testWSClient{player, spectator} {
+		msg = client.read(s.T())
+		assert.Equal(s.T(), api.MessageState, msg.Type)
+		assert.Equal(s.T(), "s", msg.LastGuess)
+		assert.Equal(s.T(), true, *msg.Accepted)
+		assert.Equal(s.T(), "s", msg.Game.UsedChars)
+	}
+
+	// Guesses over REST are streamed too.
+	s.post("/games/"+game.ID+"/guesses", `{"char": "z"}`, &api.GuessResponse{})
+	msg = spectator.read(s.T())
+	assert.Equal(s.T(), "z", msg.LastGuess)
+	assert.Equal(s.T(), false, *msg.Accepted)
+
+	// Spectators can not guess.
+	spectator.send(api.Message{Type: api.MessageGuess, Char: "o"})
+	msg = spectator.read(s.T())
+	assert.Equal(s.T(), api.MessageError, msg.Type)
+	assert.Equal(s.T(), api.CodeForbidden, msg.Error.Code)
+}
+
+func (s *ServerTestSuite) TestPreview() {
+	var errResp api.ErrorResponse
+	game := s.createGame()
+	resp, err := http.Get(s.server.URL + "/games/" + game.ID + "/preview")
+	assert.Nil(s.T(), err)
+	json.NewDecoder(resp.Body).Decode(&errResp)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusForbidden, resp.StatusCode)
+	assert.Equal(s.T(), api.CodeForbidden, errResp.Error.Code)
+
+	status := s.post("/games", `{"word_length": 4, "retries": 3, "practice": true}`, &game)
+	assert.Equal(s.T(), http.StatusCreated, status)
+	assert.Equal(s.T(), true, game.Practice)
+	client := dialTestWS(s.T(), s.server.URL, "/games/"+game.ID+"/ws")
+	defer client.conn.Close()
+	assert.Equal(s.T(), api.MessageState, client.read(s.T()).Type)
+	msg := client.read(s.T())
+	assert.Equal(s.T(), api.MessagePreview, msg.Type)
+	assert.Equal(s.T(), 0, msg.Preview.Guesses)
+
+	s.post("/games/"+game.ID+"/guesses", `{"char": "a"}`, &api.GuessResponse{})
+	assert.Equal(s.T(), api.MessageState, client.read(s.T()).Type)
+	msg = client.read(s.T())
+	assert.Equal(s.T(), api.MessagePreview, msg.Type)
+	assert.Equal(s.T(), 1, msg.Preview.Guesses)
+
+	// The REST endpoint returns the same cached preview.
+	resp, err = http.Get(s.server.URL + "/games/" + game.ID + "/preview")
+	assert.Nil(s.T(), err)
+	var preview api.Preview
+	json.NewDecoder(resp.Body).Decode(&preview)
+	resp.Body.Close()
+	assert.Equal(s.T(), *msg.Preview, preview)
+}
+
+func (s *ServerTestSuite) TestAbout() {
+	resp, err := http.Get(s.server.URL + "/about")
+	assert.Nil(s.T(), err)
+	var about api.About
+	json.NewDecoder(resp.Body).Decode(&about)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), api.About{WordCount: 4}, about)
+}
+
+func (s *ServerTestSuite) TestWebUI() {
+	resp, err := http.Get(s.server.URL + "/")
+	assert.Nil(s.T(), err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Contains(s.T(), resp.Header.Get("Content-Type"), "text/html")
+	assert.Contains(s.T(), string(body), `<script src="ui/app.js">`)
+	assert.Contains(s.T(), string(body), `name="practice"`)
+
+	resp, err = http.Get(s.server.URL + "/ui/app.js")
+	assert.Nil(s.T(), err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusOK, resp.StatusCode)
+	assert.Contains(s.T(), string(body), "/games/${id}/ws")
+
+	server := newGameServer()
+	server.webUI = false
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(s.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (s *ServerTestSuite) TestGuessTimeout() {
+	server := newGameServer()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+	newSession := func() *session {
+		game, err := NewGame(4, 1, WithGuessTimeout(50*time.Millisecond))
+		s.Require().NoError(err)
+		sess, apiErr := server.addSession(context.Background(), game,
+			api.CreateGameRequest{WordLength: 4, Retries: 1}, 0, nil)
+		s.Require().Nil(apiErr)
+		return sess
+	}
+
+	// The game is lost at the deadline, without waiting for a guess.
+	sess := newSession()
+	client := dialTestWS(s.T(), httpServer.URL, "/games/"+sess.id+"/ws")
+	defer client.conn.Close()
+	assert.Equal(s.T(), api.StateRunning, client.read(s.T()).Game.State)
+	msg := client.read(s.T())
+	assert.Equal(s.T(), api.MessageState, msg.Type)
+	assert.Equal(s.T(), api.StateLost, msg.Game.State)
+	assert.Equal(s.T(), 0, msg.Game.RetriesLeft)
+
+	// A guess made after the deadlines lost the game sends the lost game too.
+	sess = newSession()
+	sess.mu.Lock()
+	sess.turnTimer.Stop()
+	sess.mu.Unlock()
+	late := dialTestWS(s.T(), httpServer.URL, "/games/"+sess.id+"/ws")
+	defer late.conn.Close()
+	assert.Equal(s.T(), api.StateRunning, late.read(s.T()).Game.State)
+	time.Sleep(60 * time.Millisecond)
+	_, _, apiErr := sess.guess(context.Background(), "s", "")
+	s.Require().NotNil(apiErr)
+	assert.Equal(s.T(), api.CodeGameExpired, apiErr.Code)
+	msg = late.read(s.T())
+	assert.Equal(s.T(), api.StateLost, msg.Game.State)
+	assert.False(s.T(), sess.ended)
+
+	// A guess given after the deadline of its turn is a timeout, like in the
+	// terminal game.
+	game, err := NewGame(4, 3, WithGuessTimeout(100*time.Millisecond))
+	s.Require().NoError(err)
+	sess, apiErr = server.addSession(context.Background(), game,
+		api.CreateGameRequest{WordLength: 4, Retries: 3}, 0, nil)
+	s.Require().Nil(apiErr)
+	sess.mu.Lock()
+	sess.turnTimer.Stop()
+	sess.mu.Unlock()
+	time.Sleep(130 * time.Millisecond)
+	_, _, apiErr = sess.guess(context.Background(), "s", "")
+	s.Require().NotNil(apiErr)
+	assert.Equal(s.T(), api.CodeTurnTimeout, apiErr.Code)
+	assert.Equal(s.T(), http.StatusConflict, apiErr.Code.HTTPStatus())
+	view := sess.view()
+	assert.Equal(s.T(), api.StateRunning, view.State)
+	assert.Equal(s.T(), 2, view.RetriesLeft)
+	assert.Equal(s.T(), "", view.UsedChars)
+}
+
+func (s *ServerTestSuite) TestHint() {
+	InitGame([]string{"cats|animal", "rats|animal", "blue|color", "grey|color"})
+	defer InitGame([]string{"last", "fast", "bets", "code"})
+	game := s.createGame()
+	assert.False(s.T(), game.HintAvailable)
+	var errResp api.ErrorResponse
+	resp, err := http.Get(s.server.URL + "/games/" + game.ID + "/hint")
+	assert.Nil(s.T(), err)
+	json.NewDecoder(resp.Body).Decode(&errResp)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusConflict, resp.StatusCode)
+	assert.Equal(s.T(), api.CodeHintUnavailable, errResp.Error.Code)
+
+	var guess api.GuessResponse
+	s.post("/games/"+game.ID+"/guesses", `{"char": "s"}`, &guess)
+	assert.True(s.T(), guess.Game.HintAvailable)
+	resp, err = http.Get(s.server.URL + "/games/" + game.ID + "/hint")
+	assert.Nil(s.T(), err)
+	var hint api.Hint
+	json.NewDecoder(resp.Body).Decode(&hint)
+	resp.Body.Close()
+	assert.Equal(s.T(), api.Hint{GameID: game.ID, Hint: "color"}, hint)
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
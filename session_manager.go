@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"sync"
+	"time"
+)
+
+var (
+	sessionTTL = flag.Duration("session_ttl", 24*time.Hour,
+		"Time after which the server forgets a game nobody played or watched, so "+
+			"that its memory does not grow forever. Correspondence games are kept "+
+			"for at least their lifetime. Games are never forgotten if zero.")
+)
+
+const (
+	// Max time between two checks for idle sessions.
+	maxEvictionInterval = time.Minute
+)
+
+// Hooks called by a session manager, e.g. to export metrics. Unset hooks are
+// not called.
+type SessionHooks struct {
+	// Called with the number of sessions stored after every change.
+	Stored func(count int)
+	// Called for every session evicted after being idle.
+	Evicted func(id string)
+}
+
+// Session store keeping the sessions in memory, which forgets the sessions
+// left idle for longer than a TTL. A session is active while it is looked up
+// (i.e. for every request about its game) or watched over WebSocket.
+type SessionManager struct {
+	// Guards the fields below.
+	mu       sync.Mutex
+	sessions map[string]*managedSession
+	// Idle time after which a session is evicted, zero to keep the sessions
+	// forever.
+	ttl   time.Duration
+	clock Clock
+	hooks SessionHooks
+}
+
+// Session stored by a session manager.
+type managedSession struct {
+	sess       *session
+	lastActive time.Time
+}
+
+// Method to create a session manager evicting the sessions idle for longer
+// than ttl, see StartEviction.
+func NewSessionManager(ttl time.Duration, hooks SessionHooks) *SessionManager {
+	return &SessionManager{
+		sessions: make(map[string]*managedSession),
+		ttl:      ttl,
+		clock:    realClock{},
+		hooks:    hooks,
+	}
+}
+
+// Method to create the session manager of the server, reporting to the
+// metrics of the process.
+func newServerSessionManager() *SessionManager {
+	return NewSessionManager(*sessionTTL, SessionHooks{
+		Stored: gameMetrics.setStoredSessions,
+		Evicted: func(id string) {
+			gameMetrics.sessionEvicted()
+			defaultLogger.Infof("Game %s was idle for too long, it is forgotten", id)
+		},
+	})
+}
+
+func (m *SessionManager) add(ctx context.Context, sess *session) error {
+	m.mu.Lock()
+	m.sessions[sess.id] = &managedSession{sess: sess, lastActive: m.clock.Now()}
+	count := len(m.sessions)
+	m.mu.Unlock()
+	m.storedChanged(count)
+	return nil
+}
+
+// Method to find a session by its id, which marks it as active.
+func (m *SessionManager) get(ctx context.Context, id string) (*session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	managed, ok := m.sessions[id]
+	if !ok {
+		return nil, errSessionNotFound
+	}
+	managed.lastActive = m.clock.Now()
+	return managed.sess, nil
+}
+
+// Method to find a session by its id, without marking it as active.
+func (m *SessionManager) peek(id string) (*session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	managed, ok := m.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	return managed.sess, true
+}
+
+// Method to save a session. The sessions are kept in memory, so there is
+// nothing to do.
+func (m *SessionManager) save(ctx context.Context, sess *session) error {
+	return nil
+}
+
+// Method to get the number of sessions stored.
+func (m *SessionManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// Method to evict the sessions idle for longer than the TTL at the given time.
+// The sessions watched over WebSocket are kept, and so are the correspondence
+// games till they expire.
+// Returns the number of sessions evicted.
+func (m *SessionManager) EvictIdle(now time.Time) int {
+	if m.ttl <= 0 {
+		return 0
+	}
+	m.mu.Lock()
+	var evicted []*session
+	for id, managed := range m.sessions {
+		ttl := m.ttl
+		if managed.sess.lifetime > ttl {
+			ttl = managed.sess.lifetime
+		}
+		if now.Sub(managed.lastActive) < ttl || managed.sess.watched() {
+			continue
+		}
+		delete(m.sessions, id)
+		evicted = append(evicted, managed.sess)
+	}
+	count := len(m.sessions)
+	m.mu.Unlock()
+	if len(evicted) == 0 {
+		return 0
+	}
+	for _, sess := range evicted {
+		sess.close()
+		if m.hooks.Evicted != nil {
+			m.hooks.Evicted(sess.id)
+		}
+	}
+	m.storedChanged(count)
+	return len(evicted)
+}
+
+// Method to evict the idle sessions in the background, every interval (or
+// more often if the TTL is short). Returns a function stopping it.
+func (m *SessionManager) StartEviction(interval time.Duration) func() {
+	if m.ttl <= 0 {
+		return func() {}
+	}
+	if interval <= 0 || interval > m.ttl/2 {
+		interval = m.ttl / 2
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				m.EvictIdle(m.clock.Now())
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// Method to call the hook of the number of sessions stored.
+func (m *SessionManager) storedChanged(count int) {
+	if m.hooks.Stored != nil {
+		m.hooks.Stored(count)
+	}
+}
+
+// Method to check if a session is watched over WebSocket.
+func (sess *session) watched() bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return len(sess.watchers) > 0
+}
+
+// Method to release a session once it is forgotten: its expiry timer is
+// stopped, and a game still running no longer counts as active.
+func (sess *session) close() {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.expiryTimer != nil {
+		sess.expiryTimer.Stop()
+	}
+	if sess.turnTimer != nil {
+		sess.turnTimer.Stop()
+	}
+	if sess.game.State == Running {
+		gameMetrics.sessionEnded()
+	}
+}
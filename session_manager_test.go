@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type SessionManagerTestSuite struct {
+	suite.Suite
+	clock   *fakeClock
+	manager *SessionManager
+	stored  []int
+	evicted []string
+}
+
+func (s *SessionManagerTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+	s.clock = &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.stored = nil
+	s.evicted = nil
+	s.manager = NewSessionManager(time.Hour, SessionHooks{
+		Stored:  func(count int) { s.stored = append(s.stored, count) },
+		Evicted: func(id string) { s.evicted = append(s.evicted, id) },
+	})
+	s.manager.clock = s.clock
+}
+
+func (s *SessionManagerTestSuite) newSession(id string) *session {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	sess := &session{id: id, game: game, watchers: make(map[*watcher]bool)}
+	assert.Nil(s.T(), s.manager.add(context.Background(), sess))
+	return sess
+}
+
+func (s *SessionManagerTestSuite) TestAddGet() {
+	sess := s.newSession("a")
+	got, err := s.manager.get(context.Background(), "a")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), sess, got)
+	_, err = s.manager.get(context.Background(), "b")
+	assert.Equal(s.T(), errSessionNotFound, err)
+	assert.Equal(s.T(), 1, s.manager.Len())
+	assert.Equal(s.T(), []int{1}, s.stored)
+}
+
+func (s *SessionManagerTestSuite) TestEvictIdle() {
+	s.newSession("a")
+	s.newSession("b")
+	s.clock.Advance(45 * time.Minute)
+	s.manager.get(context.Background(), "b")
+	s.clock.Advance(30 * time.Minute)
+	assert.Equal(s.T(), 1, s.manager.EvictIdle(s.clock.Now()))
+	assert.Equal(s.T(), []string{"a"}, s.evicted)
+	assert.Equal(s.T(), []int{1, 2, 1}, s.stored)
+	_, err := s.manager.get(context.Background(), "a")
+	assert.Equal(s.T(), errSessionNotFound, err)
+	_, err = s.manager.get(context.Background(), "b")
+	assert.Nil(s.T(), err)
+}
+
+func (s *SessionManagerTestSuite) TestKeepCorrespondence() {
+	sess := s.newSession("a")
+	sess.lifetime = 3 * time.Hour
+	s.clock.Advance(2 * time.Hour)
+	assert.Equal(s.T(), 0, s.manager.EvictIdle(s.clock.Now()))
+	s.clock.Advance(time.Hour)
+	assert.Equal(s.T(), 1, s.manager.EvictIdle(s.clock.Now()))
+}
+
+func (s *SessionManagerTestSuite) TestKeepWatched() {
+	sess := s.newSession("a")
+	sess.watchers[&watcher{}] = true
+	s.clock.Advance(2 * time.Hour)
+	assert.Equal(s.T(), 0, s.manager.EvictIdle(s.clock.Now()))
+	assert.Equal(s.T(), 1, s.manager.Len())
+}
+
+func (s *SessionManagerTestSuite) TestNoTTL() {
+	s.manager.ttl = 0
+	s.newSession("a")
+	s.clock.Advance(1000 * time.Hour)
+	assert.Equal(s.T(), 0, s.manager.EvictIdle(s.clock.Now()))
+	assert.Equal(s.T(), 1, s.manager.Len())
+}
+
+func (s *SessionManagerTestSuite) TestSessionID() {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	id := newSessionID()
+	assert.True(s.T(), uuid.MatchString(id), id)
+	assert.NotEqual(s.T(), id, newSessionID())
+}
+
+func (s *SessionManagerTestSuite) TestEvictedGameNotFound() {
+	gameServer := newGameServer()
+	gameServer.store = s.manager
+	server := httptest.NewServer(gameServer.Handler())
+	defer server.Close()
+	s.newSession("a")
+	s.clock.Advance(2 * time.Hour)
+	s.manager.EvictIdle(s.clock.Now())
+	resp, err := http.Get(server.URL + "/games/a")
+	assert.Nil(s.T(), err)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+func TestSessionManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionManagerTestSuite))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Error returned by a session store when no session has the given id.
+var errSessionNotFound = errors.New("session not found")
+
+// Error returned by a session store when a session can not be saved because
+// its game was changed by another server since it was loaded. The session is
+// reloaded with the saved state before the error is returned.
+var errSessionConflict = errors.New("session changed concurrently")
+
+const (
+	// Max number of times a guess is tried while its game keeps being changed
+	// by other servers.
+	maxSaveAttempts = 5
+)
+
+// Storage of the sessions of the server. The context is the one of the request
+// using the session: the stores doing I/O give up once it is done, and get the
+// tracing data of the request from it.
+type sessionStore interface {
+	// Method to store a new session.
+	add(ctx context.Context, sess *session) error
+	// Method to find a session by its id. Returns errSessionNotFound if it does
+	// not exist.
+	get(ctx context.Context, id string) (*session, error)
+	// Method to save a session after its game changed. Must be called with
+	// the session lock held. Returns errSessionConflict if the game was
+	// changed concurrently.
+	save(ctx context.Context, sess *session) error
+}
+
+// Method to save the session to its store, if any. Must be called with the
+// session lock held.
+func (sess *session) saveLocked(ctx context.Context) error {
+	if sess.store == nil {
+		return nil
+	}
+	return sess.store.save(ctx, sess)
+}
+
+// State of a session before a change, to undo the change if it can not be
+// saved. Without it, the game in memory would get ahead of the store: the
+// other servers would never see the change, and the store would not take the
+// version saved last by another server, see redisStore.applyLocked.
+type sessionCheckpoint struct {
+	game         Snapshot
+	dict         *Dictionary
+	deadline     time.Time
+	turns        int
+	lastDecision *Decision
+	revealed     string
+	replaySteps  int
+	analysis     int
+	coop         *coopSeats
+	expiresAt    time.Time
+	nextWarning  int
+	warningsSent int
+	ended        bool
+}
+
+// Method to take the checkpoint of the session. Must be called with the
+// session lock held.
+func (sess *session) checkpointLocked() sessionCheckpoint {
+	game := sess.game
+	cp := sessionCheckpoint{
+		game:         game.Snapshot(true),
+		dict:         game.dict,
+		deadline:     game.deadline,
+		turns:        len(game.Turns),
+		lastDecision: game.LastDecision,
+		revealed:     game.revealed,
+		replaySteps:  len(game.replaySteps),
+		coop:         sess.coop.clone(),
+		expiresAt:    sess.expiresAt,
+		nextWarning:  sess.nextWarning,
+		warningsSent: sess.warningsSent,
+		ended:        sess.ended,
+	}
+	if game.analysis != nil {
+		cp.analysis = len(game.analysis.turns)
+	}
+	return cp
+}
+
+// Method to bring the session back to its checkpoint, e.g. after a guess
+// which could not be saved. Must be called with the session lock held.
+func (sess *session) rollbackLocked(cp sessionCheckpoint) {
+	game := sess.game
+	wasRunning := game.State == Running
+	if err := game.restore(cp.game); err != nil {
+		defaultLogger.Errorf("Unable to restore game %s, error %v", sess.id, err)
+		return
+	}
+	game.dict = cp.dict
+	game.deadline = cp.deadline
+	game.Turns = game.Turns[:cp.turns]
+	game.LastDecision = cp.lastDecision
+	game.revealed = cp.revealed
+	game.replaySteps = game.replaySteps[:cp.replaySteps]
+	if game.analysis != nil {
+		game.analysis.pending = nil
+		game.analysis.turns = game.analysis.turns[:cp.analysis]
+	}
+	// The game ended by the change runs again.
+	if game.State == Running && !wasRunning {
+		gameMetrics.sessionStarted()
+	}
+	sess.coop = cp.coop
+	sess.ended = cp.ended
+	sess.preview = nil
+	if sess.lifetime > 0 && game.State == Running {
+		if sess.expiryTimer != nil {
+			sess.expiryTimer.Stop()
+		}
+		sess.expiresAt = cp.expiresAt
+		sess.nextWarning = cp.nextWarning
+		sess.warningsSent = cp.warningsSent
+		sess.armExpiryLocked(time.Now())
+	}
+}
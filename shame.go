@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	hallOfShameFile = flag.String("hall_of_shame_file", "",
+		"Absolute path of the file where the server records its finished games, "+
+			"to find the words no player ever solved. No hall of shame is kept, "+
+			"and no challenge game can be played, if empty.")
+	hallOfShameSize = flag.Int("hall_of_shame_size", 10,
+		"Number of classes of words shown by the \"shame\" subcommand.")
+)
+
+const (
+	// Max number of classes returned by the /hall_of_shame endpoint.
+	maxHallOfShameSize = 100
+)
+
+// Hall of shame kept in a local file, with one JSON entry per line like the
+// leaderboard. Every finished game of the server is recorded, named player or
+// not.
+type shameStore struct {
+	// Serializes the writes of this process.
+	mu   sync.Mutex
+	path string
+}
+
+func newShameStore(path string) *shameStore {
+	return &shameStore{path: path}
+}
+
+// Method to record a finished game.
+func (h *shameStore) add(entry api.ShameEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Method to read all the recorded games. A missing file is an empty hall of
+// shame. Lines which can not be parsed are skipped.
+func (h *shameStore) entries() ([]api.ShameEntry, error) {
+	f, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []api.ShameEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry api.ShameEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			defaultLogger.Errorf("Skipping invalid hall of shame entry %q, error %v",
+				scanner.Text(), err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Method to get the classes of words never solved, keeping the most played
+// limit ones. All of them are returned if limit is negative.
+func (h *shameStore) unsolved(limit int) (api.HallOfShame, error) {
+	entries, err := h.entries()
+	if err != nil {
+		return api.HallOfShame{}, err
+	}
+	return unsolvedClasses(entries, limit), nil
+}
+
+// Method to group the given games by class of words, keeping the classes
+// without any won game. Classes are ranked by games played, then by the time
+// of the last game (most recent first), then by pattern.
+func unsolvedClasses(entries []api.ShameEntry, limit int) api.HallOfShame {
+	byPattern := make(map[string]*api.ShamedClass)
+	solved := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Won {
+			solved[entry.Pattern] = true
+			continue
+		}
+		class, ok := byPattern[entry.Pattern]
+		if !ok {
+			class = &api.ShamedClass{Pattern: entry.Pattern, WordLength: entry.WordLength}
+			byPattern[entry.Pattern] = class
+		}
+		class.Games++
+		if entry.Finished.After(class.LastLost) {
+			class.LastLost = entry.Finished
+		}
+	}
+	hall := api.HallOfShame{Classes: []api.ShamedClass{}}
+	for pattern, class := range byPattern {
+		if !solved[pattern] {
+			hall.Classes = append(hall.Classes, *class)
+		}
+	}
+	sort.Slice(hall.Classes, func(i, j int) bool {
+		a, b := hall.Classes[i], hall.Classes[j]
+		switch {
+		case a.Games != b.Games:
+			return a.Games > b.Games
+		case !a.LastLost.Equal(b.LastLost):
+			return a.LastLost.After(b.LastLost)
+		}
+		return a.Pattern < b.Pattern
+	})
+	if limit >= 0 && len(hall.Classes) > limit {
+		hall.Classes = hall.Classes[:limit]
+	}
+	return hall
+}
+
+// Method to build the hall of shame entry of a finished game.
+func shameEntry(game *Game, now time.Time) api.ShameEntry {
+	return api.ShameEntry{
+		Pattern:    game.replayStart,
+		WordLength: game.ExpectedLength,
+		Won:        game.State == Won,
+		Finished:   now,
+	}
+}
+
+// Method to pick the word length of a challenge game, see challengeLength.
+func (s *gameServer) challengeLength(wordLength int) (int, *api.Error) {
+	if s.shame == nil {
+		return 0, api.NewError(api.CodeChallengeUnavailable,
+			"the server keeps no hall of shame")
+	}
+	hall, err := s.shame.unsolved(-1)
+	if err != nil {
+		defaultLogger.Errorf("Unable to read the hall of shame, error %v", err)
+		return 0, api.NewError(api.CodeInternal, "unable to read the hall of shame")
+	}
+	return challengeLength(hall, wordLength)
+}
+
+// Method to pick the word length of a challenge game among the classes of the
+// hall of shame: the requested one if it was never solved, or the length of
+// the most played class if none is requested.
+func challengeLength(hall api.HallOfShame, wordLength int) (int, *api.Error) {
+	if len(hall.Classes) == 0 {
+		return 0, api.NewError(api.CodeChallengeUnavailable,
+			"every word played on the server was solved")
+	}
+	if wordLength == 0 {
+		return hall.Classes[0].WordLength, nil
+	}
+	for _, class := range hall.Classes {
+		if class.WordLength == wordLength {
+			return wordLength, nil
+		}
+	}
+	return 0, api.NewError(api.CodeChallengeUnavailable,
+		"every word of length %d played on the server was solved", wordLength).
+		WithDetail("word_length", wordLength)
+}
+
+// Method to format the hall of shame for the terminal.
+func formatHallOfShame(hall api.HallOfShame) string {
+	if len(hall.Classes) == 0 {
+		return "No word left unsolved yet.\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-4s %-24s %7s %6s %17s\n", "#", "Word", "Letters", "Games", "Last lost")
+	for i, c := range hall.Classes {
+		fmt.Fprintf(&b, "%-4d %-24s %7d %6d %17s\n", i+1, c.Pattern, c.WordLength, c.Games,
+			c.LastLost.Local().Format("2006-01-02 15:04"))
+	}
+	return b.String()
+}
+
+// Driver method for the "shame" subcommand, which shows the classes of words
+// no player of the server has solved.
+func StartHallOfShame() error {
+	if *hallOfShameFile == "" {
+		return configError("please pass the hall of shame using --hall_of_shame_file")
+	}
+	hall, err := newShameStore(*hallOfShameFile).unsolved(*hallOfShameSize)
+	if err != nil {
+		return fmt.Errorf("unable to read the hall of shame: %w", err)
+	}
+	fmt.Print(formatHallOfShame(hall))
+	if len(hall.Classes) > 0 {
+		fmt.Println("Create a game with \"challenge\": true to play them for double points.")
+	}
+	return nil
+}
+
+// Handler of GET /hall_of_shame, which returns the classes of words never
+// solved as an api.HallOfShame. The number of classes is set with ?limit=, 10
+// by default.
+func (s *gameServer) handleHallOfShame(w http.ResponseWriter, r *http.Request) {
+	limit := 10
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxHallOfShameSize {
+			writeError(w, api.NewError(api.CodeInvalidRequest,
+				"limit must be between 1 and %d", maxHallOfShameSize).WithDetail("limit", value))
+			return
+		}
+	}
+	if s.shame == nil {
+		writeJSON(w, http.StatusOK, api.HallOfShame{Classes: []api.ShamedClass{}})
+		return
+	}
+	hall, err := s.shame.unsolved(limit)
+	if err != nil {
+		defaultLogger.Errorf("Unable to read the hall of shame, error %v", err)
+		writeError(w, api.NewError(api.CodeInternal, "unable to read the hall of shame"))
+		return
+	}
+	writeJSON(w, http.StatusOK, hall)
+}
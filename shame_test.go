@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type ShameTestSuite struct {
+	suite.Suite
+	store  *shameStore
+	server *httptest.Server
+}
+
+func (s *ShameTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bird", "words"})
+	s.store = newShameStore(filepath.Join(s.T().TempDir(), "shame.jsonl"))
+	server := newGameServer()
+	server.shame = s.store
+	server.leaderboard = newLeaderboardStore(filepath.Join(s.T().TempDir(),
+		"leaderboard.jsonl"))
+	s.server = httptest.NewServer(server.Handler())
+}
+
+func (s *ShameTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ShameTestSuite) post(path string, body string, out interface{}) int {
+	resp, err := http.Post(s.server.URL+path, "application/json",
+		bytes.NewBufferString(body))
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+func (s *ShameTestSuite) get(path string, out interface{}) int {
+	resp, err := http.Get(s.server.URL + path)
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(out)
+	return resp.StatusCode
+}
+
+// Method to guess the characters in order, till the game is finished.
+func (s *ShameTestSuite) play(id string, chars string) api.Game {
+	var guess api.GuessResponse
+	for _, char := range chars {
+		status := s.post("/games/"+id+"/guesses", `{"char": "`+string(char)+`"}`, &guess)
+		assert.Equal(s.T(), http.StatusOK, status)
+		if guess.Game.State != api.StateRunning {
+			break
+		}
+	}
+	return guess.Game
+}
+
+func (s *ShameTestSuite) TestUnsolvedClasses() {
+	day := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []api.ShameEntry{
+		{Pattern: "____", WordLength: 4, Finished: day},
+		{Pattern: "_____", WordLength: 5, Finished: day},
+		{Pattern: "___ __", WordLength: 6, Finished: day.Add(time.Hour)},
+		{Pattern: "____", WordLength: 4, Won: true, Finished: day},
+		{Pattern: "_____", WordLength: 5, Finished: day.Add(time.Minute)},
+	}
+	hall := unsolvedClasses(entries, 10)
+	assert.Equal(s.T(), []api.ShamedClass{
+		{Pattern: "_____", WordLength: 5, Games: 2, LastLost: day.Add(time.Minute)},
+		{Pattern: "___ __", WordLength: 6, Games: 1, LastLost: day.Add(time.Hour)},
+	}, hall.Classes)
+	assert.Contains(s.T(), formatHallOfShame(hall), "___ __")
+	assert.Equal(s.T(), 1, len(unsolvedClasses(entries, 1).Classes))
+	assert.Equal(s.T(), "No word left unsolved yet.\n", formatHallOfShame(unsolvedClasses(nil, 10)))
+
+	length, apiErr := challengeLength(hall, 0)
+	assert.Nil(s.T(), apiErr)
+	assert.Equal(s.T(), 5, length)
+	length, apiErr = challengeLength(hall, 6)
+	assert.Nil(s.T(), apiErr)
+	assert.Equal(s.T(), 6, length)
+	_, apiErr = challengeLength(hall, 4)
+	assert.Equal(s.T(), api.CodeChallengeUnavailable, apiErr.Code)
+}
+
+func (s *ShameTestSuite) TestChallenge() {
+	var errResp api.ErrorResponse
+	status := s.post("/games", `{"retries": 3, "challenge": true}`, &errResp)
+	assert.Equal(s.T(), http.StatusConflict, status)
+	assert.Equal(s.T(), api.CodeChallengeUnavailable, errResp.Error.Code)
+
+	// A lost game of 4 letters and a won game of 5 letters.
+	var game api.Game
+	s.post("/games", `{"word_length": 4, "retries": 0, "player": "alice"}`, &game)
+	assert.Equal(s.T(), api.StateLost, s.play(game.ID, "z").State)
+	s.post("/games", `{"word_length": 5, "retries": 0, "player": "alice"}`, &game)
+	assert.Equal(s.T(), api.StateWon, s.play(game.ID, "words").State)
+
+	var hall api.HallOfShame
+	assert.Equal(s.T(), http.StatusOK, s.get("/hall_of_shame?limit=5", &hall))
+	assert.Equal(s.T(), 1, len(hall.Classes))
+	assert.Equal(s.T(), "____", hall.Classes[0].Pattern)
+	assert.Equal(s.T(), 1, hall.Classes[0].Games)
+	assert.Equal(s.T(), http.StatusBadRequest, s.get("/hall_of_shame?limit=0", &hall))
+
+	// Only the words never solved can be challenged.
+	status = s.post("/games", `{"word_length": 5, "retries": 3, "challenge": true}`,
+		&errResp)
+	assert.Equal(s.T(), http.StatusConflict, status)
+	status = s.post("/games", `{"retries": 3, "challenge": true, "player": "bob"}`, &game)
+	assert.Equal(s.T(), http.StatusCreated, status)
+	assert.Equal(s.T(), 4, game.WordLength)
+	assert.Equal(s.T(), true, game.Challenge)
+	assert.Equal(s.T(), api.StateWon, s.play(game.ID, "astlf").State)
+
+	// Solving the challenge removes the class from the hall of shame, and
+	// counts double.
+	assert.Equal(s.T(), http.StatusOK, s.get("/hall_of_shame", &hall))
+	assert.Equal(s.T(), 0, len(hall.Classes))
+	var board api.Leaderboard
+	s.get("/leaderboard", &board)
+	assert.Equal(s.T(), "bob", board.Players[0].Player)
+	assert.Equal(s.T(), 2, board.Players[0].Points)
+	assert.Equal(s.T(), 1, board.Players[1].Points)
+}
+
+func (s *ShameTestSuite) TestNoHallOfShame() {
+	server := httptest.NewServer(newGameServer().Handler())
+	defer server.Close()
+	resp, err := http.Post(server.URL+"/games", "application/json",
+		bytes.NewBufferString(`{"word_length": 4, "retries": 3, "challenge": true}`))
+	assert.Nil(s.T(), err)
+	resp.Body.Close()
+	assert.Equal(s.T(), http.StatusConflict, resp.StatusCode)
+}
+
+func TestShameTestSuite(t *testing.T) {
+	suite.Run(t, new(ShameTestSuite))
+}
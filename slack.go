@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	slackSigningSecret = flag.String("slack_signing_secret", "",
+		"Signing secret of the Slack app, which enables the /hangman slash "+
+			"command on POST /slack/commands. Requests not signed with it are "+
+			"refused.")
+	slackRetries = flag.Int("slack_retries", 6,
+		"Number of retries of the games started from Slack without giving them.")
+)
+
+const (
+	// Max age of a signed Slack request, older ones may be replayed.
+	slackMaxRequestAge = 5 * time.Minute
+	// Usage of the slash command.
+	slackUsage = "*Usage:*\n" +
+		"`/hangman start [length] [retries]` start a game in this channel, of a " +
+		"random length if none is given\n" +
+		"`/hangman guess <letter>` guess a letter of the word\n" +
+		"`/hangman state` show the game of this channel"
+)
+
+// Stages of the gallows, from no incorrect guess to a lost game.
+var gallowsStages = []string{
+	"  +---+\n  |   |\n      |\n      |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n      |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n  |   |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n /|   |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n /|\\  |\n      |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n /|\\  |\n /    |\n      |\n=========",
+	"  +---+\n  |   |\n  O   |\n /|\\  |\n / \\  |\n      |\n=========",
+}
+
+// Response to a slash command, see
+// https://api.slack.com/interactivity/slash-commands.
+type slackResponse struct {
+	// "in_channel" to show the response to the whole channel, "ephemeral" to
+	// only show it to the user who typed the command.
+	ResponseType string `json:"response_type"`
+	// Fallback of the blocks, for the notifications.
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks,omitempty"`
+}
+
+// Block of a Block Kit message. Only section and context blocks are used.
+type slackBlock struct {
+	Type     string       `json:"type"`
+	Text     *slackText   `json:"text,omitempty"`
+	Elements []*slackText `json:"elements,omitempty"`
+}
+
+// Text object of a Block Kit message.
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Games played from Slack, one per channel. A channel keeps its game once it
+// is finished, till a new one is started. The channels are only known to the
+// server which started their games.
+type slackGames struct {
+	// Serializes the starts of the games, so that a channel never gets two.
+	startMu sync.Mutex
+	// Guards the maps.
+	mu sync.Mutex
+	// Id of the session of the game of every channel, by team and channel id.
+	channels map[string]string
+	// Word revealed when the game of a channel was lost, so that it does not
+	// change every time the game is shown.
+	revealed map[string]string
+}
+
+func newSlackGames() *slackGames {
+	return &slackGames{channels: make(map[string]string), revealed: make(map[string]string)}
+}
+
+// Method to get the id of the game of a channel, empty if it has none.
+func (g *slackGames) get(channel string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.channels[channel]
+}
+
+// Method to set the game of a channel.
+func (g *slackGames) set(channel, id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.channels[channel] = id
+	delete(g.revealed, channel)
+}
+
+// Method to get the word revealed for the lost game of a channel.
+func (g *slackGames) reveal(channel string, game *Game) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	word, ok := g.revealed[channel]
+	if !ok {
+		word = game.RevealWord()
+		g.revealed[channel] = word
+	}
+	return word
+}
+
+// Method to check the signature of a Slack request, see
+// https://api.slack.com/authentication/verifying-requests-from-slack.
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) bool {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > slackMaxRequestAge || age < -slackMaxRequestAge {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature")))
+}
+
+// Handler of POST /slack/commands, which plays the /hangman slash command of
+// a Slack app. Every channel plays its own game, and anyone in the channel
+// can guess. The games are regular games of the server, so they can also be
+// followed over WebSocket, and the player who started one is recorded in the
+// leaderboard once it is finished.
+func (s *gameServer) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	if err != nil {
+		writeError(w, api.NewError(api.CodeInvalidRequest, "invalid request body: %v", err))
+		return
+	}
+	if !verifySlackSignature(s.slackSecret, r.Header, body, time.Now()) {
+		writeError(w, api.NewError(api.CodeForbidden, "invalid Slack signature"))
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeError(w, api.NewError(api.CodeInvalidRequest, "invalid request body: %v", err))
+		return
+	}
+	// Slack expects a response to every command, the errors are shown to the
+	// user who typed it.
+	writeJSON(w, http.StatusOK, s.runSlackCommand(r.Context(), form))
+}
+
+// Method to run a slash command and build its response.
+func (s *gameServer) runSlackCommand(ctx context.Context, form url.Values) slackResponse {
+	channel := form.Get("team_id") + "/" + form.Get("channel_id")
+	user := form.Get("user_name")
+	args := strings.Fields(form.Get("text"))
+	if len(args) == 0 {
+		args = []string{"state"}
+	}
+	switch strings.ToLower(args[0]) {
+	case "start":
+		return s.slackStart(ctx, channel, user, args[1:])
+	case "guess":
+		if len(args) != 2 {
+			return slackEphemeral("Guess a single letter, e.g. `/hangman guess e`")
+		}
+		return s.slackGuess(ctx, channel, user, args[1])
+	case "state":
+		sess := s.slackSession(ctx, channel)
+		if sess == nil {
+			return slackEphemeral("No game in this channel, start one with `/hangman start`")
+		}
+		return s.slackGameResponse(channel, sess, "")
+	}
+	return slackEphemeral(slackUsage)
+}
+
+// Method to start a new game in a channel, unless one is running.
+func (s *gameServer) slackStart(ctx context.Context, channel, user string,
+	args []string) slackResponse {
+	s.slackGames.startMu.Lock()
+	defer s.slackGames.startMu.Unlock()
+	if sess := s.slackSession(ctx, channel); sess != nil && sess.view().State == api.StateRunning {
+		return slackEphemeral("A game is already running in this channel, finish it first")
+	}
+	numbers := make([]int, len(args))
+	for i, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil || i > 1 {
+			return slackEphemeral(slackUsage)
+		}
+		numbers[i] = n
+	}
+	req := api.CreateGameRequest{Retries: *slackRetries, Player: user}
+	if len(numbers) > 0 {
+		req.WordLength = numbers[0]
+	}
+	if len(numbers) > 1 {
+		req.Retries = numbers[1]
+	}
+	var game *Game
+	var err error
+	if req.WordLength == 0 {
+		game, err = NewGameRandomLength(req.Retries)
+	} else {
+		game, err = NewGame(req.WordLength, req.Retries)
+	}
+	if err != nil {
+		return slackEphemeral(inputErrorToAPI(err, req.WordLength, req.Retries).Message)
+	}
+	sess, apiErr := s.addSession(ctx, game, req, 0, nil)
+	if apiErr != nil {
+		return slackEphemeral(apiErr.Message)
+	}
+	s.slackGames.set(channel, sess.id)
+	return s.slackGameResponse(channel, sess, fmt.Sprintf("@%s started a game of %d letters",
+		user, game.ExpectedLength))
+}
+
+// Method to guess a letter in the game of a channel.
+func (s *gameServer) slackGuess(ctx context.Context, channel, user, char string) slackResponse {
+	sess := s.slackSession(ctx, channel)
+	if sess == nil {
+		return slackEphemeral("No game in this channel, start one with `/hangman start`")
+	}
+	if sess.view().State != api.StateRunning {
+		return slackEphemeral("The game is over, start a new one with `/hangman start`")
+	}
+	accepted, _, apiErr := sess.guess(ctx, char, "")
+	if apiErr != nil {
+		return slackEphemeral(apiErr.Message)
+	}
+	verdict := "is not in the word"
+	if accepted {
+		verdict = "is in the word"
+	}
+	return s.slackGameResponse(channel, sess, fmt.Sprintf("@%s guessed %s, which %s", user,
+		strings.ToLower(char), verdict))
+}
+
+// Method to get the session of the game of a channel, nil if it has none or if
+// the game was evicted.
+func (s *gameServer) slackSession(ctx context.Context, channel string) *session {
+	id := s.slackGames.get(channel)
+	if id == "" {
+		return nil
+	}
+	sess, apiErr := s.getSession(ctx, id)
+	if apiErr != nil {
+		return nil
+	}
+	return sess
+}
+
+// Method to build an error response, only shown to the user who typed the
+// command.
+func slackEphemeral(text string) slackResponse {
+	return slackResponse{
+		ResponseType: "ephemeral",
+		Text:         text,
+		Blocks:       []slackBlock{slackSection(text)},
+	}
+}
+
+// Method to build a section block of Markdown text.
+func slackSection(text string) slackBlock {
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}
+}
+
+// Method to build the response showing the state of the game of a channel to
+// the channel, with the gallows, the word shown and the letters used. event
+// describes what just happened, if anything.
+func (s *gameServer) slackGameResponse(channel string, sess *session, event string) slackResponse {
+	view := sess.view()
+	var status string
+	switch view.State {
+	case api.StateWon:
+		status = "The word was guessed, well done!"
+	case api.StateLost:
+		status = fmt.Sprintf("The man is hanged! The word was *%s*", s.slackGames.reveal(channel, sess.game))
+	case api.StateForfeited:
+		status = fmt.Sprintf("Nobody guessed in time, the game is forfeited! The word was *%s*",
+			s.slackGames.reveal(channel, sess.game))
+	default:
+		status = fmt.Sprintf("%d incorrect guesses left", view.RetriesLeft)
+	}
+	word := strings.Join(strings.Split(view.MaskedWord, ""), " ")
+	used := view.UsedChars
+	if used == "" {
+		used = "none"
+	}
+	var blocks []slackBlock
+	if event != "" {
+		blocks = append(blocks, slackSection(event))
+	}
+	blocks = append(blocks,
+		slackSection("```\n"+gallows(view)+"\n```"),
+		slackSection(fmt.Sprintf("`%s`\n%s", word, status)),
+		slackBlock{Type: "context", Elements: []*slackText{
+			{Type: "mrkdwn", Text: "Letters used: " + used},
+		}})
+	text := status
+	if event != "" {
+		text = event + ". " + status
+	}
+	return slackResponse{ResponseType: "in_channel", Text: text, Blocks: blocks}
+}
+
+// Method to draw the gallows of a game, with as many parts of the man as the
+// share of the retries used. A lost game shows the whole man.
+func gallows(view api.Game) string {
+	last := len(gallowsStages) - 1
+	if view.State == api.StateLost || view.State == api.StateForfeited {
+		return gallowsStages[last]
+	}
+	if view.AllowedRetries <= 0 {
+		return gallowsStages[0]
+	}
+	used := view.AllowedRetries - view.RetriesLeft
+	stage := used * last / view.AllowedRetries
+	if stage >= last {
+		// The last part is only drawn once the game is lost.
+		stage = last - 1
+	}
+	return gallowsStages[stage]
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type SlackTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+func (s *SlackTestSuite) SetupTest() {
+	InitGame([]string{"cats"})
+	server := newGameServer()
+	server.slackSecret = "secret"
+	s.server = httptest.NewServer(server.Handler())
+}
+
+func (s *SlackTestSuite) TearDownTest() {
+	s.server.Close()
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to sign a request body like Slack does.
+func slackSignature(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Method to send a slash command typed in a channel, signed with the secret.
+func (s *SlackTestSuite) command(secret, channel, text string) (int, slackResponse) {
+	body := url.Values{"team_id": {"T1"}, "channel_id": {channel}, "user_name": {"alice"},
+		"command": {"/hangman"}, "text": {text}}.Encode()
+	req, err := http.NewRequest(http.MethodPost, s.server.URL+"/slack/commands",
+		bytes.NewBufferString(body))
+	assert.Nil(s.T(), err)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", slackSignature(secret, timestamp, body))
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(s.T(), err)
+	defer resp.Body.Close()
+	var out slackResponse
+	json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func (s *SlackTestSuite) TestPlay() {
+	status, resp := s.command("secret", "C1", "start 4 3")
+	assert.Equal(s.T(), http.StatusOK, status)
+	assert.Equal(s.T(), "in_channel", resp.ResponseType)
+	assert.Contains(s.T(), resp.Text, "@alice started a game of 4 letters")
+	assert.Equal(s.T(), "```\n"+gallowsStages[0]+"\n```", resp.Blocks[1].Text.Text)
+	assert.Equal(s.T(), "`_ _ _ _`\n3 incorrect guesses left", resp.Blocks[2].Text.Text)
+
+	// A channel plays a single game at a time.
+	_, resp = s.command("secret", "C1", "start")
+	assert.Equal(s.T(), "ephemeral", resp.ResponseType)
+
+	_, resp = s.command("secret", "C1", "guess z")
+	assert.Contains(s.T(), resp.Text, "@alice guessed z, which is not in the word")
+	assert.Equal(s.T(), "```\n"+gallowsStages[2]+"\n```", resp.Blocks[1].Text.Text)
+	assert.Equal(s.T(), "Letters used: z", resp.Blocks[3].Elements[0].Text)
+
+	_, resp = s.command("secret", "C1", "guess z")
+	assert.Equal(s.T(), "ephemeral", resp.ResponseType)
+
+	// Other channels have their own games.
+	_, resp = s.command("secret", "C2", "state")
+	assert.Equal(s.T(), "ephemeral", resp.ResponseType)
+
+	for _, char := range []string{"c", "a", "t"} {
+		s.command("secret", "C1", "guess "+char)
+	}
+	_, resp = s.command("secret", "C1", "guess s")
+	assert.Equal(s.T(), "in_channel", resp.ResponseType)
+	assert.Contains(s.T(), resp.Text, "The word was guessed")
+	assert.Contains(s.T(), resp.Blocks[2].Text.Text, "`c a t s`")
+
+	// A new game can be started once the game is over, of a random length.
+	_, resp = s.command("secret", "C1", "start")
+	assert.Equal(s.T(), "in_channel", resp.ResponseType)
+	assert.Contains(s.T(), resp.Text, "started a game of 4 letters")
+}
+
+func (s *SlackTestSuite) TestLost() {
+	s.command("secret", "C1", "start 4 1")
+	_, resp := s.command("secret", "C1", "guess z")
+	assert.Contains(s.T(), resp.Text, "The man is hanged! The word was *cats*")
+	assert.Equal(s.T(), "```\n"+gallowsStages[len(gallowsStages)-1]+"\n```",
+		resp.Blocks[1].Text.Text)
+	_, resp = s.command("secret", "C1", "guess a")
+	assert.Equal(s.T(), "ephemeral", resp.ResponseType)
+}
+
+func (s *SlackTestSuite) TestInvalidCommands() {
+	_, resp := s.command("secret", "C1", "start 5")
+	assert.Equal(s.T(), "ephemeral", resp.ResponseType)
+	_, resp = s.command("secret", "C1", "start four")
+	assert.Contains(s.T(), resp.Text, "Usage")
+	_, resp = s.command("secret", "C1", "help")
+	assert.Contains(s.T(), resp.Text, "Usage")
+	_, resp = s.command("secret", "C1", "guess")
+	assert.Equal(s.T(), "ephemeral", resp.ResponseType)
+}
+
+func (s *SlackTestSuite) TestSignature() {
+	status, _ := s.command("wrong", "C1", "start")
+	assert.Equal(s.T(), http.StatusForbidden, status)
+
+	header := http.Header{}
+	old := time.Now().Add(-10 * time.Minute)
+	timestamp := strconv.FormatInt(old.Unix(), 10)
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", slackSignature("secret", timestamp, "text=start"))
+	assert.True(s.T(), verifySlackSignature("secret", header, []byte("text=start"), old))
+	assert.False(s.T(), verifySlackSignature("secret", header, []byte("text=start"), time.Now()))
+}
+
+func (s *SlackTestSuite) TestDisabled() {
+	server := httptest.NewServer(newGameServer().Handler())
+	defer server.Close()
+	resp, err := http.Post(server.URL+"/slack/commands", "application/x-www-form-urlencoded",
+		bytes.NewBufferString("text=start"))
+	assert.Nil(s.T(), err)
+	resp.Body.Close()
+	assert.NotEqual(s.T(), http.StatusOK, resp.StatusCode)
+}
+
+func TestSlackTestSuite(t *testing.T) {
+	suite.Run(t, new(SlackTestSuite))
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hackeracc/WordGuess/api"
+	"sort"
+	"unicode/utf8"
+)
+
+// State of a game at one point, which can be encoded as JSON. It is what a
+// client needs to render the game, so it can be returned directly by servers,
+// and a game can be restored from it.
+type Snapshot struct {
+	WordLength int `json:"word_length"`
+	// Word shown to the player, with "_" for the characters not guessed yet.
+	Word string `json:"word"`
+	// Characters guessed so far, in order.
+	UsedChars string `json:"used_chars"`
+	// Retries allowed when the game started, and left now. The retries left
+	// can be negative, see Game.CurrentRetries.
+	AllowedRetries int             `json:"allowed_retries"`
+	RetriesLeft    int             `json:"retries_left"`
+	RetryPolicy    api.RetryPolicy `json:"retry_policy"`
+	State          api.GameState   `json:"state"`
+	// True if the game was lost by forfeit, see Forfeited. Snapshots taken
+	// before the state existed have the state "lost" and this field set.
+	Forfeited bool `json:"forfeited,omitempty"`
+	// Words the game is still choosing from, in sorted order. Nil if redacted,
+	// so the snapshot can be shown to the player.
+	Candidates []string `json:"candidates,omitempty"`
+}
+
+// Method to get the snapshot of the game. The candidates are only included if
+// withCandidates is true.
+func (g *Game) Snapshot(withCandidates bool) Snapshot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s := Snapshot{
+		WordLength:     g.ExpectedLength,
+		Word:           DefaultPatternFormat.Format(g.CurrentDisplayedWord),
+		UsedChars:      string(g.UsedChars),
+		AllowedRetries: g.AllowedRetries,
+		RetriesLeft:    g.CurrentRetries,
+		RetryPolicy:    g.RetryPolicy.apiPolicy(),
+		State:          apiGameState(g.State),
+		Forfeited:      g.State == Forfeited,
+	}
+	if withCandidates {
+		s.Candidates = append([]string{}, g.candidatesLocked()...)
+	}
+	return s
+}
+
+// Method to encode the game as JSON, see Snapshot. The candidates are redacted
+// so that the JSON can be sent to the player; use Snapshot(true) to keep them.
+func (g *Game) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.Snapshot(false))
+}
+
+// Method to get the pattern of the engine for a word shown with the default
+// format, and the used characters which are not in the word.
+// A "_" in the word is a character not guessed yet, unless "_" was guessed,
+// which can only happen if it is a letter of the alphabet.
+func parseMaskedWord(masked string, usedChars []rune) ([]rune, []rune) {
+	word := []rune(masked)
+	for i, char := range word {
+		if char == DefaultPatternFormat.Blank && !contains(usedChars, char) {
+			word[i] = emptyChar
+		}
+	}
+	var excluded []rune
+	for _, char := range usedChars {
+		if !contains(word, char) {
+			excluded = append(excluded, char)
+		}
+	}
+	return word, excluded
+}
+
+// Method to restore a game from its JSON snapshot, using the current
+// dictionary. If the candidates were redacted, they are found again in the
+// dictionary: all the words matching the word shown and none of the wrong
+// guesses.
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if err := g.restore(s); err != nil {
+		return err
+	}
+	if s.State == api.StateRunning {
+		gameMetrics.sessionStarted()
+	}
+	return nil
+}
+
+// Method to restore a game from a snapshot, see UnmarshalJSON. The game does
+// not count as a new session in the metrics.
+func (g *Game) restore(s Snapshot) error {
+	policy, err := ParseRetryPolicy(string(s.RetryPolicy))
+	if err != nil {
+		return err
+	}
+	var state GameState
+	switch s.State {
+	case api.StateRunning:
+		state = Running
+	case api.StateWon:
+		state = Won
+	case api.StateLost:
+		state = Lost
+		if s.Forfeited {
+			state = Forfeited
+		}
+	case api.StateForfeited:
+		state = Forfeited
+	default:
+		return fmt.Errorf("invalid game state %q", s.State)
+	}
+	if utf8.RuneCountInString(s.Word) != s.WordLength {
+		return fmt.Errorf("word %q does not have %d characters", s.Word, s.WordLength)
+	}
+	usedChars := []rune(s.UsedChars)
+	word, excluded := parseMaskedWord(s.Word, usedChars)
+	dict := currentDictionary()
+	candidates := s.Candidates
+	if candidates == nil {
+		candidates = dict.matchPattern(word, excluded)
+	}
+	for _, candidate := range candidates {
+		if utf8.RuneCountInString(candidate) != s.WordLength {
+			return fmt.Errorf("candidate %q does not have %d characters",
+				candidate, s.WordLength)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no word of the dictionary matches %q", s.Word)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ExpectedLength = s.WordLength
+	g.AllowedRetries = s.AllowedRetries
+	g.CurrentRetries = s.RetriesLeft
+	g.RetryPolicy = policy
+	g.UsedChars = usedChars
+	g.CurrentDisplayedWord = word
+	g.State = state
+	g.dict = dict
+	g.CurrentSetOfWords, g.packed, g.bits = nil, nil, nil
+	if _, ok := dict.packed[s.WordLength]; ok {
+		sorted := append([]string{}, candidates...)
+		sort.Strings(sorted)
+		g.packed, _ = packWords(sorted)
+	}
+	if g.packed == nil {
+		g.CurrentSetOfWords = candidates
+	}
+	if g.Logger == nil {
+		g.Logger = defaultLogger
+	}
+	if g.clock == nil {
+		g.clock = realClock{}
+	}
+	g.resetDeadline()
+	return nil
+}
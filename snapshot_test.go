@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type SnapshotTestSuite struct {
+	suite.Suite
+}
+
+func (s *SnapshotTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats"})
+}
+
+// Method to play the guesses on a new game of 4 letters with 3 retries.
+func (s *SnapshotTestSuite) play(guesses string) *Game {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	for _, char := range guesses {
+		_, err := game.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+	}
+	return game
+}
+
+func (s *SnapshotTestSuite) TestSnapshot() {
+	game := s.play("ea")
+	assert.Equal(s.T(), Snapshot{
+		WordLength:     4,
+		Word:           "_a__",
+		UsedChars:      "ea",
+		AllowedRetries: 3,
+		RetriesLeft:    2,
+		RetryPolicy:    api.RetryStrict,
+		State:          api.StateRunning,
+		Candidates:     []string{"cats", "fast", "last"},
+	}, game.Snapshot(true))
+	assert.Nil(s.T(), game.Snapshot(false).Candidates)
+}
+
+func (s *SnapshotTestSuite) TestMarshalRedactsCandidates() {
+	data, err := json.Marshal(s.play("ea"))
+	assert.Nil(s.T(), err)
+	assert.JSONEq(s.T(), `{"word_length": 4, "word": "_a__", "used_chars": "ea",
+		"allowed_retries": 3, "retries_left": 2, "retry_policy": "strict",
+		"state": "running"}`, string(data))
+}
+
+func (s *SnapshotTestSuite) TestRoundTrip() {
+	game := s.play("ea")
+	data, err := json.Marshal(game)
+	assert.Nil(s.T(), err)
+	var restored Game
+	assert.Nil(s.T(), json.Unmarshal(data, &restored))
+	// The redacted candidates are found again in the dictionary.
+	assert.Equal(s.T(), game.Snapshot(true), restored.Snapshot(true))
+
+	// The restored game plays on like the original one.
+	accepted, err := game.CheckUserInput('s')
+	assert.Nil(s.T(), err)
+	restoredAccepted, err := restored.CheckUserInput('s')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), accepted, restoredAccepted)
+	assert.Equal(s.T(), game.Snapshot(true), restored.Snapshot(true))
+}
+
+func (s *SnapshotTestSuite) TestRestoreCandidates() {
+	data, err := json.Marshal(s.play("ea").Snapshot(true))
+	assert.Nil(s.T(), err)
+	// The candidates of the snapshot are kept even if the dictionary changed.
+	InitGame([]string{"bets"})
+	var restored Game
+	assert.Nil(s.T(), json.Unmarshal(data, &restored))
+	assert.Equal(s.T(), []string{"cats", "fast", "last"}, restored.Candidates())
+}
+
+func (s *SnapshotTestSuite) TestForfeited() {
+	game := s.play("ea")
+	game.forfeit()
+	snapshot := game.Snapshot(false)
+	assert.Equal(s.T(), api.StateForfeited, snapshot.State)
+	assert.True(s.T(), snapshot.Forfeited)
+	data, err := json.Marshal(snapshot)
+	assert.Nil(s.T(), err)
+	var restored Game
+	assert.Nil(s.T(), json.Unmarshal(data, &restored))
+	assert.Equal(s.T(), Forfeited, restored.State)
+
+	// The snapshots taken before the state existed are forfeited too.
+	assert.Nil(s.T(), json.Unmarshal([]byte(`{"word_length": 4, "word": "____",
+		"retry_policy": "strict", "state": "lost", "forfeited": true}`), &restored))
+	assert.Equal(s.T(), Forfeited, restored.State)
+}
+
+func (s *SnapshotTestSuite) TestInvalidSnapshot() {
+	var game Game
+	assert.NotNil(s.T(), json.Unmarshal([]byte(`{"word_length": 4, "word": "_a_",
+		"state": "running"}`), &game))
+	assert.NotNil(s.T(), json.Unmarshal([]byte(`{"word_length": 4, "word": "____",
+		"state": "paused"}`), &game))
+	assert.NotNil(s.T(), json.Unmarshal([]byte(`{"word_length": 4, "word": "x___",
+		"used_chars": "x", "state": "running"}`), &game))
+}
+
+func TestSnapshotTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotTestSuite))
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Driver method for the "solve" subcommand, where the computer guesses the word
+// the user thinks of.
+func StartSolver() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	fmt.Println("Think of a word and I will guess it!")
+	var solver *Solver
+	for solver == nil {
+		fmt.Println("Enter the length of your word: ")
+		length, err := readInt()
+		if isInputEnd(err) {
+			return err
+		}
+		if err != nil {
+			fmt.Println("Invalid input given, error: ", err)
+			continue
+		}
+		solver, err = NewSolver(length)
+		if err != nil {
+			fmt.Println("Sorry we do not have any words of length ", length,
+				" in the dictionary. Please try again!")
+		}
+	}
+	for {
+		if word, ok := solver.Word(); ok {
+			fmt.Println("Your word is", word, "! I got it with",
+				solver.WrongGuesses, "wrong guesses.")
+			return nil
+		}
+		char, ok := solver.NextGuess()
+		if !ok {
+			fmt.Println("I do not know any word matching", displayFormat.Format(solver.Pattern),
+				"without the letters I got wrong. You win!")
+			return nil
+		}
+		fmt.Println(displayFormat.Format(solver.Pattern))
+		fmt.Printf("Does your word have the letter '%s'? Enter its positions "+
+			"(starting from 1, separated by spaces), or just press enter if not: \n",
+			string(char))
+		line, err := readLine()
+		if err != nil {
+			return err
+		}
+		positions, err := parsePositions(line)
+		if err != nil {
+			fmt.Println("Invalid positions given, error: ", err)
+			continue
+		}
+		if err := solver.Feedback(char, positions); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// Method to parse a line of 1 based positions into 0 based positions.
+func parsePositions(line string) ([]int, error) {
+	var positions []int
+	for _, field := range strings.FieldsFunc(line, func(r rune) bool {
+		return r == ' ' || r == ','
+	}) {
+		pos, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, pos-1)
+	}
+	return positions, nil
+}
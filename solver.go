@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Solver for the inverse game, where the user thinks of a word and the computer
+// guesses it. The solver keeps the list of dictionary words which are still
+// consistent with the user's answers, and guesses the letter which tells it the
+// most about the word.
+type Solver struct {
+	// Length of the word the user thinks of.
+	WordLength int
+	// Dictionary words which are consistent with the answers so far.
+	Candidates []string
+	// Word as known so far. Please note we use emptyChar to represent a character
+	// which is not known yet.
+	Pattern []rune
+	// Characters guessed so far.
+	UsedChars []rune
+	// Number of guessed characters which are not in the word.
+	WrongGuesses int
+}
+
+// Method to create a solver for a word of the given length.
+// It returns an error wrapping ErrInvalidLength if no dictionary word is of
+// that length.
+func NewSolver(length int) (*Solver, error) {
+	dict := currentDictionary()
+	if !validateLength(dict, length) {
+		return nil, newGameError(ErrInvalidLength,
+			"No words of length %d in the dictionary", length)
+	}
+	s := &Solver{
+		WordLength: length,
+		Candidates: dict.Words(length),
+		Pattern:    make([]rune, length),
+	}
+	for i := range s.Pattern {
+		s.Pattern[i] = emptyChar
+	}
+	return s, nil
+}
+
+// Method to pick the next letter to guess.
+// The letter is picked by its entropy: the candidates are grouped by the
+// positions at which they contain the letter (or not at all), and the letter
+// with the most even spread of groups is picked, since whatever the answer, it
+// leaves the fewest candidates on average. Ties go to the letter present in
+// more candidates, then to the smaller letter.
+// Returns false if no candidate is left to guess a letter from.
+func (s *Solver) NextGuess() (rune, bool) {
+	var best rune
+	bestEntropy := -1.0
+	// The letters are ranked by count then letter, so the first one wins the
+	// ties.
+	for _, letter := range s.BestNextGuesses(0) {
+		if entropy := s.entropy(letter.Char); entropy > bestEntropy {
+			best, bestEntropy = letter.Char, entropy
+		}
+	}
+	return best, bestEntropy >= 0
+}
+
+// Method to get the n letters in the most candidates, like
+// Game.BestNextGuesses. All the letters in at least one candidate are returned
+// if n is zero or less.
+func (s *Solver) BestNextGuesses(n int) []ScoredLetter {
+	letters := rankLetters(s.Candidates, s.UsedChars)
+	if n > 0 && len(letters) > n {
+		letters = letters[:n]
+	}
+	return letters
+}
+
+// Method to apply the user's answer for a guessed letter.
+// Params:
+// char: Guessed letter.
+// positions: Positions (starting from 0) of the letter in the word. Empty if
+//   the letter is not in the word.
+//
+// Returns an error if the answer is not valid, e.g. a position out of range
+// or already known to hold a different letter.
+func (s *Solver) Feedback(char rune, positions []int) error {
+	if contains(s.UsedChars, char) {
+		return fmt.Errorf("Character %s has already been guessed", string(char))
+	}
+	for _, pos := range positions {
+		if pos < 0 || pos >= s.WordLength {
+			return fmt.Errorf("Position %d is out of range", pos+1)
+		}
+		if s.Pattern[pos] != emptyChar {
+			return fmt.Errorf("Position %d is already known to be %s", pos+1,
+				string(s.Pattern[pos]))
+		}
+	}
+	s.UsedChars = append(s.UsedChars, char)
+	if len(positions) == 0 {
+		s.WrongGuesses++
+	}
+	for _, pos := range positions {
+		s.Pattern[pos] = char
+	}
+	var remaining []string
+	for _, word := range s.Candidates {
+		if s.consistent([]rune(word), char) {
+			remaining = append(remaining, word)
+		}
+	}
+	s.Candidates = remaining
+	return nil
+}
+
+// Method to get the word if it is known.
+// The word is known when all its letters are revealed or only one candidate is
+// left.
+func (s *Solver) Word() (string, bool) {
+	if !contains(s.Pattern, emptyChar) {
+		return string(s.Pattern), true
+	}
+	if len(s.Candidates) == 1 {
+		return s.Candidates[0], true
+	}
+	return "", false
+}
+
+// Method to check if a word is consistent with the latest answer for char,
+// i.e. it has char exactly at the positions revealed for it.
+func (s *Solver) consistent(word []rune, char rune) bool {
+	for i, wordChar := range word {
+		if (wordChar == char) != (s.Pattern[i] == char) {
+			return false
+		}
+	}
+	return true
+}
+
+// Method to compute the entropy (in bits) of the groups the candidates would be
+// split into by guessing char.
+func (s *Solver) entropy(char rune) float64 {
+	groups := make(map[string]int)
+	for _, word := range s.Candidates {
+		key := make([]rune, 0, s.WordLength)
+		for _, wordChar := range word {
+			if wordChar == char {
+				key = append(key, char)
+			} else {
+				key = append(key, emptyChar)
+			}
+		}
+		groups[string(key)]++
+	}
+	total := float64(len(s.Candidates))
+	entropy := 0.0
+	for _, size := range groups {
+		p := float64(size) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
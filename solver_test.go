@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type SolverTestSuite struct {
+	suite.Suite
+}
+
+func (s *SolverTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code", "cast", "lost"})
+}
+
+func (s *SolverTestSuite) TestInvalidLength() {
+	solver, err := NewSolver(9)
+	assert.Nil(s.T(), solver)
+	assert.ErrorIs(s.T(), err, ErrInvalidLength)
+}
+
+func (s *SolverTestSuite) TestSolve() {
+	solver, err := NewSolver(4)
+	assert.Nil(s.T(), err)
+	// Play against "lost" as the secret word.
+	secret := []rune("lost")
+	for i := 0; i < 10; i++ {
+		if _, ok := solver.Word(); ok {
+			break
+		}
+		char, ok := solver.NextGuess()
+		assert.Equal(s.T(), true, ok)
+		var positions []int
+		for pos, secretChar := range secret {
+			if secretChar == char {
+				positions = append(positions, pos)
+			}
+		}
+		assert.Nil(s.T(), solver.Feedback(char, positions))
+	}
+	word, ok := solver.Word()
+	assert.Equal(s.T(), true, ok)
+	assert.Equal(s.T(), "lost", word)
+}
+
+func (s *SolverTestSuite) TestFirstGuessSplitsEvenly() {
+	solver, _ := NewSolver(4)
+	// 's', 't' and 'e' split the words in three groups of sizes 1, 4 and 1,
+	// which is more than any other letter. 's' and 't' are both in 5 words,
+	// and 's' is the smaller letter.
+	char, ok := solver.NextGuess()
+	assert.Equal(s.T(), true, ok)
+	assert.Equal(s.T(), 's', char)
+}
+
+func (s *SolverTestSuite) TestInvalidFeedback() {
+	solver, _ := NewSolver(4)
+	assert.NotNil(s.T(), solver.Feedback('a', []int{4}))
+	assert.Nil(s.T(), solver.Feedback('a', []int{1}))
+	assert.NotNil(s.T(), solver.Feedback('a', nil))
+	assert.NotNil(s.T(), solver.Feedback('o', []int{1}))
+	assert.Equal(s.T(), []string{"cast", "fast", "last"}, solver.Candidates)
+}
+
+func (s *SolverTestSuite) TestUnknownWord() {
+	solver, _ := NewSolver(4)
+	assert.Nil(s.T(), solver.Feedback('q', []int{0}))
+	_, ok := solver.NextGuess()
+	assert.Equal(s.T(), false, ok)
+}
+
+func TestSolverTestSuite(t *testing.T) {
+	suite.Run(t, new(SolverTestSuite))
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var (
+	soundMode = flag.String("sound", "off",
+		"Audio feedback of the terminal game on the guesses and at the end of "+
+			"the game: \"off\", \"bell\" to ring the bell of the terminal on the "+
+			"wrong guesses and at the end of the game, or \"on\" to play short "+
+			"sounds with the audio player of the system, silently skipped when "+
+			"none is installed.")
+	soundDir = flag.String("sound_dir", "",
+		"Directory of the sounds played with --sound=on, as WAV files named "+
+			"correct.wav, wrong.wav, won.wav and lost.wav. The built in sounds are "+
+			"played for the missing files.")
+)
+
+// Event of a game with a sound.
+type soundEvent string
+
+const (
+	soundCorrect soundEvent = "correct"
+	soundWrong   soundEvent = "wrong"
+	soundWon     soundEvent = "won"
+	soundLost    soundEvent = "lost"
+)
+
+// Note of a built in sound.
+type tone struct {
+	// Frequency in Hz.
+	freq     float64
+	duration time.Duration
+}
+
+// Notes of the built in sounds, synthesized when they are first played.
+var builtinSounds = map[soundEvent][]tone{
+	soundCorrect: {{880, 70 * time.Millisecond}, {1320, 110 * time.Millisecond}},
+	soundWrong:   {{196, 220 * time.Millisecond}},
+	soundWon: {{523.25, 110 * time.Millisecond}, {659.25, 110 * time.Millisecond},
+		{783.99, 110 * time.Millisecond}, {1046.5, 320 * time.Millisecond}},
+	soundLost: {{392, 200 * time.Millisecond}, {311.13, 200 * time.Millisecond},
+		{261.63, 420 * time.Millisecond}},
+}
+
+// Sample rate of the built in sounds.
+const soundSampleRate = 22050
+
+// Number of sounds waiting to be played. The sounds of the events coming
+// faster than they can be started are dropped.
+const soundQueue = 4
+
+// Player of the sounds of the terminal game, set by setupSound. Nil without
+// --sound.
+var sounds *soundPlayer
+
+// Player of the sounds of the games. It observes the games it is added to,
+// see Game.AddObserver.
+//
+// Go has no audio output in its standard library, and the audio libraries
+// need cgo on most systems, so the sounds are synthesized in Go as WAV files
+// and handed to the audio player of the system instead.
+type soundPlayer struct {
+	// Only ring the bell, see --sound=bell.
+	bellOnly bool
+	// Directory of the sound files, empty to only play the built in sounds.
+	dir string
+	// Directory the built in sounds are written to, for the audio player.
+	cacheDir string
+	// Terminal the bell is rung on.
+	out io.Writer
+	// Audio player installed, see soundCommand. Empty if there is none, in
+	// which case no sound is played.
+	command string
+	// Starts a command, a variable so that tests can record the commands.
+	start func(*exec.Cmd) error
+	// Sounds waiting to be played, see run. Nil to play them right away.
+	// The observers are called with the game locked, so the audio player is
+	// not started from them.
+	events chan soundEvent
+
+	mu sync.Mutex
+	// Sound being played, stopped when the next one starts.
+	playing *exec.Cmd
+}
+
+// Method to set up the sounds of the terminal game from --sound. Returns an
+// error if --sound is not valid.
+func setupSound() error {
+	switch *soundMode {
+	case "off":
+		return nil
+	case "bell":
+		sounds = &soundPlayer{bellOnly: true, out: os.Stdout}
+		return nil
+	case "on":
+	default:
+		return configError("unknown --sound %s, expected off, bell or on", *soundMode)
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	sounds = &soundPlayer{
+		dir:      *soundDir,
+		cacheDir: filepath.Join(cacheDir, "WordGuess", "sounds"),
+		out:      os.Stdout,
+		command:  soundCommand(runtime.GOOS, exec.LookPath),
+		start:    (*exec.Cmd).Start,
+		events:   make(chan soundEvent, soundQueue),
+	}
+	if sounds.command == "" {
+		defaultLogger.Infof("No audio player found, the game is silent")
+	}
+	go sounds.run()
+	return nil
+}
+
+// Method to find the command playing a WAV file on an OS, named like
+// runtime.GOOS, among the ones installed. Returns an empty name if none is
+// installed.
+func soundCommand(goos string, lookPath func(string) (string, error)) string {
+	var names []string
+	switch goos {
+	case "darwin":
+		names = []string{"afplay"}
+	case "windows":
+		names = []string{"powershell"}
+	default:
+		names = []string{"paplay", "pw-play", "aplay"}
+	}
+	for _, name := range names {
+		if _, err := lookPath(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// Method to get the arguments of the audio player to play a file.
+func soundArgs(command, path string) []string {
+	switch command {
+	case "aplay":
+		return []string{"-q", path}
+	case "powershell":
+		return []string{"-NoProfile", "-NonInteractive", "-Command",
+			"(New-Object Media.SoundPlayer " + powerShellString(path) + ").PlaySync()"}
+	}
+	return []string{path}
+}
+
+func (p *soundPlayer) OnGuess(turn Turn) {
+	if turn.Accepted {
+		p.notify(soundCorrect)
+	} else {
+		p.notify(soundWrong)
+	}
+}
+
+func (p *soundPlayer) OnStateChange(state GameState) {
+	if state == Won {
+		p.notify(soundWon)
+	} else {
+		p.notify(soundLost)
+	}
+}
+
+func (p *soundPlayer) OnReveal(string) {}
+
+// Method to queue the sound of an event, or to play it right away if the
+// player has no queue.
+func (p *soundPlayer) notify(event soundEvent) {
+	if p.events == nil {
+		p.play(event)
+		return
+	}
+	select {
+	case p.events <- event:
+	default:
+		// The game never waits for the sounds.
+	}
+}
+
+// Method to play the queued sounds, in order, till the queue is closed.
+func (p *soundPlayer) run() {
+	for event := range p.events {
+		p.play(event)
+	}
+}
+
+// Method to play the sound of an event, without waiting for it to end. The
+// sound being played is stopped, e.g. the sound of the last guess once the
+// game is won. Nothing is played if the sound can not be played, only
+// --sound=bell rings the bell.
+func (p *soundPlayer) play(event soundEvent) {
+	if p.bellOnly {
+		p.ring(event)
+		return
+	}
+	if p.command == "" {
+		return
+	}
+	path, err := p.file(event)
+	if err != nil {
+		defaultLogger.Infof("Unable to prepare the %s sound, error %v", event, err)
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.playing != nil && p.playing.Process != nil {
+		p.playing.Process.Kill()
+	}
+	cmd := exec.Command(p.command, soundArgs(p.command, path)...)
+	if err := p.start(cmd); err != nil {
+		defaultLogger.Infof("Unable to play the %s sound with %s, error %v", event,
+			p.command, err)
+		p.playing = nil
+		return
+	}
+	p.playing = cmd
+	if cmd.Process != nil {
+		go cmd.Wait()
+	}
+}
+
+// Method to ring the bell of the terminal for an event. The correct guesses
+// are silent, a bell for every one of them would be noise.
+func (p *soundPlayer) ring(event soundEvent) {
+	if event != soundCorrect {
+		fmt.Fprint(p.out, "\a")
+	}
+}
+
+// Method to get the WAV file of the sound of an event: the one of the sound
+// directory if any, else the built in sound, written to the cache directory
+// the first time it is played.
+func (p *soundPlayer) file(event soundEvent) (string, error) {
+	name := string(event) + ".wav"
+	if p.dir != "" {
+		path := filepath.Join(p.dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	path := filepath.Join(p.cacheDir, name)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(p.cacheDir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, synthesizeWAV(builtinSounds[event]), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Method to synthesize notes as a WAV file: 16 bit mono PCM sine waves. Every
+// note fades in and out quickly, so that the notes do not click.
+func synthesizeWAV(tones []tone) []byte {
+	var samples []int16
+	const fade = soundSampleRate / 200
+	for _, t := range tones {
+		n := int(t.duration.Seconds() * soundSampleRate)
+		for i := 0; i < n; i++ {
+			gain := 0.3
+			if i < fade {
+				gain *= float64(i) / fade
+			} else if n-i < fade {
+				gain *= float64(n-i) / fade
+			}
+			value := gain * math.Sin(2*math.Pi*t.freq*float64(i)/soundSampleRate)
+			samples = append(samples, int16(value*math.MaxInt16))
+		}
+	}
+	dataSize := uint32(len(samples) * 2)
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, 36+dataSize)
+	b.WriteString("WAVEfmt ")
+	for _, field := range []any{
+		uint32(16),                  // Size of the format chunk.
+		uint16(1),                   // PCM.
+		uint16(1),                   // Mono.
+		uint32(soundSampleRate),     // Sample rate.
+		uint32(soundSampleRate * 2), // Bytes per second.
+		uint16(2),                   // Bytes per sample.
+		uint16(16),                  // Bits per sample.
+	} {
+		binary.Write(&b, binary.LittleEndian, field)
+	}
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, dataSize)
+	binary.Write(&b, binary.LittleEndian, samples)
+	return b.Bytes()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type SoundTestSuite struct {
+	suite.Suite
+}
+
+func (s *SoundTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *SoundTestSuite) TestSynthesizeWAV() {
+	wav := synthesizeWAV([]tone{{440, 100 * time.Millisecond}, {880, 50 * time.Millisecond}})
+	assert.Equal(s.T(), "RIFF", string(wav[:4]))
+	assert.Equal(s.T(), "WAVEfmt ", string(wav[8:16]))
+	assert.Equal(s.T(), "data", string(wav[36:40]))
+	samples := soundSampleRate/10 + soundSampleRate/20
+	assert.Equal(s.T(), uint32(samples*2), binary.LittleEndian.Uint32(wav[40:44]))
+	assert.Equal(s.T(), 44+samples*2, len(wav))
+	assert.Equal(s.T(), uint32(len(wav)-8), binary.LittleEndian.Uint32(wav[4:8]))
+}
+
+func (s *SoundTestSuite) TestSoundCommand() {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	assert.Equal(s.T(), "paplay", soundCommand("linux", installed("aplay", "paplay")))
+	assert.Equal(s.T(), "aplay", soundCommand("freebsd", installed("aplay")))
+	assert.Equal(s.T(), "", soundCommand("linux", installed("afplay")))
+	assert.Equal(s.T(), "afplay", soundCommand("darwin", installed("afplay")))
+	assert.Equal(s.T(), "powershell", soundCommand("windows", installed("powershell")))
+	assert.Equal(s.T(), []string{"-q", "a.wav"}, soundArgs("aplay", "a.wav"))
+	assert.Equal(s.T(), []string{"a.wav"}, soundArgs("afplay", "a.wav"))
+}
+
+func (s *SoundTestSuite) TestPlay() {
+	dir := s.T().TempDir()
+	soundDir := filepath.Join(dir, "sounds")
+	assert.Nil(s.T(), os.Mkdir(soundDir, 0755))
+	assert.Nil(s.T(), os.WriteFile(filepath.Join(soundDir, "won.wav"), []byte("custom"), 0644))
+	var played []string
+	var bell bytes.Buffer
+	player := &soundPlayer{dir: soundDir, cacheDir: filepath.Join(dir, "cache"), out: &bell,
+		command: "afplay", start: func(cmd *exec.Cmd) error {
+			played = append(played, cmd.Args[1])
+			return nil
+		}}
+	game, err := NewGame(4, 2, WithObserver(player))
+	assert.Nil(s.T(), err)
+	for _, char := range "satlf" {
+		_, err = game.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+	}
+	cached := filepath.Join(dir, "cache", "correct.wav")
+	assert.Equal(s.T(), []string{cached, cached, cached, filepath.Join(dir, "cache", "wrong.wav"),
+		cached, filepath.Join(soundDir, "won.wav")}, played)
+	assert.Equal(s.T(), "", bell.String())
+	data, err := os.ReadFile(cached)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), synthesizeWAV(builtinSounds[soundCorrect]), data)
+
+	// Nothing is played if the sound can not be played, or without an audio
+	// player.
+	player.start = func(*exec.Cmd) error { return errors.New("no audio device") }
+	player.play(soundWrong)
+	player.command = ""
+	player.play(soundLost)
+	assert.Equal(s.T(), "", bell.String())
+}
+
+func (s *SoundTestSuite) TestQueue() {
+	played := make(chan string, soundQueue)
+	player := &soundPlayer{cacheDir: s.T().TempDir(), command: "afplay",
+		start: func(cmd *exec.Cmd) error {
+			played <- filepath.Base(cmd.Args[1])
+			return nil
+		}, events: make(chan soundEvent, soundQueue)}
+	game, err := NewGame(4, 1, WithObserver(player))
+	assert.Nil(s.T(), err)
+	// The sounds are played once the guess is made, outside of the game.
+	_, err = game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	go player.run()
+	defer close(player.events)
+	assert.Equal(s.T(), "wrong.wav", <-played)
+	assert.Equal(s.T(), "lost.wav", <-played)
+}
+
+func (s *SoundTestSuite) TestBell() {
+	var bell bytes.Buffer
+	player := &soundPlayer{bellOnly: true, out: &bell}
+	game, err := NewGame(4, 1, WithObserver(player))
+	assert.Nil(s.T(), err)
+	_, err = game.CheckUserInput('s')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "", bell.String())
+	_, err = game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	// The wrong guess and the lost game.
+	assert.Equal(s.T(), "\a\a", bell.String())
+}
+
+func TestSoundTestSuite(t *testing.T) {
+	suite.Run(t, new(SoundTestSuite))
+}
@@ -0,0 +1,37 @@
+package main
+
+// Statistics of all the games played by a player.
+type Stats struct {
+	// Number of finished games.
+	GamesPlayed int `json:"games_played"`
+	// Number of games won.
+	GamesWon int `json:"games_won"`
+	// Number of games lost.
+	GamesLost int `json:"games_lost"`
+	// Number of games won in a row, reset on a loss.
+	CurrentWinStreak int `json:"current_win_streak"`
+	// Number of games lost in a row, reset on a win.
+	CurrentLossStreak int `json:"current_loss_streak"`
+	// Longest streak of wins.
+	LongestWinStreak int `json:"longest_win_streak"`
+}
+
+// Method to record the result of a finished game.
+// Games which are still running are ignored.
+func (s *Stats) Record(state GameState) {
+	switch state {
+	case Won:
+		s.GamesPlayed++
+		s.GamesWon++
+		s.CurrentWinStreak++
+		s.CurrentLossStreak = 0
+		if s.CurrentWinStreak > s.LongestWinStreak {
+			s.LongestWinStreak = s.CurrentWinStreak
+		}
+	case Lost, Forfeited:
+		s.GamesPlayed++
+		s.GamesLost++
+		s.CurrentLossStreak++
+		s.CurrentWinStreak = 0
+	}
+}
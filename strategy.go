@@ -0,0 +1,286 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/hackeracc/WordGuess/engine"
+	"math/rand"
+	"sync"
+)
+
+var (
+	opponentName = flag.String("opponent", "vindictive",
+		"Personality of the computer: \"vindictive\" (keeps the most words after "+
+			"every guess), \"merciful\" (accepts every guess some word contains), "+
+			"\"chaotic\" (keeps a random group of words), \"balanced\" (adapts "+
+			"to let the player win --opponent_win_rate of the games) or \"entropy\" "+
+			"(keeps the words hardest to tell apart).")
+	opponentWinRate = flag.Float64("opponent_win_rate", 0.5,
+		"Fraction of the games the balanced opponent lets the player win, from 0 to 1.")
+)
+
+// Strategy of the computer deciding which words to keep after a guess, i.e.
+// the personality of the opponent. The candidates are split in partitions by
+// the positions of the guessed character, see Decision, and the strategy picks
+// the partition to keep.
+type Strategy interface {
+	// Method to get the name of the strategy, as accepted by ParseStrategy.
+	Name() string
+	// Method to pick the partition to keep. The partitions of the decision are
+	// sorted in the order the vindictive strategy prefers them, and there is
+	// always at least one.
+	// Returns the index of the partition to keep.
+	Choose(d Decision) int
+}
+
+// Strategy notified of the result of every game it played, e.g. to adapt to the
+// player.
+type resultObserver interface {
+	observeResult(won bool)
+}
+
+// Strategy making random choices, which uses the source of the game it plays
+// when it has no source of its own, see WithSeed.
+type randomStrategy interface {
+	// Method to get the strategy using the source of the game.
+	withRand(rng *rand.Rand) Strategy
+}
+
+// Option to set the strategy of the computer. The vindictive strategy is used
+// if not set.
+func WithStrategy(strategy Strategy) GameOption {
+	return func(g *Game) {
+		g.strategy = strategy
+		if s, ok := strategy.(gameStrategy); ok {
+			g.strategy = s.forGame()
+		}
+	}
+}
+
+// Strategy keeping the largest partition, which makes the game as hard as
+// possible. This is the default.
+type Vindictive struct{}
+
+func (Vindictive) Name() string {
+	return "vindictive"
+}
+
+func (Vindictive) Choose(d Decision) int {
+	return 0
+}
+
+// Strategy accepting every guess contained in at least one candidate. Among the
+// partitions accepting the guess, the largest one is kept.
+type Merciful struct{}
+
+func (Merciful) Name() string {
+	return "merciful"
+}
+
+func (Merciful) Choose(d Decision) int {
+	if i := d.acceptingPartition(); i >= 0 {
+		return i
+	}
+	return 0
+}
+
+// Strategy keeping a partition picked at random, whatever its size.
+type Chaotic struct {
+	// Source of the random choices, the source of the game if nil (see
+	// WithSeed). A Rand is not safe for concurrent use, so it must not be
+	// shared by games played concurrently.
+	Rand *rand.Rand
+}
+
+func (Chaotic) Name() string {
+	return "chaotic"
+}
+
+func (c Chaotic) Choose(d Decision) int {
+	return randomIntn(c.Rand, len(d.Partitions))
+}
+
+func (c Chaotic) withRand(rng *rand.Rand) Strategy {
+	if c.Rand == nil {
+		c.Rand = rng
+	}
+	return c
+}
+
+// Strategy adapting to the player so that they win a target fraction of the
+// games: every decision is merciful with a probability which goes up while the
+// player wins less than the target, and down while they win more. The same
+// Balanced must be used for all the games of a player.
+type Balanced struct {
+	// Fraction of the games the player should win, from 0 to 1.
+	Target float64
+	// Source of the random choices, the engine source if nil (see
+	// SetRandSource), as the strategy is shared by the games of the player.
+	Rand *rand.Rand
+
+	// Guards the fields below.
+	mu    sync.Mutex
+	games int
+	wins  int
+}
+
+// Method to create a balanced strategy targeting a win rate.
+func NewBalanced(target float64) *Balanced {
+	return &Balanced{Target: target}
+}
+
+func (b *Balanced) Name() string {
+	return "balanced"
+}
+
+// Method to get the probability of a merciful decision: the target itself
+// before the first game, corrected by how far the player is from the target.
+func (b *Balanced) mercy() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	mercy := b.Target
+	if b.games > 0 {
+		mercy += b.Target - float64(b.wins)/float64(b.games)
+	}
+	switch {
+	case mercy < 0:
+		return 0
+	case mercy > 1:
+		return 1
+	}
+	return mercy
+}
+
+func (b *Balanced) Choose(d Decision) int {
+	b.mu.Lock()
+	roll := randomFloat(b.Rand)
+	b.mu.Unlock()
+	if roll < b.mercy() {
+		return Merciful{}.Choose(d)
+	}
+	return 0
+}
+
+func (b *Balanced) observeResult(won bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.games++
+	if won {
+		b.wins++
+	}
+}
+
+// Method to get a random int in [0, n) from a source, or the engine source if
+// it is nil, see SetRandSource.
+func randomIntn(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return randIntn(n)
+	}
+	return rng.Intn(n)
+}
+
+// Method to get a random float in [0, 1) from a source, or the engine source
+// if it is nil, see SetRandSource.
+func randomFloat(rng *rand.Rand) float64 {
+	if rng == nil {
+		return randFloat64()
+	}
+	return rng.Float64()
+}
+
+// Method to parse a strategy from its name. A new Balanced is created for
+// "balanced", with the given target win rate.
+func ParseStrategy(name string, winRate float64) (Strategy, error) {
+	switch name {
+	case "", "vindictive":
+		return Vindictive{}, nil
+	case "merciful":
+		return Merciful{}, nil
+	case "chaotic":
+		return Chaotic{}, nil
+	case "entropy":
+		return StrategyEntropy{}, nil
+	case "balanced":
+		if winRate < 0 || winRate > 1 {
+			return nil, fmt.Errorf("invalid win rate %v, expected a value from 0 to 1",
+				winRate)
+		}
+		return NewBalanced(winRate), nil
+	}
+	return nil, fmt.Errorf("unknown opponent %q, expected vindictive, merciful, chaotic, "+
+		"balanced or entropy", name)
+}
+
+// Method to get the strategy configured by the flags. Returns an error if the
+// flags are invalid.
+func strategyFromFlags() (Strategy, error) {
+	strategy, err := ParseStrategy(*opponentName, *opponentWinRate)
+	if err != nil {
+		return nil, configError("%w", err)
+	}
+	if _, ok := strategy.(StrategyEntropy); ok &&
+		!featureEnabled(featureEntropyOpponent, *playerName) {
+		return nil, configError("the entropy opponent is not enabled")
+	}
+	if *opponentMaxDodges >= 0 {
+		strategy = Capped{Strategy: strategy, MaxDodges: *opponentMaxDodges}
+	}
+	return strategy, nil
+}
+
+// Method to let the strategy of the game change the decision of the vindictive
+// engine. The game lock must be held.
+// Returns the decision with the partition picked by the strategy first.
+func (g *Game) chooseLocked(decision Decision) Decision {
+	if g.strategy == nil {
+		return decision
+	}
+	strategy := g.strategy
+	if s, ok := strategy.(randomStrategy); ok {
+		strategy = s.withRand(g.randLocked())
+	}
+	var choice int
+	if s, ok := strategy.(wordsStrategy); ok {
+		choice = s.chooseWords(decision,
+			partitionWords(g.candidatesLocked(), decision), g.UsedChars)
+	} else {
+		choice = strategy.Choose(decision)
+	}
+	decision = decision.keep(choice)
+	decision.Strategy = g.strategy.Name()
+	return decision
+}
+
+// Method to get the words of the partition kept by a decision.
+func wordsInPartition(words []string, d Decision) []string {
+	before := []rune(d.Before)
+	var kept []string
+	for _, word := range words {
+		if engine.GuessPattern(word, before, d.Char) == d.Pattern {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
+
+// Method to get the packed words of the partition kept by a decision.
+func packedInPartition(words []packedWord, length int, d Decision) []packedWord {
+	before := []rune(d.Before)
+	var kept []packedWord
+	for _, word := range words {
+		if engine.GuessPattern(word.unpack(length), before, d.Char) == d.Pattern {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
+
+// Method to record that the game ended, once it is won or lost. The game lock
+// must be held.
+func (g *Game) endedLocked() {
+	gameMetrics.sessionEnded()
+	g.notifyStateLocked()
+	if observer, ok := g.strategy.(resultObserver); ok {
+		observer.observeResult(g.State == Won)
+	}
+}
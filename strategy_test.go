@@ -0,0 +1,118 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math/rand"
+	"testing"
+)
+
+type StrategyTestSuite struct {
+	suite.Suite
+}
+
+func (s *StrategyTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats"})
+}
+
+func (s *StrategyTestSuite) TestVindictiveIsDefault() {
+	game, err := NewGame(4, 3, WithStrategy(Vindictive{}))
+	assert.Nil(s.T(), err)
+	accepted, err := game.CheckUserInput('e')
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), accepted)
+	assert.Equal(s.T(), 3, len(game.candidatesLocked()))
+	assert.NotContains(s.T(), explainDecision(*game.LastDecision, DefaultPatternFormat),
+		"opponent")
+}
+
+func (s *StrategyTestSuite) TestMerciful() {
+	game, err := NewGame(4, 3, WithStrategy(Merciful{}))
+	assert.Nil(s.T(), err)
+	accepted, err := game.CheckUserInput('e')
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), accepted)
+	assert.Equal(s.T(), 1, len(game.candidatesLocked()))
+	assert.Equal(s.T(), 3, game.CurrentRetries)
+	d := *game.LastDecision
+	assert.Equal(s.T(), "merciful", d.Strategy)
+	assert.Equal(s.T(), d.Partitions[0].Pattern, d.Pattern)
+	assert.Contains(s.T(), explainDecision(d, DefaultPatternFormat),
+		"The merciful opponent picked this choice.\n")
+
+	// A guess no word contains is still rejected.
+	accepted, err = game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), accepted)
+}
+
+func (s *StrategyTestSuite) TestPacked() {
+	words := []string{"last", "fast", "bets", "code", "cats"}
+	packed, _ := packWords(words)
+	_, d := getMaxSetPacked(NopLogger{}, packed, 4, rawPattern("____"), 'e')
+	d = d.keep(d.acceptingPartition())
+	assert.Equal(s.T(), []string{"code"}, wordsInPartition(words, d))
+	kept := packedInPartition(packed, 4, d)
+	assert.Equal(s.T(), 1, len(kept))
+	assert.Equal(s.T(), "code", kept[0].unpack(4))
+}
+
+func (s *StrategyTestSuite) TestChaotic() {
+	d := Decision{Partitions: make([]Partition, 3)}
+	first := Chaotic{Rand: rand.New(rand.NewSource(7))}
+	second := Chaotic{Rand: rand.New(rand.NewSource(7))}
+	for i := 0; i < 20; i++ {
+		choice := first.Choose(d)
+		assert.Equal(s.T(), second.Choose(d), choice)
+		assert.True(s.T(), choice >= 0 && choice < 3)
+	}
+}
+
+func (s *StrategyTestSuite) TestBalanced() {
+	balanced := NewBalanced(0.5)
+	assert.Equal(s.T(), 0.5, balanced.mercy())
+	// The player lost every game, so the opponent is always merciful.
+	balanced.observeResult(false)
+	balanced.observeResult(false)
+	assert.Equal(s.T(), 1.0, balanced.mercy())
+	d := Decision{Before: "____", Partitions: []Partition{{Pattern: "____"}, {Pattern: "e___"}}}
+	for i := 0; i < 10; i++ {
+		assert.Equal(s.T(), 1, balanced.Choose(d))
+	}
+
+	// The player won every game, so the opponent is never merciful.
+	balanced = NewBalanced(0.5)
+	balanced.observeResult(true)
+	assert.Equal(s.T(), 0.0, balanced.mercy())
+	for i := 0; i < 10; i++ {
+		assert.Equal(s.T(), 0, balanced.Choose(d))
+	}
+}
+
+func (s *StrategyTestSuite) TestBalancedObservesGames() {
+	InitGame([]string{"ab", "cd"})
+	balanced := NewBalanced(0)
+	game, err := NewGame(2, 1, WithStrategy(balanced))
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('x')
+	assert.Equal(s.T(), Lost, game.State)
+	assert.Equal(s.T(), 1, balanced.games)
+	assert.Equal(s.T(), 0, balanced.wins)
+}
+
+func (s *StrategyTestSuite) TestParseStrategy() {
+	strategy, err := ParseStrategy("", 0.5)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), Vindictive{}, strategy)
+	strategy, err = ParseStrategy("balanced", 0.3)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0.3, strategy.(*Balanced).Target)
+	_, err = ParseStrategy("balanced", 1.5)
+	assert.NotNil(s.T(), err)
+	_, err = ParseStrategy("friendly", 0.5)
+	assert.NotNil(s.T(), err)
+}
+
+func TestStrategyTestSuite(t *testing.T) {
+	suite.Run(t, new(StrategyTestSuite))
+}
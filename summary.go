@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	statsFile = flag.String("stats_file", "",
+		"Absolute path of the JSON file where the rating and the summaries of the "+
+			"past sessions are saved. The summary shown when quitting is compared "+
+			"with the previous session only if this is set.")
+	summaryMarkdown = flag.String("summary_markdown", "",
+		"Absolute path of a Markdown file to export the session summary to when "+
+			"quitting.")
+)
+
+const (
+	// Rating of a new player.
+	initialRating = 1200
+	// Rating of the computer, which is the opponent in every game.
+	computerRating = 1200
+	// Max rating change of a single game.
+	ratingFactor = 32
+)
+
+// Result of a single finished game.
+type GameRecord struct {
+	// Word shown to the player at the end of the game.
+	Word string `json:"word"`
+	// Number of incorrect guesses made.
+	RetriesUsed int `json:"retries_used"`
+	// True if the game was won.
+	Won bool `json:"won"`
+	// Word revealed at the end of the game: the word guessed, or the word
+	// picked among the candidates if the game was lost, see Game.RevealWord.
+	Revealed string `json:"revealed"`
+	// Length of the word.
+	Length int `json:"length"`
+	// Time the game ended, and how long it lasted.
+	Ended          time.Time `json:"ended"`
+	DurationMillis int64     `json:"duration_millis"`
+}
+
+// Summary of the games played from the start of the CLI till the player quits.
+type SessionSummary struct {
+	// Time the session ended.
+	Ended time.Time `json:"ended"`
+	// Number of finished games.
+	Games int `json:"games"`
+	// Number of games won.
+	Wins int `json:"wins"`
+	// Fraction of the games won, from 0 to 1.
+	WinRate float64 `json:"win_rate"`
+	// Longest word guessed. Ties go to the word guessed with fewer retries.
+	BestWord string `json:"best_word,omitempty"`
+	// Average number of incorrect guesses per game.
+	AverageRetriesUsed float64 `json:"average_retries_used"`
+	// Rating at the start and at the end of the session.
+	RatingBefore float64 `json:"rating_before"`
+	RatingAfter  float64 `json:"rating_after"`
+	// Points scored in the session, and best score of a single game, see
+	// ScoreConfig.
+	Score     int `json:"score"`
+	BestScore int `json:"best_score"`
+}
+
+// Stats saved across the sessions.
+type StatsHistory struct {
+	// Current rating of the player.
+	Rating float64 `json:"rating"`
+	// Summaries of the past sessions, oldest first.
+	Sessions []SessionSummary `json:"sessions"`
+	// Points scored in all the sessions, and best score of a single game.
+	TotalScore int `json:"total_score"`
+	BestScore  int `json:"best_score"`
+	// Finished games of all the sessions, oldest first, see the "history"
+	// subcommand.
+	Games []GameRecord `json:"games,omitempty"`
+}
+
+// Method to load the saved stats. A missing file is a new player.
+func loadStatsHistory(path string) (StatsHistory, error) {
+	history := StatsHistory{Rating: initialRating}
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return history, err
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return history, fmt.Errorf("invalid stats file %s: %v", path, err)
+	}
+	return history, nil
+}
+
+// Method to save the stats.
+func (h StatsHistory) save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Method to get the summary of the last saved session, nil if there is none.
+func (h StatsHistory) previous() *SessionSummary {
+	if len(h.Sessions) == 0 {
+		return nil
+	}
+	return &h.Sessions[len(h.Sessions)-1]
+}
+
+// Tracker of the games played in the current session.
+type SessionTracker struct {
+	// Finished games, in the order they were played.
+	Records []GameRecord
+	// Rating at the start of the session.
+	RatingBefore float64
+	// Current rating.
+	Rating float64
+	// Points scored so far, and best score of a single game, see AddScore.
+	Score     int
+	BestScore int
+}
+
+func NewSessionTracker(rating float64) *SessionTracker {
+	return &SessionTracker{RatingBefore: rating, Rating: rating}
+}
+
+// Method to record a finished game, which lasted the given duration and ended
+// at the given time, and update the rating. The rating is an Elo rating where
+// the computer is the opponent in every game.
+// Games which are still running are ignored.
+func (t *SessionTracker) Record(game *Game, duration time.Duration, now time.Time) {
+	if game.State == Running {
+		return
+	}
+	record := GameRecord{
+		Word:           DefaultPatternFormat.Format(game.CurrentDisplayedWord),
+		RetriesUsed:    game.AllowedRetries - game.CurrentRetries,
+		Won:            game.State == Won,
+		Revealed:       game.RevealWord(),
+		Length:         game.ExpectedLength,
+		Ended:          now,
+		DurationMillis: duration.Milliseconds(),
+	}
+	t.Records = append(t.Records, record)
+	score := 0.0
+	if record.Won {
+		score = 1
+	}
+	expected := 1 / (1 + math.Pow(10, (computerRating-t.Rating)/400))
+	t.Rating += ratingFactor * (score - expected)
+}
+
+// Method to add the score of a finished game to the session.
+func (t *SessionTracker) AddScore(score GameScore) {
+	t.Score += score.Total
+	if score.Total > t.BestScore {
+		t.BestScore = score.Total
+	}
+}
+
+// Method to summarize the games played so far.
+func (t *SessionTracker) Summary(now time.Time) SessionSummary {
+	summary := SessionSummary{
+		Ended:        now,
+		Games:        len(t.Records),
+		RatingBefore: t.RatingBefore,
+		RatingAfter:  t.Rating,
+		Score:        t.Score,
+		BestScore:    t.BestScore,
+	}
+	if summary.Games == 0 {
+		return summary
+	}
+	retriesUsed, bestRetries := 0, 0
+	for _, record := range t.Records {
+		retriesUsed += record.RetriesUsed
+		if !record.Won {
+			continue
+		}
+		summary.Wins++
+		length := utf8.RuneCountInString(record.Word)
+		bestLength := utf8.RuneCountInString(summary.BestWord)
+		if summary.BestWord == "" || length > bestLength ||
+			(length == bestLength && record.RetriesUsed < bestRetries) {
+			summary.BestWord, bestRetries = record.Word, record.RetriesUsed
+		}
+	}
+	summary.WinRate = float64(summary.Wins) / float64(summary.Games)
+	summary.AverageRetriesUsed = float64(retriesUsed) / float64(summary.Games)
+	return summary
+}
+
+// Method to describe how a value changed since the previous session.
+func trend(current, previous float64, format string) string {
+	switch {
+	case current > previous:
+		return fmt.Sprintf("up from "+format, previous)
+	case current < previous:
+		return fmt.Sprintf("down from "+format, previous)
+	}
+	return "same as"
+}
+
+// Method to get the lines of a summary as (label, value) pairs, compared with
+// the previous session if there is one.
+func summaryLines(summary SessionSummary, previous *SessionSummary) [][2]string {
+	bestWord := summary.BestWord
+	if bestWord == "" {
+		bestWord = "none"
+	}
+	lines := [][2]string{
+		{"Games", fmt.Sprintf("%d (%d won)", summary.Games, summary.Wins)},
+		{"Win rate", fmt.Sprintf("%.0f%%", summary.WinRate*100)},
+		{"Best word", bestWord},
+		{"Average retries used", fmt.Sprintf("%.1f", summary.AverageRetriesUsed)},
+		{"Rating", fmt.Sprintf("%.0f (%+.0f)", summary.RatingAfter,
+			summary.RatingAfter-summary.RatingBefore)},
+		{"Score", fmt.Sprintf("%d (best game %d)", summary.Score, summary.BestScore)},
+	}
+	if previous != nil {
+		lines[1][1] += fmt.Sprintf(" (%s last session)",
+			trend(summary.WinRate*100, previous.WinRate*100, "%.0f%%"))
+		lines[3][1] += fmt.Sprintf(" (%s last session)",
+			trend(summary.AverageRetriesUsed, previous.AverageRetriesUsed, "%.1f"))
+		lines[5][1] += fmt.Sprintf(" (%s last session)",
+			trend(float64(summary.Score), float64(previous.Score), "%.0f"))
+	}
+	return lines
+}
+
+// Method to format a summary for the terminal.
+func formatSummary(summary SessionSummary, previous *SessionSummary) string {
+	var b strings.Builder
+	b.WriteString("Session summary\n")
+	for _, line := range summaryLines(summary, previous) {
+		fmt.Fprintf(&b, "  %-22s %s\n", line[0]+":", line[1])
+	}
+	return b.String()
+}
+
+// Method to format a summary as Markdown.
+func formatSummaryMarkdown(summary SessionSummary, previous *SessionSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Session summary (%s)\n\n", summary.Ended.Format("2006-01-02 15:04"))
+	b.WriteString("| Stat | Value |\n|---|---|\n")
+	for _, line := range summaryLines(summary, previous) {
+		fmt.Fprintf(&b, "| %s | %s |\n", line[0], strings.ReplaceAll(line[1], "|", "\\|"))
+	}
+	return b.String()
+}
+
+// Method to show the summary of the session when the player quits, and save it
+// for the next session.
+// Returns the stats with the session added, which the profile of the player
+// keeps with --profiles.
+func endSession(tracker *SessionTracker, history StatsHistory) StatsHistory {
+	summary := tracker.Summary(time.Now())
+	fmt.Print(formatSummary(summary, history.previous()))
+	if *summaryMarkdown != "" {
+		markdown := formatSummaryMarkdown(summary, history.previous())
+		if err := ioutil.WriteFile(*summaryMarkdown, []byte(markdown), 0644); err != nil {
+			fmt.Println("Unable to export the summary, error ", err)
+		} else {
+			fmt.Println("Summary exported to ", *summaryMarkdown)
+		}
+	}
+	if summary.Games == 0 {
+		return history
+	}
+	history.Rating = tracker.Rating
+	history.Sessions = append(history.Sessions, summary)
+	history.Games = append(history.Games, tracker.Records...)
+	history.TotalScore += summary.Score
+	if summary.BestScore > history.BestScore {
+		history.BestScore = summary.BestScore
+	}
+	if *statsFile == "" && !*useProfiles {
+		return history
+	}
+	fmt.Println("Total score: ", history.TotalScore, ", best game: ", history.BestScore)
+	if *statsFile != "" {
+		if err := history.save(*statsFile); err != nil {
+			fmt.Println("Unable to save the stats, error ", err)
+		}
+	}
+	return history
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type SummaryTestSuite struct {
+	suite.Suite
+}
+
+func (s *SummaryTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code", "hello"})
+}
+
+// Method to play a game with the given guesses.
+func (s *SummaryTestSuite) play(length, retries int, guesses string) *Game {
+	game, err := NewGame(length, retries, WithRetryPolicy(RetryLenient))
+	assert.Nil(s.T(), err)
+	for _, char := range guesses {
+		game.CheckUserInput(char)
+	}
+	return game
+}
+
+func (s *SummaryTestSuite) TestSummary() {
+	tracker := NewSessionTracker(initialRating)
+	tracker.Record(s.play(4, 1, "ab"), time.Second, time.Now())
+	tracker.Record(s.play(5, 3, "helo"), time.Second, time.Now())
+	tracker.Record(s.play(4, 3, "z"), time.Second, time.Now())
+	tracker.AddScore(GameScore{Total: 30})
+	tracker.AddScore(GameScore{Total: 80})
+	summary := tracker.Summary(time.Now())
+	assert.Equal(s.T(), 2, summary.Games)
+	assert.Equal(s.T(), 1, summary.Wins)
+	assert.Equal(s.T(), 0.5, summary.WinRate)
+	assert.Equal(s.T(), "hello", summary.BestWord)
+	// The lost game made 2 incorrect guesses, the won game none.
+	assert.Equal(s.T(), 1.0, summary.AverageRetriesUsed)
+	// A loss and a win against an equal rating cancel out to a small gain.
+	assert.True(s.T(), summary.RatingAfter > summary.RatingBefore)
+	assert.True(s.T(), summary.RatingAfter < summary.RatingBefore+1)
+	assert.Equal(s.T(), 110, summary.Score)
+	assert.Equal(s.T(), 80, summary.BestScore)
+}
+
+func (s *SummaryTestSuite) TestTrend() {
+	previous := &SessionSummary{WinRate: 0.25, AverageRetriesUsed: 1, Score: 200}
+	summary := SessionSummary{Games: 2, Wins: 1, WinRate: 0.5, AverageRetriesUsed: 1,
+		BestWord: "hello", RatingBefore: 1200, RatingAfter: 1216, Score: 110, BestScore: 80}
+	text := formatSummary(summary, previous)
+	assert.Contains(s.T(), text, "50% (up from 25% last session)")
+	assert.Contains(s.T(), text, "1.0 (same as last session)")
+	assert.Contains(s.T(), text, "1216 (+16)")
+	assert.Contains(s.T(), text, "110 (best game 80) (down from 200 last session)")
+	assert.NotContains(s.T(), formatSummary(summary, nil), "last session")
+
+	markdown := formatSummaryMarkdown(summary, previous)
+	assert.True(s.T(), strings.HasPrefix(markdown, "# Session summary"))
+	assert.Contains(s.T(), markdown, "| Best word | hello |")
+}
+
+func (s *SummaryTestSuite) TestHistory() {
+	path := filepath.Join(s.T().TempDir(), "stats.json")
+	history, err := loadStatsHistory(path)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), float64(initialRating), history.Rating)
+	assert.Nil(s.T(), history.previous())
+
+	history.Rating = 1210
+	history.Sessions = append(history.Sessions, SessionSummary{Games: 3, Wins: 2})
+	history.TotalScore = 450
+	assert.Nil(s.T(), history.save(path))
+	loaded, err := loadStatsHistory(path)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1210.0, loaded.Rating)
+	assert.Equal(s.T(), 3, loaded.previous().Games)
+	assert.Equal(s.T(), 450, loaded.TotalScore)
+}
+
+func TestSummaryTestSuite(t *testing.T) {
+	suite.Run(t, new(SummaryTestSuite))
+}
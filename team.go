@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"github.com/hackeracc/WordGuess/engine"
+	"time"
+)
+
+// Letter submitted by a teammate in a team turn, see CheckTeamInput.
+type TeamGuess struct {
+	// Name of the teammate.
+	Player string
+	// Guessed character.
+	Char rune
+}
+
+// Result of a letter of a team turn.
+type TeamGuessResult struct {
+	TeamGuess
+	// True if the character is present in the word.
+	Accepted bool
+}
+
+// Method to play a turn where every teammate submits a letter at once, like in
+// the simultaneous (lightning) team variant. All the letters are resolved in a
+// single adversarial step: the candidates are split by the positions of all
+// the letters together and the largest group is kept, so the computer can not
+// answer the letters one at a time. The strategy of the game (see
+// WithStrategy) only applies to single guesses.
+// Every rejected letter consumes a retry, and the game is lost once they run
+// out, even if the accepted letters of the turn complete the word. Teammates
+// submitting the same letter share its result and its retry. A turn is recorded
+// for every distinct letter, with the first teammate who submitted it.
+// Params:
+// guesses: Letters of the teammates, at least one.
+//
+// Returns the result of every letter, in the order of the guesses. Nothing is
+// played if an error is returned, for the same reasons as CheckUserInput.
+func (g *Game) CheckTeamInput(guesses []TeamGuess) ([]TeamGuessResult, error) {
+	return g.CheckTeamInputCtx(context.Background(), guesses)
+}
+
+// Method to play a team turn like CheckTeamInput, for a turn which ends when
+// the context is done, see CheckUserInputCtx.
+func (g *Game) CheckTeamInputCtx(ctx context.Context, guesses []TeamGuess) (
+	[]TeamGuessResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(guesses) == 0 {
+		return nil, newGameError(ErrInvalidCharacter, "A team turn needs at least one letter")
+	}
+	if err := g.startTurnLocked(ctx); err != nil {
+		return nil, err
+	}
+	// The letters of the turn, without the duplicates.
+	var chars []rune
+	guesses = append([]TeamGuess{}, guesses...)
+	for i, guess := range guesses {
+		guess.Char = g.dict.alphabet.Fold(guess.Char)
+		guesses[i] = guess
+		if !g.dict.alphabet.Contains(guess.Char) {
+			return nil, newGameError(ErrInvalidCharacter, "Character %s guessed by %s "+
+				"is not a valid letter for this dictionary.", string(guess.Char), guess.Player)
+		}
+		if contains(g.UsedChars, guess.Char) {
+			return nil, newGameError(ErrCharAlreadyUsed, "Character %s guessed by %s "+
+				"has been used.", string(guess.Char), guess.Player)
+		}
+		if !contains(chars, guess.Char) {
+			chars = append(chars, guess.Char)
+		}
+	}
+	g.UsedChars = append(g.UsedChars, chars...)
+	g.resetDeadline()
+	start := time.Now()
+	defer func() {
+		gameMetrics.observeGuess(time.Since(start))
+		if g.State != Running {
+			g.endedLocked()
+		}
+	}()
+
+	pattern := g.keepJointPatternLocked(chars)
+	g.Logger.Infof("Team guessed %s, new pattern %s", string(chars), pattern)
+	// A joint turn is not a single decision which can be explained.
+	g.LastDecision = nil
+	g.revealLocked([]rune(pattern))
+	results := make([]TeamGuessResult, len(guesses))
+	for i, guess := range guesses {
+		results[i] = TeamGuessResult{
+			TeamGuess: guess,
+			Accepted:  contains(g.CurrentDisplayedWord, guess.Char),
+		}
+	}
+	for _, char := range chars {
+		accepted := contains(g.CurrentDisplayedWord, char)
+		if !accepted && g.State == Running {
+			g.consumeRetry()
+		}
+		g.recordTurnLocked(Turn{Kind: TurnGuess, Char: char, Accepted: accepted,
+			Player: teamGuesser(guesses, char)})
+	}
+	if g.State == Running && !contains(g.CurrentDisplayedWord, emptyChar) {
+		g.State = Won
+	}
+	return results, nil
+}
+
+// Method to keep the largest group of candidates sharing the positions of all
+// the letters of a team turn. The game lock must be held.
+// Returns the word shown to the player once the letters are revealed.
+func (g *Game) keepJointPatternLocked(chars []rune) string {
+	sizes := make(map[string]int)
+	var best string
+	bestSize := -1
+	for _, word := range g.candidatesLocked() {
+		pattern := jointPattern(word, g.CurrentDisplayedWord, chars)
+		sizes[pattern]++
+	}
+	for pattern, size := range sizes {
+		if engine.Prefer(pattern, size, best, bestSize) {
+			best, bestSize = pattern, size
+		}
+	}
+	if g.packed != nil {
+		var kept []packedWord
+		for _, word := range g.packed {
+			if jointPattern(word.unpack(g.ExpectedLength), g.CurrentDisplayedWord, chars) == best {
+				kept = append(kept, word)
+			}
+		}
+		g.packed = kept
+	} else {
+		var kept []string
+		for _, word := range g.CurrentSetOfWords {
+			if jointPattern(word, g.CurrentDisplayedWord, chars) == best {
+				kept = append(kept, word)
+			}
+		}
+		g.CurrentSetOfWords = kept
+		g.bits = nil
+	}
+	return best
+}
+
+// Method to get the word displayed after all the letters of a team turn if a
+// word is kept.
+func jointPattern(word string, currWord []rune, chars []rune) string {
+	pattern := make([]rune, len(currWord))
+	copy(pattern, currWord)
+	for idx, wordChar := range []rune(word) {
+		if contains(chars, wordChar) {
+			pattern[idx] = wordChar
+		}
+	}
+	return string(pattern)
+}
+
+// Method to get the first teammate who guessed a character.
+func teamGuesser(guesses []TeamGuess, char rune) string {
+	for _, guess := range guesses {
+		if guess.Char == char {
+			return guess.Player
+		}
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type TeamTestSuite struct {
+	suite.Suite
+}
+
+func (s *TeamTestSuite) SetupTest() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats"})
+}
+
+func (s *TeamTestSuite) TestJointPartition() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	results, err := game.CheckTeamInput([]TeamGuess{
+		{Player: "alice", Char: 'a'}, {Player: "bob", Char: 'e'}, {Player: "carol", Char: 'a'},
+	})
+	assert.Nil(s.T(), err)
+	// Guessed one at a time, 'a' would keep 3 words and then 'e' would be
+	// rejected. Together, the words with 'a' and without 'e' are kept too.
+	assert.Equal(s.T(), []TeamGuessResult{
+		{TeamGuess: TeamGuess{Player: "alice", Char: 'a'}, Accepted: true},
+		{TeamGuess: TeamGuess{Player: "bob", Char: 'e'}, Accepted: false},
+		{TeamGuess: TeamGuess{Player: "carol", Char: 'a'}, Accepted: true},
+	}, results)
+	assert.Equal(s.T(), "_a__", DefaultPatternFormat.Format(game.CurrentDisplayedWord))
+	assert.Equal(s.T(), []string{"cats", "fast", "last"}, game.Candidates())
+	assert.Equal(s.T(), 2, game.CurrentRetries)
+	assert.Equal(s.T(), []rune{'a', 'e'}, game.UsedChars)
+	assert.Nil(s.T(), game.LastDecision)
+	assert.Equal(s.T(), []Turn{
+		{Kind: TurnGuess, Char: 'a', Accepted: true, Player: "alice", RetriesLeft: 3},
+		{Kind: TurnGuess, Char: 'e', Player: "bob", RetriesLeft: 2},
+	}, game.Turns)
+}
+
+func (s *TeamTestSuite) TestJointPatternReveals() {
+	InitGame([]string{"ab", "cd"})
+	game, err := NewGame(2, 3, withSecretWord(0))
+	assert.Nil(s.T(), err)
+	word := game.Candidates()[0]
+	results, err := game.CheckTeamInput([]TeamGuess{
+		{Player: "alice", Char: rune(word[0])}, {Player: "bob", Char: rune(word[1])},
+	})
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), results[0].Accepted && results[1].Accepted)
+	assert.Equal(s.T(), Won, game.State)
+}
+
+func (s *TeamTestSuite) TestRejectionsLose() {
+	game, err := NewGame(4, 1)
+	assert.Nil(s.T(), err)
+	_, err = game.CheckTeamInput([]TeamGuess{{Player: "alice", Char: 'x'},
+		{Player: "bob", Char: 'z'}})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), Lost, game.State)
+	_, err = game.CheckTeamInput([]TeamGuess{{Player: "alice", Char: 'a'}})
+	assert.True(s.T(), errors.Is(err, ErrGameFinished))
+}
+
+func (s *TeamTestSuite) TestInvalidTurn() {
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	game.CheckUserInput('e')
+	_, err = game.CheckTeamInput([]TeamGuess{{Player: "alice", Char: 'a'},
+		{Player: "bob", Char: 'e'}})
+	assert.True(s.T(), errors.Is(err, ErrCharAlreadyUsed))
+	_, err = game.CheckTeamInput([]TeamGuess{{Player: "alice", Char: '4'}})
+	assert.True(s.T(), errors.Is(err, ErrInvalidCharacter))
+	_, err = game.CheckTeamInput(nil)
+	assert.True(s.T(), errors.Is(err, ErrInvalidCharacter))
+	// Nothing was played.
+	assert.Equal(s.T(), []rune{'e'}, game.UsedChars)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = game.CheckTeamInputCtx(ctx, []TeamGuess{{Player: "alice", Char: 'a'}})
+	assert.Equal(s.T(), context.Canceled, err)
+}
+
+func (s *TeamTestSuite) TestPacked() {
+	*compactWords = true
+	defer func() { *compactWords = false }()
+	InitGame([]string{"last", "fast", "bets", "code", "cats"})
+	game, err := NewGame(4, 3)
+	assert.Nil(s.T(), err)
+	_, err = game.CheckTeamInput([]TeamGuess{{Player: "alice", Char: 'a'},
+		{Player: "bob", Char: 's'}})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "_as_", DefaultPatternFormat.Format(game.CurrentDisplayedWord))
+	assert.Equal(s.T(), []string{"fast", "last"}, game.Candidates())
+}
+
+func TestTeamTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamTestSuite))
+}
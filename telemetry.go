@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+	"unicode"
+)
+
+var (
+	telemetryFile = flag.String("telemetry_file", "",
+		"Absolute path of the JSON file where the consent to share anonymous "+
+			"gameplay statistics and the statistics not shared yet are kept. "+
+			"Telemetry is off if empty.")
+	telemetryEndpoint = flag.String("telemetry_endpoint", "",
+		"URL the anonymous statistics are posted to, once the player agreed. "+
+			"They are only kept locally if empty.")
+	telemetryInterval = flag.Duration("telemetry_interval", 24*time.Hour,
+		"Min time between two posts of the anonymous statistics.")
+)
+
+// Anonymous statistics of the games finished since the last post. They never
+// contain the words, the guesses or anything about the player.
+type TelemetrySummary struct {
+	// Time the first game of the summary finished.
+	Since time.Time `json:"since,omitempty"`
+	// Number of finished games.
+	Games int `json:"games"`
+	// Number of games won.
+	Wins int `json:"wins"`
+	// Number of finished games by word length.
+	WordLengths map[int]int `json:"word_lengths,omitempty"`
+	// Total incorrect guesses, timeouts included.
+	RetriesUsed int `json:"retries_used"`
+	// Total characters guessed.
+	Guesses int `json:"guesses"`
+	// Total turns which timed out.
+	Timeouts int `json:"timeouts"`
+}
+
+// Method to add a finished game to the summary.
+func (s *TelemetrySummary) record(game *Game, now time.Time) {
+	if s.Games == 0 {
+		s.Since = now
+	}
+	s.Games++
+	if game.State == Won {
+		s.Wins++
+	}
+	if s.WordLengths == nil {
+		s.WordLengths = make(map[int]int)
+	}
+	s.WordLengths[game.ExpectedLength]++
+	s.RetriesUsed += game.AllowedRetries - game.CurrentRetries
+	for _, turn := range game.Turns {
+		if turn.Kind == TurnTimeout {
+			s.Timeouts++
+		} else {
+			s.Guesses++
+		}
+	}
+}
+
+// Telemetry state saved in the telemetry file.
+type telemetryState struct {
+	// Answer of the player to the consent prompt, nil if never asked.
+	Consent *bool `json:"consent,omitempty"`
+	// Time of the last successful post.
+	LastSent time.Time `json:"last_sent,omitempty"`
+	// Statistics not posted yet.
+	Pending TelemetrySummary `json:"pending"`
+}
+
+// Method to load the telemetry state. A missing file means the player was never
+// asked.
+func loadTelemetryState(path string) (telemetryState, error) {
+	var state telemetryState
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("invalid telemetry file %s: %v", path, err)
+	}
+	return state, nil
+}
+
+// Method to save the telemetry state.
+func (t telemetryState) save(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Method to check if the player agreed to share the statistics.
+func (t telemetryState) enabled() bool {
+	return t.Consent != nil && *t.Consent
+}
+
+// Telemetry of the CLI. Nothing is recorded without the consent of the player,
+// and nothing leaves the machine without an endpoint.
+type telemetry struct {
+	path     string
+	endpoint string
+	interval time.Duration
+	client   *http.Client
+	state    telemetryState
+}
+
+// Method to post a summary to the endpoint.
+func (t *telemetry) send(summary TelemetrySummary) error {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Post(t.endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Method to record a finished game, posting the pending statistics if the
+// interval has passed since the last post. The statistics are kept for the
+// next time if the post fails. Does nothing without consent.
+func (t *telemetry) record(game *Game, now time.Time) {
+	if t == nil || !t.state.enabled() {
+		return
+	}
+	t.state.Pending.record(game, now)
+	if t.endpoint != "" && !now.Before(t.state.LastSent.Add(t.interval)) {
+		if err := t.send(t.state.Pending); err != nil {
+			defaultLogger.Errorf("Unable to post the telemetry to %s, error %v", t.endpoint, err)
+		} else {
+			t.state.LastSent = now
+			t.state.Pending = TelemetrySummary{}
+		}
+	}
+	if err := t.state.save(t.path); err != nil {
+		defaultLogger.Errorf("Unable to save the telemetry, error %v", err)
+	}
+}
+
+// Method to load the telemetry set by the flags, nil if it is off. The player
+// is asked for consent the first time. Returns an error if the telemetry file
+// can not be read.
+func telemetryFromFlags() (*telemetry, error) {
+	if *telemetryFile == "" {
+		return nil, nil
+	}
+	state, err := loadTelemetryState(*telemetryFile)
+	if err != nil {
+		return nil, configError("unable to load the telemetry: %w", err)
+	}
+	t := &telemetry{
+		path:     *telemetryFile,
+		endpoint: *telemetryEndpoint,
+		interval: *telemetryInterval,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		state:    state,
+	}
+	if t.state.Consent == nil {
+		fmt.Println("Help improve the game by sharing anonymous statistics: the number " +
+			"of games, wins, word lengths, guesses and retries used. Words, guesses " +
+			"and names are never shared. Run \"hangman telemetry show\" to see them " +
+			"at any time. Share them? (Y/N): ")
+		answer, err := readChar()
+		if err != nil {
+			return nil, err
+		}
+		consent := unicode.ToLower(answer) == 'y'
+		t.state.Consent = &consent
+		if err := t.state.save(t.path); err != nil {
+			fmt.Println("Unable to save the telemetry consent, error ", err)
+		}
+	}
+	return t, nil
+}
+
+// Driver method for the "telemetry" subcommand. "telemetry show" prints the
+// statistics which would be posted next without posting them, "telemetry on"
+// and "telemetry off" change the consent.
+func StartTelemetry(args []string) error {
+	if len(args) != 1 || (args[0] != "show" && args[0] != "on" && args[0] != "off") {
+		return usageError("hangman --telemetry_file=<path> telemetry show|on|off")
+	}
+	if *telemetryFile == "" {
+		fmt.Println("Telemetry is off, set --telemetry_file to turn it on.")
+		return nil
+	}
+	state, err := loadTelemetryState(*telemetryFile)
+	if err != nil {
+		return configError("unable to load the telemetry: %w", err)
+	}
+	if args[0] != "show" {
+		consent := args[0] == "on"
+		state.Consent = &consent
+		if err := state.save(*telemetryFile); err != nil {
+			return fmt.Errorf("unable to save the telemetry consent: %w", err)
+		}
+	}
+	switch {
+	case state.Consent == nil:
+		fmt.Println("Consent: not asked yet")
+	case *state.Consent:
+		fmt.Println("Consent: given")
+	default:
+		fmt.Println("Consent: refused")
+	}
+	if *telemetryEndpoint == "" {
+		fmt.Println("Endpoint: none, the statistics are only kept locally")
+	} else {
+		fmt.Println("Endpoint:", *telemetryEndpoint)
+	}
+	if args[0] == "show" {
+		data, _ := json.MarshalIndent(state.Pending, "", "  ")
+		fmt.Println("Statistics to post next (dry run, nothing is posted):")
+		fmt.Println(string(data))
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type TelemetryTestSuite struct {
+	suite.Suite
+	// Summaries received by the endpoint.
+	received []TelemetrySummary
+	// Status returned by the endpoint.
+	status   int
+	endpoint *httptest.Server
+	t        *telemetry
+}
+
+func (s *TelemetryTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *TelemetryTestSuite) SetupTest() {
+	s.received = nil
+	s.status = http.StatusNoContent
+	s.endpoint = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+		var summary TelemetrySummary
+		json.NewDecoder(r.Body).Decode(&summary)
+		s.received = append(s.received, summary)
+		w.WriteHeader(s.status)
+	}))
+	consent := true
+	s.t = &telemetry{
+		path:     filepath.Join(s.T().TempDir(), "telemetry.json"),
+		endpoint: s.endpoint.URL,
+		interval: time.Hour,
+		client:   s.endpoint.Client(),
+		state:    telemetryState{Consent: &consent},
+	}
+}
+
+func (s *TelemetryTestSuite) TearDownTest() {
+	s.endpoint.Close()
+}
+
+// Method to play a game of 4 letters with 2 retries, guessing the chars.
+func (s *TelemetryTestSuite) play(guesses string) *Game {
+	game, err := NewGame(4, 2)
+	assert.Nil(s.T(), err)
+	for _, char := range guesses {
+		game.CheckUserInput(char)
+	}
+	return game
+}
+
+func (s *TelemetryTestSuite) TestSummary() {
+	var summary TelemetrySummary
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	summary.record(s.play("ij"), now)
+	summary.record(s.play("i"), now.Add(time.Minute))
+	assert.Equal(s.T(), TelemetrySummary{
+		Since:       now,
+		Games:       2,
+		WordLengths: map[int]int{4: 2},
+		RetriesUsed: 3,
+		Guesses:     3,
+	}, summary)
+}
+
+func (s *TelemetryTestSuite) TestPostsPeriodically() {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.t.record(s.play("i"), now)
+	assert.Equal(s.T(), 1, len(s.received))
+	assert.Equal(s.T(), 1, s.received[0].Games)
+	// Within the interval the games are only aggregated locally.
+	s.t.record(s.play("i"), now.Add(time.Minute))
+	s.t.record(s.play("i"), now.Add(2*time.Minute))
+	assert.Equal(s.T(), 1, len(s.received))
+	s.t.record(s.play("i"), now.Add(time.Hour))
+	assert.Equal(s.T(), 2, len(s.received))
+	assert.Equal(s.T(), 3, s.received[1].Games)
+
+	saved, err := loadTelemetryState(s.t.path)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, saved.Pending.Games)
+	assert.Equal(s.T(), true, saved.LastSent.Equal(now.Add(time.Hour)))
+}
+
+func (s *TelemetryTestSuite) TestKeptWhenPostFails() {
+	s.status = http.StatusInternalServerError
+	now := time.Now()
+	s.t.record(s.play("i"), now)
+	s.t.record(s.play("i"), now)
+	assert.Equal(s.T(), 2, len(s.received))
+	saved, err := loadTelemetryState(s.t.path)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, saved.Pending.Games)
+}
+
+func (s *TelemetryTestSuite) TestNothingWithoutConsent() {
+	refused := false
+	s.t.state.Consent = &refused
+	s.t.record(s.play("i"), time.Now())
+	s.t.state.Consent = nil
+	s.t.record(s.play("i"), time.Now())
+	assert.Equal(s.T(), 0, len(s.received))
+	assert.Equal(s.T(), 0, s.t.state.Pending.Games)
+	// A nil telemetry is off.
+	var off *telemetry
+	off.record(s.play("i"), time.Now())
+}
+
+func (s *TelemetryTestSuite) TestOffline() {
+	s.t.endpoint = ""
+	s.t.record(s.play("i"), time.Now())
+	assert.Equal(s.T(), 0, len(s.received))
+	saved, err := loadTelemetryState(s.t.path)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, saved.Pending.Games)
+	assert.Equal(s.T(), true, saved.enabled())
+}
+
+func TestTelemetryTestSuite(t *testing.T) {
+	suite.Run(t, new(TelemetryTestSuite))
+}
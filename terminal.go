@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	lineInput = flag.Bool("line_input", false,
+		"Read whole lines ended by Enter, instead of registering every key as "+
+			"soon as it is pressed. Lines are always read when stdin is not a terminal.")
+)
+
+const (
+	// Control characters sent by the terminal in key mode.
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+	keyCtrlH     = 0x08
+	keyReturn    = '\r'
+	keyLineFeed  = '\n'
+	keyFirstChar = 0x20
+)
+
+// Kind of a key pressed on the terminal.
+type keyKind int
+
+const (
+	keyChar keyKind = iota
+	keyEnter
+	keyBackspace
+	keyInterrupt
+)
+
+// Key pressed on the terminal. Char is only set for keyChar.
+type keyPress struct {
+	Kind keyKind
+	Char rune
+}
+
+// Settings of the terminal before it was switched to key mode, as printed by
+// "stty -g". Empty if the terminal is not in key mode.
+var savedTerminal string
+
+// Method to check if a file is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Method to run stty on the terminal of stdin.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// Method to switch the terminal to key mode: every key is sent to the game as
+// soon as it is pressed, without being echoed, and Ctrl+C is read as a key
+// instead of killing the game. Fails if stdin is not a terminal or stty is not
+// available, e.g. on Windows.
+func enableKeyMode() error {
+	saved, err := stty("-g")
+	if err != nil {
+		return err
+	}
+	if _, err := stty("-icanon", "-echo", "-isig", "min", "1", "time", "0"); err != nil {
+		return err
+	}
+	savedTerminal = saved
+	return nil
+}
+
+// Method to restore the settings the terminal had before key mode. Does
+// nothing if the terminal is not in key mode.
+func restoreTerminal() {
+	if savedTerminal == "" {
+		return
+	}
+	if _, err := stty(savedTerminal); err != nil {
+		defaultLogger.Errorf("Unable to restore the terminal, error %v", err)
+	}
+	savedTerminal = ""
+}
+
+// Method to read the keys pressed on a terminal in key mode till the input
+// ends or Ctrl+D is pressed. Escape sequences (arrow keys, function keys,
+// Alt+key) and the other control characters are ignored.
+func readKeys(in io.Reader, emit func(keyPress)) {
+	r := bufio.NewReader(in)
+	for {
+		char, _, err := r.ReadRune()
+		if err != nil {
+			return
+		}
+		switch {
+		case char == keyCtrlD:
+			return
+		case char == keyCtrlC:
+			emit(keyPress{Kind: keyInterrupt})
+		case char == keyReturn || char == keyLineFeed:
+			emit(keyPress{Kind: keyEnter})
+		case char == keyDelete || char == keyCtrlH:
+			emit(keyPress{Kind: keyBackspace})
+		case char == keyEscape:
+			skipEscapeSequence(r)
+		case char >= keyFirstChar && char != utf8.RuneError:
+			emit(keyPress{Kind: keyChar, Char: char})
+		}
+	}
+}
+
+// Method to skip the rest of an escape sequence once its escape character was
+// read. A terminal sends a whole sequence at once, so an escape with nothing
+// buffered after it is the Escape key itself.
+func skipEscapeSequence(r *bufio.Reader) {
+	if r.Buffered() == 0 {
+		return
+	}
+	next, _, err := r.ReadRune()
+	if err != nil {
+		return
+	}
+	switch next {
+	case '[':
+		// Control sequence: parameters and intermediates, then a final byte
+		// in the range '@' to '~'.
+		for {
+			b, err := r.ReadByte()
+			if err != nil || (b >= '@' && b <= '~') {
+				return
+			}
+		}
+	case 'O':
+		// Single shift sequence, e.g. F1 or the arrow keys in application
+		// mode.
+		r.ReadByte()
+	}
+	// Anything else was Alt+key, whose key was just skipped.
+}
+
+// Editor of the line being typed in key mode. The terminal does not echo in
+// key mode, so the editor echoes the keys itself.
+type lineEditor struct {
+	out  io.Writer
+	line []rune
+}
+
+// Method to apply a key pressed to the line being typed.
+// Params:
+// key: the key pressed.
+// single: whether the first character typed completes the line, to register a
+// guess without waiting for Enter.
+// Returns:
+// the line and true once it is complete, or false if more keys are
+// needed.
+func (e *lineEditor) press(key keyPress, single bool) (string, bool) {
+	switch key.Kind {
+	case keyChar:
+		fmt.Fprint(e.out, string(key.Char))
+		e.line = append(e.line, key.Char)
+		if !single || len(e.line) > 1 {
+			return "", false
+		}
+	case keyBackspace:
+		if len(e.line) > 0 {
+			e.line = e.line[:len(e.line)-1]
+			fmt.Fprint(e.out, "\b \b")
+		}
+		return "", false
+	case keyEnter:
+	default:
+		return "", false
+	}
+	fmt.Fprintln(e.out)
+	line := string(e.line)
+	e.line = nil
+	return line, true
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type TerminalTestSuite struct {
+	suite.Suite
+}
+
+// Method to get the keys read from some terminal input.
+func pressedKeys(input string) []keyPress {
+	var keys []keyPress
+	readKeys(strings.NewReader(input), func(key keyPress) {
+		keys = append(keys, key)
+	})
+	return keys
+}
+
+func (s *TerminalTestSuite) TestReadKeys() {
+	// "a", up arrow, "é", backspace, Enter, Ctrl+C.
+	keys := pressedKeys("a\x1b[Aé\x7f\r\x03")
+	assert.Equal(s.T(), []keyPress{
+		{Kind: keyChar, Char: 'a'},
+		{Kind: keyChar, Char: 'é'},
+		{Kind: keyBackspace},
+		{Kind: keyEnter},
+		{Kind: keyInterrupt},
+	}, keys)
+}
+
+func (s *TerminalTestSuite) TestIgnoredKeys() {
+	// F5, arrow in application mode, Alt+x, a control character and a lone
+	// Escape.
+	assert.Nil(s.T(), pressedKeys("\x1b[15~\x1bOB\x1bx\x01\x1b"))
+}
+
+func (s *TerminalTestSuite) TestCtrlDEndsInput() {
+	assert.Equal(s.T(), []keyPress{{Kind: keyChar, Char: 'a'}}, pressedKeys("a\x04b"))
+}
+
+func (s *TerminalTestSuite) TestSingleKey() {
+	var out bytes.Buffer
+	editor := lineEditor{out: &out}
+	line, complete := editor.press(keyPress{Kind: keyChar, Char: 'x'}, true)
+	assert.True(s.T(), complete)
+	assert.Equal(s.T(), "x", line)
+	assert.Equal(s.T(), "x\n", out.String())
+}
+
+func (s *TerminalTestSuite) TestLineEditing() {
+	var out bytes.Buffer
+	editor := lineEditor{out: &out}
+	keys := append(pressedKeys("12"), keyPress{Kind: keyBackspace},
+		keyPress{Kind: keyBackspace}, keyPress{Kind: keyBackspace})
+	keys = append(keys, pressedKeys("7")...)
+	for _, key := range keys {
+		_, complete := editor.press(key, false)
+		assert.False(s.T(), complete)
+	}
+	line, complete := editor.press(keyPress{Kind: keyEnter}, false)
+	assert.True(s.T(), complete)
+	assert.Equal(s.T(), "7", line)
+	assert.Equal(s.T(), "12\b \b\b \b7\n", out.String())
+}
+
+func (s *TerminalTestSuite) TestNotATerminal() {
+	assert.False(s.T(), isTerminal(nil))
+}
+
+func TestTerminalTestSuite(t *testing.T) {
+	suite.Run(t, new(TerminalTestSuite))
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Kind of a turn of a game.
+type TurnKind int
+
+const (
+	// The player guessed a character.
+	TurnGuess TurnKind = iota
+	// The player did not guess before the deadline of the turn.
+	TurnTimeout
+)
+
+func (k TurnKind) String() string {
+	if k == TurnTimeout {
+		return "timeout"
+	}
+	return "guess"
+}
+
+// Turn played in a game.
+type Turn struct {
+	Kind TurnKind
+	// Guessed character, zero for a timeout.
+	Char rune
+	// True if the guess was accepted. Always false for a timeout.
+	Accepted bool
+	// Teammate who guessed the character in a team turn, see CheckTeamInput.
+	// Empty otherwise.
+	Player string
+	// Retries left after the turn.
+	RetriesLeft int
+}
+
+// Function called after every turn of a game, e.g. to show the timeouts as they
+// happen. It is called with the game locked, so it must not call the methods of
+// the game.
+type TurnListener func(Turn)
+
+// Interface to get the current time. Games use it to enforce the guess timeout,
+// which lets tests control the time instead of sleeping.
+type Clock interface {
+	Now() time.Time
+}
+
+// Clock returning the actual time.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Option to limit the time allowed for every guess. A retry is consumed every
+// time the player does not guess within the timeout.
+func WithGuessTimeout(timeout time.Duration) GameOption {
+	return func(g *Game) {
+		g.GuessTimeout = timeout
+	}
+}
+
+// Option to get notified of every turn, including the timeouts. It is a
+// shorthand for an Observer of the turns only.
+func WithTurnListener(listener TurnListener) GameOption {
+	return WithObserver(ObserverFuncs{Guess: listener})
+}
+
+// Option to use a custom clock for the guess timeout.
+func WithClock(clock Clock) GameOption {
+	return func(g *Game) {
+		g.clock = clock
+	}
+}
+
+// Method to get the time by which the next guess is expected.
+// Returns false if the game has no guess timeout or is not running.
+func (g *Game) Deadline() (time.Time, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.GuessTimeout <= 0 || g.State != Running {
+		return time.Time{}, false
+	}
+	return g.deadline, true
+}
+
+// Method to get a context which is done when the current turn ends, i.e. at the
+// deadline of the next guess. Pass it to CheckUserInputCtx so that a guess given
+// too late is recorded as a timeout. The deadline is in real time, so this is
+// not meant for games using a custom clock.
+func (g *Game) TurnContext(parent context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := g.Deadline()
+	if !ok {
+		return context.WithCancel(parent)
+	}
+	return context.WithDeadline(parent, deadline)
+}
+
+// Method to enforce the guess timeout. It should be called periodically while
+// waiting for the player's guess (it is also called before every guess).
+// A retry is consumed for every timeout which has passed since the last guess,
+// and the game is lost once all the retries are used.
+// Returns true if at least one retry was consumed.
+func (g *Game) Tick() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.tickLocked()
+}
+
+// Method to enforce the guess timeout, the game lock must be held.
+func (g *Game) tickLocked() bool {
+	if g.GuessTimeout <= 0 || g.State != Running {
+		return false
+	}
+	now := g.clock.Now()
+	timedOut := false
+	for g.State == Running && !now.Before(g.deadline) {
+		g.Logger.Infof("Guess timed out, deadline was %v", g.deadline)
+		g.timeoutLocked()
+		g.deadline = g.deadline.Add(g.GuessTimeout)
+		timedOut = true
+	}
+	return timedOut
+}
+
+// Method to record a turn which timed out, consuming a retry. The game lock must
+// be held.
+func (g *Game) timeoutLocked() {
+	g.consumeRetry()
+	g.recordTurnLocked(Turn{Kind: TurnTimeout})
+	if g.State != Running {
+		g.endedLocked()
+	}
+}
+
+// Method to record a turn and notify the observers. The game lock must be held.
+func (g *Game) recordTurnLocked(turn Turn) {
+	turn.RetriesLeft = g.CurrentRetries
+	if turn.RetriesLeft < 0 {
+		turn.RetriesLeft = 0
+	}
+	g.Turns = append(g.Turns, turn)
+	g.recordReplayLocked(turn)
+	g.recordAnalysisLocked(turn)
+	g.notifyGuessLocked(turn)
+}
+
+// Method to start the timer for the next guess.
+func (g *Game) resetDeadline() {
+	if g.GuessTimeout > 0 {
+		g.deadline = g.clock.Now().Add(g.GuessTimeout)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+// Clock which only moves when the test moves it.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+type TimerTestSuite struct {
+	suite.Suite
+	clock *fakeClock
+}
+
+func (s *TimerTestSuite) SetupSuite() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *TimerTestSuite) SetupTest() {
+	s.clock = &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (s *TimerTestSuite) TestNoTimeout() {
+	game, err := NewGame(4, 2)
+	assert.Nil(s.T(), err)
+	_, ok := game.Deadline()
+	assert.Equal(s.T(), false, ok)
+	assert.Equal(s.T(), false, game.Tick())
+}
+
+func (s *TimerTestSuite) TestTickConsumesRetries() {
+	game, err := NewGame(4, 2, WithClock(s.clock), WithGuessTimeout(10*time.Second),
+		WithRetryPolicy(RetryLenient))
+	assert.Nil(s.T(), err)
+	deadline, ok := game.Deadline()
+	assert.Equal(s.T(), true, ok)
+	assert.Equal(s.T(), s.clock.now.Add(10*time.Second), deadline)
+
+	s.clock.Advance(9 * time.Second)
+	assert.Equal(s.T(), false, game.Tick())
+	assert.Equal(s.T(), 2, game.CurrentRetries)
+
+	s.clock.Advance(1 * time.Second)
+	assert.Equal(s.T(), true, game.Tick())
+	assert.Equal(s.T(), 1, game.CurrentRetries)
+	assert.Equal(s.T(), Running, game.State)
+
+	// Two more deadlines missed at once.
+	s.clock.Advance(20 * time.Second)
+	assert.Equal(s.T(), true, game.Tick())
+	assert.Equal(s.T(), -1, game.CurrentRetries)
+	assert.Equal(s.T(), Lost, game.State)
+	_, ok = game.Deadline()
+	assert.Equal(s.T(), false, ok)
+}
+
+func (s *TimerTestSuite) TestGuessResetsDeadline() {
+	game, err := NewGame(4, 2, WithClock(s.clock), WithGuessTimeout(10*time.Second))
+	assert.Nil(s.T(), err)
+	s.clock.Advance(8 * time.Second)
+	_, err = game.CheckUserInput('z')
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, game.CurrentRetries)
+
+	s.clock.Advance(8 * time.Second)
+	assert.Equal(s.T(), false, game.Tick())
+	assert.Equal(s.T(), 1, game.CurrentRetries)
+}
+
+func (s *TimerTestSuite) TestLateGuess() {
+	game, err := NewGame(4, 0, WithClock(s.clock), WithGuessTimeout(10*time.Second))
+	assert.Nil(s.T(), err)
+	s.clock.Advance(11 * time.Second)
+	// The deadline was missed before the guess was given, which loses the game.
+	_, err = game.CheckUserInput('a')
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), Lost, game.State)
+	assert.Equal(s.T(), 0, len(game.UsedChars))
+}
+
+func (s *TimerTestSuite) TestContextDeadline() {
+	var events []Turn
+	game, err := NewGame(4, 2, WithRetryPolicy(RetryLenient), WithTurnListener(func(t Turn) {
+		events = append(events, t)
+	}))
+	assert.Nil(s.T(), err)
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	// The guess is too late for the turn, which costs a retry.
+	accepted, err := game.CheckUserInputCtx(ctx, 'a')
+	assert.ErrorIs(s.T(), err, ErrTurnTimeout)
+	assert.Equal(s.T(), false, accepted)
+	assert.Equal(s.T(), 1, game.CurrentRetries)
+	assert.Equal(s.T(), 0, len(game.UsedChars))
+
+	_, err = game.CheckUserInputCtx(context.Background(), 'z')
+	assert.Nil(s.T(), err)
+	expected := []Turn{
+		{Kind: TurnTimeout, RetriesLeft: 1},
+		{Kind: TurnGuess, Char: 'z', RetriesLeft: 0},
+	}
+	assert.Equal(s.T(), expected, events)
+	assert.Equal(s.T(), expected, game.Turns)
+
+	// A canceled turn does not change the game.
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = game.CheckUserInputCtx(ctx, 'a')
+	assert.ErrorIs(s.T(), err, context.Canceled)
+	assert.Equal(s.T(), 2, len(game.Turns))
+}
+
+func (s *TimerTestSuite) TestTimeoutsAreTurns() {
+	game, err := NewGame(4, 3, WithClock(s.clock), WithGuessTimeout(10*time.Second),
+		WithRetryPolicy(RetryLenient))
+	assert.Nil(s.T(), err)
+	s.clock.Advance(25 * time.Second)
+	assert.Equal(s.T(), true, game.Tick())
+	assert.Equal(s.T(), []Turn{
+		{Kind: TurnTimeout, RetriesLeft: 2},
+		{Kind: TurnTimeout, RetriesLeft: 1},
+	}, game.Turns)
+
+	// The game already took the retry for the missed deadline, so an expired
+	// context does not cost another one.
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	s.clock.Advance(5 * time.Second)
+	_, err = game.CheckUserInputCtx(ctx, 'a')
+	assert.ErrorIs(s.T(), err, ErrTurnTimeout)
+	assert.Equal(s.T(), 0, game.CurrentRetries)
+	assert.Equal(s.T(), 3, len(game.Turns))
+}
+
+func TestTimerTestSuite(t *testing.T) {
+	suite.Run(t, new(TimerTestSuite))
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// One round of a tournament, a game with a word of its own length.
+type TournamentRound struct {
+	// Length of the word to be guessed in this round.
+	WordLength int
+	// Game of this round. It is nil till the previous rounds are over, and
+	// stays nil if the time budget is used up before the round starts.
+	Game *Game
+	// Score of the round once it is over.
+	Score GameScore
+	// Word of the round once it is over: the word guessed if the round is
+	// won, the word revealed otherwise.
+	Word string
+	// Time spent in the round.
+	Duration time.Duration
+
+	// Time at which the round started.
+	started time.Time
+}
+
+// Manager of a tournament where a player plays rounds of increasing word
+// length, each with the same number of retries. A lost round does not end the
+// tournament: the score of every round is added to the total, and winning
+// rounds in a row raise the multiplier like a winning streak. All the rounds
+// share one budget of time (optionally), and the tournament ends when it is
+// used up. The tournament is won if every round is won.
+type Tournament struct {
+	// Rounds of the tournament in the order they are played.
+	Rounds []*TournamentRound
+	// Total time allowed for the whole tournament. Zero means no time limit.
+	TimeBudget time.Duration
+	// Current state of the tournament.
+	State GameState
+	// Total score of the rounds played.
+	Score int
+
+	// Index of the round being played.
+	current int
+	// Number of rounds won in a row so far.
+	streak int
+	// Time at which the tournament started.
+	started time.Time
+	// Retries of every round.
+	retries int
+	// Rules to score the rounds.
+	scoring ScoreConfig
+	// Options used for the game of every round.
+	opts []GameOption
+}
+
+// Method to create a new tournament.
+// Params:
+// rounds: Number of rounds.
+// startLength: Min length of the word of the first round, the words of the
+// next rounds are longer.
+// retries: Retries of every round.
+// timeBudget: Shared budget of time for all the rounds, zero for no limit.
+// scoring: Rules to score the rounds.
+// opts: Options used for the game of every round.
+//
+// Returns an error if there are not enough word lengths in the dictionary for
+// all the rounds, or the number of retries or the scoring rules are not valid.
+func NewTournament(rounds, startLength, retries int, timeBudget time.Duration,
+	scoring ScoreConfig, opts ...GameOption) (*Tournament, error) {
+	if rounds <= 0 {
+		return nil, errors.New("A tournament needs at least one round")
+	}
+	if !validateNumRetries(retries) {
+		return nil, newGameError(ErrInvalidRetries,
+			"Invalid number of retries %d for the tournament", retries)
+	}
+	if err := scoring.Validate(); err != nil {
+		return nil, err
+	}
+	lengths := increasingLengths(startLength, rounds)
+	if len(lengths) != rounds {
+		return nil, newGameError(ErrInvalidLength, "Not enough word lengths of at "+
+			"least %d in the dictionary for %d rounds", startLength, rounds)
+	}
+	t := &Tournament{
+		TimeBudget: timeBudget,
+		State:      Running,
+		retries:    retries,
+		scoring:    scoring,
+		opts:       opts,
+	}
+	for _, length := range lengths {
+		t.Rounds = append(t.Rounds, &TournamentRound{WordLength: length})
+	}
+	if err := t.startRound(); err != nil {
+		return nil, err
+	}
+	t.started = t.CurrentRound().started
+	return t, nil
+}
+
+// Method to get the round which is being played, or the last round played
+// once the tournament is over.
+func (t *Tournament) CurrentRound() *TournamentRound {
+	return t.Rounds[t.current]
+}
+
+// Method to get the time left in the time budget.
+// Returns false if the tournament has no time limit.
+func (t *Tournament) TimeLeft() (time.Duration, bool) {
+	if t.TimeBudget <= 0 {
+		return 0, false
+	}
+	left := t.TimeBudget - t.now().Sub(t.started)
+	if left < 0 {
+		left = 0
+	}
+	return left, true
+}
+
+// Method to play a character in the current round.
+// When the round is over, it is scored and the next round starts. The
+// tournament is over after the last round, or as soon as the time budget is
+// used up, which loses the current round.
+// Returns true if it is a correct guess, like Game.CheckUserInput.
+func (t *Tournament) CheckUserInput(char rune) (bool, error) {
+	if t.State != Running {
+		return false, newGameError(ErrGameFinished,
+			"Unexpected scenario: input given for a tournament which is not running")
+	}
+	game := t.CurrentRound().Game
+	if left, ok := t.TimeLeft(); ok && left == 0 {
+		game.forfeit()
+		t.endRound()
+		t.finish()
+		return false, newGameError(ErrGameFinished,
+			"Time is up: the tournament ended before the input was given")
+	}
+	accepted, err := game.CheckUserInput(char)
+	if game.State != Running {
+		t.endRound()
+		if t.current == len(t.Rounds)-1 {
+			t.finish()
+		} else {
+			t.current++
+			if startErr := t.startRound(); startErr != nil {
+				return accepted, startErr
+			}
+		}
+	}
+	return accepted, err
+}
+
+// Method to get the number of rounds won.
+func (t *Tournament) Wins() int {
+	wins := 0
+	for _, round := range t.Rounds {
+		if round.Game != nil && round.Game.State == Won {
+			wins++
+		}
+	}
+	return wins
+}
+
+// Method to format the summary of the tournament as a table, with a line per
+// round and the total. The rounds which were not played are shown as skipped.
+func (t *Tournament) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-5s %6s %-20s %-7s %7s %6s %8s\n", "Round", "Length", "Word",
+		"Result", "Guesses", "Score", "Time")
+	var total time.Duration
+	for i, round := range t.Rounds {
+		if round.Game == nil {
+			fmt.Fprintf(&b, "%-5d %6d %-20s %-7s\n", i+1, round.WordLength, "-", "skipped")
+			continue
+		}
+		result := "running"
+		switch round.Game.State {
+		case Won:
+			result = "won"
+		case Lost, Forfeited:
+			result = "lost"
+		}
+		fmt.Fprintf(&b, "%-5d %6d %-20s %-7s %7d %6d %8v\n", i+1, round.WordLength,
+			round.Word, result, len(round.Game.Turns), round.Score.Total,
+			round.Duration.Round(time.Second))
+		total += round.Duration
+	}
+	fmt.Fprintf(&b, "%-5s %6s %-20s %-7s %7s %6d %8v\n", "Total", "", "",
+		fmt.Sprintf("%d/%d", t.Wins(), len(t.Rounds)), "", t.Score, total.Round(time.Second))
+	return b.String()
+}
+
+// Method to get the current time. The clock of the games is used so that a
+// custom clock passed as an option applies to the time budget too.
+func (t *Tournament) now() time.Time {
+	return t.CurrentRound().Game.clock.Now()
+}
+
+// Method to start the game of the current round.
+func (t *Tournament) startRound() error {
+	round := t.CurrentRound()
+	game, err := NewGame(round.WordLength, t.retries, t.opts...)
+	if err != nil {
+		t.finish()
+		return fmt.Errorf("Unable to start round %d: %w", t.current+1, err)
+	}
+	round.Game = game
+	round.started = t.now()
+	return nil
+}
+
+// Method to score the current round once its game is over.
+func (t *Tournament) endRound() {
+	round := t.CurrentRound()
+	round.Duration = t.now().Sub(round.started)
+	if round.Game.State == Won {
+		t.streak++
+		round.Word = string(round.Game.CurrentDisplayedWord)
+	} else {
+		t.streak = 0
+		round.Word = round.Game.RevealWord()
+	}
+	round.Score = t.scoring.Score(round.Game, t.streak)
+	t.Score += round.Score.Total
+}
+
+// Method to end the tournament, which is won if every round is won.
+func (t *Tournament) finish() {
+	if t.Wins() == len(t.Rounds) {
+		t.State = Won
+	} else {
+		t.State = Lost
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+var (
+	tournamentRounds = flag.Int("tournament_rounds", 3,
+		"Number of rounds of the tournament subcommand. The word of every round "+
+			"is longer than the previous one.")
+	tournamentStartLength = flag.Int("tournament_start_length", 0,
+		"Min length of the word of the first round of a tournament, the shortest "+
+			"length of the dictionary if zero.")
+	tournamentRetries = flag.Int("tournament_retries", 5,
+		"Number of retries of every round of a tournament.")
+	tournamentTimeBudget = flag.Duration("tournament_time_budget", 10*time.Minute,
+		"Total time allowed for all the rounds of a tournament, e.g. 5m. No limit "+
+			"if zero.")
+)
+
+// Driver method for the "tournament" subcommand, which plays the rounds of a
+// tournament in the terminal and prints their summary.
+func StartTournament() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	var err error
+	if definitions, err = definitionsFromFlags(); err != nil {
+		return err
+	}
+	revealPolicy, err := revealPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	strategy, err := strategyFromFlags()
+	if err != nil {
+		return err
+	}
+	scoring, err := scoreConfigFromFlags()
+	if err != nil {
+		return err
+	}
+	opts := []GameOption{WithRevealPolicy(revealPolicy), WithStrategy(strategy)}
+	tournament, err := NewTournament(*tournamentRounds, *tournamentStartLength,
+		*tournamentRetries, *tournamentTimeBudget, scoring, opts...)
+	if err != nil {
+		return configError("unable to start the tournament: %w", err)
+	}
+	fmt.Println("Tournament of", len(tournament.Rounds), "rounds with",
+		*tournamentRetries, "retries each")
+	for tournament.State == Running {
+		round := tournament.CurrentRound()
+		game := round.Game
+		if len(game.Turns) == 0 {
+			fmt.Println("Round", tournament.current+1, "of", len(tournament.Rounds),
+				": word of length", round.WordLength)
+		}
+		fmt.Println(displayFormat.Format(game.CurrentDisplayedWord))
+		prompt := fmt.Sprint("Enter a character (previous characters: ",
+			string(game.UsedChars), ", remaining tries ", game.CurrentRetries)
+		if left, ok := tournament.TimeLeft(); ok {
+			prompt += fmt.Sprint(", time left ", left.Round(time.Second))
+		}
+		fmt.Println(prompt, "): ")
+		char, err := readChar()
+		if err != nil {
+			return err
+		}
+		accepted, err := tournament.CheckUserInput(char)
+		if err != nil {
+			fmt.Println(err)
+		} else if game.State == Running {
+			if accepted {
+				fmt.Println(colors.correct(tr("right_char")))
+			} else {
+				fmt.Println(colors.wrong(fmt.Sprint("Sorry its a wrong input. Remaining tries: ",
+					game.CurrentRetries)))
+			}
+		}
+		if game.State == Running {
+			continue
+		}
+		if game.State == Won {
+			fmt.Println(colors.correct(tr("won")))
+		} else {
+			fmt.Println(colors.wrong(tr("lost", round.Word)))
+		}
+		printDefinition(round.Word)
+		fmt.Println("Round score:", round.Score.Total, "points, total:", tournament.Score)
+	}
+	fmt.Print(tournament.Summary())
+	if tournament.State == Won {
+		fmt.Println("You won every round of the tournament! Congratulations!!!")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+)
+
+type TournamentTestSuite struct {
+	suite.Suite
+}
+
+func (s *TournamentTestSuite) SetupTest() {
+	InitGame([]string{"cat", "last", "fast", "bets", "code", "sunny"})
+}
+
+func (s *TournamentTestSuite) TearDownTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to play characters in a tournament, ignoring the errors.
+func (s *TournamentTestSuite) play(t *Tournament, chars string) {
+	for _, char := range chars {
+		t.CheckUserInput(char)
+	}
+}
+
+func (s *TournamentTestSuite) TestIncreasingLengths() {
+	t, err := NewTournament(3, 0, 5, 0, DefaultScoreConfig)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 3, t.Rounds[0].WordLength)
+	assert.Equal(s.T(), 4, t.Rounds[1].WordLength)
+	assert.Equal(s.T(), 5, t.Rounds[2].WordLength)
+	assert.NotNil(s.T(), t.Rounds[0].Game)
+	assert.Nil(s.T(), t.Rounds[1].Game)
+
+	_, err = NewTournament(3, 4, 5, 0, DefaultScoreConfig)
+	assert.NotNil(s.T(), err)
+	_, err = NewTournament(0, 3, 5, 0, DefaultScoreConfig)
+	assert.NotNil(s.T(), err)
+	_, err = NewTournament(1, 3, -1, 0, DefaultScoreConfig)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *TournamentTestSuite) TestCumulativeScore() {
+	t, err := NewTournament(2, 3, 2, 0, DefaultScoreConfig)
+	assert.Nil(s.T(), err)
+	// "cat" is the only word of length 3.
+	s.play(t, "cat")
+	first := t.Rounds[0]
+	assert.Equal(s.T(), Won, first.Game.State)
+	assert.Equal(s.T(), "cat", first.Word)
+	assert.Equal(s.T(), 3*10+2*20, first.Score.Total)
+	assert.Equal(s.T(), first.Score.Total, t.Score)
+	assert.Equal(s.T(), Running, t.State)
+	assert.Equal(s.T(), 2, t.CurrentRound().Game.CurrentRetries)
+
+	// A lost round ends the tournament as it is the last one.
+	s.play(t, "zyx")
+	assert.Equal(s.T(), Lost, t.Rounds[1].Game.State)
+	assert.NotEmpty(s.T(), t.Rounds[1].Word)
+	assert.Equal(s.T(), Lost, t.State)
+	assert.Equal(s.T(), first.Score.Total+t.Rounds[1].Score.Total, t.Score)
+	assert.Equal(s.T(), 1, t.Wins())
+	_, err = t.CheckUserInput('a')
+	assert.NotNil(s.T(), err)
+}
+
+func (s *TournamentTestSuite) TestLostRoundGoesOn() {
+	t, err := NewTournament(2, 3, 0, 0, DefaultScoreConfig)
+	assert.Nil(s.T(), err)
+	s.play(t, "z")
+	assert.Equal(s.T(), Lost, t.Rounds[0].Game.State)
+	assert.Equal(s.T(), "cat", t.Rounds[0].Word)
+	assert.Equal(s.T(), Running, t.State)
+	assert.Equal(s.T(), 4, t.CurrentRound().WordLength)
+}
+
+func (s *TournamentTestSuite) TestStreak() {
+	InitGame([]string{"cat", "dogs"})
+	t, err := NewTournament(2, 3, 0, 0, DefaultScoreConfig)
+	assert.Nil(s.T(), err)
+	s.play(t, "catdogs")
+	assert.Equal(s.T(), 1.0, t.Rounds[0].Score.Multiplier)
+	assert.Equal(s.T(), 1.5, t.Rounds[1].Score.Multiplier)
+	assert.Equal(s.T(), 30+60, t.Score)
+	assert.Equal(s.T(), Won, t.State)
+}
+
+func (s *TournamentTestSuite) TestTimeBudget() {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	t, err := NewTournament(3, 3, 5, time.Minute, DefaultScoreConfig, WithClock(clock))
+	assert.Nil(s.T(), err)
+	left, ok := t.TimeLeft()
+	assert.Equal(s.T(), true, ok)
+	assert.Equal(s.T(), time.Minute, left)
+
+	clock.Advance(20 * time.Second)
+	s.play(t, "cat")
+	assert.Equal(s.T(), 20*time.Second, t.Rounds[0].Duration)
+	left, _ = t.TimeLeft()
+	assert.Equal(s.T(), 40*time.Second, left)
+
+	clock.Advance(time.Minute)
+	_, err = t.CheckUserInput('a')
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), Lost, t.State)
+	assert.Equal(s.T(), Forfeited, t.Rounds[1].Game.State)
+	assert.Nil(s.T(), t.Rounds[2].Game)
+
+	summary := t.Summary()
+	assert.Contains(s.T(), summary, "cat")
+	assert.Contains(s.T(), summary, "skipped")
+	assert.Contains(s.T(), summary, "1/3")
+	assert.Equal(s.T(), 5, strings.Count(summary, "\n"))
+}
+
+func TestTournamentTestSuite(t *testing.T) {
+	suite.Run(t, new(TournamentTestSuite))
+}
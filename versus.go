@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Outcome of a race between the player and a solver bot.
+type VersusOutcome int
+
+const (
+	// One of the boards is still being played.
+	VersusRunning VersusOutcome = iota
+	// The player solved the word with fewer wrong guesses than the bot, or the
+	// bot did not solve it.
+	VersusPlayerWon
+	// The bot solved the word with fewer wrong guesses than the player, or the
+	// player did not solve it.
+	VersusSolverWon
+	// Both solved the word with as many wrong guesses, or neither solved it.
+	VersusDraw
+)
+
+func (o VersusOutcome) String() string {
+	switch o {
+	case VersusPlayerWon:
+		return "player won"
+	case VersusSolverWon:
+		return "solver won"
+	case VersusDraw:
+		return "draw"
+	}
+	return "running"
+}
+
+// Race between the player and a solver bot, who guess the same word on
+// parallel boards with the same retries. The word is picked when the race
+// starts and kept for the whole game on both boards, so the engine can not
+// treat the two differently. The bot makes one guess after every guess of the
+// player, and plays its board to the end once the player's board is over.
+// Whoever solves the word with fewer wrong guesses wins.
+type Versus struct {
+	// Board of the player.
+	Player *Game
+	// Board of the bot.
+	Solver *Game
+	// Bot playing the solver board.
+	Bot Guesser
+	// Time allowed to the bot for every move. A bot which does not move in
+	// time forfeits its board.
+	MoveTimeout time.Duration
+	// Last character guessed by the bot, zero before its first guess.
+	LastSolverGuess rune
+}
+
+// Method to start a race.
+// Params:
+// expectedLen: Length of the word.
+// retries: Retries of every board.
+// bot: Bot playing against the player.
+// seed: Seed picking the word of the race.
+// opts: Options of both boards.
+//
+// Returns an error if the length or the number of retries is not valid.
+func NewVersus(expectedLen, retries int, bot Guesser, seed int64,
+	opts ...GameOption) (*Versus, error) {
+	// The same seed picks the same word on both boards.
+	opts = append(opts, withSecretWord(seed))
+	player, err := NewGame(expectedLen, retries, opts...)
+	if err != nil {
+		return nil, err
+	}
+	solver, err := NewGame(expectedLen, retries, opts...)
+	if err != nil {
+		player.forfeit()
+		return nil, err
+	}
+	return &Versus{Player: player, Solver: solver, Bot: bot, MoveTimeout: time.Second}, nil
+}
+
+// Method to play a character on the board of the player, followed by the move
+// of the bot. A guess which is not valid, e.g. a character already used, does
+// not give a move to the bot.
+// Returns true if it is a correct guess, like Game.CheckUserInput.
+func (v *Versus) CheckUserInput(char rune) (bool, error) {
+	accepted, err := v.Player.CheckUserInput(char)
+	if err != nil {
+		return accepted, err
+	}
+	v.solverMove()
+	for v.Player.State != Running && v.Solver.State == Running {
+		v.solverMove()
+	}
+	return accepted, nil
+}
+
+// Method to make the bot guess on its board, if it is still running. A bot
+// which fails or makes an invalid move forfeits its board.
+func (v *Versus) solverMove() {
+	if v.Solver.State != Running {
+		return
+	}
+	// The move ends with the turn of the board too, if its guesses are timed.
+	ctx, cancel := context.WithTimeout(context.Background(), v.MoveTimeout)
+	defer cancel()
+	ctx, cancelTurn := v.Solver.TurnContext(ctx)
+	defer cancelTurn()
+	char, err := guessSafely(ctx, v.Bot, botState(v.Solver))
+	if err == nil {
+		_, err = v.Solver.CheckUserInputCtx(ctx, char)
+	}
+	if err != nil {
+		defaultLogger.Errorf("The solver forfeits the race, error %v", err)
+		v.Solver.forfeit()
+		return
+	}
+	v.LastSolverGuess = char
+}
+
+// Method to count the wrong guesses made on a board, including the timeouts.
+func wrongGuesses(game *Game) int {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	wrong := 0
+	for _, turn := range game.Turns {
+		if !turn.Accepted {
+			wrong++
+		}
+	}
+	return wrong
+}
+
+// Method to get the outcome of the race, VersusRunning till both boards are
+// over.
+func (v *Versus) Outcome() VersusOutcome {
+	if v.Player.State == Running || v.Solver.State == Running {
+		return VersusRunning
+	}
+	playerSolved, solverSolved := v.Player.State == Won, v.Solver.State == Won
+	switch {
+	case playerSolved && !solverSolved:
+		return VersusPlayerWon
+	case solverSolved && !playerSolved:
+		return VersusSolverWon
+	case !playerSolved:
+		return VersusDraw
+	}
+	playerWrong, solverWrong := wrongGuesses(v.Player), wrongGuesses(v.Solver)
+	switch {
+	case playerWrong < solverWrong:
+		return VersusPlayerWon
+	case solverWrong < playerWrong:
+		return VersusSolverWon
+	}
+	return VersusDraw
+}
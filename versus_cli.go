@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+var (
+	versusBot = flag.String("versus_bot", "entropy",
+		"Bot racing the player in the versus subcommand, like the bots of "+
+			"--arena_bots.")
+)
+
+// Method to show the boards of a race side by side, with the wrong guesses of
+// both players.
+func formatVersus(v *Versus) string {
+	board := func(name string, game *Game, solved string) string {
+		return fmt.Sprintf("%s: %s%s (%d wrong)", name,
+			displayFormat.Format(game.CurrentDisplayedWord), solved, wrongGuesses(game))
+	}
+	mark := func(game *Game) string {
+		if game.State == Won {
+			return " ✓"
+		}
+		return ""
+	}
+	left := board("You", v.Player, mark(v.Player))
+	// The board of the bot starts at the same column till the end of the
+	// race, whether the player solved the word or not.
+	width := len([]rune(board("You", v.Player, " ✓")))
+	left += strings.Repeat(" ", width-len([]rune(left)))
+	return left + boardSeparator + board("Solver", v.Solver, mark(v.Solver))
+}
+
+// Driver method for the "versus" subcommand, where the player races the bot
+// of --versus_bot to guess the same word.
+func StartVersus() error {
+	if err := InitGame(nil); err != nil {
+		return err
+	}
+	revealPolicy, err := revealPolicyFromFlags()
+	if err != nil {
+		return err
+	}
+	bot, err := newGuesser(*versusBot)
+	if err != nil {
+		return configError("unable to load bot %s: %w", *versusBot, err)
+	}
+	defer bot.Close()
+	var race *Versus
+	for race == nil {
+		fmt.Println("Race against the", *versusBot, "solver to guess the same word")
+		expectedLen, retries, ok, err := readGameConfig()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		race, err = NewVersus(expectedLen, retries, bot, randInt63(),
+			WithRevealPolicy(revealPolicy))
+		if err != nil {
+			fmt.Println(err, ". Please try again!")
+		}
+	}
+	for race.Player.State == Running {
+		fmt.Println(formatVersus(race))
+		fmt.Println("Enter a character (previous characters: ",
+			string(race.Player.UsedChars), ", remaining tries",
+			race.Player.CurrentRetries, "): ")
+		char, err := readChar()
+		if err != nil {
+			return err
+		}
+		accepted, err := race.CheckUserInput(char)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if accepted {
+			fmt.Println(colors.correct(tr("right_char")))
+		} else {
+			fmt.Println(colors.wrong(fmt.Sprint("Sorry its a wrong input. Remaining tries: ",
+				race.Player.CurrentRetries)))
+		}
+		if race.LastSolverGuess != 0 && race.Player.State == Running {
+			fmt.Println("The solver guessed", string(race.LastSolverGuess))
+		}
+	}
+	fmt.Println(formatVersus(race))
+	word := string(race.Solver.CurrentDisplayedWord)
+	if race.Player.State == Won {
+		word = string(race.Player.CurrentDisplayedWord)
+	} else if race.Solver.State != Won {
+		word = race.Player.RevealWord()
+	}
+	fmt.Println("The word was:", word)
+	printDefinition(word)
+	switch race.Outcome() {
+	case VersusPlayerWon:
+		fmt.Println(colors.correct("You beat the solver! Congratulations!!!"))
+	case VersusSolverWon:
+		fmt.Println(colors.wrong("The solver wins this race."))
+	default:
+		fmt.Println("It's a draw.")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+)
+
+type VersusTestSuite struct {
+	suite.Suite
+}
+
+func (s *VersusTestSuite) SetupTest() {
+	InitGame([]string{"code"})
+}
+
+func (s *VersusTestSuite) TearDownTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+// Method to play characters on the board of the player.
+func (s *VersusTestSuite) play(v *Versus, chars string) {
+	for _, char := range chars {
+		_, err := v.CheckUserInput(char)
+		assert.Nil(s.T(), err)
+	}
+}
+
+func (s *VersusTestSuite) TestSameWord() {
+	InitGame([]string{"last", "fast", "bets", "code", "cats", "dogs"})
+	for seed := int64(0); seed < 10; seed++ {
+		v, err := NewVersus(4, 10, scriptedBot{}, seed)
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), v.Player.RevealWord(), v.Solver.RevealWord())
+	}
+}
+
+func (s *VersusTestSuite) TestPlayerWins() {
+	v, err := NewVersus(4, 3, scriptedBot{order: "zcode"}, 1)
+	assert.Nil(s.T(), err)
+	s.play(v, "cod")
+	assert.Equal(s.T(), 'o', v.LastSolverGuess)
+	assert.Equal(s.T(), VersusRunning, v.Outcome())
+	// The bot plays its board to the end once the player solved the word.
+	s.play(v, "e")
+	assert.Equal(s.T(), Won, v.Solver.State)
+	assert.Equal(s.T(), VersusPlayerWon, v.Outcome())
+}
+
+func (s *VersusTestSuite) TestSolverWins() {
+	v, err := NewVersus(4, 3, scriptedBot{order: "code"}, 1)
+	assert.Nil(s.T(), err)
+	s.play(v, "zyx")
+	assert.Equal(s.T(), Won, v.Solver.State)
+	assert.Equal(s.T(), Lost, v.Player.State)
+	assert.Equal(s.T(), VersusSolverWon, v.Outcome())
+	assert.Contains(s.T(), formatVersus(v), "Solver: code ✓ (0 wrong)")
+}
+
+func (s *VersusTestSuite) TestDraw() {
+	v, err := NewVersus(4, 3, scriptedBot{order: "zcode"}, 1)
+	assert.Nil(s.T(), err)
+	s.play(v, "ycode")
+	assert.Equal(s.T(), VersusDraw, v.Outcome())
+}
+
+func (s *VersusTestSuite) TestFaultyBot() {
+	v, err := NewVersus(4, 3, slowBot{}, 1)
+	assert.Nil(s.T(), err)
+	v.MoveTimeout = time.Millisecond
+	s.play(v, "c")
+	assert.Equal(s.T(), Forfeited, v.Solver.State)
+	s.play(v, "ode")
+	assert.Equal(s.T(), VersusPlayerWon, v.Outcome())
+}
+
+func (s *VersusTestSuite) TestInvalidGuess() {
+	v, err := NewVersus(4, 3, scriptedBot{order: "co"}, 1)
+	assert.Nil(s.T(), err)
+	s.play(v, "c")
+	// A guess of the player which is not valid gives no move to the bot.
+	_, err = v.CheckUserInput('c')
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), rawPattern("c___"), v.Solver.CurrentDisplayedWord)
+	assert.Equal(s.T(), 2, strings.Count(formatVersus(v), "c___"))
+}
+
+func TestVersusTestSuite(t *testing.T) {
+	suite.Run(t, new(VersusTestSuite))
+}
@@ -0,0 +1,57 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"encoding/json"
+	"github.com/hackeracc/WordGuess/api"
+	"syscall/js"
+)
+
+// Entry point of the WebAssembly build, which plays the games in the browser
+// without a server: it exposes an offlineEngine to JavaScript as the global
+// "wordGuessEngine" and then waits forever, see web/wordguess.js. The functions
+// take and return strings, the JSON bodies of the server API; an error is
+// returned as the JSON body of an api.ErrorResponse. The engine is not exposed
+// if the dictionary can not be loaded, the error is logged to the console.
+func main() {
+	if err := InitGame(nil); err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return
+	}
+	engine := newOfflineEngine()
+	js.Global().Set("wordGuessEngine", js.ValueOf(map[string]interface{}{
+		"loadDictionary": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return engine.loadDictionary(args[0].String())
+		}),
+		"createGame": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return jsResponse(engine.createGame([]byte(args[0].String())))
+		}),
+		"getGame": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return jsResponse(engine.getGame(args[0].String()))
+		}),
+		"guess": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return jsResponse(engine.guess(args[0].String(), []byte(args[1].String())))
+		}),
+		"hint": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return jsResponse(engine.hint(args[0].String()))
+		}),
+		"explain": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return jsResponse(engine.explain(args[0].String()))
+		}),
+		"deleteGame": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			engine.deleteGame(args[0].String())
+			return nil
+		}),
+	}))
+	select {}
+}
+
+// Method to convert the response of the engine to the string returned to
+// JavaScript.
+func jsResponse(body []byte, apiErr *api.Error) interface{} {
+	if apiErr != nil {
+		body, _ = json.Marshal(api.ErrorResponse{Error: apiErr})
+	}
+	return string(body)
+}
@@ -0,0 +1,200 @@
+// Web frontend of the server, served on "/" (see webui.go). It creates games
+// with "POST /games" and then follows them over "GET /games/<id>/ws": guesses
+// are sent as guess messages, and the page is redrawn from every state
+// message, so guesses made by other clients (e.g. over REST) show up too.
+// Practice games also get a preview message after every state message, shown
+// as a heatmap over the letters.
+
+const LETTERS = "abcdefghijklmnopqrstuvwxyz";
+// Time before reconnecting a WebSocket connection which was closed while the
+// game was running.
+const RECONNECT_DELAY_MS = 2000;
+
+const page = {
+  form: document.getElementById("new-game"),
+  game: document.getElementById("game"),
+  parts: document.querySelectorAll("#gallows .part"),
+  word: document.getElementById("word"),
+  status: document.getElementById("status"),
+  letters: document.getElementById("letters"),
+  error: document.getElementById("error"),
+};
+
+// Game being played, as last received from the server, and its connection.
+let game = null;
+let socket = null;
+// Last preview of the next guess received for a practice game.
+let preview = null;
+
+function showError(error) {
+  page.error.textContent = error ? error.message : "";
+}
+
+// Method to send a request to the REST API. Throws the "error" of an error
+// response.
+async function request(method, path, body) {
+  const response = await fetch(path, {
+    method,
+    headers: {"Content-Type": "application/json"},
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  const result = await response.json();
+  if (!response.ok) {
+    throw result.error;
+  }
+  return result;
+}
+
+function render() {
+  page.game.hidden = false;
+  page.word.textContent = game.masked_word;
+  // The parts of the body are shown in proportion of the retries used, so
+  // that the last one is drawn when the game is lost whatever the retries.
+  const used = game.allowed_retries - game.retries_left;
+  const shown = game.state === "lost" || game.state === "forfeited" ?
+    page.parts.length :
+    Math.floor(used * page.parts.length / Math.max(game.allowed_retries, 1));
+  page.parts.forEach((part, i) => part.classList.toggle("shown", i < shown));
+  switch (game.state) {
+    case "won":
+      page.status.textContent = "You won!";
+      break;
+    case "lost":
+      page.status.textContent = "You lost.";
+      break;
+    case "forfeited":
+      page.status.textContent = "The game expired.";
+      break;
+    default:
+      page.status.textContent = `Retries left: ${game.retries_left}`;
+  }
+  const masked = game.masked_word.toLowerCase();
+  for (const button of page.letters.children) {
+    const char = button.dataset.char;
+    const used = game.used_chars.toLowerCase().includes(char);
+    button.disabled = used || game.state !== "running";
+    button.classList.toggle("accepted", used && masked.includes(char));
+    button.classList.toggle("rejected", used && !masked.includes(char));
+  }
+  renderHeatmap();
+}
+
+// Method to shade the letters not guessed yet by the fraction of the remaining
+// words containing them, and to dim the ones which would be accepted right
+// now. The preview is only shown while it matches the game, since the state
+// and preview messages are sent apart.
+function renderHeatmap() {
+  const current = preview && game.practice && game.state === "running" &&
+    preview.game_id === game.id &&
+    preview.guesses === [...game.used_chars].length;
+  const letters = new Map(current ?
+    preview.letters.map((letter) => [letter.char.toLowerCase(), letter]) : []);
+  for (const button of page.letters.children) {
+    const letter = letters.get(button.dataset.char);
+    const shaded = current && !button.disabled;
+    button.classList.toggle("heat", shaded);
+    button.classList.toggle("would-accept", shaded && !!letter && letter.would_accept);
+    if (shaded) {
+      button.style.setProperty("--probability", letter ? letter.probability : 0);
+    } else {
+      button.style.removeProperty("--probability");
+    }
+  }
+}
+
+function connect(id) {
+  const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+  const ws = new WebSocket(`${scheme}//${location.host}/games/${id}/ws`);
+  socket = ws;
+  ws.onmessage = (event) => {
+    const message = JSON.parse(event.data);
+    if (message.type === "error") {
+      showError(message.error);
+      return;
+    }
+    if (message.type === "preview") {
+      if (message.preview.game_id === id) {
+        preview = message.preview;
+        render();
+      }
+      return;
+    }
+    if (message.game && message.game.id === id) {
+      game = message.game;
+      render();
+    }
+  };
+  ws.onclose = () => {
+    if (socket === ws && game && game.id === id && game.state === "running") {
+      setTimeout(() => socket === ws && connect(id), RECONNECT_DELAY_MS);
+    }
+  };
+}
+
+async function guess(char) {
+  if (!game || game.state !== "running") {
+    return;
+  }
+  showError(null);
+  if (socket && socket.readyState === WebSocket.OPEN) {
+    socket.send(JSON.stringify({type: "guess", char}));
+    return;
+  }
+  // Without a connection, e.g. while reconnecting, guess over REST.
+  try {
+    const response = await request("POST", `/games/${game.id}/guesses`, {char});
+    game = response.game;
+    if (game.practice) {
+      preview = await request("GET", `/games/${game.id}/preview`);
+    }
+    render();
+  } catch (error) {
+    showError(error);
+  }
+}
+
+page.form.addEventListener("submit", async (event) => {
+  event.preventDefault();
+  showError(null);
+  const fields = new FormData(page.form);
+  const body = {
+    word_length: Number(fields.get("word_length")),
+    retries: Number(fields.get("retries")),
+  };
+  if (fields.get("player")) {
+    body.player = fields.get("player");
+  }
+  if (fields.get("practice")) {
+    body.practice = true;
+  }
+  try {
+    game = await request("POST", "/games", body);
+  } catch (error) {
+    showError(error);
+    return;
+  }
+  if (socket) {
+    socket.close();
+  }
+  preview = null;
+  render();
+  connect(game.id);
+});
+
+for (const char of LETTERS) {
+  const button = document.createElement("button");
+  button.type = "button";
+  button.textContent = char;
+  button.dataset.char = char;
+  button.addEventListener("click", () => guess(char));
+  page.letters.appendChild(button);
+}
+
+// Letters of other alphabets can be typed, the server checks them.
+document.addEventListener("keydown", (event) => {
+  if (event.target instanceof HTMLInputElement || event.ctrlKey ||
+      event.metaKey || event.altKey || [...event.key].length !== 1) {
+    return;
+  }
+  guess(event.key.toLowerCase());
+});
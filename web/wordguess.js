@@ -0,0 +1,50 @@
+// Client of the WordGuess engine compiled to WebAssembly, which plays the games
+// in the browser without a server. Build the engine with:
+//
+//   GOOS=js GOARCH=wasm go build -o web/wordguess.wasm .
+//   cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" web/
+//
+// and load wasm_exec.js before this file. The methods take and return the same
+// objects as the REST API of the server, and throw the "error" of an error
+// response, e.g. {code: "character_used", message: "..."}.
+
+class WordGuessError extends Error {
+  constructor(error) {
+    super(error.message);
+    this.code = error.code;
+    this.details = error.details;
+  }
+}
+
+// Method to start the engine. The returned promise resolves once the engine is
+// ready to play.
+async function loadWordGuess(wasmURL = "wordguess.wasm") {
+  const go = new Go();
+  const result = await WebAssembly.instantiateStreaming(fetch(wasmURL), go.importObject);
+  // The engine never exits, run resolves only if it crashes.
+  go.run(result.instance);
+  const engine = globalThis.wordGuessEngine;
+
+  const parse = (body) => {
+    const response = JSON.parse(body);
+    if (response.error) {
+      throw new WordGuessError(response.error);
+    }
+    return response;
+  };
+
+  return {
+    // Replace the dictionary of the new games, one word per line. Returns the
+    // number of words.
+    loadDictionary: (words) => engine.loadDictionary(words),
+    // Same body as "POST /games", plus "opponent" and "opponent_win_rate".
+    createGame: (request) => parse(engine.createGame(JSON.stringify(request))),
+    getGame: (id) => parse(engine.getGame(id)),
+    // Returns {accepted, game}, like "POST /games/<id>/guesses".
+    guess: (id, char) => parse(engine.guess(id, JSON.stringify({char}))),
+    hint: (id) => parse(engine.hint(id)),
+    // Returns the explanation of the last decision, like the "why" command.
+    explain: (id) => parse(engine.explain(id)),
+    deleteGame: (id) => engine.deleteGame(id),
+  };
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"io/fs"
+	"net/http"
+)
+
+var (
+	webUI = flag.Bool("web_ui", true,
+		"Serve the web frontend of the game on / in server mode (see --http_addr).")
+)
+
+// Static files of the web frontend: a single page playing the games of the
+// server through the REST API, and following them over WebSocket.
+//
+//go:embed web/ui
+var webUIFiles embed.FS
+
+// Method to build the handler serving the web frontend: the page on / and its
+// assets under /ui/.
+func webUIHandler() (index http.Handler, assets http.Handler) {
+	files, err := fs.Sub(webUIFiles, "web/ui")
+	if err != nil {
+		// The path is checked by go:embed at build time.
+		panic(err)
+	}
+	index = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(w, r, files, "index.html")
+	})
+	return index, http.StripPrefix("/ui/", http.FileServerFS(files))
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	_ "embed"
+	"flag"
+	"os"
+	"strings"
+)
+
+var (
+	blocklistFile = flag.String("blocklist", "",
+		"Absolute path of a file of words never used in the games, one per line. "+
+			"A phrase is excluded if any of its words is blocked.")
+	allowlistFile = flag.String("allowlist", "",
+		"Absolute path of a file of words, one per line. Only the words of the "+
+			"dictionary which are also in this file are used in the games.")
+	familyFriendly = flag.Bool("family_friendly", false,
+		"Exclude the offensive words of a built-in list from the games, along "+
+			"with the words of --blocklist. Unlike --family_safe, the words are "+
+			"removed when the dictionary is loaded, so they are never picked.")
+)
+
+// Offensive words excluded by --family_friendly.
+//
+//go:embed family_blocklist.txt
+var familyBlocklist string
+
+// Filter of the words of a dictionary, applied when it is loaded, e.g. to
+// deploy the game in classrooms. The words are compared once their case is
+// folded like the words of the dictionary.
+type WordFilter struct {
+	// Words never used.
+	blocked map[string]bool
+	// Only words used, nil if all the words which are not blocked are used.
+	allowed map[string]bool
+}
+
+// Method to add the words of a list, one per line, to a set. Empty lines and
+// lines starting with # are skipped.
+func addWordList(set map[string]bool, list string, alphabet Alphabet) {
+	for _, line := range strings.Split(list, "\n") {
+		word := strings.TrimSpace(line)
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		set[alphabet.FoldWord(word)] = true
+	}
+}
+
+// Method to read a list of words from a file, see addWordList.
+func readWordList(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	var b strings.Builder
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		b.WriteString(scanner.Text())
+		b.WriteByte('\n')
+	}
+	return b.String(), scanner.Err()
+}
+
+// Method to build the filter of a dictionary source, nil if it does not filter
+// any word.
+// Returns an error if the lists can not be read.
+func newWordFilter(source dictionarySource, alphabet Alphabet) (*WordFilter, error) {
+	if source.blocklist == "" && source.allowlist == "" && !source.familyFriendly {
+		return nil, nil
+	}
+	f := &WordFilter{blocked: make(map[string]bool)}
+	if source.familyFriendly {
+		addWordList(f.blocked, familyBlocklist, alphabet)
+	}
+	if source.blocklist != "" {
+		list, err := readWordList(source.blocklist)
+		if err != nil {
+			return nil, err
+		}
+		addWordList(f.blocked, list, alphabet)
+	}
+	if source.allowlist != "" {
+		list, err := readWordList(source.allowlist)
+		if err != nil {
+			return nil, err
+		}
+		f.allowed = make(map[string]bool)
+		addWordList(f.allowed, list, alphabet)
+	}
+	return f, nil
+}
+
+// Method to check if a word of the dictionary can be used in the games.
+func (f *WordFilter) Allowed(word string) bool {
+	if f.allowed != nil && !f.allowed[word] {
+		return false
+	}
+	if f.blocked[word] {
+		return false
+	}
+	for _, part := range strings.Split(word, string(phraseSeparator)) {
+		if f.blocked[part] {
+			return false
+		}
+	}
+	return true
+}
+
+// Method to remove the words the filter does not allow from the dictionary,
+// and count them in its report. Must only be called while the dictionary is
+// built.
+func (d *Dictionary) filterWords(f *WordFilter) {
+	if f == nil {
+		return
+	}
+	d.report.Filtered += d.retainWords(f.Allowed)
+}
+
+// Method to remove the words for which keep returns false, and get the number
+// of words removed. Must only be called while the dictionary is built.
+func (d *Dictionary) retainWords(keep func(word string) bool) int {
+	removed := 0
+	retain := func(words []string) []string {
+		var kept []string
+		for _, word := range words {
+			if keep(word) {
+				kept = append(kept, word)
+			}
+		}
+		removed += len(words) - len(kept)
+		return kept
+	}
+	for length, words := range d.words {
+		kept := retain(words)
+		switch {
+		case len(kept) == len(words):
+		case len(kept) == 0:
+			delete(d.words, length)
+			delete(d.index, length)
+			delete(d.anagrams, length)
+		default:
+			d.index[length] = newWordIndex(length, kept)
+			d.words[length] = d.index[length].words
+			d.anagrams[length] = newAnagramIndex(d.words[length])
+		}
+	}
+	for length, packed := range d.packed {
+		words := unpackWords(packed, length)
+		kept := retain(words)
+		switch {
+		case len(kept) == len(words):
+		case len(kept) == 0:
+			delete(d.packed, length)
+			delete(d.anagrams, length)
+		default:
+			// The kept words are still sorted and made of the letters a-z.
+			d.packed[length], _ = packWords(kept)
+			d.anagrams[length] = newAnagramIndex(kept)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+type WordFilterTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (s *WordFilterTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+}
+
+// Method to write a file of the test directory and get its path.
+func (s *WordFilterTestSuite) write(name, content string) string {
+	path := filepath.Join(s.dir, name)
+	assert.Nil(s.T(), ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// Method to get the source of a dictionary of the given words, with the
+// default flags and a filter.
+func (s *WordFilterTestSuite) source(words string, filter dictionarySource) dictionarySource {
+	source := dictionarySourceFromFlags()
+	source.index = ""
+	source.file = s.write("words.txt", words)
+	source.compact = filter.compact
+	source.blocklist = filter.blocklist
+	source.allowlist = filter.allowlist
+	source.familyFriendly = filter.familyFriendly
+	return source
+}
+
+// Method to load a dictionary of the given words with a filter.
+func (s *WordFilterTestSuite) load(words string, filter dictionarySource) *Dictionary {
+	d, err := loadDictionary(s.source(words, filter))
+	assert.Nil(s.T(), err)
+	return d
+}
+
+func (s *WordFilterTestSuite) TestBlocklist() {
+	blocklist := s.write("blocked.txt", "# Comment\nFast\n\nbets\n")
+	d := s.load("last\nfast\nbets\ncode\nhorse", dictionarySource{blocklist: blocklist})
+	assert.Equal(s.T(), []string{"code", "last"}, d.Words(4))
+	assert.Equal(s.T(), []string{"code", "last"}, d.Match("____", nil))
+	assert.Equal(s.T(), 2, d.Report().Filtered)
+
+	// A length without words left can not be played.
+	d = s.load("last\nhorse", dictionarySource{blocklist: s.write("horse.txt", "horse")})
+	assert.False(s.T(), d.HasLength(5))
+}
+
+func (s *WordFilterTestSuite) TestAllowlist() {
+	allowlist := s.write("allowed.txt", "code\nlast\nzebra")
+	d := s.load("last\nfast\nbets\ncode", dictionarySource{allowlist: allowlist})
+	assert.Equal(s.T(), []string{"code", "last"}, d.Words(4))
+
+	// The blocklist still applies to the allowed words.
+	d = s.load("last\nfast\nbets\ncode", dictionarySource{allowlist: allowlist,
+		blocklist: s.write("blocked.txt", "code")})
+	assert.Equal(s.T(), []string{"last"}, d.Words(4))
+}
+
+func (s *WordFilterTestSuite) TestFamilyFriendly() {
+	d := s.load("shit\nship\ndamn\ndame", dictionarySource{familyFriendly: true})
+	assert.Equal(s.T(), []string{"dame", "ship"}, d.Words(4))
+	d = s.load("shit\nship", dictionarySource{})
+	assert.Equal(s.T(), []string{"ship", "shit"}, d.Words(4))
+}
+
+func (s *WordFilterTestSuite) TestPhrases() {
+	alphabet := NewAlphabet("")
+	alphabet.Phrases = true
+	f, err := newWordFilter(dictionarySource{blocklist: s.write("blocked.txt", "cat")},
+		alphabet)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), f.Allowed("big cat"))
+	assert.True(s.T(), f.Allowed("big catfish"))
+}
+
+func (s *WordFilterTestSuite) TestCompact() {
+	d := s.load("last\nfast\nbets\ncode", dictionarySource{compact: true,
+		blocklist: s.write("blocked.txt", "fast")})
+	assert.Equal(s.T(), []string{"bets", "code", "last"}, d.Words(4))
+}
+
+func (s *WordFilterTestSuite) TestMissingList() {
+	_, err := loadDictionary(s.source("last",
+		dictionarySource{blocklist: filepath.Join(s.dir, "missing.txt")}))
+	assert.NotNil(s.T(), err)
+	f, err := newWordFilter(dictionarySource{}, NewAlphabet(""))
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), f)
+}
+
+func TestWordFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(WordFilterTestSuite))
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Max size of a word list downloaded by a URLSource.
+	maxDownloadedDictionary = 64 * 1024 * 1024
+	// Time allowed to download a word list when the context has no deadline.
+	dictionaryDownloadTimeout = 30 * time.Second
+)
+
+// Backend the words of the dictionary are loaded from. Every word is a line
+// of a word list, so it can have a clue (e.g. "cat|animal") or a frequency
+// like the lines of a dictionary file.
+type WordSource interface {
+	Words(ctx context.Context) ([]string, error)
+}
+
+// Word list in a local file, which may be gzip compressed. The word list of
+// the language of the game is used if the path is empty, see --lang.
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) Words(ctx context.Context) ([]string, error) {
+	data, err := readDictionary(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// Word list downloaded from an HTTP URL, which may be gzip compressed.
+type URLSource struct {
+	URL string
+	// Client used for the download, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+func (s URLSource) Words(ctx context.Context) ([]string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dictionaryDownloadTimeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadedDictionary+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDownloadedDictionary {
+		return nil, fmt.Errorf("the word list is larger than %d bytes",
+			maxDownloadedDictionary)
+	}
+	if data, err = gunzipDictionary(data); err != nil {
+		return nil, err
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// Words stored in a column of an SQL table, one word (or line) per row, e.g.
+// in an SQLite database. The program opens the database with the driver of
+// its choice, e.g.
+//
+//	db, err := sql.Open("sqlite", "words.db")
+//	source := SQLSource{DB: db, Table: "words", Column: "word"}
+type SQLSource struct {
+	DB     *sql.DB
+	Table  string
+	Column string
+}
+
+func (s SQLSource) Words(ctx context.Context) ([]string, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s", quoteSQLIdentifier(s.Column),
+		quoteSQLIdentifier(s.Table))
+	rows, err := s.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var words []string
+	for rows.Next() {
+		var word sql.NullString
+		if err := rows.Scan(&word); err != nil {
+			return nil, err
+		}
+		if word.Valid {
+			words = append(words, word.String)
+		}
+	}
+	return words, rows.Err()
+}
+
+// Method to quote the name of a table or column, which can not be passed as
+// a query parameter.
+func quoteSQLIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Words given by the program, e.g. the fixtures of a test.
+type SliceSource []string
+
+func (s SliceSource) Words(ctx context.Context) ([]string, error) {
+	return append([]string(nil), s...), nil
+}
+
+// Method to get the source of the --dictionary flag: a URL if it starts with
+// http:// or https://, a file otherwise.
+func wordSourceFromPath(path string) WordSource {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return URLSource{URL: path}
+	}
+	return FileSource{Path: path}
+}
+
+// Method to load the dictionary of the new games from a source, with the
+// alphabet, filters and limits of the flags like InitGame. The dictionary is
+// loaded from the source again when it is reloaded. The current dictionary is
+// kept if the words can not be loaded.
+func InitGameFromSource(ctx context.Context, source WordSource) error {
+	config := dictionarySourceFromFlags()
+	config.index = ""
+	config.words = source
+	d, err := loadDictionaryCtx(ctx, config)
+	if err != nil {
+		return err
+	}
+	currentDict.Store(d)
+	return nil
+}
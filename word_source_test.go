@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+type WordSourceTestSuite struct {
+	suite.Suite
+}
+
+func (s *WordSourceTestSuite) TearDownTest() {
+	InitGame([]string{"last", "fast", "bets", "code"})
+}
+
+func (s *WordSourceTestSuite) TestSliceSource() {
+	err := InitGameFromSource(context.Background(), SliceSource{"cats|animal", "dogs", "horse"})
+	assert.Nil(s.T(), err)
+	d := currentDictionary()
+	assert.Equal(s.T(), []string{"cats", "dogs"}, d.Words(4))
+	hint, _ := d.Hint("cats")
+	assert.Equal(s.T(), "animal", hint)
+
+	// The dictionary is reloaded from its source.
+	reloaded, err := d.Reload()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 3, reloaded.WordCount())
+}
+
+func (s *WordSourceTestSuite) TestFileSource() {
+	path := filepath.Join(s.T().TempDir(), "words.txt")
+	assert.Nil(s.T(), ioutil.WriteFile(path, []byte("last\nfast"), 0644))
+	words, err := FileSource{Path: path}.Words(context.Background())
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"last", "fast"}, words)
+
+	_, err = FileSource{Path: filepath.Join(s.T().TempDir(), "missing.txt")}.
+		Words(context.Background())
+	assert.NotNil(s.T(), err)
+}
+
+func (s *WordSourceTestSuite) TestURLSource() {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write([]byte("mist\nhorse"))
+	writer.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/words.txt":
+			w.Write([]byte("last\nfast"))
+		case "/words.txt.gz":
+			w.Write(compressed.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	words, err := URLSource{URL: server.URL + "/words.txt"}.Words(context.Background())
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"last", "fast"}, words)
+	words, err = URLSource{URL: server.URL + "/words.txt.gz"}.Words(context.Background())
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"mist", "horse"}, words)
+	_, err = URLSource{URL: server.URL + "/missing.txt"}.Words(context.Background())
+	assert.NotNil(s.T(), err)
+
+	// The --dictionary flag takes URLs too.
+	assert.Equal(s.T(), URLSource{URL: server.URL + "/words.txt"},
+		wordSourceFromPath(server.URL+"/words.txt"))
+	source := dictionarySourceFromFlags()
+	source.index = ""
+	source.file = server.URL + "/words.txt"
+	d, err := loadDictionary(source)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"fast", "last"}, d.Words(4))
+
+	// The download stops with the context, and the dictionary is kept.
+	InitGame([]string{"last"})
+	oldFile, oldIndex := *dictionaryFile, *dictionaryIndex
+	defer func() { *dictionaryFile, *dictionaryIndex = oldFile, oldIndex }()
+	*dictionaryFile, *dictionaryIndex = server.URL+"/words.txt", ""
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = InitGameCtx(ctx, nil)
+	assert.True(s.T(), errors.Is(err, context.Canceled))
+	assert.False(s.T(), errors.Is(err, ErrInvalidConfig))
+	assert.Equal(s.T(), []string{"last"}, currentDictionary().Words(4))
+	assert.Nil(s.T(), InitGameCtx(context.Background(), nil))
+	assert.Equal(s.T(), []string{"fast", "last"}, currentDictionary().Words(4))
+}
+
+func (s *WordSourceTestSuite) TestSourceError() {
+	InitGame([]string{"last"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := InitGameFromSource(ctx, URLSource{URL: "http://127.0.0.1:1/words.txt"})
+	assert.NotNil(s.T(), err)
+	// The current dictionary is kept.
+	assert.Equal(s.T(), []string{"last"}, currentDictionary().Words(4))
+}
+
+func (s *WordSourceTestSuite) TestQuoteSQLIdentifier() {
+	assert.Equal(s.T(), `"words"`, quoteSQLIdentifier("words"))
+	assert.Equal(s.T(), `"a""b"`, quoteSQLIdentifier(`a"b`))
+}
+
+func TestWordSourceTestSuite(t *testing.T) {
+	suite.Run(t, new(WordSourceTestSuite))
+}